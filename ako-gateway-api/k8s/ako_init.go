@@ -29,6 +29,7 @@ import (
 
 	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
 	akogatewayapinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/nodes"
+	akogatewayapiobjects "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/objects"
 	akogatewayapistatus "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/status"
 	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
@@ -70,7 +71,7 @@ func (c *GatewayController) InitController(informers k8s.K8sinformers, registere
 	var graphQueue *utils.WorkerQueue
 	// This is the first time initialization of the queue. For hostname based sharding, we don't want layer 2 to process the queue using multiple go routines.
 	retryQueueWorkers := uint32(1)
-	slowRetryQParams := utils.WorkerQueue{NumWorkers: retryQueueWorkers, WorkqueueName: lib.SLOW_RETRY_LAYER, SlowSyncTime: lib.SLOW_SYNC_TIME}
+	slowRetryQParams := utils.WorkerQueue{NumWorkers: retryQueueWorkers, WorkqueueName: lib.SLOW_RETRY_LAYER}
 	fastRetryQParams := utils.WorkerQueue{NumWorkers: retryQueueWorkers, WorkqueueName: lib.FAST_RETRY_LAYER}
 
 	//TODO Parallelize workers
@@ -79,11 +80,11 @@ func (c *GatewayController) InitController(informers k8s.K8sinformers, registere
 	//HTTPRoutes can be attached to multiple gateways
 	//This will make HTTPRoute updates affect multiple graphs
 	numWorkers := uint32(1)
-	ingestionQueueParams := utils.WorkerQueue{NumWorkers: numWorkers, WorkqueueName: utils.ObjectIngestionLayer}
+	ingestionQueueParams := utils.WorkerQueue{NumWorkers: numWorkers, WorkqueueName: utils.ObjectIngestionLayer, FairQueuing: lib.GetFairQueueOptions(utils.FairQueueFlowByNamespace)}
 
 	numGraphWorkers := uint32(8)
 
-	graphQueueParams := utils.WorkerQueue{NumWorkers: numGraphWorkers, WorkqueueName: utils.GraphLayer}
+	graphQueueParams := utils.WorkerQueue{NumWorkers: numGraphWorkers, WorkqueueName: utils.GraphLayer, FairQueuing: lib.GetFairQueueOptions(utils.FairQueueFlowByTenant)}
 	statusQueueParams := utils.WorkerQueue{NumWorkers: numGraphWorkers, WorkqueueName: utils.StatusQueue}
 	graphQueue = utils.SharedWorkQueue(&ingestionQueueParams, &graphQueueParams, &slowRetryQParams, &fastRetryQParams, &statusQueueParams).GetQueueByName(utils.GraphLayer)
 
@@ -233,6 +234,19 @@ func (c *GatewayController) FullSyncK8s(sync bool) error {
 		}
 	}
 
+	// ReferenceGrant Section
+	referenceGrantObjs, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().ReferenceGrantInformer.Lister().ReferenceGrants(metav1.NamespaceAll).List(labels.Set(nil).AsSelector())
+	if err != nil {
+		utils.AviLog.Errorf("Unable to retrieve the referencegrants during full sync: %s", err)
+		return err
+	}
+
+	for _, referenceGrantObj := range referenceGrantObjs {
+		// The grants are evaluated while building the Gateways and routes, only the namespaces
+		// they permit references from are recorded, to re-sync them when the grant changes.
+		akogatewayapiobjects.GatewayApiLister().UpdateReferenceGrantToFrom(utils.ObjKey(referenceGrantObj), akogatewayapilib.GetReferenceGrantFrom(referenceGrantObj))
+	}
+
 	// Gateway Section
 	gatewayObjs, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().GatewayInformer.Lister().Gateways(metav1.NamespaceAll).List(labels.Set(nil).AsSelector())
 	if err != nil {
@@ -271,6 +285,66 @@ func (c *GatewayController) FullSyncK8s(sync bool) error {
 		}
 	}
 
+	// GRPCRoute Section
+	grpcRouteObjs, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().GRPCRouteInformer.Lister().GRPCRoutes(metav1.NamespaceAll).List(labels.Set(nil).AsSelector())
+	if err != nil {
+		utils.AviLog.Errorf("Unable to retrieve the grpcroutes during full sync: %s", err)
+		return err
+	}
+
+	for _, grpcRouteObj := range grpcRouteObjs {
+		key := lib.GRPCRoute + "/" + utils.ObjKey(grpcRouteObj)
+		objects.SharedResourceVerInstanceLister().Save(key, grpcRouteObj.GetResourceVersion())
+		if IsGRPCRouteValid(key, grpcRouteObj) {
+			akogatewayapinodes.DequeueIngestion(key, true)
+		}
+	}
+
+	// TCPRoute Section
+	tcpRouteObjs, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().TCPRouteInformer.Lister().TCPRoutes(metav1.NamespaceAll).List(labels.Set(nil).AsSelector())
+	if err != nil {
+		utils.AviLog.Errorf("Unable to retrieve the tcproutes during full sync: %s", err)
+		return err
+	}
+
+	for _, tcpRouteObj := range tcpRouteObjs {
+		key := lib.TCPRoute + "/" + utils.ObjKey(tcpRouteObj)
+		objects.SharedResourceVerInstanceLister().Save(key, tcpRouteObj.GetResourceVersion())
+		if IsL4RouteValid(key, tcpRouteObj) {
+			akogatewayapinodes.DequeueIngestion(key, true)
+		}
+	}
+
+	// UDPRoute Section
+	udpRouteObjs, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().UDPRouteInformer.Lister().UDPRoutes(metav1.NamespaceAll).List(labels.Set(nil).AsSelector())
+	if err != nil {
+		utils.AviLog.Errorf("Unable to retrieve the udproutes during full sync: %s", err)
+		return err
+	}
+
+	for _, udpRouteObj := range udpRouteObjs {
+		key := lib.UDPRoute + "/" + utils.ObjKey(udpRouteObj)
+		objects.SharedResourceVerInstanceLister().Save(key, udpRouteObj.GetResourceVersion())
+		if IsL4RouteValid(key, udpRouteObj) {
+			akogatewayapinodes.DequeueIngestion(key, true)
+		}
+	}
+
+	// TLSRoute Section
+	tlsRouteObjs, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().TLSRouteInformer.Lister().TLSRoutes(metav1.NamespaceAll).List(labels.Set(nil).AsSelector())
+	if err != nil {
+		utils.AviLog.Errorf("Unable to retrieve the tlsroutes during full sync: %s", err)
+		return err
+	}
+
+	for _, tlsRouteObj := range tlsRouteObjs {
+		key := lib.TLSRoute + "/" + utils.ObjKey(tlsRouteObj)
+		objects.SharedResourceVerInstanceLister().Save(key, tlsRouteObj.GetResourceVersion())
+		if IsL4RouteValid(key, tlsRouteObj) {
+			akogatewayapinodes.DequeueIngestion(key, true)
+		}
+	}
+
 	// Service Section
 	svcObjs, err := utils.GetInformers().ServiceInformer.Lister().Services(metav1.NamespaceAll).List(labels.Set(nil).AsSelector())
 	if err != nil {
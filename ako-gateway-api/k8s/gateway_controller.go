@@ -21,17 +21,29 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 	gatewayclientset "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
 	gatewayexternalversions "sigs.k8s.io/gateway-api/pkg/client/informers/externalversions"
 
 	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	akogatewayapinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/nodes"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	akov1alpha1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha1"
+	akocrd "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha1/clientset/versioned"
+	akoinformers "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha1/informers/externalversions"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 )
 
@@ -56,24 +68,46 @@ func SharedGatewayController() *GatewayController {
 	return controllerInstance
 }
 
-func (c *GatewayController) InitGatewayAPIInformers(cs gatewayclientset.Interface) {
+func (c *GatewayController) InitGatewayAPIInformers(cs gatewayclientset.Interface, kubeClient kubernetes.Interface) {
 	gatewayFactory := gatewayexternalversions.NewSharedInformerFactory(cs, time.Second*30)
+	// the ConfigMap informer of the ingestion layer only watches the AKO namespace
+	kubeFactory := kubeinformers.NewSharedInformerFactory(kubeClient, time.Second*30)
 	akogatewayapilib.AKOControlConfig().SetGatewayApiInformers(&akogatewayapilib.GatewayAPIInformers{
-		GatewayInformer:      gatewayFactory.Gateway().V1().Gateways(),
-		GatewayClassInformer: gatewayFactory.Gateway().V1().GatewayClasses(),
-		HTTPRouteInformer:    gatewayFactory.Gateway().V1().HTTPRoutes(),
+		GatewayInformer:          gatewayFactory.Gateway().V1().Gateways(),
+		GatewayClassInformer:     gatewayFactory.Gateway().V1().GatewayClasses(),
+		HTTPRouteInformer:        gatewayFactory.Gateway().V1().HTTPRoutes(),
+		TCPRouteInformer:         gatewayFactory.Gateway().V1alpha2().TCPRoutes(),
+		UDPRouteInformer:         gatewayFactory.Gateway().V1alpha2().UDPRoutes(),
+		TLSRouteInformer:         gatewayFactory.Gateway().V1alpha2().TLSRoutes(),
+		GRPCRouteInformer:        gatewayFactory.Gateway().V1alpha2().GRPCRoutes(),
+		ReferenceGrantInformer:   gatewayFactory.Gateway().V1beta1().ReferenceGrants(),
+		BackendTLSPolicyInformer: gatewayFactory.Gateway().V1alpha2().BackendTLSPolicies(),
+		ConfigMapInformer:        kubeFactory.Core().V1().ConfigMaps(),
+	})
+}
+
+func (c *GatewayController) InitAKOCRDInformers(cs akocrd.Interface) {
+	akoCRDFactory := akoinformers.NewSharedInformerFactory(cs, time.Second*30)
+	akogatewayapilib.AKOControlConfig().SetAKOCRDInformers(&akogatewayapilib.AKOCRDInformers{
+		BackendPolicyInformer: akoCRDFactory.Ako().V1alpha1().BackendPolicies(),
 	})
 }
 
 func (c *GatewayController) Start(stopCh <-chan struct{}) {
 	go c.informers.ServiceInformer.Informer().Run(stopCh)
-	go c.informers.EpInformer.Informer().Run(stopCh)
 
 	informersList := []cache.InformerSynced{
-		c.informers.EpInformer.Informer().HasSynced,
 		c.informers.ServiceInformer.Informer().HasSynced,
 	}
 
+	if c.informers.EpSlicesInformer != nil {
+		go c.informers.EpSlicesInformer.Informer().Run(stopCh)
+		informersList = append(informersList, c.informers.EpSlicesInformer.Informer().HasSynced)
+	} else {
+		go c.informers.EpInformer.Informer().Run(stopCh)
+		informersList = append(informersList, c.informers.EpInformer.Informer().HasSynced)
+	}
+
 	if !lib.AviSecretInitialized {
 		go c.informers.SecretInformer.Informer().Run(stopCh)
 		informersList = append(informersList, c.informers.SecretInformer.Informer().HasSynced)
@@ -90,6 +124,22 @@ func (c *GatewayController) Start(stopCh <-chan struct{}) {
 	informersList = append(informersList, akogatewayapilib.AKOControlConfig().GatewayApiInformers().GatewayInformer.Informer().HasSynced)
 	go akogatewayapilib.AKOControlConfig().GatewayApiInformers().HTTPRouteInformer.Informer().Run(stopCh)
 	informersList = append(informersList, akogatewayapilib.AKOControlConfig().GatewayApiInformers().HTTPRouteInformer.Informer().HasSynced)
+	go akogatewayapilib.AKOControlConfig().GatewayApiInformers().TCPRouteInformer.Informer().Run(stopCh)
+	informersList = append(informersList, akogatewayapilib.AKOControlConfig().GatewayApiInformers().TCPRouteInformer.Informer().HasSynced)
+	go akogatewayapilib.AKOControlConfig().GatewayApiInformers().UDPRouteInformer.Informer().Run(stopCh)
+	informersList = append(informersList, akogatewayapilib.AKOControlConfig().GatewayApiInformers().UDPRouteInformer.Informer().HasSynced)
+	go akogatewayapilib.AKOControlConfig().GatewayApiInformers().TLSRouteInformer.Informer().Run(stopCh)
+	informersList = append(informersList, akogatewayapilib.AKOControlConfig().GatewayApiInformers().TLSRouteInformer.Informer().HasSynced)
+	go akogatewayapilib.AKOControlConfig().GatewayApiInformers().GRPCRouteInformer.Informer().Run(stopCh)
+	informersList = append(informersList, akogatewayapilib.AKOControlConfig().GatewayApiInformers().GRPCRouteInformer.Informer().HasSynced)
+	go akogatewayapilib.AKOControlConfig().GatewayApiInformers().ReferenceGrantInformer.Informer().Run(stopCh)
+	informersList = append(informersList, akogatewayapilib.AKOControlConfig().GatewayApiInformers().ReferenceGrantInformer.Informer().HasSynced)
+	go akogatewayapilib.AKOControlConfig().GatewayApiInformers().BackendTLSPolicyInformer.Informer().Run(stopCh)
+	informersList = append(informersList, akogatewayapilib.AKOControlConfig().GatewayApiInformers().BackendTLSPolicyInformer.Informer().HasSynced)
+	go akogatewayapilib.AKOControlConfig().GatewayApiInformers().ConfigMapInformer.Informer().Run(stopCh)
+	informersList = append(informersList, akogatewayapilib.AKOControlConfig().GatewayApiInformers().ConfigMapInformer.Informer().HasSynced)
+	go akogatewayapilib.AKOControlConfig().AKOCRDInformers().BackendPolicyInformer.Informer().Run(stopCh)
+	informersList = append(informersList, akogatewayapilib.AKOControlConfig().AKOCRDInformers().BackendPolicyInformer.Informer().HasSynced)
 
 	if !cache.WaitForCacheSync(stopCh, informersList...) {
 		runtime.HandleError(fmt.Errorf("timed out waiting for caches to sync"))
@@ -166,7 +216,88 @@ func (c *GatewayController) SetupEventHandlers(k8sinfo k8s.K8sinformers) {
 			}
 		},
 	}
-	c.informers.EpInformer.Informer().AddEventHandler(epEventHandler)
+
+	epSliceEventHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			eps := obj.(*discoveryv1.EndpointSlice)
+			svcKey, ok := utils.EndpointSliceObjKey(eps)
+			if !ok {
+				return
+			}
+			namespace, _, _ := cache.SplitMetaNamespaceKey(svcKey)
+			key := utils.Endpoints + "/" + svcKey
+			if lib.IsNamespaceBlocked(namespace) {
+				utils.AviLog.Debugf("key: %s, msg: EndpointSlice Add event: Namespace: %s didn't qualify filter", key, namespace)
+				return
+			}
+			bkt := utils.Bkt(namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: ADD", key)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			eps, ok := obj.(*discoveryv1.EndpointSlice)
+			if !ok {
+				// endpointslice was deleted but its final state is unrecorded.
+				tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
+					return
+				}
+				eps, ok = tombstone.Obj.(*discoveryv1.EndpointSlice)
+				if !ok {
+					utils.AviLog.Errorf("Tombstone contained object that is not an EndpointSlice: %#v", obj)
+					return
+				}
+			}
+			svcKey, ok := utils.EndpointSliceObjKey(eps)
+			if !ok {
+				return
+			}
+			namespace, _, _ := cache.SplitMetaNamespaceKey(svcKey)
+			key := utils.Endpoints + "/" + svcKey
+			if lib.IsNamespaceBlocked(namespace) {
+				utils.AviLog.Debugf("key: %s, msg: EndpointSlice Delete event: Namespace: %s didn't qualify filter", key, namespace)
+				return
+			}
+			bkt := utils.Bkt(namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: DELETE", key)
+		},
+		UpdateFunc: func(old, cur interface{}) {
+			if c.DisableSync {
+				return
+			}
+			oeps := old.(*discoveryv1.EndpointSlice)
+			ceps := cur.(*discoveryv1.EndpointSlice)
+			if reflect.DeepEqual(ceps.Endpoints, oeps.Endpoints) && reflect.DeepEqual(ceps.Ports, oeps.Ports) {
+				return
+			}
+			svcKey, ok := utils.EndpointSliceObjKey(ceps)
+			if !ok {
+				return
+			}
+			namespace, _, _ := cache.SplitMetaNamespaceKey(svcKey)
+			key := utils.Endpoints + "/" + svcKey
+			if lib.IsNamespaceBlocked(namespace) {
+				utils.AviLog.Debugf("key: %s, msg: EndpointSlice Update event: Namespace: %s didn't qualify filter", key, namespace)
+				return
+			}
+			bkt := utils.Bkt(namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
+		},
+	}
+	if c.informers.EpSlicesInformer != nil {
+		c.informers.EpSlicesInformer.Informer().AddEventHandler(epSliceEventHandler)
+	} else {
+		c.informers.EpInformer.Informer().AddEventHandler(epEventHandler)
+	}
 
 	svcEventHandler := cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
@@ -181,6 +312,7 @@ func (c *GatewayController) SetupEventHandlers(k8sinfo k8s.K8sinformers) {
 				return
 			}
 			namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(svc))
+			validateBackendTLSPoliciesForServices(key, namespace, []string{svc.Name})
 			bkt := utils.Bkt(namespace, numWorkers)
 			c.workqueue[bkt].AddRateLimited(key)
 			utils.AviLog.Debugf("key: %s, msg: ADD", key)
@@ -205,6 +337,7 @@ func (c *GatewayController) SetupEventHandlers(k8sinfo k8s.K8sinformers) {
 			}
 			namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(svc))
 			key := utils.Service + "/" + utils.ObjKey(svc)
+			validateBackendTLSPoliciesForServices(key, namespace, []string{svc.Name})
 			bkt := utils.Bkt(namespace, numWorkers)
 			c.workqueue[bkt].AddRateLimited(key)
 			objects.SharedResourceVerInstanceLister().Delete(key)
@@ -460,6 +593,7 @@ func (c *GatewayController) SetupGatewayApiEventHandlers(numWorkers uint32) {
 				utils.AviLog.Debugf("key: %s, msg: same resource version returning", key)
 				return
 			}
+			validateBackendTLSPoliciesForRoute(key, httpRoute.Namespace, getHTTPRouteBackendRefs(httpRoute))
 			if !IsHTTPRouteValid(key, httpRoute) {
 				return
 			}
@@ -488,6 +622,7 @@ func (c *GatewayController) SetupGatewayApiEventHandlers(numWorkers uint32) {
 			}
 			key := lib.HTTPRoute + "/" + utils.ObjKey(httpRoute)
 			objects.SharedResourceVerInstanceLister().Delete(key)
+			validateBackendTLSPoliciesForRoute(key, httpRoute.Namespace, getHTTPRouteBackendRefs(httpRoute))
 			namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(httpRoute))
 			bkt := utils.Bkt(namespace, numWorkers)
 			c.workqueue[bkt].AddRateLimited(key)
@@ -501,6 +636,8 @@ func (c *GatewayController) SetupGatewayApiEventHandlers(numWorkers uint32) {
 			newHTTPRoute := obj.(*gatewayv1.HTTPRoute)
 			if IsHTTPRouteUpdated(oldHTTPRoute, newHTTPRoute) {
 				key := lib.HTTPRoute + "/" + utils.ObjKey(newHTTPRoute)
+				backendRefs := append(getHTTPRouteBackendRefs(oldHTTPRoute), getHTTPRouteBackendRefs(newHTTPRoute)...)
+				validateBackendTLSPoliciesForRoute(key, newHTTPRoute.Namespace, backendRefs)
 				if !IsHTTPRouteValid(key, newHTTPRoute) {
 					return
 				}
@@ -512,6 +649,493 @@ func (c *GatewayController) SetupGatewayApiEventHandlers(numWorkers uint32) {
 		},
 	}
 	informer.HTTPRouteInformer.Informer().AddEventHandler(httpRouteEventHandler)
+
+	grpcRouteEventHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			grpcRoute := obj.(*gatewayv1alpha2.GRPCRoute)
+			key := lib.GRPCRoute + "/" + utils.ObjKey(grpcRoute)
+			ok, resVer := objects.SharedResourceVerInstanceLister().Get(key)
+			if ok && resVer.(string) == grpcRoute.ResourceVersion {
+				utils.AviLog.Debugf("key: %s, msg: same resource version returning", key)
+				return
+			}
+			validateBackendTLSPoliciesForRoute(key, grpcRoute.Namespace, getGRPCRouteBackendRefs(grpcRoute))
+			if !IsGRPCRouteValid(key, grpcRoute) {
+				return
+			}
+			namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(grpcRoute))
+			bkt := utils.Bkt(namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: ADD", key)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			grpcRoute, ok := obj.(*gatewayv1alpha2.GRPCRoute)
+			if !ok {
+				// grpcRoute was deleted but its final state is unrecorded.
+				tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
+					return
+				}
+				grpcRoute, ok = tombstone.Obj.(*gatewayv1alpha2.GRPCRoute)
+				if !ok {
+					utils.AviLog.Errorf("Tombstone contained object that is not a GRPCRoute: %#v", obj)
+					return
+				}
+			}
+			key := lib.GRPCRoute + "/" + utils.ObjKey(grpcRoute)
+			objects.SharedResourceVerInstanceLister().Delete(key)
+			validateBackendTLSPoliciesForRoute(key, grpcRoute.Namespace, getGRPCRouteBackendRefs(grpcRoute))
+			namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(grpcRoute))
+			bkt := utils.Bkt(namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: DELETE", key)
+		},
+		UpdateFunc: func(old, obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			oldGRPCRoute := old.(*gatewayv1alpha2.GRPCRoute)
+			newGRPCRoute := obj.(*gatewayv1alpha2.GRPCRoute)
+			if IsGRPCRouteUpdated(oldGRPCRoute, newGRPCRoute) {
+				key := lib.GRPCRoute + "/" + utils.ObjKey(newGRPCRoute)
+				backendRefs := append(getGRPCRouteBackendRefs(oldGRPCRoute), getGRPCRouteBackendRefs(newGRPCRoute)...)
+				validateBackendTLSPoliciesForRoute(key, newGRPCRoute.Namespace, backendRefs)
+				if !IsGRPCRouteValid(key, newGRPCRoute) {
+					return
+				}
+				namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(newGRPCRoute))
+				bkt := utils.Bkt(namespace, numWorkers)
+				c.workqueue[bkt].AddRateLimited(key)
+				utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
+			}
+		},
+	}
+	informer.GRPCRouteInformer.Informer().AddEventHandler(grpcRouteEventHandler)
+
+	informer.TCPRouteInformer.Informer().AddEventHandler(c.l4RouteEventHandler(lib.TCPRoute, numWorkers))
+	informer.UDPRouteInformer.Informer().AddEventHandler(c.l4RouteEventHandler(lib.UDPRoute, numWorkers))
+	informer.TLSRouteInformer.Informer().AddEventHandler(c.l4RouteEventHandler(lib.TLSRoute, numWorkers))
+
+	referenceGrantEventHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			referenceGrant := obj.(*gatewayv1beta1.ReferenceGrant)
+			key := lib.ReferenceGrant + "/" + utils.ObjKey(referenceGrant)
+			c.validateReferenceGrantDependents(key, referenceGrant.Namespace, referenceGrant.Name, numWorkers)
+			bkt := utils.Bkt(referenceGrant.Namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: ADD", key)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			referenceGrant, ok := obj.(*gatewayv1beta1.ReferenceGrant)
+			if !ok {
+				// referenceGrant was deleted but its final state is unrecorded.
+				tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
+					return
+				}
+				referenceGrant, ok = tombstone.Obj.(*gatewayv1beta1.ReferenceGrant)
+				if !ok {
+					utils.AviLog.Errorf("Tombstone contained object that is not a ReferenceGrant: %#v", obj)
+					return
+				}
+			}
+			key := lib.ReferenceGrant + "/" + utils.ObjKey(referenceGrant)
+			c.validateReferenceGrantDependents(key, referenceGrant.Namespace, referenceGrant.Name, numWorkers)
+			bkt := utils.Bkt(referenceGrant.Namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: DELETE", key)
+		},
+		UpdateFunc: func(old, obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			oldReferenceGrant := old.(*gatewayv1beta1.ReferenceGrant)
+			referenceGrant := obj.(*gatewayv1beta1.ReferenceGrant)
+			if reflect.DeepEqual(oldReferenceGrant.Spec, referenceGrant.Spec) {
+				return
+			}
+			key := lib.ReferenceGrant + "/" + utils.ObjKey(referenceGrant)
+			c.validateReferenceGrantDependents(key, referenceGrant.Namespace, referenceGrant.Name, numWorkers)
+			bkt := utils.Bkt(referenceGrant.Namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
+		},
+	}
+	informer.ReferenceGrantInformer.Informer().AddEventHandler(referenceGrantEventHandler)
+
+	backendTLSPolicyEventHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			backendTLSPolicy := obj.(*gatewayv1alpha2.BackendTLSPolicy)
+			key := lib.BackendTLSPolicy + "/" + utils.ObjKey(backendTLSPolicy)
+			utils.AviLog.Debugf("key: %s, msg: ADD", key)
+			IsBackendTLSPolicyValid(key, backendTLSPolicy)
+			c.syncBackendTLSPolicyTarget(key, backendTLSPolicy, numWorkers)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			backendTLSPolicy, ok := obj.(*gatewayv1alpha2.BackendTLSPolicy)
+			if !ok {
+				// backendTLSPolicy was deleted but its final state is unrecorded.
+				tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
+					return
+				}
+				backendTLSPolicy, ok = tombstone.Obj.(*gatewayv1alpha2.BackendTLSPolicy)
+				if !ok {
+					utils.AviLog.Errorf("Tombstone contained object that is not a BackendTLSPolicy: %#v", obj)
+					return
+				}
+			}
+			key := lib.BackendTLSPolicy + "/" + utils.ObjKey(backendTLSPolicy)
+			utils.AviLog.Debugf("key: %s, msg: DELETE", key)
+			c.syncBackendTLSPolicyTarget(key, backendTLSPolicy, numWorkers)
+		},
+		UpdateFunc: func(old, obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			oldBackendTLSPolicy := old.(*gatewayv1alpha2.BackendTLSPolicy)
+			backendTLSPolicy := obj.(*gatewayv1alpha2.BackendTLSPolicy)
+			if reflect.DeepEqual(oldBackendTLSPolicy.Spec, backendTLSPolicy.Spec) {
+				return
+			}
+			key := lib.BackendTLSPolicy + "/" + utils.ObjKey(backendTLSPolicy)
+			utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
+			IsBackendTLSPolicyValid(key, backendTLSPolicy)
+			// the pools of the old target service must stop re-encrypting
+			if !reflect.DeepEqual(oldBackendTLSPolicy.Spec.TargetRef, backendTLSPolicy.Spec.TargetRef) {
+				c.syncBackendTLSPolicyTarget(key, oldBackendTLSPolicy, numWorkers)
+			}
+			c.syncBackendTLSPolicyTarget(key, backendTLSPolicy, numWorkers)
+		},
+	}
+	informer.BackendTLSPolicyInformer.Informer().AddEventHandler(backendTLSPolicyEventHandler)
+
+	configMapEventHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			configMap := obj.(*corev1.ConfigMap)
+			key := lib.ConfigMap + "/" + utils.ObjKey(configMap)
+			if !validateConfigMapBackendTLSPolicies(key, configMap) {
+				return
+			}
+			bkt := utils.Bkt(configMap.Namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: ADD", key)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			configMap, ok := obj.(*corev1.ConfigMap)
+			if !ok {
+				// configMap was deleted but its final state is unrecorded.
+				tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
+					return
+				}
+				configMap, ok = tombstone.Obj.(*corev1.ConfigMap)
+				if !ok {
+					utils.AviLog.Errorf("Tombstone contained object that is not a ConfigMap: %#v", obj)
+					return
+				}
+			}
+			key := lib.ConfigMap + "/" + utils.ObjKey(configMap)
+			if !validateConfigMapBackendTLSPolicies(key, configMap) {
+				return
+			}
+			bkt := utils.Bkt(configMap.Namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: DELETE", key)
+		},
+		UpdateFunc: func(old, obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			oldConfigMap := old.(*corev1.ConfigMap)
+			configMap := obj.(*corev1.ConfigMap)
+			if reflect.DeepEqual(oldConfigMap.Data, configMap.Data) {
+				return
+			}
+			key := lib.ConfigMap + "/" + utils.ObjKey(configMap)
+			if !validateConfigMapBackendTLSPolicies(key, configMap) {
+				return
+			}
+			bkt := utils.Bkt(configMap.Namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
+		},
+	}
+	informer.ConfigMapInformer.Informer().AddEventHandler(configMapEventHandler)
+
+	backendPolicyEventHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			backendPolicy := obj.(*akov1alpha1.BackendPolicy)
+			key := lib.BackendPolicy + "/" + utils.ObjKey(backendPolicy)
+			if !IsBackendPolicyValid(key, backendPolicy) {
+				return
+			}
+			bkt := utils.Bkt(backendPolicy.Namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: ADD", key)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			backendPolicy, ok := obj.(*akov1alpha1.BackendPolicy)
+			if !ok {
+				// backendPolicy was deleted but its final state is unrecorded.
+				tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
+					return
+				}
+				backendPolicy, ok = tombstone.Obj.(*akov1alpha1.BackendPolicy)
+				if !ok {
+					utils.AviLog.Errorf("Tombstone contained object that is not a BackendPolicy: %#v", obj)
+					return
+				}
+			}
+			key := lib.BackendPolicy + "/" + utils.ObjKey(backendPolicy)
+			bkt := utils.Bkt(backendPolicy.Namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: DELETE", key)
+			c.syncConflictedBackendPolicies(key, backendPolicy, numWorkers)
+		},
+		UpdateFunc: func(old, obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			oldBackendPolicy := old.(*akov1alpha1.BackendPolicy)
+			backendPolicy := obj.(*akov1alpha1.BackendPolicy)
+			key := lib.BackendPolicy + "/" + utils.ObjKey(backendPolicy)
+			accepted := string(gatewayv1alpha2.PolicyConditionAccepted)
+			if !reflect.DeepEqual(oldBackendPolicy.Spec, backendPolicy.Spec) {
+				// the policy is enqueued even if it turns invalid, so that it is removed from the pools
+				IsBackendPolicyValid(key, backendPolicy)
+			} else if apimeta.IsStatusConditionTrue(oldBackendPolicy.Status.Conditions, accepted) ==
+				apimeta.IsStatusConditionTrue(backendPolicy.Status.Conditions, accepted) {
+				return
+			}
+			bkt := utils.Bkt(backendPolicy.Namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
+			if !reflect.DeepEqual(oldBackendPolicy.Spec.TargetRef, backendPolicy.Spec.TargetRef) {
+				c.syncConflictedBackendPolicies(key, oldBackendPolicy, numWorkers)
+			}
+		},
+	}
+	akogatewayapilib.AKOControlConfig().AKOCRDInformers().BackendPolicyInformer.Informer().AddEventHandler(backendPolicyEventHandler)
+}
+
+// syncConflictedBackendPolicies re-validates the BackendPolicies with the same target as a BackendPolicy which
+// is deleted or no longer targets the object, as one of them can now be accepted.
+func (c *GatewayController) syncConflictedBackendPolicies(key string, backendPolicy *akov1alpha1.BackendPolicy, numWorkers uint32) {
+	backendPolicies, err := akogatewayapilib.AKOControlConfig().AKOCRDInformers().BackendPolicyInformer.Lister().BackendPolicies(backendPolicy.Namespace).List(labels.Everything())
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to list the BackendPolicies, err: %v", key, err)
+		return
+	}
+	for _, otherPolicy := range backendPolicies {
+		if otherPolicy.Name == backendPolicy.Name || !reflect.DeepEqual(otherPolicy.Spec.TargetRef, backendPolicy.Spec.TargetRef) {
+			continue
+		}
+		otherKey := lib.BackendPolicy + "/" + utils.ObjKey(otherPolicy)
+		// the status update of an accepted policy enqueues it
+		IsBackendPolicyValid(otherKey, otherPolicy)
+	}
+}
+
+// syncBackendTLSPolicyTarget enqueues the Service targeted by a BackendTLSPolicy, so that the graph layer
+// rebuilds the pools of the routes which use the Service as a backend. The other policies with the same
+// target are re-validated, as the policy which is applied to the Service may have changed.
+func (c *GatewayController) syncBackendTLSPolicyTarget(key string, backendTLSPolicy *gatewayv1alpha2.BackendTLSPolicy, numWorkers uint32) {
+	targetRef := backendTLSPolicy.Spec.TargetRef
+	if targetRef.Group != "" || targetRef.Kind != "Service" {
+		utils.AviLog.Warnf("key: %s, msg: BackendTLSPolicy target %s/%s is not supported", key, targetRef.Group, targetRef.Kind)
+		return
+	}
+	namespace := backendTLSPolicy.Namespace
+	if targetRef.Namespace != nil && string(*targetRef.Namespace) != namespace {
+		utils.AviLog.Warnf("key: %s, msg: BackendTLSPolicy can only target a Service in its own namespace", key)
+		return
+	}
+	backendTLSPolicies, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().BackendTLSPolicyInformer.Lister().BackendTLSPolicies(namespace).List(labels.Everything())
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to list the BackendTLSPolicies, err: %v", key, err)
+	}
+	for _, otherPolicy := range backendTLSPolicies {
+		if otherPolicy.Name != backendTLSPolicy.Name && otherPolicy.Spec.TargetRef.Name == targetRef.Name {
+			IsBackendTLSPolicyValid(lib.BackendTLSPolicy+"/"+utils.ObjKey(otherPolicy), otherPolicy)
+		}
+	}
+	svcKey := utils.Service + "/" + namespace + "/" + string(targetRef.Name)
+	bkt := utils.Bkt(namespace, numWorkers)
+	c.workqueue[bkt].AddRateLimited(svcKey)
+	utils.AviLog.Debugf("key: %s, msg: enqueued %s for the BackendTLSPolicy change", key, svcKey)
+}
+
+// validateConfigMapBackendTLSPolicies re-validates the BackendTLSPolicies which refer to a ConfigMap for the CA
+// certificates, and returns whether there are any, as the other ConfigMaps are not processed.
+func validateConfigMapBackendTLSPolicies(key string, configMap *corev1.ConfigMap) bool {
+	backendTLSPolicies := akogatewayapilib.GetBackendTLSPoliciesForConfigMap(configMap.Namespace, configMap.Name)
+	for _, backendTLSPolicy := range backendTLSPolicies {
+		IsBackendTLSPolicyValid(lib.BackendTLSPolicy+"/"+utils.ObjKey(backendTLSPolicy), backendTLSPolicy)
+	}
+	return len(backendTLSPolicies) > 0
+}
+
+// validateReferenceGrantDependents re-validates the Gateways and routes in the namespaces a ReferenceGrant
+// permits, or used to permit, references from, so that their status reflects the references which are
+// permitted now. The valid Gateways are enqueued, as a Gateway turns valid once its certificates are
+// permitted, and the models of the other objects are rebuilt when the ReferenceGrant key is dequeued.
+func (c *GatewayController) validateReferenceGrantDependents(key, namespace, name string, numWorkers uint32) {
+	gwNsNameList, routeTypeNsNameList, found := akogatewayapinodes.GetReferenceGrantDependents(namespace, name, key)
+	if !found {
+		return
+	}
+	informer := akogatewayapilib.AKOControlConfig().GatewayApiInformers()
+	for _, gwNsName := range gwNsNameList {
+		gwNamespace, _, gwName := lib.ExtractTypeNameNamespace(gwNsName)
+		gateway, err := informer.GatewayInformer.Lister().Gateways(gwNamespace).Get(gwName)
+		if err != nil {
+			continue
+		}
+		gwKey := lib.Gateway + "/" + gwNsName
+		if !IsValidGateway(gwKey, gateway) {
+			continue
+		}
+		bkt := utils.Bkt(gwNamespace, numWorkers)
+		c.workqueue[bkt].AddRateLimited(gwKey)
+		utils.AviLog.Debugf("key: %s, msg: enqueued %s for the ReferenceGrant change", key, gwKey)
+	}
+	for _, routeTypeNsName := range routeTypeNsNameList {
+		routeType, routeNamespace, routeName := lib.ExtractTypeNameNamespace(routeTypeNsName)
+		var err error
+		switch routeType {
+		case lib.HTTPRoute:
+			var httpRoute *gatewayv1.HTTPRoute
+			if httpRoute, err = informer.HTTPRouteInformer.Lister().HTTPRoutes(routeNamespace).Get(routeName); err == nil {
+				IsHTTPRouteValid(routeTypeNsName, httpRoute)
+			}
+		case lib.GRPCRoute:
+			var grpcRoute *gatewayv1alpha2.GRPCRoute
+			if grpcRoute, err = informer.GRPCRouteInformer.Lister().GRPCRoutes(routeNamespace).Get(routeName); err == nil {
+				IsGRPCRouteValid(routeTypeNsName, grpcRoute)
+			}
+		case lib.TCPRoute:
+			var tcpRoute *gatewayv1alpha2.TCPRoute
+			if tcpRoute, err = informer.TCPRouteInformer.Lister().TCPRoutes(routeNamespace).Get(routeName); err == nil {
+				IsL4RouteValid(routeTypeNsName, tcpRoute)
+			}
+		case lib.UDPRoute:
+			var udpRoute *gatewayv1alpha2.UDPRoute
+			if udpRoute, err = informer.UDPRouteInformer.Lister().UDPRoutes(routeNamespace).Get(routeName); err == nil {
+				IsL4RouteValid(routeTypeNsName, udpRoute)
+			}
+		case lib.TLSRoute:
+			var tlsRoute *gatewayv1alpha2.TLSRoute
+			if tlsRoute, err = informer.TLSRouteInformer.Lister().TLSRoutes(routeNamespace).Get(routeName); err == nil {
+				IsL4RouteValid(routeTypeNsName, tlsRoute)
+			}
+		}
+		if err != nil {
+			utils.AviLog.Debugf("key: %s, msg: unable to get the route %s, err: %v", key, routeTypeNsName, err)
+		}
+	}
+}
+
+// l4RouteEventHandler returns the event handler shared by the TCPRoute, UDPRoute and TLSRoute informers.
+func (c *GatewayController) l4RouteEventHandler(kind string, numWorkers uint32) cache.ResourceEventHandlerFuncs {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			route := obj.(metav1.Object)
+			key := kind + "/" + utils.ObjKey(route)
+			ok, resVer := objects.SharedResourceVerInstanceLister().Get(key)
+			if ok && resVer.(string) == route.GetResourceVersion() {
+				utils.AviLog.Debugf("key: %s, msg: same resource version returning", key)
+				return
+			}
+			if !IsL4RouteValid(key, obj) {
+				return
+			}
+			namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(route))
+			bkt := utils.Bkt(namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: ADD", key)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			route, ok := obj.(metav1.Object)
+			if !ok {
+				// route was deleted but its final state is unrecorded.
+				tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
+					return
+				}
+				route, ok = tombstone.Obj.(metav1.Object)
+				if !ok {
+					utils.AviLog.Errorf("Tombstone contained object that is not a %s: %#v", kind, obj)
+					return
+				}
+			}
+			key := kind + "/" + utils.ObjKey(route)
+			objects.SharedResourceVerInstanceLister().Delete(key)
+			namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(route))
+			bkt := utils.Bkt(namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: DELETE", key)
+		},
+		UpdateFunc: func(old, obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			if IsL4RouteUpdated(old, obj) {
+				route := obj.(metav1.Object)
+				key := kind + "/" + utils.ObjKey(route)
+				if !IsL4RouteValid(key, obj) {
+					return
+				}
+				namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(route))
+				bkt := utils.Bkt(namespace, numWorkers)
+				c.workqueue[bkt].AddRateLimited(key)
+				utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
+			}
+		},
+	}
 }
 
 func IsGatewayUpdated(oldGateway, newGateway *gatewayv1.Gateway) bool {
@@ -531,3 +1155,30 @@ func IsHTTPRouteUpdated(oldHTTPRoute, newHTTPRoute *gatewayv1.HTTPRoute) bool {
 	newHash := utils.Hash(utils.Stringify(newHTTPRoute.Spec))
 	return oldHash != newHash
 }
+
+func IsGRPCRouteUpdated(oldGRPCRoute, newGRPCRoute *gatewayv1alpha2.GRPCRoute) bool {
+	if newGRPCRoute.GetDeletionTimestamp() != nil {
+		return true
+	}
+	oldHash := utils.Hash(utils.Stringify(oldGRPCRoute.Spec))
+	newHash := utils.Hash(utils.Stringify(newGRPCRoute.Spec))
+	return oldHash != newHash
+}
+
+func IsL4RouteUpdated(oldRoute, newRoute interface{}) bool {
+	if newRoute.(metav1.Object).GetDeletionTimestamp() != nil {
+		return true
+	}
+	var oldSpec, newSpec interface{}
+	switch route := newRoute.(type) {
+	case *gatewayv1alpha2.TCPRoute:
+		oldSpec, newSpec = oldRoute.(*gatewayv1alpha2.TCPRoute).Spec, route.Spec
+	case *gatewayv1alpha2.UDPRoute:
+		oldSpec, newSpec = oldRoute.(*gatewayv1alpha2.UDPRoute).Spec, route.Spec
+	case *gatewayv1alpha2.TLSRoute:
+		oldSpec, newSpec = oldRoute.(*gatewayv1alpha2.TLSRoute).Spec, route.Spec
+	}
+	oldHash := utils.Hash(utils.Stringify(oldSpec))
+	newHash := utils.Hash(utils.Stringify(newSpec))
+	return oldHash != newHash
+}
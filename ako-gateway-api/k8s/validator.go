@@ -16,17 +16,25 @@ package k8s
 
 import (
 	"fmt"
-	"regexp"
+	"net"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
 	akogatewayapiobjects "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/objects"
 	akogatewayapistatus "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/status"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	akov1alpha1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha1"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 )
 
@@ -74,20 +82,12 @@ func IsValidGateway(key string, gateway *gatewayv1.Gateway) bool {
 		return false
 	}
 
-	// has 1 or none addresses
-	if len(spec.Addresses) > 1 {
-		utils.AviLog.Errorf("key: %s, msg: more than 1 gateway address found in gateway %+v", key, gateway.Name)
+	// has at most one IPv4, one IPv6 and one NamedAddress address
+	if reason, err := validateGatewayAddresses(spec.Addresses); err != nil {
+		utils.AviLog.Errorf("key: %s, msg: gateway %s has invalid addresses, err: %v", key, gateway.Name, err)
 		defaultCondition.
-			Message("More than one address is not supported").
-			SetIn(&gatewayStatus.Conditions)
-		akogatewayapistatus.Record(key, gateway, &akogatewayapistatus.Status{GatewayStatus: gatewayStatus})
-		return false
-	}
-
-	if len(spec.Addresses) == 1 && *spec.Addresses[0].Type != "IPAddress" {
-		utils.AviLog.Errorf("key: %s, msg: gateway address is not of type IPAddress %+v", key, gateway.Name)
-		defaultCondition.
-			Message("Only IPAddress as AddressType is supported").
+			Reason(string(reason)).
+			Message(err.Error()).
 			SetIn(&gatewayStatus.Conditions)
 		akogatewayapistatus.Record(key, gateway, &akogatewayapistatus.Status{GatewayStatus: gatewayStatus})
 		return false
@@ -136,18 +136,8 @@ func isValidListener(key string, gateway *gatewayv1.Gateway, gatewayStatus *gate
 		Status(metav1.ConditionFalse).
 		ObservedGeneration(gateway.ObjectMeta.Generation)
 
-	// hostname is not nil or wildcard
-	if listener.Hostname == nil || *listener.Hostname == "*" {
-		utils.AviLog.Errorf("key: %s, msg: hostname with wildcard found in listener %s", key, listener.Name)
-		defaultCondition.
-			Message("Hostname not found or Hostname has invalid configuration").
-			SetIn(&gatewayStatus.Listeners[index].Conditions)
-		return false
-	}
-
 	// protocol validation
-	if listener.Protocol != gatewayv1.HTTPProtocolType &&
-		listener.Protocol != gatewayv1.HTTPSProtocolType {
+	if _, ok := akogatewayapilib.SupportedKinds[listener.Protocol]; !ok {
 		utils.AviLog.Errorf("key: %s, msg: protocol is not supported for listener %s", key, listener.Name)
 		defaultCondition.
 			Reason(string(gatewayv1.ListenerReasonUnsupportedProtocol)).
@@ -157,8 +147,39 @@ func isValidListener(key string, gateway *gatewayv1.Gateway, gatewayStatus *gate
 		return false
 	}
 
-	// has valid TLS config
-	if listener.TLS != nil {
+	// routes can be allowed from the same or all namespaces, the namespace selector is not supported
+	if listener.AllowedRoutes != nil && listener.AllowedRoutes.Namespaces != nil &&
+		listener.AllowedRoutes.Namespaces.From != nil && *listener.AllowedRoutes.Namespaces.From == gatewayv1.NamespacesFromSelector {
+		utils.AviLog.Errorf("key: %s, msg: namespace selector in allowed routes is not supported for listener %s", key, listener.Name)
+		defaultCondition.
+			Reason(string(gatewayv1.ListenerReasonInvalid)).
+			Message("AllowedRoutes namespaces from Selector is not supported").
+			SetIn(&gatewayStatus.Listeners[index].Conditions)
+		return false
+	}
+
+	// hostname is not nil or wildcard, it is optional for the TCP, UDP and TLS listeners
+	if !akogatewayapilib.IsL4Protocol(string(listener.Protocol)) &&
+		(listener.Hostname == nil || *listener.Hostname == "*") {
+		utils.AviLog.Errorf("key: %s, msg: hostname with wildcard found in listener %s", key, listener.Name)
+		defaultCondition.
+			Message("Hostname not found or Hostname has invalid configuration").
+			SetIn(&gatewayStatus.Listeners[index].Conditions)
+		return false
+	}
+
+	// TLS listeners are supported only in the Passthrough mode
+	if listener.Protocol == gatewayv1.TLSProtocolType {
+		if listener.TLS == nil || listener.TLS.Mode == nil || *listener.TLS.Mode != gatewayv1.TLSModePassthrough {
+			utils.AviLog.Errorf("key: %s, msg: tls mode is not passthrough for the TLS listener %+v/%+v", key, gateway.Name, listener.Name)
+			defaultCondition.
+				Reason(string(gatewayv1.ListenerReasonInvalid)).
+				Message("TLS mode must be Passthrough for the TLS protocol").
+				SetIn(&gatewayStatus.Listeners[index].Conditions)
+			return false
+		}
+	} else if listener.TLS != nil {
+		// has valid TLS config
 		if (listener.TLS.Mode != nil && *listener.TLS.Mode != gatewayv1.TLSModeTerminate) || len(listener.TLS.CertificateRefs) == 0 {
 			utils.AviLog.Errorf("key: %s, msg: tls mode/ref not valid %+v/%+v", key, gateway.Name, listener.Name)
 			defaultCondition.
@@ -178,11 +199,31 @@ func isValidListener(key string, gateway *gatewayv1.Gateway, gatewayStatus *gate
 					SetIn(&gatewayStatus.Listeners[index].Conditions)
 				return false
 			}
-
+			// cross namespace secrets require a ReferenceGrant in the namespace of the secret
+			certNamespace := gateway.Namespace
+			if certRef.Namespace != nil {
+				certNamespace = string(*certRef.Namespace)
+			}
+			if !akogatewayapilib.IsReferencePermitted(lib.Gateway, gateway.Namespace, utils.Secret, certNamespace, string(certRef.Name)) {
+				utils.AviLog.Errorf("key: %s, msg: reference to the Secret %s/%s is not permitted for %+v/%+v", key, certNamespace, certRef.Name, gateway.Name, listener.Name)
+				defaultCondition.
+					Type(string(gatewayv1.ListenerConditionResolvedRefs)).
+					Reason(string(gatewayv1.ListenerReasonRefNotPermitted)).
+					Message(fmt.Sprintf("Reference to the Secret %s/%s is not permitted", certNamespace, certRef.Name)).
+					SetIn(&gatewayStatus.Listeners[index].Conditions)
+				return false
+			}
 		}
 	}
 
 	// Valid listener
+	akogatewayapistatus.NewCondition().
+		Type(string(gatewayv1.ListenerConditionResolvedRefs)).
+		Reason(string(gatewayv1.ListenerReasonResolvedRefs)).
+		Status(metav1.ConditionTrue).
+		ObservedGeneration(gateway.ObjectMeta.Generation).
+		Message("All the references are resolved").
+		SetIn(&gatewayStatus.Listeners[index].Conditions)
 	defaultCondition.
 		Reason(string(gatewayv1.GatewayReasonAccepted)).
 		Status(metav1.ConditionTrue).
@@ -200,15 +241,6 @@ func IsHTTPRouteValid(key string, obj *gatewayv1.HTTPRoute) bool {
 		return false
 	}
 
-	for _, hostname := range httpRoute.Spec.Hostnames {
-		if strings.Contains(string(hostname), "*") {
-			utils.AviLog.Errorf("key: %s, msg: Wildcard in hostname is not supported for the HTTPRoute %s", key, httpRoute.Name)
-			akogatewayapilib.AKOControlConfig().EventRecorder().Eventf(httpRoute, corev1.EventTypeWarning,
-				lib.Detached, "Wildcard in hostname is not supported for the HTTPRoute %s", httpRoute.Name)
-			return false
-		}
-	}
-
 	httpRouteStatus := obj.Status.DeepCopy()
 	httpRouteStatus.Parents = make([]gatewayv1.RouteParentStatus, 0, len(httpRoute.Spec.ParentRefs))
 	var invalidParentRefCount int
@@ -288,6 +320,9 @@ func validateParentReference(key string, httpRoute *gatewayv1.HTTPRoute, httpRou
 
 	var listenersMatchedToRoute []gatewayv1.Listener
 	for _, listenerObj := range listenersForRoute {
+		if !akogatewayapilib.IsRouteKindSupported(string(listenerObj.Protocol), lib.HTTPRoute) {
+			continue
+		}
 		// TODO: Don't attach to a invalid listener configuration
 		// check from store
 		hostInListener := listenerObj.Hostname
@@ -296,18 +331,10 @@ func validateParentReference(key string, httpRoute *gatewayv1.HTTPRoute, httpRou
 			utils.AviLog.Errorf("key: %s, msg: no hostname found in parent", key)
 			continue
 		}
-		// replace the wildcard character with a regex
-		replacedHostname := strings.Replace(string(*hostInListener), "*", "([a-zA-Z0-9-]{1,})", 1)
-		// create the expression for pattern matching
-		pattern := fmt.Sprintf("^%s$", replacedHostname)
-		expr, err := regexp.Compile(pattern)
-		if err != nil {
-			utils.AviLog.Warnf("key: %s, msg: unable to match the hostname with listener hostname. err: %s", key, err)
-			continue
-		}
 		var matched bool
 		for _, host := range httpRoute.Spec.Hostnames {
-			matched = matched || expr.MatchString(string(host))
+			_, hostMatched := akogatewayapilib.GetHostnameIntersection(string(*hostInListener), string(host))
+			matched = matched || hostMatched
 		}
 		if !matched {
 			utils.AviLog.Warnf("key: %s, msg: Gateway object %s don't have any listeners that matches the hostnames in HTTPRoute %s", key, gateway.Name, httpRoute.Name)
@@ -318,6 +345,15 @@ func validateParentReference(key string, httpRoute *gatewayv1.HTTPRoute, httpRou
 	if len(listenersMatchedToRoute) == 0 {
 		err := fmt.Errorf("Hostname in Gateway Listener doesn't match with any of the hostnames in HTTPRoute")
 		defaultCondition.
+			Reason(string(gatewayv1.RouteReasonNoMatchingListenerHostname)).
+			Message(err.Error()).
+			SetIn(&httpRouteStatus.Parents[index].Conditions)
+		return err
+	}
+	if reason, err := validateHTTPRouteFilters(httpRoute); err != nil {
+		utils.AviLog.Errorf("key: %s, msg: filters of the HTTPRoute object %s are not valid, err: %v", key, httpRoute.Name, err)
+		defaultCondition.
+			Reason(string(reason)).
 			Message(err.Error()).
 			SetIn(&httpRouteStatus.Parents[index].Conditions)
 		return err
@@ -346,6 +382,657 @@ func validateParentReference(key string, httpRoute *gatewayv1.HTTPRoute, httpRou
 		Status(metav1.ConditionTrue).
 		Message("Parent reference is valid").
 		SetIn(&httpRouteStatus.Parents[index].Conditions)
+
+	if unsupportedFilters := getUnsupportedHTTPRouteFilters(httpRoute); len(unsupportedFilters) != 0 {
+		// the unsupported filters are ignored, the rest of the route is still programmed
+		utils.AviLog.Warnf("key: %s, msg: filters %v of the HTTPRoute object %s are not supported and are ignored", key, unsupportedFilters, httpRoute.Name)
+		message := fmt.Sprintf("%s filters are not supported and are ignored", strings.Join(unsupportedFilters, ", "))
+		if utils.HasElem(unsupportedFilters, string(gatewayv1.HTTPRouteFilterRequestMirror)) {
+			message += ", RequestMirror filters are supported only in ClusterIP mode"
+		}
+		akogatewayapistatus.NewCondition().
+			Type(string(gatewayv1.RouteConditionPartiallyInvalid)).
+			Reason(string(gatewayv1.RouteReasonUnsupportedValue)).
+			Status(metav1.ConditionTrue).
+			ObservedGeneration(httpRoute.ObjectMeta.Generation).
+			Message(message).
+			SetIn(&httpRouteStatus.Parents[index].Conditions)
+	}
+
+	var backendRefs []gatewayv1.BackendRef
+	for _, rule := range httpRoute.Spec.Rules {
+		for _, backendRef := range rule.BackendRefs {
+			backendRefs = append(backendRefs, backendRef.BackendRef)
+		}
+		for _, filter := range rule.Filters {
+			if filter.RequestMirror != nil {
+				backendRefs = append(backendRefs, gatewayv1.BackendRef{BackendObjectReference: filter.RequestMirror.BackendRef})
+			}
+		}
+	}
+	setResolvedRefsCondition(key, lib.HTTPRoute, httpRoute, backendRefs, &httpRouteStatus.Parents[index].Conditions)
 	utils.AviLog.Infof("key: %s, msg: Parent Reference %s of HTTPRoute object %s is valid", key, name, httpRoute.Name)
 	return nil
 }
+
+// IsL4RouteValid validates the TCPRoute, UDPRoute and TLSRoute objects and records the
+// parent statuses on the route and the attached routes count on the gateway listeners.
+func IsL4RouteValid(key string, obj interface{}) bool {
+	return isRouteValid(key, obj)
+}
+
+// IsGRPCRouteValid validates the GRPCRoute object and records the parent statuses on the
+// route and the attached routes count on the gateway listeners.
+func IsGRPCRouteValid(key string, obj *gatewayv1alpha2.GRPCRoute) bool {
+	return isRouteValid(key, obj)
+}
+
+func isRouteValid(key string, obj interface{}) bool {
+
+	var route runtime.Object
+	var kind string
+	var parentRefs []gatewayv1.ParentReference
+	var hostnames []gatewayv1.Hostname
+	var backendRefs []gatewayv1.BackendRef
+	var routeStatus *gatewayv1.RouteStatus
+	status := &akogatewayapistatus.Status{}
+	switch r := obj.(type) {
+	case *gatewayv1alpha2.TCPRoute:
+		tcpRoute := r.DeepCopy()
+		route, kind, parentRefs = tcpRoute, lib.TCPRoute, tcpRoute.Spec.ParentRefs
+		status.TCPRouteStatus = tcpRoute.Status.DeepCopy()
+		for _, rule := range tcpRoute.Spec.Rules {
+			backendRefs = append(backendRefs, rule.BackendRefs...)
+		}
+		routeStatus = &status.TCPRouteStatus.RouteStatus
+	case *gatewayv1alpha2.UDPRoute:
+		udpRoute := r.DeepCopy()
+		route, kind, parentRefs = udpRoute, lib.UDPRoute, udpRoute.Spec.ParentRefs
+		status.UDPRouteStatus = udpRoute.Status.DeepCopy()
+		for _, rule := range udpRoute.Spec.Rules {
+			backendRefs = append(backendRefs, rule.BackendRefs...)
+		}
+		routeStatus = &status.UDPRouteStatus.RouteStatus
+	case *gatewayv1alpha2.TLSRoute:
+		tlsRoute := r.DeepCopy()
+		route, kind, parentRefs, hostnames = tlsRoute, lib.TLSRoute, tlsRoute.Spec.ParentRefs, tlsRoute.Spec.Hostnames
+		status.TLSRouteStatus = tlsRoute.Status.DeepCopy()
+		for _, rule := range tlsRoute.Spec.Rules {
+			backendRefs = append(backendRefs, rule.BackendRefs...)
+		}
+		routeStatus = &status.TLSRouteStatus.RouteStatus
+	case *gatewayv1alpha2.GRPCRoute:
+		grpcRoute := r.DeepCopy()
+		route, kind, parentRefs, hostnames = grpcRoute, lib.GRPCRoute, grpcRoute.Spec.ParentRefs, grpcRoute.Spec.Hostnames
+		status.GRPCRouteStatus = grpcRoute.Status.DeepCopy()
+		for _, rule := range grpcRoute.Spec.Rules {
+			for _, backendRef := range rule.BackendRefs {
+				backendRefs = append(backendRefs, backendRef.BackendRef)
+			}
+		}
+		routeStatus = &status.GRPCRouteStatus.RouteStatus
+	default:
+		utils.AviLog.Warnf("key: %s, msg: unsupported route object %T", key, obj)
+		return false
+	}
+	routeMeta := route.(metav1.Object)
+
+	if len(parentRefs) == 0 {
+		utils.AviLog.Errorf("key: %s, msg: Parent Reference is empty for the %s %s", key, kind, routeMeta.GetName())
+		return false
+	}
+
+	routeStatus.Parents = make([]gatewayv1.RouteParentStatus, 0, len(parentRefs))
+	var invalidParentRefCount int
+	for index := range parentRefs {
+		err := validateRouteParentReference(key, kind, routeMeta, parentRefs[index], hostnames, backendRefs, routeStatus)
+		if err != nil {
+			invalidParentRefCount++
+			utils.AviLog.Warnf("key: %s, msg: Parent Reference %s of %s object %s is not valid, err: %v", key, parentRefs[index].Name, kind, routeMeta.GetName(), err)
+		}
+	}
+	akogatewayapistatus.Record(key, route, status)
+
+	// No valid attachment, we can't proceed with this route object.
+	if invalidParentRefCount == len(parentRefs) {
+		utils.AviLog.Errorf("key: %s, msg: %s object %s is not valid", key, kind, routeMeta.GetName())
+		akogatewayapilib.AKOControlConfig().EventRecorder().Eventf(route, corev1.EventTypeWarning,
+			lib.Detached, "%s object %s is not valid", kind, routeMeta.GetName())
+		return false
+	}
+	utils.AviLog.Infof("key: %s, msg: %s object %s is valid", key, kind, routeMeta.GetName())
+	return true
+}
+
+func validateRouteParentReference(key, kind string, route metav1.Object, parentRef gatewayv1.ParentReference, hostnames []gatewayv1.Hostname, backendRefs []gatewayv1.BackendRef, routeStatus *gatewayv1.RouteStatus) error {
+
+	name := string(parentRef.Name)
+	namespace := route.GetNamespace()
+	if parentRef.Namespace != nil {
+		namespace = string(*parentRef.Namespace)
+	}
+
+	obj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().GatewayInformer.Lister().Gateways(namespace).Get(name)
+	if err != nil {
+		utils.AviLog.Errorf("key: %s, msg: unable to get the gateway object. err: %s", key, err)
+		return err
+	}
+	gateway := obj.DeepCopy()
+
+	gwClass := string(gateway.Spec.GatewayClassName)
+	_, isAKOCtrl := akogatewayapiobjects.GatewayApiLister().IsGatewayClassControllerAKO(gwClass)
+	if !isAKOCtrl {
+		utils.AviLog.Warnf("key: %s, msg: controller for the parent reference %s of %s object %s is not ako", key, name, kind, route.GetName())
+		return fmt.Errorf("controller for the parent reference %s of %s object %s is not ako", name, kind, route.GetName())
+	}
+	// creates the Parent status only when the AKO is the gateway controller
+	routeStatus.Parents = append(routeStatus.Parents, gatewayv1.RouteParentStatus{})
+	parentStatus := &routeStatus.Parents[len(routeStatus.Parents)-1]
+	parentStatus.ControllerName = akogatewayapilib.GatewayController
+	parentStatus.ParentRef.Name = gatewayv1.ObjectName(name)
+	parentStatus.ParentRef.Namespace = (*gatewayv1.Namespace)(&namespace)
+	parentStatus.ParentRef.SectionName = parentRef.SectionName
+	parentStatus.ParentRef.Port = parentRef.Port
+
+	defaultCondition := akogatewayapistatus.NewCondition().
+		Type(string(gatewayv1.RouteConditionAccepted)).
+		Reason(string(gatewayv1.RouteReasonNoMatchingParent)).
+		Status(metav1.ConditionFalse).
+		ObservedGeneration(route.GetGeneration())
+
+	var listenersMatchedToRoute []gatewayv1.Listener
+	for _, listenerObj := range gateway.Spec.Listeners {
+		if parentRef.SectionName != nil && *parentRef.SectionName != listenerObj.Name {
+			continue
+		}
+		if parentRef.Port != nil && *parentRef.Port != listenerObj.Port {
+			continue
+		}
+		if !akogatewayapilib.IsRouteKindSupported(string(listenerObj.Protocol), kind) {
+			continue
+		}
+		// only the TLSRoute and GRPCRoute objects carry hostnames
+		if listenerObj.Hostname != nil && len(hostnames) > 0 {
+			var matched bool
+			for _, host := range hostnames {
+				_, hostMatched := akogatewayapilib.GetHostnameIntersection(string(*listenerObj.Hostname), string(host))
+				matched = matched || hostMatched
+			}
+			if !matched {
+				continue
+			}
+		}
+		listenersMatchedToRoute = append(listenersMatchedToRoute, listenerObj)
+	}
+	if len(listenersMatchedToRoute) == 0 {
+		utils.AviLog.Errorf("key: %s, msg: Gateway object %s doesn't have any listener that accepts %s %s", key, gateway.Name, kind, route.GetName())
+		err := fmt.Errorf("No listener in the Gateway accepts the %s", kind)
+		defaultCondition.
+			Message(err.Error()).
+			SetIn(&parentStatus.Conditions)
+		return err
+	}
+	if grpcRoute, ok := route.(*gatewayv1alpha2.GRPCRoute); ok {
+		if reason, err := validateGRPCRouteFilters(grpcRoute); err != nil {
+			utils.AviLog.Errorf("key: %s, msg: filters of the GRPCRoute object %s are not valid, err: %v", key, grpcRoute.Name, err)
+			defaultCondition.
+				Reason(string(reason)).
+				Message(err.Error()).
+				SetIn(&parentStatus.Conditions)
+			return err
+		}
+	}
+
+	gatewayStatus := gateway.Status.DeepCopy()
+	for _, listenerObj := range listenersMatchedToRoute {
+		// Increment the attached routes of the listener in the Gateway object
+		i := akogatewayapilib.FindListenerStatusByName(string(listenerObj.Name), gatewayStatus.Listeners)
+		if i == -1 {
+			utils.AviLog.Errorf("key: %s, msg: Gateway status is missing for the listener with name %s", key, listenerObj.Name)
+			err := fmt.Errorf("Couldn't find the listener %s in the Gateway status", listenerObj.Name)
+			defaultCondition.
+				Message(err.Error()).
+				SetIn(&parentStatus.Conditions)
+			return err
+		}
+		gatewayStatus.Listeners[i].AttachedRoutes += 1
+	}
+	akogatewayapistatus.Record(key, gateway, &akogatewayapistatus.Status{GatewayStatus: gatewayStatus})
+
+	defaultCondition.
+		Reason(string(gatewayv1.RouteReasonAccepted)).
+		Status(metav1.ConditionTrue).
+		Message("Parent reference is valid").
+		SetIn(&parentStatus.Conditions)
+	setResolvedRefsCondition(key, kind, route, backendRefs, &parentStatus.Conditions)
+	utils.AviLog.Infof("key: %s, msg: Parent Reference %s of %s object %s is valid", key, name, kind, route.GetName())
+	return nil
+}
+
+// setResolvedRefsCondition sets the ResolvedRefs condition of a route parent. Backends in a
+// namespace other than the route namespace are resolved only when permitted by a ReferenceGrant.
+func setResolvedRefsCondition(key, kind string, route metav1.Object, backendRefs []gatewayv1.BackendRef, conditions *[]metav1.Condition) {
+	condition := akogatewayapistatus.NewCondition().
+		Type(string(gatewayv1.RouteConditionResolvedRefs)).
+		Reason(string(gatewayv1.RouteReasonResolvedRefs)).
+		Status(metav1.ConditionTrue).
+		ObservedGeneration(route.GetGeneration()).
+		Message("All the references are resolved")
+	for _, backendRef := range backendRefs {
+		backendNamespace := route.GetNamespace()
+		if backendRef.Namespace != nil {
+			backendNamespace = string(*backendRef.Namespace)
+		}
+		if !akogatewayapilib.IsReferencePermitted(kind, route.GetNamespace(), utils.Service, backendNamespace, string(backendRef.Name)) {
+			utils.AviLog.Warnf("key: %s, msg: reference to the Service %s/%s from %s %s is not permitted", key, backendNamespace, backendRef.Name, kind, route.GetName())
+			condition.
+				Reason(string(gatewayv1.RouteReasonRefNotPermitted)).
+				Status(metav1.ConditionFalse).
+				Message(fmt.Sprintf("Reference to the Service %s/%s is not permitted", backendNamespace, backendRef.Name))
+			break
+		}
+	}
+	condition.SetIn(conditions)
+}
+
+// getUnsupportedHTTPRouteFilters returns the types of the filters of the HTTPRoute object,
+// which are not translated and are ignored. The RequestMirror filters are not translated
+// in the NodePort and NodePortLocal modes.
+func getUnsupportedHTTPRouteFilters(httpRoute *gatewayv1.HTTPRoute) []string {
+	var unsupportedFilters []string
+	for _, rule := range httpRoute.Spec.Rules {
+		for _, filter := range rule.Filters {
+			if filter.Type != gatewayv1.HTTPRouteFilterExtensionRef &&
+				(filter.Type != gatewayv1.HTTPRouteFilterRequestMirror || akogatewayapilib.IsRequestMirrorSupported()) {
+				continue
+			}
+			if !utils.HasElem(unsupportedFilters, string(filter.Type)) {
+				unsupportedFilters = append(unsupportedFilters, string(filter.Type))
+			}
+		}
+	}
+	return unsupportedFilters
+}
+
+func validateHTTPRouteFilters(httpRoute *gatewayv1.HTTPRoute) (gatewayv1.RouteConditionReason, error) {
+	for _, rule := range httpRoute.Spec.Rules {
+		var hasRedirect, hasRewrite bool
+		for _, filter := range rule.Filters {
+			var pathModifier *gatewayv1.HTTPPathModifier
+			switch filter.Type {
+			case gatewayv1.HTTPRouteFilterRequestRedirect:
+				hasRedirect = true
+				if filter.RequestRedirect != nil {
+					pathModifier = filter.RequestRedirect.Path
+				}
+			case gatewayv1.HTTPRouteFilterURLRewrite:
+				hasRewrite = true
+				if filter.URLRewrite != nil {
+					pathModifier = filter.URLRewrite.Path
+				}
+			}
+			if pathModifier == nil || pathModifier.Type != gatewayv1.PrefixMatchHTTPPathModifier {
+				continue
+			}
+			// the prefix to be replaced is known only for the PathPrefix matches
+			for _, match := range rule.Matches {
+				if match.Path != nil && match.Path.Type != nil && *match.Path.Type != gatewayv1.PathMatchPathPrefix {
+					return gatewayv1.RouteReasonIncompatibleFilters, fmt.Errorf("%s filter with ReplacePrefixMatch requires PathPrefix matches", filter.Type)
+				}
+			}
+		}
+		if hasRedirect && hasRewrite {
+			return gatewayv1.RouteReasonIncompatibleFilters, fmt.Errorf("RequestRedirect and URLRewrite filters cannot be used in the same rule")
+		}
+	}
+	return "", nil
+}
+
+// validateGatewayAddresses returns an error for the addresses which can't be configured on the vsvip,
+// a Gateway supports at most one IPv4 and one IPv6 address, and one NamedAddress.
+func validateGatewayAddresses(addresses []gatewayv1.GatewayAddress) (gatewayv1.GatewayConditionReason, error) {
+	var v4Count, v6Count, namedCount int
+	for _, address := range addresses {
+		addressType := gatewayv1.IPAddressType
+		if address.Type != nil {
+			addressType = *address.Type
+		}
+		switch addressType {
+		case gatewayv1.IPAddressType:
+			ip := net.ParseIP(address.Value)
+			if ip == nil {
+				return gatewayv1.GatewayReasonInvalid, fmt.Errorf("%s is not a valid IP address", address.Value)
+			}
+			if ip.To4() != nil {
+				v4Count++
+			} else {
+				v6Count++
+			}
+		case gatewayv1.NamedAddressType:
+			namedCount++
+		default:
+			return gatewayv1.GatewayReasonUnsupportedAddress, fmt.Errorf("Only IPAddress and NamedAddress as AddressType are supported")
+		}
+	}
+	if v4Count > 1 {
+		return gatewayv1.GatewayReasonUnsupportedAddress, fmt.Errorf("More than one IPv4 address is not supported")
+	}
+	if v6Count > 1 {
+		return gatewayv1.GatewayReasonUnsupportedAddress, fmt.Errorf("More than one IPv6 address is not supported")
+	}
+	if namedCount > 1 {
+		return gatewayv1.GatewayReasonUnsupportedAddress, fmt.Errorf("More than one NamedAddress is not supported")
+	}
+	return "", nil
+}
+
+// validateGRPCRouteFilters returns an error for the filters which can't be translated, only the
+// header modifier filters are supported on GRPCRoute objects.
+func validateGRPCRouteFilters(grpcRoute *gatewayv1alpha2.GRPCRoute) (gatewayv1.RouteConditionReason, error) {
+	for _, rule := range grpcRoute.Spec.Rules {
+		filters := rule.Filters
+		for _, backendRef := range rule.BackendRefs {
+			filters = append(filters, backendRef.Filters...)
+		}
+		for _, filter := range filters {
+			if filter.Type != gatewayv1alpha2.GRPCRouteFilterRequestHeaderModifier &&
+				filter.Type != gatewayv1alpha2.GRPCRouteFilterResponseHeaderModifier {
+				return gatewayv1.RouteReasonUnsupportedValue, fmt.Errorf("%s filter is not supported", filter.Type)
+			}
+		}
+	}
+	return "", nil
+}
+
+// IsBackendPolicyValid validates the target and the Avi object references of a BackendPolicy, and records
+// the result as the Accepted condition of the BackendPolicy.
+func IsBackendPolicyValid(key string, obj *akov1alpha1.BackendPolicy) bool {
+
+	backendPolicy := obj.DeepCopy()
+	backendPolicyStatus := obj.Status.DeepCopy()
+	condition := akogatewayapistatus.NewCondition().
+		Type(string(gatewayv1alpha2.PolicyConditionAccepted)).
+		ObservedGeneration(backendPolicy.ObjectMeta.Generation)
+
+	reason, err := validateBackendPolicy(key, backendPolicy)
+	if err != nil {
+		utils.AviLog.Errorf("key: %s, msg: BackendPolicy object %s is not valid, err: %v", key, backendPolicy.Name, err)
+		condition.Status(metav1.ConditionFalse).
+			Reason(string(reason)).
+			Message(err.Error()).
+			SetIn(&backendPolicyStatus.Conditions)
+		akogatewayapistatus.Record(key, backendPolicy, &akogatewayapistatus.Status{BackendPolicyStatus: backendPolicyStatus})
+		return false
+	}
+
+	condition.Status(metav1.ConditionTrue).
+		Reason(string(gatewayv1alpha2.PolicyReasonAccepted)).
+		Message("BackendPolicy is accepted").
+		SetIn(&backendPolicyStatus.Conditions)
+	akogatewayapistatus.Record(key, backendPolicy, &akogatewayapistatus.Status{BackendPolicyStatus: backendPolicyStatus})
+	utils.AviLog.Infof("key: %s, msg: BackendPolicy object %s is valid", key, backendPolicy.Name)
+	return true
+}
+
+func validateBackendPolicy(key string, backendPolicy *akov1alpha1.BackendPolicy) (gatewayv1alpha2.PolicyConditionReason, error) {
+	targetRef := backendPolicy.Spec.TargetRef
+	switch {
+	case targetRef.Group == "" && targetRef.Kind == utils.Service:
+	case targetRef.Group == gatewayv1.GroupName && targetRef.Kind == lib.HTTPRoute:
+		if targetRef.SectionName != nil {
+			if ruleIndex, err := strconv.Atoi(*targetRef.SectionName); err != nil || ruleIndex < 0 {
+				return gatewayv1alpha2.PolicyReasonInvalid, fmt.Errorf("sectionName %s is not a valid HTTPRoute rule index", *targetRef.SectionName)
+			}
+		}
+	case targetRef.Group == gatewayv1.GroupName && targetRef.Kind == lib.Gateway:
+		if targetRef.SectionName != nil {
+			return gatewayv1alpha2.PolicyReasonInvalid, fmt.Errorf("sectionName is not supported for a Gateway target")
+		}
+	default:
+		return gatewayv1alpha2.PolicyReasonInvalid, fmt.Errorf("target %s of group %q is not supported", targetRef.Kind, targetRef.Group)
+	}
+
+	// only the oldest of the policies with the same target is accepted
+	backendPolicies, err := akogatewayapilib.AKOControlConfig().AKOCRDInformers().BackendPolicyInformer.Lister().BackendPolicies(backendPolicy.Namespace).List(labels.Everything())
+	if err != nil {
+		return gatewayv1alpha2.PolicyReasonInvalid, fmt.Errorf("unable to list the BackendPolicies, err: %v", err)
+	}
+	for _, otherPolicy := range backendPolicies {
+		if otherPolicy.Name == backendPolicy.Name || !reflect.DeepEqual(otherPolicy.Spec.TargetRef, targetRef) {
+			continue
+		}
+		if akogatewayapilib.OlderBackendPolicy(backendPolicy, otherPolicy) == otherPolicy {
+			return gatewayv1alpha2.PolicyReasonConflicted, fmt.Errorf("BackendPolicy %s has the same target", otherPolicy.Name)
+		}
+	}
+
+	lbPolicy := backendPolicy.Spec.LoadBalancerPolicy
+	if lbPolicy.HostHeader != "" && lbPolicy.Hash != lib.LB_ALGORITHM_CONSISTENT_HASH_CUSTOM_HEADER {
+		utils.AviLog.Warnf("key: %s, HostHeader is only applicable for LB_ALGORITHM_CONSISTENT_HASH_CUSTOM_HEADER", key)
+	}
+
+	refData := make(map[string]string)
+	refData[backendPolicy.Spec.ApplicationPersistence] = "ApplicationPersistence"
+	for _, hm := range backendPolicy.Spec.HealthMonitors {
+		refData[hm] = "HealthMonitor"
+	}
+	if err := k8s.CheckRefsOnController(key, refData); err != nil {
+		return gatewayv1alpha2.PolicyReasonInvalid, err
+	}
+	return "", nil
+}
+
+// IsBackendTLSPolicyValid validates the target and the CA certificate refs of a BackendTLSPolicy, and records
+// the result as the Accepted and ResolvedRefs conditions for each Gateway the policy applies to. A policy
+// applies to the Gateways which have a route with the target Service as a backend.
+func IsBackendTLSPolicyValid(key string, obj *gatewayv1alpha2.BackendTLSPolicy) bool {
+
+	backendTLSPolicy := obj.DeepCopy()
+	acceptedCondition := akogatewayapistatus.NewCondition().
+		Type(string(gatewayv1alpha2.PolicyConditionAccepted)).
+		ObservedGeneration(backendTLSPolicy.ObjectMeta.Generation)
+	resolvedRefsCondition := akogatewayapistatus.NewCondition().
+		Type(string(gatewayv1.RouteConditionResolvedRefs)).
+		ObservedGeneration(backendTLSPolicy.ObjectMeta.Generation)
+
+	isValid := true
+	if reason, err := validateBackendTLSPolicyTarget(backendTLSPolicy); err != nil {
+		utils.AviLog.Errorf("key: %s, msg: BackendTLSPolicy object %s is not valid, err: %v", key, backendTLSPolicy.Name, err)
+		acceptedCondition.Status(metav1.ConditionFalse).Reason(string(reason)).Message(err.Error())
+		isValid = false
+	} else {
+		acceptedCondition.Status(metav1.ConditionTrue).Reason(string(gatewayv1alpha2.PolicyReasonAccepted)).Message("BackendTLSPolicy is accepted")
+	}
+
+	caCerts, err := akogatewayapilib.GetBackendTLSPolicyCACerts(backendTLSPolicy)
+	if err == nil && len(caCerts) == 0 && backendTLSPolicy.Spec.TLS.WellKnownCACerts == nil {
+		err = fmt.Errorf("no CA certificates are specified")
+	}
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: CA certificates of BackendTLSPolicy object %s are not resolved, err: %v", key, backendTLSPolicy.Name, err)
+		resolvedRefsCondition.Status(metav1.ConditionFalse).Reason(akogatewayapilib.BackendTLSPolicyReasonInvalidCACertificateRef).Message(err.Error())
+	} else {
+		resolvedRefsCondition.Status(metav1.ConditionTrue).Reason(string(gatewayv1.RouteReasonResolvedRefs)).Message("CA certificates are resolved")
+	}
+
+	// the ancestors of the other controllers are retained
+	backendTLSPolicyStatus := &gatewayv1alpha2.PolicyStatus{}
+	for _, ancestor := range backendTLSPolicy.Status.Ancestors {
+		if string(ancestor.ControllerName) != akogatewayapilib.GatewayController {
+			backendTLSPolicyStatus.Ancestors = append(backendTLSPolicyStatus.Ancestors, ancestor)
+		}
+	}
+	for _, ancestorRef := range getBackendTLSPolicyAncestors(backendTLSPolicy.Namespace, string(backendTLSPolicy.Spec.TargetRef.Name)) {
+		ancestorStatus := gatewayv1alpha2.PolicyAncestorStatus{
+			AncestorRef:    ancestorRef,
+			ControllerName: akogatewayapilib.GatewayController,
+		}
+		for _, ancestor := range backendTLSPolicy.Status.Ancestors {
+			if reflect.DeepEqual(ancestor.AncestorRef, ancestorRef) && string(ancestor.ControllerName) == akogatewayapilib.GatewayController {
+				ancestorStatus.Conditions = append([]metav1.Condition{}, ancestor.Conditions...)
+				break
+			}
+		}
+		acceptedCondition.SetIn(&ancestorStatus.Conditions)
+		resolvedRefsCondition.SetIn(&ancestorStatus.Conditions)
+		backendTLSPolicyStatus.Ancestors = append(backendTLSPolicyStatus.Ancestors, ancestorStatus)
+	}
+	akogatewayapistatus.Record(key, backendTLSPolicy, &akogatewayapistatus.Status{PolicyStatus: backendTLSPolicyStatus})
+	return isValid
+}
+
+func validateBackendTLSPolicyTarget(backendTLSPolicy *gatewayv1alpha2.BackendTLSPolicy) (gatewayv1alpha2.PolicyConditionReason, error) {
+	targetRef := backendTLSPolicy.Spec.TargetRef
+	if targetRef.Group != "" || targetRef.Kind != utils.Service {
+		return gatewayv1alpha2.PolicyReasonInvalid, fmt.Errorf("target %s of group %q is not supported", targetRef.Kind, targetRef.Group)
+	}
+	if targetRef.Namespace != nil && string(*targetRef.Namespace) != backendTLSPolicy.Namespace {
+		return gatewayv1alpha2.PolicyReasonInvalid, fmt.Errorf("target Service must be in the namespace of the BackendTLSPolicy")
+	}
+	_, err := utils.GetInformers().ServiceInformer.Lister().Services(backendTLSPolicy.Namespace).Get(string(targetRef.Name))
+	if err != nil {
+		return gatewayv1alpha2.PolicyReasonTargetNotFound, fmt.Errorf("target Service %s not found", targetRef.Name)
+	}
+
+	// only the oldest of the policies with the same target is applied
+	var sectionName string
+	if targetRef.SectionName != nil {
+		sectionName = string(*targetRef.SectionName)
+	}
+	appliedPolicy := akogatewayapilib.GetBackendTLSPolicy(backendTLSPolicy.Namespace, string(targetRef.Name), sectionName)
+	if appliedPolicy != nil && appliedPolicy.Name != backendTLSPolicy.Name {
+		return gatewayv1alpha2.PolicyReasonConflicted, fmt.Errorf("BackendTLSPolicy %s has the same target", appliedPolicy.Name)
+	}
+	return "", nil
+}
+
+// getBackendTLSPolicyAncestors returns the Gateways, with AKO as the controller, of the HTTPRoutes and
+// GRPCRoutes which have the Service as a backend.
+func getBackendTLSPolicyAncestors(svcNamespace, svcName string) []gatewayv1.ParentReference {
+	informers := akogatewayapilib.AKOControlConfig().GatewayApiInformers()
+	var parentRefs []gatewayv1.ParentReference
+	addParentRefs := func(routeNamespace string, routeParentRefs []gatewayv1.ParentReference, backendRefs []gatewayv1.BackendRef) {
+		hasBackend := false
+		for _, backendRef := range backendRefs {
+			if isServiceBackendRef(routeNamespace, backendRef.BackendObjectReference, svcNamespace, svcName) {
+				hasBackend = true
+				break
+			}
+		}
+		if !hasBackend {
+			return
+		}
+		for _, parentRef := range routeParentRefs {
+			gwNamespace := routeNamespace
+			if parentRef.Namespace != nil {
+				gwNamespace = string(*parentRef.Namespace)
+			}
+			gateway, err := informers.GatewayInformer.Lister().Gateways(gwNamespace).Get(string(parentRef.Name))
+			if err != nil {
+				continue
+			}
+			if _, isAkoCtrl := akogatewayapiobjects.GatewayApiLister().IsGatewayClassControllerAKO(string(gateway.Spec.GatewayClassName)); !isAkoCtrl {
+				continue
+			}
+			group := gatewayv1.Group(gatewayv1.GroupName)
+			kind := gatewayv1.Kind(lib.Gateway)
+			namespace := gatewayv1.Namespace(gwNamespace)
+			ancestorRef := gatewayv1.ParentReference{Group: &group, Kind: &kind, Namespace: &namespace, Name: parentRef.Name}
+			found := false
+			for i := range parentRefs {
+				if reflect.DeepEqual(parentRefs[i], ancestorRef) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				parentRefs = append(parentRefs, ancestorRef)
+			}
+		}
+	}
+
+	httpRoutes, err := informers.HTTPRouteInformer.Lister().List(labels.Everything())
+	if err != nil {
+		utils.AviLog.Warnf("Unable to list the HTTPRoutes, err: %v", err)
+	}
+	for _, httpRoute := range httpRoutes {
+		addParentRefs(httpRoute.Namespace, httpRoute.Spec.ParentRefs, getHTTPRouteBackendRefs(httpRoute))
+	}
+	grpcRoutes, err := informers.GRPCRouteInformer.Lister().List(labels.Everything())
+	if err != nil {
+		utils.AviLog.Warnf("Unable to list the GRPCRoutes, err: %v", err)
+	}
+	for _, grpcRoute := range grpcRoutes {
+		addParentRefs(grpcRoute.Namespace, grpcRoute.Spec.ParentRefs, getGRPCRouteBackendRefs(grpcRoute))
+	}
+
+	sort.Slice(parentRefs, func(i, j int) bool {
+		return string(*parentRefs[i].Namespace)+"/"+string(parentRefs[i].Name) < string(*parentRefs[j].Namespace)+"/"+string(parentRefs[j].Name)
+	})
+	return parentRefs
+}
+
+func isServiceBackendRef(routeNamespace string, backendRef gatewayv1.BackendObjectReference, svcNamespace, svcName string) bool {
+	if (backendRef.Group != nil && *backendRef.Group != "") || (backendRef.Kind != nil && *backendRef.Kind != utils.Service) {
+		return false
+	}
+	namespace := routeNamespace
+	if backendRef.Namespace != nil {
+		namespace = string(*backendRef.Namespace)
+	}
+	return namespace == svcNamespace && string(backendRef.Name) == svcName
+}
+
+// validateBackendTLSPoliciesForServices re-validates the BackendTLSPolicies which target the Services, as the
+// Gateways the policies apply to, or the existence of the targets, may have changed.
+func validateBackendTLSPoliciesForServices(key, namespace string, svcNames []string) {
+	backendTLSPolicies, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().BackendTLSPolicyInformer.Lister().BackendTLSPolicies(namespace).List(labels.Everything())
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to list the BackendTLSPolicies in namespace %s, err: %v", key, namespace, err)
+		return
+	}
+	for _, backendTLSPolicy := range backendTLSPolicies {
+		if utils.HasElem(svcNames, string(backendTLSPolicy.Spec.TargetRef.Name)) {
+			IsBackendTLSPolicyValid(lib.BackendTLSPolicy+"/"+utils.ObjKey(backendTLSPolicy), backendTLSPolicy)
+		}
+	}
+}
+
+// validateBackendTLSPoliciesForRoute re-validates the BackendTLSPolicies which target the backends of a route,
+// as the route adds or removes its Gateways from the ancestors of the policies.
+func validateBackendTLSPoliciesForRoute(key, routeNamespace string, backendRefs []gatewayv1.BackendRef) {
+	svcNamesByNamespace := make(map[string][]string)
+	for _, backendRef := range backendRefs {
+		if (backendRef.Group != nil && *backendRef.Group != "") || (backendRef.Kind != nil && *backendRef.Kind != utils.Service) {
+			continue
+		}
+		namespace := routeNamespace
+		if backendRef.Namespace != nil {
+			namespace = string(*backendRef.Namespace)
+		}
+		svcNamesByNamespace[namespace] = append(svcNamesByNamespace[namespace], string(backendRef.Name))
+	}
+	for namespace, svcNames := range svcNamesByNamespace {
+		validateBackendTLSPoliciesForServices(key, namespace, svcNames)
+	}
+}
+
+func getHTTPRouteBackendRefs(httpRoute *gatewayv1.HTTPRoute) []gatewayv1.BackendRef {
+	var backendRefs []gatewayv1.BackendRef
+	for _, rule := range httpRoute.Spec.Rules {
+		for _, backendRef := range rule.BackendRefs {
+			backendRefs = append(backendRefs, backendRef.BackendRef)
+		}
+	}
+	return backendRefs
+}
+
+func getGRPCRouteBackendRefs(grpcRoute *gatewayv1alpha2.GRPCRoute) []gatewayv1.BackendRef {
+	var backendRefs []gatewayv1.BackendRef
+	for _, rule := range grpcRoute.Spec.Rules {
+		for _, backendRef := range rule.BackendRefs {
+			backendRefs = append(backendRefs, backendRef.BackendRef)
+		}
+	}
+	return backendRefs
+}
@@ -26,3 +26,12 @@ const (
 const (
 	GatewayClassGatewayControllerIndex = "GatewayClassGatewayController"
 )
+
+const (
+	// BackendTLSPolicyCACertKey is the key of the CA certificate in the ConfigMaps referred by a BackendTLSPolicy.
+	BackendTLSPolicyCACertKey = "ca.crt"
+
+	// BackendTLSPolicyReasonInvalidCACertificateRef is used with the ResolvedRefs condition of a BackendTLSPolicy
+	// when a CA certificate ref can't be resolved.
+	BackendTLSPolicyReasonInvalidCACertificateRef = "InvalidCACertificateRef"
+)
@@ -19,18 +19,35 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	coreinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
 	gatewayclientset "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned"
 	gatewayinformerv1 "sigs.k8s.io/gateway-api/pkg/client/informers/externalversions/apis/v1"
+	gatewayinformerv1alpha2 "sigs.k8s.io/gateway-api/pkg/client/informers/externalversions/apis/v1alpha2"
+	gatewayinformerv1beta1 "sigs.k8s.io/gateway-api/pkg/client/informers/externalversions/apis/v1beta1"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	akocrd "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha1/clientset/versioned"
+	akoinformerv1alpha1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha1/informers/externalversions/ako/v1alpha1"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 )
 
 type GatewayAPIInformers struct {
-	GatewayInformer      gatewayinformerv1.GatewayInformer
-	GatewayClassInformer gatewayinformerv1.GatewayClassInformer
-	HTTPRouteInformer    gatewayinformerv1.HTTPRouteInformer
+	GatewayInformer          gatewayinformerv1.GatewayInformer
+	GatewayClassInformer     gatewayinformerv1.GatewayClassInformer
+	HTTPRouteInformer        gatewayinformerv1.HTTPRouteInformer
+	TCPRouteInformer         gatewayinformerv1alpha2.TCPRouteInformer
+	UDPRouteInformer         gatewayinformerv1alpha2.UDPRouteInformer
+	TLSRouteInformer         gatewayinformerv1alpha2.TLSRouteInformer
+	GRPCRouteInformer        gatewayinformerv1alpha2.GRPCRouteInformer
+	ReferenceGrantInformer   gatewayinformerv1beta1.ReferenceGrantInformer
+	BackendTLSPolicyInformer gatewayinformerv1alpha2.BackendTLSPolicyInformer
+	// ConfigMapInformer watches the ConfigMaps in all the namespaces, for the CA certificates of the BackendTLSPolicies.
+	ConfigMapInformer coreinformers.ConfigMapInformer
+}
+
+type AKOCRDInformers struct {
+	BackendPolicyInformer akoinformerv1alpha1.BackendPolicyInformer
 }
 
 // akoControlConfig struct is intended to store all AKO related global
@@ -43,6 +60,10 @@ type akoControlConfig struct {
 	gwApiCS        gatewayclientset.Interface
 	gwApiInformers *GatewayAPIInformers
 
+	// client-set and informer for the AKO CRDs of Gateway API.
+	akoCRDCS        akocrd.Interface
+	akoCRDInformers *AKOCRDInformers
+
 	// akoEventRecorder is used to store record.akoEventRecorder
 	// that allows AKO to broadcast kubernetes Events.
 	akoEventRecorder *utils.EventRecorder
@@ -82,6 +103,22 @@ func (c *akoControlConfig) GatewayApiInformers() *GatewayAPIInformers {
 	return c.gwApiInformers
 }
 
+func (c *akoControlConfig) SetAKOCRDClientset(cs akocrd.Interface) {
+	c.akoCRDCS = cs
+}
+
+func (c *akoControlConfig) AKOCRDClientset() akocrd.Interface {
+	return c.akoCRDCS
+}
+
+func (c *akoControlConfig) SetAKOCRDInformers(i *AKOCRDInformers) {
+	c.akoCRDInformers = i
+}
+
+func (c *akoControlConfig) AKOCRDInformers() *AKOCRDInformers {
+	return c.akoCRDInformers
+}
+
 func (c *akoControlConfig) ControllerVersion() string {
 	return c.controllerVersion
 }
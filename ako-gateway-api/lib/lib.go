@@ -15,19 +15,28 @@
 package lib
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	akov1alpha1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha1"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 )
 
 func InformersToRegister(kclient *kubernetes.Clientset) ([]string, error) {
 	// Initialize the following informers in all AKO deployments. Provide AKO the ability to watch over
-	// Services, Endpoints, Secrets, ConfigMaps.
+	// Services, EndpointSlices (or Endpoints), Secrets, ConfigMaps.
 	allInformers := []string{
 		utils.ServiceInformer,
-		utils.EndpointInformer,
+		utils.GetEndpointInformer(kclient),
 		utils.SecretInformer,
 		utils.ConfigMapInformer,
 	}
@@ -58,6 +67,271 @@ func GetPoolGroupName(parentNs, parentName, routeNs, routeName, matchName string
 	return lib.Encode(name, lib.PG)
 }
 
+// l4 policyset name format - encoded value of parentNs-parentName-routeKind-routeNs-routeName
+func GetL4PolicySetName(parentNs, parentName, routeKind, routeNs, routeName string) string {
+	name := parentNs + "-" + parentName + "-" + routeKind + "-" + routeNs + "-" + routeName
+	return lib.Encode(name, lib.L4PS)
+}
+
+// IsL4Protocol returns true for the listener protocols that are served through L4 policysets.
+func IsL4Protocol(protocol string) bool {
+	switch gatewayv1.ProtocolType(protocol) {
+	case gatewayv1.TCPProtocolType, gatewayv1.UDPProtocolType, gatewayv1.TLSProtocolType:
+		return true
+	}
+	return false
+}
+
+// IsL4RouteKind returns true for the route kinds that are attached to L4 listeners.
+func IsL4RouteKind(kind string) bool {
+	return kind == lib.TCPRoute || kind == lib.UDPRoute || kind == lib.TLSRoute
+}
+
+// IsRouteKindSupported returns true if a route of the given kind can attach to a listener of the given protocol.
+func IsRouteKindSupported(protocol, kind string) bool {
+	for _, routeGroupKind := range SupportedKinds[gatewayv1.ProtocolType(protocol)] {
+		if string(routeGroupKind.Kind) == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// GetHostnameIntersection returns the hostname matched by both the listener and the route hostnames
+// as defined by the Gateway API spec, the more specific of the two hostnames is returned. A wildcard
+// label matches one or more labels and an empty hostname matches all the hostnames.
+func GetHostnameIntersection(listenerHostname, routeHostname string) (string, bool) {
+	if listenerHostname == "" || listenerHostname == "*" {
+		return routeHostname, true
+	}
+	if routeHostname == "" || routeHostname == "*" {
+		return listenerHostname, true
+	}
+	if listenerHostname == routeHostname || isWildcardMatched(listenerHostname, routeHostname) {
+		return routeHostname, true
+	}
+	if isWildcardMatched(routeHostname, listenerHostname) {
+		return listenerHostname, true
+	}
+	return "", false
+}
+
+// isWildcardMatched returns true if the hostname has one or more labels in place of the wildcard label.
+func isWildcardMatched(wildcardHostname, hostname string) bool {
+	if !strings.HasPrefix(wildcardHostname, "*.") {
+		return false
+	}
+	suffix := wildcardHostname[1:]
+	return len(hostname) > len(suffix) && strings.HasSuffix(hostname, suffix)
+}
+
+// IsWildcardHostname returns true if the left-most label of the hostname is a wildcard.
+func IsWildcardHostname(hostname string) bool {
+	return strings.HasPrefix(hostname, "*")
+}
+
+// IsReferencePermitted returns true if the object of the fromKind in the fromNamespace is allowed to
+// refer to the object of the toKind in the toNamespace. References within the same namespace are always
+// permitted, cross namespace references require a ReferenceGrant in the namespace of the referent.
+func IsReferencePermitted(fromKind, fromNamespace, toKind, toNamespace, toName string) bool {
+	if fromNamespace == toNamespace {
+		return true
+	}
+	referenceGrants, err := AKOControlConfig().GatewayApiInformers().ReferenceGrantInformer.Lister().ReferenceGrants(toNamespace).List(labels.Everything())
+	if err != nil {
+		utils.AviLog.Warnf("Unable to list the ReferenceGrants in namespace %s, err: %v", toNamespace, err)
+		return false
+	}
+	for _, referenceGrant := range referenceGrants {
+		fromMatched := false
+		for _, from := range referenceGrant.Spec.From {
+			if string(from.Group) == gatewayv1.GroupName && string(from.Kind) == fromKind && string(from.Namespace) == fromNamespace {
+				fromMatched = true
+				break
+			}
+		}
+		if !fromMatched {
+			continue
+		}
+		for _, to := range referenceGrant.Spec.To {
+			// Service and Secret are in the core group
+			if to.Group == "" && string(to.Kind) == toKind && (to.Name == nil || string(*to.Name) == toName) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetReferenceGrantFrom returns the kinds and the namespaces of the Gateway API objects a ReferenceGrant
+// permits references from, in the format fromKind/fromNamespace.
+func GetReferenceGrantFrom(referenceGrant *gatewayv1beta1.ReferenceGrant) []string {
+	var fromKindNsList []string
+	for _, from := range referenceGrant.Spec.From {
+		if string(from.Group) != gatewayv1.GroupName {
+			continue
+		}
+		fromKindNs := string(from.Kind) + "/" + string(from.Namespace)
+		if !utils.HasElem(fromKindNsList, fromKindNs) {
+			fromKindNsList = append(fromKindNsList, fromKindNs)
+		}
+	}
+	return fromKindNsList
+}
+
+// GetBackendTLSPolicy returns the BackendTLSPolicy which applies to the port of the Service. A policy
+// targeting the port by its name takes precedence over a policy targeting the whole Service, and among
+// conflicting policies the oldest one is picked.
+func GetBackendTLSPolicy(namespace, svcName, portName string) *gatewayv1alpha2.BackendTLSPolicy {
+	backendTLSPolicies, err := AKOControlConfig().GatewayApiInformers().BackendTLSPolicyInformer.Lister().BackendTLSPolicies(namespace).List(labels.Everything())
+	if err != nil {
+		utils.AviLog.Warnf("Unable to list the BackendTLSPolicies in namespace %s, err: %v", namespace, err)
+		return nil
+	}
+	var svcPolicy, portPolicy *gatewayv1alpha2.BackendTLSPolicy
+	for _, backendTLSPolicy := range backendTLSPolicies {
+		targetRef := backendTLSPolicy.Spec.TargetRef
+		if targetRef.Group != "" || targetRef.Kind != "Service" || string(targetRef.Name) != svcName {
+			continue
+		}
+		if targetRef.Namespace != nil && string(*targetRef.Namespace) != namespace {
+			continue
+		}
+		if targetRef.SectionName == nil {
+			svcPolicy = olderBackendTLSPolicy(svcPolicy, backendTLSPolicy)
+		} else if portName != "" && string(*targetRef.SectionName) == portName {
+			portPolicy = olderBackendTLSPolicy(portPolicy, backendTLSPolicy)
+		}
+	}
+	if portPolicy != nil {
+		return portPolicy
+	}
+	return svcPolicy
+}
+
+// GetBackendTLSPolicyCACerts returns the CA certificates in the ConfigMaps referred by a BackendTLSPolicy. An
+// error is returned if any of the refs is not a ConfigMap, or the ConfigMap or its CA certificate is missing.
+func GetBackendTLSPolicyCACerts(backendTLSPolicy *gatewayv1alpha2.BackendTLSPolicy) ([]string, error) {
+	caCertRefs := backendTLSPolicy.Spec.TLS.CACertRefs
+	caCerts := make([]string, 0, len(caCertRefs))
+	for _, caCertRef := range caCertRefs {
+		if caCertRef.Group != "" || caCertRef.Kind != "ConfigMap" {
+			return nil, fmt.Errorf("CA certificate ref %s/%s is not supported", caCertRef.Kind, caCertRef.Name)
+		}
+		configMap, err := AKOControlConfig().GatewayApiInformers().ConfigMapInformer.Lister().ConfigMaps(backendTLSPolicy.Namespace).Get(string(caCertRef.Name))
+		if err != nil {
+			return nil, fmt.Errorf("unable to get the ConfigMap %s, err: %v", caCertRef.Name, err)
+		}
+		caCert := configMap.Data[BackendTLSPolicyCACertKey]
+		if caCert == "" {
+			return nil, fmt.Errorf("ConfigMap %s has no %s", caCertRef.Name, BackendTLSPolicyCACertKey)
+		}
+		caCerts = append(caCerts, caCert)
+	}
+	return caCerts, nil
+}
+
+// GetBackendTLSPoliciesForConfigMap returns the BackendTLSPolicies which refer to the ConfigMap for the CA certificates.
+func GetBackendTLSPoliciesForConfigMap(namespace, name string) []*gatewayv1alpha2.BackendTLSPolicy {
+	backendTLSPolicies, err := AKOControlConfig().GatewayApiInformers().BackendTLSPolicyInformer.Lister().BackendTLSPolicies(namespace).List(labels.Everything())
+	if err != nil {
+		utils.AviLog.Warnf("Unable to list the BackendTLSPolicies in namespace %s, err: %v", namespace, err)
+		return nil
+	}
+	var configMapPolicies []*gatewayv1alpha2.BackendTLSPolicy
+	for _, backendTLSPolicy := range backendTLSPolicies {
+		for _, caCertRef := range backendTLSPolicy.Spec.TLS.CACertRefs {
+			if caCertRef.Group == "" && caCertRef.Kind == "ConfigMap" && string(caCertRef.Name) == name {
+				configMapPolicies = append(configMapPolicies, backendTLSPolicy)
+				break
+			}
+		}
+	}
+	return configMapPolicies
+}
+
+func olderBackendTLSPolicy(current, candidate *gatewayv1alpha2.BackendTLSPolicy) *gatewayv1alpha2.BackendTLSPolicy {
+	if current == nil {
+		return candidate
+	}
+	if candidate.CreationTimestamp.Before(&current.CreationTimestamp) ||
+		(candidate.CreationTimestamp.Equal(&current.CreationTimestamp) && candidate.Name < current.Name) {
+		return candidate
+	}
+	return current
+}
+
+// GetBackendPolicy returns the accepted BackendPolicy which applies to a backend of a route rule. The most
+// specific target takes precedence, in the order: the rule of the route, the route, the port of the Service,
+// the Service and the parent Gateway. Among the policies with the same target, the oldest one is picked.
+func GetBackendPolicy(gwNs, gwName, routeKind, routeNs, routeName string, ruleIndex int, svcNs, svcName, portName string) *akov1alpha1.BackendPolicy {
+	routeTargetRef := akov1alpha1.BackendPolicyTargetRef{Group: gatewayv1.GroupName, Kind: routeKind, Name: routeName}
+	if backendPolicy := getBackendPolicyForTarget(routeNs, routeTargetRef, strconv.Itoa(ruleIndex)); backendPolicy != nil {
+		return backendPolicy
+	}
+	if backendPolicy := getBackendPolicyForTarget(routeNs, routeTargetRef, ""); backendPolicy != nil {
+		return backendPolicy
+	}
+	svcTargetRef := akov1alpha1.BackendPolicyTargetRef{Kind: utils.Service, Name: svcName}
+	if portName != "" {
+		if backendPolicy := getBackendPolicyForTarget(svcNs, svcTargetRef, portName); backendPolicy != nil {
+			return backendPolicy
+		}
+	}
+	if backendPolicy := getBackendPolicyForTarget(svcNs, svcTargetRef, ""); backendPolicy != nil {
+		return backendPolicy
+	}
+	gwTargetRef := akov1alpha1.BackendPolicyTargetRef{Group: gatewayv1.GroupName, Kind: lib.Gateway, Name: gwName}
+	return getBackendPolicyForTarget(gwNs, gwTargetRef, "")
+}
+
+// getBackendPolicyForTarget returns the oldest accepted BackendPolicy with the given target. An empty
+// sectionName selects the policies which target the whole object.
+func getBackendPolicyForTarget(namespace string, targetRef akov1alpha1.BackendPolicyTargetRef, sectionName string) *akov1alpha1.BackendPolicy {
+	backendPolicies, err := AKOControlConfig().AKOCRDInformers().BackendPolicyInformer.Lister().BackendPolicies(namespace).List(labels.Everything())
+	if err != nil {
+		utils.AviLog.Warnf("Unable to list the BackendPolicies in namespace %s, err: %v", namespace, err)
+		return nil
+	}
+	var selectedPolicy *akov1alpha1.BackendPolicy
+	for _, backendPolicy := range backendPolicies {
+		policyTargetRef := backendPolicy.Spec.TargetRef
+		if policyTargetRef.Group != targetRef.Group || policyTargetRef.Kind != targetRef.Kind || policyTargetRef.Name != targetRef.Name {
+			continue
+		}
+		if (sectionName == "" && policyTargetRef.SectionName != nil) ||
+			(sectionName != "" && (policyTargetRef.SectionName == nil || *policyTargetRef.SectionName != sectionName)) {
+			continue
+		}
+		if !apimeta.IsStatusConditionTrue(backendPolicy.Status.Conditions, string(gatewayv1alpha2.PolicyConditionAccepted)) {
+			continue
+		}
+		selectedPolicy = OlderBackendPolicy(selectedPolicy, backendPolicy)
+	}
+	return selectedPolicy
+}
+
+// OlderBackendPolicy returns the BackendPolicy created first, which takes precedence among the policies
+// with the same target.
+func OlderBackendPolicy(current, candidate *akov1alpha1.BackendPolicy) *akov1alpha1.BackendPolicy {
+	if current == nil {
+		return candidate
+	}
+	if candidate.CreationTimestamp.Before(&current.CreationTimestamp) ||
+		(candidate.CreationTimestamp.Equal(&current.CreationTimestamp) && candidate.Name < current.Name) {
+		return candidate
+	}
+	return current
+}
+
+// IsRequestMirrorSupported returns true if the requests can be mirrored to the endpoints of a Service. The
+// requests are cloned to the addresses of the endpoints, which are not the pool servers in the NodePort and
+// NodePortLocal modes.
+func IsRequestMirrorSupported() bool {
+	serviceType := lib.GetServiceType()
+	return serviceType != lib.NodePort && serviceType != lib.NodePortLocal
+}
+
 func CheckGatewayClassController(controllerName string) bool {
 	return controllerName == lib.AviIngressController
 }
@@ -15,21 +15,185 @@
 package nodes
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/vmware/alb-sdk/go/models"
+
+	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	akogatewayapiobjects "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 )
 
+// ProcessL4Routes translates a TCPRoute, UDPRoute or TLSRoute into a pool group, its pools and
+// an L4 policyset attached to the gateway parent VS. The L4 policyset selects the pool group
+// for every TCP, UDP or TLS listener port the route is attached to.
 func (o *AviObjectGraph) ProcessL4Routes(key string, routeModel RouteModel, parentNsName string) {
+	parentNode := o.GetAviEvhVS()
+	if len(parentNode) == 0 {
+		utils.AviLog.Warnf("key: %s, msg: parent vs of gateway %s not found for the route %s/%s", key, parentNsName, routeModel.GetNamespace(), routeModel.GetName())
+		return
+	}
+
+	// the objects are rebuilt on every change, so drop the ones built previously for this route.
+	o.DeleteL4RouteObjects(key, parentNode[0], routeModel, parentNsName)
+
+	o.BuildL4PolicySet(key, parentNode[0], routeModel, parentNsName)
+}
+
+func (o *AviObjectGraph) BuildL4PolicySet(key string, vsNode *nodes.AviEvhVsNode, routeModel RouteModel, parentNsName string) {
+
+	parentNs, _, parentName := lib.ExtractTypeNameNamespace(parentNsName)
+	routeTypeNsName := routeModel.GetType() + "/" + routeModel.GetNamespace() + "/" + routeModel.GetName()
+
+	// ListenerName/port/protocol/allowedRouteSpec
+	gatewayListeners := make(map[string][]string)
+	for _, listener := range akogatewayapiobjects.GatewayApiLister().GetGatewayToListeners(parentNsName) {
+		listenerSlice := strings.Split(listener, "/")
+		gatewayListeners[listenerSlice[0]] = listenerSlice
+	}
+
+	poolProtocol := utils.TCP
+	if routeModel.GetType() == lib.UDPRoute {
+		poolProtocol = utils.UDP
+	}
+
+	pgName := akogatewayapilib.GetPoolGroupName(parentNs, parentName, routeModel.GetNamespace(), routeModel.GetName(), routeModel.GetType())
+	var portPools []nodes.AviHostPathPortPoolPG
+	_, routeListeners := akogatewayapiobjects.GatewayApiLister().GetRouteToGatewayListener(routeTypeNsName)
+	for _, routeListener := range routeListeners {
+		if !strings.HasPrefix(routeListener, parentNsName+"/") {
+			continue
+		}
+		listenerSlice, ok := gatewayListeners[strings.TrimPrefix(routeListener, parentNsName+"/")]
+		if !ok || !akogatewayapilib.IsRouteKindSupported(listenerSlice[2], routeModel.GetType()) {
+			continue
+		}
+		port, err := strconv.Atoi(listenerSlice[1])
+		if err != nil {
+			continue
+		}
+		portPools = append(portPools, nodes.AviHostPathPortPoolPG{
+			Name:      pgName + "-" + listenerSlice[1],
+			Port:      uint32(port),
+			PoolGroup: "/api/poolgroup/?name=" + pgName,
+			Protocol:  poolProtocol,
+		})
+	}
+	if len(portPools) == 0 {
+		utils.AviLog.Warnf("key: %s, msg: no listener of gateway %s is attached to the route %s", key, parentNsName, routeTypeNsName)
+		return
+	}
+
+	PG := &nodes.AviPoolGroupNode{
+		Name:   pgName,
+		Tenant: lib.GetTenant(),
+	}
 	for _, rule := range routeModel.ParseRouteRules().Rules {
-		parentNode := o.GetAviEvhVS()
+		for _, backend := range rule.Backends {
+			poolNode := o.BuildL4Pool(key, parentNs, parentName, routeModel, backend, poolProtocol)
+			if poolNode == nil {
+				continue
+			}
+			vsNode.PoolRefs = append(vsNode.PoolRefs, poolNode)
+			poolRef := fmt.Sprintf("/api/pool?name=%s", poolNode.Name)
+			ratio := uint32(backend.Weight)
+			PG.Members = append(PG.Members, &models.PoolGroupMember{PoolRef: &poolRef, Ratio: &ratio})
+		}
+	}
+	vsNode.PoolGroupRefs = append(vsNode.PoolGroupRefs, PG)
 
-		// create L4 policyset per rule
-		o.BuildL4PolicySet(key, parentNode[0], routeModel, rule)
+	l4Policy := &nodes.AviL4PolicyNode{
+		Name:     akogatewayapilib.GetL4PolicySetName(parentNs, parentName, routeModel.GetType(), routeModel.GetNamespace(), routeModel.GetName()),
+		Tenant:   lib.GetTenant(),
+		PortPool: portPools,
+		AviMarkers: utils.AviObjectMarkers{
+			GatewayName: parentName,
+			Namespace:   parentNs,
+		},
+	}
+	vsNode.L4PolicyRefs = append(vsNode.L4PolicyRefs, l4Policy)
+
+	// the TLSRoute hostnames are served through the gateway VIP.
+	if routeModel.GetType() == lib.TLSRoute && len(vsNode.VSVIPRefs) != 0 {
+		for _, host := range routeModel.ParseRouteRules().Hosts {
+			if !akogatewayapilib.IsWildcardHostname(host) && !utils.HasElem(vsNode.VSVIPRefs[0].FQDNs, host) {
+				vsNode.VSVIPRefs[0].FQDNs = append(vsNode.VSVIPRefs[0].FQDNs, host)
+			}
+		}
+	}
+	utils.AviLog.Infof("key: %s, msg: Attached L4 policyset %s to vs %s", key, l4Policy.Name, vsNode.Name)
+}
 
-		// Logic to override the app profile per port also must be taken care here.
+func (o *AviObjectGraph) BuildL4Pool(key, parentNs, parentName string, routeModel RouteModel, backend *Backend, protocol string) *nodes.AviPoolNode {
+	svcObj, err := utils.GetInformers().ServiceInformer.Lister().Services(backend.Namespace).Get(backend.Name)
+	if err != nil {
+		utils.AviLog.Debugf("key: %s, msg: there was an error in retrieving the service %s/%s", key, backend.Namespace, backend.Name)
+		return nil
+	}
+	poolNode := &nodes.AviPoolNode{
+		Name: akogatewayapilib.GetPoolName(parentNs, parentName,
+			routeModel.GetNamespace(), routeModel.GetName(), routeModel.GetType(),
+			backend.Namespace, backend.Name, strconv.Itoa(int(backend.Port))),
+		Tenant:   lib.GetTenant(),
+		Protocol: protocol,
+		Port:     backend.Port,
+		ServiceMetadata: lib.ServiceMetadataObj{
+			NamespaceServiceName: []string{backend.Namespace + "/" + backend.Name},
+		},
+		VrfContext: lib.GetVrf(),
+	}
+	for _, port := range svcObj.Spec.Ports {
+		if port.Port == backend.Port {
+			poolNode.PortName = port.Name
+			poolNode.TargetPort = port.TargetPort
+			break
+		}
+	}
+	poolNode.NetworkPlacementSettings = lib.GetNodeNetworkMap()
+	if lib.GetServiceType() == lib.NodePort {
+		if servers := nodes.PopulateServersForNodePort(poolNode, svcObj.Namespace, svcObj.Name, false, key); servers != nil {
+			poolNode.Servers = servers
+		}
+	} else {
+		if servers := nodes.PopulateServers(poolNode, svcObj.Namespace, svcObj.Name, false, key); servers != nil {
+			poolNode.Servers = servers
+		}
 	}
+	return poolNode
 }
 
-func (o *AviObjectGraph) BuildL4PolicySet(key string, vsNode *nodes.AviEvhVsNode, routeModel RouteModel, rule *Rule) {
+// DeleteL4RouteObjects removes the L4 policyset, pool group and pools of a route from the parent VS.
+func (o *AviObjectGraph) DeleteL4RouteObjects(key string, vsNode *nodes.AviEvhVsNode, routeModel RouteModel, parentNsName string) {
+	parentNs, _, parentName := lib.ExtractTypeNameNamespace(parentNsName)
 
-	// TODO: add the l4policset code here
+	l4PolicyName := akogatewayapilib.GetL4PolicySetName(parentNs, parentName, routeModel.GetType(), routeModel.GetNamespace(), routeModel.GetName())
+	for i, l4Policy := range vsNode.L4PolicyRefs {
+		if l4Policy.Name == l4PolicyName {
+			vsNode.L4PolicyRefs = append(vsNode.L4PolicyRefs[:i], vsNode.L4PolicyRefs[i+1:]...)
+			break
+		}
+	}
+
+	pgName := akogatewayapilib.GetPoolGroupName(parentNs, parentName, routeModel.GetNamespace(), routeModel.GetName(), routeModel.GetType())
+	for i, pg := range vsNode.PoolGroupRefs {
+		if pg.Name != pgName {
+			continue
+		}
+		for _, member := range pg.Members {
+			poolName := strings.TrimPrefix(*member.PoolRef, "/api/pool?name=")
+			for j, pool := range vsNode.PoolRefs {
+				if pool.Name == poolName {
+					vsNode.PoolRefs = append(vsNode.PoolRefs[:j], vsNode.PoolRefs[j+1:]...)
+					break
+				}
+			}
+		}
+		vsNode.PoolGroupRefs = append(vsNode.PoolGroupRefs[:i], vsNode.PoolGroupRefs[i+1:]...)
+		break
+	}
+	utils.AviLog.Debugf("key: %s, msg: removed L4 objects of route %s/%s/%s from vs %s", key, routeModel.GetType(), routeModel.GetNamespace(), routeModel.GetName(), vsNode.Name)
 }
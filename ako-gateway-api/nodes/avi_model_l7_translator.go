@@ -16,11 +16,16 @@ package nodes
 
 import (
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/vmware/alb-sdk/go/models"
 	"google.golang.org/protobuf/proto"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
 	akogatewayapiobjects "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/objects"
@@ -45,8 +50,8 @@ func (o *AviObjectGraph) BuildChildVS(key string, routeModel RouteModel, parentN
 	parentNode := o.GetAviEvhVS()
 	parentNs, _, parentName := lib.ExtractTypeNameNamespace(parentNsName)
 
-	found, hosts := akogatewayapiobjects.GatewayApiLister().GetGatewayRouteToHostname(parentNs, parentName)
-	if !found {
+	hosts := getRouteHostnames(parentNs, parentName, routeModel)
+	if len(hosts) == 0 {
 		utils.AviLog.Warnf("key: %s, msg: No hosts mapped to the route %s/%s/%s", key, routeModel.GetType(), routeModel.GetNamespace(), routeModel.GetName())
 		return
 	}
@@ -75,6 +80,10 @@ func (o *AviObjectGraph) BuildChildVS(key string, routeModel RouteModel, parentN
 		Host:        hosts,
 	}
 	for _, host := range hosts {
+		// wildcard hostnames can't be added as the DNS records of the vsvip
+		if akogatewayapilib.IsWildcardHostname(host) {
+			continue
+		}
 		if !utils.HasElem(parentNode[0].VSVIPRefs[0].FQDNs, host) {
 			parentNode[0].VSVIPRefs[0].FQDNs = append(parentNode[0].VSVIPRefs[0].FQDNs, host)
 		}
@@ -84,7 +93,7 @@ func (o *AviObjectGraph) BuildChildVS(key string, routeModel RouteModel, parentN
 	o.BuildPGPool(key, parentNsName, childNode, routeModel, rule)
 
 	// create vhmatch from the match
-	o.BuildVHMatch(key, childNode, routeModel, rule)
+	o.BuildVHMatch(key, childNode, routeModel, rule, hosts)
 
 	// create the httppolicyset if the filter is present
 	o.BuildHTTPPolicySet(key, childNode, routeModel, rule)
@@ -98,10 +107,34 @@ func (o *AviObjectGraph) BuildChildVS(key string, routeModel RouteModel, parentN
 	utils.AviLog.Infof("key: %s, msg: processing of child vs %s attached to parent vs %s completed", key, childNode.Name, childNode.VHParentName)
 }
 
+// getRouteHostnames returns the intersection of the route hostnames with the hostnames of
+// the listeners of the parent gateway the route is attached to.
+func getRouteHostnames(parentNs, parentName string, routeModel RouteModel) []string {
+	var hosts []string
+	routeTypeNsName := routeModel.GetType() + "/" + routeModel.GetNamespace() + "/" + routeModel.GetName()
+	_, gwListeners := akogatewayapiobjects.GatewayApiLister().GetRouteToGatewayListener(routeTypeNsName)
+	for _, gwListener := range gwListeners {
+		//gatewayNs/gatewayName/listenerName
+		gwListenerSlice := strings.Split(gwListener, "/")
+		if len(gwListenerSlice) != 3 || gwListenerSlice[0] != parentNs || gwListenerSlice[1] != parentName {
+			continue
+		}
+		gwNsName := parentNs + "/" + parentName
+		listenerHostname := akogatewayapiobjects.GatewayApiLister().GetGatewayListenerToHostname(gwNsName, gwListenerSlice[2])
+		for _, routeHostname := range routeModel.ParseRouteRules().Hosts {
+			hostname, matched := akogatewayapilib.GetHostnameIntersection(listenerHostname, routeHostname)
+			if matched && hostname != "" && !utils.HasElem(hosts, hostname) {
+				hosts = append(hosts, hostname)
+			}
+		}
+	}
+	return hosts
+}
+
 func (o *AviObjectGraph) BuildPGPool(key, parentNsName string, childVsNode *nodes.AviEvhVsNode, routeModel RouteModel, rule *Rule) {
 
 	// create the PG from backends
-	routeTypeNsName := lib.HTTPRoute + "/" + routeModel.GetNamespace() + "/" + routeModel.GetName()
+	routeTypeNsName := routeModel.GetType() + "/" + routeModel.GetNamespace() + "/" + routeModel.GetName()
 	parentNs, _, parentName := lib.ExtractTypeNameNamespace(parentNsName)
 	_, listeners := akogatewayapiobjects.GatewayApiLister().GetRouteToGatewayListener(routeTypeNsName)
 	//ListenerName/port/protocol/allowedRouteSpec
@@ -135,19 +168,15 @@ func (o *AviObjectGraph) BuildPGPool(key, parentNsName string, childVsNode *node
 			},
 			VrfContext: lib.GetVrf(),
 		}
-		poolNode.NetworkPlacementSettings = lib.GetNodeNetworkMap()
-		serviceType := lib.GetServiceType()
-		if serviceType == lib.NodePort {
-			servers := nodes.PopulateServersForNodePort(poolNode, svcObj.ObjectMeta.Namespace, svcObj.ObjectMeta.Name, false, key)
-			if servers != nil {
-				poolNode.Servers = servers
-			}
-		} else {
-			servers := nodes.PopulateServers(poolNode, svcObj.ObjectMeta.Namespace, svcObj.ObjectMeta.Name, false, key)
-			if servers != nil {
-				poolNode.Servers = servers
-			}
+		// gRPC requires HTTP/2 to the backends
+		if routeModel.GetType() == lib.GRPCRoute {
+			poolNode.EnableHttp2 = true
 		}
+		portName := getServicePortName(svcObj, backend.Port)
+		buildPoolBackendTLS(key, poolNode, svcObj, portName)
+		buildPoolBackendPolicy(key, poolNode, parentNs, parentName, routeModel, rule, svcObj, portName)
+		poolNode.NetworkPlacementSettings = lib.GetNodeNetworkMap()
+		populatePoolServers(key, poolNode, svcObj)
 		if childVsNode.CheckPoolNChecksum(poolNode.Name, poolNode.GetCheckSum()) {
 			// Replace the poolNode.
 			childVsNode.ReplaceEvhPoolInEVHNode(poolNode, key)
@@ -160,48 +189,117 @@ func (o *AviObjectGraph) BuildPGPool(key, parentNsName string, childVsNode *node
 	childVsNode.DefaultPoolGroup = PG.Name
 }
 
-func (o *AviObjectGraph) BuildVHMatch(key string, vsNode *nodes.AviEvhVsNode, routeModel RouteModel, rule *Rule) {
+func populatePoolServers(key string, poolNode *nodes.AviPoolNode, svcObj *corev1.Service) {
+	serviceType := lib.GetServiceType()
+	if serviceType == lib.NodePort {
+		servers := nodes.PopulateServersForNodePort(poolNode, svcObj.ObjectMeta.Namespace, svcObj.ObjectMeta.Name, false, key)
+		if servers != nil {
+			poolNode.Servers = servers
+		}
+	} else {
+		servers := nodes.PopulateServers(poolNode, svcObj.ObjectMeta.Namespace, svcObj.ObjectMeta.Name, false, key)
+		if servers != nil {
+			poolNode.Servers = servers
+		}
+	}
+}
+
+// getServicePortName returns the name of the port of the Service, which the policies use to target the port.
+func getServicePortName(svcObj *corev1.Service, port int32) string {
+	for _, svcPort := range svcObj.Spec.Ports {
+		if svcPort.Port == port {
+			return svcPort.Name
+		}
+	}
+	return ""
+}
+
+// buildPoolBackendTLS enables TLS from the pool to the backends when a BackendTLSPolicy targets the backend
+// Service. The CA certificates referred by the policy are added as the PKI profile of the pool to validate
+// the backends, and the hostname of the policy is sent as the SNI.
+func buildPoolBackendTLS(key string, poolNode *nodes.AviPoolNode, svcObj *corev1.Service, portName string) {
+	backendTLSPolicy := akogatewayapilib.GetBackendTLSPolicy(svcObj.Namespace, svcObj.Name, portName)
+	if backendTLSPolicy == nil {
+		return
+	}
+
+	tlsConfig := backendTLSPolicy.Spec.TLS
+	caCerts, err := akogatewayapilib.GetBackendTLSPolicyCACerts(backendTLSPolicy)
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: CA certificates of BackendTLSPolicy %s/%s are not resolved, err: %v",
+			key, backendTLSPolicy.Namespace, backendTLSPolicy.Name, err)
+		return
+	}
+	if len(caCerts) == 0 && tlsConfig.WellKnownCACerts == nil {
+		utils.AviLog.Warnf("key: %s, msg: BackendTLSPolicy %s/%s has no CA certificates", key, backendTLSPolicy.Namespace, backendTLSPolicy.Name)
+		return
+	}
+
+	poolNode.SniEnabled = true
+	poolNode.ServerName = string(tlsConfig.Hostname)
+	poolNode.SslProfileRef = proto.String(fmt.Sprintf("/api/sslprofile?name=%s", lib.DefaultPoolSSLProfile))
+	if len(caCerts) > 0 {
+		poolNode.PkiProfile = &nodes.AviPkiProfileNode{
+			Name:   lib.GetPoolPKIProfileName(poolNode.Name),
+			Tenant: lib.GetTenant(),
+			CACert: strings.Join(caCerts, "\n"),
+		}
+	}
+	utils.AviLog.Infof("key: %s, msg: applied BackendTLSPolicy %s/%s to pool %s", key, backendTLSPolicy.Namespace, backendTLSPolicy.Name, poolNode.Name)
+}
+
+// buildPoolBackendPolicy applies the health monitors, the load balancer policy and the persistence profile
+// of the BackendPolicy, which targets the rule, the route, the backend Service or the parent Gateway, to the pool.
+func buildPoolBackendPolicy(key string, poolNode *nodes.AviPoolNode, parentNs, parentName string, routeModel RouteModel, rule *Rule, svcObj *corev1.Service, portName string) {
+	backendPolicy := akogatewayapilib.GetBackendPolicy(parentNs, parentName,
+		routeModel.GetType(), routeModel.GetNamespace(), routeModel.GetName(), rule.Index,
+		svcObj.Namespace, svcObj.Name, portName)
+	if backendPolicy == nil {
+		return
+	}
+
+	for _, hm := range backendPolicy.Spec.HealthMonitors {
+		hmRef := fmt.Sprintf("/api/healthmonitor?name=%s", hm)
+		if !utils.HasElem(poolNode.HealthMonitorRefs, hmRef) {
+			poolNode.HealthMonitorRefs = append(poolNode.HealthMonitorRefs, hmRef)
+		}
+	}
+	if backendPolicy.Spec.ApplicationPersistence != "" {
+		poolNode.ApplicationPersistenceProfileRef = proto.String(fmt.Sprintf("/api/applicationpersistenceprofile?name=%s", backendPolicy.Spec.ApplicationPersistence))
+	}
+
+	lbPolicy := backendPolicy.Spec.LoadBalancerPolicy
+	if lbPolicy.Algorithm != "" {
+		poolNode.LbAlgorithm = proto.String(lbPolicy.Algorithm)
+	}
+	if lbPolicy.Algorithm == lib.LB_ALGORITHM_CONSISTENT_HASH && lbPolicy.Hash != "" {
+		poolNode.LbAlgorithmHash = proto.String(lbPolicy.Hash)
+		if lbPolicy.Hash == lib.LB_ALGORITHM_CONSISTENT_HASH_CUSTOM_HEADER {
+			if lbPolicy.HostHeader != "" {
+				poolNode.LbAlgorithmConsistentHashHdr = proto.String(lbPolicy.HostHeader)
+			} else {
+				utils.AviLog.Warnf("key: %s, HostHeader is not provided for LB_ALGORITHM_CONSISTENT_HASH_CUSTOM_HEADER", key)
+			}
+		}
+	}
+	utils.AviLog.Infof("key: %s, msg: applied BackendPolicy %s/%s to pool %s", key, backendPolicy.Namespace, backendPolicy.Name, poolNode.Name)
+}
+
+func (o *AviObjectGraph) BuildVHMatch(key string, vsNode *nodes.AviEvhVsNode, routeModel RouteModel, rule *Rule, hosts []string) {
 	var vhMatches []*models.VHMatch
 
-	for _, host := range routeModel.ParseRouteRules().Hosts {
+	for _, host := range hosts {
 		hostname := host
 		vhMatch := &models.VHMatch{
 			Host: &hostname,
 		}
 
-		for i, match := range rule.Matches {
+		for i, match := range getMatchesByPrecedence(rule.Matches) {
 			ruleName := fmt.Sprintf("rule-%d", i)
 			rule := &models.VHMatchRule{
 				Name:    &ruleName,
-				Matches: &models.MatchTarget{},
-			}
-
-			// path match
-			if match.PathMatch != nil {
-				rule.Matches.Path = &models.PathMatch{
-					MatchCase: proto.String("SENSITIVE"),
-					MatchStr:  []string{match.PathMatch.Path},
-				}
-				if match.PathMatch.Type == "Exact" {
-					rule.Matches.Path.MatchCriteria = proto.String("EQUALS")
-				} else if match.PathMatch.Type == "PathPrefix" {
-					rule.Matches.Path.MatchCriteria = proto.String("BEGINS_WITH")
-				}
-			}
-
-			// header match
-			rule.Matches.Hdrs = make([]*models.HdrMatch, 0, len(match.HeaderMatch))
-			for _, headerMatch := range match.HeaderMatch {
-				headerName := headerMatch.Name
-				hdrMatch := &models.HdrMatch{
-					MatchCase:     proto.String("SENSITIVE"),
-					MatchCriteria: proto.String("HDR_EQUALS"),
-					Hdr:           &headerName,
-					Value:         []string{headerMatch.Value},
-				}
-				rule.Matches.Hdrs = append(rule.Matches.Hdrs, hdrMatch)
+				Matches: buildMatchTarget(match),
 			}
-
 			vhMatch.Rules = append(vhMatch.Rules, rule)
 		}
 		vhMatches = append(vhMatches, vhMatch)
@@ -211,8 +309,75 @@ func (o *AviObjectGraph) BuildVHMatch(key string, vsNode *nodes.AviEvhVsNode, ro
 	utils.AviLog.Infof("key: %s, msg: Attached match criteria to vs %s", key, vsNode.Name)
 }
 
+func buildMatchTarget(match *Match) *models.MatchTarget {
+	matchTarget := &models.MatchTarget{}
+
+	// path match
+	if match.PathMatch != nil {
+		matchTarget.Path = &models.PathMatch{
+			MatchCase: proto.String("SENSITIVE"),
+			MatchStr:  []string{match.PathMatch.Path},
+		}
+		if match.PathMatch.Type == "Exact" {
+			matchTarget.Path.MatchCriteria = proto.String("EQUALS")
+		} else if match.PathMatch.Type == "PathPrefix" {
+			matchTarget.Path.MatchCriteria = proto.String("BEGINS_WITH")
+		} else if match.PathMatch.Type == "RegularExpression" {
+			matchTarget.Path.MatchCriteria = proto.String("REGEX_MATCH")
+		}
+	}
+
+	// header match
+	matchTarget.Hdrs = make([]*models.HdrMatch, 0, len(match.HeaderMatch))
+	for _, headerMatch := range match.HeaderMatch {
+		headerName := headerMatch.Name
+		hdrMatch := &models.HdrMatch{
+			MatchCase:     proto.String("SENSITIVE"),
+			MatchCriteria: proto.String("HDR_EQUALS"),
+			Hdr:           &headerName,
+			Value:         []string{headerMatch.Value},
+		}
+		matchTarget.Hdrs = append(matchTarget.Hdrs, hdrMatch)
+	}
+
+	// query param match
+	if len(match.QueryParamMatch) != 0 {
+		matchTarget.Query = &models.QueryMatch{
+			MatchCase:     proto.String("SENSITIVE"),
+			MatchCriteria: proto.String("QUERY_MATCH_REGEX_MATCH"),
+			MatchStr:      []string{buildQueryParamRegex(match.QueryParamMatch)},
+		}
+	}
+
+	// method match
+	if match.Method != "" {
+		matchTarget.Method = &models.MethodMatch{
+			MatchCriteria: proto.String("IS_IN"),
+			Methods:       []string{"HTTP_METHOD_" + match.Method},
+		}
+	}
+	return matchTarget
+}
+
+// buildQueryParamRegex builds a regex for the query string of the request. The query is
+// matched as a whole, hence a lookahead is added for each of the query params to be present
+// with the expected value at any position in the query.
+func buildQueryParamRegex(queryParamMatches []*QueryParamMatch) string {
+	var regex strings.Builder
+	regex.WriteString("^")
+	for _, queryParamMatch := range queryParamMatches {
+		value := regexp.QuoteMeta(queryParamMatch.Value)
+		if queryParamMatch.Type == string(gatewayv1.QueryParamMatchRegularExpression) {
+			value = "(" + queryParamMatch.Value + ")"
+		}
+		regex.WriteString(fmt.Sprintf("(?=(.*&)?%s=%s(&|$))", regexp.QuoteMeta(queryParamMatch.Name), value))
+	}
+	return regex.String()
+}
+
 func (o *AviObjectGraph) BuildHTTPPolicySet(key string, vsNode *nodes.AviEvhVsNode, routeModel RouteModel, rule *Rule) {
 
+	vsNode.CloneProfileRefs = nil
 	if len(rule.Filters) == 0 {
 		vsNode.HttpPolicyRefs = nil
 		return
@@ -221,42 +386,189 @@ func (o *AviObjectGraph) BuildHTTPPolicySet(key string, vsNode *nodes.AviEvhVsNo
 	policy := &nodes.AviHttpPolicySetNode{Name: vsNode.Name, Tenant: lib.GetTenant()}
 	vsNode.HttpPolicyRefs = []*nodes.AviHttpPolicySetNode{policy}
 
-	o.BuildHTTPPolicySetHTTPRequestRedirectRules(key, vsNode, routeModel, rule.Filters)
-	if len(vsNode.HttpPolicyRefs[0].RequestRules) == 1 {
+	o.BuildHTTPPolicySetHTTPRequestRedirectRules(key, vsNode, routeModel, rule)
+	if len(vsNode.HttpPolicyRefs[0].RequestRules) != 0 {
 		// When the RedirectAction is specified the Request and Response Modify Header Action
 		// won't have any effect, hence returning.
 		utils.AviLog.Infof("key: %s, msg: Attached HTTP redirect policy to vs %s", key, vsNode.Name)
 		return
 	}
-	o.BuildHTTPPolicySetHTTPRequestRules(key, vsNode, routeModel, rule.Filters)
+	o.BuildHTTPPolicySetHTTPRequestRules(key, vsNode, routeModel, rule)
 	o.BuildHTTPPolicySetHTTPResponseRules(key, vsNode, routeModel, rule.Filters)
+	if len(policy.RequestRules) == 0 && len(policy.ResponseRules) == 0 {
+		// none of the filters is translated into HTTP policies, e.g. only a RequestMirror filter is present
+		vsNode.HttpPolicyRefs = nil
+		return
+	}
 	utils.AviLog.Infof("key: %s, msg: Attached HTTP policies to vs %s", key, vsNode.Name)
 }
 
-func (o *AviObjectGraph) BuildHTTPPolicySetHTTPRequestRules(key string, vsNode *nodes.AviEvhVsNode, routeModel RouteModel, filters []*Filter) {
-	requestRule := &models.HTTPRequestRule{Name: &vsNode.Name, Enable: proto.Bool(true), Index: proto.Int32(1)}
-	for _, filter := range filters {
+func (o *AviObjectGraph) BuildHTTPPolicySetHTTPRequestRules(key string, vsNode *nodes.AviEvhVsNode, routeModel RouteModel, rule *Rule) {
+	var hdrActions []*models.HTTPHdrAction
+	var rewriteFilter *RewriteFilter
+	var mirrorFilter *MirrorFilter
+	for _, filter := range rule.Filters {
 		if filter.RequestFilter != nil {
 			for i := range filter.RequestFilter.Add {
 				action := o.BuildHTTPPolicySetHTTPRuleHdrAction(key, "HTTP_ADD_HDR", filter.RequestFilter.Add[i])
-				requestRule.HdrAction = append(requestRule.HdrAction, action)
+				hdrActions = append(hdrActions, action)
 			}
 
 			for i := range filter.RequestFilter.Set {
 				action := o.BuildHTTPPolicySetHTTPRuleHdrAction(key, "HTTP_REPLACE_HDR", filter.RequestFilter.Set[i])
-				requestRule.HdrAction = append(requestRule.HdrAction, action)
+				hdrActions = append(hdrActions, action)
 			}
 
 			for i := range filter.RequestFilter.Remove {
 				action := o.BuildHTTPPolicySetHTTPRuleHdrAction(key, "HTTP_REMOVE_HDR", &Header{Name: filter.RequestFilter.Remove[i]})
-				requestRule.HdrAction = append(requestRule.HdrAction, action)
+				hdrActions = append(hdrActions, action)
 			}
 		}
+		// considering only the first URLRewrite filter
+		if filter.RewriteFilter != nil && rewriteFilter == nil {
+			rewriteFilter = filter.RewriteFilter
+		}
+		// considering only the first RequestMirror filter
+		if filter.MirrorFilter != nil && mirrorFilter == nil {
+			mirrorFilter = filter.MirrorFilter
+		}
+	}
+	if mirrorFilter != nil {
+		o.BuildHTTPRequestMirror(key, vsNode, mirrorFilter)
 	}
-	if len(requestRule.HdrAction) != 0 {
-		vsNode.HttpPolicyRefs[0].RequestRules = []*models.HTTPRequestRule{requestRule}
-		utils.AviLog.Debugf("key: %s, msg: Attached HTTP request policies %s to vs %s", key, utils.Stringify(vsNode.HttpPolicyRefs[0].RequestRules), vsNode.Name)
+	if len(hdrActions) == 0 && rewriteFilter == nil {
+		return
+	}
+
+	var pathModifier *PathModifier
+	if rewriteFilter != nil {
+		pathModifier = rewriteFilter.Path
 	}
+	var requestRules []*models.HTTPRequestRule
+	for i, match := range getPrefixReplacedMatches(rule, pathModifier) {
+		requestRule := &models.HTTPRequestRule{
+			Name:      getHTTPRequestRuleName(vsNode.Name, match, i),
+			Enable:    proto.Bool(true),
+			Index:     proto.Int32(int32(i + 1)),
+			HdrAction: hdrActions,
+		}
+		if match != nil {
+			requestRule.Match = buildMatchTarget(match)
+		}
+		if rewriteFilter != nil {
+			requestRule.RewriteURLAction = &models.HTTPRewriteURLAction{
+				Path: buildPathURIParam(rewriteFilter.Path, match),
+			}
+			if rewriteFilter.Host != "" {
+				requestRule.RewriteURLAction.HostHdr = buildHostURIParam(rewriteFilter.Host)
+			}
+		}
+		requestRules = append(requestRules, requestRule)
+	}
+	vsNode.HttpPolicyRefs[0].RequestRules = requestRules
+	utils.AviLog.Debugf("key: %s, msg: Attached HTTP request policies %s to vs %s", key, utils.Stringify(vsNode.HttpPolicyRefs[0].RequestRules), vsNode.Name)
+}
+
+// BuildHTTPRequestMirror clones the requests of the child VS to the endpoints of the mirror backend port
+// through a traffic clone profile. The clone servers are addresses only, hence the mirror is skipped when
+// the pool servers are the nodes, as in the NodePort and NodePortLocal modes.
+func (o *AviObjectGraph) BuildHTTPRequestMirror(key string, vsNode *nodes.AviEvhVsNode, mirrorFilter *MirrorFilter) {
+	backend := mirrorFilter.Backend
+	if !akogatewayapilib.IsRequestMirrorSupported() {
+		utils.AviLog.Warnf("key: %s, msg: RequestMirror filter is not supported in %s mode, skipping the mirror to the Service %s/%s",
+			key, lib.GetServiceType(), backend.Namespace, backend.Name)
+		return
+	}
+	svcObj, err := utils.GetInformers().ServiceInformer.Lister().Services(backend.Namespace).Get(backend.Name)
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to get the mirror Service %s/%s, err: %v", key, backend.Namespace, backend.Name, err)
+		return
+	}
+	cloneServers := getMirrorServers(key, svcObj, backend.Port)
+	if len(cloneServers) == 0 {
+		utils.AviLog.Warnf("key: %s, msg: no endpoints found for the port %d of the mirror Service %s/%s", key, backend.Port, backend.Namespace, backend.Name)
+		return
+	}
+	vsNode.CloneProfileRefs = []*nodes.AviTrafficCloneProfileNode{{
+		Name:         lib.GetTrafficCloneProfileName(vsNode.Name),
+		Tenant:       lib.GetTenant(),
+		CloneServers: cloneServers,
+		AviMarkers:   vsNode.AviMarkers,
+	}}
+	utils.AviLog.Infof("key: %s, msg: mirroring the requests of vs %s to the Service %s/%s", key, vsNode.Name, backend.Namespace, backend.Name)
+}
+
+// getMirrorServers returns the addresses of the ready endpoints of the Service, which serve the given port
+// of the Service. The endpoints of a port are found by the name of the port, as done by kubernetes.
+func getMirrorServers(key string, svcObj *corev1.Service, port int32) []string {
+	portName, found := "", false
+	for _, svcPort := range svcObj.Spec.Ports {
+		if svcPort.Port == port {
+			portName, found = svcPort.Name, true
+			break
+		}
+	}
+	if !found {
+		utils.AviLog.Warnf("key: %s, msg: port %d not found in the mirror Service %s/%s", key, port, svcObj.Namespace, svcObj.Name)
+		return nil
+	}
+
+	var servers []string
+	if utils.GetInformers().EpSlicesInformer != nil {
+		selector := labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: svcObj.Name})
+		epSlices, err := utils.GetInformers().EpSlicesInformer.Lister().EndpointSlices(svcObj.Namespace).List(selector)
+		if err != nil {
+			utils.AviLog.Warnf("key: %s, msg: error while retrieving endpointslices: %s", key, err)
+			return nil
+		}
+		for _, eps := range epSlices {
+			portFound := false
+			for _, epp := range eps.Ports {
+				if (epp.Name != nil && *epp.Name == portName) || (epp.Name == nil && portName == "") {
+					portFound = true
+					break
+				}
+			}
+			if !portFound {
+				continue
+			}
+			for _, endpoint := range eps.Endpoints {
+				if endpoint.Conditions.Ready != nil && !*endpoint.Conditions.Ready {
+					continue
+				}
+				for _, addr := range endpoint.Addresses {
+					if !utils.HasElem(servers, addr) {
+						servers = append(servers, addr)
+					}
+				}
+			}
+		}
+		return servers
+	}
+
+	epObj, err := utils.GetInformers().EpInformer.Lister().Endpoints(svcObj.Namespace).Get(svcObj.Name)
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: error while retrieving endpoints: %s", key, err)
+		return nil
+	}
+	for _, subset := range epObj.Subsets {
+		portFound := false
+		for _, epp := range subset.Ports {
+			if epp.Name == portName {
+				portFound = true
+				break
+			}
+		}
+		if !portFound {
+			continue
+		}
+		for _, addr := range subset.Addresses {
+			if !utils.HasElem(servers, addr.IP) {
+				servers = append(servers, addr.IP)
+			}
+		}
+	}
+	return servers
 }
 
 func (o *AviObjectGraph) BuildHTTPPolicySetHTTPResponseRules(key string, vsNode *nodes.AviEvhVsNode, routeModel RouteModel, filters []*Filter) {
@@ -298,30 +610,184 @@ func (o *AviObjectGraph) BuildHTTPPolicySetHTTPRuleHdrAction(key string, action
 	return hdrAction
 }
 
-func (o *AviObjectGraph) BuildHTTPPolicySetHTTPRequestRedirectRules(key string, vsNode *nodes.AviEvhVsNode, routeModel RouteModel, filters []*Filter) {
-	redirectAction := &models.HTTPRedirectAction{}
-	for _, filter := range filters {
+func (o *AviObjectGraph) BuildHTTPPolicySetHTTPRequestRedirectRules(key string, vsNode *nodes.AviEvhVsNode, routeModel RouteModel, rule *Rule) {
+	var redirectFilter *RedirectFilter
+	for _, filter := range rule.Filters {
 		// considering only the first RedirectFilter
 		if filter.RedirectFilter != nil {
-			uriParamToken := &models.URIParamToken{
-				StrValue: &filter.RedirectFilter.Host,
-				Type:     proto.String("URI_TOKEN_TYPE_STRING"),
-			}
-			redirectAction.Host = &models.URIParam{
-				Tokens: []*models.URIParamToken{uriParamToken},
-				Type:   proto.String("URI_PARAM_TYPE_TOKENIZED"),
-			}
-			redirectAction.Protocol = proto.String("HTTP")
-			statusCode := "HTTP_REDIRECT_STATUS_CODE_302"
-			switch filter.RedirectFilter.StatusCode {
-			case 301, 302, 307:
-				statusCode = fmt.Sprintf("HTTP_REDIRECT_STATUS_CODE_%d", filter.RedirectFilter.StatusCode)
-			}
-			redirectAction.StatusCode = &statusCode
-			requestRule := &models.HTTPRequestRule{Name: &vsNode.Name, Enable: proto.Bool(true), Index: proto.Int32(1), RedirectAction: redirectAction}
-			vsNode.HttpPolicyRefs[0].RequestRules = []*models.HTTPRequestRule{requestRule}
-			utils.AviLog.Debugf("key: %s, msg: Attached HTTP request redirect policies %s to vs %s", key, utils.Stringify(vsNode.HttpPolicyRefs[0].RequestRules), vsNode.Name)
+			redirectFilter = filter.RedirectFilter
 			break
 		}
 	}
+	if redirectFilter == nil {
+		return
+	}
+
+	// scheme and port of the listener are retained when they are not specified in the filter
+	listenerProtocol, listenerPort := getRouteListenerProtocolPort(routeModel)
+	protocol := strings.ToUpper(redirectFilter.Scheme)
+	if protocol == "" {
+		protocol = listenerProtocol
+	}
+	var port uint32
+	if redirectFilter.Port != 0 {
+		port = uint32(redirectFilter.Port)
+	} else if redirectFilter.Scheme == "" {
+		port = listenerPort
+	}
+	statusCode := "HTTP_REDIRECT_STATUS_CODE_302"
+	switch redirectFilter.StatusCode {
+	case 301, 302, 307:
+		statusCode = fmt.Sprintf("HTTP_REDIRECT_STATUS_CODE_%d", redirectFilter.StatusCode)
+	}
+
+	var requestRules []*models.HTTPRequestRule
+	for i, match := range getPrefixReplacedMatches(rule, redirectFilter.Path) {
+		redirectAction := &models.HTTPRedirectAction{
+			Protocol:   proto.String(protocol),
+			StatusCode: proto.String(statusCode),
+			KeepQuery:  proto.Bool(true),
+			Path:       buildPathURIParam(redirectFilter.Path, match),
+		}
+		if redirectFilter.Host != "" {
+			redirectAction.Host = buildHostURIParam(redirectFilter.Host)
+		}
+		if port != 0 {
+			redirectAction.Port = port
+		}
+		requestRule := &models.HTTPRequestRule{
+			Name:           getHTTPRequestRuleName(vsNode.Name, match, i),
+			Enable:         proto.Bool(true),
+			Index:          proto.Int32(int32(i + 1)),
+			RedirectAction: redirectAction,
+		}
+		if match != nil {
+			requestRule.Match = buildMatchTarget(match)
+		}
+		requestRules = append(requestRules, requestRule)
+	}
+	vsNode.HttpPolicyRefs[0].RequestRules = requestRules
+	utils.AviLog.Debugf("key: %s, msg: Attached HTTP request redirect policies %s to vs %s", key, utils.Stringify(vsNode.HttpPolicyRefs[0].RequestRules), vsNode.Name)
+}
+
+// getPrefixReplacedMatches returns the rule matches, in the order of precedence, when the path
+// modifier replaces the prefix match. The ReplacePrefixMatch modifier depends on the prefix which
+// matched the request, hence a request rule is required per match. A single nil match is
+// returned for the rest of the cases.
+func getPrefixReplacedMatches(rule *Rule, pathModifier *PathModifier) []*Match {
+	if pathModifier == nil || pathModifier.Type != string(gatewayv1.PrefixMatchHTTPPathModifier) || len(rule.Matches) == 0 {
+		return []*Match{nil}
+	}
+	return getMatchesByPrecedence(rule.Matches)
+}
+
+func getHTTPRequestRuleName(vsName string, match *Match, index int) *string {
+	if match == nil {
+		return proto.String(vsName)
+	}
+	return proto.String(fmt.Sprintf("%s-%d", vsName, index))
+}
+
+func buildHostURIParam(host string) *models.URIParam {
+	return &models.URIParam{
+		Tokens: []*models.URIParamToken{{
+			StrValue: proto.String(host),
+			Type:     proto.String("URI_TOKEN_TYPE_STRING"),
+		}},
+		Type: proto.String("URI_PARAM_TYPE_TOKENIZED"),
+	}
+}
+
+// buildPathURIParam builds the tokenized path for the path modifier. The tokens are joined with
+// "/" by the controller, hence the leading and trailing "/" are not part of the string tokens.
+// For ReplacePrefixMatch, the path segments of the request after the matched prefix are retained.
+func buildPathURIParam(pathModifier *PathModifier, match *Match) *models.URIParam {
+	if pathModifier == nil {
+		return nil
+	}
+	uriParam := &models.URIParam{
+		Type: proto.String("URI_PARAM_TYPE_TOKENIZED"),
+	}
+	value := strings.Trim(pathModifier.Value, "/")
+	if value != "" || pathModifier.Type == string(gatewayv1.FullPathHTTPPathModifier) {
+		uriParam.Tokens = append(uriParam.Tokens, &models.URIParamToken{
+			StrValue: proto.String(value),
+			Type:     proto.String("URI_TOKEN_TYPE_STRING"),
+		})
+	}
+	if pathModifier.Type == string(gatewayv1.PrefixMatchHTTPPathModifier) {
+		var prefixSegments uint32
+		if trimmedPrefix := strings.Trim(match.getPathMatch().Path, "/"); trimmedPrefix != "" {
+			prefixSegments = uint32(len(strings.Split(trimmedPrefix, "/")))
+		}
+		uriParam.Tokens = append(uriParam.Tokens, &models.URIParamToken{
+			StartIndex: prefixSegments,
+			EndIndex:   65535,
+			Type:       proto.String("URI_TOKEN_TYPE_PATH"),
+		})
+	}
+	return uriParam
+}
+
+// getRouteListenerProtocolPort returns the protocol and port of the listener the route is attached to.
+func getRouteListenerProtocolPort(routeModel RouteModel) (string, uint32) {
+	routeTypeNsName := routeModel.GetType() + "/" + routeModel.GetNamespace() + "/" + routeModel.GetName()
+	found, gwListeners := akogatewayapiobjects.GatewayApiLister().GetRouteToGatewayListener(routeTypeNsName)
+	if !found || len(gwListeners) == 0 {
+		return "HTTP", 0
+	}
+	//gatewayNs/gatewayName/listenerName
+	gwListenerSlice := strings.Split(gwListeners[0], "/")
+	if len(gwListenerSlice) != 3 {
+		return "HTTP", 0
+	}
+	gwNsName := gwListenerSlice[0] + "/" + gwListenerSlice[1]
+	for _, listener := range akogatewayapiobjects.GatewayApiLister().GetGatewayToListeners(gwNsName) {
+		//listenerName/port/protocol/allowedRouteSpec
+		listenerSlice := strings.Split(listener, "/")
+		if len(listenerSlice) < 3 || listenerSlice[0] != gwListenerSlice[2] {
+			continue
+		}
+		port, _ := strconv.Atoi(listenerSlice[1])
+		return strings.ToUpper(listenerSlice[2]), uint32(port)
+	}
+	return "HTTP", 0
+}
+
+// UpdateHTTP2OnListenerPorts enables HTTP/2 on the ports of the parent vs whose listeners have
+// GRPCRoutes attached, and disables it on the rest of the ports.
+func (o *AviObjectGraph) UpdateHTTP2OnListenerPorts(key, gwNsName string) {
+	parentNode := o.GetAviEvhVS()
+	if len(parentNode) == 0 {
+		return
+	}
+	grpcListeners := make(map[string]struct{})
+	_, routeTypeNsNameList := akogatewayapiobjects.GatewayApiLister().GetGatewayToRoute(gwNsName)
+	for _, routeTypeNsName := range routeTypeNsNameList {
+		if !strings.HasPrefix(routeTypeNsName, lib.GRPCRoute+"/") {
+			continue
+		}
+		_, gwListeners := akogatewayapiobjects.GatewayApiLister().GetRouteToGatewayListener(routeTypeNsName)
+		for _, gwListener := range gwListeners {
+			//gatewayNs/gatewayName/listenerName
+			if strings.HasPrefix(gwListener, gwNsName+"/") {
+				grpcListeners[strings.TrimPrefix(gwListener, gwNsName+"/")] = struct{}{}
+			}
+		}
+	}
+	http2Ports := make(map[int32]struct{})
+	for _, listener := range akogatewayapiobjects.GatewayApiLister().GetGatewayToListeners(gwNsName) {
+		//listenerName/port/protocol/allowedRouteSpec
+		listenerSlice := strings.Split(listener, "/")
+		if _, ok := grpcListeners[listenerSlice[0]]; !ok {
+			continue
+		}
+		port, _ := strconv.Atoi(listenerSlice[1])
+		http2Ports[int32(port)] = struct{}{}
+	}
+	for i := range parentNode[0].PortProto {
+		_, enableHTTP2 := http2Ports[parentNode[0].PortProto[i].Port]
+		parentNode[0].PortProto[i].EnableHTTP2 = enableHTTP2
+	}
+	utils.AviLog.Debugf("key: %s, msg: HTTP/2 enabled on %d ports of vs %s", key, len(http2Ports), parentNode[0].Name)
 }
@@ -48,6 +48,19 @@ func DequeueIngestion(key string, fullsync bool) {
 		handleGateway(namespace, name, fullsync, key)
 	}
 
+	if objType == lib.ReferenceGrant {
+		// the certificates of the Gateways with a model are re-evaluated, as they may no longer be permitted,
+		// the valid Gateways are enqueued by the controller and their routes are processed below
+		referenceGrantGwNsNameList, _, _ := GetReferenceGrantDependents(namespace, name, key)
+		for _, gwNsName := range referenceGrantGwNsNameList {
+			gwNamespace, _, gwName := lib.ExtractTypeNameNamespace(gwNsName)
+			modelName := lib.GetModelName(lib.GetTenant(), akogatewayapilib.GetGatewayParentName(gwNamespace, gwName))
+			if found, _ := objects.SharedAviGraphLister().Get(modelName); found {
+				handleGateway(gwNamespace, gwName, fullsync, key)
+			}
+		}
+	}
+
 	routeTypeNsNameList, found := schema.GetRoutes(namespace, name, key)
 	if !found {
 		utils.AviLog.Errorf("key: %s, msg: got error while getting object", key, objType)
@@ -86,14 +99,18 @@ func DequeueIngestion(key string, fullsync bool) {
 			childVSes := make(map[string]struct{}, 0)
 
 			switch objType {
-			case lib.HTTPRoute:
+			case lib.HTTPRoute, lib.GRPCRoute:
 				model.ProcessL7Routes(key, routeModel, gatewayNsName, childVSes)
+			case lib.TCPRoute, lib.UDPRoute, lib.TLSRoute:
+				model.ProcessL4Routes(key, routeModel, gatewayNsName)
+				continue
 			default:
 				utils.AviLog.Warnf("key: %s, msg: route of type %s not supported", key, objType)
 				continue
 			}
 			model.DeleteStaleChildVSes(key, routeModel, childVSes, fullsync)
 		}
+		model.UpdateHTTP2OnListenerPorts(key, gatewayNsName)
 
 		// Only add this node to the list of models if the checksum has changed.
 		modelChanged := saveAviModel(modelName, model.AviObjectGraph, key)
@@ -104,7 +121,7 @@ func DequeueIngestion(key string, fullsync bool) {
 	}
 }
 func handleSecrets(gatewayNamespace string, gatewayName string, key string, object *AviObjectGraph) {
-	_, _, secretName := lib.ExtractTypeNameNamespace(key)
+	_, secretNamespace, secretName := lib.ExtractTypeNameNamespace(key)
 	utils.AviLog.Infof("key: %s, msg: Processing secret update %s has been added.", key, secretName)
 	cs := utils.GetInformers().ClientSet
 	evhVsCertRefs := object.GetAviEvhVS()[0].SSLKeyCertRefs
@@ -113,7 +130,7 @@ func handleSecrets(gatewayNamespace string, gatewayName string, key string, obje
 		utils.AviLog.Errorf("key: %s, msg: unable to get the gateway object. err: %s", key, err)
 		return
 	}
-	secretObj, err := cs.CoreV1().Secrets(gatewayNamespace).Get(context.TODO(), secretName, metav1.GetOptions{})
+	secretObj, err := cs.CoreV1().Secrets(secretNamespace).Get(context.TODO(), secretName, metav1.GetOptions{})
 	encodedCertNameIndexMap := make(map[string][]int)
 	for index, evhVsCertRef := range evhVsCertRefs {
 		_, exists := encodedCertNameIndexMap[evhVsCertRef.Name]
@@ -215,6 +232,21 @@ func saveAviModel(modelName string, aviGraph *nodes.AviObjectGraph, key string)
 func (o *AviObjectGraph) ProcessRouteDeletion(key string, routeModel RouteModel, fullsync bool) {
 
 	parentNode := o.GetAviEvhVS()
+	if len(parentNode) == 0 {
+		utils.AviLog.Warnf("key: %s, msg: parent vs not found for the route %s/%s", key, routeModel.GetNamespace(), routeModel.GetName())
+		return
+	}
+
+	if akogatewayapilib.IsL4RouteKind(routeModel.GetType()) {
+		o.DeleteL4RouteObjects(key, parentNode[0], routeModel, parentNode[0].ServiceMetadata.Gateway)
+		modelName := lib.GetTenant() + "/" + parentNode[0].Name
+		ok := saveAviModel(modelName, o.AviObjectGraph, key)
+		if ok && len(o.AviObjectGraph.GetOrderedNodes()) != 0 && !fullsync {
+			sharedQueue := utils.SharedWorkQueue().GetQueueByName(utils.GraphLayer)
+			nodes.PublishKeyToRestLayer(modelName, key, sharedQueue)
+		}
+		return
+	}
 
 	found, childVSNames := akogatewayapiobjects.GatewayApiLister().GetRouteToChildVS(routeModel.GetType() + "/" + routeModel.GetNamespace() + "/" + routeModel.GetName())
 	if !found {
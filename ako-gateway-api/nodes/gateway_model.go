@@ -24,6 +24,7 @@ import (
 	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	akov1beta1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1beta1"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 )
 
@@ -92,7 +93,8 @@ func BuildTLSNodesForGateway(gateway *gatewayv1.Gateway, key string) []*nodes.Av
 	var ns, name string
 	cs := utils.GetInformers().ClientSet
 	for _, listener := range gateway.Spec.Listeners {
-		if listener.TLS != nil {
+		// the listeners without a hostname are not valid, and their certificates are not added
+		if listener.TLS != nil && listener.Hostname != nil {
 			for _, certRef := range listener.TLS.CertificateRefs {
 				//kind is validated at ingestion
 				if certRef.Namespace == nil || *certRef.Namespace == "" {
@@ -101,6 +103,10 @@ func BuildTLSNodesForGateway(gateway *gatewayv1.Gateway, key string) []*nodes.Av
 					ns = string(*certRef.Namespace)
 				}
 				name = string(certRef.Name)
+				if !akogatewayapilib.IsReferencePermitted(lib.Gateway, gateway.Namespace, utils.Secret, ns, name) {
+					utils.AviLog.Warnf("key: %s, msg: reference to the secret %s/%s is not permitted", key, ns, name)
+					continue
+				}
 				secretObj, err := cs.CoreV1().Secrets(ns).Get(context.TODO(), name, metav1.GetOptions{})
 				if err != nil || secretObj == nil {
 					utils.AviLog.Warnf("key: %s, msg: secret %s has been deleted, err: %s", key, name, err)
@@ -142,20 +148,38 @@ func BuildVsVipNodeForGateway(gateway *gatewayv1.Gateway, vsName string) *nodes.
 		VipNetworks: utils.GetVipNetworkList(),
 	}
 
-	//Type is validated at ingestion
-	//TODO IPV6 handdling
-	if len(gateway.Spec.Addresses) == 1 {
-		vsvipNode.IPAddress = gateway.Spec.Addresses[0].Value
+	//Type and count per IP family are validated at ingestion
+	for _, address := range gateway.Spec.Addresses {
+		if address.Type != nil && *address.Type == gatewayv1.NamedAddressType {
+			vsvipNode.VipNetworks = getNamedAddressVipNetworks(address.Value)
+			continue
+		}
+		if utils.IsV4(address.Value) {
+			vsvipNode.IPAddress = address.Value
+		} else {
+			vsvipNode.V6IPAddress = address.Value
+		}
 	}
 	return vsvipNode
 }
 
+// getNamedAddressVipNetworks returns the VIP network the NamedAddress points to. The network
+// configured in AKO with the same name is picked up, so that the configured CIDRs are retained.
+func getNamedAddressVipNetworks(networkName string) []akov1beta1.AviInfraSettingVipNetwork {
+	for _, vipNetwork := range utils.GetVipNetworkList() {
+		if vipNetwork.NetworkName == networkName {
+			return []akov1beta1.AviInfraSettingVipNetwork{vipNetwork}
+		}
+	}
+	return []akov1beta1.AviInfraSettingVipNetwork{{NetworkName: networkName}}
+}
+
 func DeleteTLSNode(key string, object *AviObjectGraph, gateway *gatewayv1.Gateway, secretObj *corev1.Secret, encodedCertNameIndexMap map[string][]int) {
 	var tlsNodes []*nodes.AviTLSKeyCertNode
 	_, _, secretName := lib.ExtractTypeNameNamespace(key)
 	evhVsCertRefs := object.GetAviEvhVS()[0].SSLKeyCertRefs
 	for _, listener := range gateway.Spec.Listeners {
-		if listener.TLS != nil {
+		if listener.TLS != nil && listener.Hostname != nil {
 			for _, certRef := range listener.TLS.CertificateRefs {
 				name := string(certRef.Name)
 				encodedCertName := lib.GetTLSKeyCertNodeName("", string(*listener.Hostname), name)
@@ -182,7 +206,7 @@ func AddTLSNode(key string, object *AviObjectGraph, gateway *gatewayv1.Gateway,
 	_, _, secretName := lib.ExtractTypeNameNamespace(key)
 	evhVsCertRefs := object.GetAviEvhVS()[0].SSLKeyCertRefs
 	for _, listener := range gateway.Spec.Listeners {
-		if listener.TLS != nil {
+		if listener.TLS != nil && listener.Hostname != nil {
 			for _, certRef := range listener.TLS.CertificateRefs {
 				name := string(certRef.Name)
 				encodedCertName := lib.GetTLSKeyCertNodeName("", string(*listener.Hostname), name)
@@ -195,7 +219,12 @@ func AddTLSNode(key string, object *AviObjectGraph, gateway *gatewayv1.Gateway,
 						delete(encodedCertNameIndexMap, encodedCertName)
 					}
 				} else {
-					if name == secretName {
+					certNs := gateway.Namespace
+					if certRef.Namespace != nil && *certRef.Namespace != "" {
+						certNs = string(*certRef.Namespace)
+					}
+					if name == secretName && certNs == secretObj.Namespace &&
+						akogatewayapilib.IsReferencePermitted(lib.Gateway, gateway.Namespace, utils.Secret, certNs, name) {
 						tlsNode := TLSNodeFromSecret(secretObj, string(*listener.Hostname), name, key)
 						tlsNodes = append(tlsNodes, tlsNode)
 					}
@@ -20,6 +20,9 @@ import (
 	"strings"
 
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
 	akogatewayapiobjects "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/objects"
@@ -79,6 +82,41 @@ var (
 		GetGateways: HTTPRouteToGateway,
 		GetRoutes:   HTTPRouteChanges,
 	}
+	GRPCRoute = GraphSchema{
+		Type:        lib.GRPCRoute,
+		GetGateways: GRPCRouteToGateway,
+		GetRoutes:   GRPCRouteChanges,
+	}
+	TCPRoute = GraphSchema{
+		Type:        lib.TCPRoute,
+		GetGateways: TCPRouteToGateway,
+		GetRoutes:   TCPRouteChanges,
+	}
+	UDPRoute = GraphSchema{
+		Type:        lib.UDPRoute,
+		GetGateways: UDPRouteToGateway,
+		GetRoutes:   UDPRouteChanges,
+	}
+	TLSRoute = GraphSchema{
+		Type:        lib.TLSRoute,
+		GetGateways: TLSRouteToGateway,
+		GetRoutes:   TLSRouteChanges,
+	}
+	BackendPolicy = GraphSchema{
+		Type:        lib.BackendPolicy,
+		GetGateways: BackendPolicyToGateways,
+		GetRoutes:   BackendPolicyToRoutes,
+	}
+	ConfigMap = GraphSchema{
+		Type:        lib.ConfigMap,
+		GetGateways: ConfigMapToGateways,
+		GetRoutes:   ConfigMapToRoutes,
+	}
+	ReferenceGrant = GraphSchema{
+		Type:        lib.ReferenceGrant,
+		GetGateways: ReferenceGrantToGateways,
+		GetRoutes:   ReferenceGrantToRoutes,
+	}
 	SupportedGraphTypes = GraphDescriptor{
 		Gateway,
 		GatewayClass,
@@ -86,6 +124,13 @@ var (
 		Service,
 		Endpoint,
 		HTTPRoute,
+		GRPCRoute,
+		TCPRoute,
+		UDPRoute,
+		TLSRoute,
+		BackendPolicy,
+		ConfigMap,
+		ReferenceGrant,
 	}
 )
 
@@ -116,18 +161,17 @@ func GatewayGetGw(namespace, name, key string) ([]string, bool) {
 	for _, listenerObj := range gwObj.Spec.Listeners {
 		listenerString := string(listenerObj.Name) + "/" +
 			strconv.Itoa(int(listenerObj.Port)) + "/" + string(listenerObj.Protocol)
-		if listenerObj.AllowedRoutes == nil {
-			listenerString += "/" + string(gwObj.Namespace)
+		if listenerObj.AllowedRoutes != nil && listenerObj.AllowedRoutes.Namespaces != nil &&
+			listenerObj.AllowedRoutes.Namespaces.From != nil &&
+			string(*listenerObj.AllowedRoutes.Namespaces.From) == "All" {
+			listenerString += "/All"
+		} else if listenerObj.AllowedRoutes != nil && listenerObj.AllowedRoutes.Namespaces != nil &&
+			listenerObj.AllowedRoutes.Namespaces.From != nil &&
+			string(*listenerObj.AllowedRoutes.Namespaces.From) == "Selector" {
+			// the namespace selector is not supported, no route is attached to the listener
+			listenerString += "/Selector"
 		} else {
-			if listenerObj.AllowedRoutes.Namespaces != nil {
-				if listenerObj.AllowedRoutes.Namespaces.From != nil {
-					if string(*listenerObj.AllowedRoutes.Namespaces.From) == "Same" {
-						listenerString += "/" + string(gwObj.Namespace)
-					} else if string(*listenerObj.AllowedRoutes.Namespaces.From) == "All" {
-						listenerString += "/All"
-					}
-				}
-			}
+			listenerString += "/" + string(gwObj.Namespace)
 		}
 		if listenerObj.TLS != nil {
 			for _, cert := range listenerObj.TLS.CertificateRefs {
@@ -139,7 +183,12 @@ func GatewayGetGw(namespace, name, key string) ([]string, bool) {
 			}
 		}
 		listeners = append(listeners, listenerString)
-		hostnames[string(listenerObj.Name)] = string(*listenerObj.Hostname)
+		// hostname is optional for the TCP, UDP and TLS listeners
+		if listenerObj.Hostname != nil {
+			hostnames[string(listenerObj.Name)] = string(*listenerObj.Hostname)
+		} else {
+			hostnames[string(listenerObj.Name)] = ""
+		}
 	}
 	sort.Strings(listeners)
 	akogatewayapiobjects.GatewayApiLister().UpdateGatewayToListener(gwNsName, listeners)
@@ -205,15 +254,45 @@ func HTTPRouteToGateway(namespace, name, key string) ([]string, bool) {
 		}
 		return gwNsNameList, true
 	}
+	return l7RouteToGateway(lib.HTTPRoute, namespace, name, hrObj.Spec.ParentRefs, hrObj.Spec.Hostnames, key)
+}
+
+func GRPCRouteToGateway(namespace, name, key string) ([]string, bool) {
+
+	routeTypeNsName := lib.GRPCRoute + "/" + namespace + "/" + name
+	grObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().GRPCRouteInformer.Lister().GRPCRoutes(namespace).Get(name)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			utils.AviLog.Errorf("key: %s, msg: got error while getting GRPCRoute: %v", key, err)
+			return []string{}, false
+		}
+		found, gwNsNameList := akogatewayapiobjects.GatewayApiLister().GetRouteToGateway(routeTypeNsName)
+		if !found {
+			return []string{}, true
+		}
+		return gwNsNameList, true
+	}
+	hostnames := grObj.Spec.Hostnames
+	if len(hostnames) == 0 {
+		// the route without hostnames is attached to the listeners with any hostname
+		hostnames = []gatewayv1.Hostname{""}
+	}
+	return l7RouteToGateway(lib.GRPCRoute, namespace, name, grObj.Spec.ParentRefs, hostnames, key)
+}
+
+// l7RouteToGateway maps an HTTPRoute or GRPCRoute to the listeners of its parent gateways, whose
+// protocol supports the route kind and whose hostname matches one of the route hostnames.
+func l7RouteToGateway(kind, namespace, name string, parentRefs []gatewayv1.ParentReference, hostnames []gatewayv1.Hostname, key string) ([]string, bool) {
+	routeTypeNsName := kind + "/" + namespace + "/" + name
 	var listenerList []string
 	var gatewayList []string
 	var hostnameIntersection []string
 	var gwNsNameList []string
-	for _, parentRef := range hrObj.Spec.ParentRefs {
+	for _, parentRef := range parentRefs {
 		ns := namespace
 		if parentRef.Namespace != nil {
 			ns = string(*parentRef.Namespace)
-			// if *parentRef.Namespace != gatewayv1beta1.Namespace(hrObj.Namespace) {
+			// if *parentRef.Namespace != gatewayv1beta1.Namespace(namespace) {
 			// 	//check reference grant
 			// }
 		}
@@ -224,20 +303,23 @@ func HTTPRouteToGateway(namespace, name, key string) ([]string, bool) {
 			listenerSlice := strings.Split(listener, "/")
 			listenerName := listenerSlice[0]
 			listenerPort := listenerSlice[1]
+			listenerProtocol := listenerSlice[2]
 			listenerAllowedNS := listenerSlice[3]
+			if !akogatewayapilib.IsRouteKindSupported(listenerProtocol, kind) {
+				continue
+			}
 			//check if namespace is allowed
-			if listenerAllowedNS == "All" || listenerAllowedNS == hrObj.Namespace {
+			if listenerAllowedNS == "All" || listenerAllowedNS == namespace {
 				//if provided, check if section name and port matches
 				if (parentRef.SectionName == nil || string(*parentRef.SectionName) == listenerName) &&
 					(parentRef.Port == nil || string(*parentRef.Port) == listenerPort) {
 					listenerHostname := akogatewayapiobjects.GatewayApiLister().GetGatewayListenerToHostname(gwNsName, listenerName)
-					if strings.HasPrefix(listenerHostname, "*") {
-						listenerHostname = listenerHostname[1:]
-					}
 					hostnameMatched := false
-					for _, routeHostname := range hrObj.Spec.Hostnames {
-						if strings.HasSuffix(string(routeHostname), listenerHostname) {
-							hostnameIntersection = append(hostnameIntersection, string(routeHostname))
+					for _, routeHostname := range hostnames {
+						if hostname, matched := akogatewayapilib.GetHostnameIntersection(listenerHostname, string(routeHostname)); matched {
+							if !utils.HasElem(hostnameIntersection, hostname) {
+								hostnameIntersection = append(hostnameIntersection, hostname)
+							}
 							hostnameMatched = true
 						}
 					}
@@ -278,15 +360,7 @@ func HTTPRouteChanges(namespace, name, key string) ([]string, bool) {
 			utils.AviLog.Errorf("key: %s, msg: got error while getting gateway: %v", key, err)
 			return []string{}, false
 		}
-		_, svcNsNameList := akogatewayapiobjects.GatewayApiLister().GetRouteToService(routeTypeNsName)
-		_, gwNsNameList := akogatewayapiobjects.GatewayApiLister().GetRouteToGateway(routeTypeNsName)
-		for _, gwNsName := range gwNsNameList {
-			for _, svcNsName := range svcNsNameList {
-				akogatewayapiobjects.GatewayApiLister().DeleteGatewayServiceMappings(gwNsName, svcNsName)
-			}
-		}
-		akogatewayapiobjects.GatewayApiLister().DeleteRouteServiceMappings(routeTypeNsName)
-		akogatewayapiobjects.GatewayApiLister().DeleteRouteGatewayMappings(routeTypeNsName)
+		deleteRouteMappings(routeTypeNsName)
 		return []string{routeTypeNsName}, true
 	}
 
@@ -310,8 +384,42 @@ func HTTPRouteChanges(namespace, name, key string) ([]string, bool) {
 			svcNsName := ns + "/" + string(backendRef.Name)
 			svcNsNameList = append(svcNsNameList, svcNsName)
 		}
+		// the requests are cloned to the endpoints of the mirror backends
+		for _, filter := range rule.Filters {
+			if filter.RequestMirror == nil {
+				continue
+			}
+			ns := namespace
+			if filter.RequestMirror.BackendRef.Namespace != nil {
+				ns = string(*filter.RequestMirror.BackendRef.Namespace)
+			}
+			svcNsName := ns + "/" + string(filter.RequestMirror.BackendRef.Name)
+			if !utils.HasElem(svcNsNameList, svcNsName) {
+				svcNsNameList = append(svcNsNameList, svcNsName)
+			}
+		}
 	}
 
+	updateRouteServiceMappings(routeTypeNsName, gwNsNameList, svcNsNameList)
+
+	utils.AviLog.Debugf("key: %s, msg: HTTPRoutes retrieved %s", key, []string{routeTypeNsName})
+	return []string{routeTypeNsName}, true
+}
+
+// deleteRouteMappings removes all the gateway and service mappings of a deleted route.
+func deleteRouteMappings(routeTypeNsName string) {
+	_, svcNsNameList := akogatewayapiobjects.GatewayApiLister().GetRouteToService(routeTypeNsName)
+	_, gwNsNameList := akogatewayapiobjects.GatewayApiLister().GetRouteToGateway(routeTypeNsName)
+	for _, gwNsName := range gwNsNameList {
+		for _, svcNsName := range svcNsNameList {
+			akogatewayapiobjects.GatewayApiLister().DeleteGatewayServiceMappings(gwNsName, svcNsName)
+		}
+	}
+	akogatewayapiobjects.GatewayApiLister().DeleteRouteServiceMappings(routeTypeNsName)
+	akogatewayapiobjects.GatewayApiLister().DeleteRouteGatewayMappings(routeTypeNsName)
+}
+
+func updateRouteServiceMappings(routeTypeNsName string, gwNsNameList, svcNsNameList []string) {
 	// deletes the services, which are removed, from the gateway <-> service and route <-> service mappings
 	found, oldSvcs := akogatewayapiobjects.GatewayApiLister().GetRouteToService(routeTypeNsName)
 	if found {
@@ -336,8 +444,148 @@ func HTTPRouteChanges(namespace, name, key string) ([]string, bool) {
 			akogatewayapiobjects.GatewayApiLister().UpdateGatewayServiceMappings(gwNsName, svcNsName)
 		}
 	}
+}
 
-	utils.AviLog.Debugf("key: %s, msg: HTTPRoutes retrieved %s", key, []string{routeTypeNsName})
+func TCPRouteToGateway(namespace, name, key string) ([]string, bool) {
+	return l4RouteToGateway(lib.TCPRoute, namespace, name, key)
+}
+
+func UDPRouteToGateway(namespace, name, key string) ([]string, bool) {
+	return l4RouteToGateway(lib.UDPRoute, namespace, name, key)
+}
+
+func TLSRouteToGateway(namespace, name, key string) ([]string, bool) {
+	return l4RouteToGateway(lib.TLSRoute, namespace, name, key)
+}
+
+// l4RouteToGateway maps a TCPRoute, UDPRoute or TLSRoute to the listeners of its parent gateways.
+// A listener is selected only when its protocol supports the route kind, and for TLSRoute
+// objects, when one of the route hostnames matches the listener hostname.
+func l4RouteToGateway(kind, namespace, name, key string) ([]string, bool) {
+
+	routeTypeNsName := kind + "/" + namespace + "/" + name
+	route, err := newL4Route(key, kind, name, namespace)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			utils.AviLog.Errorf("key: %s, msg: got error while getting %s: %v", key, kind, err)
+			return []string{}, false
+		}
+		found, gwNsNameList := akogatewayapiobjects.GatewayApiLister().GetRouteToGateway(routeTypeNsName)
+		if !found {
+			return []string{}, true
+		}
+		return gwNsNameList, true
+	}
+
+	var gwNsNameList []string
+	for _, parentRef := range route.parentRefs {
+		ns := namespace
+		if parentRef.Namespace != nil {
+			ns = string(*parentRef.Namespace)
+		}
+		gwNsName := ns + "/" + string(parentRef.Name)
+		var listenerList []string
+		listeners := akogatewayapiobjects.GatewayApiLister().GetGatewayToListeners(gwNsName)
+		for _, listener := range listeners {
+			//ListenerName/port/protocol/allowedRouteSpec
+			listenerSlice := strings.Split(listener, "/")
+			listenerName := listenerSlice[0]
+			listenerPort := listenerSlice[1]
+			listenerProtocol := listenerSlice[2]
+			listenerAllowedNS := listenerSlice[3]
+			if !akogatewayapilib.IsRouteKindSupported(listenerProtocol, kind) {
+				continue
+			}
+			if listenerAllowedNS != "All" && listenerAllowedNS != namespace {
+				continue
+			}
+			if parentRef.SectionName != nil && string(*parentRef.SectionName) != listenerName {
+				continue
+			}
+			if parentRef.Port != nil && strconv.Itoa(int(*parentRef.Port)) != listenerPort {
+				continue
+			}
+			if kind == lib.TLSRoute {
+				listenerHostname := akogatewayapiobjects.GatewayApiLister().GetGatewayListenerToHostname(gwNsName, listenerName)
+				if !isHostnameMatched(listenerHostname, route.hostnames) {
+					continue
+				}
+			}
+			listenerList = append(listenerList, gwNsName+"/"+listenerName)
+		}
+
+		// removes the listeners of this gateway which are no longer selected by the route
+		_, oldListenerList := akogatewayapiobjects.GatewayApiLister().GetRouteToGatewayListener(routeTypeNsName)
+		for _, gwListener := range oldListenerList {
+			if strings.HasPrefix(gwListener, gwNsName+"/") && !utils.HasElem(listenerList, gwListener) {
+				akogatewayapiobjects.GatewayApiLister().DeleteGatewayRouteMappings(gwNsName, gwListener, routeTypeNsName)
+			}
+		}
+		akogatewayapiobjects.GatewayApiLister().UpdateGatewayRouteMappings(gwNsName, listenerList, routeTypeNsName)
+		if !utils.HasElem(gwNsNameList, gwNsName) {
+			gwNsNameList = append(gwNsNameList, gwNsName)
+		}
+	}
+
+	utils.AviLog.Debugf("key: %s, msg: Gateways retrieved %s", key, gwNsNameList)
+	return gwNsNameList, true
+}
+
+// isHostnameMatched returns true if the listener doesn't restrict the hostname, the route
+// doesn't specify any hostname, or one of the route hostnames matches the listener hostname.
+func isHostnameMatched(listenerHostname string, routeHostnames []gatewayv1.Hostname) bool {
+	if listenerHostname == "" || len(routeHostnames) == 0 {
+		return true
+	}
+	for _, routeHostname := range routeHostnames {
+		if _, matched := akogatewayapilib.GetHostnameIntersection(listenerHostname, string(routeHostname)); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func GRPCRouteChanges(namespace, name, key string) ([]string, bool) {
+	return routeChanges(lib.GRPCRoute, namespace, name, key)
+}
+
+func TCPRouteChanges(namespace, name, key string) ([]string, bool) {
+	return routeChanges(lib.TCPRoute, namespace, name, key)
+}
+
+func UDPRouteChanges(namespace, name, key string) ([]string, bool) {
+	return routeChanges(lib.UDPRoute, namespace, name, key)
+}
+
+func TLSRouteChanges(namespace, name, key string) ([]string, bool) {
+	return routeChanges(lib.TLSRoute, namespace, name, key)
+}
+
+func routeChanges(kind, namespace, name, key string) ([]string, bool) {
+	routeTypeNsName := kind + "/" + namespace + "/" + name
+	route, err := NewRouteModel(key, kind, name, namespace)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			utils.AviLog.Errorf("key: %s, msg: got error while getting %s: %v", key, kind, err)
+			return []string{}, false
+		}
+		deleteRouteMappings(routeTypeNsName)
+		return []string{routeTypeNsName}, true
+	}
+
+	gwNsNameList := sets.List(route.GetParents())
+	var svcNsNameList []string
+	for _, rule := range route.ParseRouteRules().Rules {
+		for _, backend := range rule.Backends {
+			svcNsName := backend.Namespace + "/" + backend.Name
+			if !utils.HasElem(svcNsNameList, svcNsName) {
+				svcNsNameList = append(svcNsNameList, svcNsName)
+			}
+		}
+	}
+	updateRouteServiceMappings(routeTypeNsName, gwNsNameList, svcNsNameList)
+
+	utils.AviLog.Debugf("key: %s, msg: %ss retrieved %s", key, kind, []string{routeTypeNsName})
 	return []string{routeTypeNsName}, true
 }
 
@@ -379,6 +627,229 @@ func SecretToGateways(namespace, name, key string) ([]string, bool) {
 	return gwNsNameList, found
 }
 
+// BackendPolicyToGateways returns the gateways of the current and the previous target of a BackendPolicy,
+// so that the pools of both the targets are rebuilt when the target changes or the policy is deleted.
+func BackendPolicyToGateways(namespace, name, key string) ([]string, bool) {
+	prevTarget, target, found := getBackendPolicyTargets(namespace, name, key)
+	if !found {
+		return []string{}, false
+	}
+	var gwNsNameList []string
+	for _, targetKindNsName := range []string{prevTarget, target} {
+		if targetKindNsName == "" {
+			continue
+		}
+		var targetGwNsNameList []string
+		targetKind, targetNs, targetName := lib.ExtractTypeNameNamespace(targetKindNsName)
+		switch targetKind {
+		case utils.Service:
+			targetGwNsNameList, _ = ServiceToGateways(targetNs, targetName, key)
+		case lib.HTTPRoute:
+			_, targetGwNsNameList = akogatewayapiobjects.GatewayApiLister().GetRouteToGateway(targetKindNsName)
+		case lib.Gateway:
+			targetGwNsNameList = []string{targetNs + "/" + targetName}
+		}
+		for _, gwNsName := range targetGwNsNameList {
+			if !utils.HasElem(gwNsNameList, gwNsName) {
+				gwNsNameList = append(gwNsNameList, gwNsName)
+			}
+		}
+	}
+	utils.AviLog.Debugf("key: %s, msg: Gateways retrieved %s", key, gwNsNameList)
+	return gwNsNameList, true
+}
+
+// BackendPolicyToRoutes returns the routes of the current and the previous target of a BackendPolicy, and
+// records the current target of the policy.
+func BackendPolicyToRoutes(namespace, name, key string) ([]string, bool) {
+	prevTarget, target, found := getBackendPolicyTargets(namespace, name, key)
+	if !found {
+		return []string{}, false
+	}
+	var routeTypeNsNameList []string
+	for _, targetKindNsName := range []string{prevTarget, target} {
+		if targetKindNsName == "" {
+			continue
+		}
+		var targetRouteTypeNsNameList []string
+		targetKind, targetNs, targetName := lib.ExtractTypeNameNamespace(targetKindNsName)
+		switch targetKind {
+		case utils.Service:
+			targetRouteTypeNsNameList, _ = ServiceToRoutes(targetNs, targetName, key)
+		case lib.HTTPRoute:
+			targetRouteTypeNsNameList = []string{targetKindNsName}
+		case lib.Gateway:
+			targetRouteTypeNsNameList, _ = GatewayToRoutes(targetNs, targetName, key)
+		}
+		for _, routeTypeNsName := range targetRouteTypeNsNameList {
+			if !utils.HasElem(routeTypeNsNameList, routeTypeNsName) {
+				routeTypeNsNameList = append(routeTypeNsNameList, routeTypeNsName)
+			}
+		}
+	}
+
+	backendPolicyNsName := namespace + "/" + name
+	if target == "" {
+		akogatewayapiobjects.GatewayApiLister().DeleteBackendPolicyToTarget(backendPolicyNsName)
+	} else {
+		akogatewayapiobjects.GatewayApiLister().UpdateBackendPolicyToTarget(backendPolicyNsName, target)
+	}
+	utils.AviLog.Debugf("key: %s, msg: Routes retrieved %s", key, routeTypeNsNameList)
+	return routeTypeNsNameList, true
+}
+
+// getBackendPolicyTargets returns the recorded and the current target of a BackendPolicy, in the format
+// targetKind/targetNs/targetName. The current target is empty if the BackendPolicy has been deleted.
+func getBackendPolicyTargets(namespace, name, key string) (string, string, bool) {
+	_, prevTarget := akogatewayapiobjects.GatewayApiLister().GetBackendPolicyToTarget(namespace + "/" + name)
+	backendPolicy, err := akogatewayapilib.AKOControlConfig().AKOCRDInformers().BackendPolicyInformer.Lister().BackendPolicies(namespace).Get(name)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			utils.AviLog.Errorf("key: %s, msg: got error while getting BackendPolicy: %v", key, err)
+			return "", "", false
+		}
+		return prevTarget, "", true
+	}
+	return prevTarget, backendPolicy.Spec.TargetRef.Kind + "/" + namespace + "/" + backendPolicy.Spec.TargetRef.Name, true
+}
+
+// ConfigMapToGateways returns the gateways of the Services targeted by the BackendTLSPolicies which refer to
+// the ConfigMap for the CA certificates.
+func ConfigMapToGateways(namespace, name, key string) ([]string, bool) {
+	var gwNsNameList []string
+	for _, svcName := range getConfigMapBackendTLSPolicyTargets(namespace, name) {
+		svcGwNsNameList, _ := ServiceToGateways(namespace, svcName, key)
+		for _, gwNsName := range svcGwNsNameList {
+			if !utils.HasElem(gwNsNameList, gwNsName) {
+				gwNsNameList = append(gwNsNameList, gwNsName)
+			}
+		}
+	}
+	utils.AviLog.Debugf("key: %s, msg: Gateways retrieved %s", key, gwNsNameList)
+	return gwNsNameList, true
+}
+
+// ConfigMapToRoutes returns the routes of the Services targeted by the BackendTLSPolicies which refer to
+// the ConfigMap for the CA certificates.
+func ConfigMapToRoutes(namespace, name, key string) ([]string, bool) {
+	var routeTypeNsNameList []string
+	for _, svcName := range getConfigMapBackendTLSPolicyTargets(namespace, name) {
+		svcRouteTypeNsNameList, _ := ServiceToRoutes(namespace, svcName, key)
+		for _, routeTypeNsName := range svcRouteTypeNsNameList {
+			if !utils.HasElem(routeTypeNsNameList, routeTypeNsName) {
+				routeTypeNsNameList = append(routeTypeNsNameList, routeTypeNsName)
+			}
+		}
+	}
+	utils.AviLog.Debugf("key: %s, msg: Routes retrieved %s", key, routeTypeNsNameList)
+	return routeTypeNsNameList, true
+}
+
+// getConfigMapBackendTLSPolicyTargets returns the names of the Services targeted by the BackendTLSPolicies
+// which refer to the ConfigMap, a BackendTLSPolicy only targets the Services in its own namespace.
+func getConfigMapBackendTLSPolicyTargets(namespace, name string) []string {
+	var svcNames []string
+	for _, backendTLSPolicy := range akogatewayapilib.GetBackendTLSPoliciesForConfigMap(namespace, name) {
+		svcName := string(backendTLSPolicy.Spec.TargetRef.Name)
+		if !utils.HasElem(svcNames, svcName) {
+			svcNames = append(svcNames, svcName)
+		}
+	}
+	return svcNames
+}
+
+// ReferenceGrantToGateways returns the Gateways in the namespaces a ReferenceGrant permits references
+// from, and the Gateways of the routes in such namespaces. Both the current and the previous spec of the
+// ReferenceGrant are considered, as the references which are no longer permitted must be removed.
+func ReferenceGrantToGateways(namespace, name, key string) ([]string, bool) {
+	gwNsNameList, routeTypeNsNameList, found := GetReferenceGrantDependents(namespace, name, key)
+	if !found {
+		return []string{}, false
+	}
+	for _, routeTypeNsName := range routeTypeNsNameList {
+		_, routeGwNsNameList := akogatewayapiobjects.GatewayApiLister().GetRouteToGateway(routeTypeNsName)
+		for _, gwNsName := range routeGwNsNameList {
+			if !utils.HasElem(gwNsNameList, gwNsName) {
+				gwNsNameList = append(gwNsNameList, gwNsName)
+			}
+		}
+	}
+	utils.AviLog.Debugf("key: %s, msg: Gateways retrieved %s", key, gwNsNameList)
+	return gwNsNameList, true
+}
+
+// ReferenceGrantToRoutes returns the routes in the namespaces a ReferenceGrant permits references from, and
+// the routes of the Gateways in such namespaces, as the models of these Gateways are rebuilt. It records the
+// current spec of the ReferenceGrant.
+func ReferenceGrantToRoutes(namespace, name, key string) ([]string, bool) {
+	gwNsNameList, routeTypeNsNameList, found := GetReferenceGrantDependents(namespace, name, key)
+	if !found {
+		return []string{}, false
+	}
+	for _, gwNsName := range gwNsNameList {
+		gwNamespace, _, gwName := lib.ExtractTypeNameNamespace(gwNsName)
+		gwRouteTypeNsNameList, _ := GatewayToRoutes(gwNamespace, gwName, key)
+		for _, routeTypeNsName := range gwRouteTypeNsNameList {
+			if !utils.HasElem(routeTypeNsNameList, routeTypeNsName) {
+				routeTypeNsNameList = append(routeTypeNsNameList, routeTypeNsName)
+			}
+		}
+	}
+
+	referenceGrantNsName := namespace + "/" + name
+	referenceGrant, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().ReferenceGrantInformer.Lister().ReferenceGrants(namespace).Get(name)
+	if err != nil {
+		akogatewayapiobjects.GatewayApiLister().DeleteReferenceGrantToFrom(referenceGrantNsName)
+	} else {
+		akogatewayapiobjects.GatewayApiLister().UpdateReferenceGrantToFrom(referenceGrantNsName, akogatewayapilib.GetReferenceGrantFrom(referenceGrant))
+	}
+	utils.AviLog.Debugf("key: %s, msg: Routes retrieved %s", key, routeTypeNsNameList)
+	return routeTypeNsNameList, true
+}
+
+// GetReferenceGrantDependents returns the Gateways and the routes, known to AKO, in the namespaces the
+// current and the recorded spec of a ReferenceGrant permit references from.
+func GetReferenceGrantDependents(namespace, name, key string) ([]string, []string, bool) {
+	_, fromKindNsList := akogatewayapiobjects.GatewayApiLister().GetReferenceGrantToFrom(namespace + "/" + name)
+	referenceGrant, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().ReferenceGrantInformer.Lister().ReferenceGrants(namespace).Get(name)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			utils.AviLog.Errorf("key: %s, msg: got error while getting ReferenceGrant: %v", key, err)
+			return nil, nil, false
+		}
+	} else {
+		for _, fromKindNs := range akogatewayapilib.GetReferenceGrantFrom(referenceGrant) {
+			if !utils.HasElem(fromKindNsList, fromKindNs) {
+				fromKindNsList = append(fromKindNsList, fromKindNs)
+			}
+		}
+	}
+
+	var gwNsNameList, routeTypeNsNameList []string
+	informers := akogatewayapilib.AKOControlConfig().GatewayApiInformers()
+	for _, fromKindNs := range fromKindNsList {
+		fromKind, fromNamespace, _ := strings.Cut(fromKindNs, "/")
+		switch fromKind {
+		case lib.Gateway:
+			gateways, err := informers.GatewayInformer.Lister().Gateways(fromNamespace).List(labels.Everything())
+			if err != nil {
+				utils.AviLog.Warnf("key: %s, msg: unable to list the Gateways in namespace %s, err: %v", key, fromNamespace, err)
+				continue
+			}
+			for _, gateway := range gateways {
+				if _, isAkoCtrl := akogatewayapiobjects.GatewayApiLister().IsGatewayClassControllerAKO(string(gateway.Spec.GatewayClassName)); isAkoCtrl {
+					gwNsNameList = append(gwNsNameList, fromNamespace+"/"+gateway.Name)
+				}
+			}
+		case lib.HTTPRoute, lib.GRPCRoute, lib.TCPRoute, lib.UDPRoute, lib.TLSRoute:
+			// the routes are looked up in the route to gateway mappings, a route without a
+			// mapping is not attached to any Gateway
+			routeTypeNsNameList = append(routeTypeNsNameList, akogatewayapiobjects.GatewayApiLister().GetRoutesInNamespace(fromKind, fromNamespace)...)
+		}
+	}
+	return gwNsNameList, routeTypeNsNameList, true
+}
+
 func NoOperation(namespace, name, key string) ([]string, bool) {
 	// No-op
 	return []string{}, true
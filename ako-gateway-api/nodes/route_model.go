@@ -16,13 +16,16 @@ package nodes
 
 import (
 	"fmt"
+	"regexp"
 	"sort"
 
 	"k8s.io/apimachinery/pkg/util/sets"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 )
 
 type RouteModel interface {
@@ -39,6 +42,10 @@ func NewRouteModel(key, objType, name, namespace string) (RouteModel, error) {
 	switch objType {
 	case lib.HTTPRoute:
 		return GetHTTPRouteModel(key, name, namespace)
+	case lib.GRPCRoute:
+		return getGRPCRouteModel(key, name, namespace)
+	case lib.TCPRoute, lib.UDPRoute, lib.TLSRoute:
+		return newL4Route(key, objType, name, namespace)
 	}
 	return nil, fmt.Errorf("object of type %s not supported", objType)
 }
@@ -51,26 +58,90 @@ type HeaderMatch struct {
 
 type PathMatch struct {
 	Path string
-	//Exact, PathPrefix
+	//Exact, PathPrefix, RegularExpression
 	Type string
 }
 
+type QueryParamMatch struct {
+	//Exact, RegularExpression
+	Type  string
+	Name  string
+	Value string
+}
+
+// Match is part of the child vs name, the fields added later are omitted when empty
+// to retain the names of the existing child virtual services.
 type Match struct {
-	PathMatch   *PathMatch
-	HeaderMatch []*HeaderMatch
+	PathMatch       *PathMatch
+	HeaderMatch     []*HeaderMatch
+	QueryParamMatch []*QueryParamMatch `json:",omitempty"`
+	Method          string             `json:",omitempty"`
 }
 
 type Matches []*Match
 
 func (m Matches) Len() int      { return len(m) }
 func (m Matches) Swap(i, j int) { m[i], m[j] = m[j], m[i] }
+
+// Less orders the matches by path. The child VS, pool group and pool names are computed from
+// the matches in this order, hence it must not change. The precedence of the matches is only
+// applied to the match rules of the child VS, see getMatchesByPrecedence.
 func (m Matches) Less(i, j int) bool {
 	if m[i].PathMatch != nil && m[j].PathMatch != nil {
-		return m[i].PathMatch.Path < m[j].PathMatch.Path // TODO: need to check this logic
+		return m[i].PathMatch.Path < m[j].PathMatch.Path
 	}
 	return false
 }
 
+type matchesByPrecedence []*Match
+
+func (m matchesByPrecedence) Len() int      { return len(m) }
+func (m matchesByPrecedence) Swap(i, j int) { m[i], m[j] = m[j], m[i] }
+
+// Less orders the matches as per the precedence in the Gateway API spec, an exact path
+// match followed by the longest path prefix, the method match, the largest number of
+// header matches and the largest number of query param matches.
+func (m matchesByPrecedence) Less(i, j int) bool {
+	pathI, pathJ := m[i].getPathMatch(), m[j].getPathMatch()
+	if (pathI.Type == "Exact") != (pathJ.Type == "Exact") {
+		return pathI.Type == "Exact"
+	}
+	if len(pathI.Path) != len(pathJ.Path) {
+		return len(pathI.Path) > len(pathJ.Path)
+	}
+	if (m[i].Method != "") != (m[j].Method != "") {
+		return m[i].Method != ""
+	}
+	if len(m[i].HeaderMatch) != len(m[j].HeaderMatch) {
+		return len(m[i].HeaderMatch) > len(m[j].HeaderMatch)
+	}
+	if len(m[i].QueryParamMatch) != len(m[j].QueryParamMatch) {
+		return len(m[i].QueryParamMatch) > len(m[j].QueryParamMatch)
+	}
+	// deterministic order for the matches with the same precedence
+	if pathI.Path != pathJ.Path {
+		return pathI.Path < pathJ.Path
+	}
+	return utils.Stringify(m[i]) < utils.Stringify(m[j])
+}
+
+// getMatchesByPrecedence returns a copy of the matches in the order of precedence, the
+// order of the rule matches is retained.
+func getMatchesByPrecedence(matches []*Match) []*Match {
+	sorted := make([]*Match, len(matches))
+	copy(sorted, matches)
+	sort.Sort(matchesByPrecedence(sorted))
+	return sorted
+}
+
+// getPathMatch returns the path match, the match without a path matches all the paths.
+func (m *Match) getPathMatch() *PathMatch {
+	if m == nil || m.PathMatch == nil {
+		return &PathMatch{Path: "/", Type: "PathPrefix"}
+	}
+	return m.PathMatch
+}
+
 type Header struct {
 	Name  string
 	Value string
@@ -88,16 +159,36 @@ type HeaderFilter struct {
 	Remove []string
 }
 
+type PathModifier struct {
+	//ReplaceFullPath, ReplacePrefixMatch
+	Type  string
+	Value string
+}
+
 type RedirectFilter struct {
+	Scheme     string
 	Host       string
+	Port       int32
+	Path       *PathModifier
 	StatusCode int32
 }
 
+type RewriteFilter struct {
+	Host string
+	Path *PathModifier
+}
+
+type MirrorFilter struct {
+	Backend *Backend
+}
+
 type Filter struct {
 	Type           string
 	RequestFilter  *HeaderFilter
 	ResponseFilter *HeaderFilter
 	RedirectFilter *RedirectFilter
+	RewriteFilter  *RewriteFilter
+	MirrorFilter   *MirrorFilter
 }
 
 type Backend struct {
@@ -108,6 +199,8 @@ type Backend struct {
 }
 
 type Rule struct {
+	// Index is the position of the rule in the route spec
+	Index    int
 	Matches  []*Match
 	Filters  []*Filter
 	Backends []*Backend
@@ -169,8 +262,8 @@ func (hr *httpRoute) ParseRouteRules() *RouteConfig {
 	}
 
 	routeConfig.Rules = make([]*Rule, 0, len(hr.spec.Rules))
-	for _, rule := range hr.spec.Rules {
-		routeConfigRule := &Rule{}
+	for index, rule := range hr.spec.Rules {
+		routeConfigRule := &Rule{Index: index}
 		routeConfigRule.Matches = make([]*Match, 0, len(rule.Matches))
 		for _, ruleMatch := range rule.Matches {
 			match := &Match{}
@@ -202,6 +295,25 @@ func (hr *httpRoute) ParseRouteRules() *RouteConfig {
 				match.HeaderMatch = append(match.HeaderMatch, headerMatch)
 			}
 
+			// query param match
+			match.QueryParamMatch = make([]*QueryParamMatch, 0, len(ruleMatch.QueryParams))
+			for _, queryParam := range ruleMatch.QueryParams {
+				queryParamMatch := &QueryParamMatch{}
+				if queryParam.Type != nil {
+					queryParamMatch.Type = string(*queryParam.Type)
+				} else {
+					queryParamMatch.Type = string(gatewayv1.QueryParamMatchExact)
+				}
+				queryParamMatch.Name = string(queryParam.Name)
+				queryParamMatch.Value = queryParam.Value
+				match.QueryParamMatch = append(match.QueryParamMatch, queryParamMatch)
+			}
+
+			// method match
+			if ruleMatch.Method != nil {
+				match.Method = string(*ruleMatch.Method)
+			}
+
 			routeConfigRule.Matches = append(routeConfigRule.Matches, match)
 		}
 		sort.Sort((Matches)(routeConfigRule.Matches))
@@ -211,70 +323,41 @@ func (hr *httpRoute) ParseRouteRules() *RouteConfig {
 			filter := &Filter{}
 			filter.Type = string(ruleFilter.Type)
 
-			// request header filter
-			if ruleFilter.RequestHeaderModifier != nil {
-				filter.RequestFilter = &HeaderFilter{}
-				filter.RequestFilter.Add = make([]*Header, 0, len(ruleFilter.RequestHeaderModifier.Add))
-				for _, addFilter := range ruleFilter.RequestHeaderModifier.Add {
-					addHeader := &Header{
-						Name:  string(addFilter.Name),
-						Value: addFilter.Value,
-					}
-					filter.RequestFilter.Add = append(filter.RequestFilter.Add, addHeader)
-				}
-				filter.RequestFilter.Set = make([]*Header, 0, len(ruleFilter.RequestHeaderModifier.Set))
-				for _, setFilter := range ruleFilter.RequestHeaderModifier.Set {
-					setHeader := &Header{
-						Name:  string(setFilter.Name),
-						Value: setFilter.Value,
-					}
-					filter.RequestFilter.Set = append(filter.RequestFilter.Set, setHeader)
-				}
-				filter.RequestFilter.Remove = make([]string, len(ruleFilter.RequestHeaderModifier.Remove))
-				copy(filter.RequestFilter.Remove, ruleFilter.RequestHeaderModifier.Remove)
-
-				sort.Sort((Headers)(filter.RequestFilter.Add))
-				sort.Sort((Headers)(filter.RequestFilter.Set))
-				sort.Strings(filter.RequestFilter.Remove)
-			}
-
-			// response header filter
-			if ruleFilter.ResponseHeaderModifier != nil {
-				filter.ResponseFilter = &HeaderFilter{}
-				filter.ResponseFilter.Add = make([]*Header, 0, len(ruleFilter.ResponseHeaderModifier.Add))
-				for _, addFilter := range ruleFilter.ResponseHeaderModifier.Add {
-					addHeader := &Header{
-						Name:  string(addFilter.Name),
-						Value: addFilter.Value,
-					}
-					filter.ResponseFilter.Add = append(filter.ResponseFilter.Add, addHeader)
-				}
-				filter.ResponseFilter.Set = make([]*Header, 0, len(ruleFilter.ResponseHeaderModifier.Set))
-				for _, setFilter := range ruleFilter.ResponseHeaderModifier.Set {
-					setHeader := &Header{
-						Name:  string(setFilter.Name),
-						Value: setFilter.Value,
-					}
-					filter.ResponseFilter.Set = append(filter.ResponseFilter.Set, setHeader)
-				}
-				filter.ResponseFilter.Remove = make([]string, len(ruleFilter.ResponseHeaderModifier.Remove))
-				copy(filter.ResponseFilter.Remove, ruleFilter.ResponseHeaderModifier.Remove)
-
-				sort.Sort((Headers)(filter.ResponseFilter.Add))
-				sort.Sort((Headers)(filter.ResponseFilter.Set))
-				sort.Strings(filter.ResponseFilter.Remove)
-			}
+			// request and response header filters
+			filter.RequestFilter = parseHeaderFilter(ruleFilter.RequestHeaderModifier)
+			filter.ResponseFilter = parseHeaderFilter(ruleFilter.ResponseHeaderModifier)
 
 			// request redirect filter
 			if ruleFilter.RequestRedirect != nil {
 				filter.RedirectFilter = &RedirectFilter{}
+				if ruleFilter.RequestRedirect.Scheme != nil {
+					filter.RedirectFilter.Scheme = *ruleFilter.RequestRedirect.Scheme
+				}
 				if ruleFilter.RequestRedirect.Hostname != nil {
 					filter.RedirectFilter.Host = string(*ruleFilter.RequestRedirect.Hostname)
 				}
+				if ruleFilter.RequestRedirect.Port != nil {
+					filter.RedirectFilter.Port = int32(*ruleFilter.RequestRedirect.Port)
+				}
+				filter.RedirectFilter.Path = parsePathModifier(ruleFilter.RequestRedirect.Path)
 				if ruleFilter.RequestRedirect.StatusCode != nil {
 					filter.RedirectFilter.StatusCode = int32(*ruleFilter.RequestRedirect.StatusCode)
 				}
 			}
+
+			// url rewrite filter
+			if ruleFilter.URLRewrite != nil {
+				filter.RewriteFilter = &RewriteFilter{}
+				if ruleFilter.URLRewrite.Hostname != nil {
+					filter.RewriteFilter.Host = string(*ruleFilter.URLRewrite.Hostname)
+				}
+				filter.RewriteFilter.Path = parsePathModifier(ruleFilter.URLRewrite.Path)
+			}
+
+			// request mirror filter
+			if ruleFilter.RequestMirror != nil {
+				filter.MirrorFilter = hr.parseMirrorFilter(ruleFilter.RequestMirror)
+			}
 			routeConfigRule.Filters = append(routeConfigRule.Filters, filter)
 		}
 		for _, ruleBackend := range rule.BackendRefs {
@@ -285,6 +368,10 @@ func (hr *httpRoute) ParseRouteRules() *RouteConfig {
 			} else {
 				backend.Namespace = hr.namespace
 			}
+			if !akogatewayapilib.IsReferencePermitted(lib.HTTPRoute, hr.namespace, utils.Service, backend.Namespace, backend.Name) {
+				utils.AviLog.Warnf("key: %s, msg: reference to the Service %s/%s is not permitted, skipping the backend", hr.key, backend.Namespace, backend.Name)
+				continue
+			}
 			if ruleBackend.Port != nil {
 				//Default 0
 				backend.Port = int32(*ruleBackend.Port)
@@ -301,6 +388,74 @@ func (hr *httpRoute) ParseRouteRules() *RouteConfig {
 	return hr.routeConfig
 }
 
+// parseMirrorFilter returns the backend the requests are mirrored to, nil is returned
+// if the reference to the backend Service is not permitted.
+func (hr *httpRoute) parseMirrorFilter(requestMirror *gatewayv1.HTTPRequestMirrorFilter) *MirrorFilter {
+	backend := &Backend{
+		Name:      string(requestMirror.BackendRef.Name),
+		Namespace: hr.namespace,
+	}
+	if requestMirror.BackendRef.Namespace != nil {
+		backend.Namespace = string(*requestMirror.BackendRef.Namespace)
+	}
+	if !akogatewayapilib.IsReferencePermitted(lib.HTTPRoute, hr.namespace, utils.Service, backend.Namespace, backend.Name) {
+		utils.AviLog.Warnf("key: %s, msg: reference to the mirror Service %s/%s is not permitted, skipping the mirror filter", hr.key, backend.Namespace, backend.Name)
+		return nil
+	}
+	if requestMirror.BackendRef.Port != nil {
+		backend.Port = int32(*requestMirror.BackendRef.Port)
+	}
+	return &MirrorFilter{Backend: backend}
+}
+
+func parseHeaderFilter(headerModifier *gatewayv1.HTTPHeaderFilter) *HeaderFilter {
+	if headerModifier == nil {
+		return nil
+	}
+	headerFilter := &HeaderFilter{}
+	headerFilter.Add = make([]*Header, 0, len(headerModifier.Add))
+	for _, addFilter := range headerModifier.Add {
+		addHeader := &Header{
+			Name:  string(addFilter.Name),
+			Value: addFilter.Value,
+		}
+		headerFilter.Add = append(headerFilter.Add, addHeader)
+	}
+	headerFilter.Set = make([]*Header, 0, len(headerModifier.Set))
+	for _, setFilter := range headerModifier.Set {
+		setHeader := &Header{
+			Name:  string(setFilter.Name),
+			Value: setFilter.Value,
+		}
+		headerFilter.Set = append(headerFilter.Set, setHeader)
+	}
+	headerFilter.Remove = make([]string, len(headerModifier.Remove))
+	copy(headerFilter.Remove, headerModifier.Remove)
+
+	sort.Sort((Headers)(headerFilter.Add))
+	sort.Sort((Headers)(headerFilter.Set))
+	sort.Strings(headerFilter.Remove)
+	return headerFilter
+}
+
+func parsePathModifier(pathModifier *gatewayv1.HTTPPathModifier) *PathModifier {
+	if pathModifier == nil {
+		return nil
+	}
+	modifier := &PathModifier{Type: string(pathModifier.Type)}
+	switch pathModifier.Type {
+	case gatewayv1.FullPathHTTPPathModifier:
+		if pathModifier.ReplaceFullPath != nil {
+			modifier.Value = *pathModifier.ReplaceFullPath
+		}
+	case gatewayv1.PrefixMatchHTTPPathModifier:
+		if pathModifier.ReplacePrefixMatch != nil {
+			modifier.Value = *pathModifier.ReplacePrefixMatch
+		}
+	}
+	return modifier
+}
+
 func (hr *httpRoute) Exists() bool {
 	return hr != nil
 }
@@ -316,3 +471,311 @@ func (hr *httpRoute) GetParents() sets.Set[string] {
 	}
 	return parents
 }
+
+type grpcRoute struct {
+	key         string
+	name        string
+	namespace   string
+	routeConfig *RouteConfig
+	spec        *gatewayv1alpha2.GRPCRouteSpec
+}
+
+func getGRPCRouteModel(key string, name, namespace string) (RouteModel, error) {
+	gr := &grpcRoute{
+		key:       key,
+		name:      name,
+		namespace: namespace,
+	}
+
+	grObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().GRPCRouteInformer.Lister().GRPCRoutes(namespace).Get(name)
+	if err != nil {
+		return gr, err
+	}
+	gr.spec = grObj.Spec.DeepCopy()
+	return gr, nil
+}
+
+func (gr *grpcRoute) GetName() string {
+	return gr.name
+}
+
+func (gr *grpcRoute) GetNamespace() string {
+	return gr.namespace
+}
+
+func (gr *grpcRoute) GetType() string {
+	return lib.GRPCRoute
+}
+
+func (gr *grpcRoute) GetSpec() interface{} {
+	return gr.spec
+}
+
+// ParseRouteRules translates the GRPCRoute rules to the rules of the HTTP/2 requests, a gRPC
+// request carries the service and the method in the path as /<service>/<method>.
+func (gr *grpcRoute) ParseRouteRules() *RouteConfig {
+	if gr.routeConfig != nil {
+		return gr.routeConfig
+	}
+	routeConfig := &RouteConfig{}
+
+	if len(gr.spec.Hostnames) == 0 {
+		// the route without hostnames takes the hostnames of the listeners it is attached to
+		routeConfig.Hosts = []string{""}
+	} else {
+		routeConfig.Hosts = make([]string, len(gr.spec.Hostnames))
+		for i := range gr.spec.Hostnames {
+			routeConfig.Hosts[i] = string(gr.spec.Hostnames[i])
+		}
+	}
+
+	routeConfig.Rules = make([]*Rule, 0, len(gr.spec.Rules))
+	for index, rule := range gr.spec.Rules {
+		routeConfigRule := &Rule{Index: index}
+		routeConfigRule.Matches = make([]*Match, 0, len(rule.Matches))
+		for _, ruleMatch := range rule.Matches {
+			match := &Match{}
+
+			// method match
+			if ruleMatch.Method != nil {
+				match.PathMatch = parseGRPCMethodMatch(ruleMatch.Method)
+			}
+
+			// header match
+			match.HeaderMatch = make([]*HeaderMatch, 0, len(ruleMatch.Headers))
+			for _, header := range ruleMatch.Headers {
+				headerMatch := &HeaderMatch{}
+				if header.Type != nil {
+					headerMatch.Type = string(*header.Type)
+				}
+				headerMatch.Name = string(header.Name)
+				headerMatch.Value = header.Value
+				match.HeaderMatch = append(match.HeaderMatch, headerMatch)
+			}
+			routeConfigRule.Matches = append(routeConfigRule.Matches, match)
+		}
+		if len(routeConfigRule.Matches) == 0 {
+			// the rule without matches matches all the requests
+			routeConfigRule.Matches = append(routeConfigRule.Matches, &Match{
+				PathMatch:   &PathMatch{Path: "/", Type: "PathPrefix"},
+				HeaderMatch: []*HeaderMatch{},
+			})
+		}
+		sort.Sort((Matches)(routeConfigRule.Matches))
+
+		routeConfigRule.Filters = make([]*Filter, 0, len(rule.Filters))
+		for _, ruleFilter := range rule.Filters {
+			filter := &Filter{}
+			filter.Type = string(ruleFilter.Type)
+			filter.RequestFilter = parseHeaderFilter(ruleFilter.RequestHeaderModifier)
+			filter.ResponseFilter = parseHeaderFilter(ruleFilter.ResponseHeaderModifier)
+			routeConfigRule.Filters = append(routeConfigRule.Filters, filter)
+		}
+		for _, ruleBackend := range rule.BackendRefs {
+			backend := &Backend{}
+			backend.Name = string(ruleBackend.Name)
+			if ruleBackend.Namespace != nil {
+				backend.Namespace = string(*ruleBackend.Namespace)
+			} else {
+				backend.Namespace = gr.namespace
+			}
+			if !akogatewayapilib.IsReferencePermitted(lib.GRPCRoute, gr.namespace, utils.Service, backend.Namespace, backend.Name) {
+				utils.AviLog.Warnf("key: %s, msg: reference to the Service %s/%s is not permitted, skipping the backend", gr.key, backend.Namespace, backend.Name)
+				continue
+			}
+			if ruleBackend.Port != nil {
+				backend.Port = int32(*ruleBackend.Port)
+			}
+			backend.Weight = 1
+			if ruleBackend.Weight != nil {
+				backend.Weight = *ruleBackend.Weight
+			}
+			routeConfigRule.Backends = append(routeConfigRule.Backends, backend)
+		}
+		routeConfig.Rules = append(routeConfig.Rules, routeConfigRule)
+	}
+	gr.routeConfig = routeConfig
+	return gr.routeConfig
+}
+
+// parseGRPCMethodMatch returns the match on the /<service>/<method> path of the gRPC request.
+func parseGRPCMethodMatch(methodMatch *gatewayv1alpha2.GRPCMethodMatch) *PathMatch {
+	var service, method string
+	if methodMatch.Service != nil {
+		service = *methodMatch.Service
+	}
+	if methodMatch.Method != nil {
+		method = *methodMatch.Method
+	}
+	if methodMatch.Type != nil && *methodMatch.Type == gatewayv1alpha2.GRPCMethodMatchRegularExpression {
+		if service == "" {
+			service = "[^/]+"
+		}
+		if method == "" {
+			method = "[^/]+"
+		}
+		return &PathMatch{Path: "^/" + service + "/" + method + "$", Type: "RegularExpression"}
+	}
+	switch {
+	case service != "" && method != "":
+		return &PathMatch{Path: "/" + service + "/" + method, Type: "Exact"}
+	case service != "":
+		return &PathMatch{Path: "/" + service + "/", Type: "PathPrefix"}
+	case method != "":
+		return &PathMatch{Path: "^/[^/]+/" + regexp.QuoteMeta(method) + "$", Type: "RegularExpression"}
+	}
+	return &PathMatch{Path: "/", Type: "PathPrefix"}
+}
+
+func (gr *grpcRoute) Exists() bool {
+	return gr != nil
+}
+
+func (gr *grpcRoute) GetParents() sets.Set[string] {
+	parents := sets.New[string]()
+	for _, ref := range gr.spec.ParentRefs {
+		namespace := gr.namespace
+		if ref.Namespace != nil {
+			namespace = string(*ref.Namespace)
+		}
+		parents.Insert(namespace + "/" + string(ref.Name))
+	}
+	return parents
+}
+
+// l4Route is the RouteModel for TCPRoute, UDPRoute and TLSRoute objects. These routes
+// only carry backend references per rule, and TLSRoute additionally carries hostnames.
+type l4Route struct {
+	key         string
+	kind        string
+	name        string
+	namespace   string
+	routeConfig *RouteConfig
+	spec        interface{}
+	parentRefs  []gatewayv1.ParentReference
+	hostnames   []gatewayv1.Hostname
+	rules       [][]gatewayv1.BackendRef
+}
+
+func newL4Route(key, kind, name, namespace string) (*l4Route, error) {
+	route := &l4Route{
+		key:       key,
+		kind:      kind,
+		name:      name,
+		namespace: namespace,
+	}
+
+	informers := akogatewayapilib.AKOControlConfig().GatewayApiInformers()
+	switch kind {
+	case lib.TCPRoute:
+		obj, err := informers.TCPRouteInformer.Lister().TCPRoutes(namespace).Get(name)
+		if err != nil {
+			return route, err
+		}
+		spec := obj.Spec.DeepCopy()
+		route.spec = spec
+		route.parentRefs = spec.ParentRefs
+		for _, rule := range spec.Rules {
+			route.rules = append(route.rules, rule.BackendRefs)
+		}
+	case lib.UDPRoute:
+		obj, err := informers.UDPRouteInformer.Lister().UDPRoutes(namespace).Get(name)
+		if err != nil {
+			return route, err
+		}
+		spec := obj.Spec.DeepCopy()
+		route.spec = spec
+		route.parentRefs = spec.ParentRefs
+		for _, rule := range spec.Rules {
+			route.rules = append(route.rules, rule.BackendRefs)
+		}
+	case lib.TLSRoute:
+		obj, err := informers.TLSRouteInformer.Lister().TLSRoutes(namespace).Get(name)
+		if err != nil {
+			return route, err
+		}
+		spec := obj.Spec.DeepCopy()
+		route.spec = spec
+		route.parentRefs = spec.ParentRefs
+		route.hostnames = spec.Hostnames
+		for _, rule := range spec.Rules {
+			route.rules = append(route.rules, rule.BackendRefs)
+		}
+	default:
+		return route, fmt.Errorf("object of type %s not supported", kind)
+	}
+	return route, nil
+}
+
+func (l4 *l4Route) GetName() string {
+	return l4.name
+}
+
+func (l4 *l4Route) GetNamespace() string {
+	return l4.namespace
+}
+
+func (l4 *l4Route) GetType() string {
+	return l4.kind
+}
+
+func (l4 *l4Route) GetSpec() interface{} {
+	return l4.spec
+}
+
+func (l4 *l4Route) ParseRouteRules() *RouteConfig {
+	if l4.routeConfig != nil {
+		return l4.routeConfig
+	}
+	routeConfig := &RouteConfig{}
+
+	routeConfig.Hosts = make([]string, len(l4.hostnames))
+	for i := range l4.hostnames {
+		routeConfig.Hosts[i] = string(l4.hostnames[i])
+	}
+
+	routeConfig.Rules = make([]*Rule, 0, len(l4.rules))
+	for index, backendRefs := range l4.rules {
+		routeConfigRule := &Rule{Index: index}
+		for _, ruleBackend := range backendRefs {
+			backend := &Backend{}
+			backend.Name = string(ruleBackend.Name)
+			if ruleBackend.Namespace != nil {
+				backend.Namespace = string(*ruleBackend.Namespace)
+			} else {
+				backend.Namespace = l4.namespace
+			}
+			if !akogatewayapilib.IsReferencePermitted(l4.kind, l4.namespace, utils.Service, backend.Namespace, backend.Name) {
+				utils.AviLog.Warnf("key: %s, msg: reference to the Service %s/%s is not permitted, skipping the backend", l4.key, backend.Namespace, backend.Name)
+				continue
+			}
+			if ruleBackend.Port != nil {
+				backend.Port = int32(*ruleBackend.Port)
+			}
+			backend.Weight = 1
+			if ruleBackend.Weight != nil {
+				backend.Weight = *ruleBackend.Weight
+			}
+			routeConfigRule.Backends = append(routeConfigRule.Backends, backend)
+		}
+		routeConfig.Rules = append(routeConfig.Rules, routeConfigRule)
+	}
+	l4.routeConfig = routeConfig
+	return l4.routeConfig
+}
+
+func (l4 *l4Route) Exists() bool {
+	return l4 != nil
+}
+
+func (l4 *l4Route) GetParents() sets.Set[string] {
+	parents := sets.New[string]()
+	for _, ref := range l4.parentRefs {
+		namespace := l4.namespace
+		if ref.Namespace != nil {
+			namespace = string(*ref.Namespace)
+		}
+		parents.Insert(namespace + "/" + string(ref.Name))
+	}
+	return parents
+}
@@ -15,6 +15,7 @@
 package objects
 
 import (
+	"strings"
 	"sync"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
@@ -44,6 +45,8 @@ func GatewayApiLister() *GWLister {
 			gatewayToHostnameStore:      objects.NewObjectMapStore(),
 			routeToHostnameStore:        objects.NewObjectMapStore(),
 			gatewayRouteToHostnameStore: objects.NewObjectMapStore(),
+			backendPolicyToTarget:       objects.NewObjectMapStore(),
+			referenceGrantToFrom:        objects.NewObjectMapStore(),
 		}
 	})
 	return gwLister
@@ -103,6 +106,12 @@ type GWLister struct {
 	//FQDNs in parent VS
 	//gatewayns/gatewayname -> [hostname, ...]
 	gatewayRouteToHostnameStore *objects.ObjectMapStore
+
+	// backendPolicyNs/backendPolicyName -> targetKind/targetNs/targetName
+	backendPolicyToTarget *objects.ObjectMapStore
+
+	// referenceGrantNs/referenceGrantName -> [fromKind/fromNamespace, ...]
+	referenceGrantToFrom *objects.ObjectMapStore
 }
 
 func (g *GWLister) IsGatewayClassControllerAKO(gwClass string) (bool, bool) {
@@ -204,7 +213,10 @@ func (g *GWLister) GetGatewayToListeners(gwNsName string) []string {
 	g.gwLock.RLock()
 	defer g.gwLock.RUnlock()
 
-	_, listenerList := g.gatewayToListenerStore.Get(gwNsName)
+	found, listenerList := g.gatewayToListenerStore.Get(gwNsName)
+	if !found {
+		return []string{}
+	}
 	return listenerList.([]string)
 
 }
@@ -222,6 +234,18 @@ func (g *GWLister) GetRouteToGateway(routeTypeNsName string) (bool, []string) {
 	return false, []string{}
 }
 
+// GetRoutesInNamespace returns the routes of the route type in the namespace which are mapped to a gateway.
+func (g *GWLister) GetRoutesInNamespace(routeType, namespace string) []string {
+	prefix := routeType + "/" + namespace + "/"
+	var routeTypeNsNameList []string
+	for _, routeTypeNsName := range g.routeToGateway.GetAllKeys() {
+		if strings.HasPrefix(routeTypeNsName, prefix) {
+			routeTypeNsNameList = append(routeTypeNsNameList, routeTypeNsName)
+		}
+	}
+	return routeTypeNsNameList
+}
+
 func (g *GWLister) GetRouteToGatewayListener(routeTypeNsName string) (bool, []string) {
 	if found, obj := g.routeToGatewayListener.Get(routeTypeNsName); found {
 		return true, obj.([]string)
@@ -625,8 +649,10 @@ func (g *GWLister) GetGatewayListenerToHostname(gwNsName, listner string) string
 	defer g.gwLock.RUnlock()
 
 	key := gwNsName + "/" + listner
-	_, obj := g.gatewayToHostnameStore.Get(key)
-
+	found, obj := g.gatewayToHostnameStore.Get(key)
+	if !found {
+		return ""
+	}
 	return obj.(string)
 }
 func (g *GWLister) UpdateGatewayListenerToHostname(gwListenerNsName, hostname string) {
@@ -655,3 +681,57 @@ func (g *GWLister) GetGatewayRouteToHostname(ns, gw string) (bool, []string) {
 	}
 	return false, make([]string, 0)
 }
+
+//=====All backend policy <-> target go here.
+
+func (g *GWLister) GetBackendPolicyToTarget(backendPolicyNsName string) (bool, string) {
+	g.gwLock.RLock()
+	defer g.gwLock.RUnlock()
+
+	found, obj := g.backendPolicyToTarget.Get(backendPolicyNsName)
+	if !found {
+		return false, ""
+	}
+	return true, obj.(string)
+}
+
+func (g *GWLister) UpdateBackendPolicyToTarget(backendPolicyNsName, targetKindNsName string) {
+	g.gwLock.Lock()
+	defer g.gwLock.Unlock()
+
+	g.backendPolicyToTarget.AddOrUpdate(backendPolicyNsName, targetKindNsName)
+}
+
+func (g *GWLister) DeleteBackendPolicyToTarget(backendPolicyNsName string) {
+	g.gwLock.Lock()
+	defer g.gwLock.Unlock()
+
+	g.backendPolicyToTarget.Delete(backendPolicyNsName)
+}
+
+//=====All reference grant <-> from go here.
+
+func (g *GWLister) GetReferenceGrantToFrom(referenceGrantNsName string) (bool, []string) {
+	g.gwLock.RLock()
+	defer g.gwLock.RUnlock()
+
+	found, obj := g.referenceGrantToFrom.Get(referenceGrantNsName)
+	if !found {
+		return false, []string{}
+	}
+	return true, obj.([]string)
+}
+
+func (g *GWLister) UpdateReferenceGrantToFrom(referenceGrantNsName string, fromKindNsList []string) {
+	g.gwLock.Lock()
+	defer g.gwLock.Unlock()
+
+	g.referenceGrantToFrom.AddOrUpdate(referenceGrantNsName, fromKindNsList)
+}
+
+func (g *GWLister) DeleteReferenceGrantToFrom(referenceGrantNsName string) {
+	g.gwLock.Lock()
+	defer g.gwLock.Unlock()
+
+	g.referenceGrantToFrom.Delete(referenceGrantNsName)
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/status"
+	akov1alpha1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha1"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+)
+
+type backendpolicy struct{}
+
+func (o *backendpolicy) Get(key string, name string, namespace string) *akov1alpha1.BackendPolicy {
+
+	obj, err := akogatewayapilib.AKOControlConfig().AKOCRDInformers().BackendPolicyInformer.Lister().BackendPolicies(namespace).Get(name)
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to get the BackendPolicy object. err: %s", key, err)
+		return nil
+	}
+	utils.AviLog.Debugf("key: %s, msg: Successfully retrieved the BackendPolicy object %s", key, name)
+	return obj.DeepCopy()
+}
+
+func (o *backendpolicy) Delete(key string, option status.StatusOptions) {
+	// The status of a BackendPolicy is only recorded at the ingestion layer
+}
+
+func (o *backendpolicy) Update(key string, option status.StatusOptions) {
+	// The status of a BackendPolicy is only recorded at the ingestion layer
+}
+
+func (o *backendpolicy) BulkUpdate(key string, options []status.StatusOptions) {
+	// The status of a BackendPolicy is only recorded at the ingestion layer
+}
+
+func (o *backendpolicy) Patch(key string, obj runtime.Object, status *Status, retryNum ...int) {
+	retry := 0
+	if len(retryNum) > 0 {
+		retry = retryNum[0]
+		if retry >= 5 {
+			utils.AviLog.Errorf("key: %s, msg: Patch retried 5 times, aborting", key)
+			return
+		}
+	}
+
+	backendPolicy := obj.(*akov1alpha1.BackendPolicy)
+	if o.isStatusEqual(&backendPolicy.Status, status.BackendPolicyStatus) {
+		return
+	}
+
+	patchPayload, _ := json.Marshal(map[string]interface{}{
+		"status": status.BackendPolicyStatus,
+	})
+	_, err := akogatewayapilib.AKOControlConfig().AKOCRDClientset().AkoV1alpha1().BackendPolicies(backendPolicy.Namespace).Patch(context.TODO(), backendPolicy.Name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: there was an error in updating the BackendPolicy status. err: %+v, retry: %d", key, err, retry)
+		updatedObj, err := akogatewayapilib.AKOControlConfig().AKOCRDInformers().BackendPolicyInformer.Lister().BackendPolicies(backendPolicy.Namespace).Get(backendPolicy.Name)
+		if err != nil {
+			utils.AviLog.Warnf("BackendPolicy not found %v", err)
+			return
+		}
+		o.Patch(key, updatedObj, status, retry+1)
+		return
+	}
+
+	utils.AviLog.Infof("key: %s, msg: Successfully updated the BackendPolicy %s/%s status %+v", key, backendPolicy.Namespace, backendPolicy.Name, utils.Stringify(status))
+}
+
+func (o *backendpolicy) isStatusEqual(old, new *akov1alpha1.BackendPolicyStatus) bool {
+	oldStatus, newStatus := old.DeepCopy(), new.DeepCopy()
+	currentTime := metav1.Now()
+	for i := range oldStatus.Conditions {
+		oldStatus.Conditions[i].LastTransitionTime = currentTime
+	}
+	for i := range newStatus.Conditions {
+		newStatus.Conditions[i].LastTransitionTime = currentTime
+	}
+	return reflect.DeepEqual(oldStatus, newStatus)
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/status"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+)
+
+type backendtlspolicy struct{}
+
+func (o *backendtlspolicy) Get(key string, name string, namespace string) *gatewayv1alpha2.BackendTLSPolicy {
+
+	obj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().BackendTLSPolicyInformer.Lister().BackendTLSPolicies(namespace).Get(name)
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to get the BackendTLSPolicy object. err: %s", key, err)
+		return nil
+	}
+	utils.AviLog.Debugf("key: %s, msg: Successfully retrieved the BackendTLSPolicy object %s", key, name)
+	return obj.DeepCopy()
+}
+
+func (o *backendtlspolicy) Delete(key string, option status.StatusOptions) {
+	// The status of a BackendTLSPolicy is only recorded at the ingestion layer
+}
+
+func (o *backendtlspolicy) Update(key string, option status.StatusOptions) {
+	// The status of a BackendTLSPolicy is only recorded at the ingestion layer
+}
+
+func (o *backendtlspolicy) BulkUpdate(key string, options []status.StatusOptions) {
+	// The status of a BackendTLSPolicy is only recorded at the ingestion layer
+}
+
+func (o *backendtlspolicy) Patch(key string, obj runtime.Object, status *Status, retryNum ...int) {
+	retry := 0
+	if len(retryNum) > 0 {
+		retry = retryNum[0]
+		if retry >= 5 {
+			utils.AviLog.Errorf("key: %s, msg: Patch retried 5 times, aborting", key)
+			return
+		}
+	}
+
+	backendTLSPolicy := obj.(*gatewayv1alpha2.BackendTLSPolicy)
+	if o.isStatusEqual(&backendTLSPolicy.Status, status.PolicyStatus) {
+		return
+	}
+
+	patchPayload, _ := json.Marshal(map[string]interface{}{
+		"status": status.PolicyStatus,
+	})
+	_, err := akogatewayapilib.AKOControlConfig().GatewayAPIClientset().GatewayV1alpha2().BackendTLSPolicies(backendTLSPolicy.Namespace).Patch(context.TODO(), backendTLSPolicy.Name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: there was an error in updating the BackendTLSPolicy status. err: %+v, retry: %d", key, err, retry)
+		updatedObj, err := akogatewayapilib.AKOControlConfig().GatewayApiInformers().BackendTLSPolicyInformer.Lister().BackendTLSPolicies(backendTLSPolicy.Namespace).Get(backendTLSPolicy.Name)
+		if err != nil {
+			utils.AviLog.Warnf("BackendTLSPolicy not found %v", err)
+			return
+		}
+		o.Patch(key, updatedObj, status, retry+1)
+		return
+	}
+
+	utils.AviLog.Infof("key: %s, msg: Successfully updated the BackendTLSPolicy %s/%s status %+v", key, backendTLSPolicy.Namespace, backendTLSPolicy.Name, utils.Stringify(status))
+}
+
+func (o *backendtlspolicy) isStatusEqual(old, new *gatewayv1alpha2.PolicyStatus) bool {
+	oldStatus, newStatus := old.DeepCopy(), new.DeepCopy()
+	currentTime := metav1.Now()
+	for i := range oldStatus.Ancestors {
+		for j := range oldStatus.Ancestors[i].Conditions {
+			oldStatus.Ancestors[i].Conditions[j].LastTransitionTime = currentTime
+		}
+	}
+	for i := range newStatus.Ancestors {
+		for j := range newStatus.Ancestors[i].Conditions {
+			newStatus.Ancestors[i].Conditions[j].LastTransitionTime = currentTime
+		}
+	}
+	return reflect.DeepEqual(oldStatus, newStatus)
+}
@@ -102,7 +102,6 @@ func (o *gateway) Delete(key string, option status.StatusOptions) {
 		return
 	}
 
-	// assuming 1 IP per gateway
 	status := gw.Status.DeepCopy()
 	status.Addresses = []gatewayv1.GatewayStatusAddress{}
 
@@ -139,11 +138,7 @@ func (o *gateway) Update(key string, option status.StatusOptions) {
 	}
 
 	status := gw.Status.DeepCopy()
-	addressType := gatewayv1.IPAddressType
-	status.Addresses = append(status.Addresses, gatewayv1.GatewayStatusAddress{
-		Type:  &addressType,
-		Value: option.Options.Vip[0],
-	})
+	status.Addresses = getGatewayStatusAddresses(option.Options.Vip)
 
 	// TODO: Add a way to propagate the error from the Rest layer to status layer.
 
@@ -179,11 +174,7 @@ func (o *gateway) BulkUpdate(key string, options []status.StatusOptions) {
 		nsName := option.Options.ServiceMetadata.Gateway
 		if gw, ok := gwMap[nsName]; ok {
 			status := &gatewayv1.GatewayStatus{}
-			addressType := gatewayv1.IPAddressType
-			status.Addresses = append(status.Addresses, gatewayv1.GatewayStatusAddress{
-				Type:  &addressType,
-				Value: option.Options.Vip[0],
-			})
+			status.Addresses = getGatewayStatusAddresses(option.Options.Vip)
 			apimeta.SetStatusCondition(&status.Conditions, metav1.Condition{
 				Type:               string(gatewayv1.GatewayConditionProgrammed),
 				Status:             metav1.ConditionTrue,
@@ -252,3 +243,19 @@ func (o *gateway) isStatusEqual(old, new *gatewayv1.GatewayStatus) bool {
 	}
 	return reflect.DeepEqual(oldStatus, newStatus)
 }
+
+// getGatewayStatusAddresses returns all the IPv4 and IPv6 addresses assigned to the VsVip.
+func getGatewayStatusAddresses(vips []string) []gatewayv1.GatewayStatusAddress {
+	addresses := make([]gatewayv1.GatewayStatusAddress, 0, len(vips))
+	for _, vip := range vips {
+		if vip == "" {
+			continue
+		}
+		addressType := gatewayv1.IPAddressType
+		addresses = append(addresses, gatewayv1.GatewayStatusAddress{
+			Type:  &addressType,
+			Value: vip,
+		})
+	}
+	return addresses
+}
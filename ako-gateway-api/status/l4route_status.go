@@ -0,0 +1,124 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/status"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+)
+
+// l4route updates the status of the TCPRoute, UDPRoute and TLSRoute objects, identified by kind.
+type l4route struct {
+	kind string
+}
+
+func (o *l4route) Get(key string, name string, namespace string) runtime.Object {
+	var obj runtime.Object
+	var err error
+	informers := akogatewayapilib.AKOControlConfig().GatewayApiInformers()
+	switch o.kind {
+	case lib.TCPRoute:
+		obj, err = informers.TCPRouteInformer.Lister().TCPRoutes(namespace).Get(name)
+	case lib.UDPRoute:
+		obj, err = informers.UDPRouteInformer.Lister().UDPRoutes(namespace).Get(name)
+	case lib.TLSRoute:
+		obj, err = informers.TLSRouteInformer.Lister().TLSRoutes(namespace).Get(name)
+	}
+	if err != nil || obj == nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to get the %s object. err: %v", key, o.kind, err)
+		return nil
+	}
+	utils.AviLog.Debugf("key: %s, msg: Successfully retrieved the %s object %s", key, o.kind, name)
+	return obj.DeepCopyObject()
+}
+
+func (o *l4route) Delete(key string, option status.StatusOptions) {
+	// TODO: Add this code when we publish the status from the rest layer
+}
+
+func (o *l4route) Update(key string, option status.StatusOptions) {
+	// TODO: Add this code when we publish the status from the rest layer
+}
+
+func (o *l4route) BulkUpdate(key string, options []status.StatusOptions) {
+	// TODO: Add this code when we publish the status from the rest layer
+}
+
+func (o *l4route) Patch(key string, obj runtime.Object, status *Status, retryNum ...int) {
+	retry := 0
+	if len(retryNum) > 0 {
+		retry = retryNum[0]
+		if retry >= 5 {
+			utils.AviLog.Errorf("key: %s, msg: Patch retried 5 times, aborting", key)
+			return
+		}
+	}
+
+	var namespace, name string
+	var currentStatus, newStatus *gatewayv1alpha2.RouteStatus
+	var newRouteStatus interface{}
+	switch route := obj.(type) {
+	case *gatewayv1alpha2.TCPRoute:
+		namespace, name = route.Namespace, route.Name
+		currentStatus, newStatus, newRouteStatus = &route.Status.RouteStatus, &status.TCPRouteStatus.RouteStatus, status.TCPRouteStatus
+	case *gatewayv1alpha2.UDPRoute:
+		namespace, name = route.Namespace, route.Name
+		currentStatus, newStatus, newRouteStatus = &route.Status.RouteStatus, &status.UDPRouteStatus.RouteStatus, status.UDPRouteStatus
+	case *gatewayv1alpha2.TLSRoute:
+		namespace, name = route.Namespace, route.Name
+		currentStatus, newStatus, newRouteStatus = &route.Status.RouteStatus, &status.TLSRouteStatus.RouteStatus, status.TLSRouteStatus
+	default:
+		utils.AviLog.Warnf("key: %s, msg: unsupported object %T received for the %s status", key, obj, o.kind)
+		return
+	}
+	if isRouteStatusEqual(currentStatus, newStatus) {
+		return
+	}
+
+	patchPayload, _ := json.Marshal(map[string]interface{}{
+		"status": newRouteStatus,
+	})
+	clientset := akogatewayapilib.AKOControlConfig().GatewayAPIClientset().GatewayV1alpha2()
+	var err error
+	switch o.kind {
+	case lib.TCPRoute:
+		_, err = clientset.TCPRoutes(namespace).Patch(context.TODO(), name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
+	case lib.UDPRoute:
+		_, err = clientset.UDPRoutes(namespace).Patch(context.TODO(), name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
+	case lib.TLSRoute:
+		_, err = clientset.TLSRoutes(namespace).Patch(context.TODO(), name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
+	}
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: there was an error in updating the %s status. err: %+v, retry: %d", key, o.kind, err, retry)
+		updatedObj := o.Get(key, name, namespace)
+		if updatedObj == nil {
+			return
+		}
+		o.Patch(key, updatedObj, status, retry+1)
+		return
+	}
+
+	utils.AviLog.Infof("key: %s, msg: Successfully updated the %s %s/%s status %+v", key, o.kind, namespace, name, utils.Stringify(status))
+}
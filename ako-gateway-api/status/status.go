@@ -15,11 +15,16 @@
 package status
 
 import (
+	"reflect"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/status"
+	akov1alpha1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha1"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 )
 
@@ -34,6 +39,12 @@ type Status struct {
 	*gatewayv1.GatewayClassStatus
 	*gatewayv1.GatewayStatus
 	*gatewayv1.HTTPRouteStatus
+	*gatewayv1alpha2.TCPRouteStatus
+	*gatewayv1alpha2.UDPRouteStatus
+	*gatewayv1alpha2.TLSRouteStatus
+	*gatewayv1alpha2.GRPCRouteStatus
+	*akov1alpha1.BackendPolicyStatus
+	*gatewayv1alpha2.PolicyStatus
 }
 
 func New(ObjectType string) StatusUpdater {
@@ -44,6 +55,14 @@ func New(ObjectType string) StatusUpdater {
 		return &gateway{}
 	case lib.HTTPRoute:
 		return &httproute{}
+	case lib.TCPRoute, lib.UDPRoute, lib.TLSRoute:
+		return &l4route{kind: ObjectType}
+	case lib.GRPCRoute:
+		return &grpcroute{}
+	case lib.BackendPolicy:
+		return &backendpolicy{}
+	case lib.BackendTLSPolicy:
+		return &backendtlspolicy{}
 	}
 	return nil
 }
@@ -85,6 +104,18 @@ func Record(key string, obj runtime.Object, status *Status) {
 		objectType = lib.Gateway
 	case *gatewayv1.HTTPRoute:
 		objectType = lib.HTTPRoute
+	case *gatewayv1alpha2.TCPRoute:
+		objectType = lib.TCPRoute
+	case *gatewayv1alpha2.UDPRoute:
+		objectType = lib.UDPRoute
+	case *gatewayv1alpha2.TLSRoute:
+		objectType = lib.TLSRoute
+	case *gatewayv1alpha2.GRPCRoute:
+		objectType = lib.GRPCRoute
+	case *akov1alpha1.BackendPolicy:
+		objectType = lib.BackendPolicy
+	case *gatewayv1alpha2.BackendTLSPolicy:
+		objectType = lib.BackendTLSPolicy
 	default:
 		utils.AviLog.Warnf("key %s, msg: Unsupported object received at the status layer, %T", key, obj)
 		return
@@ -92,3 +123,20 @@ func Record(key string, obj runtime.Object, status *Status) {
 	o := New(objectType)
 	o.Patch(key, obj, status)
 }
+
+// isRouteStatusEqual compares the route parent statuses ignoring the condition transition times.
+func isRouteStatusEqual(old, new *gatewayv1.RouteStatus) bool {
+	oldStatus, newStatus := old.DeepCopy(), new.DeepCopy()
+	currentTime := metav1.Now()
+	for i := range oldStatus.Parents {
+		for j := range oldStatus.Parents[i].Conditions {
+			oldStatus.Parents[i].Conditions[j].LastTransitionTime = currentTime
+		}
+	}
+	for i := range newStatus.Parents {
+		for j := range newStatus.Parents[i].Conditions {
+			newStatus.Parents[i].Conditions[j].LastTransitionTime = currentTime
+		}
+	}
+	return reflect.DeepEqual(oldStatus, newStatus)
+}
@@ -30,6 +30,7 @@ import (
 	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/tracing"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api/models"
 	crd "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha1/clientset/versioned"
@@ -274,6 +275,10 @@ func InitializeAKC() {
 	if lib.IsPrometheusEnabled() {
 		lib.RegisterPromMetrics()
 	}
+	if err := tracing.Init(lib.GetOTLPTracesEndpoint()); err != nil {
+		utils.AviLog.Warnf("Unable to set up the export of the sync traces: %v", err)
+	}
+	defer tracing.Shutdown()
 	if aviRestClientPool != nil && !avicache.IsAviClusterActive(aviRestClientPool.AviClient[0]) {
 		akoControlConfig.PodEventf(corev1.EventTypeWarning, lib.AKOShutdown, "Avi Controller Cluster state is not Active")
 		utils.AviLog.Fatalf("Avi Controller Cluster state is not Active, shutting down AKO")
@@ -306,6 +311,11 @@ func InitializeAKC() {
 
 	c.InitializeNamespaceSync()
 	k8s.PopulateNodeCache(kubeClient)
+	if lib.IsValidatingWebhookEnabled() {
+		webhookApi := api.NewWebhookServer(lib.GetWebhookPort(), lib.WebhookCertFile, lib.WebhookKeyFile, []models.ApiModel{&k8s.ValidatingWebhook{}})
+		webhookApi.InitApi()
+		defer webhookApi.ShutDown()
+	}
 	waitGroupMap := make(map[string]*sync.WaitGroup)
 	wgIngestion := &sync.WaitGroup{}
 	waitGroupMap["ingestion"] = wgIngestion
@@ -33,6 +33,7 @@ import (
 	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	akocrd "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha1/clientset/versioned"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 )
 
@@ -90,6 +91,12 @@ func Initialize() {
 	}
 	akoControlConfig.SetGatewayAPIClientset(gwApiClient)
 
+	akoCRDClient, err := akocrd.NewForConfig(cfg)
+	if err != nil {
+		utils.AviLog.Fatalf("Error building AKO CRD clientset: %s", err.Error())
+	}
+	akoControlConfig.SetAKOCRDClientset(akoCRDClient)
+
 	kubeClient, err := kubernetes.NewForConfig(cfg)
 	if err != nil {
 		utils.AviLog.Fatalf("Error building kubernetes clientset: %s", err.Error())
@@ -115,7 +122,8 @@ func Initialize() {
 
 	informers := k8s.K8sinformers{Cs: kubeClient}
 	c := akogatewayk8s.SharedGatewayController()
-	c.InitGatewayAPIInformers(gwApiClient)
+	c.InitGatewayAPIInformers(gwApiClient, kubeClient)
+	c.InitAKOCRDInformers(akoCRDClient)
 	stopCh := utils.SetupSignalHandler()
 	ctrlCh := make(chan struct{})
 	quickSyncCh := make(chan struct{})
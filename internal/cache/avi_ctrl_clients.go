@@ -15,16 +15,31 @@
 package cache
 
 import (
+	"sync"
+
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api/models"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/third_party/github.com/vmware/alb-sdk/go/clients"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/third_party/github.com/vmware/alb-sdk/go/session"
 )
 
 var AviClientInstance *utils.AviRestClientPool
 
+// AviConcurrentClientInstance is the pool of clients on which the parallel rest operations of the rest workers
+// are sent, when REST_CONCURRENCY is more than 1. The clients of AviClientInstance are in use by their rest
+// workers, and an AviSession is not safe for concurrent use, so they are never shared with other goroutines.
+var AviConcurrentClientInstance *utils.AviRestClientPool
+var concurrentClients chan *clients.AviClient
+var concurrentClientsLock sync.Mutex
+
+// AviDriftClientInstance is the client with which the drift detection fetches the Avi objects from the controller,
+// so that it does not share the session of a rest worker.
+var AviDriftClientInstance *utils.AviRestClientPool
+var driftClientLock sync.Mutex
+
 // This class is in control of AKC. It uses utils from the common project.
 func SharedAVIClients() *utils.AviRestClientPool {
 	var err error
@@ -90,3 +105,97 @@ func SharedAVIClients() *utils.AviRestClientPool {
 	models.RestStatus.UpdateAviApiRestStatus(connectionStatus, err)
 	return AviClientInstance
 }
+
+// newAVIClientPool creates a pool of num clients with the credentials in avi-secret, for the tenant and
+// the controller version of AKO.
+func newAVIClientPool(num uint32) (*utils.AviRestClientPool, error) {
+	ctrlProp := utils.SharedCtrlProp().GetAllCtrlProp()
+	ctrlVersion := lib.AKOControlConfig().ControllerVersion()
+	clientPool, _, err := utils.NewAviRestClientPool(
+		num,
+		lib.GetControllerIP(),
+		ctrlProp[utils.ENV_CTRL_USERNAME],
+		ctrlProp[utils.ENV_CTRL_PASSWORD],
+		ctrlProp[utils.ENV_CTRL_AUTHTOKEN],
+		ctrlVersion,
+		ctrlProp[utils.ENV_CTRL_CADATA],
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, client := range clientPool.AviClient {
+		SetTenant := session.SetTenant(lib.GetTenant())
+		SetTenant(client.AviSession)
+		if ctrlVersion != "" {
+			SetVersion := session.SetVersion(ctrlVersion)
+			SetVersion(client.AviSession)
+		}
+	}
+	return clientPool, nil
+}
+
+// SharedAVIDriftClient returns the client of the drift detection, which is created on first use.
+func SharedAVIDriftClient() *clients.AviClient {
+	driftClientLock.Lock()
+	defer driftClientLock.Unlock()
+	if AviDriftClientInstance == nil || len(AviDriftClientInstance.AviClient) == 0 {
+		clientPool, err := newAVIClientPool(1)
+		if err != nil {
+			utils.AviLog.Warnf("Creation of the client for the drift detection failed: %v", err)
+			return nil
+		}
+		AviDriftClientInstance = clientPool
+	}
+	return AviDriftClientInstance.AviClient[0]
+}
+
+// AcquireAVIConcurrentClients takes at most num clients of the concurrent client pool which are not in use.
+// The pool is created with REST_CONCURRENCY clients on first use. The clients must be given back with
+// ReleaseAVIConcurrentClients once the rest operations sent on them are done.
+func AcquireAVIConcurrentClients(num int) []*clients.AviClient {
+	concurrentClientsLock.Lock()
+	if AviConcurrentClientInstance == nil || len(AviConcurrentClientInstance.AviClient) == 0 {
+		clientPool, err := newAVIClientPool(uint32(lib.GetRestConcurrency()))
+		if err != nil {
+			concurrentClientsLock.Unlock()
+			utils.AviLog.Warnf("Creation of the clients for the concurrent rest operations failed: %v", err)
+			return nil
+		}
+		AviConcurrentClientInstance = clientPool
+		concurrentClients = make(chan *clients.AviClient, len(clientPool.AviClient))
+		for _, client := range clientPool.AviClient {
+			concurrentClients <- client
+		}
+	}
+	pool := concurrentClients
+	concurrentClientsLock.Unlock()
+
+	var acquired []*clients.AviClient
+	for len(acquired) < num {
+		select {
+		case client := <-pool:
+			acquired = append(acquired, client)
+		default:
+			return acquired
+		}
+	}
+	return acquired
+}
+
+// ReleaseAVIConcurrentClients gives back the clients taken with AcquireAVIConcurrentClients.
+func ReleaseAVIConcurrentClients(acquired []*clients.AviClient) {
+	concurrentClientsLock.Lock()
+	defer concurrentClientsLock.Unlock()
+	if AviConcurrentClientInstance == nil {
+		return
+	}
+	for _, client := range acquired {
+		// The clients of a pool which has been created again in the meantime are dropped.
+		for _, poolClient := range AviConcurrentClientInstance.AviClient {
+			if client == poolClient {
+				concurrentClients <- client
+				break
+			}
+		}
+	}
+}
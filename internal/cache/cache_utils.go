@@ -78,6 +78,9 @@ type AviVsCache struct {
 	HTTPKeyCollection    []NamespaceName
 	SSLKeyCertCollection []NamespaceName
 	L4PolicyCollection   []NamespaceName
+	NSPCollection        []NamespaceName
+	AppProfileCollection []NamespaceName
+	CloneKeyCollection   []NamespaceName
 	SNIChildCollection   []string
 	ParentVSRef          NamespaceName
 	PassthroughParentRef NamespaceName
@@ -247,6 +250,54 @@ func (v *AviVsCache) RemoveFromL4PolicyCollection(k NamespaceName) {
 	v.L4PolicyCollection = RemoveNamespaceName(v.L4PolicyCollection, k)
 }
 
+func (v *AviVsCache) AddToNSPCollection(k NamespaceName) {
+	if v.NSPCollection == nil {
+		v.NSPCollection = []NamespaceName{k}
+	}
+	if !utils.HasElem(v.NSPCollection, k) {
+		v.NSPCollection = append(v.NSPCollection, k)
+	}
+}
+
+func (v *AviVsCache) RemoveFromNSPCollection(k NamespaceName) {
+	if v.NSPCollection == nil {
+		return
+	}
+	v.NSPCollection = RemoveNamespaceName(v.NSPCollection, k)
+}
+
+func (v *AviVsCache) AddToAppProfileCollection(k NamespaceName) {
+	if v.AppProfileCollection == nil {
+		v.AppProfileCollection = []NamespaceName{k}
+	}
+	if !utils.HasElem(v.AppProfileCollection, k) {
+		v.AppProfileCollection = append(v.AppProfileCollection, k)
+	}
+}
+
+func (v *AviVsCache) RemoveFromAppProfileCollection(k NamespaceName) {
+	if v.AppProfileCollection == nil {
+		return
+	}
+	v.AppProfileCollection = RemoveNamespaceName(v.AppProfileCollection, k)
+}
+
+func (v *AviVsCache) AddToCloneKeyCollection(k NamespaceName) {
+	if v.CloneKeyCollection == nil {
+		v.CloneKeyCollection = []NamespaceName{k}
+	}
+	if !utils.HasElem(v.CloneKeyCollection, k) {
+		v.CloneKeyCollection = append(v.CloneKeyCollection, k)
+	}
+}
+
+func (v *AviVsCache) RemoveFromCloneKeyCollection(k NamespaceName) {
+	if v.CloneKeyCollection == nil {
+		return
+	}
+	v.CloneKeyCollection = RemoveNamespaceName(v.CloneKeyCollection, k)
+}
+
 func (v *AviVsCache) AddToSNIChildCollection(k string) {
 	if v.SNIChildCollection == nil {
 		v.SNIChildCollection = []string{k}
@@ -343,6 +394,37 @@ type AviL4PolicyCache struct {
 	HasReference     bool
 }
 
+type AviNetworkSecurityPolicyCache struct {
+	Name             string
+	Tenant           string
+	Uuid             string
+	CloudConfigCksum uint32
+	LastModified     string
+	HasReference     bool
+}
+
+// AviAppProfileCache is the application profile that AKO manages for a VS, with the
+// PKI profile that AKO created for it from the CA bundle of a Secret, if any.
+type AviAppProfileCache struct {
+	Name                 string
+	Tenant               string
+	Uuid                 string
+	CloudConfigCksum     uint32
+	PkiProfileCollection NamespaceName
+	LastModified         string
+	HasReference         bool
+}
+
+// AviTrafficCloneProfileCache is the traffic clone profile that AKO manages for a VS.
+type AviTrafficCloneProfileCache struct {
+	Name             string
+	Tenant           string
+	Uuid             string
+	CloudConfigCksum uint32
+	LastModified     string
+	HasReference     bool
+}
+
 type AviVrfCache struct {
 	Name             string
 	Uuid             string
@@ -488,6 +570,24 @@ func (c *AviCache) AviCacheGetNameByUuid(uuid string) (interface{}, bool) {
 			} else if value.(*AviHTTPPolicyCache).Uuid == uuid {
 				return value.(*AviHTTPPolicyCache).Name, true
 			}
+		case *AviNetworkSecurityPolicyCache:
+			if value.(*AviNetworkSecurityPolicyCache) == nil {
+				utils.AviLog.Warnf("Got nil value in cache for network security policy key %v", reflect.ValueOf(key))
+			} else if value.(*AviNetworkSecurityPolicyCache).Uuid == uuid {
+				return value.(*AviNetworkSecurityPolicyCache).Name, true
+			}
+		case *AviAppProfileCache:
+			if value.(*AviAppProfileCache) == nil {
+				utils.AviLog.Warnf("Got nil value in cache for application profile key %v", reflect.ValueOf(key))
+			} else if value.(*AviAppProfileCache).Uuid == uuid {
+				return value.(*AviAppProfileCache).Name, true
+			}
+		case *AviTrafficCloneProfileCache:
+			if value.(*AviTrafficCloneProfileCache) == nil {
+				utils.AviLog.Warnf("Got nil value in cache for traffic clone profile key %v", reflect.ValueOf(key))
+			} else if value.(*AviTrafficCloneProfileCache).Uuid == uuid {
+				return value.(*AviTrafficCloneProfileCache).Name, true
+			}
 		case *AviPGCache:
 			if value.(*AviPGCache) == nil {
 				utils.AviLog.Warnf("Got nil value in cache for PG key %v", reflect.ValueOf(key))
@@ -47,6 +47,9 @@ type AviObjCache struct {
 	CloudKeyCache      *AviCache
 	HTTPPolicyCache    *AviCache
 	L4PolicyCache      *AviCache
+	NSPCache           *AviCache
+	AppProfileCache    *AviCache
+	CloneProfileCache  *AviCache
 	SSLKeyCache        *AviCache
 	PKIProfileCache    *AviCache
 	VSVIPCache         *AviCache
@@ -67,6 +70,9 @@ func NewAviObjCache() *AviObjCache {
 	c.CloudKeyCache = NewAviCache()
 	c.HTTPPolicyCache = NewAviCache()
 	c.L4PolicyCache = NewAviCache()
+	c.NSPCache = NewAviCache()
+	c.AppProfileCache = NewAviCache()
+	c.CloneProfileCache = NewAviCache()
 	c.VSVIPCache = NewAviCache()
 	c.VrfCache = NewAviCache()
 	c.PKIProfileCache = NewAviCache()
@@ -97,6 +103,8 @@ func (c *AviObjCache) AviRefreshObjectCache(client []*clients.AviClient, cloud s
 		c.PopulateVsVipDataToCache(client[7], cloud)
 	}()
 	c.PopulatePkiProfilesToCache(client[0])
+	c.PopulateAppProfilesToCache(client[0], cloud)
+	c.PopulateTrafficCloneProfilesToCache(client[0], cloud)
 	c.PopulatePoolsToCache(client[1], cloud)
 	c.PopulatePgDataToCache(client[2], cloud)
 
@@ -112,6 +120,7 @@ func (c *AviObjCache) AviRefreshObjectCache(client []*clients.AviClient, cloud s
 	go func() {
 		defer wg.Done()
 		c.PopulateL4PolicySetToCache(client[6], cloud)
+		c.PopulateNetworkSecurityPolicyToCache(client[6], cloud)
 	}()
 
 	wg.Wait()
@@ -264,6 +273,30 @@ func (c *AviObjCache) MarkReference(vsCacheObj *AviVsCache) {
 		}
 	}
 
+	for _, objKey := range vsCacheObj.NSPCollection {
+		if intf, found := c.NSPCache.AviCacheGet(objKey); found {
+			if obj, ok := intf.(*AviNetworkSecurityPolicyCache); ok {
+				obj.HasReference = true
+			}
+		}
+	}
+
+	for _, objKey := range vsCacheObj.AppProfileCollection {
+		if intf, found := c.AppProfileCache.AviCacheGet(objKey); found {
+			if obj, ok := intf.(*AviAppProfileCache); ok {
+				obj.HasReference = true
+			}
+		}
+	}
+
+	for _, objKey := range vsCacheObj.CloneKeyCollection {
+		if intf, found := c.CloneProfileCache.AviCacheGet(objKey); found {
+			if obj, ok := intf.(*AviTrafficCloneProfileCache); ok {
+				obj.HasReference = true
+			}
+		}
+	}
+
 	for _, objKey := range vsCacheObj.PGKeyCollection {
 		if intf, found := c.PgCache.AviCacheGet(objKey); found {
 			if obj, ok := intf.(*AviPGCache); ok {
@@ -302,7 +335,7 @@ func (c *AviObjCache) MarkReference(vsCacheObj *AviVsCache) {
 func (c *AviObjCache) DeleteUnmarked(childCollection []string) {
 
 	var dsKeys, vsVipKeys, httpKeys, sslKeys []NamespaceName
-	var pgKeys, poolKeys, l4Keys []NamespaceName
+	var pgKeys, poolKeys, l4Keys, nspKeys, appProfileKeys, cloneKeys []NamespaceName
 	for _, objkey := range c.DSCache.AviGetAllKeys() {
 		intf, _ := c.DSCache.AviCacheGet(objkey)
 		if obj, ok := intf.(*AviDSCache); ok {
@@ -333,6 +366,36 @@ func (c *AviObjCache) DeleteUnmarked(childCollection []string) {
 		}
 	}
 
+	for _, objkey := range c.NSPCache.AviGetAllKeys() {
+		intf, _ := c.NSPCache.AviCacheGet(objkey)
+		if obj, ok := intf.(*AviNetworkSecurityPolicyCache); ok {
+			if obj.HasReference == false {
+				utils.AviLog.Infof("Reference Not found for network security policy: %s", objkey)
+				nspKeys = append(nspKeys, objkey)
+			}
+		}
+	}
+
+	for _, objkey := range c.AppProfileCache.AviGetAllKeys() {
+		intf, _ := c.AppProfileCache.AviCacheGet(objkey)
+		if obj, ok := intf.(*AviAppProfileCache); ok {
+			if obj.HasReference == false {
+				utils.AviLog.Infof("Reference Not found for application profile: %s", objkey)
+				appProfileKeys = append(appProfileKeys, objkey)
+			}
+		}
+	}
+
+	for _, objkey := range c.CloneProfileCache.AviGetAllKeys() {
+		intf, _ := c.CloneProfileCache.AviCacheGet(objkey)
+		if obj, ok := intf.(*AviTrafficCloneProfileCache); ok {
+			if obj.HasReference == false {
+				utils.AviLog.Infof("Reference Not found for traffic clone profile: %s", objkey)
+				cloneKeys = append(cloneKeys, objkey)
+			}
+		}
+	}
+
 	for _, objkey := range c.PgCache.AviGetAllKeys() {
 		intf, _ := c.PgCache.AviCacheGet(objkey)
 		if obj, ok := intf.(*AviPGCache); ok {
@@ -393,6 +456,9 @@ func (c *AviObjCache) DeleteUnmarked(childCollection []string) {
 		PGKeyCollection:      pgKeys,
 		PoolKeyCollection:    poolKeys,
 		L4PolicyCollection:   l4Keys,
+		NSPCollection:        nspKeys,
+		AppProfileCollection: appProfileKeys,
+		CloneKeyCollection:   cloneKeys,
 		SNIChildCollection:   childCollection,
 	}
 	vsKey := NamespaceName{
@@ -545,6 +611,9 @@ func (c *AviObjCache) AviPopulateAllPkiPRofiles(client *clients.AviClient, pkiDa
 			Tenant:           lib.GetTenant(),
 			CloudConfigCksum: lib.SSLKeyCertChecksum(*pki.Name, string(*pki.CaCerts[0].Certificate), "", emptyIngestionMarkers, pki.Markers, true),
 		}
+		if pki.LastModified != nil {
+			pkiCacheObj.LastModified = *pki.LastModified
+		}
 		*pkiData = append(*pkiData, pkiCacheObj)
 
 	}
@@ -879,6 +948,9 @@ func (c *AviObjCache) AviPopulateAllDSs(client *clients.AviClient, cloud string,
 			checksum += utils.Hash(*ds.Datascript[0].Script)
 		}
 		dsCacheObj.CloudConfigCksum = checksum
+		if ds.LastModified != nil {
+			dsCacheObj.LastModified = *ds.LastModified
+		}
 		*DsData = append(*DsData, dsCacheObj)
 	}
 	if result.Next != "" {
@@ -981,6 +1053,9 @@ func (c *AviObjCache) AviPopulateAllSSLKeys(client *clients.AviClient, cloud str
 			CACertUUID:       cacertUUID,
 			CloudConfigCksum: lib.SSLKeyCertChecksum(*sslkey.Name, *sslkey.Certificate.Certificate, cacert, emptyIngestionMarkers, sslkey.Markers, true),
 		}
+		if sslkey.LastModified != nil {
+			sslCacheObj.LastModified = *sslkey.LastModified
+		}
 		*SslData = append(*SslData, sslCacheObj)
 	}
 	if result.Next != "" {
@@ -1052,6 +1127,9 @@ func (c *AviObjCache) AviPopulateOneSSLCache(client *clients.AviClient,
 			CloudConfigCksum: lib.SSLKeyCertChecksum(*sslkey.Name, *sslkey.Certificate.Certificate, cacert, emptyIngestionMarkers, sslkey.Markers, true),
 			HasCARef:         hasCA,
 		}
+		if sslkey.LastModified != nil {
+			sslCacheObj.LastModified = *sslkey.LastModified
+		}
 		k := NamespaceName{Namespace: lib.GetTenant(), Name: *sslkey.Name}
 		c.SSLKeyCache.AviCacheAdd(k, &sslCacheObj)
 		utils.AviLog.Debugf("Adding sslkey to Cache during refresh %s", k)
@@ -1224,6 +1302,9 @@ func (c *AviObjCache) AviPopulateOneVsDSCache(client *clients.AviClient,
 			checksum += utils.Hash(*ds.Datascript[0].Script)
 		}
 		dsCacheObj.CloudConfigCksum = checksum
+		if ds.LastModified != nil {
+			dsCacheObj.LastModified = *ds.LastModified
+		}
 		k := NamespaceName{Namespace: lib.GetTenant(), Name: *ds.Name}
 		c.DSCache.AviCacheAdd(k, &dsCacheObj)
 		utils.AviLog.Debugf("Adding ds to Cache during refresh %s", k)
@@ -1486,7 +1567,7 @@ func (c *AviObjCache) AviPopulateOneVsL4PolCache(client *clients.AviClient,
 		if l4pol.L4ConnectionPolicy != nil {
 			for _, rule := range l4pol.L4ConnectionPolicy.Rules {
 				protocols = append(protocols, *rule.Match.Protocol.Protocol)
-				if rule.Action != nil {
+				if rule.Action != nil && rule.Action.SelectPool.PoolRef != nil {
 					poolUuid := ExtractUuid(*rule.Action.SelectPool.PoolRef, "pool-.*.#")
 					poolName, found := c.PoolCache.AviCacheGetNameByUuid(poolUuid)
 					if found {
@@ -1705,6 +1786,9 @@ func (c *AviObjCache) AviPopulateAllL4PolicySets(client *clients.AviClient, clou
 						protocol = utils.UDP
 					}
 					protocols = append(protocols, protocol)
+					if rule.Action.SelectPool.PoolRef == nil {
+						continue
+					}
 					poolUuid := ExtractUuid(*rule.Action.SelectPool.PoolRef, "pool-.*.#")
 					poolName, found := c.PoolCache.AviCacheGetNameByUuid(poolUuid)
 					if found {
@@ -1765,6 +1849,375 @@ func (c *AviObjCache) PopulateL4PolicySetToCache(client *clients.AviClient, clou
 	}
 }
 
+func (c *AviObjCache) AviPopulateAllNetworkSecurityPolicies(client *clients.AviClient, cloud string, nspData *[]AviNetworkSecurityPolicyCache, nextPage ...NextPage) (*[]AviNetworkSecurityPolicyCache, int, error) {
+	var uri string
+	akoUser := lib.AKOUser
+
+	if len(nextPage) == 1 {
+		uri = nextPage[0].NextURI
+	} else {
+		uri = "/api/networksecuritypolicy/?" + "&include_name=true" + "&created_by=" + akoUser + "&page_size=100"
+	}
+
+	result, err := lib.AviGetCollectionRaw(client, uri)
+	if err != nil {
+		utils.AviLog.Warnf("Get uri %v returned err for networksecuritypolicy %v", uri, err)
+		return nil, 0, err
+	}
+	elems := make([]json.RawMessage, result.Count)
+	err = json.Unmarshal(result.Results, &elems)
+	if err != nil {
+		utils.AviLog.Warnf("Failed to unmarshal networksecuritypolicy data, err: %v", err)
+		return nil, 0, err
+	}
+	for i := 0; i < len(elems); i++ {
+		nsp := models.NetworkSecurityPolicy{}
+		err = json.Unmarshal(elems[i], &nsp)
+		if err != nil {
+			utils.AviLog.Warnf("Failed to unmarshal networksecuritypolicy data, err: %v", err)
+			continue
+		}
+		if nsp.Name == nil || nsp.UUID == nil {
+			utils.AviLog.Warnf("Incomplete network security policy data unmarshalled, %s", utils.Stringify(nsp))
+			continue
+		}
+		*nspData = append(*nspData, getNetworkSecurityPolicyCacheObj(&nsp))
+	}
+
+	if result.Next != "" {
+		// It has a next page, let's recursively call the same method.
+		next_uri := strings.Split(result.Next, "/api/networksecuritypolicy")
+		if len(next_uri) > 1 {
+			overrideUri := "/api/networksecuritypolicy" + next_uri[1]
+			nextPage := NextPage{NextURI: overrideUri}
+			_, _, err := c.AviPopulateAllNetworkSecurityPolicies(client, cloud, nspData, nextPage)
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+	return nspData, result.Count, nil
+}
+
+func (c *AviObjCache) PopulateNetworkSecurityPolicyToCache(client *clients.AviClient, cloud string) {
+	var nspData []AviNetworkSecurityPolicyCache
+	_, count, err := c.AviPopulateAllNetworkSecurityPolicies(client, cloud, &nspData)
+	if err != nil || len(nspData) != count {
+		return
+	}
+	nspCacheData := c.NSPCache.ShallowCopy()
+	for i, nspCacheObj := range nspData {
+		k := NamespaceName{Namespace: lib.GetTenant(), Name: nspCacheObj.Name}
+		utils.AviLog.Debugf("Adding key to network security policy cache :%s", utils.Stringify(nspCacheObj))
+		c.NSPCache.AviCacheAdd(k, &nspData[i])
+		delete(nspCacheData, k)
+	}
+	// The data that is left in nspCacheData should be explicitly removed
+	for key := range nspCacheData {
+		utils.AviLog.Debugf("Deleting key from network security policy cache :%s", key)
+		c.NSPCache.AviCacheDelete(key)
+	}
+}
+
+func (c *AviObjCache) AviPopulateOneNSPCache(client *clients.AviClient, cloud string, objName string) error {
+	var uri string
+	akoUser := lib.AKOUser
+
+	uri = "/api/networksecuritypolicy?name=" + objName + "&created_by=" + akoUser
+	result, err := lib.AviGetCollectionRaw(client, uri)
+	if err != nil {
+		utils.AviLog.Warnf("Get uri %v returned err for networksecuritypolicy %v", uri, err)
+		return err
+	}
+	elems := make([]json.RawMessage, result.Count)
+	err = json.Unmarshal(result.Results, &elems)
+	if err != nil {
+		utils.AviLog.Warnf("Failed to unmarshal networksecuritypolicy data, err: %v", err)
+		return err
+	}
+	for i := 0; i < len(elems); i++ {
+		nsp := models.NetworkSecurityPolicy{}
+		err = json.Unmarshal(elems[i], &nsp)
+		if err != nil {
+			utils.AviLog.Warnf("Failed to unmarshal networksecuritypolicy data, err: %v", err)
+			continue
+		}
+		if nsp.Name == nil || nsp.UUID == nil {
+			utils.AviLog.Warnf("Incomplete network security policy data unmarshalled, %s", utils.Stringify(nsp))
+			continue
+		}
+		if !strings.HasPrefix(*nsp.Name, lib.GetNamePrefix()) {
+			continue
+		}
+		nspCacheObj := getNetworkSecurityPolicyCacheObj(&nsp)
+		k := NamespaceName{Namespace: lib.GetTenant(), Name: *nsp.Name}
+		c.NSPCache.AviCacheAdd(k, &nspCacheObj)
+		utils.AviLog.Infof("Adding network security policy to Cache during refresh %s", utils.Stringify(nspCacheObj))
+	}
+	return nil
+}
+
+func getNetworkSecurityPolicyCacheObj(nsp *models.NetworkSecurityPolicy) AviNetworkSecurityPolicyCache {
+	emptyIngestionMarkers := utils.AviObjectMarkers{}
+	nspCacheObj := AviNetworkSecurityPolicyCache{
+		Name:             *nsp.Name,
+		Uuid:             *nsp.UUID,
+		CloudConfigCksum: lib.NetworkSecurityPolicyChecksum(lib.GetNSPAllowedClientIPs(nsp.Rules), emptyIngestionMarkers, nsp.Markers, true),
+	}
+	if nsp.LastModified != nil {
+		nspCacheObj.LastModified = *nsp.LastModified
+	}
+	return nspCacheObj
+}
+
+func (c *AviObjCache) AviPopulateAllAppProfiles(client *clients.AviClient, cloud string, appProfileData *[]AviAppProfileCache, nextPage ...NextPage) (*[]AviAppProfileCache, int, error) {
+	var uri string
+	akoUser := lib.AKOUser
+
+	if len(nextPage) == 1 {
+		uri = nextPage[0].NextURI
+	} else {
+		uri = "/api/applicationprofile/?" + "&include_name=true" + "&created_by=" + akoUser + "&page_size=100"
+	}
+
+	result, err := lib.AviGetCollectionRaw(client, uri)
+	if err != nil {
+		utils.AviLog.Warnf("Get uri %v returned err for applicationprofile %v", uri, err)
+		return nil, 0, err
+	}
+	elems := make([]json.RawMessage, result.Count)
+	err = json.Unmarshal(result.Results, &elems)
+	if err != nil {
+		utils.AviLog.Warnf("Failed to unmarshal applicationprofile data, err: %v", err)
+		return nil, 0, err
+	}
+	for i := 0; i < len(elems); i++ {
+		appProfile := models.ApplicationProfile{}
+		err = json.Unmarshal(elems[i], &appProfile)
+		if err != nil {
+			utils.AviLog.Warnf("Failed to unmarshal applicationprofile data, err: %v", err)
+			continue
+		}
+		if appProfile.Name == nil || appProfile.UUID == nil {
+			utils.AviLog.Warnf("Incomplete application profile data unmarshalled, %s", utils.Stringify(appProfile))
+			continue
+		}
+		*appProfileData = append(*appProfileData, c.getAppProfileCacheObj(&appProfile))
+	}
+
+	if result.Next != "" {
+		// It has a next page, let's recursively call the same method.
+		next_uri := strings.Split(result.Next, "/api/applicationprofile")
+		if len(next_uri) > 1 {
+			overrideUri := "/api/applicationprofile" + next_uri[1]
+			nextPage := NextPage{NextURI: overrideUri}
+			_, _, err := c.AviPopulateAllAppProfiles(client, cloud, appProfileData, nextPage)
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+	return appProfileData, result.Count, nil
+}
+
+func (c *AviObjCache) PopulateAppProfilesToCache(client *clients.AviClient, cloud string) {
+	var appProfileData []AviAppProfileCache
+	_, count, err := c.AviPopulateAllAppProfiles(client, cloud, &appProfileData)
+	if err != nil || len(appProfileData) != count {
+		return
+	}
+	appProfileCacheData := c.AppProfileCache.ShallowCopy()
+	for i, appProfileCacheObj := range appProfileData {
+		k := NamespaceName{Namespace: lib.GetTenant(), Name: appProfileCacheObj.Name}
+		utils.AviLog.Debugf("Adding key to application profile cache :%s", utils.Stringify(appProfileCacheObj))
+		c.AppProfileCache.AviCacheAdd(k, &appProfileData[i])
+		delete(appProfileCacheData, k)
+	}
+	// The data that is left in appProfileCacheData should be explicitly removed
+	for key := range appProfileCacheData {
+		utils.AviLog.Debugf("Deleting key from application profile cache :%s", key)
+		c.AppProfileCache.AviCacheDelete(key)
+	}
+}
+
+func (c *AviObjCache) AviPopulateOneAppProfileCache(client *clients.AviClient, cloud string, objName string) error {
+	var uri string
+	akoUser := lib.AKOUser
+
+	uri = "/api/applicationprofile?name=" + objName + "&created_by=" + akoUser
+	result, err := lib.AviGetCollectionRaw(client, uri)
+	if err != nil {
+		utils.AviLog.Warnf("Get uri %v returned err for applicationprofile %v", uri, err)
+		return err
+	}
+	elems := make([]json.RawMessage, result.Count)
+	err = json.Unmarshal(result.Results, &elems)
+	if err != nil {
+		utils.AviLog.Warnf("Failed to unmarshal applicationprofile data, err: %v", err)
+		return err
+	}
+	for i := 0; i < len(elems); i++ {
+		appProfile := models.ApplicationProfile{}
+		err = json.Unmarshal(elems[i], &appProfile)
+		if err != nil {
+			utils.AviLog.Warnf("Failed to unmarshal applicationprofile data, err: %v", err)
+			continue
+		}
+		if appProfile.Name == nil || appProfile.UUID == nil {
+			utils.AviLog.Warnf("Incomplete application profile data unmarshalled, %s", utils.Stringify(appProfile))
+			continue
+		}
+		if !strings.HasPrefix(*appProfile.Name, lib.GetNamePrefix()) {
+			continue
+		}
+		appProfileCacheObj := c.getAppProfileCacheObj(&appProfile)
+		k := NamespaceName{Namespace: lib.GetTenant(), Name: *appProfile.Name}
+		c.AppProfileCache.AviCacheAdd(k, &appProfileCacheObj)
+		utils.AviLog.Infof("Adding application profile to Cache during refresh %s", utils.Stringify(appProfileCacheObj))
+	}
+	return nil
+}
+
+func (c *AviObjCache) getAppProfileCacheObj(appProfile *models.ApplicationProfile) AviAppProfileCache {
+	emptyIngestionMarkers := utils.AviObjectMarkers{}
+	mode, pkiProfileName, headers := lib.GetClientCertAppProfileSettings(appProfile)
+	appProfileCacheObj := AviAppProfileCache{
+		Name:             *appProfile.Name,
+		Uuid:             *appProfile.UUID,
+		CloudConfigCksum: lib.ClientCertAppProfileChecksum(mode, pkiProfileName, headers, emptyIngestionMarkers, appProfile.Markers, true),
+	}
+	// the PKI profiles created by AKO are present in the cache
+	pkiKey := NamespaceName{Namespace: lib.GetTenant(), Name: pkiProfileName}
+	if _, found := c.PKIProfileCache.AviCacheGet(pkiKey); found {
+		appProfileCacheObj.PkiProfileCollection = pkiKey
+	}
+	if appProfile.LastModified != nil {
+		appProfileCacheObj.LastModified = *appProfile.LastModified
+	}
+	return appProfileCacheObj
+}
+
+func (c *AviObjCache) AviPopulateAllTrafficCloneProfiles(client *clients.AviClient, cloud string, cloneProfileData *[]AviTrafficCloneProfileCache, nextPage ...NextPage) (*[]AviTrafficCloneProfileCache, int, error) {
+	var uri string
+	akoUser := lib.AKOUser
+
+	if len(nextPage) == 1 {
+		uri = nextPage[0].NextURI
+	} else {
+		uri = "/api/trafficcloneprofile/?" + "&include_name=true" + "&cloud_ref.name=" + cloud + "&created_by=" + akoUser + "&page_size=100"
+	}
+
+	result, err := lib.AviGetCollectionRaw(client, uri)
+	if err != nil {
+		utils.AviLog.Warnf("Get uri %v returned err for trafficcloneprofile %v", uri, err)
+		return nil, 0, err
+	}
+	elems := make([]json.RawMessage, result.Count)
+	err = json.Unmarshal(result.Results, &elems)
+	if err != nil {
+		utils.AviLog.Warnf("Failed to unmarshal trafficcloneprofile data, err: %v", err)
+		return nil, 0, err
+	}
+	for i := 0; i < len(elems); i++ {
+		cloneProfile := models.TrafficCloneProfile{}
+		err = json.Unmarshal(elems[i], &cloneProfile)
+		if err != nil {
+			utils.AviLog.Warnf("Failed to unmarshal trafficcloneprofile data, err: %v", err)
+			continue
+		}
+		if cloneProfile.Name == nil || cloneProfile.UUID == nil {
+			utils.AviLog.Warnf("Incomplete traffic clone profile data unmarshalled, %s", utils.Stringify(cloneProfile))
+			continue
+		}
+		*cloneProfileData = append(*cloneProfileData, getTrafficCloneProfileCacheObj(&cloneProfile))
+	}
+
+	if result.Next != "" {
+		// It has a next page, let's recursively call the same method.
+		next_uri := strings.Split(result.Next, "/api/trafficcloneprofile")
+		if len(next_uri) > 1 {
+			overrideUri := "/api/trafficcloneprofile" + next_uri[1]
+			nextPage := NextPage{NextURI: overrideUri}
+			_, _, err := c.AviPopulateAllTrafficCloneProfiles(client, cloud, cloneProfileData, nextPage)
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+	return cloneProfileData, result.Count, nil
+}
+
+func (c *AviObjCache) PopulateTrafficCloneProfilesToCache(client *clients.AviClient, cloud string) {
+	var cloneProfileData []AviTrafficCloneProfileCache
+	_, count, err := c.AviPopulateAllTrafficCloneProfiles(client, cloud, &cloneProfileData)
+	if err != nil || len(cloneProfileData) != count {
+		return
+	}
+	cloneProfileCacheData := c.CloneProfileCache.ShallowCopy()
+	for i, cloneProfileCacheObj := range cloneProfileData {
+		k := NamespaceName{Namespace: lib.GetTenant(), Name: cloneProfileCacheObj.Name}
+		utils.AviLog.Debugf("Adding key to traffic clone profile cache :%s", utils.Stringify(cloneProfileCacheObj))
+		c.CloneProfileCache.AviCacheAdd(k, &cloneProfileData[i])
+		delete(cloneProfileCacheData, k)
+	}
+	// The data that is left in cloneProfileCacheData should be explicitly removed
+	for key := range cloneProfileCacheData {
+		utils.AviLog.Debugf("Deleting key from traffic clone profile cache :%s", key)
+		c.CloneProfileCache.AviCacheDelete(key)
+	}
+}
+
+func (c *AviObjCache) AviPopulateOneTrafficCloneProfileCache(client *clients.AviClient, cloud string, objName string) error {
+	var uri string
+	akoUser := lib.AKOUser
+
+	uri = "/api/trafficcloneprofile?name=" + objName + "&cloud_ref.name=" + cloud + "&created_by=" + akoUser
+	result, err := lib.AviGetCollectionRaw(client, uri)
+	if err != nil {
+		utils.AviLog.Warnf("Get uri %v returned err for trafficcloneprofile %v", uri, err)
+		return err
+	}
+	elems := make([]json.RawMessage, result.Count)
+	err = json.Unmarshal(result.Results, &elems)
+	if err != nil {
+		utils.AviLog.Warnf("Failed to unmarshal trafficcloneprofile data, err: %v", err)
+		return err
+	}
+	for i := 0; i < len(elems); i++ {
+		cloneProfile := models.TrafficCloneProfile{}
+		err = json.Unmarshal(elems[i], &cloneProfile)
+		if err != nil {
+			utils.AviLog.Warnf("Failed to unmarshal trafficcloneprofile data, err: %v", err)
+			continue
+		}
+		if cloneProfile.Name == nil || cloneProfile.UUID == nil {
+			utils.AviLog.Warnf("Incomplete traffic clone profile data unmarshalled, %s", utils.Stringify(cloneProfile))
+			continue
+		}
+		if !strings.HasPrefix(*cloneProfile.Name, lib.GetNamePrefix()) {
+			continue
+		}
+		cloneProfileCacheObj := getTrafficCloneProfileCacheObj(&cloneProfile)
+		k := NamespaceName{Namespace: lib.GetTenant(), Name: *cloneProfile.Name}
+		c.CloneProfileCache.AviCacheAdd(k, &cloneProfileCacheObj)
+		utils.AviLog.Infof("Adding traffic clone profile to Cache during refresh %s", utils.Stringify(cloneProfileCacheObj))
+	}
+	return nil
+}
+
+func getTrafficCloneProfileCacheObj(cloneProfile *models.TrafficCloneProfile) AviTrafficCloneProfileCache {
+	emptyIngestionMarkers := utils.AviObjectMarkers{}
+	cloneProfileCacheObj := AviTrafficCloneProfileCache{
+		Name:             *cloneProfile.Name,
+		Uuid:             *cloneProfile.UUID,
+		CloudConfigCksum: lib.TrafficCloneProfileChecksum(lib.GetTrafficCloneProfileServers(cloneProfile), emptyIngestionMarkers, cloneProfile.Markers, true),
+	}
+	if cloneProfile.LastModified != nil {
+		cloneProfileCacheObj.LastModified = *cloneProfile.LastModified
+	}
+	return cloneProfileCacheObj
+}
+
 func (c *AviObjCache) AviObjVrfCachePopulate(client *clients.AviClient, cloud string) error {
 	if lib.GetDisableStaticRoute() {
 		utils.AviLog.Debugf("Static route sync disabled, skipping vrf cache population")
@@ -1887,6 +2340,9 @@ func (c *AviObjCache) AviObjVSCachePopulate(client *clients.AviClient, cloud str
 				var dsKeys []NamespaceName
 				var httpKeys []NamespaceName
 				var l4Keys []NamespaceName
+				var nspKeys []NamespaceName
+				var appProfileKeys []NamespaceName
+				var cloneKeys []NamespaceName
 				var poolgroupKeys []NamespaceName
 				var poolKeys []NamespaceName
 				var sharedVsOrL4 bool
@@ -1993,6 +2449,30 @@ func (c *AviObjCache) AviObjVSCachePopulate(client *clients.AviClient, cloud str
 						}
 					}
 				}
+				if vs["network_security_policy_ref"] != nil {
+					nspUuid := ExtractUuid(vs["network_security_policy_ref"].(string), "networksecuritypolicy-.*.#")
+					// Only the network security policies created by AKO are present in the cache
+					nspName, foundNSP := c.NSPCache.AviCacheGetNameByUuid(nspUuid)
+					if foundNSP {
+						nspKeys = append(nspKeys, NamespaceName{Namespace: lib.GetTenant(), Name: nspName.(string)})
+					}
+				}
+				if vs["application_profile_ref"] != nil {
+					appProfileUuid := ExtractUuid(vs["application_profile_ref"].(string), "applicationprofile-.*.#")
+					// Only the application profiles created by AKO are present in the cache
+					appProfileName, foundAppProfile := c.AppProfileCache.AviCacheGetNameByUuid(appProfileUuid)
+					if foundAppProfile {
+						appProfileKeys = append(appProfileKeys, NamespaceName{Namespace: lib.GetTenant(), Name: appProfileName.(string)})
+					}
+				}
+				if vs["traffic_clone_profile_ref"] != nil {
+					cloneProfileUuid := ExtractUuid(vs["traffic_clone_profile_ref"].(string), "trafficcloneprofile-.*.#")
+					// Only the traffic clone profiles created by AKO are present in the cache
+					cloneProfileName, foundCloneProfile := c.CloneProfileCache.AviCacheGetNameByUuid(cloneProfileUuid)
+					if foundCloneProfile {
+						cloneKeys = append(cloneKeys, NamespaceName{Namespace: lib.GetTenant(), Name: cloneProfileName.(string)})
+					}
+				}
 				if vs["http_policies"] != nil {
 					for _, http_intf := range vs["http_policies"].([]interface{}) {
 						httpmap, ok := http_intf.(map[string]interface{})
@@ -2053,6 +2533,9 @@ func (c *AviObjCache) AviObjVSCachePopulate(client *clients.AviClient, cloud str
 					ParentVSRef:          parentVSKey,
 					ServiceMetadataObj:   svc_mdata_obj,
 					L4PolicyCollection:   l4Keys,
+					NSPCollection:        nspKeys,
+					AppProfileCollection: appProfileKeys,
+					CloneKeyCollection:   cloneKeys,
 					LastModified:         vs["_last_modified"].(string),
 				}
 				if val, ok := vs["enable_rhi"]; ok {
@@ -2151,6 +2634,9 @@ func (c *AviObjCache) AviObjOneVSCachePopulate(client *clients.AviClient, cloud
 				var poolgroupKeys []NamespaceName
 				var poolKeys []NamespaceName
 				var l4Keys []NamespaceName
+				var nspKeys []NamespaceName
+				var appProfileKeys []NamespaceName
+				var cloneKeys []NamespaceName
 
 				// Populate the VSVIP cache
 				if vs["vsvip_ref"] != nil {
@@ -2246,6 +2732,30 @@ func (c *AviObjCache) AviObjOneVSCachePopulate(client *clients.AviClient, cloud
 						}
 					}
 				}
+				if vs["network_security_policy_ref"] != nil {
+					nspUuid := ExtractUuid(vs["network_security_policy_ref"].(string), "networksecuritypolicy-.*.#")
+					// Only the network security policies created by AKO are present in the cache
+					nspName, foundNSP := c.NSPCache.AviCacheGetNameByUuid(nspUuid)
+					if foundNSP {
+						nspKeys = append(nspKeys, NamespaceName{Namespace: lib.GetTenant(), Name: nspName.(string)})
+					}
+				}
+				if vs["application_profile_ref"] != nil {
+					appProfileUuid := ExtractUuid(vs["application_profile_ref"].(string), "applicationprofile-.*.#")
+					// Only the application profiles created by AKO are present in the cache
+					appProfileName, foundAppProfile := c.AppProfileCache.AviCacheGetNameByUuid(appProfileUuid)
+					if foundAppProfile {
+						appProfileKeys = append(appProfileKeys, NamespaceName{Namespace: lib.GetTenant(), Name: appProfileName.(string)})
+					}
+				}
+				if vs["traffic_clone_profile_ref"] != nil {
+					cloneProfileUuid := ExtractUuid(vs["traffic_clone_profile_ref"].(string), "trafficcloneprofile-.*.#")
+					// Only the traffic clone profiles created by AKO are present in the cache
+					cloneProfileName, foundCloneProfile := c.CloneProfileCache.AviCacheGetNameByUuid(cloneProfileUuid)
+					if foundCloneProfile {
+						cloneKeys = append(cloneKeys, NamespaceName{Namespace: lib.GetTenant(), Name: cloneProfileName.(string)})
+					}
+				}
 				if vs["http_policies"] != nil {
 					for _, http_intf := range vs["http_policies"].([]interface{}) {
 						// find the sslkey name from the ssl key cache
@@ -2283,6 +2793,9 @@ func (c *AviObjCache) AviObjOneVSCachePopulate(client *clients.AviClient, cloud
 					SNIChildCollection:   sni_child_collection,
 					ParentVSRef:          parentVSKey,
 					L4PolicyCollection:   l4Keys,
+					NSPCollection:        nspKeys,
+					AppProfileCollection: appProfileKeys,
+					CloneKeyCollection:   cloneKeys,
 					ServiceMetadataObj:   svc_mdata_obj,
 				}
 				if val, ok := vs["enable_rhi"]; ok {
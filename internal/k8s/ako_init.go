@@ -32,6 +32,7 @@ import (
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/rest"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/retry"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/status"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api/models"
 	akov1beta1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1beta1"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/third_party/github.com/vmware/alb-sdk/go/clients"
@@ -456,6 +457,7 @@ func (c *AviController) InitController(informers K8sinformers, registeredInforme
 	// set up signals so we handle the first shutdown signal gracefully
 	var worker *utils.FullSyncThread
 	var tokenWorker *utils.FullSyncThread
+	var driftWorker *utils.FullSyncThread
 	informersArg := make(map[string]interface{})
 	informersArg[utils.INFORMERS_OPENSHIFT_CLIENT] = informers.OshiftClient
 	if lib.GetNamespaceToSync() != "" {
@@ -492,19 +494,25 @@ func (c *AviController) InitController(informers K8sinformers, registeredInforme
 	// This is the first time initialization of the queue. For hostname based sharding, we don't want layer 2 to process the queue using multiple go routines.
 	var retryQueueWorkers uint32
 	retryQueueWorkers = 1
-	slowRetryQParams := utils.WorkerQueue{NumWorkers: retryQueueWorkers, WorkqueueName: lib.SLOW_RETRY_LAYER, SlowSyncTime: lib.SLOW_SYNC_TIME}
+	slowRetryQParams := utils.WorkerQueue{NumWorkers: retryQueueWorkers, WorkqueueName: lib.SLOW_RETRY_LAYER}
 	fastRetryQParams := utils.WorkerQueue{NumWorkers: retryQueueWorkers, WorkqueueName: lib.FAST_RETRY_LAYER}
 
 	numWorkers := uint32(1)
-	ingestionQueueParams := utils.WorkerQueue{NumWorkers: numWorkers, WorkqueueName: utils.ObjectIngestionLayer}
+	ingestionQueueParams := utils.WorkerQueue{NumWorkers: numWorkers, WorkqueueName: utils.ObjectIngestionLayer, FairQueuing: lib.GetFairQueueOptions(utils.FairQueueFlowByNamespace)}
 	numGraphWorkers := lib.GetshardSize()
 	if numGraphWorkers == 0 {
 		// For dedicated VSes - we will have 8 threads layer 3
 		numGraphWorkers = 8
 	}
-	graphQueueParams := utils.WorkerQueue{NumWorkers: numGraphWorkers, WorkqueueName: utils.GraphLayer}
+	graphQueueParams := utils.WorkerQueue{NumWorkers: numGraphWorkers, WorkqueueName: utils.GraphLayer, FairQueuing: lib.GetFairQueueOptions(utils.FairQueueFlowByTenant)}
 	statusQueueParams := utils.WorkerQueue{NumWorkers: numGraphWorkers, WorkqueueName: utils.StatusQueue}
 	graphQueue = utils.SharedWorkQueue(&ingestionQueueParams, &graphQueueParams, &slowRetryQParams, &fastRetryQParams, &statusQueueParams).GetQueueByName(utils.GraphLayer)
+	// The virtualservices planned in dry run mode are synced to the controller, once the mode is disabled.
+	models.DryRunPlans.SetSyncFunc(func(keys []string) {
+		for _, key := range keys {
+			nodes.PublishKeyToRestLayer(key, "dryrun", graphQueue)
+		}
+	})
 
 	err := PopulateCache()
 	if err != nil {
@@ -576,6 +584,11 @@ func (c *AviController) InitController(informers K8sinformers, registeredInforme
 		tokenWorker.SyncFunction = c.RefreshAuthToken
 		go tokenWorker.Run()
 	}
+	if driftInterval := lib.GetDriftDetectionInterval(); driftInterval != 0 && !lib.IsWCP() {
+		driftWorker = utils.NewFullSyncThread(driftInterval)
+		driftWorker.SyncFunction = c.DetectDrift
+		go driftWorker.Run()
+	}
 	if lib.DisableSync {
 		lib.AKOControlConfig().PodEventf(corev1.EventTypeNormal, lib.AKODeleteConfigSet, "AKO is in disable sync state")
 	} else {
@@ -610,6 +623,9 @@ LABEL:
 	if worker != nil {
 		worker.Shutdown()
 	}
+	if driftWorker != nil {
+		driftWorker.Shutdown()
+	}
 
 	cancel()
 	if !lib.IsWCP() {
@@ -798,6 +814,15 @@ func (c *AviController) FullSync() {
 	}
 }
 
+// DetectDrift checks the Avi objects of AKO for changes made on the Avi controller outside of AKO.
+func (c *AviController) DetectDrift() {
+	if c.DisableSync {
+		return
+	}
+	restlayer := rest.NewRestOperations(avicache.SharedAviObjCache(), avicache.SharedAVIClients())
+	restlayer.DetectDrift()
+}
+
 func (c *AviController) FullSyncK8s(sync bool) error {
 	if c.DisableSync {
 		utils.AviLog.Infof("Sync disabled, skipping full sync")
@@ -22,11 +22,13 @@ import (
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/status"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/tracing"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 
 	routev1 "github.com/openshift/api/route/v1"
 	oshiftclient "github.com/openshift/client-go/route/clientset/versioned"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
@@ -182,6 +184,7 @@ func AddIngressFromNSToIngestionQueue(numWorkers uint32, c *AviController, names
 		key := utils.Ingress + "/" + utils.ObjKey(ingObj)
 		bkt := utils.Bkt(namespace, numWorkers)
 		c.workqueue[bkt].AddRateLimited(key)
+		tracing.ObjectQueued(key)
 		lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 		utils.AviLog.Debugf("key: %s, msg: %s for namespace: %s", key, msg, namespace)
 	}
@@ -197,6 +200,7 @@ func AddRoutesFromNSToIngestionQueue(numWorkers uint32, c *AviController, namesp
 		key := utils.OshiftRoute + "/" + utils.ObjKey(routeObj)
 		bkt := utils.Bkt(namespace, numWorkers)
 		c.workqueue[bkt].AddRateLimited(key)
+		tracing.ObjectQueued(key)
 		lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 		utils.AviLog.Debugf("key: %s, msg: %s for namespace: %s", key, msg, namespace)
 	}
@@ -222,6 +226,7 @@ func AddServicesFromNSToIngestionQueue(numWorkers uint32, c *AviController, name
 		}
 		bkt := utils.Bkt(namespace, numWorkers)
 		c.workqueue[bkt].AddRateLimited(key)
+		tracing.ObjectQueued(key)
 		lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 		utils.AviLog.Debugf("key: %s, msg: %s for namespace: %s", key, msg, namespace)
 	}
@@ -239,6 +244,7 @@ func AddGatewaysFromNSToIngestionQueue(numWorkers uint32, c *AviController, name
 		InformerStatusUpdatesForSvcApiGateway(key, gatewayObj)
 		bkt := utils.Bkt(namespace, numWorkers)
 		c.workqueue[bkt].AddRateLimited(key)
+		tracing.ObjectQueued(key)
 		lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 		utils.AviLog.Debugf("key: %s, msg: %s for namespace: %s", key, msg, namespace)
 	}
@@ -254,6 +260,7 @@ func AddMultiClusterIngressFromNSToIngestionQueue(numWorkers uint32, c *AviContr
 		key := lib.MultiClusterIngress + "/" + utils.ObjKey(mciObj)
 		bkt := utils.Bkt(namespace, numWorkers)
 		c.workqueue[bkt].AddRateLimited(key)
+		tracing.ObjectQueued(key)
 		lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 		utils.AviLog.Debugf("key: %s, msg: %s for namespace: %s", key, msg, namespace)
 	}
@@ -269,6 +276,7 @@ func AddServiceImportsFromNSToIngestionQueue(numWorkers uint32, c *AviController
 		key := lib.MultiClusterIngress + "/" + utils.ObjKey(siObj)
 		bkt := utils.Bkt(namespace, numWorkers)
 		c.workqueue[bkt].AddRateLimited(key)
+		tracing.ObjectQueued(key)
 		lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 		utils.AviLog.Debugf("key: %s, msg: %s for namespace: %s", key, msg, namespace)
 	}
@@ -441,6 +449,7 @@ func AddRouteEventHandler(numWorkers uint32, c *AviController) cache.ResourceEve
 				status.UpdateRouteStatusWithErrMsg(key, route.Name, namespace, lib.DuplicateBackends)
 			}
 			c.workqueue[bkt].AddRateLimited(key)
+			tracing.ObjectQueued(key)
 			lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 			utils.AviLog.Debugf("key: %s, msg: ADD", key)
 		},
@@ -469,6 +478,7 @@ func AddRouteEventHandler(numWorkers uint32, c *AviController) cache.ResourceEve
 			}
 			bkt := utils.Bkt(namespace, numWorkers)
 			c.workqueue[bkt].AddRateLimited(key)
+			tracing.ObjectQueued(key)
 			lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 			objects.SharedResourceVerInstanceLister().Delete(key)
 			utils.AviLog.Debugf("key: %s, msg: DELETE", key)
@@ -491,6 +501,7 @@ func AddRouteEventHandler(numWorkers uint32, c *AviController) cache.ResourceEve
 					status.UpdateRouteStatusWithErrMsg(key, newRoute.Name, namespace, lib.DuplicateBackends)
 				}
 				c.workqueue[bkt].AddRateLimited(key)
+				tracing.ObjectQueued(key)
 				lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 				utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
 			}
@@ -523,6 +534,7 @@ func AddPodEventHandler(numWorkers uint32, c *AviController) cache.ResourceEvent
 			}
 			bkt := utils.Bkt(namespace, numWorkers)
 			c.workqueue[bkt].AddRateLimited(key)
+			tracing.ObjectQueued(key)
 			lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 			utils.AviLog.Debugf("key: %s, msg: ADD", key)
 		},
@@ -556,6 +568,7 @@ func AddPodEventHandler(numWorkers uint32, c *AviController) cache.ResourceEvent
 			bkt := utils.Bkt(namespace, numWorkers)
 			objects.SharedResourceVerInstanceLister().Delete(key)
 			c.workqueue[bkt].AddRateLimited(key)
+			tracing.ObjectQueued(key)
 			lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 			utils.AviLog.Debugf("key: %s, msg: DELETE", key)
 		},
@@ -589,6 +602,7 @@ func AddPodEventHandler(numWorkers uint32, c *AviController) cache.ResourceEvent
 			}
 			bkt := utils.Bkt(namespace, numWorkers)
 			c.workqueue[bkt].AddRateLimited(key)
+			tracing.ObjectQueued(key)
 			lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 			utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
 		},
@@ -600,8 +614,90 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 	mcpQueue := utils.SharedWorkQueue().GetQueueByName(utils.ObjectIngestionLayer)
 	c.workqueue = mcpQueue.Workqueue
 	numWorkers := mcpQueue.NumWorkers
-	var epEventHandler cache.ResourceEventHandlerFuncs
+	var epEventHandler, epSliceEventHandler cache.ResourceEventHandlerFuncs
 	if lib.GetServiceType() != lib.NodePortLocal {
+		epSliceEventHandler = cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				if c.DisableSync {
+					return
+				}
+				eps := obj.(*discoveryv1.EndpointSlice)
+				svcKey, ok := utils.EndpointSliceObjKey(eps)
+				if !ok {
+					return
+				}
+				namespace, _, _ := cache.SplitMetaNamespaceKey(svcKey)
+				key := utils.Endpoints + "/" + svcKey
+				if lib.IsNamespaceBlocked(namespace) {
+					utils.AviLog.Debugf("key: %s, msg: EndpointSlice Add event: Namespace: %s didn't qualify filter", key, namespace)
+					return
+				}
+				bkt := utils.Bkt(namespace, numWorkers)
+				c.workqueue[bkt].AddRateLimited(key)
+				tracing.ObjectQueued(key)
+				lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
+				utils.AviLog.Debugf("key: %s, msg: ADD", key)
+			},
+			DeleteFunc: func(obj interface{}) {
+				if c.DisableSync {
+					return
+				}
+				eps, ok := obj.(*discoveryv1.EndpointSlice)
+				if !ok {
+					// endpointslice was deleted but its final state is unrecorded.
+					tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+					if !ok {
+						utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
+						return
+					}
+					eps, ok = tombstone.Obj.(*discoveryv1.EndpointSlice)
+					if !ok {
+						utils.AviLog.Errorf("Tombstone contained object that is not an EndpointSlice: %#v", obj)
+						return
+					}
+				}
+				svcKey, ok := utils.EndpointSliceObjKey(eps)
+				if !ok {
+					return
+				}
+				namespace, _, _ := cache.SplitMetaNamespaceKey(svcKey)
+				key := utils.Endpoints + "/" + svcKey
+				if lib.IsNamespaceBlocked(namespace) {
+					utils.AviLog.Debugf("key: %s, msg: EndpointSlice Delete event: Namespace: %s didn't qualify filter", key, namespace)
+					return
+				}
+				bkt := utils.Bkt(namespace, numWorkers)
+				c.workqueue[bkt].AddRateLimited(key)
+				tracing.ObjectQueued(key)
+				lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
+				utils.AviLog.Debugf("key: %s, msg: DELETE", key)
+			},
+			UpdateFunc: func(old, cur interface{}) {
+				if c.DisableSync {
+					return
+				}
+				oeps := old.(*discoveryv1.EndpointSlice)
+				ceps := cur.(*discoveryv1.EndpointSlice)
+				if reflect.DeepEqual(ceps.Endpoints, oeps.Endpoints) && reflect.DeepEqual(ceps.Ports, oeps.Ports) {
+					return
+				}
+				svcKey, ok := utils.EndpointSliceObjKey(ceps)
+				if !ok {
+					return
+				}
+				namespace, _, _ := cache.SplitMetaNamespaceKey(svcKey)
+				key := utils.Endpoints + "/" + svcKey
+				if lib.IsNamespaceBlocked(namespace) {
+					utils.AviLog.Debugf("key: %s, msg: EndpointSlice Update event: Namespace: %s didn't qualify filter", key, namespace)
+					return
+				}
+				bkt := utils.Bkt(namespace, numWorkers)
+				c.workqueue[bkt].AddRateLimited(key)
+				tracing.ObjectQueued(key)
+				lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
+				utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
+			},
+		}
 		epEventHandler = cache.ResourceEventHandlerFuncs{
 			AddFunc: func(obj interface{}) {
 				if c.DisableSync {
@@ -616,6 +712,7 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 				}
 				bkt := utils.Bkt(namespace, numWorkers)
 				c.workqueue[bkt].AddRateLimited(key)
+				tracing.ObjectQueued(key)
 				lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 				utils.AviLog.Debugf("key: %s, msg: ADD", key)
 			},
@@ -645,6 +742,7 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 				}
 				bkt := utils.Bkt(namespace, numWorkers)
 				c.workqueue[bkt].AddRateLimited(key)
+				tracing.ObjectQueued(key)
 				lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 				utils.AviLog.Debugf("key: %s, msg: DELETE", key)
 			},
@@ -663,6 +761,7 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 					}
 					bkt := utils.Bkt(namespace, numWorkers)
 					c.workqueue[bkt].AddRateLimited(key)
+					tracing.ObjectQueued(key)
 					lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 					utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
 				}
@@ -709,6 +808,7 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 			}
 			bkt := utils.Bkt(namespace, numWorkers)
 			c.workqueue[bkt].AddRateLimited(key)
+			tracing.ObjectQueued(key)
 			lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 			utils.AviLog.Debugf("key: %s, msg: ADD", key)
 		},
@@ -753,6 +853,7 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 			}
 			bkt := utils.Bkt(namespace, numWorkers)
 			c.workqueue[bkt].AddRateLimited(key)
+			tracing.ObjectQueued(key)
 			lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 			objects.SharedResourceVerInstanceLister().Delete(key)
 			utils.AviLog.Debugf("key: %s, msg: DELETE", key)
@@ -804,10 +905,12 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 					}
 				}
 				c.workqueue[bkt].AddRateLimited(key)
+				tracing.ObjectQueued(key)
 				lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 				utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
 				if oldKey != "" && key != oldKey {
 					c.workqueue[bkt].AddRateLimited(oldKey)
+					tracing.ObjectQueued(oldKey)
 					lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 					utils.AviLog.Debugf("key: %s, msg: UPDATE", oldKey)
 				}
@@ -816,7 +919,11 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 	}
 
 	if lib.GetServiceType() != lib.NodePortLocal {
-		c.informers.EpInformer.Informer().AddEventHandler(epEventHandler)
+		if c.informers.EpSlicesInformer != nil {
+			c.informers.EpSlicesInformer.Informer().AddEventHandler(epSliceEventHandler)
+		} else {
+			c.informers.EpInformer.Informer().AddEventHandler(epEventHandler)
+		}
 	}
 	c.informers.ServiceInformer.Informer().AddEventHandler(svcEventHandler)
 
@@ -836,6 +943,7 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 				key := utils.NodeObj + "/" + specJSON["node"]
 				bkt := utils.Bkt(lib.GetTenant(), numWorkers)
 				c.workqueue[bkt].AddRateLimited(key)
+				tracing.ObjectQueued(key)
 				lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 			},
 			DeleteFunc: func(obj interface{}) {
@@ -852,6 +960,7 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 				key := utils.NodeObj + "/" + specJSON["node"]
 				bkt := utils.Bkt(lib.GetTenant(), numWorkers)
 				c.workqueue[bkt].AddRateLimited(key)
+				tracing.ObjectQueued(key)
 				lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 			},
 		}
@@ -876,6 +985,7 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 				key := utils.NodeObj + "/" + host
 				bkt := utils.Bkt(lib.GetTenant(), numWorkers)
 				c.workqueue[bkt].AddRateLimited(key)
+				tracing.ObjectQueued(key)
 				lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 			},
 			DeleteFunc: func(obj interface{}) {
@@ -892,6 +1002,7 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 				key := utils.NodeObj + "/" + host
 				bkt := utils.Bkt(lib.GetTenant(), numWorkers)
 				c.workqueue[bkt].AddRateLimited(key)
+				tracing.ObjectQueued(key)
 				lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 			},
 		}
@@ -911,6 +1022,7 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 				key := utils.NodeObj + "/" + nodename
 				bkt := utils.Bkt(lib.GetTenant(), numWorkers)
 				c.workqueue[bkt].AddRateLimited(key)
+				tracing.ObjectQueued(key)
 				lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 			},
 			UpdateFunc: func(oldObj interface{}, newObj interface{}) {
@@ -923,6 +1035,7 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 				key := utils.NodeObj + "/" + nodename
 				bkt := utils.Bkt(lib.GetTenant(), numWorkers)
 				c.workqueue[bkt].AddRateLimited(key)
+				tracing.ObjectQueued(key)
 				lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 			},
 			DeleteFunc: func(obj interface{}) {
@@ -935,6 +1048,7 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 				key := utils.NodeObj + "/" + nodename
 				bkt := utils.Bkt(lib.GetTenant(), numWorkers)
 				c.workqueue[bkt].AddRateLimited(key)
+				tracing.ObjectQueued(key)
 				lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 			},
 		}
@@ -960,6 +1074,7 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 			}
 			bkt := utils.Bkt(namespace, numWorkers)
 			c.workqueue[bkt].AddRateLimited(key)
+			tracing.ObjectQueued(key)
 			lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 			utils.AviLog.Debugf("key: %s, msg: ADD", key)
 		},
@@ -992,6 +1107,7 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 				}
 				bkt := utils.Bkt(namespace, numWorkers)
 				c.workqueue[bkt].AddRateLimited(key)
+				tracing.ObjectQueued(key)
 				lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 				utils.AviLog.Debugf("key: %s, msg: DELETE", key)
 			}
@@ -1017,6 +1133,7 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 					}
 					bkt := utils.Bkt(namespace, numWorkers)
 					c.workqueue[bkt].AddRateLimited(key)
+					tracing.ObjectQueued(key)
 					lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 					utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
 				}
@@ -1073,6 +1190,7 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 			}
 			bkt := utils.Bkt(namespace, numWorkers)
 			c.workqueue[bkt].AddRateLimited(key)
+			tracing.ObjectQueued(key)
 			lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 			utils.AviLog.Debugf("key: %s, msg: ADD", key)
 		},
@@ -1108,6 +1226,7 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 			objects.SharedResourceVerInstanceLister().Delete(key)
 			bkt := utils.Bkt(namespace, numWorkers)
 			c.workqueue[bkt].AddRateLimited(key)
+			tracing.ObjectQueued(key)
 			lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 			utils.AviLog.Debugf("key: %s, msg: DELETE", key)
 		},
@@ -1126,6 +1245,7 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 				}
 				bkt := utils.Bkt(namespace, numWorkers)
 				c.workqueue[bkt].AddRateLimited(key)
+				tracing.ObjectQueued(key)
 				lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 				utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
 			}
@@ -1153,6 +1273,7 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 				return
 			}
 			c.workqueue[bkt].AddRateLimited(key)
+			tracing.ObjectQueued(key)
 			lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 			utils.AviLog.Debugf("key: %s, msg: ADD", key)
 		},
@@ -1183,6 +1304,7 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 			bkt := utils.Bkt(lib.GetTenant(), numWorkers)
 			objects.SharedResourceVerInstanceLister().Delete(key)
 			c.workqueue[bkt].AddRateLimited(key)
+			tracing.ObjectQueued(key)
 			lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 			utils.AviLog.Debugf("key: %s, msg: DELETE", key)
 		},
@@ -1202,6 +1324,7 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 			if isNodeUpdated(oldobj, node) {
 				bkt := utils.Bkt(lib.GetTenant(), numWorkers)
 				c.workqueue[bkt].AddRateLimited(key)
+				tracing.ObjectQueued(key)
 				lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 				utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
 			} else {
@@ -1230,6 +1353,7 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 				}
 				bkt := utils.Bkt(namespace, numWorkers)
 				c.workqueue[bkt].AddRateLimited(key)
+				tracing.ObjectQueued(key)
 				lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 				utils.AviLog.Debugf("key: %s, msg: ADD", key)
 			},
@@ -1255,6 +1379,7 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 				bkt := utils.Bkt(namespace, numWorkers)
 				objects.SharedResourceVerInstanceLister().Delete(key)
 				c.workqueue[bkt].AddRateLimited(key)
+				tracing.ObjectQueued(key)
 				lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 				utils.AviLog.Debugf("key: %s, msg: DELETE", key)
 			},
@@ -1270,6 +1395,7 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 					key := utils.IngressClass + "/" + utils.ObjKey(ingClass)
 					bkt := utils.Bkt(namespace, numWorkers)
 					c.workqueue[bkt].AddRateLimited(key)
+					tracing.ObjectQueued(key)
 					lib.IncrementQueueCounter(utils.ObjectIngestionLayer)
 					utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
 				}
@@ -1337,15 +1463,21 @@ func checkAviSecretUpdateAndShutdown(secret *corev1.Secret) bool {
 
 func (c *AviController) Start(stopCh <-chan struct{}) {
 	go c.informers.ServiceInformer.Informer().Run(stopCh)
-	go c.informers.EpInformer.Informer().Run(stopCh)
 	go c.informers.NSInformer.Informer().Run(stopCh)
 
 	informersList := []cache.InformerSynced{
-		c.informers.EpInformer.Informer().HasSynced,
 		c.informers.ServiceInformer.Informer().HasSynced,
 		c.informers.NSInformer.Informer().HasSynced,
 	}
 
+	if c.informers.EpSlicesInformer != nil {
+		go c.informers.EpSlicesInformer.Informer().Run(stopCh)
+		informersList = append(informersList, c.informers.EpSlicesInformer.Informer().HasSynced)
+	} else {
+		go c.informers.EpInformer.Informer().Run(stopCh)
+		informersList = append(informersList, c.informers.EpInformer.Informer().HasSynced)
+	}
+
 	if !lib.AviSecretInitialized {
 		go c.informers.SecretInformer.Informer().Run(stopCh)
 		informersList = append(informersList, c.informers.SecretInformer.Informer().HasSynced)
@@ -1461,7 +1593,8 @@ func (c *AviController) Start(stopCh <-chan struct{}) {
 
 func isServiceLBType(svcObj *corev1.Service) bool {
 	// If we don't find a service or it is not of type loadbalancer - return false.
-	if svcObj.Spec.Type == "LoadBalancer" {
+	// Services of type loadbalancer handled by another LB provider are not considered either.
+	if svcObj.Spec.Type == "LoadBalancer" && lib.IsValidLoadBalancerClass(svcObj) {
 		return true
 	}
 	return false
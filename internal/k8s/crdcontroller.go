@@ -835,7 +835,9 @@ func (c *AviController) SetupServiceImportEventHandlers(numWorkers uint32) {
 	c.informers.ServiceImportInformer.Informer().AddEventHandler(serviceImportEventHandler)
 }
 
-func checkRefsOnController(key string, refMap map[string]string) error {
+// CheckRefsOnController checks whether the provided refs, a map of the ref name to the ref type,
+// exist on the controller.
+func CheckRefsOnController(key string, refMap map[string]string) error {
 	for k, value := range refMap {
 		if k == "" {
 			continue
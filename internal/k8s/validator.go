@@ -16,9 +16,11 @@ package k8s
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"regexp"
+	"strings"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
@@ -47,6 +49,39 @@ type (
 	leader   struct{}
 )
 
+// refError is returned by the validations when an object referred to in a CRD can not be found, for the
+// ResolvedRefs condition of the CRD.
+type refError struct {
+	error
+}
+
+func (e *refError) Unwrap() error {
+	return e.error
+}
+
+// checkRefs checks the refs to the Avi objects with CheckRefsOnController, and returns a refError if one of them
+// is not found.
+func checkRefs(key string, refData map[string]string) error {
+	if err := CheckRefsOnController(key, refData); err != nil {
+		return &refError{err}
+	}
+	return nil
+}
+
+// rejectedStatus returns the status of a CRD rejected by the validations with err.
+func rejectedStatus(err error) status.UpdateCRDStatusOptions {
+	updateStatus := status.UpdateCRDStatusOptions{
+		Status: lib.StatusRejected,
+		Error:  err.Error(),
+		Reason: lib.CRDReasonInvalid,
+	}
+	var refErr *refError
+	if errors.As(err, &refErr) {
+		updateStatus.Reason = lib.CRDReasonRefNotFound
+	}
+	return updateStatus
+}
+
 func NewValidator() Validator {
 	if lib.AKOControlConfig().IsLeader() {
 		return &leader{}
@@ -57,13 +92,28 @@ func NewValidator() Validator {
 // validateHostRuleObj would do validation checks
 // update internal CRD caches, and push relevant ingresses to ingestion
 func (l *leader) ValidateHostRuleObj(key string, hostrule *akov1beta1.HostRule) error {
+	if err := validateHostRuleSpec(key, hostrule); err != nil {
+		status.UpdateHostRuleStatus(key, hostrule, rejectedStatus(err))
+		return err
+	}
+
+	// No need to update status of hostrule object as accepted since this generation was accepted before.
+	if hostrule.Status.Status == lib.StatusAccepted && hostrule.Status.ObservedGeneration == hostrule.Generation {
+		return nil
+	}
+
+	status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusAccepted, Error: ""})
+	return nil
+}
+
+// validateHostRuleSpec checks the hostrule spec, and the refs to the Avi objects in it.
+func validateHostRuleSpec(key string, hostrule *akov1beta1.HostRule) error {
 
 	var err error
 	fqdn := hostrule.Spec.VirtualHost.Fqdn
 	foundHost, foundHR := objects.SharedCRDLister().GetFQDNToHostruleMapping(fqdn)
 	if foundHost && foundHR != hostrule.Namespace+"/"+hostrule.Name {
 		err = fmt.Errorf("duplicate fqdn %s found in %s", fqdn, foundHR)
-		status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 		return err
 	}
 
@@ -80,7 +130,6 @@ func (l *leader) ValidateHostRuleObj(key string, hostrule *akov1beta1.HostRule)
 		re := regexp.MustCompile(lib.IPRegex)
 		if !re.MatchString(hostrule.Spec.VirtualHost.TCPSettings.LoadBalancerIP) {
 			err = fmt.Errorf("loadBalancerIP %s is not a valid IP", hostrule.Spec.VirtualHost.TCPSettings.LoadBalancerIP)
-			status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 			return err
 		}
 	}
@@ -88,7 +137,6 @@ func (l *leader) ValidateHostRuleObj(key string, hostrule *akov1beta1.HostRule)
 	if hostrule.Spec.VirtualHost.Gslb.Fqdn != "" {
 		if fqdn == hostrule.Spec.VirtualHost.Gslb.Fqdn {
 			err = fmt.Errorf("GSLB FQDN and local FQDN are same")
-			status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 			return err
 		}
 	}
@@ -103,7 +151,18 @@ func (l *leader) ValidateHostRuleObj(key string, hostrule *akov1beta1.HostRule)
 		}
 		if !sslEnabled {
 			err = fmt.Errorf("Hosting parent virtualservice must have SSL enabled")
-			status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
+			return err
+		}
+	}
+
+	if hostrule.Spec.VirtualHost.RateLimit != nil {
+		if err = validateRateLimit(hostrule.Spec.VirtualHost.RateLimit); err != nil {
+			return err
+		}
+	}
+
+	if hostrule.Spec.VirtualHost.TLS.ClientCertificate != nil {
+		if err = validateClientCertificate(hostrule); err != nil {
 			return err
 		}
 	}
@@ -111,26 +170,22 @@ func (l *leader) ValidateHostRuleObj(key string, hostrule *akov1beta1.HostRule)
 	if hostrule.Spec.VirtualHost.Aliases != nil {
 		if hostrule.Spec.VirtualHost.FqdnType != akov1beta1.Exact {
 			err = fmt.Errorf("Aliases is supported only when FQDN type is set as Exact")
-			status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 			return err
 		}
 
 		if utils.HasElem(hostrule.Spec.VirtualHost.Aliases, fqdn) {
 			err = fmt.Errorf("Duplicate entry found. Aliases field has same entry as the FQDN field")
-			status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 			return err
 		}
 
 		if utils.ContainsDuplicate(hostrule.Spec.VirtualHost.Aliases) {
 			err = fmt.Errorf("Aliases must be unique")
-			status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 			return err
 		}
 
 		if hostrule.Spec.VirtualHost.Gslb.Fqdn != "" &&
 			utils.HasElem(hostrule.Spec.VirtualHost.Aliases, hostrule.Spec.VirtualHost.Gslb.Fqdn) {
 			err = fmt.Errorf("Aliases must not contain GSLB FQDN")
-			status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 			return err
 		}
 
@@ -142,7 +197,6 @@ func (l *leader) ValidateHostRuleObj(key string, hostrule *akov1beta1.HostRule)
 			for _, alias := range hostrule.Spec.VirtualHost.Aliases {
 				if utils.HasElem(aliases, alias) {
 					err = fmt.Errorf("%s is already in use by hostrule %s", alias, cachedFQDN)
-					status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 					return err
 				}
 			}
@@ -164,7 +218,6 @@ func (l *leader) ValidateHostRuleObj(key string, hostrule *akov1beta1.HostRule)
 		secretName := hostrule.Spec.VirtualHost.TLS.SSLKeyCertificate.Name
 		err := validateSecretReferenceInHostrule(hostrule.Namespace, secretName)
 		if err != nil {
-			status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 			return err
 		}
 	}
@@ -176,12 +229,22 @@ func (l *leader) ValidateHostRuleObj(key string, hostrule *akov1beta1.HostRule)
 		secretName := hostrule.Spec.VirtualHost.TLS.SSLKeyCertificate.AlternateCertificate.Name
 		err := validateSecretReferenceInHostrule(hostrule.Namespace, secretName)
 		if err != nil {
-			status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 			return err
 		}
 	}
+	if clientCertificate := hostrule.Spec.VirtualHost.TLS.ClientCertificate; clientCertificate != nil &&
+		clientCertificate.Mode != akov1beta1.HostRuleClientCertificateModeNone {
+		if clientCertificate.CA.Type == akov1beta1.HostRuleSecretTypeAviReference {
+			refData[clientCertificate.CA.Name] = "PKIProfile"
+		}
+		if clientCertificate.CA.Type == akov1beta1.HostRuleSecretTypeSecretReference {
+			err := validateSecretReferenceInHostrule(hostrule.Namespace, clientCertificate.CA.Name)
+			if err != nil {
+				return err
+			}
+		}
+	}
 	if len(hostrule.Spec.VirtualHost.ICAPProfile) > 1 {
-		status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: "Can only have 1 ICAP profile associated with VS"})
 		return fmt.Errorf("Can only have 1 ICAP profile associated with VS")
 	} else {
 		for _, icapprofile := range hostrule.Spec.VirtualHost.ICAPProfile {
@@ -203,17 +266,88 @@ func (l *leader) ValidateHostRuleObj(key string, hostrule *akov1beta1.HostRule)
 		refData[hostrule.Spec.VirtualHost.NetworkSecurityPolicy] = "NetworkSecurityPolicy"
 	}
 
-	if err := checkRefsOnController(key, refData); err != nil {
-		status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
-		return err
+	return checkRefs(key, refData)
+}
+
+func validateRateLimit(rateLimit *akov1beta1.HostRuleRateLimit) error {
+	if requests := rateLimit.Requests; requests != nil {
+		if requests.Count == 0 || requests.Period == 0 {
+			return fmt.Errorf("rateLimit requests must have a non-zero count and period")
+		}
+		switch requests.Key {
+		case "", akov1beta1.HostRuleRateLimitKeyClientIP:
+			if requests.Header != "" {
+				return fmt.Errorf("rateLimit requests header must be specified only when key is \"%s\"", akov1beta1.HostRuleRateLimitKeyHeader)
+			}
+		case akov1beta1.HostRuleRateLimitKeyHeader:
+			if requests.Header == "" {
+				return fmt.Errorf("rateLimit requests header must be specified when key is \"%s\"", akov1beta1.HostRuleRateLimitKeyHeader)
+			}
+		default:
+			return fmt.Errorf("rateLimit requests key %s is not supported", requests.Key)
+		}
+		switch requests.Action.Type {
+		case "", akov1beta1.HostRuleRateLimitActionDrop, akov1beta1.HostRuleRateLimitActionTooManyRequests:
+			if requests.Action.Redirect != nil {
+				return fmt.Errorf("rateLimit action redirect must be specified only when type is \"%s\"", akov1beta1.HostRuleRateLimitActionRedirect)
+			}
+		case akov1beta1.HostRuleRateLimitActionRedirect:
+			redirect := requests.Action.Redirect
+			if redirect == nil || (redirect.Host == "" && redirect.Path == "" && redirect.Port == 0 && redirect.Protocol == "") {
+				return fmt.Errorf("rateLimit action redirect must be specified when type is \"%s\"", akov1beta1.HostRuleRateLimitActionRedirect)
+			}
+			if redirect.Protocol != "" && !strings.EqualFold(redirect.Protocol, "HTTP") && !strings.EqualFold(redirect.Protocol, "HTTPS") {
+				return fmt.Errorf("rateLimit action redirect protocol %s is not supported", redirect.Protocol)
+			}
+			if redirect.StatusCode != 0 && redirect.StatusCode != 301 && redirect.StatusCode != 302 && redirect.StatusCode != 307 {
+				return fmt.Errorf("rateLimit action redirect statusCode %d is not supported", redirect.StatusCode)
+			}
+		default:
+			return fmt.Errorf("rateLimit action type %s is not supported", requests.Action.Type)
+		}
 	}
+	if connections := rateLimit.Connections; connections != nil {
+		if connections.Count == 0 || connections.Period == 0 {
+			return fmt.Errorf("rateLimit connections must have a non-zero count and period")
+		}
+	}
+	return nil
+}
 
-	// No need to update status of hostrule object as accepted since it was accepted before.
-	if hostrule.Status.Status == lib.StatusAccepted {
+func validateClientCertificate(hostrule *akov1beta1.HostRule) error {
+	clientCertificate := hostrule.Spec.VirtualHost.TLS.ClientCertificate
+	switch clientCertificate.Mode {
+	case "", akov1beta1.HostRuleClientCertificateModeRequire, akov1beta1.HostRuleClientCertificateModeRequest:
+		if clientCertificate.CA.Name == "" {
+			return fmt.Errorf("clientCertificate ca must be specified when mode is not \"%s\"", akov1beta1.HostRuleClientCertificateModeNone)
+		}
+		if clientCertificate.CA.Type != akov1beta1.HostRuleSecretTypeAviReference &&
+			clientCertificate.CA.Type != akov1beta1.HostRuleSecretTypeSecretReference {
+			return fmt.Errorf("clientCertificate ca type %s is not supported", clientCertificate.CA.Type)
+		}
+		// the client certificate is configured in the application profile created by AKO for the virtual host
+		if hostrule.Spec.VirtualHost.ApplicationProfile != "" {
+			return fmt.Errorf("clientCertificate and applicationProfile can not be specified together, configure the client certificate in the application profile instead")
+		}
+	case akov1beta1.HostRuleClientCertificateModeNone:
 		return nil
+	default:
+		return fmt.Errorf("clientCertificate mode %s is not supported", clientCertificate.Mode)
 	}
 
-	status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{Status: lib.StatusAccepted, Error: ""})
+	var headerNames []string
+	for _, header := range clientCertificate.Headers {
+		if header.Name == "" {
+			return fmt.Errorf("clientCertificate header name must be specified")
+		}
+		if utils.HasElem(headerNames, strings.ToLower(header.Name)) {
+			return fmt.Errorf("clientCertificate header %s is specified more than once", header.Name)
+		}
+		headerNames = append(headerNames, strings.ToLower(header.Name))
+		if lib.GetClientCertificateHeaderVariable(header.Value) == "" {
+			return fmt.Errorf("clientCertificate header value %s is not supported", header.Value)
+		}
+	}
 	return nil
 }
 
@@ -228,8 +362,10 @@ func validateSecretReferenceInHostrule(namespace, secretName string) error {
 		return err
 	}
 
-	_, err := utils.GetInformers().SecretInformer.Lister().Secrets(namespace).Get(secretName)
-	return err
+	if _, err := utils.GetInformers().SecretInformer.Lister().Secrets(namespace).Get(secretName); err != nil {
+		return &refError{err}
+	}
+	return nil
 }
 
 func validateSecretReferenceInSSORule(namespace, secretName string) (*v1.Secret, error) {
@@ -244,22 +380,39 @@ func validateSecretReferenceInSSORule(namespace, secretName string) (*v1.Secret,
 	}
 
 	secretObj, err := utils.GetInformers().SecretInformer.Lister().Secrets(namespace).Get(secretName)
-	return secretObj, err
+	if err != nil {
+		return nil, &refError{err}
+	}
+	return secretObj, nil
 }
 
 // validateHTTPRuleObj would do validation checks
 // update internal CRD caches, and push relevant ingresses to ingestion
 func (l *leader) ValidateHTTPRuleObj(key string, httprule *akov1beta1.HTTPRule) error {
+	if err := validateHTTPRuleSpec(key, httprule); err != nil {
+		status.UpdateHTTPRuleStatus(key, httprule, rejectedStatus(err))
+		return err
+	}
+
+	// No need to update status of httprule object as accepted since this generation was accepted before.
+	if httprule.Status.Status == lib.StatusAccepted && httprule.Status.ObservedGeneration == httprule.Generation {
+		return nil
+	}
+
+	status.UpdateHTTPRuleStatus(key, httprule, status.UpdateCRDStatusOptions{
+		Status: lib.StatusAccepted,
+		Error:  "",
+	})
+	return nil
+}
 
+// validateHTTPRuleSpec checks the httprule spec, and the refs to the Avi objects in it.
+func validateHTTPRuleSpec(key string, httprule *akov1beta1.HTTPRule) error {
 	refData := make(map[string]string)
 	for _, path := range httprule.Spec.Paths {
 		if path.TLS.PKIProfile != "" && path.TLS.DestinationCA != "" {
 			//if both pkiProfile and destCA set, reject httprule
-			status.UpdateHTTPRuleStatus(key, httprule, status.UpdateCRDStatusOptions{
-				Status: lib.StatusRejected,
-				Error:  lib.HttpRulePkiAndDestCASetErr,
-			})
-			return fmt.Errorf("key: %s, msg: %s", key, lib.HttpRulePkiAndDestCASetErr)
+			return errors.New(lib.HttpRulePkiAndDestCASetErr)
 		}
 		refData[path.TLS.SSLProfile] = "SslProfile"
 		refData[path.ApplicationPersistence] = "ApplicationPersistence"
@@ -270,39 +423,98 @@ func (l *leader) ValidateHTTPRuleObj(key string, httprule *akov1beta1.HTTPRule)
 		for _, hm := range path.HealthMonitors {
 			refData[hm] = "HealthMonitor"
 		}
+
+		if err := validateHTTPRuleBackends(path); err != nil {
+			return err
+		}
 	}
 
-	if err := checkRefsOnController(key, refData); err != nil {
-		status.UpdateHTTPRuleStatus(key, httprule, status.UpdateCRDStatusOptions{
-			Status: lib.StatusRejected,
-			Error:  err.Error(),
-		})
+	return checkRefs(key, refData)
+}
+
+func validateHTTPRuleBackends(path akov1beta1.HTTPRulePaths) error {
+	var totalWeight int32
+	var backends []string
+	for _, backend := range path.Backends {
+		if backend.ServiceName == "" || backend.Port <= 0 || backend.Port > 65535 {
+			return fmt.Errorf("backends in path %s must have a serviceName and a valid port", path.Target)
+		}
+		backendKey := fmt.Sprintf("%s:%d", backend.ServiceName, backend.Port)
+		if utils.HasElem(backends, backendKey) {
+			return fmt.Errorf("backend %s is specified more than once in path %s", backendKey, path.Target)
+		}
+		backends = append(backends, backendKey)
+
+		if backend.Match == nil {
+			if backend.Weight <= 0 || backend.Weight >= 100 {
+				return fmt.Errorf("backend %s in path %s must have a weight between 1 and 99, or a match", backendKey, path.Target)
+			}
+			totalWeight += backend.Weight
+			continue
+		}
+		if backend.Weight != 0 {
+			return fmt.Errorf("weight and match can not be specified together for backend %s in path %s", backendKey, path.Target)
+		}
+		match := backend.Match
+		if (match.Header == nil) == (match.Cookie == nil) {
+			return fmt.Errorf("match for backend %s in path %s must have exactly one of header and cookie", backendKey, path.Target)
+		}
+		for _, value := range []*akov1beta1.HTTPRuleMatchValue{match.Header, match.Cookie} {
+			if value != nil && (value.Name == "" || value.Value == "") {
+				return fmt.Errorf("match for backend %s in path %s must have a name and a value", backendKey, path.Target)
+			}
+		}
+	}
+	// the service of the ingress/route path receives the rest of the traffic
+	if totalWeight >= 100 {
+		return fmt.Errorf("sum of the backend weights in path %s must be less than 100", path.Target)
+	}
+	return nil
+}
+
+// validateAviInfraSetting would do validaion checks on the
+// ingested AviInfraSetting objects
+func (l *leader) ValidateAviInfraSetting(key string, infraSetting *akov1beta1.AviInfraSetting) error {
+	if err := validateAviInfraSettingSpec(key, infraSetting); err != nil {
+		status.UpdateAviInfraSettingStatus(key, infraSetting, rejectedStatus(err))
 		return err
 	}
 
-	// No need to update status of httprule object as accepted since it was accepted before.
-	if httprule.Status.Status == lib.StatusAccepted {
+	// This would add SEG labels only if they are not configured yet. In case there is a label mismatch
+	// to any pre-existing SEG labels, the AviInfraSettig CR will get Rejected from the CheckRefsOnController
+	// step before this.
+	segMgmtNetworK := ""
+	if infraSetting.Spec.SeGroup.Name != "" {
+		addSeGroupLabel(key, infraSetting.Spec.SeGroup.Name)
+		if lib.GetCloudType() == lib.CLOUD_VCENTER {
+			segMgmtNetworK = GetSEGManagementNetwork(infraSetting.Spec.SeGroup.Name)
+		}
+	}
+
+	if len(infraSetting.Spec.Network.VipNetworks) > 0 {
+		SetAviInfrasettingVIPNetworks(infraSetting.Name, segMgmtNetworK, infraSetting.Spec.SeGroup.Name, infraSetting.Spec.Network.VipNetworks)
+	}
+
+	if len(infraSetting.Spec.Network.NodeNetworks) > 0 {
+		SetAviInfrasettingNodeNetworks(infraSetting.Name, segMgmtNetworK, infraSetting.Spec.SeGroup.Name, infraSetting.Spec.Network.NodeNetworks)
+	}
+	// No need to update status of infra setting object as accepted since this generation was accepted before.
+	if infraSetting.Status.Status == lib.StatusAccepted && infraSetting.Status.ObservedGeneration == infraSetting.Generation {
 		return nil
 	}
 
-	status.UpdateHTTPRuleStatus(key, httprule, status.UpdateCRDStatusOptions{
+	status.UpdateAviInfraSettingStatus(key, infraSetting, status.UpdateCRDStatusOptions{
 		Status: lib.StatusAccepted,
 		Error:  "",
 	})
 	return nil
 }
 
-// validateAviInfraSetting would do validaion checks on the
-// ingested AviInfraSetting objects
-func (l *leader) ValidateAviInfraSetting(key string, infraSetting *akov1beta1.AviInfraSetting) error {
-
+// validateAviInfraSettingSpec checks the AviInfraSetting spec, and the refs to the Avi objects in it.
+func validateAviInfraSettingSpec(key string, infraSetting *akov1beta1.AviInfraSetting) error {
 	if ((infraSetting.Spec.Network.EnableRhi != nil && !*infraSetting.Spec.Network.EnableRhi) || infraSetting.Spec.Network.EnableRhi == nil) &&
 		len(infraSetting.Spec.Network.BgpPeerLabels) > 0 {
 		err := fmt.Errorf("BGPPeerLabels cannot be set if EnableRhi is false.")
-		status.UpdateAviInfraSettingStatus(key, infraSetting, status.UpdateCRDStatusOptions{
-			Status: lib.StatusRejected,
-			Error:  err.Error(),
-		})
 		return err
 	}
 
@@ -312,10 +524,6 @@ func (l *leader) ValidateAviInfraSetting(key string, infraSetting *akov1beta1.Av
 			re := regexp.MustCompile(lib.IPCIDRRegex)
 			if !re.MatchString(vipNetwork.Cidr) {
 				err := fmt.Errorf("invalid CIDR configuration %s detected for networkName %s in vipNetworkList", vipNetwork.Cidr, vipNetwork.NetworkName)
-				status.UpdateAviInfraSettingStatus(key, infraSetting, status.UpdateCRDStatusOptions{
-					Status: lib.StatusRejected,
-					Error:  err.Error(),
-				})
 				return err
 			}
 		}
@@ -323,10 +531,6 @@ func (l *leader) ValidateAviInfraSetting(key string, infraSetting *akov1beta1.Av
 			re := regexp.MustCompile(lib.IPV6CIDRRegex)
 			if !re.MatchString(vipNetwork.V6Cidr) {
 				err := fmt.Errorf("invalid IPv6 CIDR configuration %s detected for networkName %s in vipNetworkList", vipNetwork.V6Cidr, vipNetwork.NetworkName)
-				status.UpdateAviInfraSettingStatus(key, infraSetting, status.UpdateCRDStatusOptions{
-					Status: lib.StatusRejected,
-					Error:  err.Error(),
-				})
 				return err
 			}
 		}
@@ -359,49 +563,10 @@ func (l *leader) ValidateAviInfraSetting(key string, infraSetting *akov1beta1.Av
 		}
 		if !sslEnabled {
 			err := fmt.Errorf("One of the port in aviInfraSetting must have SSL enabled")
-			status.UpdateAviInfraSettingStatus(key, infraSetting, status.UpdateCRDStatusOptions{
-				Status: lib.StatusRejected,
-				Error:  err.Error(),
-			})
 			return err
 		}
 	}
-	if err := checkRefsOnController(key, refData); err != nil {
-		status.UpdateAviInfraSettingStatus(key, infraSetting, status.UpdateCRDStatusOptions{
-			Status: lib.StatusRejected,
-			Error:  err.Error(),
-		})
-		return err
-	}
-
-	// This would add SEG labels only if they are not configured yet. In case there is a label mismatch
-	// to any pre-existing SEG labels, the AviInfraSettig CR will get Rejected from the checkRefsOnController
-	// step before this.
-	segMgmtNetworK := ""
-	if infraSetting.Spec.SeGroup.Name != "" {
-		addSeGroupLabel(key, infraSetting.Spec.SeGroup.Name)
-		if lib.GetCloudType() == lib.CLOUD_VCENTER {
-			segMgmtNetworK = GetSEGManagementNetwork(infraSetting.Spec.SeGroup.Name)
-		}
-	}
-
-	if len(infraSetting.Spec.Network.VipNetworks) > 0 {
-		SetAviInfrasettingVIPNetworks(infraSetting.Name, segMgmtNetworK, infraSetting.Spec.SeGroup.Name, infraSetting.Spec.Network.VipNetworks)
-	}
-
-	if len(infraSetting.Spec.Network.NodeNetworks) > 0 {
-		SetAviInfrasettingNodeNetworks(infraSetting.Name, segMgmtNetworK, infraSetting.Spec.SeGroup.Name, infraSetting.Spec.Network.NodeNetworks)
-	}
-	// No need to update status of infra setting object as accepted since it was accepted before.
-	if infraSetting.Status.Status == lib.StatusAccepted {
-		return nil
-	}
-
-	status.UpdateAviInfraSettingStatus(key, infraSetting, status.UpdateCRDStatusOptions{
-		Status: lib.StatusAccepted,
-		Error:  "",
-	})
-	return nil
+	return checkRefs(key, refData)
 }
 
 // validateMultiClusterIngressObj validates the MCI CRD changes before pushing it to ingestion
@@ -452,12 +617,27 @@ func (l *leader) ValidateServiceImportObj(key string, serviceImport *akov1alpha1
 // ValidateSSORuleObj would do validation checks
 // update internal CRD caches, and push relevant ingresses to ingestion
 func (l *leader) ValidateSSORuleObj(key string, ssoRule *akov1alpha2.SSORule) error {
+	if err := validateSSORuleSpec(key, ssoRule); err != nil {
+		status.UpdateSSORuleStatus(key, ssoRule, rejectedStatus(err))
+		return err
+	}
+
+	// No need to update status of ssoRule object as accepted since this generation was accepted before.
+	if ssoRule.Status.Status == lib.StatusAccepted && ssoRule.Status.ObservedGeneration == ssoRule.Generation {
+		return nil
+	}
+
+	status.UpdateSSORuleStatus(key, ssoRule, status.UpdateCRDStatusOptions{Status: lib.StatusAccepted, Error: ""})
+	return nil
+}
+
+// validateSSORuleSpec checks the SSORule spec, and the refs to the Avi objects in it.
+func validateSSORuleSpec(key string, ssoRule *akov1alpha2.SSORule) error {
 	var err error
 	fqdn := *ssoRule.Spec.Fqdn
 	foundHost, foundSR := objects.SharedCRDLister().GetFQDNToSSORuleMapping(fqdn)
 	if foundHost && foundSR != ssoRule.Namespace+"/"+ssoRule.Name {
 		err = fmt.Errorf("duplicate fqdn %s found in %s", fqdn, foundSR)
-		status.UpdateSSORuleStatus(key, ssoRule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 		return err
 	}
 
@@ -465,7 +645,6 @@ func (l *leader) ValidateSSORuleObj(key string, ssoRule *akov1alpha2.SSORule) er
 
 	if ssoRule.Spec.SsoPolicyRef == nil {
 		err = fmt.Errorf("SsoPolicyRef is not specified")
-		status.UpdateSSORuleStatus(key, ssoRule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 		return err
 	}
 	refData[*ssoRule.Spec.SsoPolicyRef] = "SSOPolicy"
@@ -481,19 +660,16 @@ func (l *leader) ValidateSSORuleObj(key string, ssoRule *akov1alpha2.SSORule) er
 					clientSecret := *profile.AppSettings.ClientSecret
 					clientSecretObj, err := validateSecretReferenceInSSORule(ssoRule.Namespace, clientSecret)
 					if err != nil {
-						err = fmt.Errorf("Got error while fetching %s secret : %s", clientSecret, err.Error())
-						status.UpdateSSORuleStatus(key, ssoRule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
+						err = fmt.Errorf("Got error while fetching %s secret : %w", clientSecret, err)
 						return err
 					}
 					if clientSecretObj == nil {
 						err = fmt.Errorf("specified client secret is empty : %s", clientSecret)
-						status.UpdateSSORuleStatus(key, ssoRule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 						return err
 					}
 					clientSecretString := string(clientSecretObj.Data["clientSecret"])
 					if clientSecretString == "" {
 						err = fmt.Errorf("clientSecret field not found in %s secret", clientSecret)
-						status.UpdateSSORuleStatus(key, ssoRule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 						return err
 					}
 				}
@@ -501,12 +677,10 @@ func (l *leader) ValidateSSORuleObj(key string, ssoRule *akov1alpha2.SSORule) er
 				if profile.ResourceServer != nil {
 					if *profile.ResourceServer.AccessType == lib.ACCESS_TOKEN_TYPE_JWT && profile.ResourceServer.JwtParams == nil {
 						err = fmt.Errorf("Access Type is %s, but Jwt Params have not been specified", *profile.ResourceServer.AccessType)
-						status.UpdateSSORuleStatus(key, ssoRule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 						return err
 					}
 					if *profile.ResourceServer.AccessType == lib.ACCESS_TOKEN_TYPE_OPAQUE && profile.ResourceServer.OpaqueTokenParams == nil {
 						err = fmt.Errorf("Access Type is %s, but Opaque Token Params have not been specified", *profile.ResourceServer.AccessType)
-						status.UpdateSSORuleStatus(key, ssoRule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 						return err
 					}
 
@@ -514,19 +688,16 @@ func (l *leader) ValidateSSORuleObj(key string, ssoRule *akov1alpha2.SSORule) er
 						serverSecret := *profile.ResourceServer.OpaqueTokenParams.ServerSecret
 						serverSecretObj, err := utils.GetInformers().ClientSet.CoreV1().Secrets(ssoRule.Namespace).Get(context.TODO(), serverSecret, metav1.GetOptions{})
 						if err != nil {
-							err = fmt.Errorf("Got error while fetching %s secret : %s", serverSecret, err.Error())
-							status.UpdateSSORuleStatus(key, ssoRule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
+							err = fmt.Errorf("Got error while fetching %s secret : %w", serverSecret, &refError{err})
 							return err
 						}
 						if serverSecretObj == nil {
 							err = fmt.Errorf("specified server secret is empty : %s", serverSecret)
-							status.UpdateSSORuleStatus(key, ssoRule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 							return err
 						}
 						serverSecretString := string(serverSecretObj.Data["serverSecret"])
 						if serverSecretString == "" {
 							err = fmt.Errorf("serverSecret field not found in %s secret", serverSecret)
-							status.UpdateSSORuleStatus(key, ssoRule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
 							return err
 						}
 					}
@@ -542,33 +713,37 @@ func (l *leader) ValidateSSORuleObj(key string, ssoRule *akov1alpha2.SSORule) er
 		}
 	}
 
-	if err := checkRefsOnController(key, refData); err != nil {
-		status.UpdateSSORuleStatus(key, ssoRule, status.UpdateCRDStatusOptions{Status: lib.StatusRejected, Error: err.Error()})
+	return checkRefs(key, refData)
+}
+
+// ValidateL4RuleObj would do validation checks and updates the status before
+// pushing to ingestion
+func (l *leader) ValidateL4RuleObj(key string, l4Rule *akov1alpha2.L4Rule) error {
+	if err := validateL4RuleSpec(key, l4Rule); err != nil {
+		status.UpdateL4RuleStatus(key, l4Rule, rejectedStatus(err))
 		return err
 	}
 
-	// No need to update status of ssoRule object as accepted since it was accepted before.
-	if ssoRule.Status.Status == lib.StatusAccepted {
+	// No need to update status of l4rule object as accepted since this generation was accepted before.
+	if l4Rule.Status.Status == lib.StatusAccepted && l4Rule.Status.ObservedGeneration == l4Rule.Generation {
 		return nil
 	}
 
-	status.UpdateSSORuleStatus(key, ssoRule, status.UpdateCRDStatusOptions{Status: lib.StatusAccepted, Error: ""})
+	status.UpdateL4RuleStatus(key, l4Rule, status.UpdateCRDStatusOptions{
+		Status: lib.StatusAccepted,
+		Error:  "",
+	})
+
 	return nil
 }
 
-// ValidateL4RuleObj would do validation checks and updates the status before
-// pushing to ingestion
-func (l *leader) ValidateL4RuleObj(key string, l4Rule *akov1alpha2.L4Rule) error {
-
+// validateL4RuleSpec checks the l4rule spec, and the refs to the Avi objects in it.
+func validateL4RuleSpec(key string, l4Rule *akov1alpha2.L4Rule) error {
 	l4RuleSpec := l4Rule.Spec
 
 	if l4RuleSpec.LoadBalancerIP != nil &&
 		net.ParseIP(*l4RuleSpec.LoadBalancerIP) == nil {
 		err := fmt.Errorf("loadBalancerIP %s is not valid", *l4RuleSpec.LoadBalancerIP)
-		status.UpdateL4RuleStatus(key, l4Rule, status.UpdateCRDStatusOptions{
-			Status: lib.StatusRejected,
-			Error:  err.Error(),
-		})
 		return err
 	}
 
@@ -588,19 +763,11 @@ func (l *leader) ValidateL4RuleObj(key string, l4Rule *akov1alpha2.L4Rule) error
 		}
 		isL4SSL, err := checkForL4SSLAppProfile(key, *l4RuleSpec.ApplicationProfileRef)
 		if err != nil {
-			status.UpdateL4RuleStatus(key, l4Rule, status.UpdateCRDStatusOptions{
-				Status: lib.StatusRejected,
-				Error:  err.Error(),
-			})
 			return err
 		}
 		if isL4SSL {
 			if !isSSLEnabled {
 				sslErr := fmt.Errorf("SSL is not enabled in l4rule listener Spec but App Profile %s is of type SSL", *l4RuleSpec.ApplicationProfileRef)
-				status.UpdateL4RuleStatus(key, l4Rule, status.UpdateCRDStatusOptions{
-					Status: lib.StatusRejected,
-					Error:  sslErr.Error(),
-				})
 				return sslErr
 			}
 			if l4RuleSpec.SslProfileRef != nil {
@@ -612,10 +779,6 @@ func (l *leader) ValidateL4RuleObj(key string, l4Rule *akov1alpha2.L4Rule) error
 			if l4RuleSpec.NetworkProfileRef != nil {
 				isNetworkProfileTypeTCP, err = checkForNetworkProfileTypeTCP(key, *l4RuleSpec.NetworkProfileRef)
 				if err != nil {
-					status.UpdateL4RuleStatus(key, l4Rule, status.UpdateCRDStatusOptions{
-						Status: lib.StatusRejected,
-						Error:  err.Error(),
-					})
 					return err
 				}
 			}
@@ -623,27 +786,15 @@ func (l *leader) ValidateL4RuleObj(key string, l4Rule *akov1alpha2.L4Rule) error
 			if *l4RuleSpec.ApplicationProfileRef != utils.DEFAULT_L4_APP_PROFILE {
 				if isSSLEnabled {
 					sslErr := fmt.Errorf("SSL is enabled in l4rule listener Spec but App Profile %s is not of type SSL", *l4RuleSpec.ApplicationProfileRef)
-					status.UpdateL4RuleStatus(key, l4Rule, status.UpdateCRDStatusOptions{
-						Status: lib.StatusRejected,
-						Error:  sslErr.Error(),
-					})
 					return sslErr
 				}
 			}
 			if l4RuleSpec.SslProfileRef != nil {
 				sslProfileErr := fmt.Errorf("App Profile %s is not of type SSL but SslProfileRef is set", *l4RuleSpec.ApplicationProfileRef)
-				status.UpdateL4RuleStatus(key, l4Rule, status.UpdateCRDStatusOptions{
-					Status: lib.StatusRejected,
-					Error:  sslProfileErr.Error(),
-				})
 				return sslProfileErr
 			}
 			if len(l4RuleSpec.SslKeyAndCertificateRefs) != 0 {
 				sslKeyCertErr := fmt.Errorf("App Profile %s is not of type SSL but SslKeyAndCertificateRefs are set", *l4RuleSpec.ApplicationProfileRef)
-				status.UpdateL4RuleStatus(key, l4Rule, status.UpdateCRDStatusOptions{
-					Status: lib.StatusRejected,
-					Error:  sslKeyCertErr.Error(),
-				})
 				return sslKeyCertErr
 			}
 		}
@@ -688,33 +839,11 @@ func (l *leader) ValidateL4RuleObj(key string, l4Rule *akov1alpha2.L4Rule) error
 		}
 
 		if err := validateLBAlgorithm(backendProperties); err != nil {
-			status.UpdateL4RuleStatus(key, l4Rule, status.UpdateCRDStatusOptions{
-				Status: lib.StatusRejected,
-				Error:  err.Error(),
-			})
 			return err
 		}
 	}
 
-	if err := checkRefsOnController(key, refData); err != nil {
-		status.UpdateL4RuleStatus(key, l4Rule, status.UpdateCRDStatusOptions{
-			Status: lib.StatusRejected,
-			Error:  err.Error(),
-		})
-		return err
-	}
-
-	// No need to update status of l4rule object as accepted since it was accepted before.
-	if l4Rule.Status.Status == lib.StatusAccepted {
-		return nil
-	}
-
-	status.UpdateL4RuleStatus(key, l4Rule, status.UpdateCRDStatusOptions{
-		Status: lib.StatusAccepted,
-		Error:  "",
-	})
-
-	return nil
+	return checkRefs(key, refData)
 }
 
 func validateLBAlgorithm(backendProperties *akov1alpha2.BackendProperties) error {
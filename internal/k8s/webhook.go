@@ -0,0 +1,160 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api/models"
+	akov1alpha2 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha2"
+	akov1beta1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1beta1"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	"github.com/prometheus/client_golang/prometheus"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const ValidatingWebhookPath = "/validate"
+
+// ValidatingWebhook implements ApiModel. It validates the HostRule, HTTPRule, L4Rule, SSORule and AviInfraSetting
+// objects when they are created or updated, with the same checks done on them in the ingestion layer, so that the
+// invalid objects are rejected by the kubernetes API server instead of being marked rejected in their status.
+type ValidatingWebhook struct{}
+
+func (v *ValidatingWebhook) InitModel() {}
+
+func (v *ValidatingWebhook) ApiOperationMap(prometheusEnavbled bool, reg *prometheus.Registry) []models.OperationMap {
+	var operationMapList []models.OperationMap
+
+	validate := models.OperationMap{
+		Route:   ValidatingWebhookPath,
+		Method:  "POST",
+		Handler: ServeValidatingWebhook,
+	}
+	operationMapList = append(operationMapList, validate)
+	return operationMapList
+}
+
+// ServeValidatingWebhook responds to an AdmissionReview request with whether the object is allowed.
+func ServeValidatingWebhook(w http.ResponseWriter, r *http.Request) {
+	review := &admissionv1.AdmissionReview{}
+	if err := json.NewDecoder(r.Body).Decode(review); err != nil || review.Request == nil {
+		http.Error(w, "expected an AdmissionReview request", http.StatusBadRequest)
+		return
+	}
+
+	response := &admissionv1.AdmissionResponse{
+		UID:     review.Request.UID,
+		Allowed: true,
+	}
+	if err := validateAdmissionRequest(review.Request); err != nil {
+		utils.AviLog.Infof("Rejecting %s %s/%s: %v", review.Request.Kind.Kind, review.Request.Namespace, review.Request.Name, err)
+		response.Allowed = false
+		response.Result = &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Message: err.Error(),
+			Reason:  metav1.StatusReasonInvalid,
+			Code:    http.StatusUnprocessableEntity,
+		}
+	}
+	review.Request = nil
+	review.Response = response
+	utils.Respond(w, review)
+}
+
+// validateAdmissionRequest runs the checks of the ingestion layer on the object in the request. An update which
+// does not change the spec of the object is always allowed, so that the finalizers and labels of an object which
+// turned invalid can still be updated.
+func validateAdmissionRequest(req *admissionv1.AdmissionRequest) error {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return nil
+	}
+
+	switch req.Kind.Kind {
+	case lib.HostRule:
+		hostrule, oldHostrule := &akov1beta1.HostRule{}, &akov1beta1.HostRule{}
+		if err := decodeAdmissionObjects(req, hostrule, oldHostrule); err != nil {
+			return err
+		}
+		if req.Operation == admissionv1.Update && reflect.DeepEqual(hostrule.Spec, oldHostrule.Spec) {
+			return nil
+		}
+		return validateHostRuleSpec(lib.HostRule+"/"+utils.ObjKey(hostrule), hostrule)
+	case lib.HTTPRule:
+		httprule, oldHttprule := &akov1beta1.HTTPRule{}, &akov1beta1.HTTPRule{}
+		if err := decodeAdmissionObjects(req, httprule, oldHttprule); err != nil {
+			return err
+		}
+		if req.Operation == admissionv1.Update && reflect.DeepEqual(httprule.Spec, oldHttprule.Spec) {
+			return nil
+		}
+		return validateHTTPRuleSpec(lib.HTTPRule+"/"+utils.ObjKey(httprule), httprule)
+	case lib.L4Rule:
+		l4Rule, oldL4Rule := &akov1alpha2.L4Rule{}, &akov1alpha2.L4Rule{}
+		if err := decodeAdmissionObjects(req, l4Rule, oldL4Rule); err != nil {
+			return err
+		}
+		if req.Operation == admissionv1.Update && reflect.DeepEqual(l4Rule.Spec, oldL4Rule.Spec) {
+			return nil
+		}
+		return validateL4RuleSpec(lib.L4Rule+"/"+utils.ObjKey(l4Rule), l4Rule)
+	case lib.SSORule:
+		ssoRule, oldSSORule := &akov1alpha2.SSORule{}, &akov1alpha2.SSORule{}
+		if err := decodeAdmissionObjects(req, ssoRule, oldSSORule); err != nil {
+			return err
+		}
+		if req.Operation == admissionv1.Update && reflect.DeepEqual(ssoRule.Spec, oldSSORule.Spec) {
+			return nil
+		}
+		return validateSSORuleSpec(lib.SSORule+"/"+utils.ObjKey(ssoRule), ssoRule)
+	case lib.AviInfraSetting:
+		infraSetting, oldInfraSetting := &akov1beta1.AviInfraSetting{}, &akov1beta1.AviInfraSetting{}
+		if err := decodeAdmissionObjects(req, infraSetting, oldInfraSetting); err != nil {
+			return err
+		}
+		if req.Operation == admissionv1.Update && reflect.DeepEqual(infraSetting.Spec, oldInfraSetting.Spec) {
+			return nil
+		}
+		return validateAviInfraSettingSpec(lib.AviInfraSetting+"/"+utils.ObjKey(infraSetting), infraSetting)
+	}
+	utils.AviLog.Debugf("Not validating object of kind %s", req.Kind.Kind)
+	return nil
+}
+
+// decodeAdmissionObjects decodes the object in the request, and the object it replaces for an update.
+func decodeAdmissionObjects(req *admissionv1.AdmissionRequest, obj, oldObj metav1.Object) error {
+	if err := json.Unmarshal(req.Object.Raw, obj); err != nil {
+		return fmt.Errorf("unable to decode %s: %v", req.Kind.Kind, err)
+	}
+	// The name and namespace are not set in the object yet, if they are generated by the API server.
+	if obj.GetName() == "" {
+		obj.SetName(req.Name)
+	}
+	if obj.GetNamespace() == "" {
+		obj.SetNamespace(req.Namespace)
+	}
+	if req.Operation != admissionv1.Update {
+		return nil
+	}
+	if err := json.Unmarshal(req.OldObject.Raw, oldObj); err != nil {
+		return fmt.Errorf("unable to decode %s: %v", req.Kind.Kind, err)
+	}
+	return nil
+}
@@ -130,6 +130,10 @@ func AviPut(client *clients.AviClient, uri string, payload interface{}, response
 		}
 	}
 
+	if IsDryRunEnabled() {
+		utils.AviLog.Infof("Dry run mode, not sending PUT on uri %s", uri)
+		return nil
+	}
 	err := client.AviSession.Put(uri, payload, &response)
 	IncrementRestOpCouter(HTTPMethodPut, uri)
 	if err != nil {
@@ -167,6 +171,10 @@ func AviPost(client *clients.AviClient, uri string, payload interface{}, respons
 		}
 	}
 
+	if IsDryRunEnabled() {
+		utils.AviLog.Infof("Dry run mode, not sending POST on uri %s", uri)
+		return nil
+	}
 	err := client.AviSession.Post(uri, payload, &response)
 	if err != nil {
 		utils.AviLog.Warnf("msg: Unable to execute Post on uri %s %v", uri, err)
@@ -203,6 +211,10 @@ func AviDelete(client *clients.AviClient, uri string, retryNum ...int) error {
 		}
 	}
 
+	if IsDryRunEnabled() {
+		utils.AviLog.Infof("Dry run mode, not sending DELETE on uri %s", uri)
+		return nil
+	}
 	err := client.AviSession.Delete(uri)
 	if err != nil {
 		utils.AviLog.Warnf("msg: Unable to execute Delete on uri %s %v", uri, err)
@@ -45,6 +45,23 @@ const (
 	DEFAULT_DOMAIN                             = "DEFAULT_DOMAIN"
 	ADVANCED_L4                                = "ADVANCED_L4"
 	SERVICES_API                               = "SERVICES_API"
+	ENABLE_REST_TRANSACTION                    = "ENABLE_REST_TRANSACTION"
+	REST_CONCURRENCY                           = "REST_CONCURRENCY"
+	RETRY_MAX_ATTEMPTS                         = "RETRY_MAX_ATTEMPTS"
+	RETRY_BASE_DELAY                           = "RETRY_BASE_DELAY"
+	RETRY_MAX_DELAY                            = "RETRY_MAX_DELAY"
+	DRIFT_DETECTION_INTERVAL                   = "DRIFT_DETECTION_INTERVAL"
+	DRIFT_ACTIONS                              = "DRIFT_ACTIONS"
+	DriftActionReapply                         = "reapply"
+	DriftActionReport                          = "report"
+	DRY_RUN                                    = "DRY_RUN"
+	FAIR_QUEUING                               = "FAIR_QUEUING"
+	FAIR_QUEUE_WEIGHTS                         = "FAIR_QUEUE_WEIGHTS"
+	OTLP_TRACES_ENDPOINT                       = "OTLP_TRACES_ENDPOINT"
+	VALIDATING_WEBHOOK                         = "VALIDATING_WEBHOOK"
+	WEBHOOK_PORT                               = "WEBHOOK_PORT"
+	WebhookCertFile                            = "/etc/ako/webhook/tls.crt"
+	WebhookKeyFile                             = "/etc/ako/webhook/tls.key"
 	CLUSTER_NAME                               = "CLUSTER_NAME"
 	CLUSTER_ID                                 = "CLUSTER_ID"
 	CLOUD_VCENTER                              = "CLOUD_VCENTER"
@@ -63,7 +80,18 @@ const (
 	STATUS_REDIRECT                            = "HTTP_REDIRECT_STATUS_CODE_302"
 	CLOSE_CONNECTION                           = "HTTP_SECURITY_ACTION_CLOSE_CONN"
 	IS_IN                                      = "IS_IN"
-	SLOW_SYNC_TIME                             = 90 // seconds
+	RATE_LIMIT                                 = "HTTP_SECURITY_ACTION_RATE_LIMIT"
+	RL_ACTION_DROP_CONN                        = "RL_ACTION_DROP_CONN"
+	RL_ACTION_LOCAL_RSP                        = "RL_ACTION_LOCAL_RSP"
+	RL_ACTION_REDIRECT                         = "RL_ACTION_REDIRECT"
+	STATUS_TOO_MANY_REQUESTS                   = "HTTP_LOCAL_RESPONSE_STATUS_CODE_429"
+	SSL_CLIENT_CERTIFICATE_REQUIRE             = "SSL_CLIENT_CERTIFICATE_REQUIRE"
+	SSL_CLIENT_CERTIFICATE_REQUEST             = "SSL_CLIENT_CERTIFICATE_REQUEST"
+	APPLICATION_PROFILE_TYPE_HTTP              = "APPLICATION_PROFILE_TYPE_HTTP"
+	SLOW_SYNC_TIME                             = 90  // seconds
+	DEFAULT_RETRY_BASE_DELAY                   = 1   // seconds
+	DEFAULT_RETRY_MAX_DELAY                    = 300 // seconds
+	RETRY_JITTER_FACTOR                        = 0.2
 	LOG_LEVEL                                  = "logLevel"
 	EnableEvents                               = "enableEvents"
 	LAYER7_ONLY                                = "layer7Only"
@@ -115,9 +143,20 @@ const (
 	Gateway                                    = "Gateway"
 	GatewayClass                               = "GatewayClass"
 	HTTPRoute                                  = "HTTPRoute"
+	TCPRoute                                   = "TCPRoute"
+	UDPRoute                                   = "UDPRoute"
+	TLSRoute                                   = "TLSRoute"
+	GRPCRoute                                  = "GRPCRoute"
+	ReferenceGrant                             = "ReferenceGrant"
+	BackendTLSPolicy                           = "BackendTLSPolicy"
+	BackendPolicy                              = "BackendPolicy"
+	ConfigMap                                  = "ConfigMap"
 	DuplicateBackends                          = "MultipleBackendsWithSameServiceError"
 	DummyVSForStaleData                        = "DummyVSForStaleData"
 	ControllerReqWaitTime                      = 300
+	MacroAPIPath                               = "/api/macro"
+	MacroAPIBatchMinVersion                    = "22.1.1"
+	RolledBackError                            = "Rolled back due to subsequent error"
 	PassthroughInsecure                        = "-insecure"
 	AviControllerVSVipIDChangeError            = "Changing an existing VIP's vip_id is not supported"
 	AviControllerRecreateVIPError              = "If a new preferred IP is needed, please recreate the VIP"
@@ -147,18 +186,22 @@ const (
 	HTTPRewriteRule                            = "HTTP Header Rewrite Rule"
 	HTTPRedirectPolicy                         = "HTTP Redirect Policy"
 	HeaderRewritePolicy                        = "Header Rewrite Policy"
+	HTTPRateLimitPolicy                        = "HTTP Rate Limit Policy"
 	L4VS                                       = "L4 Virtual Service"
 	L4VIP                                      = "L4 VIP"
 	L4Pool                                     = "L4 Pool"
 	L4AdvPool                                  = "L4 Advance Pool"
 	L4PS                                       = "L4 Policyset"
 	L4PSRule                                   = "L4 Policyset Rule"
+	NSP                                        = "Network Security Policy"
 	SNIVS                                      = "SNI VirtualService"
 	VIP                                        = "VS VIP"
 	PG                                         = "Poolgroup"
 	PriorityLabel                              = "PriorityLabel"
 	SSLKeyCert                                 = "SSLKeyandCertificate"
 	PKIProfile                                 = "PKI Profile"
+	ApplicationProfile                         = "Application Profile"
+	TrafficCloneProfile                        = "Traffic Clone Profile"
 	PassthroughPG                              = "Passthrough PG"
 	Passthroughpool                            = "Passthrough pool"
 	PassthroughVS                              = "Passthrough VirtualService"
@@ -213,10 +256,13 @@ const (
 	Attached                 = "Attached"
 	Detached                 = "Detached"
 	InvalidConfiguration     = "InvalidConfiguration"
+	UnsupportedConfiguration = "UnsupportedConfiguration"
 	AKODeleteConfigSet       = "AKODeleteConfigSet"
 	AKODeleteConfigUnset     = "AKODeleteConfigUnset"
 	AKODeleteConfigDone      = "AKODeleteConfigDone"
 	AKODeleteConfigTimeout   = "AKODeleteConfigTimeout"
+	RetryExhausted           = "RetryExhausted"
+	DriftDetected            = "DriftDetected"
 	AKOGatewayEventComponent = "avi-kubernetes-operator-gateway-api"
 
 	DefaultIngressClassAnnotation  = "ingressclass.kubernetes.io/is-default-class"
@@ -225,6 +271,7 @@ const (
 	IngressFinalizer               = "ingress.ako.vmware.com/finalizer"
 	AkoGroup                       = "ako.vmware.com"
 	AviIngressController           = "ako.vmware.com/avi-lb"
+	AviLoadBalancerClass           = "ako.vmware.com/avi-lb"
 	AKOConditionType               = "ako.vmware.com/ObjectDeletionInProgress"
 	DefaultSecretEnabled           = "ako.vmware.com/enable-tls"
 	GatewayNameLabelKey            = "service.route.lbapi.run.tanzu.vmware.com/gateway-name"
@@ -306,6 +353,30 @@ const (
 	AviSettingNamespaceIndex = "aviSettingNamespaces"
 )
 
+// Condition types and reasons in the status of the AKO CRDs.
+const (
+	// CRDConditionAccepted is set to True when the CRD passes the validations in AKO.
+	CRDConditionAccepted = "Accepted"
+	// CRDConditionResolvedRefs is set to True when the objects referred to in the CRD are found.
+	CRDConditionResolvedRefs = "ResolvedRefs"
+	// CRDConditionProgrammed is set to True when the CRD is applied to at least one Avi object.
+	CRDConditionProgrammed = "Programmed"
+	// CRDConditionBackendsApplied is set to False when backends in the paths of an HTTPRule are ignored,
+	// and back to True once they are applied.
+	CRDConditionBackendsApplied = "BackendsApplied"
+
+	CRDReasonAccepted     = "Accepted"
+	CRDReasonInvalid      = "Invalid"
+	CRDReasonResolvedRefs = "ResolvedRefs"
+	CRDReasonRefNotFound  = "RefNotFound"
+	CRDReasonProgrammed   = "Programmed"
+	CRDReasonPending      = "Pending"
+	CRDReasonNotApplied   = "NotApplied"
+
+	CRDReasonBackendsApplied = "BackendsApplied"
+	CRDReasonBackendsIgnored = "BackendsIgnored"
+)
+
 // Passthrough deployment same in EVH and SNI. Not changing log messages.
 const (
 	PassthroughDatascript = `local avi_tls = require "Default-TLS"
@@ -31,6 +31,7 @@ import (
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api"
+	apimodels "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api/models"
 	akov1beta1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1beta1"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
@@ -135,7 +136,14 @@ func (c ServiceMetadataObj) ServiceMetadataMapping(objType string) ServiceMetada
 
 var RestOpPerKeyType *prometheus.CounterVec
 var TotalRestOp prometheus.Counter
+var RestOpLatency *prometheus.HistogramVec
+var RetryAttempts *prometheus.CounterVec
+var RetryDeadLetterKeys prometheus.Gauge
+var DriftedObjects *prometheus.CounterVec
 var ObjectsInQueue *prometheus.GaugeVec
+var ObjectsInQueuePerFlow *prometheus.GaugeVec
+var QueueLatency *prometheus.HistogramVec
+var SyncDuration *prometheus.HistogramVec
 var reg *prometheus.Registry
 
 func SetPrometheusRegistry() {
@@ -174,6 +182,63 @@ func RegisterPromMetrics() *prometheus.Registry {
 	)
 	reg.MustRegister(TotalRestOp)
 
+	RestOpLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "ako",
+			Subsystem: subSystem,
+			Name:      "rest_api_latency_seconds",
+			Help:      "Latency of the rest operations sent to controller from AKO per object type per rest type.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{
+			// Which object type is the operation for?
+			"model",
+			// Of what type is the operation?
+			"type",
+		},
+	)
+	reg.MustRegister(RestOpLatency)
+
+	RetryAttempts = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ako",
+			Subsystem: subSystem,
+			Name:      "retry_attempts",
+			Help:      "Number of keys published to the retry queues.",
+		},
+		[]string{
+			// Queue name
+			"queuename",
+		},
+	)
+	reg.MustRegister(RetryAttempts)
+
+	RetryDeadLetterKeys = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "ako",
+			Subsystem: subSystem,
+			Name:      "retry_dead_letter_keys",
+			Help:      "Number of keys which are not retried any further after exhausting their retry attempts.",
+		},
+	)
+	reg.MustRegister(RetryDeadLetterKeys)
+
+	DriftedObjects = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "ako",
+			Subsystem: subSystem,
+			Name:      "drifted_objects",
+			Help:      "Number of Avi objects found changed on the Avi controller outside of AKO.",
+		},
+		[]string{
+			// Object type
+			"objtype",
+			// Action taken on the drift
+			"action",
+		},
+	)
+	reg.MustRegister(DriftedObjects)
+
 	ObjectsInQueue = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: "ako",
@@ -187,6 +252,56 @@ func RegisterPromMetrics() *prometheus.Registry {
 		},
 	)
 	reg.MustRegister(ObjectsInQueue)
+
+	ObjectsInQueuePerFlow = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "ako",
+			Subsystem: subSystem,
+			Name:      "objects_in_queue_per_namespace",
+			Help:      "Number of objects of a namespace or tenant present in the queue, when fair queuing is enabled.",
+		},
+		[]string{
+			// Queue name
+			"queuename",
+			// Namespace for the ingestion layer, tenant for the graph layer
+			"namespace",
+		},
+	)
+	reg.MustRegister(ObjectsInQueuePerFlow)
+
+	QueueLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "ako",
+			Subsystem: subSystem,
+			Name:      "queue_latency_seconds",
+			Help:      "Time spent in the queue by the objects of a namespace or tenant, when fair queuing is enabled.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{
+			// Queue name
+			"queuename",
+			// Namespace for the ingestion layer, tenant for the graph layer
+			"namespace",
+		},
+	)
+	reg.MustRegister(QueueLatency)
+
+	SyncDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "ako",
+			Subsystem: subSystem,
+			Name:      "sync_duration_seconds",
+			Help:      "Time taken to sync an object, from its kubernetes event to the completion of its Avi rest operations and status update.",
+			Buckets:   []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+		},
+		[]string{
+			// Object type
+			"objtype",
+			// Namespace of the object, empty for cluster scoped objects
+			"namespace",
+		},
+	)
+	reg.MustRegister(SyncDuration)
 	return reg
 }
 
@@ -200,6 +315,25 @@ func DecrementQueueCounter(queueName string) {
 		ObjectsInQueue.With(prometheus.Labels{"queuename": queueName}).Dec()
 	}
 }
+
+func SetQueueDepthPerNamespace(queueName, namespace string, depth int) {
+	if IsPrometheusEnabled() {
+		ObjectsInQueuePerFlow.With(prometheus.Labels{"queuename": queueName, "namespace": namespace}).Set(float64(depth))
+	}
+}
+
+func ObserveQueueLatency(queueName, namespace string, wait time.Duration) {
+	if IsPrometheusEnabled() {
+		QueueLatency.With(prometheus.Labels{"queuename": queueName, "namespace": namespace}).Observe(wait.Seconds())
+	}
+}
+
+func ObserveSyncDuration(objType, namespace string, duration time.Duration) {
+	if IsPrometheusEnabled() {
+		SyncDuration.With(prometheus.Labels{"objtype": objType, "namespace": namespace}).Observe(duration.Seconds())
+	}
+}
+
 func IncrementRestOpCouter(restOpMethod, objName string) {
 	if IsPrometheusEnabled() {
 		TotalRestOp.Inc()
@@ -207,6 +341,30 @@ func IncrementRestOpCouter(restOpMethod, objName string) {
 	}
 }
 
+func ObserveRestOpLatency(restOpMethod, model string, start time.Time) {
+	if IsPrometheusEnabled() {
+		RestOpLatency.With(prometheus.Labels{"type": restOpMethod, "model": model}).Observe(time.Since(start).Seconds())
+	}
+}
+
+func IncrementRetryCounter(queueName string) {
+	if IsPrometheusEnabled() {
+		RetryAttempts.With(prometheus.Labels{"queuename": queueName}).Inc()
+	}
+}
+
+func SetDeadLetterKeysCounter(count int) {
+	if IsPrometheusEnabled() {
+		RetryDeadLetterKeys.Set(float64(count))
+	}
+}
+
+func IncrementDriftCounter(objType, action string) {
+	if IsPrometheusEnabled() {
+		DriftedObjects.With(prometheus.Labels{"objtype": objType, "action": action}).Inc()
+	}
+}
+
 type VSNameMetadata struct {
 	Name      string
 	Dedicated bool
@@ -506,6 +664,12 @@ func GetL7HttpRedirPolicy(vsName string) string {
 	return httpRedirectPolicy
 }
 
+func GetRateLimitPolicyName(vsName string) string {
+	rateLimitPolicy := vsName + "--rate-limit"
+	CheckObjectNameLength(rateLimitPolicy, HTTPRateLimitPolicy)
+	return rateLimitPolicy
+}
+
 func GetHeaderRewritePolicy(vsName, localHost string) string {
 	headerWriterPolicy := vsName + "--host-hdr-re-write" + "--" + localHost
 	CheckObjectNameLength(headerWriterPolicy, HeaderRewritePolicy)
@@ -662,6 +826,35 @@ func GetPoolPKIProfileName(poolName string) string {
 	return Encode(poolName+"-pkiprofile", PKIProfile)
 }
 
+func GetClientCertPKIProfileName(vsName string) string {
+	return Encode(vsName+"-client-ca", PKIProfile)
+}
+
+func GetClientCertAppProfileName(vsName string) string {
+	return Encode(vsName+"-client-cert", ApplicationProfile)
+}
+
+func GetTrafficCloneProfileName(vsName string) string {
+	return Encode(vsName+"-mirror", TrafficCloneProfile)
+}
+
+var clientCertificateHeaderVariables = map[akov1beta1.HostRuleClientCertificateHeaderValue]string{
+	akov1beta1.HostRuleClientCertificateHeaderCertificate:        "HTTP_POLICY_VAR_SSL_CLIENT_RAW",
+	akov1beta1.HostRuleClientCertificateHeaderEscapedCertificate: "HTTP_POLICY_VAR_SSL_CLIENT_ESCAPED",
+	akov1beta1.HostRuleClientCertificateHeaderSubject:            "HTTP_POLICY_VAR_SSL_CLIENT_SUBJECT",
+	akov1beta1.HostRuleClientCertificateHeaderIssuer:             "HTTP_POLICY_VAR_SSL_CLIENT_ISSUER",
+	akov1beta1.HostRuleClientCertificateHeaderSerial:             "HTTP_POLICY_VAR_SSL_CLIENT_SERIAL",
+	akov1beta1.HostRuleClientCertificateHeaderFingerprint:        "HTTP_POLICY_VAR_SSL_CLIENT_FINGERPRINT",
+	akov1beta1.HostRuleClientCertificateHeaderNotValidBefore:     "HTTP_POLICY_VAR_SSL_CLIENT_NOTVALIDBEFORE",
+	akov1beta1.HostRuleClientCertificateHeaderNotValidAfter:      "HTTP_POLICY_VAR_SSL_CLIENT_NOTVALIDAFTER",
+}
+
+// GetClientCertificateHeaderVariable returns the Avi SSL variable, which sets the value
+// of a client certificate header. An empty string is returned for unsupported values.
+func GetClientCertificateHeaderVariable(value akov1beta1.HostRuleClientCertificateHeaderValue) string {
+	return clientCertificateHeaderVariables[value]
+}
+
 var VRFContext string
 var VRFUuid string
 
@@ -716,6 +909,31 @@ func GetDefaultIngController() bool {
 	return false
 }
 
+func GetDefaultLBController() bool {
+	defaultLBCtrl := os.Getenv("DEFAULT_LB_CONTROLLER")
+	if defaultLBCtrl != "false" {
+		return true
+	}
+	return false
+}
+
+// GetLoadBalancerClass returns the spec.loadBalancerClass of the Services of type LoadBalancer handled by AKO.
+func GetLoadBalancerClass() string {
+	if lbClass := os.Getenv("LOAD_BALANCER_CLASS"); lbClass != "" {
+		return lbClass
+	}
+	return AviLoadBalancerClass
+}
+
+// IsValidLoadBalancerClass returns true if the Service of type LoadBalancer is to be handled by AKO,
+// i.e. its loadBalancerClass matches the one configured for AKO, or it is unset and AKO is the default LB controller.
+func IsValidLoadBalancerClass(svc *corev1.Service) bool {
+	if svc.Spec.LoadBalancerClass == nil {
+		return GetDefaultLBController()
+	}
+	return *svc.Spec.LoadBalancerClass == GetLoadBalancerClass()
+}
+
 func GetNamespaceToSync() string {
 	namespace := os.Getenv("SYNC_NAMESPACE")
 	if namespace != "" {
@@ -1043,6 +1261,141 @@ func CompareVersions(v1, cmpSign, v2 string) bool {
 	return false
 }
 
+// IsRestTransactionEnabled returns true if the rest operations of a virtualservice graph have to be
+// applied on the controller as a single transaction.
+func IsRestTransactionEnabled() bool {
+	if ok, _ := strconv.ParseBool(os.Getenv(ENABLE_REST_TRANSACTION)); ok {
+		return true
+	}
+	return false
+}
+
+// GetRestConcurrency returns the number of rest operations of a virtualservice graph which can be sent to the
+// controller in parallel. A value of 1 (default) keeps the rest operations serial.
+func GetRestConcurrency() int {
+	concurrency, err := strconv.Atoi(os.Getenv(REST_CONCURRENCY))
+	if err != nil || concurrency < 1 {
+		return 1
+	}
+	return concurrency
+}
+
+// GetRetryMaxAttempts returns the number of times a key is retried before it is moved to the dead-letter set.
+// A value of 0 (default) retries the key until it is synced.
+func GetRetryMaxAttempts() int {
+	maxAttempts, err := strconv.Atoi(os.Getenv(RETRY_MAX_ATTEMPTS))
+	if err != nil || maxAttempts < 0 {
+		return 0
+	}
+	return maxAttempts
+}
+
+// GetRetryBaseDelay returns the delay before the first retry of a key, which is doubled for every subsequent retry.
+func GetRetryBaseDelay() time.Duration {
+	baseDelay, err := strconv.Atoi(os.Getenv(RETRY_BASE_DELAY))
+	if err != nil || baseDelay < 1 {
+		baseDelay = DEFAULT_RETRY_BASE_DELAY
+	}
+	return time.Duration(baseDelay) * time.Second
+}
+
+// GetRetryMaxDelay returns the maximum delay before a retry of a key.
+func GetRetryMaxDelay() time.Duration {
+	maxDelay, err := strconv.Atoi(os.Getenv(RETRY_MAX_DELAY))
+	if err != nil || maxDelay < 1 {
+		maxDelay = DEFAULT_RETRY_MAX_DELAY
+	}
+	return time.Duration(maxDelay) * time.Second
+}
+
+// GetDriftDetectionInterval returns the interval at which the Avi objects are checked for changes made outside of AKO.
+// A value of 0 (default) disables drift detection.
+func GetDriftDetectionInterval() time.Duration {
+	interval, err := strconv.Atoi(os.Getenv(DRIFT_DETECTION_INTERVAL))
+	if err != nil || interval < 0 {
+		return 0
+	}
+	return time.Duration(interval) * time.Second
+}
+
+// GetDriftAction returns the action taken on an Avi object of the type which was changed outside of AKO.
+// The object is re-applied if it is configured so for the type, else the drift is only reported.
+func GetDriftAction(objType string) string {
+	driftActionsStr := os.Getenv(DRIFT_ACTIONS)
+	if driftActionsStr == "" || driftActionsStr == "null" {
+		return DriftActionReport
+	}
+	var driftActions map[string]string
+	if err := json.Unmarshal([]byte(driftActionsStr), &driftActions); err != nil {
+		utils.AviLog.Warnf("Unable to unmarshall json for driftActions :%v", err)
+		return DriftActionReport
+	}
+	if driftActions[objType] == DriftActionReapply {
+		return DriftActionReapply
+	}
+	return DriftActionReport
+}
+
+// IsDryRunEnabled returns true if the rest operations have to be recorded instead of being sent to the controller.
+// The mode set through the AKO API server takes precedence over the one set at startup.
+func IsDryRunEnabled() bool {
+	if enabled, ok := apimodels.DryRunPlans.IsEnabled(); ok {
+		return enabled
+	}
+	if ok, _ := strconv.ParseBool(os.Getenv(DRY_RUN)); ok {
+		return true
+	}
+	return false
+}
+
+// GetFairQueueOptions returns the options of the fair queuing mode of the ingestion and graph layer queues,
+// with the flow of a key returned by flowFunc. It returns nil if fair queuing is not enabled.
+func GetFairQueueOptions(flowFunc func(item interface{}) string) *utils.FairQueueOptions {
+	if ok, _ := strconv.ParseBool(os.Getenv(FAIR_QUEUING)); !ok {
+		return nil
+	}
+	options := &utils.FairQueueOptions{
+		FlowFunc:       flowFunc,
+		ObserveDepth:   SetQueueDepthPerNamespace,
+		ObserveLatency: ObserveQueueLatency,
+	}
+	weightsStr := os.Getenv(FAIR_QUEUE_WEIGHTS)
+	if weightsStr != "" && weightsStr != "null" {
+		if err := json.Unmarshal([]byte(weightsStr), &options.Weights); err != nil {
+			utils.AviLog.Warnf("Unable to unmarshall json for fairQueueWeights :%v", err)
+		}
+	}
+	return options
+}
+
+// GetOTLPTracesEndpoint returns the host:port of the OTLP/HTTP collector to which the sync traces are exported.
+// Tracing is disabled if it is not set.
+func GetOTLPTracesEndpoint() string {
+	return os.Getenv(OTLP_TRACES_ENDPOINT)
+}
+
+// IsValidatingWebhookEnabled returns true if AKO validates the AKO CRDs when they are applied, through the validating webhook.
+func IsValidatingWebhookEnabled() bool {
+	if ok, _ := strconv.ParseBool(os.Getenv(VALIDATING_WEBHOOK)); ok {
+		return true
+	}
+	return false
+}
+
+// The port to run the validating webhook server on
+func GetWebhookPort() string {
+	port := os.Getenv(WEBHOOK_PORT)
+	if port != "" {
+		return port
+	}
+	return "9443"
+}
+
+// IsMacroAPIBatchSupported returns true if the controller accepts a batch of objects in a single macro API call.
+func IsMacroAPIBatchSupported() bool {
+	return CompareVersions(AKOControlConfig().ControllerVersion(), ">=", MacroAPIBatchMinVersion)
+}
+
 func IsValidCni(returnErr *error) bool {
 	// if serviceType is set as NodePortLocal, then the CNI must be of type 'antrea'
 	if GetServiceType() == NodePortLocal && GetCNIPlugin() != ANTREA_CNI {
@@ -1355,10 +1708,10 @@ func PopulatePassthroughPoolMarkers(host, svcName, infrasettingName string) util
 func InformersToRegister(kclient *kubernetes.Clientset, oclient *oshiftclient.Clientset) ([]string, error) {
 	var isOshift bool
 	// Initialize the following informers in all AKO deployments. Provide AKO the ability to watch over
-	// Services, Endpoints, Secrets, ConfigMaps and Namespaces.
+	// Services, EndpointSlices (or Endpoints), Secrets, ConfigMaps and Namespaces.
 	allInformers := []string{
 		utils.ServiceInformer,
-		utils.EndpointInformer,
+		utils.GetEndpointInformer(kclient),
 		utils.SecretInformer,
 		utils.ConfigMapInformer,
 		utils.NSInformer,
@@ -1465,6 +1818,112 @@ func L4PolicyChecksum(ports []int64, protocols []string, ingestionMarkers utils.
 	return checksum
 }
 
+func NetworkSecurityPolicyChecksum(clientIPs []string, ingestionMarkers utils.AviObjectMarkers, markers []*models.RoleFilterMatchLabel, populateCache bool) uint32 {
+	sortedIPs := make([]string, len(clientIPs))
+	copy(sortedIPs, clientIPs)
+	sort.Strings(sortedIPs)
+	checksum := utils.Hash(utils.Stringify(sortedIPs))
+	if populateCache {
+		if markers != nil {
+			checksum += ObjectLabelChecksum(markers)
+		}
+		return checksum
+	}
+	checksum += GetMarkersChecksum(ingestionMarkers)
+	return checksum
+}
+
+func ClientCertAppProfileChecksum(mode, pkiProfile string, headers []*models.SSLClientRequestHeader, ingestionMarkers utils.AviObjectMarkers, markers []*models.RoleFilterMatchLabel, populateCache bool) uint32 {
+	var headerValues []string
+	for _, header := range headers {
+		if header.RequestHeader == nil || header.RequestHeaderValue == nil {
+			continue
+		}
+		headerValues = append(headerValues, *header.RequestHeader+":"+*header.RequestHeaderValue)
+	}
+	checksum := utils.Hash(mode + pkiProfile + utils.Stringify(headerValues))
+	if populateCache {
+		if markers != nil {
+			checksum += ObjectLabelChecksum(markers)
+		}
+		return checksum
+	}
+	checksum += GetMarkersChecksum(ingestionMarkers)
+	return checksum
+}
+
+func TrafficCloneProfileChecksum(cloneServers []string, ingestionMarkers utils.AviObjectMarkers, markers []*models.RoleFilterMatchLabel, populateCache bool) uint32 {
+	servers := make([]string, len(cloneServers))
+	copy(servers, cloneServers)
+	sort.Strings(servers)
+	checksum := utils.Hash(utils.Stringify(servers))
+	if populateCache {
+		if markers != nil {
+			checksum += ObjectLabelChecksum(markers)
+		}
+		return checksum
+	}
+	checksum += GetMarkersChecksum(ingestionMarkers)
+	return checksum
+}
+
+// GetTrafficCloneProfileServers returns the IP addresses of the clone servers of the traffic clone profiles created by AKO.
+func GetTrafficCloneProfileServers(trafficCloneProfile *models.TrafficCloneProfile) []string {
+	var servers []string
+	for _, cloneServer := range trafficCloneProfile.CloneServers {
+		if cloneServer.IPAddress == nil || cloneServer.IPAddress.Addr == nil {
+			continue
+		}
+		servers = append(servers, *cloneServer.IPAddress.Addr)
+	}
+	return servers
+}
+
+// GetClientCertAppProfileSettings returns the client certificate mode, the name of the PKI profile
+// and the client certificate headers of the application profiles created by AKO.
+func GetClientCertAppProfileSettings(appProfile *models.ApplicationProfile) (string, string, []*models.SSLClientRequestHeader) {
+	var mode, pkiProfile string
+	var headers []*models.SSLClientRequestHeader
+	if appProfile.HTTPProfile == nil {
+		return mode, pkiProfile, headers
+	}
+	if appProfile.HTTPProfile.SslClientCertificateMode != nil {
+		mode = *appProfile.HTTPProfile.SslClientCertificateMode
+	}
+	if appProfile.HTTPProfile.PkiProfileRef != nil {
+		// the ref is either /api/pkiprofile?name=<name> in the requests,
+		// or https://<controller>/api/pkiprofile/<uuid>#<name> in the responses
+		pkiProfileRef := *appProfile.HTTPProfile.PkiProfileRef
+		if strings.Contains(pkiProfileRef, "#") {
+			pkiProfile = pkiProfileRef[strings.LastIndex(pkiProfileRef, "#")+1:]
+		} else if strings.Contains(pkiProfileRef, "name=") {
+			pkiProfile = pkiProfileRef[strings.LastIndex(pkiProfileRef, "name=")+len("name="):]
+		}
+	}
+	if appProfile.HTTPProfile.SslClientCertificateAction != nil {
+		headers = appProfile.HTTPProfile.SslClientCertificateAction.Headers
+	}
+	return mode, pkiProfile, headers
+}
+
+// GetNSPAllowedClientIPs returns the client IP prefixes, outside of which the
+// network security policy rules created by AKO deny the traffic.
+func GetNSPAllowedClientIPs(rules []*models.NetworkSecurityRule) []string {
+	var clientIPs []string
+	for _, rule := range rules {
+		if rule.Match == nil || rule.Match.ClientIP == nil {
+			continue
+		}
+		for _, prefix := range rule.Match.ClientIP.Prefixes {
+			if prefix.IPAddr == nil || prefix.IPAddr.Addr == nil || prefix.Mask == nil {
+				continue
+			}
+			clientIPs = append(clientIPs, *prefix.IPAddr.Addr+"/"+strconv.Itoa(int(*prefix.Mask)))
+		}
+	}
+	return clientIPs
+}
+
 func IsNodePortMode() bool {
 	nodePortType := os.Getenv(SERVICE_TYPE)
 	if nodePortType == NODE_PORT {
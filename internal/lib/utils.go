@@ -53,7 +53,8 @@ func ExtractTypeNameNamespace(key string) (string, string, string) {
 
 func isServiceLBType(svcObj *corev1.Service) bool {
 	// If we don't find a service or it is not of type loadbalancer - return false.
-	if svcObj.Spec.Type == "LoadBalancer" {
+	// Services of type loadbalancer handled by another LB provider are not considered either.
+	if svcObj.Spec.Type == "LoadBalancer" && IsValidLoadBalancerClass(svcObj) {
 		return true
 	}
 	return false
@@ -178,7 +179,7 @@ func GetServicesForPod(pod *corev1.Pod) ([]string, []string) {
 			continue
 		}
 		svcKey := svc.Namespace + "/" + svc.Name
-		if svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
+		if svc.Spec.Type == corev1.ServiceTypeLoadBalancer && IsValidLoadBalancerClass(svc) {
 			lbList = append(lbList, svcKey)
 		}
 		if svc.Spec.Type != corev1.ServiceTypeNodePort {
@@ -94,6 +94,12 @@ type AviVsEvhSniModel interface {
 	GetAnalyticsPolicy() *avimodels.AnalyticsPolicy
 	SetAnalyticsPolicy(*avimodels.AnalyticsPolicy)
 
+	GetRequestsRateLimit() *avimodels.RateProfile
+	SetRequestsRateLimit(*avimodels.RateProfile)
+
+	GetConnectionsRateLimit() *avimodels.RateProfile
+	SetConnectionsRateLimit(*avimodels.RateProfile)
+
 	GetVSVIPLoadBalancerIP() string
 	SetVSVIPLoadBalancerIP(string)
 
@@ -105,6 +111,9 @@ type AviVsEvhSniModel interface {
 
 	GetNetworkSecurityPolicyRef() *string
 	SetNetworkSecurityPolicyRef(*string)
+
+	GetAppProfileRefs() []*AviApplicationProfileNode
+	SetAppProfileRefs([]*AviApplicationProfileNode)
 }
 
 type AviEvhVsNode struct {
@@ -134,7 +143,10 @@ type AviEvhVsNode struct {
 	CACertRefs          []*AviTLSKeyCertNode
 	SSLKeyCertRefs      []*AviTLSKeyCertNode
 	HttpPolicyRefs      []*AviHttpPolicySetNode
+	L4PolicyRefs        []*AviL4PolicyNode
 	VSVIPRefs           []*AviVSVIPNode
+	AppProfileRefs      []*AviApplicationProfileNode
+	CloneProfileRefs    []*AviTrafficCloneProfileNode
 	TLSType             string
 	ServiceMetadata     lib.ServiceMetadataObj
 	VrfContext          string
@@ -310,6 +322,30 @@ func (v *AviEvhVsNode) SetAnalyticsPolicy(policy *avimodels.AnalyticsPolicy) {
 	v.AnalyticsPolicy = policy
 }
 
+func (v *AviEvhVsNode) GetAppProfileRefs() []*AviApplicationProfileNode {
+	return v.AppProfileRefs
+}
+
+func (v *AviEvhVsNode) SetAppProfileRefs(appProfiles []*AviApplicationProfileNode) {
+	v.AppProfileRefs = appProfiles
+}
+
+func (v *AviEvhVsNode) GetRequestsRateLimit() *avimodels.RateProfile {
+	return v.RequestsRateLimit
+}
+
+func (v *AviEvhVsNode) SetRequestsRateLimit(rateLimit *avimodels.RateProfile) {
+	v.RequestsRateLimit = rateLimit
+}
+
+func (v *AviEvhVsNode) GetConnectionsRateLimit() *avimodels.RateProfile {
+	return v.ConnectionsRateLimit
+}
+
+func (v *AviEvhVsNode) SetConnectionsRateLimit(rateLimit *avimodels.RateProfile) {
+	v.ConnectionsRateLimit = rateLimit
+}
+
 func (v *AviEvhVsNode) GetVSVIPLoadBalancerIP() string {
 	if len(v.VSVIPRefs) > 0 {
 		return v.VSVIPRefs[0].IPAddress
@@ -621,6 +657,10 @@ func (v *AviEvhVsNode) CalculateCheckSum() {
 		checksumStringSlice = append(checksumStringSlice, "HttpPolicy"+httppol.Name)
 	}
 
+	for _, l4pol := range v.L4PolicyRefs {
+		checksumStringSlice = append(checksumStringSlice, "L4Policy"+l4pol.Name)
+	}
+
 	for _, cacert := range v.CACertRefs {
 		checksumStringSlice = append(checksumStringSlice, "CACert"+cacert.Name)
 	}
@@ -715,6 +755,14 @@ func (v *AviEvhVsNode) CalculateCheckSum() {
 		checksum += lib.GetAnalyticsPolicyChecksum(v.AnalyticsPolicy)
 	}
 
+	if v.RequestsRateLimit != nil {
+		checksum += utils.Hash(utils.Stringify(v.RequestsRateLimit))
+	}
+
+	if v.ConnectionsRateLimit != nil {
+		checksum += utils.Hash(utils.Stringify(v.ConnectionsRateLimit))
+	}
+
 	checksum += v.AviVsNodeGeneratedFields.CalculateCheckSumOfGeneratedCode()
 
 	if v.VHMatches != nil {
@@ -725,6 +773,10 @@ func (v *AviEvhVsNode) CalculateCheckSum() {
 		checksum += utils.Hash(v.DefaultPoolGroup)
 	}
 
+	for _, cloneProfile := range v.CloneProfileRefs {
+		checksum += utils.Hash(cloneProfile.Name)
+	}
+
 	v.CloudConfigCksum = checksum
 }
 
@@ -1804,6 +1856,7 @@ func (o *AviObjectGraph) RemovePoolNodeRefsFromEvh(poolName string, evhNode *Avi
 			break
 		}
 	}
+	evhNode.PoolRefs = removeHTTPRuleBackendPools(evhNode.PoolRefs, poolName)
 	utils.AviLog.Debugf("After removing the pool ref nodes are: %s", utils.Stringify(evhNode.PoolRefs))
 
 }
@@ -1829,6 +1882,7 @@ func (o *AviObjectGraph) RemoveHTTPRefsFromEvh(httpPol, hppmapName string, evhNo
 					break
 				}
 			}
+			evhNode.HttpPolicyRefs[i].HppMap = removeHTTPRuleBackendHppMaps(evhNode.HttpPolicyRefs[i].HppMap, hppmapName)
 			if len(pol.HppMap) == 0 {
 				utils.AviLog.Debugf("Removing http pol ref: %s", httpPol)
 				evhNode.HttpPolicyRefs = append(evhNode.HttpPolicyRefs[:i], evhNode.HttpPolicyRefs[i+1:]...)
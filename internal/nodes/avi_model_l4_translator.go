@@ -16,6 +16,7 @@ package nodes
 
 import (
 	"fmt"
+	"net"
 	"sort"
 	"strconv"
 	"strings"
@@ -34,7 +35,9 @@ import (
 	"github.com/vmware/alb-sdk/go/models"
 	avimodels "github.com/vmware/alb-sdk/go/models"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
@@ -142,6 +145,8 @@ func (o *AviObjectGraph) ConstructAviL4VsNode(svcObj *corev1.Service, key string
 		buildWithL4Rule(key, avi_vs_meta, l4Rule)
 	}
 
+	buildWithLoadBalancerSourceRanges(key, svcObj, avi_vs_meta)
+
 	if lib.HasSpecLoadBalancerIP(svcObj) {
 		vsVipNode.IPAddress = svcObj.Spec.LoadBalancerIP
 	} else if lib.HasLoadBalancerIPAnnotation(svcObj) {
@@ -170,6 +175,7 @@ func (o *AviObjectGraph) ConstructAviL4PolPoolNodes(svcObj *corev1.Service, vsNo
 		}
 	}
 
+	persistenceProfile := getSessionAffinityPersistenceProfile(key, svcObj)
 	protocolSet := sets.NewString()
 	for _, portProto := range vsNode.PortProto {
 		filterPort := portProto.Port
@@ -185,6 +191,11 @@ func (o *AviObjectGraph) ConstructAviL4PolPoolNodes(svcObj *corev1.Service, vsNo
 
 		buildPoolWithL4Rule(key, poolNode, l4Rule)
 
+		// The persistence profile of the L4Rule takes precedence over the sessionAffinity of the Service.
+		if persistenceProfile != nil && poolNode.ApplicationPersistenceProfileRef == nil {
+			poolNode.ApplicationPersistenceProfileRef = proto.String(*persistenceProfile)
+		}
+
 		if lib.IsIstioEnabled() {
 			poolNode.UpdatePoolNodeForIstio()
 		}
@@ -375,6 +386,12 @@ func PopulateServersForNodePort(poolNode *AviPoolNode, ns string, serviceName st
 		utils.AviLog.Debugf("key: %s, msg: ClusterIP is not processed in NodePort: %s", key, serviceName)
 		return poolMeta
 	}
+	// With the Local externalTrafficPolicy, the node port of a node only forwards the traffic
+	// to the endpoints on that node, hence only the nodes with ready endpoints are added as servers.
+	var nodesWithEndpoints sets.String
+	if svcObj.Spec.ExternalTrafficPolicy == corev1.ServiceExternalTrafficPolicyLocal {
+		nodesWithEndpoints = getNodesWithReadyEndpoints(ns, serviceName, key)
+	}
 	for _, port := range svcObj.Spec.Ports {
 		if port.Name != poolNode.PortName && len(svcObj.Spec.Ports) != 1 {
 			// continue only if port name does not match and its multiport svcobj
@@ -399,6 +416,9 @@ func PopulateServersForNodePort(poolNode *AviPoolNode, ns string, serviceName st
 				}
 
 			}
+			if nodesWithEndpoints != nil && !nodesWithEndpoints.Has(node.Name) {
+				continue
+			}
 			nodeIP, nodeIP6 := lib.GetIPFromNode(node)
 			var atype string
 			var serverIP avimodels.IPAddr
@@ -434,6 +454,40 @@ func PopulateServersForNodePort(poolNode *AviPoolNode, ns string, serviceName st
 	return poolMeta
 }
 
+// getNodesWithReadyEndpoints returns the names of the nodes which host a ready endpoint of the service.
+func getNodesWithReadyEndpoints(ns, serviceName, key string) sets.String {
+	nodeNames := sets.NewString()
+	if utils.GetInformers().EpSlicesInformer != nil {
+		selector := labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: serviceName})
+		epSlices, err := utils.GetInformers().EpSlicesInformer.Lister().EndpointSlices(ns).List(selector)
+		if err != nil {
+			utils.AviLog.Warnf("key: %s, msg: error while retrieving endpointslices: %s", key, err)
+			return nodeNames
+		}
+		for _, eps := range epSlices {
+			for _, endpoint := range eps.Endpoints {
+				if endpoint.NodeName != nil && (endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready) {
+					nodeNames.Insert(*endpoint.NodeName)
+				}
+			}
+		}
+		return nodeNames
+	}
+	epObj, err := utils.GetInformers().EpInformer.Lister().Endpoints(ns).Get(serviceName)
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: error while retrieving endpoints: %s", key, err)
+		return nodeNames
+	}
+	for _, ss := range epObj.Subsets {
+		for _, addr := range ss.Addresses {
+			if addr.NodeName != nil {
+				nodeNames.Insert(*addr.NodeName)
+			}
+		}
+	}
+	return nodeNames
+}
+
 func PopulateServers(poolNode *AviPoolNode, ns string, serviceName string, ingress bool, key string) []AviPoolMetaServer {
 
 	// Find the servers that match the port.
@@ -465,11 +519,39 @@ func PopulateServers(poolNode *AviPoolNode, ns string, serviceName string, ingre
 	} else {
 		v4Family = true
 	}
-	epObj, err := utils.GetInformers().EpInformer.Lister().Endpoints(ns).Get(serviceName)
-	if err != nil {
-		utils.AviLog.Warnf("key: %s, msg: error while retrieving endpoints: %s", key, err)
-		return nil
+	var pool_meta []AviPoolMetaServer
+	if utils.GetInformers().EpSlicesInformer != nil {
+		pool_meta = populateServersFromEndpointSlices(poolNode, ns, serviceName, v4enabled && v4Family, v6enabled && v6Family, key)
+	} else {
+		epObj, err := utils.GetInformers().EpInformer.Lister().Endpoints(ns).Get(serviceName)
+		if err != nil {
+			utils.AviLog.Warnf("key: %s, msg: error while retrieving endpoints: %s", key, err)
+			return nil
+		}
+		pool_meta = populateServersFromEndpoints(poolNode, epObj, v4enabled && v4Family, v6enabled && v6Family, key)
+	}
+	for _, server := range pool_meta {
+		if *server.Ip.Type == "V4" {
+			v4ServerCount++
+		} else {
+			v6ServerCount++
+		}
 	}
+	if len(pool_meta) == 0 {
+		utils.AviLog.Warnf("key: %s, msg: no servers for port: %v", key, poolNode.Port)
+	} else {
+		if v4Family && v4ServerCount == 0 {
+			utils.AviLog.Warnf("key: %s, msg: expected IPv4 servers but found none for port %v", key, poolNode.Port)
+		}
+		if v6Family && v6ServerCount == 0 {
+			utils.AviLog.Warnf("key: %s, msg: expected IPv6 servers but found none for port %v", key, poolNode.Port)
+		}
+		utils.AviLog.Infof("key: %s, msg: servers for port: %v , are: %v", key, poolNode.Port, utils.Stringify(pool_meta))
+	}
+	return pool_meta
+}
+
+func populateServersFromEndpoints(poolNode *AviPoolNode, epObj *corev1.Endpoints, v4, v6 bool, key string) []AviPoolMetaServer {
 	var pool_meta []AviPoolMetaServer
 	for _, ss := range epObj.Subsets {
 		port_match := false
@@ -486,15 +568,13 @@ func PopulateServers(poolNode *AviPoolNode, ns string, serviceName string, ingre
 			poolNode.Port = ss.Ports[0].Port
 		}
 		if port_match {
-			var atype string
 			utils.AviLog.Infof("key: %s, msg: found port match for port %v", key, poolNode.Port)
 			for _, addr := range ss.Addresses {
+				var atype string
 				ip := addr.IP
-				if v4enabled && v4Family && utils.IsV4(addr.IP) {
-					v4ServerCount++
+				if v4 && utils.IsV4(addr.IP) {
 					atype = "V4"
-				} else if v6enabled && v6Family && utils.IsV6(addr.IP) {
-					v6ServerCount++
+				} else if v6 && utils.IsV6(addr.IP) {
 					atype = "V6"
 				} else {
 					continue
@@ -508,18 +588,103 @@ func PopulateServers(poolNode *AviPoolNode, ns string, serviceName string, ingre
 			}
 		}
 	}
-	if len(pool_meta) == 0 {
-		utils.AviLog.Warnf("key: %s, msg: no servers for port: %v", key, poolNode.Port)
-	} else {
-		if v4Family && v4ServerCount == 0 {
-			utils.AviLog.Warnf("key: %s, msg: expected IPv4 servers but found none for port %v", key, poolNode.Port)
+	return pool_meta
+}
+
+// populateServersFromEndpointSlices aggregates the servers across all the EndpointSlices of the service.
+// Ready endpoints are used as servers, and the serving endpoints which are terminating are used only
+// when none of the endpoints is ready, so that the existing connections can drain.
+func populateServersFromEndpointSlices(poolNode *AviPoolNode, ns, serviceName string, v4, v6 bool, key string) []AviPoolMetaServer {
+	selector := labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: serviceName})
+	epSlices, err := utils.GetInformers().EpSlicesInformer.Lister().EndpointSlices(ns).List(selector)
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: error while retrieving endpointslices: %s", key, err)
+		return nil
+	}
+	if len(epSlices) == 0 {
+		utils.AviLog.Warnf("key: %s, msg: no endpointslices found for service %s/%s", key, ns, serviceName)
+		return nil
+	}
+
+	// A service with a single port has the same port across all its EndpointSlices,
+	// which is then used as the server port.
+	slicePorts := sets.NewString()
+	for _, eps := range epSlices {
+		for _, epp := range eps.Ports {
+			if epp.Port != nil {
+				slicePorts.Insert(getEndpointPortName(epp) + "/" + strconv.Itoa(int(*epp.Port)))
+			}
 		}
-		if v6Family && v6ServerCount == 0 {
-			utils.AviLog.Warnf("key: %s, msg: expected IPv6 servers but found none for port %v", key, poolNode.Port)
+	}
+	singlePort := slicePorts.Len() == 1
+
+	var readyServers, terminatingServers []AviPoolMetaServer
+	addresses := sets.NewString()
+	for _, eps := range epSlices {
+		var atype string
+		if eps.AddressType == discoveryv1.AddressTypeIPv4 && v4 {
+			atype = "V4"
+		} else if eps.AddressType == discoveryv1.AddressTypeIPv6 && v6 {
+			atype = "V6"
+		} else {
+			continue
+		}
+		port_match := false
+		for _, epp := range eps.Ports {
+			if epp.Port == nil {
+				continue
+			}
+			if poolNode.PortName == getEndpointPortName(epp) || int32(poolNode.TargetPort.IntValue()) == *epp.Port || singlePort {
+				port_match = true
+				poolNode.Port = *epp.Port
+				break
+			}
+		}
+		if !port_match {
+			continue
+		}
+		utils.AviLog.Debugf("key: %s, msg: found port match for port %v in endpointslice %s", key, poolNode.Port, eps.Name)
+		for _, endpoint := range eps.Endpoints {
+			ready := endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready
+			serving := ready
+			if endpoint.Conditions.Serving != nil {
+				serving = *endpoint.Conditions.Serving
+			}
+			terminating := endpoint.Conditions.Terminating != nil && *endpoint.Conditions.Terminating
+			if !ready && !(serving && terminating) {
+				continue
+			}
+			for _, addr := range endpoint.Addresses {
+				// an endpoint can be present in more than one EndpointSlice while it moves between them
+				if addresses.Has(addr) {
+					continue
+				}
+				addresses.Insert(addr)
+				ip := addr
+				server := AviPoolMetaServer{Ip: avimodels.IPAddr{Type: &atype, Addr: &ip}}
+				if endpoint.NodeName != nil {
+					server.ServerNode = *endpoint.NodeName
+				}
+				if ready {
+					readyServers = append(readyServers, server)
+				} else {
+					terminatingServers = append(terminatingServers, server)
+				}
+			}
 		}
-		utils.AviLog.Infof("key: %s, msg: servers for port: %v , are: %v", key, poolNode.Port, utils.Stringify(pool_meta))
 	}
-	return pool_meta
+	if len(readyServers) == 0 && len(terminatingServers) > 0 {
+		utils.AviLog.Infof("key: %s, msg: no ready endpoints for service %s/%s, using the serving endpoints which are terminating", key, ns, serviceName)
+		return terminatingServers
+	}
+	return readyServers
+}
+
+func getEndpointPortName(epp discoveryv1.EndpointPort) string {
+	if epp.Name == nil {
+		return ""
+	}
+	return *epp.Name
 }
 
 func PopulateServersForMultiClusterIngress(poolNode *AviPoolNode, ns, cluster, serviceNamespace, serviceName string, key string) []AviPoolMetaServer {
@@ -759,10 +924,70 @@ func buildWithL4Rule(key string, vs *AviVsNode, l4Rule *akov1alpha2.L4Rule) {
 	}
 	vs.AviVsNodeCommonFields.ConvertToRef()
 	vs.AviVsNodeGeneratedFields.ConvertToRef()
+	vs.ServiceMetadata.CRDStatus = lib.CRDMetadata{
+		Type:   lib.L4Rule,
+		Value:  l4Rule.Namespace + "/" + l4Rule.Name,
+		Status: lib.CRDActive,
+	}
 
 	utils.AviLog.Debugf("key: %s, msg: Applied L4Rule %s configuration over VS %s", key, l4Rule.Name, vs.Name)
 }
 
+// buildWithLoadBalancerSourceRanges attaches a network security policy to the L4 VS,
+// which only allows the clients in the loadBalancerSourceRanges of the Service.
+func buildWithLoadBalancerSourceRanges(key string, svcObj *corev1.Service, vs *AviVsNode) {
+	if len(svcObj.Spec.LoadBalancerSourceRanges) == 0 {
+		return
+	}
+	if vs.NetworkSecurityPolicyRef != nil {
+		utils.AviLog.Warnf("key: %s, msg: loadBalancerSourceRanges of the Service are overridden by the network security policy of the L4Rule", key)
+		lib.AKOControlConfig().EventRecorder().Eventf(svcObj, corev1.EventTypeWarning, lib.UnsupportedConfiguration,
+			"loadBalancerSourceRanges are not applied, as the network security policy of the L4Rule takes precedence")
+		return
+	}
+	var clientIPs []string
+	for _, sourceRange := range svcObj.Spec.LoadBalancerSourceRanges {
+		sourceRange = strings.TrimSpace(sourceRange)
+		_, ipNet, err := net.ParseCIDR(sourceRange)
+		if err != nil {
+			utils.AviLog.Warnf("key: %s, msg: invalid loadBalancerSourceRange %s: %v", key, sourceRange, err)
+			lib.AKOControlConfig().EventRecorder().Eventf(svcObj, corev1.EventTypeWarning, lib.UnsupportedConfiguration,
+				"loadBalancerSourceRange %s is not a valid CIDR and is not applied", sourceRange)
+			continue
+		}
+		if !utils.HasElem(clientIPs, ipNet.String()) {
+			clientIPs = append(clientIPs, ipNet.String())
+		}
+	}
+	if len(clientIPs) == 0 {
+		return
+	}
+	nspNode := &AviNetworkSecurityPolicyNode{
+		Name:             vs.Name,
+		Tenant:           vs.Tenant,
+		AllowedClientIPs: clientIPs,
+		AviMarkers:       lib.PopulateL4VSNodeMarkers(svcObj.Namespace, svcObj.Name),
+	}
+	vs.NSPRefs = append(vs.NSPRefs, nspNode)
+	vs.NetworkSecurityPolicyRef = proto.String("/api/networksecuritypolicy?name=" + nspNode.Name)
+}
+
+// getSessionAffinityPersistenceProfile returns the client IP persistence profile for a
+// Service with ClientIP sessionAffinity.
+func getSessionAffinityPersistenceProfile(key string, svcObj *corev1.Service) *string {
+	if svcObj.Spec.SessionAffinity != corev1.ServiceAffinityClientIP {
+		return nil
+	}
+	affinityConfig := svcObj.Spec.SessionAffinityConfig
+	if affinityConfig != nil && affinityConfig.ClientIP != nil && affinityConfig.ClientIP.TimeoutSeconds != nil &&
+		*affinityConfig.ClientIP.TimeoutSeconds != corev1.DefaultClientIPServiceAffinitySeconds {
+		utils.AviLog.Warnf("key: %s, msg: sessionAffinity timeout %d is not applied, the timeout of %s is used", key, *affinityConfig.ClientIP.TimeoutSeconds, utils.CLIENT_IP_PERSISTENCE_PROFILE)
+		lib.AKOControlConfig().EventRecorder().Eventf(svcObj, corev1.EventTypeWarning, lib.UnsupportedConfiguration,
+			"sessionAffinityConfig timeout %d is not applied, the timeout of the %s persistence profile is used", *affinityConfig.ClientIP.TimeoutSeconds, utils.CLIENT_IP_PERSISTENCE_PROFILE)
+	}
+	return proto.String("/api/applicationpersistenceprofile?name=" + utils.CLIENT_IP_PERSISTENCE_PROFILE)
+}
+
 func buildPoolWithL4Rule(key string, pool *AviPoolNode, l4Rule *akov1alpha2.L4Rule) {
 
 	if l4Rule == nil {
@@ -290,7 +290,7 @@ func (o *AviObjectGraph) BuildL7VSGraphHostNameShard(vsName, hostname string, ro
 	// Reset the PG Node members and rebuild them
 	pgNode.Members = nil
 	for _, poolNode := range vsNode[0].PoolRefs {
-		ratio := poolNode.ServiceMetadata.PoolRatio
+		ratio := poolNode.GetPoolGroupRatio()
 		pool_ref := fmt.Sprintf("/api/pool?name=%s", poolNode.Name)
 		pgNode.Members = append(pgNode.Members, &avimodels.PoolGroupMember{PoolRef: &pool_ref, PriorityLabel: &poolNode.PriorityLabel, Ratio: &ratio})
 
@@ -397,7 +397,7 @@ func (o *AviObjectGraph) DeletePoolForHostname(vsName, hostname string, routeIgr
 		if pgNode != nil {
 			pgNode.Members = nil
 			for _, poolNode := range vsNode[0].PoolRefs {
-				ratio := poolNode.ServiceMetadata.PoolRatio
+				ratio := poolNode.GetPoolGroupRatio()
 				pool_ref := fmt.Sprintf("/api/pool?name=%s", poolNode.Name)
 				pgNode.Members = append(pgNode.Members, &avimodels.PoolGroupMember{PoolRef: &pool_ref, PriorityLabel: &poolNode.PriorityLabel, Ratio: &ratio})
 			}
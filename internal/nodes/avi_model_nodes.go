@@ -30,6 +30,7 @@ import (
 	avimodels "github.com/vmware/alb-sdk/go/models"
 	"google.golang.org/protobuf/proto"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
@@ -180,6 +181,15 @@ func (v *AviVsNode) CalculateForGraphChecksum() uint32 {
 	for _, l4pol := range v.L4PolicyRefs {
 		checksumStringSlice = append(checksumStringSlice, fmt.Sprint(l4pol.GetCheckSum()))
 	}
+	for _, nsp := range v.NSPRefs {
+		checksumStringSlice = append(checksumStringSlice, fmt.Sprint(nsp.GetCheckSum()))
+	}
+	for _, appProfile := range v.AppProfileRefs {
+		checksumStringSlice = append(checksumStringSlice, fmt.Sprint(appProfile.GetCheckSum()))
+		if appProfile.PkiProfile != nil {
+			checksumStringSlice = append(checksumStringSlice, fmt.Sprint(appProfile.PkiProfile.GetCheckSum()))
+		}
+	}
 
 	return utils.Hash(strings.Join(checksumStringSlice, ":"))
 }
@@ -210,6 +220,18 @@ func (v *AviEvhVsNode) CalculateForGraphChecksum() uint32 {
 	for _, vsvip := range v.VSVIPRefs {
 		checksumStringSlice = append(checksumStringSlice, fmt.Sprint(vsvip.GetCheckSum()))
 	}
+	for _, l4pol := range v.L4PolicyRefs {
+		checksumStringSlice = append(checksumStringSlice, fmt.Sprint(l4pol.GetCheckSum()))
+	}
+	for _, appProfile := range v.AppProfileRefs {
+		checksumStringSlice = append(checksumStringSlice, fmt.Sprint(appProfile.GetCheckSum()))
+		if appProfile.PkiProfile != nil {
+			checksumStringSlice = append(checksumStringSlice, fmt.Sprint(appProfile.PkiProfile.GetCheckSum()))
+		}
+	}
+	for _, cloneProfile := range v.CloneProfileRefs {
+		checksumStringSlice = append(checksumStringSlice, fmt.Sprint(cloneProfile.GetCheckSum()))
+	}
 
 	return utils.Hash(strings.Join(checksumStringSlice, ":"))
 }
@@ -235,6 +257,7 @@ func (o *AviObjectGraph) RemovePoolNodeRefs(poolName string) {
 					break
 				}
 			}
+			node.(*AviVsNode).PoolRefs = removeHTTPRuleBackendPools(node.(*AviVsNode).PoolRefs, poolName)
 			utils.AviLog.Debugf("After removing the pool nodes are: %s", utils.Stringify(node.(*AviVsNode).PoolRefs))
 		}
 	}
@@ -263,6 +286,7 @@ func (o *AviObjectGraph) RemoveHTTPRefsFromSni(httpPol, hppMap string, sniNode *
 					break
 				}
 			}
+			sniNode.HttpPolicyRefs[i].HppMap = removeHTTPRuleBackendHppMaps(sniNode.HttpPolicyRefs[i].HppMap, hppMap)
 			if len(pol.HppMap) == 0 {
 				utils.AviLog.Debugf("Removing http pol ref: %s", httpPol)
 				sniNode.HttpPolicyRefs = append(sniNode.HttpPolicyRefs[:i], sniNode.HttpPolicyRefs[i+1:]...)
@@ -281,6 +305,7 @@ func (o *AviObjectGraph) RemovePoolNodeRefsFromSni(poolName string, sniNode *Avi
 			break
 		}
 	}
+	sniNode.PoolRefs = removeHTTPRuleBackendPools(sniNode.PoolRefs, poolName)
 	utils.AviLog.Debugf("After removing the pool ref nodes are: %s", utils.Stringify(sniNode.PoolRefs))
 
 }
@@ -398,6 +423,8 @@ type AviVsNode struct {
 	HttpPolicyRefs        []*AviHttpPolicySetNode
 	VSVIPRefs             []*AviVSVIPNode
 	L4PolicyRefs          []*AviL4PolicyNode
+	NSPRefs               []*AviNetworkSecurityPolicyNode
+	AppProfileRefs        []*AviApplicationProfileNode
 	VHParentName          string
 	VHDomainNames         []string
 	TLSType               string
@@ -429,6 +456,8 @@ type AviVsNodeCommonFields struct {
 	AnalyticsPolicy          *avimodels.AnalyticsPolicy
 	AnalyticsProfileRef      *string
 	ApplicationProfileRef    *string
+	ConnectionsRateLimit     *avimodels.RateProfile
+	RequestsRateLimit        *avimodels.RateProfile
 	SslProfileRef            *string
 	VsDatascriptRefs         []string
 	WafPolicyRef             *string
@@ -593,6 +622,30 @@ func (v *AviVsNode) SetAnalyticsPolicy(policy *avimodels.AnalyticsPolicy) {
 	v.AnalyticsPolicy = policy
 }
 
+func (v *AviVsNode) GetAppProfileRefs() []*AviApplicationProfileNode {
+	return v.AppProfileRefs
+}
+
+func (v *AviVsNode) SetAppProfileRefs(appProfiles []*AviApplicationProfileNode) {
+	v.AppProfileRefs = appProfiles
+}
+
+func (v *AviVsNode) GetRequestsRateLimit() *avimodels.RateProfile {
+	return v.RequestsRateLimit
+}
+
+func (v *AviVsNode) SetRequestsRateLimit(rateLimit *avimodels.RateProfile) {
+	v.RequestsRateLimit = rateLimit
+}
+
+func (v *AviVsNode) GetConnectionsRateLimit() *avimodels.RateProfile {
+	return v.ConnectionsRateLimit
+}
+
+func (v *AviVsNode) SetConnectionsRateLimit(rateLimit *avimodels.RateProfile) {
+	v.ConnectionsRateLimit = rateLimit
+}
+
 func (v *AviVsNode) GetVSVIPLoadBalancerIP() string {
 	if len(v.VSVIPRefs) > 0 {
 		return v.VSVIPRefs[0].IPAddress
@@ -641,6 +694,32 @@ func (o *AviObjectGraph) GetAviVS() []*AviVsNode {
 	return aviVs
 }
 
+// GetServiceMetadataOwners returns the Services and Ingresses from which an Avi object with the service metadata
+// is built. Shared objects are not owned by a single object, so nothing is returned for them.
+func GetServiceMetadataOwners(metadata lib.ServiceMetadataObj) []runtime.Object {
+	var owners []runtime.Object
+	informers := utils.GetInformers()
+	if informers.ServiceInformer != nil {
+		for _, namespaceSvcName := range metadata.NamespaceServiceName {
+			nsName := strings.Split(namespaceSvcName, "/")
+			if len(nsName) != 2 {
+				continue
+			}
+			svc, err := informers.ServiceInformer.Lister().Services(nsName[0]).Get(nsName[1])
+			if err == nil {
+				owners = append(owners, svc)
+			}
+		}
+	}
+	if informers.IngressInformer != nil && metadata.IngressName != "" && metadata.Namespace != "" {
+		ingress, err := informers.IngressInformer.Lister().Ingresses(metadata.Namespace).Get(metadata.IngressName)
+		if err == nil {
+			owners = append(owners, ingress)
+		}
+	}
+	return owners
+}
+
 func (v *AviVsNode) GetCheckSum() uint32 {
 	// Calculate checksum and return
 	v.CalculateCheckSum()
@@ -1029,6 +1108,14 @@ func (v *AviVsNode) CalculateCheckSum() {
 		checksum += lib.GetAnalyticsPolicyChecksum(v.AnalyticsPolicy)
 	}
 
+	if v.RequestsRateLimit != nil {
+		checksum += utils.Hash(utils.Stringify(v.RequestsRateLimit))
+	}
+
+	if v.ConnectionsRateLimit != nil {
+		checksum += utils.Hash(utils.Stringify(v.ConnectionsRateLimit))
+	}
+
 	checksum += v.AviVsNodeGeneratedFields.CalculateCheckSumOfGeneratedCode()
 
 	v.CloudConfigCksum = checksum
@@ -1099,6 +1186,121 @@ func (v *AviL4PolicyNode) CopyNode() AviModelNode {
 	return &newNode
 }
 
+// AviNetworkSecurityPolicyNode is the network security policy that AKO manages
+// for an L4 VS, which only allows the client IPs in AllowedClientIPs.
+type AviNetworkSecurityPolicyNode struct {
+	Name             string
+	Tenant           string
+	CloudConfigCksum uint32
+	AllowedClientIPs []string
+	AviMarkers       utils.AviObjectMarkers
+}
+
+func (v *AviNetworkSecurityPolicyNode) GetCheckSum() uint32 {
+	// Calculate checksum and return
+	v.CalculateCheckSum()
+	return v.CloudConfigCksum
+}
+
+func (v *AviNetworkSecurityPolicyNode) CalculateCheckSum() {
+	v.CloudConfigCksum = lib.NetworkSecurityPolicyChecksum(v.AllowedClientIPs, v.AviMarkers, nil, false)
+}
+
+func (v *AviNetworkSecurityPolicyNode) GetNodeType() string {
+	return "AviNetworkSecurityPolicyNode"
+}
+
+func (v *AviNetworkSecurityPolicyNode) CopyNode() AviModelNode {
+	newNode := AviNetworkSecurityPolicyNode{}
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		utils.AviLog.Warnf("Unable to marshal AviNetworkSecurityPolicyNode: %s", err)
+	}
+	err = json.Unmarshal(bytes, &newNode)
+	if err != nil {
+		utils.AviLog.Warnf("Unable to unmarshal AviNetworkSecurityPolicyNode: %s", err)
+	}
+	return &newNode
+}
+
+// AviApplicationProfileNode is the application profile that AKO manages for a VS,
+// which authenticates the clients with their certificates. PkiProfile is set when
+// the CA bundle is read from a Secret, and is created along with the application profile.
+type AviApplicationProfileNode struct {
+	Name                  string
+	Tenant                string
+	CloudConfigCksum      uint32
+	ClientCertificateMode string
+	PkiProfileName        string
+	PkiProfile            *AviPkiProfileNode
+	ClientCertHeaders     []*avimodels.SSLClientRequestHeader
+	AviMarkers            utils.AviObjectMarkers
+}
+
+func (v *AviApplicationProfileNode) GetCheckSum() uint32 {
+	// Calculate checksum and return
+	v.CalculateCheckSum()
+	return v.CloudConfigCksum
+}
+
+func (v *AviApplicationProfileNode) CalculateCheckSum() {
+	v.CloudConfigCksum = lib.ClientCertAppProfileChecksum(v.ClientCertificateMode, v.PkiProfileName, v.ClientCertHeaders, v.AviMarkers, nil, false)
+}
+
+func (v *AviApplicationProfileNode) GetNodeType() string {
+	return "AviApplicationProfileNode"
+}
+
+func (v *AviApplicationProfileNode) CopyNode() AviModelNode {
+	newNode := AviApplicationProfileNode{}
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		utils.AviLog.Warnf("Unable to marshal AviApplicationProfileNode: %s", err)
+	}
+	err = json.Unmarshal(bytes, &newNode)
+	if err != nil {
+		utils.AviLog.Warnf("Unable to unmarshal AviApplicationProfileNode: %s", err)
+	}
+	return &newNode
+}
+
+// AviTrafficCloneProfileNode is the traffic clone profile that AKO manages for a VS,
+// which clones the requests of the VS to the servers of a mirror backend.
+type AviTrafficCloneProfileNode struct {
+	Name             string
+	Tenant           string
+	CloudConfigCksum uint32
+	CloneServers     []string
+	AviMarkers       utils.AviObjectMarkers
+}
+
+func (v *AviTrafficCloneProfileNode) GetCheckSum() uint32 {
+	// Calculate checksum and return
+	v.CalculateCheckSum()
+	return v.CloudConfigCksum
+}
+
+func (v *AviTrafficCloneProfileNode) CalculateCheckSum() {
+	v.CloudConfigCksum = lib.TrafficCloneProfileChecksum(v.CloneServers, v.AviMarkers, nil, false)
+}
+
+func (v *AviTrafficCloneProfileNode) GetNodeType() string {
+	return "AviTrafficCloneProfileNode"
+}
+
+func (v *AviTrafficCloneProfileNode) CopyNode() AviModelNode {
+	newNode := AviTrafficCloneProfileNode{}
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		utils.AviLog.Warnf("Unable to marshal AviTrafficCloneProfileNode: %s", err)
+	}
+	err = json.Unmarshal(bytes, &newNode)
+	if err != nil {
+		utils.AviLog.Warnf("Unable to unmarshal AviTrafficCloneProfileNode: %s", err)
+	}
+	return &newNode
+}
+
 type AviHttpPolicySetNode struct {
 	Name               string
 	Tenant             string
@@ -1132,6 +1334,9 @@ func (v *AviHttpPolicySetNode) CalculateCheckSum() {
 	for _, sec_rule := range v.SecurityRules {
 		checksum = checksum + utils.Hash(sec_rule.Action) + utils.Hash(sec_rule.MatchCriteria)
 		checksum = checksum + uint32(sec_rule.Port)
+		if sec_rule.RateProfile != nil {
+			checksum = checksum + utils.Hash(utils.Stringify(sec_rule.RateProfile))
+		}
 	}
 	if v.HeaderReWrite != nil {
 		checksum = checksum + utils.Hash(utils.Stringify(v.HeaderReWrite))
@@ -1179,6 +1384,10 @@ type AviHostPathPortPoolPG struct {
 	MatchCriteria string
 	Protocol      string
 	IngName       string
+	// Hdrs and Cookie are matched along with the path, to switch the requests to the pools of the HTTPRule backends.
+	Hdrs              []*avimodels.HdrMatch  `json:",omitempty"`
+	Cookie            *avimodels.CookieMatch `json:",omitempty"`
+	HTTPRuleBackendOf string                 `json:",omitempty"`
 }
 
 func (v *AviHostPathPortPoolPG) GetCheckSum() uint32 {
@@ -1208,6 +1417,7 @@ type AviHTTPSecurity struct {
 	MatchCriteria string
 	Enable        bool
 	Port          int64
+	RateProfile   *avimodels.HttpsecurityActionRateProfile
 }
 type AviHostHeaderRewrite struct {
 	Name       string
@@ -1277,6 +1487,7 @@ type AviVSVIPNode struct {
 	FQDNs                   []string
 	VrfContext              string
 	IPAddress               string
+	V6IPAddress             string
 	VipNetworks             []akov1beta1.AviInfraSettingVipNetwork
 	EnablePublicIP          *bool
 	BGPPeerLabels           []string
@@ -1302,6 +1513,10 @@ func (v *AviVSVIPNode) CalculateCheckSum() {
 		checksum += utils.Hash(v.IPAddress)
 	}
 
+	if v.V6IPAddress != "" {
+		checksum += utils.Hash(v.V6IPAddress)
+	}
+
 	if len(v.VipNetworks) > 0 {
 		var vipNetworkStringList []string
 		for _, vipNetwork := range v.VipNetworks {
@@ -1524,6 +1739,12 @@ type AviPoolNode struct {
 	T1Lr                     string // Only applicable to NSX-T cloud, if this value is set, we automatically should unset the VRF context value.
 	AviMarkers               utils.AviObjectMarkers
 	AttachedWithSharedVS     bool
+	EnableHttp2              bool
+	ServerName               string
+	// HTTPRuleBackendOf is the pool of the ingress/route path, for the pools of the backends in an HTTPRule.
+	HTTPRuleBackendOf string
+	// HTTPRuleBackendsWeight is the total weight of the HTTPRule backends, which share the traffic of the pool.
+	HTTPRuleBackendsWeight uint32
 
 	AviPoolCommonFields
 
@@ -1597,6 +1818,14 @@ func (v *AviPoolNode) CalculateCheckSum() {
 		checksumStringSlice = append(checksumStringSlice, *v.SslKeyAndCertificateRef)
 	}
 
+	// added only when set to retain the checksum of the existing pools
+	if v.EnableHttp2 {
+		checksumStringSlice = append(checksumStringSlice, utils.Stringify(v.EnableHttp2))
+	}
+	if v.ServerName != "" {
+		checksumStringSlice = append(checksumStringSlice, v.ServerName)
+	}
+
 	if len(v.ServiceMetadata.NamespaceServiceName) > 0 {
 		sort.Strings(v.ServiceMetadata.NamespaceServiceName)
 		checksumStringSlice = append(checksumStringSlice, utils.Stringify(v.ServiceMetadata.NamespaceServiceName))
@@ -1638,6 +1867,19 @@ func (v *AviPoolNode) GetNodeType() string {
 	return "PoolNode"
 }
 
+// GetPoolGroupRatio returns the ratio of the pool in its pool group, leaving
+// the share of the traffic sent to the HTTPRule backends of the pool.
+func (v *AviPoolNode) GetPoolGroupRatio() uint32 {
+	if v.HTTPRuleBackendsWeight == 0 {
+		return v.ServiceMetadata.PoolRatio
+	}
+	ratio := v.ServiceMetadata.PoolRatio * (100 - v.HTTPRuleBackendsWeight) / 100
+	if ratio == 0 {
+		ratio = 1
+	}
+	return ratio
+}
+
 func (v *AviPoolNode) CopyNode() AviModelNode {
 	newNode := AviPoolNode{}
 	bytes, err := json.Marshal(v)
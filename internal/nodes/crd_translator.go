@@ -23,9 +23,11 @@ import (
 	"github.com/vmware/alb-sdk/go/models"
 	"google.golang.org/protobuf/proto"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/status"
 	akov1beta1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1beta1"
 
 	akov1alpha2 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha2"
@@ -70,6 +72,9 @@ func BuildL7HostRule(host, key string, vsNode AviVsEvhSniModel) {
 	vsHTTPPolicySets := []string{}
 	vsDatascripts := []string{}
 	var analyticsPolicy *models.AnalyticsPolicy
+	var requestsRateLimit, connectionsRateLimit *models.RateProfile
+	var rateLimitRule *AviHTTPSecurity
+	var appProfileRefs []*AviApplicationProfileNode
 
 	// Get the existing VH domain names and then manipulate it based on the aliases in Hostrule CRD.
 	VHDomainNames := vsNode.GetVHDomainNames()
@@ -166,6 +171,40 @@ func BuildL7HostRule(host, key string, vsNode AviVsEvhSniModel) {
 					"can not associate network security policy with host which is attached to child virtual service. Configuration is ignored")
 			}
 		}
+		if rateLimit := hostrule.Spec.VirtualHost.RateLimit; rateLimit != nil {
+			if rateLimit.Requests != nil {
+				requestsRateLimit, rateLimitRule = buildRequestsRateLimit(rateLimit.Requests)
+			}
+			if rateLimit.Connections != nil {
+				if vsNode.IsSharedVS() || vsNode.IsDedicatedVS() {
+					connectionsRateLimit = &models.RateProfile{
+						Action: &models.RateLimiterAction{Type: proto.String(lib.RL_ACTION_DROP_CONN)},
+						RateLimiter: &models.RateLimiter{
+							Count:   proto.Uint32(rateLimit.Connections.Count),
+							Period:  proto.Uint32(rateLimit.Connections.Period),
+							BurstSz: rateLimit.Connections.Burst,
+						},
+					}
+				} else {
+					utils.AviLog.Warnf("key: %s, can not associate connection rate limit with host which is attached to child virtual service. Configuration is ignored", key)
+					lib.AKOControlConfig().EventRecorder().Eventf(hostrule, corev1.EventTypeWarning, lib.InvalidConfiguration,
+						"can not associate connection rate limit with host which is attached to child virtual service. Configuration is ignored")
+				}
+			}
+		}
+		if clientCertificate := hostrule.Spec.VirtualHost.TLS.ClientCertificate; clientCertificate != nil &&
+			clientCertificate.Mode != akov1beta1.HostRuleClientCertificateModeNone {
+			if !vsNode.IsSharedVS() {
+				if appProfileNode := buildClientCertAppProfile(vsNode, clientCertificate, hostrule, host, key); appProfileNode != nil {
+					vsAppProfile = proto.String(fmt.Sprintf("/api/applicationprofile?name=%s", appProfileNode.Name))
+					appProfileRefs = append(appProfileRefs, appProfileNode)
+				}
+			} else {
+				utils.AviLog.Warnf("key: %s, can not associate client certificate with host which is attached to shared virtual service. Configuration is ignored", key)
+				lib.AKOControlConfig().EventRecorder().Eventf(hostrule, corev1.EventTypeWarning, lib.InvalidConfiguration,
+					"can not associate client certificate with host which is attached to shared virtual service. Configuration is ignored")
+			}
+		}
 		vsEnabled = hostrule.Spec.VirtualHost.EnableVirtualHost
 		crdStatus = lib.CRDMetadata{
 			Type:   "HostRule",
@@ -207,12 +246,16 @@ func BuildL7HostRule(host, key string, vsNode AviVsEvhSniModel) {
 	vsNode.SetHttpPolicySetRefs(vsHTTPPolicySets)
 	vsNode.SetICAPProfileRefs(vsICAPProfile)
 	vsNode.SetAppProfileRef(vsAppProfile)
+	vsNode.SetAppProfileRefs(appProfileRefs)
 	vsNode.SetAnalyticsProfileRef(vsAnalyticsProfile)
 	vsNode.SetErrorPageProfileRef(vsErrorPageProfile)
 	vsNode.SetSSLProfileRef(vsSslProfile)
 	vsNode.SetVsDatascriptRefs(vsDatascripts)
 	vsNode.SetEnabled(vsEnabled)
 	vsNode.SetAnalyticsPolicy(analyticsPolicy)
+	vsNode.SetRequestsRateLimit(requestsRateLimit)
+	vsNode.SetConnectionsRateLimit(connectionsRateLimit)
+	setRateLimitHTTPPolicy(vsNode, rateLimitRule, host, hostrule)
 	if len(portProtocols) != 0 {
 		vsNode.SetPortProtocols(portProtocols)
 	}
@@ -225,10 +268,153 @@ func BuildL7HostRule(host, key string, vsNode AviVsEvhSniModel) {
 	vsNode.SetServiceMetadata(serviceMetadataObj)
 }
 
+// buildClientCertAppProfile returns the application profile of the VS, which authenticates the clients
+// with their certificates. The PKI profile is built from the ca.crt of the Secret, if the CA is a Secret.
+func buildClientCertAppProfile(vsNode AviVsEvhSniModel, clientCertificate *akov1beta1.HostRuleClientCertificate, hostrule *akov1beta1.HostRule, host, key string) *AviApplicationProfileNode {
+	aviMarkers := lib.PopulateVSNodeMarkers(hostrule.Namespace, host, "")
+	appProfileNode := &AviApplicationProfileNode{
+		Name:                  lib.GetClientCertAppProfileName(vsNode.GetName()),
+		Tenant:                lib.GetTenant(),
+		ClientCertificateMode: lib.SSL_CLIENT_CERTIFICATE_REQUIRE,
+		AviMarkers:            aviMarkers,
+	}
+	if clientCertificate.Mode == akov1beta1.HostRuleClientCertificateModeRequest {
+		appProfileNode.ClientCertificateMode = lib.SSL_CLIENT_CERTIFICATE_REQUEST
+	}
+
+	if clientCertificate.CA.Type == akov1beta1.HostRuleSecretTypeSecretReference {
+		secretObj, err := utils.GetInformers().SecretInformer.Lister().Secrets(hostrule.Namespace).Get(clientCertificate.CA.Name)
+		if err != nil || len(secretObj.Data[utils.K8S_TLS_SECRET_CA_CERT]) == 0 {
+			utils.AviLog.Warnf("key: %s, msg: CA bundle not found in secret %s/%s for client certificate of host %s, err: %v",
+				key, hostrule.Namespace, clientCertificate.CA.Name, host, err)
+			lib.AKOControlConfig().EventRecorder().Eventf(hostrule, corev1.EventTypeWarning, lib.InvalidConfiguration,
+				"CA bundle not found in secret %s for client certificate. Configuration is ignored", clientCertificate.CA.Name)
+			return nil
+		}
+		appProfileNode.PkiProfile = &AviPkiProfileNode{
+			Name:       lib.GetClientCertPKIProfileName(vsNode.GetName()),
+			Tenant:     lib.GetTenant(),
+			CACert:     string(secretObj.Data[utils.K8S_TLS_SECRET_CA_CERT]),
+			AviMarkers: aviMarkers,
+		}
+		appProfileNode.PkiProfileName = appProfileNode.PkiProfile.Name
+	} else {
+		appProfileNode.PkiProfileName = clientCertificate.CA.Name
+	}
+
+	for _, header := range clientCertificate.Headers {
+		appProfileNode.ClientCertHeaders = append(appProfileNode.ClientCertHeaders, &models.SSLClientRequestHeader{
+			RequestHeader:      proto.String(header.Name),
+			RequestHeaderValue: proto.String(lib.GetClientCertificateHeaderVariable(header.Value)),
+		})
+	}
+	appProfileNode.CalculateCheckSum()
+	return appProfileNode
+}
+
+// buildRequestsRateLimit returns the rate profile of the VS for the requests counted per header,
+// and the HTTP security rule for the requests counted per client IP, which the VS rate profile does not support.
+func buildRequestsRateLimit(requests *akov1beta1.HostRuleRequestRateLimit) (*models.RateProfile, *AviHTTPSecurity) {
+	rateLimiter := &models.RateLimiter{
+		Count:   proto.Uint32(requests.Count),
+		Period:  proto.Uint32(requests.Period),
+		BurstSz: requests.Burst,
+	}
+	action := &models.RateLimiterAction{}
+	switch requests.Action.Type {
+	case akov1beta1.HostRuleRateLimitActionTooManyRequests:
+		action.Type = proto.String(lib.RL_ACTION_LOCAL_RSP)
+		action.StatusCode = proto.String(lib.STATUS_TOO_MANY_REQUESTS)
+	case akov1beta1.HostRuleRateLimitActionRedirect:
+		action.Type = proto.String(lib.RL_ACTION_REDIRECT)
+		action.Redirect = buildRateLimitRedirect(requests.Action.Redirect)
+	default:
+		action.Type = proto.String(lib.RL_ACTION_DROP_CONN)
+	}
+
+	if requests.Key == akov1beta1.HostRuleRateLimitKeyHeader {
+		return &models.RateProfile{
+			Action:      action,
+			HTTPHeader:  proto.String(requests.Header),
+			RateLimiter: rateLimiter,
+		}, nil
+	}
+	return nil, &AviHTTPSecurity{
+		Action: lib.RATE_LIMIT,
+		Enable: true,
+		RateProfile: &models.HttpsecurityActionRateProfile{
+			Action:      action,
+			PerClientIP: proto.Bool(true),
+			RateLimiter: rateLimiter,
+		},
+	}
+}
+
+func buildRateLimitRedirect(redirect *akov1beta1.HostRuleRateLimitRedirect) *models.HTTPRedirectAction {
+	redirectAction := &models.HTTPRedirectAction{
+		Protocol:   proto.String("HTTPS"),
+		StatusCode: proto.String(lib.STATUS_REDIRECT),
+		KeepQuery:  proto.Bool(true),
+	}
+	if redirect == nil {
+		return redirectAction
+	}
+	if redirect.Protocol != "" {
+		redirectAction.Protocol = proto.String(strings.ToUpper(redirect.Protocol))
+	}
+	if redirect.StatusCode != 0 {
+		redirectAction.StatusCode = proto.String(fmt.Sprintf("HTTP_REDIRECT_STATUS_CODE_%d", redirect.StatusCode))
+	}
+	redirectAction.Port = redirect.Port
+	uriParam := func(value string) *models.URIParam {
+		return &models.URIParam{
+			Type: proto.String("URI_PARAM_TYPE_TOKENIZED"),
+			Tokens: []*models.URIParamToken{{
+				Type:     proto.String("URI_TOKEN_TYPE_STRING"),
+				StrValue: proto.String(value),
+			}},
+		}
+	}
+	if redirect.Host != "" {
+		redirectAction.Host = uriParam(redirect.Host)
+	}
+	if redirect.Path != "" {
+		redirectAction.Path = uriParam(strings.TrimPrefix(redirect.Path, "/"))
+	}
+	return redirectAction
+}
+
+// setRateLimitHTTPPolicy replaces the rate limit HTTP policy set of the VS, which holds the
+// rate limit rule of the HostRule. The policy set is removed if there is no rule.
+func setRateLimitHTTPPolicy(vsNode AviVsEvhSniModel, rateLimitRule *AviHTTPSecurity, host string, hostrule *akov1beta1.HostRule) {
+	policyName := lib.GetRateLimitPolicyName(vsNode.GetName())
+	httpPolicyRefs := make([]*AviHttpPolicySetNode, 0, len(vsNode.GetHttpPolicyRefs()))
+	for _, policy := range vsNode.GetHttpPolicyRefs() {
+		if policy.Name != policyName {
+			httpPolicyRefs = append(httpPolicyRefs, policy)
+		}
+	}
+	if rateLimitRule != nil {
+		rateLimitPolicy := &AviHttpPolicySetNode{
+			Name:               policyName,
+			Tenant:             lib.GetTenant(),
+			SecurityRules:      []AviHTTPSecurity{*rateLimitRule},
+			AttachedToSharedVS: vsNode.IsSharedVS(),
+			AviMarkers:         lib.PopulateVSNodeMarkers(hostrule.Namespace, host, ""),
+		}
+		rateLimitPolicy.CalculateCheckSum()
+		httpPolicyRefs = append(httpPolicyRefs, rateLimitPolicy)
+	}
+	vsNode.SetHttpPolicyRefs(httpPolicyRefs)
+}
+
 // BuildPoolHTTPRule notes
 // when we get an ingress update and we are building the corresponding pools of that ingress
 // we need to get all httprules which match ingress's host/path
 func BuildPoolHTTPRule(host, poolPath, ingName, namespace, infraSettingName, key string, vsNode AviVsEvhSniModel, isSNI, isDedicated bool) {
+	// the pools of the httprule backends are built again from the httprules applied to the ingress/route paths
+	removeHTTPRuleBackends(vsNode, host, ingName, namespace)
+
 	found, pathRules := objects.SharedCRDLister().GetFqdnHTTPRulesMapping(host)
 	if !found {
 		utils.AviLog.Debugf("key: %s, msg: HTTPRules for fqdn %s not found", key, host)
@@ -259,6 +445,10 @@ func BuildPoolHTTPRule(host, poolPath, ingName, namespace, infraSettingName, key
 		}
 	}
 
+	// the backends are added for the most specific httprule path matching a pool
+	poolHTTPRulePaths := make(map[string]akov1beta1.HTTPRulePaths)
+	poolHTTPRules := make(map[string]string)
+
 	// iterate through httpRule which we get from GetFqdnHTTPRulesMapping
 	// must contain fqdn.com: {path1: rr1, path2: rr1, path3: rr2}
 	for path, rule := range pathRules {
@@ -272,6 +462,9 @@ func BuildPoolHTTPRule(host, poolPath, ingName, namespace, infraSettingName, key
 		}
 
 		for _, pool := range vsNode.GetPoolRefs() {
+			if pool.HTTPRuleBackendOf != "" {
+				continue
+			}
 			isPathSniEnabled := pool.SniEnabled
 			pathSslProfile := pool.SslProfileRef
 			pathPkiProfile := pool.PkiProfileRef
@@ -377,10 +570,325 @@ func BuildPoolHTTPRule(host, poolPath, ingName, namespace, infraSettingName, key
 					Status: lib.CRDActive,
 				}
 				utils.AviLog.Infof("key: %s, Attached httprule %s on pool %s", key, rule, pool.Name)
+
+				if current, ok := poolHTTPRulePaths[pool.Name]; !ok || len(httpRulePath.Target) >= len(current.Target) {
+					poolHTTPRulePaths[pool.Name] = httpRulePath
+					poolHTTPRules[pool.Name] = rule
+				}
+			}
+		}
+	}
+
+	var backendPools []*AviPoolNode
+	ignoredBackends := make(map[string][]string)
+	for _, pool := range vsNode.GetPoolRefs() {
+		httpRulePath, ok := poolHTTPRulePaths[pool.Name]
+		if !ok {
+			continue
+		}
+		// the rules without ignored backends are also updated, to clear the condition set by an earlier build
+		rule := poolHTTPRules[pool.Name]
+		if _, ok := ignoredBackends[rule]; !ok {
+			ignoredBackends[rule] = nil
+		}
+		if len(httpRulePath.Backends) > 0 {
+			pools, ignored := buildHTTPRuleBackends(vsNode, pool, httpRulePath, host, ingName, namespace, infraSettingName, key, isSNI, isDedicated, backendPools)
+			backendPools = append(backendPools, pools...)
+			ignoredBackends[rule] = append(ignoredBackends[rule], ignored...)
+		}
+	}
+	vsNode.SetPoolRefs(append(vsNode.GetPoolRefs(), backendPools...))
+
+	for rule, ignored := range ignoredBackends {
+		updateHTTPRuleIgnoredBackends(rule, host, ignored, key)
+	}
+}
+
+// updateHTTPRuleIgnoredBackends reports the backends with match of the httprule that are ignored for the host,
+// with a warning event and the BackendsApplied condition in the status of the httprule.
+func updateHTTPRuleIgnoredBackends(rule, host string, ignored []string, key string) {
+	nsName := strings.Split(rule, "/")
+	httprule, err := lib.AKOControlConfig().CRDInformers().HTTPRuleInformer.Lister().HTTPRules(nsName[0]).Get(nsName[1])
+	if err != nil {
+		return
+	}
+	var message string
+	if len(ignored) > 0 {
+		message = fmt.Sprintf("Backends with match are ignored, since no HTTP policy switches the requests of host %s to the pools of its paths: %s",
+			host, strings.Join(ignored, ", "))
+	}
+	if status.UpdateHTTPRuleBackendsCondition(key, httprule, message) && message != "" {
+		lib.AKOControlConfig().EventRecorder().Eventf(httprule, corev1.EventTypeWarning, lib.InvalidConfiguration, message)
+	}
+}
+
+// buildHTTPRuleBackends builds the pools of the backends in the httprule path, to which the traffic
+// of the pool is split by weight, or switched by the header/cookie in the requests. It also returns the
+// backends with match that are ignored, as no HTTP policy switches the requests to the pool.
+func buildHTTPRuleBackends(vsNode AviVsEvhSniModel, pool *AviPoolNode, httpRulePath akov1beta1.HTTPRulePaths, host, ingName, namespace, infraSettingName, key string, isSNI, isDedicated bool, builtPools []*AviPoolNode) ([]*AviPoolNode, []string) {
+	var pgNode *AviPoolGroupNode
+	var primaryPools int
+	if isSNI {
+		poolRef := fmt.Sprintf("/api/pool?name=%s", pool.Name)
+		for _, pg := range vsNode.GetPoolGroupRefs() {
+			for _, member := range pg.Members {
+				if member.PoolRef != nil && *member.PoolRef == poolRef {
+					pgNode = pg
+					break
+				}
+			}
+		}
+		if pgNode != nil {
+			primaryPools = len(pgNode.Members)
+		}
+	} else {
+		// pools of the same path share the priority label in the pool group of the shared VS
+		for _, p := range vsNode.GetPoolRefs() {
+			if p.HTTPRuleBackendOf == "" && p.PriorityLabel == pool.PriorityLabel {
+				primaryPools++
+			}
+		}
+	}
+
+	var hppMap *AviHostPathPortPoolPG
+	var hppMapPolicy *AviHttpPolicySetNode
+	if isSNI {
+		for _, policy := range vsNode.GetHttpPolicyRefs() {
+			for i := range policy.HppMap {
+				if (pgNode != nil && policy.HppMap[i].PoolGroup == pgNode.Name) || policy.HppMap[i].Pool == pool.Name {
+					hppMap = &policy.HppMap[i]
+					hppMapPolicy = policy
+					break
+				}
 			}
 		}
 	}
 
+	var poolPath string
+	if len(pool.AviMarkers.Path) > 0 {
+		poolPath = pool.AviMarkers.Path[0]
+	}
+
+	var backendPools, weightedPools []*AviPoolNode
+	var backendHppMaps []AviHostPathPortPoolPG
+	var totalWeight uint32
+	var ignoredBackends []string
+	for _, backend := range httpRulePath.Backends {
+		if backend.Match == nil {
+			// the weights are relative to the single service of the ingress/route path
+			if pgNode == nil && isSNI {
+				utils.AviLog.Warnf("key: %s, msg: no pool group found for pool %s, weighted backend %s is ignored", key, pool.Name, backend.ServiceName)
+				continue
+			}
+			if primaryPools != 1 {
+				utils.AviLog.Warnf("key: %s, msg: path of pool %s has more than one service, weighted backend %s is ignored", key, pool.Name, backend.ServiceName)
+				continue
+			}
+		} else if !isSNI || hppMap == nil {
+			utils.AviLog.Warnf("key: %s, msg: no HTTP policy switches the requests to pool %s, backend %s with match is ignored", key, pool.Name, backend.ServiceName)
+			ignoredBackends = append(ignoredBackends, fmt.Sprintf("%s (path %s)", backend.ServiceName, httpRulePath.Target))
+			continue
+		}
+
+		var poolName string
+		if !isSNI {
+			poolName = lib.GetL7PoolName(host+poolPath, namespace, ingName, infraSettingName, backend.ServiceName)
+		} else if lib.IsEvhEnabled() {
+			poolName = lib.GetEvhPoolName(ingName, namespace, host, poolPath, infraSettingName, backend.ServiceName, isDedicated)
+		} else {
+			poolName = lib.GetSniPoolName(ingName, namespace, host, poolPath, infraSettingName, isDedicated, backend.ServiceName)
+		}
+		if poolExists(poolName, vsNode.GetPoolRefs(), builtPools, backendPools) {
+			utils.AviLog.Warnf("key: %s, msg: pool %s already exists, backend %s is ignored", key, poolName, backend.ServiceName)
+			continue
+		}
+
+		backendPool := pool.CopyNode().(*AviPoolNode)
+		backendPool.Name = poolName
+		backendPool.HTTPRuleBackendOf = pool.Name
+		backendPool.Port = backend.Port
+		servicePort := &networkingv1.ServiceBackendPort{Number: backend.Port}
+		validator := &Validator{}
+		backendPool.TargetPort = validator.findTargetPort(backend.ServiceName, namespace, servicePort, key)
+		backendPool.PortName = validator.findPortName(backend.ServiceName, namespace, backend.Port, key)
+		backendPool.AviMarkers.ServiceName = backend.ServiceName
+		if backendPool.PkiProfile != nil {
+			backendPool.PkiProfile.Name = lib.GetPoolPKIProfileName(poolName)
+		}
+		backendPool.Servers = nil
+		serviceType := lib.GetServiceType()
+		if serviceType == lib.NodePortLocal {
+			backendPool.Servers = PopulateServersForNPL(backendPool, namespace, backend.ServiceName, true, key)
+		} else if serviceType == lib.NodePort {
+			backendPool.Servers = PopulateServersForNodePort(backendPool, namespace, backend.ServiceName, true, key)
+		} else {
+			backendPool.Servers = PopulateServers(backendPool, namespace, backend.ServiceName, true, key)
+		}
+		updateHTTPRuleBackendServiceMappings(namespace, ingName, backend.ServiceName, key)
+
+		if backend.Match == nil {
+			ratio := pool.ServiceMetadata.PoolRatio * uint32(backend.Weight) / 100
+			if ratio == 0 {
+				ratio = 1
+			}
+			backendPool.ServiceMetadata.PoolRatio = ratio
+			totalWeight += uint32(backend.Weight)
+			weightedPools = append(weightedPools, backendPool)
+		} else {
+			backendPool.ServiceMetadata.PoolRatio = 0
+			backendHppMap := AviHostPathPortPoolPG{
+				Name:              poolName,
+				Path:              hppMap.Path,
+				Port:              hppMap.Port,
+				Pool:              poolName,
+				MatchCriteria:     hppMap.MatchCriteria,
+				Protocol:          hppMap.Protocol,
+				IngName:           hppMap.IngName,
+				HTTPRuleBackendOf: hppMap.Name,
+			}
+			matchCriteria := "HDR_EQUALS"
+			matchCase := "SENSITIVE"
+			if header := backend.Match.Header; header != nil {
+				backendHppMap.Hdrs = []*models.HdrMatch{{
+					Hdr:           proto.String(header.Name),
+					MatchCriteria: &matchCriteria,
+					MatchCase:     &matchCase,
+					Value:         []string{header.Value},
+				}}
+			} else {
+				backendHppMap.Cookie = &models.CookieMatch{
+					Name:          proto.String(backend.Match.Cookie.Name),
+					MatchCriteria: &matchCriteria,
+					MatchCase:     &matchCase,
+					Value:         proto.String(backend.Match.Cookie.Value),
+				}
+			}
+			backendHppMap.CalculateCheckSum()
+			backendHppMaps = append(backendHppMaps, backendHppMap)
+		}
+		backendPools = append(backendPools, backendPool)
+		utils.AviLog.Infof("key: %s, msg: added pool %s for httprule backend %s of pool %s", key, poolName, backend.ServiceName, pool.Name)
+	}
+
+	pool.HTTPRuleBackendsWeight = totalWeight
+	if pgNode != nil {
+		for _, member := range pgNode.Members {
+			if *member.PoolRef == fmt.Sprintf("/api/pool?name=%s", pool.Name) {
+				ratio := pool.GetPoolGroupRatio()
+				member.Ratio = &ratio
+			}
+		}
+		for _, backendPool := range weightedPools {
+			poolRef := fmt.Sprintf("/api/pool?name=%s", backendPool.Name)
+			ratio := backendPool.GetPoolGroupRatio()
+			pgNode.Members = append(pgNode.Members, &models.PoolGroupMember{PoolRef: &poolRef, Ratio: &ratio})
+		}
+	}
+	if hppMapPolicy != nil {
+		hppMapPolicy.HppMap = append(hppMapPolicy.HppMap, backendHppMaps...)
+	}
+	return backendPools, ignoredBackends
+}
+
+func poolExists(poolName string, poolLists ...[]*AviPoolNode) bool {
+	for _, pools := range poolLists {
+		for _, pool := range pools {
+			if pool.Name == poolName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// updateHTTPRuleBackendServiceMappings maps the service of an httprule backend to the ingress/route,
+// so that the changes in the endpoints of the service update the pool of the backend.
+func updateHTTPRuleBackendServiceMappings(namespace, ingName, svcName, key string) {
+	if utils.GetInformers().IngressInformer != nil {
+		if _, err := utils.GetInformers().IngressInformer.Lister().Ingresses(namespace).Get(ingName); err == nil {
+			objects.SharedSvcLister().IngressMappings(namespace).UpdateIngressMappings(ingName, svcName)
+			return
+		}
+	}
+	if utils.GetInformers().RouteInformer != nil {
+		if _, err := utils.GetInformers().RouteInformer.Lister().Routes(namespace).Get(ingName); err == nil {
+			objects.OshiftRouteSvcLister().IngressMappings(namespace).UpdateIngressMappings(ingName, svcName)
+			return
+		}
+	}
+	utils.AviLog.Debugf("key: %s, msg: ingress/route %s/%s not found for the service %s of httprule backend", key, namespace, ingName, svcName)
+}
+
+// removeHTTPRuleBackends removes the pools of the httprule backends of the ingress/route for the host,
+// along with their pool group members and HTTP policy rules.
+func removeHTTPRuleBackends(vsNode AviVsEvhSniModel, host, ingName, namespace string) {
+	var pools []*AviPoolNode
+	var removedPoolRefs []string
+	for _, pool := range vsNode.GetPoolRefs() {
+		if pool.ServiceMetadata.IngressName != ingName || pool.ServiceMetadata.Namespace != namespace || !utils.HasElem(pool.ServiceMetadata.HostNames, host) {
+			pools = append(pools, pool)
+			continue
+		}
+		if pool.HTTPRuleBackendOf != "" {
+			removedPoolRefs = append(removedPoolRefs, fmt.Sprintf("/api/pool?name=%s", pool.Name))
+			continue
+		}
+		pool.HTTPRuleBackendsWeight = 0
+		pools = append(pools, pool)
+	}
+	if len(removedPoolRefs) == 0 {
+		return
+	}
+	vsNode.SetPoolRefs(pools)
+
+	for _, pg := range vsNode.GetPoolGroupRefs() {
+		var members []*models.PoolGroupMember
+		for _, member := range pg.Members {
+			if member.PoolRef != nil && utils.HasElem(removedPoolRefs, *member.PoolRef) {
+				continue
+			}
+			for _, pool := range pools {
+				if member.PoolRef != nil && *member.PoolRef == fmt.Sprintf("/api/pool?name=%s", pool.Name) {
+					ratio := pool.GetPoolGroupRatio()
+					member.Ratio = &ratio
+				}
+			}
+			members = append(members, member)
+		}
+		pg.Members = members
+	}
+
+	for _, policy := range vsNode.GetHttpPolicyRefs() {
+		var hppMaps []AviHostPathPortPoolPG
+		for _, hppMap := range policy.HppMap {
+			if hppMap.HTTPRuleBackendOf == "" || hppMap.IngName != ingName {
+				hppMaps = append(hppMaps, hppMap)
+			}
+		}
+		policy.HppMap = hppMaps
+	}
+}
+
+// removeHTTPRuleBackendPools removes the pools of the httprule backends of the removed pool.
+func removeHTTPRuleBackendPools(pools []*AviPoolNode, poolName string) []*AviPoolNode {
+	var result []*AviPoolNode
+	for _, pool := range pools {
+		if pool.HTTPRuleBackendOf != poolName {
+			result = append(result, pool)
+		}
+	}
+	return result
+}
+
+// removeHTTPRuleBackendHppMaps removes the HTTP policy rules of the httprule backends of the removed rule.
+func removeHTTPRuleBackendHppMaps(hppMaps []AviHostPathPortPoolPG, hppMapName string) []AviHostPathPortPoolPG {
+	var result []AviHostPathPortPoolPG
+	for _, hppMap := range hppMaps {
+		if hppMap.HTTPRuleBackendOf != hppMapName {
+			result = append(result, hppMap)
+		}
+	}
+	return result
 }
 
 func BuildL7SSORule(host, key string, vsNode AviVsEvhSniModel) {
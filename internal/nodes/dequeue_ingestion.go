@@ -23,6 +23,7 @@ import (
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/status"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/tracing"
 	akov1beta1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1beta1"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 
@@ -37,6 +38,7 @@ func DequeueIngestion(key string, fullsync bool) {
 	var ingressNames, routeNames, mciNames []string
 	utils.AviLog.Infof("key: %s, msg: starting graph Sync", key)
 	lib.DecrementQueueCounter(utils.ObjectIngestionLayer)
+	defer tracing.StartIngestion(key)()
 	sharedQueue := utils.SharedWorkQueue().GetQueueByName(utils.GraphLayer)
 
 	objType, namespace, name := lib.ExtractTypeNameNamespace(key)
@@ -212,7 +214,7 @@ func DequeueIngestion(key string, fullsync bool) {
 			}
 
 			// Do not handle service update if it belongs to unaccepted namespace
-			if svcObj.Spec.Type == utils.LoadBalancer && !lib.GetLayer7Only() && utils.CheckIfNamespaceAccepted(namespace) {
+			if svcObj.Spec.Type == utils.LoadBalancer && lib.IsValidLoadBalancerClass(svcObj) && !lib.GetLayer7Only() && utils.CheckIfNamespaceAccepted(namespace) {
 				// This endpoint update affects a LB service.
 				aviModelGraph := NewAviObjectGraph()
 				if sharedVipKey, ok := svcObj.Annotations[lib.SharedVipSvcLBAnnotation]; ok && sharedVipKey != "" {
@@ -713,6 +715,7 @@ func handleL4Service(key string, fullsync bool) {
 	if !fullsync {
 		bkt := utils.Bkt(model_name, sharedQueue.NumWorkers)
 		sharedQueue.Workqueue[bkt].AddRateLimited(model_name)
+		tracing.ModelQueued(model_name, key)
 	}
 }
 
@@ -827,6 +830,7 @@ func PublishKeyToRestLayer(modelName string, key string, sharedQueue *utils.Work
 	bkt := utils.Bkt(modelName, sharedQueue.NumWorkers)
 	sharedQueue.Workqueue[bkt].AddRateLimited(modelName)
 	lib.IncrementQueueCounter(utils.GraphLayer)
+	tracing.ModelQueued(modelName, key)
 	utils.AviLog.Infof("key: %s, msg: Published key with modelName: %s", key, modelName)
 }
 
@@ -846,6 +850,12 @@ func isServiceDelete(svcName string, namespace string, key string) bool {
 		return true
 	}
 
+	// The service might be handled by another LB provider, in which case we should delete the L4
+	// dedicated virtual service.
+	if !lib.IsValidLoadBalancerClass(svc) {
+		return true
+	}
+
 	return false
 }
 
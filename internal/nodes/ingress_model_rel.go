@@ -952,7 +952,7 @@ func ServiceChanges(serviceName, namespace, key string) ([]string, bool) {
 			objects.SharedlbLister().RemoveSharedVipKeyServiceMappings(serviceNamespaceName)
 		}
 
-		if currentKey, ok := serviceObj.Annotations[lib.SharedVipSvcLBAnnotation]; ok {
+		if currentKey, ok := serviceObj.Annotations[lib.SharedVipSvcLBAnnotation]; ok && lib.IsValidLoadBalancerClass(serviceObj) {
 			if currentKey != oldKey {
 				vipKeys = append(vipKeys, serviceObj.Namespace+"/"+currentKey)
 			}
@@ -143,6 +143,17 @@ func (rest *RestOperations) AviDSCacheAdd(rest_op *utils.RestOp, vsKey avicache.
 			continue
 		}
 
+		var lastModifiedStr string
+		lastModifiedIntf, ok := resp["_last_modified"]
+		if !ok {
+			utils.AviLog.Warnf("key: %s, msg: last_modified not present in response %v", key, resp)
+		} else {
+			lastModifiedStr, ok = lastModifiedIntf.(string)
+			if !ok {
+				utils.AviLog.Warnf("key: %s, msg: last_modified is not of type string", key)
+			}
+		}
+
 		var poolgroups []string
 		if resp["pool_group_refs"] != nil {
 			pgs, _ := resp["pool_group_refs"].([]interface{})
@@ -155,7 +166,7 @@ func (rest *RestOperations) AviDSCacheAdd(rest_op *utils.RestOp, vsKey avicache.
 			}
 		}
 		ds_cache_obj := avicache.AviDSCache{Name: name, Tenant: rest_op.Tenant,
-			Uuid: uuid, PoolGroups: poolgroups}
+			Uuid: uuid, PoolGroups: poolgroups, LastModified: lastModifiedStr}
 
 		// Datascript should not have a checksum
 		checksum := lib.DSChecksum(ds_cache_obj.PoolGroups, nil, false)
@@ -37,6 +37,7 @@ func (rest *RestOperations) RestOperationForEvh(vsName string, namespace string,
 	var sni_to_delete []avicache.NamespaceName
 	var httppol_to_delete []avicache.NamespaceName
 	var l4pol_to_delete []avicache.NamespaceName
+	var app_profile_to_delete []avicache.NamespaceName
 	var sslkey_cert_delete []avicache.NamespaceName
 	var vsvipErr error
 	var publishKey string
@@ -69,6 +70,8 @@ func (rest *RestOperations) RestOperationForEvh(vsName string, namespace string,
 		pools_to_delete, rest_ops = rest.PoolCU(aviVsNode.PoolRefs, vs_cache_obj, namespace, rest_ops, key)
 		pgs_to_delete, rest_ops = rest.PoolGroupCU(aviVsNode.PoolGroupRefs, vs_cache_obj, namespace, rest_ops, key)
 		httppol_to_delete, rest_ops = rest.HTTPPolicyCU(aviVsNode.HttpPolicyRefs, vs_cache_obj, namespace, rest_ops, key)
+		l4pol_to_delete, rest_ops = rest.L4PolicyCU(aviVsNode.L4PolicyRefs, vs_cache_obj, namespace, rest_ops, key)
+		app_profile_to_delete, rest_ops = rest.ApplicationProfileCU(aviVsNode.AppProfileRefs, vs_cache_obj, namespace, rest_ops, key)
 		utils.AviLog.Debugf("key: %s, msg: stored checksum for VS: %s, model checksum: %s", key, vs_cache_obj.CloudConfigCksum, strconv.Itoa(int(aviVsNode.GetCheckSum())))
 		if vs_cache_obj.CloudConfigCksum == strconv.Itoa(int(aviVsNode.GetCheckSum())) {
 			utils.AviLog.Debugf("key: %s, msg: the checksums are same for vs %s, not doing anything", key, vs_cache_obj.Name)
@@ -97,6 +100,8 @@ func (rest *RestOperations) RestOperationForEvh(vsName string, namespace string,
 		_, rest_ops = rest.PoolCU(aviVsNode.PoolRefs, nil, namespace, rest_ops, key)
 		_, rest_ops = rest.PoolGroupCU(aviVsNode.PoolGroupRefs, nil, namespace, rest_ops, key)
 		_, rest_ops = rest.HTTPPolicyCU(aviVsNode.HttpPolicyRefs, nil, namespace, rest_ops, key)
+		_, rest_ops = rest.L4PolicyCU(aviVsNode.L4PolicyRefs, nil, namespace, rest_ops, key)
+		_, rest_ops = rest.ApplicationProfileCU(aviVsNode.AppProfileRefs, nil, namespace, rest_ops, key)
 
 		// The cache was not found - it's a POST call.
 		restOp := rest.AviVsBuildForEvh(aviVsNode, utils.RestPost, nil, key)
@@ -125,6 +130,7 @@ func (rest *RestOperations) RestOperationForEvh(vsName string, namespace string,
 	rest_ops = rest.VSVipDelete(vsvip_to_delete, namespace, rest_ops, key)
 	rest_ops = rest.HTTPPolicyDelete(httppol_to_delete, namespace, rest_ops, key)
 	rest_ops = rest.L4PolicyDelete(l4pol_to_delete, namespace, rest_ops, key)
+	rest_ops = rest.ApplicationProfileDelete(app_profile_to_delete, namespace, rest_ops, key)
 	rest_ops = rest.PoolGroupDelete(pgs_to_delete, namespace, rest_ops, key)
 	rest_ops = rest.PoolDelete(pools_to_delete, namespace, rest_ops, key)
 	if success, _ := rest.ExecuteRestAndPopulateCache(rest_ops, vsKey, avimodel, key, true); !success {
@@ -168,6 +174,8 @@ func (rest *RestOperations) EvhNodeCU(sni_node *nodes.AviEvhVsNode, vs_cache_obj
 	var sni_pools_to_delete []avicache.NamespaceName
 	var sni_pgs_to_delete []avicache.NamespaceName
 	var http_policies_to_delete []avicache.NamespaceName
+	var app_profile_to_delete []avicache.NamespaceName
+	var clone_profile_to_delete []avicache.NamespaceName
 	var sslkey_cert_delete []avicache.NamespaceName
 	if vs_cache_obj != nil {
 		sni_key := avicache.NamespaceName{Namespace: namespace, Name: sni_node.Name}
@@ -187,6 +195,8 @@ func (rest *RestOperations) EvhNodeCU(sni_node *nodes.AviEvhVsNode, vs_cache_obj
 				sni_pools_to_delete, rest_ops = rest.PoolCU(sni_node.PoolRefs, sni_cache_obj, namespace, rest_ops, key)
 				sni_pgs_to_delete, rest_ops = rest.PoolGroupCU(sni_node.PoolGroupRefs, sni_cache_obj, namespace, rest_ops, key)
 				http_policies_to_delete, rest_ops = rest.HTTPPolicyCU(sni_node.HttpPolicyRefs, sni_cache_obj, namespace, rest_ops, key)
+				app_profile_to_delete, rest_ops = rest.ApplicationProfileCU(sni_node.AppProfileRefs, sni_cache_obj, namespace, rest_ops, key)
+				clone_profile_to_delete, rest_ops = rest.TrafficCloneProfileCU(sni_node.CloneProfileRefs, sni_cache_obj, namespace, rest_ops, key)
 
 				// The checksums are different, so it should be a PUT call.
 				if sni_cache_obj.CloudConfigCksum != strconv.Itoa(int(sni_node.GetCheckSum())) {
@@ -205,6 +215,8 @@ func (rest *RestOperations) EvhNodeCU(sni_node *nodes.AviEvhVsNode, vs_cache_obj
 			_, rest_ops = rest.PoolCU(sni_node.PoolRefs, nil, namespace, rest_ops, key)
 			_, rest_ops = rest.PoolGroupCU(sni_node.PoolGroupRefs, nil, namespace, rest_ops, key)
 			_, rest_ops = rest.HTTPPolicyCU(sni_node.HttpPolicyRefs, nil, namespace, rest_ops, key)
+			_, rest_ops = rest.ApplicationProfileCU(sni_node.AppProfileRefs, nil, namespace, rest_ops, key)
+			_, rest_ops = rest.TrafficCloneProfileCU(sni_node.CloneProfileRefs, nil, namespace, rest_ops, key)
 
 			// Not found - it should be a POST call.
 			restOp := rest.AviVsBuildForEvh(sni_node, utils.RestPost, nil, key)
@@ -214,6 +226,8 @@ func (rest *RestOperations) EvhNodeCU(sni_node *nodes.AviEvhVsNode, vs_cache_obj
 		}
 		rest_ops = rest.SSLKeyCertDelete(sslkey_cert_delete, namespace, rest_ops, key)
 		rest_ops = rest.HTTPPolicyDelete(http_policies_to_delete, namespace, rest_ops, key)
+		rest_ops = rest.ApplicationProfileDelete(app_profile_to_delete, namespace, rest_ops, key)
+		rest_ops = rest.TrafficCloneProfileDelete(clone_profile_to_delete, namespace, rest_ops, key)
 		rest_ops = rest.PoolGroupDelete(sni_pgs_to_delete, namespace, rest_ops, key)
 		rest_ops = rest.PoolDelete(sni_pools_to_delete, namespace, rest_ops, key)
 		utils.AviLog.Debugf("key: %s, msg: the EVH VSes to be deleted are: %s", key, cache_sni_nodes)
@@ -224,6 +238,8 @@ func (rest *RestOperations) EvhNodeCU(sni_node *nodes.AviEvhVsNode, vs_cache_obj
 		_, rest_ops = rest.PoolCU(sni_node.PoolRefs, nil, namespace, rest_ops, key)
 		_, rest_ops = rest.PoolGroupCU(sni_node.PoolGroupRefs, nil, namespace, rest_ops, key)
 		_, rest_ops = rest.HTTPPolicyCU(sni_node.HttpPolicyRefs, nil, namespace, rest_ops, key)
+		_, rest_ops = rest.ApplicationProfileCU(sni_node.AppProfileRefs, nil, namespace, rest_ops, key)
+		_, rest_ops = rest.TrafficCloneProfileCU(sni_node.CloneProfileRefs, nil, namespace, rest_ops, key)
 
 		// Not found - it should be a POST call.
 		restOp := rest.AviVsBuildForEvh(sni_node, utils.RestPost, nil, key)
@@ -360,9 +376,31 @@ func (rest *RestOperations) AviVsBuildForEvh(vs_meta *nodes.AviEvhVsNode, rest_m
 		for i, pp := range vs_meta.PortProto {
 			port := uint32(pp.Port)
 			svc := avimodels.Service{Port: &port, EnableSsl: &vs_meta.PortProto[i].EnableSSL, EnableHttp2: &vs_meta.PortProto[i].EnableHTTP2}
+			if vs_meta.EVHParent {
+				// Gateway TCP/UDP/TLS listeners share the parent VS with the HTTP listeners,
+				// so the L4 profiles are overridden per service port.
+				switch pp.Protocol {
+				case utils.TCP, utils.TLS:
+					svc.OverrideApplicationProfileRef = proto.String("/api/applicationprofile/?name=" + utils.DEFAULT_L4_APP_PROFILE)
+					svc.OverrideNetworkProfileRef = proto.String("/api/networkprofile/?name=" + utils.DEFAULT_TCP_NW_PROFILE)
+				case utils.UDP:
+					svc.OverrideApplicationProfileRef = proto.String("/api/applicationprofile/?name=" + utils.DEFAULT_L4_APP_PROFILE)
+					svc.OverrideNetworkProfileRef = proto.String("/api/networkprofile/?name=" + utils.SYSTEM_UDP_FAST_PATH)
+				}
+			}
 			vs.Services = append(vs.Services, &svc)
 		}
 
+		if len(vs_meta.L4PolicyRefs) > 0 {
+			var l4Policies []*avimodels.L4Policies
+			for i, l4pol := range vs_meta.L4PolicyRefs {
+				j := int32(i)
+				l4PolicyRef := fmt.Sprintf("/api/l4policyset/?name=%s", l4pol.Name)
+				l4Policies = append(l4Policies, &avimodels.L4Policies{L4PolicySetRef: &l4PolicyRef, Index: &j})
+			}
+			vs.L4Policies = l4Policies
+		}
+
 		var httpPolicyCollection []*avimodels.HTTPPolicies
 		internalPolicyIndexBuffer := int32(11)
 		if len(vs_meta.HttpPolicyRefs) > 0 {
@@ -429,6 +467,8 @@ func (rest *RestOperations) AviVsBuildForEvh(vs_meta *nodes.AviEvhVsNode, rest_m
 			vs.RemoveListeningPortOnVsDown = &vsDownOnPoolDown
 		}
 		vs.AnalyticsPolicy = vs_meta.GetAnalyticsPolicy()
+		vs.RequestsRateLimit = vs_meta.GetRequestsRateLimit()
+		vs.ConnectionsRateLimit = vs_meta.GetConnectionsRateLimit()
 
 		if err := copier.CopyWithOption(&vs, &vs_meta.AviVsNodeGeneratedFields, copier.Option{IgnoreEmpty: true}); err != nil {
 			utils.AviLog.Warnf("key: %s, msg: unable to set few parameters in the VS, err: %v", key, err)
@@ -566,6 +606,11 @@ func (rest *RestOperations) AviVsChildEvhBuild(vs_meta *nodes.AviEvhVsNode, rest
 		evhChild.PoolGroupRef = &pg_ref
 	}
 
+	if len(vs_meta.CloneProfileRefs) != 0 {
+		clone_ref := "/api/trafficcloneprofile/?name=" + vs_meta.CloneProfileRefs[0].Name
+		evhChild.TrafficCloneProfileRef = &clone_ref
+	}
+
 	//DS from hostrule
 	var datascriptCollection []*avimodels.VSDataScripts
 	for i, script := range vs_meta.VsDatascriptRefs {
@@ -581,6 +626,8 @@ func (rest *RestOperations) AviVsChildEvhBuild(vs_meta *nodes.AviEvhVsNode, rest
 		evhChild.HTTPPolicies = AviVsHttpPSAdd(vs_meta, true)
 	}
 	evhChild.AnalyticsPolicy = vs_meta.GetAnalyticsPolicy()
+	evhChild.RequestsRateLimit = vs_meta.GetRequestsRateLimit()
+	evhChild.ConnectionsRateLimit = vs_meta.GetConnectionsRateLimit()
 	if err := copier.CopyWithOption(&evhChild, &vs_meta.AviVsNodeGeneratedFields, copier.Option{IgnoreEmpty: true}); err != nil {
 		utils.AviLog.Warnf("key: %s, msg: unable to set few parameters in the child VS, err: %v", key, err)
 	}
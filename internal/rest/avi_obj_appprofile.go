@@ -0,0 +1,207 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package rest
+
+import (
+	"errors"
+	"fmt"
+
+	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	"github.com/davecgh/go-spew/spew"
+	avimodels "github.com/vmware/alb-sdk/go/models"
+)
+
+// AviAppProfileBuild builds an HTTP application profile, which authenticates the clients
+// with their certificates and forwards the certificate details in the request headers.
+func (rest *RestOperations) AviAppProfileBuild(appProfileMeta *nodes.AviApplicationProfileNode, cacheObj *avicache.AviAppProfileCache, key string) *utils.RestOp {
+	if lib.CheckObjectNameLength(appProfileMeta.Name, lib.ApplicationProfile) {
+		utils.AviLog.Warnf("key: %s not processing application profile object", key)
+		return nil
+	}
+	name := appProfileMeta.Name
+	tenant := fmt.Sprintf("/api/tenant/?name=%s", appProfileMeta.Tenant)
+	cr := lib.AKOUser
+	appProfileType := lib.APPLICATION_PROFILE_TYPE_HTTP
+	mode := appProfileMeta.ClientCertificateMode
+	pkiProfileRef := fmt.Sprintf("/api/pkiprofile?name=%s", appProfileMeta.PkiProfileName)
+
+	httpProfile := &avimodels.HTTPApplicationProfile{
+		SslClientCertificateMode: &mode,
+		PkiProfileRef:            &pkiProfileRef,
+	}
+	if len(appProfileMeta.ClientCertHeaders) != 0 {
+		httpProfile.SslClientCertificateAction = &avimodels.SSLClientCertificateAction{
+			Headers: appProfileMeta.ClientCertHeaders,
+		}
+	}
+	appProfile := avimodels.ApplicationProfile{
+		Name:        &name,
+		CreatedBy:   &cr,
+		TenantRef:   &tenant,
+		Type:        &appProfileType,
+		HTTPProfile: httpProfile,
+	}
+	appProfile.Markers = lib.GetAllMarkers(appProfileMeta.AviMarkers)
+
+	var restOp utils.RestOp
+	if cacheObj != nil {
+		restOp = utils.RestOp{
+			ObjName: appProfileMeta.Name,
+			Path:    "/api/applicationprofile/" + cacheObj.Uuid,
+			Method:  utils.RestPut,
+			Obj:     appProfile,
+			Tenant:  appProfileMeta.Tenant,
+			Model:   "ApplicationProfile",
+		}
+	} else {
+		// Update an existing application profile if it exists in the cache but not associated with this VS.
+		appProfileKey := avicache.NamespaceName{Namespace: appProfileMeta.Tenant, Name: appProfileMeta.Name}
+		appProfileCache, ok := rest.cache.AppProfileCache.AviCacheGet(appProfileKey)
+		if ok {
+			appProfileCacheObj, _ := appProfileCache.(*avicache.AviAppProfileCache)
+			restOp = utils.RestOp{
+				ObjName: appProfileMeta.Name,
+				Path:    "/api/applicationprofile/" + appProfileCacheObj.Uuid,
+				Method:  utils.RestPut,
+				Obj:     appProfile,
+				Tenant:  appProfileMeta.Tenant,
+				Model:   "ApplicationProfile",
+			}
+		} else {
+			restOp = utils.RestOp{
+				ObjName: appProfileMeta.Name,
+				Path:    "/api/applicationprofile/",
+				Method:  utils.RestPost,
+				Obj:     appProfile,
+				Tenant:  appProfileMeta.Tenant,
+				Model:   "ApplicationProfile",
+			}
+		}
+	}
+
+	utils.AviLog.Debug(spew.Sprintf("ApplicationProfile Restop %v AviApplicationProfileMeta %v",
+		restOp, utils.Stringify(appProfileMeta)))
+	return &restOp
+}
+
+func (rest *RestOperations) AviAppProfileDel(uuid string, tenant string, key string) *utils.RestOp {
+	restOp := utils.RestOp{
+		Path:   "/api/applicationprofile/" + uuid,
+		Method: "DELETE",
+		Tenant: tenant,
+		Model:  "ApplicationProfile",
+	}
+	utils.AviLog.Infof(spew.Sprintf("key: %s, msg: Application Profile DELETE Restop %v ", key,
+		utils.Stringify(restOp)))
+	return &restOp
+}
+
+func (rest *RestOperations) AviAppProfileCacheAdd(restOp *utils.RestOp, vsKey avicache.NamespaceName, key string) error {
+	if (restOp.Err != nil) || (restOp.Response == nil) {
+		utils.AviLog.Warnf("key: %s, rest_op has err or no response for applicationprofile, err: %s, response: %s", key, restOp.Err, restOp.Response)
+		return errors.New("Errored rest_op")
+	}
+
+	respElems := rest.restOperator.RestRespArrToObjByType(restOp, "applicationprofile", key)
+	if respElems == nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to find Application Profile obj in resp %v", key, restOp.Response)
+		return errors.New("Application Profile object not found")
+	}
+
+	for _, resp := range respElems {
+		name, ok := resp["name"].(string)
+		if !ok {
+			utils.AviLog.Warnf("key: %s, msg: name not present in response %v", key, resp)
+			continue
+		}
+
+		uuid, ok := resp["uuid"].(string)
+		if !ok {
+			utils.AviLog.Warnf("key: %s, msg: uuid not present in response %v", key, resp)
+			continue
+		}
+
+		var lastModifiedStr string
+		lastModifiedIntf, ok := resp["_last_modified"]
+		if !ok {
+			utils.AviLog.Warnf("key: %s, msg: last_modified not present in response %v", key, resp)
+		} else {
+			lastModifiedStr, ok = lastModifiedIntf.(string)
+			if !ok {
+				utils.AviLog.Warnf("key: %s, msg: last_modified is not of type string", key)
+			}
+		}
+
+		var appProfile avimodels.ApplicationProfile
+		switch restOp.Obj.(type) {
+		case utils.AviRestObjMacro:
+			appProfile = restOp.Obj.(utils.AviRestObjMacro).Data.(avimodels.ApplicationProfile)
+		case avimodels.ApplicationProfile:
+			appProfile = restOp.Obj.(avimodels.ApplicationProfile)
+		}
+		emptyIngestionMarkers := utils.AviObjectMarkers{}
+		mode, pkiProfileName, headers := lib.GetClientCertAppProfileSettings(&appProfile)
+		appProfileCacheObj := avicache.AviAppProfileCache{
+			Name:             name,
+			Tenant:           restOp.Tenant,
+			Uuid:             uuid,
+			LastModified:     lastModifiedStr,
+			CloudConfigCksum: lib.ClientCertAppProfileChecksum(mode, pkiProfileName, headers, emptyIngestionMarkers, appProfile.Markers, true),
+		}
+		// the PKI profiles created by AKO are present in the cache
+		pkiKey := avicache.NamespaceName{Namespace: restOp.Tenant, Name: pkiProfileName}
+		if _, found := rest.cache.PKIProfileCache.AviCacheGet(pkiKey); found {
+			appProfileCacheObj.PkiProfileCollection = pkiKey
+		}
+
+		k := avicache.NamespaceName{Namespace: restOp.Tenant, Name: name}
+		rest.cache.AppProfileCache.AviCacheAdd(k, &appProfileCacheObj)
+		vsCache, ok := rest.cache.VsCacheMeta.AviCacheGet(vsKey)
+		if ok {
+			vsCacheObj, found := vsCache.(*avicache.AviVsCache)
+			if found {
+				vsCacheObj.AddToAppProfileCollection(k)
+				utils.AviLog.Debugf("key: %s, msg: modified the VS cache object for application profile collection. The cache now is :%v", key, utils.Stringify(vsCacheObj))
+			}
+		} else {
+			vsCacheObj := rest.cache.VsCacheMeta.AviCacheAddVS(vsKey)
+			vsCacheObj.AddToAppProfileCollection(k)
+			utils.AviLog.Infof(spew.Sprintf("key: %s, msg: added VS cache key during application profile update %v val %v", key, vsKey,
+				vsCacheObj))
+		}
+		utils.AviLog.Infof(spew.Sprintf("key: %s, msg: added Application Profile cache k %v val %v", key, k,
+			appProfileCacheObj))
+	}
+
+	return nil
+}
+
+func (rest *RestOperations) AviAppProfileCacheDel(restOp *utils.RestOp, vsKey avicache.NamespaceName, key string) error {
+	appProfileKey := avicache.NamespaceName{Namespace: restOp.Tenant, Name: restOp.ObjName}
+	rest.cache.AppProfileCache.AviCacheDelete(appProfileKey)
+	vsCache, ok := rest.cache.VsCacheMeta.AviCacheGet(vsKey)
+	if ok {
+		vsCacheObj, found := vsCache.(*avicache.AviVsCache)
+		if found {
+			vsCacheObj.RemoveFromAppProfileCollection(appProfileKey)
+		}
+	}
+
+	return nil
+}
@@ -63,8 +63,16 @@ func (rest *RestOperations) AviHttpPSBuild(hps_meta *nodes.AviHttpPolicySetNode,
 		httpPresentIng.Insert(hppmap.IngName)
 	}
 	sort.Slice(hppmapWithPath, func(i, j int) bool {
+		if len(hppmapWithPath[i].Path[0]) == len(hppmapWithPath[j].Path[0]) {
+			// the rules of the httprule backends match the header/cookie along with the path,
+			// and are evaluated before the rule for the path.
+			return hppmapWithPath[i].HTTPRuleBackendOf != "" && hppmapWithPath[j].HTTPRuleBackendOf == ""
+		}
 		return len(hppmapWithPath[i].Path[0]) > len(hppmapWithPath[j].Path[0])
 	})
+	sort.SliceStable(hppmapWithoutPath, func(i, j int) bool {
+		return hppmapWithoutPath[i].HTTPRuleBackendOf != "" && hppmapWithoutPath[j].HTTPRuleBackendOf == ""
+	})
 	hppmapAllPaths = append(hppmapAllPaths, hppmapWithPath...)
 	hppmapAllPaths = append(hppmapAllPaths, hppmapWithoutPath...)
 
@@ -90,14 +98,8 @@ func (rest *RestOperations) AviHttpPSBuild(hps_meta *nodes.AviHttpPolicySetNode,
 			continue
 		}
 		action := avimodels.HttpsecurityAction{
-			Action: &sec_rule.Action,
-		}
-		portMatch := avimodels.PortMatch{
-			MatchCriteria: &sec_rule.MatchCriteria,
-			Ports:         []int64{sec_rule.Port},
-		}
-		match := avimodels.MatchTarget{
-			VsPort: &portMatch,
+			Action:      &sec_rule.Action,
+			RateProfile: sec_rule.RateProfile,
 		}
 		var j int32
 		j = idx
@@ -105,9 +107,18 @@ func (rest *RestOperations) AviHttpPSBuild(hps_meta *nodes.AviHttpPolicySetNode,
 			Action: &action,
 			Enable: &sec_rule.Enable,
 			Index:  &j,
-			Match:  &match,
 			Name:   &name,
 		}
+		// rate limit rules apply to all the requests, and have no port to match
+		if sec_rule.Port != 0 {
+			portMatch := avimodels.PortMatch{
+				MatchCriteria: &sec_rule.MatchCriteria,
+				Ports:         []int64{sec_rule.Port},
+			}
+			rule.Match = &avimodels.MatchTarget{
+				VsPort: &portMatch,
+			}
+		}
 		http_sec_pol.Rules = append(http_sec_pol.Rules, &rule)
 		idx = idx + 1
 	}
@@ -141,6 +152,9 @@ func (rest *RestOperations) AviHttpPSBuild(hps_meta *nodes.AviHttpPolicySetNode,
 			match_target.VsPort = &vsport_match
 		}
 
+		match_target.Hdrs = hppmap.Hdrs
+		match_target.Cookie = hppmap.Cookie
+
 		sw_action := avimodels.HttpswitchingAction{}
 		if hppmap.Pool != "" {
 			action := "HTTP_SWITCHING_SELECT_POOL"
@@ -0,0 +1,221 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	"github.com/davecgh/go-spew/spew"
+	avimodels "github.com/vmware/alb-sdk/go/models"
+	"google.golang.org/protobuf/proto"
+)
+
+// AviNetworkSecurityPolicyBuild builds a network security policy with a single rule,
+// which denies the clients outside of the allowed client IPs.
+func (rest *RestOperations) AviNetworkSecurityPolicyBuild(nsp_meta *nodes.AviNetworkSecurityPolicyNode, cache_obj *avicache.AviNetworkSecurityPolicyCache, key string) *utils.RestOp {
+	if lib.CheckObjectNameLength(nsp_meta.Name, lib.NSP) {
+		utils.AviLog.Warnf("key: %s not processing network security policy object", key)
+		return nil
+	}
+	name := nsp_meta.Name
+	tenant := fmt.Sprintf("/api/tenant/?name=%s", nsp_meta.Tenant)
+	cr := lib.AKOUser
+
+	var prefixes []*avimodels.IPAddrPrefix
+	for _, clientIP := range nsp_meta.AllowedClientIPs {
+		_, ipNet, err := net.ParseCIDR(clientIP)
+		if err != nil {
+			utils.AviLog.Warnf("key: %s, msg: skipping invalid client IP prefix %s in network security policy %s", key, clientIP, name)
+			continue
+		}
+		atype := "V4"
+		if utils.IsV6(ipNet.IP.String()) {
+			atype = "V6"
+		}
+		mask, _ := ipNet.Mask.Size()
+		prefixes = append(prefixes, &avimodels.IPAddrPrefix{
+			IPAddr: &avimodels.IPAddr{Addr: proto.String(ipNet.IP.String()), Type: &atype},
+			Mask:   proto.Int32(int32(mask)),
+		})
+	}
+
+	rule := &avimodels.NetworkSecurityRule{
+		Name:   proto.String(name + "-deny"),
+		Index:  proto.Uint32(0),
+		Enable: proto.Bool(true),
+		Action: proto.String("NETWORK_SECURITY_POLICY_ACTION_TYPE_DENY"),
+		Match: &avimodels.NetworkSecurityMatchTarget{
+			ClientIP: &avimodels.IPAddrMatch{
+				MatchCriteria: proto.String("IS_NOT_IN"),
+				Prefixes:      prefixes,
+			},
+		},
+	}
+	nsp := avimodels.NetworkSecurityPolicy{
+		Name:      &name,
+		CreatedBy: &cr,
+		TenantRef: &tenant,
+		Rules:     []*avimodels.NetworkSecurityRule{rule},
+	}
+	nsp.Markers = lib.GetAllMarkers(nsp_meta.AviMarkers)
+
+	var rest_op utils.RestOp
+	if cache_obj != nil {
+		rest_op = utils.RestOp{
+			ObjName: nsp_meta.Name,
+			Path:    "/api/networksecuritypolicy/" + cache_obj.Uuid,
+			Method:  utils.RestPut,
+			Obj:     nsp,
+			Tenant:  nsp_meta.Tenant,
+			Model:   "NetworkSecurityPolicy",
+		}
+	} else {
+		// Update an existing network security policy if it exists in the cache but not associated with this VS.
+		nspKey := avicache.NamespaceName{Namespace: nsp_meta.Tenant, Name: nsp_meta.Name}
+		nspCache, ok := rest.cache.NSPCache.AviCacheGet(nspKey)
+		if ok {
+			nspCacheObj, _ := nspCache.(*avicache.AviNetworkSecurityPolicyCache)
+			rest_op = utils.RestOp{
+				ObjName: nsp_meta.Name,
+				Path:    "/api/networksecuritypolicy/" + nspCacheObj.Uuid,
+				Method:  utils.RestPut,
+				Obj:     nsp,
+				Tenant:  nsp_meta.Tenant,
+				Model:   "NetworkSecurityPolicy",
+			}
+		} else {
+			rest_op = utils.RestOp{
+				ObjName: nsp_meta.Name,
+				Path:    "/api/networksecuritypolicy/",
+				Method:  utils.RestPost,
+				Obj:     nsp,
+				Tenant:  nsp_meta.Tenant,
+				Model:   "NetworkSecurityPolicy",
+			}
+		}
+	}
+
+	utils.AviLog.Debug(spew.Sprintf("NetworkSecurityPolicy Restop %v AviNetworkSecurityPolicyMeta %v",
+		rest_op, utils.Stringify(nsp_meta)))
+	return &rest_op
+}
+
+func (rest *RestOperations) AviNetworkSecurityPolicyDel(uuid string, tenant string, key string) *utils.RestOp {
+	rest_op := utils.RestOp{
+		Path:   "/api/networksecuritypolicy/" + uuid,
+		Method: "DELETE",
+		Tenant: tenant,
+		Model:  "NetworkSecurityPolicy",
+	}
+	utils.AviLog.Infof(spew.Sprintf("key: %s, msg: Network Security Policy DELETE Restop %v ", key,
+		utils.Stringify(rest_op)))
+	return &rest_op
+}
+
+func (rest *RestOperations) AviNetworkSecurityPolicyCacheAdd(rest_op *utils.RestOp, vsKey avicache.NamespaceName, key string) error {
+	if (rest_op.Err != nil) || (rest_op.Response == nil) {
+		utils.AviLog.Warnf("key: %s, rest_op has err or no response for networksecuritypolicy, err: %s, response: %s", key, rest_op.Err, rest_op.Response)
+		return errors.New("Errored rest_op")
+	}
+
+	resp_elems := rest.restOperator.RestRespArrToObjByType(rest_op, "networksecuritypolicy", key)
+	if resp_elems == nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to find Network Security Policy obj in resp %v", key, rest_op.Response)
+		return errors.New("Network Security Policy object not found")
+	}
+
+	for _, resp := range resp_elems {
+		name, ok := resp["name"].(string)
+		if !ok {
+			utils.AviLog.Warnf("key: %s, msg: name not present in response %v", key, resp)
+			continue
+		}
+
+		uuid, ok := resp["uuid"].(string)
+		if !ok {
+			utils.AviLog.Warnf("key: %s, msg: uuid not present in response %v", key, resp)
+			continue
+		}
+
+		var lastModifiedStr string
+		lastModifiedIntf, ok := resp["_last_modified"]
+		if !ok {
+			utils.AviLog.Warnf("key: %s, msg: last_modified not present in response %v", key, resp)
+		} else {
+			lastModifiedStr, ok = lastModifiedIntf.(string)
+			if !ok {
+				utils.AviLog.Warnf("key: %s, msg: last_modified is not of type string", key)
+			}
+		}
+
+		var nsp avimodels.NetworkSecurityPolicy
+		switch rest_op.Obj.(type) {
+		case utils.AviRestObjMacro:
+			nsp = rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.NetworkSecurityPolicy)
+		case avimodels.NetworkSecurityPolicy:
+			nsp = rest_op.Obj.(avimodels.NetworkSecurityPolicy)
+		}
+		emptyIngestionMarkers := utils.AviObjectMarkers{}
+		cksum := lib.NetworkSecurityPolicyChecksum(lib.GetNSPAllowedClientIPs(nsp.Rules), emptyIngestionMarkers, nsp.Markers, true)
+		nspCacheObj := avicache.AviNetworkSecurityPolicyCache{
+			Name:             name,
+			Tenant:           rest_op.Tenant,
+			Uuid:             uuid,
+			LastModified:     lastModifiedStr,
+			CloudConfigCksum: cksum,
+		}
+
+		k := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: name}
+		rest.cache.NSPCache.AviCacheAdd(k, &nspCacheObj)
+		vs_cache, ok := rest.cache.VsCacheMeta.AviCacheGet(vsKey)
+		if ok {
+			vs_cache_obj, found := vs_cache.(*avicache.AviVsCache)
+			if found {
+				vs_cache_obj.AddToNSPCollection(k)
+				utils.AviLog.Debugf("key: %s, msg: modified the VS cache object for network security policy collection. The cache now is :%v", key, utils.Stringify(vs_cache_obj))
+			}
+		} else {
+			vs_cache_obj := rest.cache.VsCacheMeta.AviCacheAddVS(vsKey)
+			vs_cache_obj.AddToNSPCollection(k)
+			utils.AviLog.Infof(spew.Sprintf("key: %s, msg: added VS cache key during network security policy update %v val %v", key, vsKey,
+				vs_cache_obj))
+		}
+		utils.AviLog.Infof(spew.Sprintf("key: %s, msg: added Network Security Policy cache k %v val %v", key, k,
+			nspCacheObj))
+	}
+
+	return nil
+}
+
+func (rest *RestOperations) AviNetworkSecurityPolicyCacheDel(rest_op *utils.RestOp, vsKey avicache.NamespaceName, key string) error {
+	nspKey := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: rest_op.ObjName}
+	rest.cache.NSPCache.AviCacheDelete(nspKey)
+	vs_cache, ok := rest.cache.VsCacheMeta.AviCacheGet(vsKey)
+	if ok {
+		vs_cache_obj, found := vs_cache.(*avicache.AviVsCache)
+		if found {
+			vs_cache_obj.RemoveFromNSPCollection(nspKey)
+		}
+	}
+
+	return nil
+}
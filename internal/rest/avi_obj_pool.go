@@ -142,6 +142,14 @@ func (rest *RestOperations) AviPoolBuild(pool_meta *nodes.AviPoolNode, cache_obj
 		pool.ApplicationPersistenceProfileRef = pool_meta.ApplicationPersistenceProfileRef
 	}
 
+	if pool_meta.EnableHttp2 {
+		pool.EnableHttp2 = &pool_meta.EnableHttp2
+	}
+
+	if pool_meta.ServerName != "" {
+		pool.ServerName = &pool_meta.ServerName
+	}
+
 	for i, server := range pool_meta.Servers {
 		port := pool_meta.Port
 		sip := server.Ip
@@ -314,6 +322,7 @@ func (rest *RestOperations) AviPoolCacheAdd(rest_op *utils.RestOp, vsKey avicach
 		if (oldCacheServiceMetadataCRD != lib.CRDMetadata{}) {
 			status.HttpRuleEventBroadcast(k.Name, oldCacheServiceMetadataCRD, svc_mdata_obj.CRDStatus)
 		}
+		status.UpdateRuleAppliedTo(k.Name, oldCacheServiceMetadataCRD, svc_mdata_obj.CRDStatus)
 
 		// Update the VS object
 		vs_cache, ok := rest.cache.VsCacheMeta.AviCacheGet(vsKey)
@@ -403,6 +412,7 @@ func (rest *RestOperations) AviPoolCacheDel(rest_op *utils.RestOp, vsKey avicach
 	if (cacheServiceMetadataCRD != lib.CRDMetadata{}) {
 		status.HttpRuleEventBroadcast(poolKey.Name, cacheServiceMetadataCRD, lib.CRDMetadata{})
 	}
+	status.UpdateRuleAppliedTo(poolKey.Name, cacheServiceMetadataCRD, lib.CRDMetadata{})
 	return nil
 }
 
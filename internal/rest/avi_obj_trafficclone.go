@@ -0,0 +1,199 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package rest
+
+import (
+	"errors"
+	"fmt"
+
+	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	"github.com/davecgh/go-spew/spew"
+	avimodels "github.com/vmware/alb-sdk/go/models"
+)
+
+// AviTrafficCloneProfileBuild builds a traffic clone profile, which clones the requests
+// of a VS to the servers of a mirror backend.
+func (rest *RestOperations) AviTrafficCloneProfileBuild(cloneProfileMeta *nodes.AviTrafficCloneProfileNode, cacheObj *avicache.AviTrafficCloneProfileCache, key string) *utils.RestOp {
+	if lib.CheckObjectNameLength(cloneProfileMeta.Name, lib.TrafficCloneProfile) {
+		utils.AviLog.Warnf("key: %s not processing traffic clone profile object", key)
+		return nil
+	}
+	name := cloneProfileMeta.Name
+	tenant := fmt.Sprintf("/api/tenant/?name=%s", cloneProfileMeta.Tenant)
+	cloudRef := fmt.Sprintf("/api/cloud?name=%s", utils.CloudName)
+
+	var cloneServers []*avimodels.CloneServer
+	for _, server := range cloneProfileMeta.CloneServers {
+		addr := server
+		addrType := "V4"
+		if utils.IsV6(addr) {
+			addrType = "V6"
+		}
+		cloneServers = append(cloneServers, &avimodels.CloneServer{
+			IPAddress: &avimodels.IPAddr{Addr: &addr, Type: &addrType},
+		})
+	}
+	cloneProfile := avimodels.TrafficCloneProfile{
+		Name:         &name,
+		TenantRef:    &tenant,
+		CloudRef:     &cloudRef,
+		CloneServers: cloneServers,
+	}
+	cloneProfile.Markers = lib.GetAllMarkers(cloneProfileMeta.AviMarkers)
+
+	var restOp utils.RestOp
+	if cacheObj != nil {
+		restOp = utils.RestOp{
+			ObjName: cloneProfileMeta.Name,
+			Path:    "/api/trafficcloneprofile/" + cacheObj.Uuid,
+			Method:  utils.RestPut,
+			Obj:     cloneProfile,
+			Tenant:  cloneProfileMeta.Tenant,
+			Model:   "TrafficCloneProfile",
+		}
+	} else {
+		// Update an existing traffic clone profile if it exists in the cache but not associated with this VS.
+		cloneProfileKey := avicache.NamespaceName{Namespace: cloneProfileMeta.Tenant, Name: cloneProfileMeta.Name}
+		cloneProfileCache, ok := rest.cache.CloneProfileCache.AviCacheGet(cloneProfileKey)
+		if ok {
+			cloneProfileCacheObj, _ := cloneProfileCache.(*avicache.AviTrafficCloneProfileCache)
+			restOp = utils.RestOp{
+				ObjName: cloneProfileMeta.Name,
+				Path:    "/api/trafficcloneprofile/" + cloneProfileCacheObj.Uuid,
+				Method:  utils.RestPut,
+				Obj:     cloneProfile,
+				Tenant:  cloneProfileMeta.Tenant,
+				Model:   "TrafficCloneProfile",
+			}
+		} else {
+			restOp = utils.RestOp{
+				ObjName: cloneProfileMeta.Name,
+				Path:    "/api/trafficcloneprofile/",
+				Method:  utils.RestPost,
+				Obj:     cloneProfile,
+				Tenant:  cloneProfileMeta.Tenant,
+				Model:   "TrafficCloneProfile",
+			}
+		}
+	}
+
+	utils.AviLog.Debug(spew.Sprintf("TrafficCloneProfile Restop %v AviTrafficCloneProfileMeta %v",
+		restOp, utils.Stringify(cloneProfileMeta)))
+	return &restOp
+}
+
+func (rest *RestOperations) AviTrafficCloneProfileDel(uuid string, tenant string, key string) *utils.RestOp {
+	restOp := utils.RestOp{
+		Path:   "/api/trafficcloneprofile/" + uuid,
+		Method: "DELETE",
+		Tenant: tenant,
+		Model:  "TrafficCloneProfile",
+	}
+	utils.AviLog.Infof(spew.Sprintf("key: %s, msg: Traffic Clone Profile DELETE Restop %v ", key,
+		utils.Stringify(restOp)))
+	return &restOp
+}
+
+func (rest *RestOperations) AviTrafficCloneProfileCacheAdd(restOp *utils.RestOp, vsKey avicache.NamespaceName, key string) error {
+	if (restOp.Err != nil) || (restOp.Response == nil) {
+		utils.AviLog.Warnf("key: %s, rest_op has err or no response for trafficcloneprofile, err: %s, response: %s", key, restOp.Err, restOp.Response)
+		return errors.New("Errored rest_op")
+	}
+
+	respElems := rest.restOperator.RestRespArrToObjByType(restOp, "trafficcloneprofile", key)
+	if respElems == nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to find Traffic Clone Profile obj in resp %v", key, restOp.Response)
+		return errors.New("Traffic Clone Profile object not found")
+	}
+
+	for _, resp := range respElems {
+		name, ok := resp["name"].(string)
+		if !ok {
+			utils.AviLog.Warnf("key: %s, msg: name not present in response %v", key, resp)
+			continue
+		}
+
+		uuid, ok := resp["uuid"].(string)
+		if !ok {
+			utils.AviLog.Warnf("key: %s, msg: uuid not present in response %v", key, resp)
+			continue
+		}
+
+		var lastModifiedStr string
+		lastModifiedIntf, ok := resp["_last_modified"]
+		if !ok {
+			utils.AviLog.Warnf("key: %s, msg: last_modified not present in response %v", key, resp)
+		} else {
+			lastModifiedStr, ok = lastModifiedIntf.(string)
+			if !ok {
+				utils.AviLog.Warnf("key: %s, msg: last_modified is not of type string", key)
+			}
+		}
+
+		var cloneProfile avimodels.TrafficCloneProfile
+		switch restOp.Obj.(type) {
+		case utils.AviRestObjMacro:
+			cloneProfile = restOp.Obj.(utils.AviRestObjMacro).Data.(avimodels.TrafficCloneProfile)
+		case avimodels.TrafficCloneProfile:
+			cloneProfile = restOp.Obj.(avimodels.TrafficCloneProfile)
+		}
+		emptyIngestionMarkers := utils.AviObjectMarkers{}
+		cloneProfileCacheObj := avicache.AviTrafficCloneProfileCache{
+			Name:             name,
+			Tenant:           restOp.Tenant,
+			Uuid:             uuid,
+			LastModified:     lastModifiedStr,
+			CloudConfigCksum: lib.TrafficCloneProfileChecksum(lib.GetTrafficCloneProfileServers(&cloneProfile), emptyIngestionMarkers, cloneProfile.Markers, true),
+		}
+
+		k := avicache.NamespaceName{Namespace: restOp.Tenant, Name: name}
+		rest.cache.CloneProfileCache.AviCacheAdd(k, &cloneProfileCacheObj)
+		vsCache, ok := rest.cache.VsCacheMeta.AviCacheGet(vsKey)
+		if ok {
+			vsCacheObj, found := vsCache.(*avicache.AviVsCache)
+			if found {
+				vsCacheObj.AddToCloneKeyCollection(k)
+				utils.AviLog.Debugf("key: %s, msg: modified the VS cache object for traffic clone profile collection. The cache now is :%v", key, utils.Stringify(vsCacheObj))
+			}
+		} else {
+			vsCacheObj := rest.cache.VsCacheMeta.AviCacheAddVS(vsKey)
+			vsCacheObj.AddToCloneKeyCollection(k)
+			utils.AviLog.Infof(spew.Sprintf("key: %s, msg: added VS cache key during traffic clone profile update %v val %v", key, vsKey,
+				vsCacheObj))
+		}
+		utils.AviLog.Infof(spew.Sprintf("key: %s, msg: added Traffic Clone Profile cache k %v val %v", key, k,
+			cloneProfileCacheObj))
+	}
+
+	return nil
+}
+
+func (rest *RestOperations) AviTrafficCloneProfileCacheDel(restOp *utils.RestOp, vsKey avicache.NamespaceName, key string) error {
+	cloneProfileKey := avicache.NamespaceName{Namespace: restOp.Tenant, Name: restOp.ObjName}
+	rest.cache.CloneProfileCache.AviCacheDelete(cloneProfileKey)
+	vsCache, ok := rest.cache.VsCacheMeta.AviCacheGet(vsKey)
+	if ok {
+		vsCacheObj, found := vsCache.(*avicache.AviVsCache)
+		if found {
+			vsCacheObj.RemoveFromCloneKeyCollection(cloneProfileKey)
+		}
+	}
+
+	return nil
+}
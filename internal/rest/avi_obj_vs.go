@@ -259,6 +259,8 @@ func (rest *RestOperations) AviVsBuild(vs_meta *nodes.AviVsNode, rest_method uti
 			vs.PoolRef = &pool_ref
 		}
 		vs.AnalyticsPolicy = vs_meta.GetAnalyticsPolicy()
+		vs.RequestsRateLimit = vs_meta.GetRequestsRateLimit()
+		vs.ConnectionsRateLimit = vs_meta.GetConnectionsRateLimit()
 
 		if vs_meta.SslProfileRef != nil {
 			vs.SslProfileRef = vs_meta.SslProfileRef
@@ -341,6 +343,8 @@ func (rest *RestOperations) AviVsSniBuild(vs_meta *nodes.AviVsNode, rest_method
 		Enabled:               vs_meta.Enabled,
 	}
 	sniChild.AnalyticsPolicy = vs_meta.GetAnalyticsPolicy()
+	sniChild.RequestsRateLimit = vs_meta.GetRequestsRateLimit()
+	sniChild.ConnectionsRateLimit = vs_meta.GetConnectionsRateLimit()
 	if vs_meta.VrfContext != "" {
 		sniChild.VrfContextRef = proto.String("/api/vrfcontext?name=" + vs_meta.VrfContext)
 	}
@@ -688,6 +692,7 @@ func (rest *RestOperations) AviVsCacheAdd(rest_op *utils.RestOp, key string) err
 
 				status.HostRuleEventBroadcast(vs_cache_obj.Name, vs_cache_obj.ServiceMetadataObj.CRDStatus, svc_mdata_obj.CRDStatus)
 				status.SSORuleEventBroadcast(vs_cache_obj.Name, vs_cache_obj.ServiceMetadataObj.CRDStatus, svc_mdata_obj.CRDStatus)
+				status.UpdateRuleAppliedTo(vs_cache_obj.Name, vs_cache_obj.ServiceMetadataObj.CRDStatus, svc_mdata_obj.CRDStatus)
 				vs_cache_obj.ServiceMetadataObj = svc_mdata_obj
 				if val, ok := resp["enable_rhi"].(bool); ok {
 					vs_cache_obj.EnableRhi = val
@@ -732,6 +737,7 @@ func (rest *RestOperations) AviVsCacheAdd(rest_op *utils.RestOp, key string) err
 			rest.cache.VsCacheMeta.AviCacheAdd(k, vs_cache_obj)
 			status.HostRuleEventBroadcast(vs_cache_obj.Name, lib.CRDMetadata{}, svc_mdata_obj.CRDStatus)
 			status.SSORuleEventBroadcast(vs_cache_obj.Name, lib.CRDMetadata{}, svc_mdata_obj.CRDStatus)
+			status.UpdateRuleAppliedTo(vs_cache_obj.Name, lib.CRDMetadata{}, svc_mdata_obj.CRDStatus)
 			utils.AviLog.Infof("key: %s, msg: added VS cache key %v val %v", key, k, utils.Stringify(vs_cache_obj))
 		}
 
@@ -843,6 +849,7 @@ func (rest *RestOperations) AviVsCacheDel(rest_op *utils.RestOp, vsKey avicache.
 					rest.DeletePoolIngressStatus(poolKey, true, vs_cache_obj.Name, key)
 				}
 			}
+			status.UpdateRuleAppliedTo(vs_cache_obj.Name, vs_cache_obj.ServiceMetadataObj.CRDStatus, lib.CRDMetadata{})
 		}
 	}
 	utils.AviLog.Infof("key: %s, msg: deleting vs cache for key: %s", key, vsKey)
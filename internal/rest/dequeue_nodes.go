@@ -25,6 +25,8 @@ import (
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/retry"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/tracing"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api/models"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/third_party/github.com/vmware/alb-sdk/go/clients"
@@ -59,6 +61,7 @@ func (rest *RestOperations) CleanupVS(key string, skipVS bool) {
 func (rest *RestOperations) DequeueNodes(key string) {
 	utils.AviLog.Infof("key: %s, msg: start rest layer sync.", key)
 	lib.DecrementQueueCounter(utils.GraphLayer)
+	defer tracing.StartRest(key)()
 	// Got the key from the Graph Layer - let's fetch the model
 	ok, avimodelIntf := objects.SharedAviGraphLister().Get(key)
 	if !ok {
@@ -71,6 +74,12 @@ func (rest *RestOperations) DequeueNodes(key string) {
 		return
 	}
 	namespace, name := utils.ExtractNamespaceObjectName(key)
+	// Reset the retry attempts of the key, unless the sync publishes it to a retry queue again.
+	defer retry.KeySynced(name)
+	if rest.isDryRun() {
+		// The plan of the virtualservice is recomputed on every sync, since the cache is not updated in dry run mode.
+		models.DryRunPlans.ResetPlan(key)
+	}
 	vsKey := avicache.NamespaceName{Namespace: namespace, Name: name}
 	vs_cache_obj := rest.getVsCacheObj(vsKey, key)
 	if !ok || avimodelIntf == nil {
@@ -290,6 +299,8 @@ func (rest *RestOperations) RestOperation(vsName string, namespace string, avimo
 	var sni_to_delete []avicache.NamespaceName
 	var httppol_to_delete []avicache.NamespaceName
 	var l4pol_to_delete []avicache.NamespaceName
+	var nsp_to_delete []avicache.NamespaceName
+	var app_profile_to_delete []avicache.NamespaceName
 	var sslkey_cert_delete []avicache.NamespaceName
 	var vsvipErr error
 	var publishKey string
@@ -327,6 +338,8 @@ func (rest *RestOperations) RestOperation(vsName string, namespace string, avimo
 		httppol_to_delete, rest_ops = rest.HTTPPolicyCU(aviVsNode.HttpPolicyRefs, vs_cache_obj, namespace, rest_ops, key)
 		ds_to_delete, rest_ops = rest.DatascriptCU(aviVsNode.HTTPDSrefs, vs_cache_obj, namespace, rest_ops, key)
 		l4pol_to_delete, rest_ops = rest.L4PolicyCU(aviVsNode.L4PolicyRefs, vs_cache_obj, namespace, rest_ops, key)
+		nsp_to_delete, rest_ops = rest.NetworkSecurityPolicyCU(aviVsNode.NSPRefs, vs_cache_obj, namespace, rest_ops, key)
+		app_profile_to_delete, rest_ops = rest.ApplicationProfileCU(aviVsNode.AppProfileRefs, vs_cache_obj, namespace, rest_ops, key)
 		utils.AviLog.Debugf("key: %s, msg: stored checksum for VS: %s, model checksum: %s", key, vs_cache_obj.CloudConfigCksum, strconv.Itoa(int(aviVsNode.GetCheckSum())))
 		if vs_cache_obj.CloudConfigCksum == strconv.Itoa(int(aviVsNode.GetCheckSum())) {
 			utils.AviLog.Debugf("key: %s, msg: the checksums are same for vs %s, not doing anything", key, vs_cache_obj.Name)
@@ -358,6 +371,8 @@ func (rest *RestOperations) RestOperation(vsName string, namespace string, avimo
 		_, rest_ops = rest.PoolGroupCU(aviVsNode.PoolGroupRefs, nil, namespace, rest_ops, key)
 		_, rest_ops = rest.HTTPPolicyCU(aviVsNode.HttpPolicyRefs, nil, namespace, rest_ops, key)
 		_, rest_ops = rest.L4PolicyCU(aviVsNode.L4PolicyRefs, nil, namespace, rest_ops, key)
+		_, rest_ops = rest.NetworkSecurityPolicyCU(aviVsNode.NSPRefs, nil, namespace, rest_ops, key)
+		_, rest_ops = rest.ApplicationProfileCU(aviVsNode.AppProfileRefs, nil, namespace, rest_ops, key)
 		_, rest_ops = rest.DatascriptCU(aviVsNode.HTTPDSrefs, nil, namespace, rest_ops, key)
 
 		// The cache was not found - it's a POST call.
@@ -389,6 +404,8 @@ func (rest *RestOperations) RestOperation(vsName string, namespace string, avimo
 	}
 	rest_ops = rest.HTTPPolicyDelete(httppol_to_delete, namespace, rest_ops, key)
 	rest_ops = rest.L4PolicyDelete(l4pol_to_delete, namespace, rest_ops, key)
+	rest_ops = rest.NetworkSecurityPolicyDelete(nsp_to_delete, namespace, rest_ops, key)
+	rest_ops = rest.ApplicationProfileDelete(app_profile_to_delete, namespace, rest_ops, key)
 	rest_ops = rest.DSDelete(ds_to_delete, namespace, rest_ops, key)
 	rest_ops = rest.PoolGroupDelete(pgs_to_delete, namespace, rest_ops, key)
 	rest_ops = rest.PoolDelete(pools_to_delete, namespace, rest_ops, key)
@@ -526,6 +543,9 @@ func (rest *RestOperations) DeleteVSOper(vsKey avicache.NamespaceName, vs_cache_
 		rest_ops = rest.SSLKeyCertDelete(vs_cache_obj.SSLKeyCertCollection, namespace, rest_ops, key)
 		rest_ops = rest.HTTPPolicyDelete(vs_cache_obj.HTTPKeyCollection, namespace, rest_ops, key)
 		rest_ops = rest.L4PolicyDelete(vs_cache_obj.L4PolicyCollection, namespace, rest_ops, key)
+		rest_ops = rest.NetworkSecurityPolicyDelete(vs_cache_obj.NSPCollection, namespace, rest_ops, key)
+		rest_ops = rest.ApplicationProfileDelete(vs_cache_obj.AppProfileCollection, namespace, rest_ops, key)
+		rest_ops = rest.TrafficCloneProfileDelete(vs_cache_obj.CloneKeyCollection, namespace, rest_ops, key)
 		rest_ops = rest.PoolGroupDelete(vs_cache_obj.PGKeyCollection, namespace, rest_ops, key)
 		rest_ops = rest.PoolDelete(vs_cache_obj.PoolKeyCollection, namespace, rest_ops, key)
 		success, _ := rest.ExecuteRestAndPopulateCache(rest_ops, vsKey, nil, key, false)
@@ -565,6 +585,8 @@ func (rest *RestOperations) deleteSniVs(vsKey avicache.NamespaceName, vs_cache_o
 		rest_ops = rest.DataScriptDelete(vs_cache_obj.DSKeyCollection, namespace, rest_ops, key)
 		rest_ops = rest.SSLKeyCertDelete(vs_cache_obj.SSLKeyCertCollection, namespace, rest_ops, key)
 		rest_ops = rest.HTTPPolicyDelete(vs_cache_obj.HTTPKeyCollection, namespace, rest_ops, key)
+		rest_ops = rest.ApplicationProfileDelete(vs_cache_obj.AppProfileCollection, namespace, rest_ops, key)
+		rest_ops = rest.TrafficCloneProfileDelete(vs_cache_obj.CloneKeyCollection, namespace, rest_ops, key)
 		rest_ops = rest.PoolGroupDelete(vs_cache_obj.PGKeyCollection, namespace, rest_ops, key)
 		rest_ops = rest.PoolDelete(vs_cache_obj.PoolKeyCollection, namespace, rest_ops, key)
 		success, _ := rest.ExecuteRestAndPopulateCache(rest_ops, vsKey, avimodel, key, false)
@@ -580,13 +602,16 @@ func (rest *RestOperations) ExecuteRestAndPopulateCache(rest_ops []*utils.RestOp
 		// Dedicated VS case
 		shardSize = 8
 	}
-	var retry, fastRetry, processNextObj bool
+	var retry, fastRetry, processNextObj, rolledBack bool
 	bkt := utils.Bkt(key, shardSize)
 	if len(rest.aviRestPoolClient.AviClient) > 0 && len(rest_ops) > 0 {
 		utils.AviLog.Infof("key: %s, msg: processing in rest queue number: %v", key, bkt)
 		aviclient := rest.aviRestPoolClient.AviClient[bkt]
 		err := rest.AviRestOperateWrapper(aviclient, rest_ops, key)
-		if err == nil {
+		if err == nil && rest.isDryRun() {
+			// The cache is left untouched in dry run mode, so that it keeps reflecting the objects on the controller.
+			return true, true
+		} else if err == nil {
 			models.RestStatus.UpdateAviApiRestStatus(utils.AVIAPI_CONNECTED, nil)
 			utils.AviLog.Debugf("key: %s, msg: rest call executed successfully, will update cache", key)
 
@@ -627,6 +652,12 @@ func (rest *RestOperations) ExecuteRestAndPopulateCache(rest_ops []*utils.RestOp
 			for i := len(rest_ops) - 1; i >= 0; i-- {
 				// Go over each of the failed requests and enqueue them to the worker queue for retry.
 				if rest_ops[i].Err != nil {
+					// The object was deleted again by the rollback of the transaction, so it is not added to the
+					// cache, and the key is retried to create it.
+					if rest_ops[i].Err.Error() == lib.RolledBackError {
+						rolledBack = true
+						continue
+					}
 					// check for VSVIP errors for blocked IP address updates
 					if checkVsVipUpdateErrors(key, rest_ops[i]) {
 						rest.PopulateOneCache(rest_ops[i], aviObjKey, key)
@@ -680,7 +711,7 @@ func (rest *RestOperations) ExecuteRestAndPopulateCache(rest_ops []*utils.RestOp
 				}
 			}
 
-			if retry {
+			if retry || rolledBack {
 				if fastRetry {
 					rest.PublishKeyToRetryLayer(publishKey, key)
 				} else {
@@ -734,6 +765,12 @@ func (rest *RestOperations) PopulateOneCache(rest_op *utils.RestOp, aviObjKey av
 			rest.AviSSLKeyCertAdd(rest_op, aviObjKey, key)
 		} else if rest_op.Model == "L4PolicySet" {
 			rest.AviL4PolicyCacheAdd(rest_op, aviObjKey, key)
+		} else if rest_op.Model == "NetworkSecurityPolicy" {
+			rest.AviNetworkSecurityPolicyCacheAdd(rest_op, aviObjKey, key)
+		} else if rest_op.Model == "ApplicationProfile" {
+			rest.AviAppProfileCacheAdd(rest_op, aviObjKey, key)
+		} else if rest_op.Model == "TrafficCloneProfile" {
+			rest.AviTrafficCloneProfileCacheAdd(rest_op, aviObjKey, key)
 		} else if rest_op.Model == "VrfContext" {
 			rest.AviVrfCacheAdd(rest_op, aviObjKey, key)
 		} else if rest_op.Model == "VsVip" {
@@ -757,6 +794,12 @@ func (rest *RestOperations) PopulateOneCache(rest_op *utils.RestOp, aviObjKey av
 			rest.AviSSLCacheDel(rest_op, aviObjKey, key)
 		} else if rest_op.Model == "L4PolicySet" {
 			rest.AviL4PolicyCacheDel(rest_op, aviObjKey, key)
+		} else if rest_op.Model == "NetworkSecurityPolicy" {
+			rest.AviNetworkSecurityPolicyCacheDel(rest_op, aviObjKey, key)
+		} else if rest_op.Model == "ApplicationProfile" {
+			rest.AviAppProfileCacheDel(rest_op, aviObjKey, key)
+		} else if rest_op.Model == "TrafficCloneProfile" {
+			rest.AviTrafficCloneProfileCacheDel(rest_op, aviObjKey, key)
 		} else if rest_op.Model == "VsVip" {
 			rest.AviVsVipCacheDel(rest_op, aviObjKey, key)
 		} else if rest_op.Model == "VSDataScriptSet" {
@@ -780,17 +823,11 @@ func (rest *RestOperations) DataScriptDelete(dsToDelete []avicache.NamespaceName
 }
 
 func (rest *RestOperations) PublishKeyToRetryLayer(parentVsKey string, key string) {
-	fastRetryQueue := utils.SharedWorkQueue().GetQueueByName(lib.FAST_RETRY_LAYER)
-	fastRetryQueue.Workqueue[0].AddRateLimited(parentVsKey)
-	lib.IncrementQueueCounter(lib.FAST_RETRY_LAYER)
-	utils.AviLog.Infof("key: %s, msg: Published key with vs_key to fast path retry queue: %s", key, parentVsKey)
+	retry.PublishKeyToRetryQueue(lib.FAST_RETRY_LAYER, parentVsKey, key)
 }
 
 func (rest *RestOperations) PublishKeyToSlowRetryLayer(parentVsKey string, key string) {
-	slowRetryQueue := utils.SharedWorkQueue().GetQueueByName(lib.SLOW_RETRY_LAYER)
-	slowRetryQueue.Workqueue[0].AddRateLimited(parentVsKey)
-	lib.IncrementQueueCounter(lib.SLOW_RETRY_LAYER)
-	utils.AviLog.Infof("key: %s, msg: Published key with vs_key to slow path retry queue: %s", key, parentVsKey)
+	retry.PublishKeyToRetryQueue(lib.SLOW_RETRY_LAYER, parentVsKey, key)
 }
 
 func (rest *RestOperations) AviRestOperateWrapper(aviClient *clients.AviClient, rest_ops []*utils.RestOp, key string) error {
@@ -935,6 +972,42 @@ func (rest *RestOperations) RefreshCacheForRetryLayer(parentVsKey string, aviObj
 					rest_op.ObjName = L4PolicySet
 				}
 				rest.AviL4PolicyCacheDel(rest_op, aviObjKey, key)
+			case "NetworkSecurityPolicy":
+				var NetworkSecurityPolicy string
+				switch rest_op.Obj.(type) {
+				case utils.AviRestObjMacro:
+					NetworkSecurityPolicy = *rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.NetworkSecurityPolicy).Name
+				case avimodels.NetworkSecurityPolicy:
+					NetworkSecurityPolicy = *rest_op.Obj.(avimodels.NetworkSecurityPolicy).Name
+				}
+				if NetworkSecurityPolicy != "" {
+					rest_op.ObjName = NetworkSecurityPolicy
+				}
+				rest.AviNetworkSecurityPolicyCacheDel(rest_op, aviObjKey, key)
+			case "ApplicationProfile":
+				var ApplicationProfile string
+				switch rest_op.Obj.(type) {
+				case utils.AviRestObjMacro:
+					ApplicationProfile = *rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.ApplicationProfile).Name
+				case avimodels.ApplicationProfile:
+					ApplicationProfile = *rest_op.Obj.(avimodels.ApplicationProfile).Name
+				}
+				if ApplicationProfile != "" {
+					rest_op.ObjName = ApplicationProfile
+				}
+				rest.AviAppProfileCacheDel(rest_op, aviObjKey, key)
+			case "TrafficCloneProfile":
+				var TrafficCloneProfile string
+				switch rest_op.Obj.(type) {
+				case utils.AviRestObjMacro:
+					TrafficCloneProfile = *rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.TrafficCloneProfile).Name
+				case avimodels.TrafficCloneProfile:
+					TrafficCloneProfile = *rest_op.Obj.(avimodels.TrafficCloneProfile).Name
+				}
+				if TrafficCloneProfile != "" {
+					rest_op.ObjName = TrafficCloneProfile
+				}
+				rest.AviTrafficCloneProfileCacheDel(rest_op, aviObjKey, key)
 			case "SSLKeyAndCertificate":
 				var SSLKeyAndCertificate string
 				switch rest_op.Obj.(type) {
@@ -1026,6 +1099,33 @@ func (rest *RestOperations) RefreshCacheForRetryLayer(parentVsKey string, aviObj
 					L4PolicySet = *rest_op.Obj.(avimodels.L4PolicySet).Name
 				}
 				aviObjCache.AviPopulateOneVsL4PolCache(c, utils.CloudName, L4PolicySet)
+			case "NetworkSecurityPolicy":
+				var NetworkSecurityPolicy string
+				switch rest_op.Obj.(type) {
+				case utils.AviRestObjMacro:
+					NetworkSecurityPolicy = *rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.NetworkSecurityPolicy).Name
+				case avimodels.NetworkSecurityPolicy:
+					NetworkSecurityPolicy = *rest_op.Obj.(avimodels.NetworkSecurityPolicy).Name
+				}
+				aviObjCache.AviPopulateOneNSPCache(c, utils.CloudName, NetworkSecurityPolicy)
+			case "ApplicationProfile":
+				var ApplicationProfile string
+				switch rest_op.Obj.(type) {
+				case utils.AviRestObjMacro:
+					ApplicationProfile = *rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.ApplicationProfile).Name
+				case avimodels.ApplicationProfile:
+					ApplicationProfile = *rest_op.Obj.(avimodels.ApplicationProfile).Name
+				}
+				aviObjCache.AviPopulateOneAppProfileCache(c, utils.CloudName, ApplicationProfile)
+			case "TrafficCloneProfile":
+				var TrafficCloneProfile string
+				switch rest_op.Obj.(type) {
+				case utils.AviRestObjMacro:
+					TrafficCloneProfile = *rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.TrafficCloneProfile).Name
+				case avimodels.TrafficCloneProfile:
+					TrafficCloneProfile = *rest_op.Obj.(avimodels.TrafficCloneProfile).Name
+				}
+				aviObjCache.AviPopulateOneTrafficCloneProfileCache(c, utils.CloudName, TrafficCloneProfile)
 			case "SSLKeyAndCertificate":
 				var SSLKeyAndCertificate string
 				switch rest_op.Obj.(type) {
@@ -1206,7 +1306,7 @@ func (rest *RestOperations) PoolCU(pool_nodes []*nodes.AviPoolNode, vs_cache_obj
 				pool_cache, ok := rest.cache.PoolCache.AviCacheGet(pool_key)
 				if ok {
 					pool_cache_obj, _ := pool_cache.(*avicache.AviPoolCache)
-					pool_pkiprofile_delete, rest_ops = rest.PkiProfileCU(pool.PkiProfile, pool_cache_obj, namespace, rest_ops, key)
+					pool_pkiprofile_delete, rest_ops = rest.PkiProfileCU(pool.PkiProfile, &pool_cache_obj.PkiProfileCollection, namespace, rest_ops, key)
 
 					// Cache found. Let's compare the checksums
 					utils.AviLog.Debugf("key: %s, msg: poolcache: %v", key, pool_cache_obj)
@@ -1299,6 +1399,7 @@ func (rest *RestOperations) SNINodeCU(sni_node *nodes.AviVsNode, vs_cache_obj *a
 	var sni_pools_to_delete []avicache.NamespaceName
 	var sni_pgs_to_delete []avicache.NamespaceName
 	var http_policies_to_delete []avicache.NamespaceName
+	var app_profile_to_delete []avicache.NamespaceName
 	var sslkey_cert_delete []avicache.NamespaceName
 	if vs_cache_obj != nil {
 		sni_key := avicache.NamespaceName{Namespace: namespace, Name: sni_node.Name}
@@ -1318,6 +1419,7 @@ func (rest *RestOperations) SNINodeCU(sni_node *nodes.AviVsNode, vs_cache_obj *a
 				sni_pools_to_delete, rest_ops = rest.PoolCU(sni_node.PoolRefs, sni_cache_obj, namespace, rest_ops, key)
 				sni_pgs_to_delete, rest_ops = rest.PoolGroupCU(sni_node.PoolGroupRefs, sni_cache_obj, namespace, rest_ops, key)
 				http_policies_to_delete, rest_ops = rest.HTTPPolicyCU(sni_node.HttpPolicyRefs, sni_cache_obj, namespace, rest_ops, key)
+				app_profile_to_delete, rest_ops = rest.ApplicationProfileCU(sni_node.AppProfileRefs, sni_cache_obj, namespace, rest_ops, key)
 				// The checksums are different, so it should be a PUT call.
 				if sni_cache_obj.CloudConfigCksum != strconv.Itoa(int(sni_node.GetCheckSum())) {
 					restOp := rest.AviVsBuild(sni_node, utils.RestPut, sni_cache_obj, key)
@@ -1335,6 +1437,7 @@ func (rest *RestOperations) SNINodeCU(sni_node *nodes.AviVsNode, vs_cache_obj *a
 			_, rest_ops = rest.PoolCU(sni_node.PoolRefs, nil, namespace, rest_ops, key)
 			_, rest_ops = rest.PoolGroupCU(sni_node.PoolGroupRefs, nil, namespace, rest_ops, key)
 			_, rest_ops = rest.HTTPPolicyCU(sni_node.HttpPolicyRefs, nil, namespace, rest_ops, key)
+			_, rest_ops = rest.ApplicationProfileCU(sni_node.AppProfileRefs, nil, namespace, rest_ops, key)
 
 			// Not found - it should be a POST call.
 			restOp := rest.AviVsBuild(sni_node, utils.RestPost, nil, key)
@@ -1344,6 +1447,7 @@ func (rest *RestOperations) SNINodeCU(sni_node *nodes.AviVsNode, vs_cache_obj *a
 		}
 		rest_ops = rest.SSLKeyCertDelete(sslkey_cert_delete, namespace, rest_ops, key)
 		rest_ops = rest.HTTPPolicyDelete(http_policies_to_delete, namespace, rest_ops, key)
+		rest_ops = rest.ApplicationProfileDelete(app_profile_to_delete, namespace, rest_ops, key)
 		rest_ops = rest.PoolGroupDelete(sni_pgs_to_delete, namespace, rest_ops, key)
 		rest_ops = rest.PoolDelete(sni_pools_to_delete, namespace, rest_ops, key)
 		utils.AviLog.Debugf("key: %s, msg: the SNI VSes to be deleted are: %s", key, cache_sni_nodes)
@@ -1354,6 +1458,7 @@ func (rest *RestOperations) SNINodeCU(sni_node *nodes.AviVsNode, vs_cache_obj *a
 		_, rest_ops = rest.PoolCU(sni_node.PoolRefs, nil, namespace, rest_ops, key)
 		_, rest_ops = rest.PoolGroupCU(sni_node.PoolGroupRefs, nil, namespace, rest_ops, key)
 		_, rest_ops = rest.HTTPPolicyCU(sni_node.HttpPolicyRefs, nil, namespace, rest_ops, key)
+		_, rest_ops = rest.ApplicationProfileCU(sni_node.AppProfileRefs, nil, namespace, rest_ops, key)
 
 		// Not found - it should be a POST call.
 		restOp := rest.AviVsBuild(sni_node, utils.RestPost, nil, key)
@@ -1632,6 +1737,200 @@ func (rest *RestOperations) L4PolicyCU(l4_nodes []*nodes.AviL4PolicyNode, vs_cac
 	return cache_l4_nodes, rest_ops
 }
 
+func (rest *RestOperations) NetworkSecurityPolicyCU(nsp_nodes []*nodes.AviNetworkSecurityPolicyNode, vs_cache_obj *avicache.AviVsCache, namespace string, rest_ops []*utils.RestOp, key string) ([]avicache.NamespaceName, []*utils.RestOp) {
+	var cache_nsp_nodes []avicache.NamespaceName
+	// Default is POST
+	if vs_cache_obj != nil {
+		cache_nsp_nodes = make([]avicache.NamespaceName, len(vs_cache_obj.NSPCollection))
+		copy(cache_nsp_nodes, vs_cache_obj.NSPCollection)
+		for _, nsp := range nsp_nodes {
+			nsp_key := avicache.NamespaceName{Namespace: namespace, Name: nsp.Name}
+			found := utils.HasElem(cache_nsp_nodes, nsp_key)
+			if found {
+				nsp_cache, ok := rest.cache.NSPCache.AviCacheGet(nsp_key)
+				if ok {
+					cache_nsp_nodes = avicache.RemoveNamespaceName(cache_nsp_nodes, nsp_key)
+					nsp_cache_obj, _ := nsp_cache.(*avicache.AviNetworkSecurityPolicyCache)
+					// Cache found. Let's compare the checksums
+					if nsp_cache_obj.CloudConfigCksum == nsp.GetCheckSum() {
+						utils.AviLog.Debugf("key: %s, msg: the checksums are same for network security policy cache obj %s, not doing anything", key, nsp_cache_obj.Name)
+					} else {
+						// The checksums are different, so it should be a PUT call.
+						restOp := rest.AviNetworkSecurityPolicyBuild(nsp, nsp_cache_obj, key)
+						if restOp != nil {
+							rest_ops = append(rest_ops, restOp)
+						}
+					}
+				}
+			} else {
+				// Not found - it should be a POST call.
+				restOp := rest.AviNetworkSecurityPolicyBuild(nsp, nil, key)
+				if restOp != nil {
+					rest_ops = append(rest_ops, restOp)
+				}
+			}
+		}
+	} else {
+		// Everything is a POST call
+		for _, nsp := range nsp_nodes {
+			restOp := rest.AviNetworkSecurityPolicyBuild(nsp, nil, key)
+			if restOp != nil {
+				rest_ops = append(rest_ops, restOp)
+			}
+		}
+	}
+	utils.AviLog.Debugf("key: %s, msg: the network security policies to be deleted are: %s", key, cache_nsp_nodes)
+	return cache_nsp_nodes, rest_ops
+}
+
+func (rest *RestOperations) NetworkSecurityPolicyDelete(nsp_to_delete []avicache.NamespaceName, namespace string, rest_ops []*utils.RestOp, key string) []*utils.RestOp {
+	for _, del_nsp := range nsp_to_delete {
+		nsp_key := avicache.NamespaceName{Namespace: namespace, Name: del_nsp.Name}
+		nsp_cache, ok := rest.cache.NSPCache.AviCacheGet(nsp_key)
+		if ok {
+			nsp_cache_obj, _ := nsp_cache.(*avicache.AviNetworkSecurityPolicyCache)
+			restOp := rest.AviNetworkSecurityPolicyDel(nsp_cache_obj.Uuid, namespace, key)
+			restOp.ObjName = del_nsp.Name
+			rest_ops = append(rest_ops, restOp)
+		}
+	}
+	return rest_ops
+}
+
+func (rest *RestOperations) ApplicationProfileCU(app_profile_nodes []*nodes.AviApplicationProfileNode, vs_cache_obj *avicache.AviVsCache, namespace string, rest_ops []*utils.RestOp, key string) ([]avicache.NamespaceName, []*utils.RestOp) {
+	var cache_app_profile_nodes []avicache.NamespaceName
+	// Default is POST
+	if vs_cache_obj != nil {
+		cache_app_profile_nodes = make([]avicache.NamespaceName, len(vs_cache_obj.AppProfileCollection))
+		copy(cache_app_profile_nodes, vs_cache_obj.AppProfileCollection)
+		for _, app_profile := range app_profile_nodes {
+			app_profile_key := avicache.NamespaceName{Namespace: namespace, Name: app_profile.Name}
+			found := utils.HasElem(cache_app_profile_nodes, app_profile_key)
+			if found {
+				app_profile_cache, ok := rest.cache.AppProfileCache.AviCacheGet(app_profile_key)
+				if ok {
+					cache_app_profile_nodes = avicache.RemoveNamespaceName(cache_app_profile_nodes, app_profile_key)
+					app_profile_cache_obj, _ := app_profile_cache.(*avicache.AviAppProfileCache)
+					// The PKI profile has to be created before the application profile, which refers to it
+					var pki_profile_delete []avicache.NamespaceName
+					pki_profile_delete, rest_ops = rest.PkiProfileCU(app_profile.PkiProfile, &app_profile_cache_obj.PkiProfileCollection, namespace, rest_ops, key)
+					// Cache found. Let's compare the checksums
+					if app_profile_cache_obj.CloudConfigCksum == app_profile.GetCheckSum() {
+						utils.AviLog.Debugf("key: %s, msg: the checksums are same for application profile cache obj %s, not doing anything", key, app_profile_cache_obj.Name)
+					} else {
+						// The checksums are different, so it should be a PUT call.
+						restOp := rest.AviAppProfileBuild(app_profile, app_profile_cache_obj, key)
+						if restOp != nil {
+							rest_ops = append(rest_ops, restOp)
+						}
+					}
+					if len(pki_profile_delete) > 0 && pki_profile_delete[0].Name != "" {
+						rest_ops = rest.PkiProfileDelete(pki_profile_delete, namespace, rest_ops, key)
+					}
+				}
+			} else {
+				// Not found - it should be a POST call.
+				_, rest_ops = rest.PkiProfileCU(app_profile.PkiProfile, nil, namespace, rest_ops, key)
+				restOp := rest.AviAppProfileBuild(app_profile, nil, key)
+				if restOp != nil {
+					rest_ops = append(rest_ops, restOp)
+				}
+			}
+		}
+	} else {
+		// Everything is a POST call
+		for _, app_profile := range app_profile_nodes {
+			_, rest_ops = rest.PkiProfileCU(app_profile.PkiProfile, nil, namespace, rest_ops, key)
+			restOp := rest.AviAppProfileBuild(app_profile, nil, key)
+			if restOp != nil {
+				rest_ops = append(rest_ops, restOp)
+			}
+		}
+	}
+	utils.AviLog.Debugf("key: %s, msg: the application profiles to be deleted are: %s", key, cache_app_profile_nodes)
+	return cache_app_profile_nodes, rest_ops
+}
+
+// ApplicationProfileDelete deletes the application profiles, along with the PKI profiles created for them.
+func (rest *RestOperations) ApplicationProfileDelete(app_profile_to_delete []avicache.NamespaceName, namespace string, rest_ops []*utils.RestOp, key string) []*utils.RestOp {
+	for _, del_app_profile := range app_profile_to_delete {
+		app_profile_key := avicache.NamespaceName{Namespace: namespace, Name: del_app_profile.Name}
+		app_profile_cache, ok := rest.cache.AppProfileCache.AviCacheGet(app_profile_key)
+		if ok {
+			app_profile_cache_obj, _ := app_profile_cache.(*avicache.AviAppProfileCache)
+			restOp := rest.AviAppProfileDel(app_profile_cache_obj.Uuid, namespace, key)
+			restOp.ObjName = del_app_profile.Name
+			rest_ops = append(rest_ops, restOp)
+
+			pkiProfile := app_profile_cache_obj.PkiProfileCollection
+			if pkiProfile.Name != "" {
+				rest_ops = rest.PkiProfileDelete([]avicache.NamespaceName{pkiProfile}, namespace, rest_ops, key)
+			}
+		}
+	}
+	return rest_ops
+}
+
+func (rest *RestOperations) TrafficCloneProfileCU(clone_profile_nodes []*nodes.AviTrafficCloneProfileNode, vs_cache_obj *avicache.AviVsCache, namespace string, rest_ops []*utils.RestOp, key string) ([]avicache.NamespaceName, []*utils.RestOp) {
+	var cache_clone_profile_nodes []avicache.NamespaceName
+	// Default is POST
+	if vs_cache_obj != nil {
+		cache_clone_profile_nodes = make([]avicache.NamespaceName, len(vs_cache_obj.CloneKeyCollection))
+		copy(cache_clone_profile_nodes, vs_cache_obj.CloneKeyCollection)
+		for _, clone_profile := range clone_profile_nodes {
+			clone_profile_key := avicache.NamespaceName{Namespace: namespace, Name: clone_profile.Name}
+			found := utils.HasElem(cache_clone_profile_nodes, clone_profile_key)
+			if found {
+				clone_profile_cache, ok := rest.cache.CloneProfileCache.AviCacheGet(clone_profile_key)
+				if ok {
+					cache_clone_profile_nodes = avicache.RemoveNamespaceName(cache_clone_profile_nodes, clone_profile_key)
+					clone_profile_cache_obj, _ := clone_profile_cache.(*avicache.AviTrafficCloneProfileCache)
+					// Cache found. Let's compare the checksums
+					if clone_profile_cache_obj.CloudConfigCksum == clone_profile.GetCheckSum() {
+						utils.AviLog.Debugf("key: %s, msg: the checksums are same for traffic clone profile cache obj %s, not doing anything", key, clone_profile_cache_obj.Name)
+					} else {
+						// The checksums are different, so it should be a PUT call.
+						restOp := rest.AviTrafficCloneProfileBuild(clone_profile, clone_profile_cache_obj, key)
+						if restOp != nil {
+							rest_ops = append(rest_ops, restOp)
+						}
+					}
+				}
+			} else {
+				// Not found - it should be a POST call.
+				restOp := rest.AviTrafficCloneProfileBuild(clone_profile, nil, key)
+				if restOp != nil {
+					rest_ops = append(rest_ops, restOp)
+				}
+			}
+		}
+	} else {
+		// Everything is a POST call
+		for _, clone_profile := range clone_profile_nodes {
+			restOp := rest.AviTrafficCloneProfileBuild(clone_profile, nil, key)
+			if restOp != nil {
+				rest_ops = append(rest_ops, restOp)
+			}
+		}
+	}
+	utils.AviLog.Debugf("key: %s, msg: the traffic clone profiles to be deleted are: %s", key, cache_clone_profile_nodes)
+	return cache_clone_profile_nodes, rest_ops
+}
+
+func (rest *RestOperations) TrafficCloneProfileDelete(clone_profile_to_delete []avicache.NamespaceName, namespace string, rest_ops []*utils.RestOp, key string) []*utils.RestOp {
+	for _, del_clone_profile := range clone_profile_to_delete {
+		clone_profile_key := avicache.NamespaceName{Namespace: namespace, Name: del_clone_profile.Name}
+		clone_profile_cache, ok := rest.cache.CloneProfileCache.AviCacheGet(clone_profile_key)
+		if ok {
+			clone_profile_cache_obj, _ := clone_profile_cache.(*avicache.AviTrafficCloneProfileCache)
+			restOp := rest.AviTrafficCloneProfileDel(clone_profile_cache_obj.Uuid, namespace, key)
+			restOp.ObjName = del_clone_profile.Name
+			rest_ops = append(rest_ops, restOp)
+		}
+	}
+	return rest_ops
+}
+
 func (rest *RestOperations) HTTPPolicyDelete(https_to_delete []avicache.NamespaceName, namespace string, rest_ops []*utils.RestOp, key string) []*utils.RestOp {
 	for _, del_http := range https_to_delete {
 		// fetch trhe http policyset uuid from cache
@@ -1744,12 +2043,14 @@ func (rest *RestOperations) SSLKeyCertDelete(ssl_to_delete []avicache.NamespaceN
 	return rest_ops
 }
 
-func (rest *RestOperations) PkiProfileCU(pki_node *nodes.AviPkiProfileNode, pool_cache_obj *avicache.AviPoolCache, namespace string, rest_ops []*utils.RestOp, key string) ([]avicache.NamespaceName, []*utils.RestOp) {
+// PkiProfileCU creates or updates the PKI profile of a pool or an application profile,
+// whose cached PKI profile is cache_pki_key. The cached PKI profile is returned if it is stale.
+func (rest *RestOperations) PkiProfileCU(pki_node *nodes.AviPkiProfileNode, cache_pki_key *avicache.NamespaceName, namespace string, rest_ops []*utils.RestOp, key string) ([]avicache.NamespaceName, []*utils.RestOp) {
 	// Default is POST
 	var cache_pki_nodes []avicache.NamespaceName
-	if pool_cache_obj != nil {
+	if cache_pki_key != nil {
 		cache_pki_nodes = make([]avicache.NamespaceName, 1)
-		copy(cache_pki_nodes, []avicache.NamespaceName{pool_cache_obj.PkiProfileCollection})
+		copy(cache_pki_nodes, []avicache.NamespaceName{*cache_pki_key})
 
 		if pki_node != nil {
 			pki_key := avicache.NamespaceName{Namespace: namespace, Name: pki_node.Name}
@@ -0,0 +1,514 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/third_party/github.com/vmware/alb-sdk/go/clients"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/third_party/github.com/vmware/alb-sdk/go/session"
+)
+
+// driftObjectTypes are the types of the Avi objects which are checked for drift. The application profiles and
+// traffic clone profiles of a VS are not checked, as they are not owned by a single model when they are shared.
+var driftObjectTypes = []string{
+	"VirtualService",
+	"VsVip",
+	"Pool",
+	"PoolGroup",
+	"HTTPPolicySet",
+	"SSLKeyAndCertificate",
+	"PKIProfile",
+	"L4PolicySet",
+	"NetworkSecurityPolicy",
+	"VSDataScriptSet",
+}
+
+// driftAviObject is the state of an Avi object, either as last applied by AKO or as live on the Avi controller.
+// The checksum is only known for the objects applied by AKO, since it is computed by AKO from the configuration.
+type driftAviObject struct {
+	model        string
+	name         string
+	cksum        string
+	lastModified string
+}
+
+// desiredAviObject is an Avi object of a model in the graph layer, along with the state of the object
+// in the cache at the time the drift detection started.
+type desiredAviObject struct {
+	driftAviObject
+	modelName string
+	tenant    string
+	metadata  lib.ServiceMetadataObj
+}
+
+func (rest *RestOperations) DetectDrift() {
+	rest.restOperator.DetectDrift()
+}
+
+// DetectDrift compares the Avi objects of the models in the graph layer with the live objects on the
+// Avi controller, and handles the objects which were changed or deleted outside of AKO. Only the objects
+// which are in sync with their models are checked, the others are already due to be synced.
+// A change is detected from the _last_modified timestamp of the live object, which is later than the one
+// AKO last applied. The checksums stored in the objects are only written by AKO and are not changed by the
+// edits from the Avi UI or API, so they are not compared.
+func (l *leader) DetectDrift() {
+	client := avicache.SharedAVIDriftClient()
+	if client == nil {
+		return
+	}
+
+	// The cache is read before the live objects are fetched, so that an object synced meanwhile is skipped.
+	desiredObjects := make(map[string][]desiredAviObject)
+	allModelsMap := objects.SharedAviGraphLister().GetAll()
+	for modelName, modelIntf := range allModelsMap.(map[string]interface{}) {
+		aviModel, ok := modelIntf.(*nodes.AviObjectGraph)
+		if !ok || aviModel == nil {
+			continue
+		}
+		tenant, _ := utils.ExtractNamespaceObjectName(modelName)
+		for _, obj := range getModelAviObjects(aviModel) {
+			cached, found := l.restOp.getCachedDriftObject(obj.model, avicache.NamespaceName{Namespace: tenant, Name: obj.name})
+			if !found || cached.cksum != obj.cksum {
+				continue
+			}
+			desiredObjects[tenant] = append(desiredObjects[tenant], desiredAviObject{
+				driftAviObject: cached,
+				modelName:      modelName,
+				tenant:         tenant,
+				metadata:       obj.metadata,
+			})
+		}
+	}
+
+	modelsToSync := make(map[string]bool)
+	for tenant, tenantObjects := range desiredObjects {
+		liveObjects := make(map[string]map[string]driftAviObject)
+		for _, model := range driftObjectTypes {
+			objs, err := getLiveDriftObjects(client, tenant, model)
+			if err != nil {
+				utils.AviLog.Warnf("Unable to fetch %s objects of tenant %s for drift detection: %v", model, tenant, err)
+				continue
+			}
+			liveObjects[model] = objs
+		}
+
+		for _, obj := range tenantObjects {
+			liveObjs, ok := liveObjects[obj.model]
+			if !ok {
+				continue
+			}
+			cached, found := l.restOp.getCachedDriftObject(obj.model, avicache.NamespaceName{Namespace: tenant, Name: obj.name})
+			if !found || cached != obj.driftAviObject {
+				continue
+			}
+			live, found := liveObjs[obj.name]
+			var reason string
+			if !found {
+				reason = "deleted"
+			} else if isModifiedAfter(live.lastModified, obj.lastModified) {
+				reason = "modified"
+			} else {
+				continue
+			}
+			if l.handleDrift(obj, reason) {
+				modelsToSync[obj.modelName] = true
+			}
+		}
+	}
+
+	sharedQueue := utils.SharedWorkQueue().GetQueueByName(utils.GraphLayer)
+	for modelName := range modelsToSync {
+		nodes.PublishKeyToRestLayer(modelName, "drift", sharedQueue)
+	}
+}
+
+func (f *follower) DetectDrift() {
+	utils.AviLog.Debug("AKO is running as a follower, not detecting drift")
+}
+
+// handleDrift reports an object which was changed outside of AKO, and invalidates its cached checksum if the
+// object has to be re-applied, so that the next sync of its model updates it. It returns true if the model
+// of the object has to be synced.
+func (l *leader) handleDrift(obj desiredAviObject, reason string) bool {
+	action := lib.GetDriftAction(obj.model)
+	utils.AviLog.Warnf("Drift detected for %s %s of model %s: %s, action: %s", obj.model, obj.name, obj.modelName, reason, action)
+	lib.IncrementDriftCounter(obj.model, action)
+
+	message := fmt.Sprintf("%s %s was %s on the Avi controller outside of AKO", obj.model, obj.name, reason)
+	if action == lib.DriftActionReapply {
+		message += ", re-applying it"
+	}
+	owners := nodes.GetServiceMetadataOwners(obj.metadata)
+	for _, owner := range owners {
+		lib.AKOControlConfig().EventRecorder().Eventf(owner, corev1.EventTypeWarning, lib.DriftDetected, message)
+	}
+	if len(owners) == 0 {
+		lib.AKOControlConfig().PodEventf(corev1.EventTypeWarning, lib.DriftDetected, message)
+	}
+
+	if action != lib.DriftActionReapply {
+		return false
+	}
+	l.restOp.invalidateCachedDriftObject(obj.model, avicache.NamespaceName{Namespace: obj.tenant, Name: obj.name})
+	return true
+}
+
+// modelAviObject is an Avi object of a model, with the checksum of its desired configuration.
+type modelAviObject struct {
+	model    string
+	name     string
+	cksum    string
+	metadata lib.ServiceMetadataObj
+}
+
+func getModelAviObjects(aviModel *nodes.AviObjectGraph) []modelAviObject {
+	var objs []modelAviObject
+	for _, vsNode := range aviModel.GetAviVS() {
+		objs = append(objs, getVsNodeAviObjects(vsNode)...)
+	}
+	for _, evhNode := range aviModel.GetAviEvhVS() {
+		objs = append(objs, getEvhNodeAviObjects(evhNode)...)
+	}
+	return objs
+}
+
+func getVsNodeAviObjects(vsNode *nodes.AviVsNode) []modelAviObject {
+	objs := []modelAviObject{{
+		model:    "VirtualService",
+		name:     vsNode.Name,
+		cksum:    strconv.Itoa(int(vsNode.GetCheckSum())),
+		metadata: vsNode.ServiceMetadata,
+	}}
+	objs = append(objs, getChildAviObjects(vsChildNodes{
+		vsvips:       vsNode.VSVIPRefs,
+		pools:        vsNode.PoolRefs,
+		poolGroups:   vsNode.PoolGroupRefs,
+		httpPolicies: vsNode.HttpPolicyRefs,
+		sslKeyCerts:  append(append([]*nodes.AviTLSKeyCertNode{}, vsNode.CACertRefs...), vsNode.SSLKeyCertRefs...),
+		l4Policies:   vsNode.L4PolicyRefs,
+		nsps:         vsNode.NSPRefs,
+		dataScripts:  vsNode.HTTPDSrefs,
+		appProfiles:  vsNode.AppProfileRefs,
+	}, vsNode.ServiceMetadata)...)
+	for _, sniNode := range vsNode.SniNodes {
+		objs = append(objs, getVsNodeAviObjects(sniNode)...)
+	}
+	return objs
+}
+
+func getEvhNodeAviObjects(evhNode *nodes.AviEvhVsNode) []modelAviObject {
+	objs := []modelAviObject{{
+		model:    "VirtualService",
+		name:     evhNode.Name,
+		cksum:    strconv.Itoa(int(evhNode.GetCheckSum())),
+		metadata: evhNode.ServiceMetadata,
+	}}
+	objs = append(objs, getChildAviObjects(vsChildNodes{
+		vsvips:       evhNode.VSVIPRefs,
+		pools:        evhNode.PoolRefs,
+		poolGroups:   evhNode.PoolGroupRefs,
+		httpPolicies: evhNode.HttpPolicyRefs,
+		sslKeyCerts:  append(append([]*nodes.AviTLSKeyCertNode{}, evhNode.CACertRefs...), evhNode.SSLKeyCertRefs...),
+		l4Policies:   evhNode.L4PolicyRefs,
+		dataScripts:  evhNode.HTTPDSrefs,
+		appProfiles:  evhNode.AppProfileRefs,
+	}, evhNode.ServiceMetadata)...)
+	for _, childNode := range evhNode.EvhNodes {
+		objs = append(objs, getEvhNodeAviObjects(childNode)...)
+	}
+	return objs
+}
+
+// vsChildNodes are the nodes of the Avi objects a virtualservice node refers to.
+type vsChildNodes struct {
+	vsvips       []*nodes.AviVSVIPNode
+	pools        []*nodes.AviPoolNode
+	poolGroups   []*nodes.AviPoolGroupNode
+	httpPolicies []*nodes.AviHttpPolicySetNode
+	sslKeyCerts  []*nodes.AviTLSKeyCertNode
+	l4Policies   []*nodes.AviL4PolicyNode
+	nsps         []*nodes.AviNetworkSecurityPolicyNode
+	dataScripts  []*nodes.AviHTTPDataScriptNode
+	appProfiles  []*nodes.AviApplicationProfileNode
+}
+
+func getChildAviObjects(children vsChildNodes, metadata lib.ServiceMetadataObj) []modelAviObject {
+	var objs []modelAviObject
+	addObj := func(model, name string, cksum uint32) {
+		objs = append(objs, modelAviObject{model: model, name: name, cksum: strconv.Itoa(int(cksum)), metadata: metadata})
+	}
+	for _, vsvip := range children.vsvips {
+		addObj("VsVip", vsvip.Name, vsvip.GetCheckSum())
+	}
+	for _, pool := range children.pools {
+		addObj("Pool", pool.Name, pool.GetCheckSum())
+		if pool.PkiProfile != nil {
+			addObj("PKIProfile", pool.PkiProfile.Name, pool.PkiProfile.GetCheckSum())
+		}
+	}
+	for _, pg := range children.poolGroups {
+		addObj("PoolGroup", pg.Name, pg.GetCheckSum())
+	}
+	for _, httpPolicy := range children.httpPolicies {
+		addObj("HTTPPolicySet", httpPolicy.Name, httpPolicy.GetCheckSum())
+	}
+	for _, sslKeyCert := range children.sslKeyCerts {
+		addObj("SSLKeyAndCertificate", sslKeyCert.Name, sslKeyCert.GetCheckSum())
+	}
+	for _, l4Policy := range children.l4Policies {
+		addObj("L4PolicySet", l4Policy.Name, l4Policy.GetCheckSum())
+	}
+	for _, nsp := range children.nsps {
+		addObj("NetworkSecurityPolicy", nsp.Name, nsp.GetCheckSum())
+	}
+	for _, ds := range children.dataScripts {
+		addObj("VSDataScriptSet", ds.Name, ds.GetCheckSum())
+	}
+	for _, appProfile := range children.appProfiles {
+		if appProfile.PkiProfile != nil {
+			addObj("PKIProfile", appProfile.PkiProfile.Name, appProfile.PkiProfile.GetCheckSum())
+		}
+	}
+	return objs
+}
+
+// getCachedDriftObject returns the state of the object as last applied by AKO.
+func (rest *RestOperations) getCachedDriftObject(model string, key avicache.NamespaceName) (driftAviObject, bool) {
+	obj := driftAviObject{model: model, name: key.Name}
+	switch model {
+	case "VirtualService":
+		if vsCache, ok := rest.cache.VsCacheMeta.AviCacheGet(key); ok {
+			if vsCacheObj, ok := vsCache.(*avicache.AviVsCache); ok {
+				vsCacheObj.VSCacheLock.RLock()
+				defer vsCacheObj.VSCacheLock.RUnlock()
+				obj.cksum, obj.lastModified = vsCacheObj.CloudConfigCksum, vsCacheObj.LastModified
+				return obj, true
+			}
+		}
+	case "VsVip":
+		if vsvipCache, ok := rest.cache.VSVIPCache.AviCacheGet(key); ok {
+			if vsvipCacheObj, ok := vsvipCache.(*avicache.AviVSVIPCache); ok {
+				obj.cksum, obj.lastModified = vsvipCacheObj.CloudConfigCksum, vsvipCacheObj.LastModified
+				return obj, true
+			}
+		}
+	case "Pool":
+		if poolCache, ok := rest.cache.PoolCache.AviCacheGet(key); ok {
+			if poolCacheObj, ok := poolCache.(*avicache.AviPoolCache); ok {
+				obj.cksum, obj.lastModified = poolCacheObj.CloudConfigCksum, poolCacheObj.LastModified
+				return obj, true
+			}
+		}
+	case "PoolGroup":
+		if pgCache, ok := rest.cache.PgCache.AviCacheGet(key); ok {
+			if pgCacheObj, ok := pgCache.(*avicache.AviPGCache); ok {
+				obj.cksum, obj.lastModified = pgCacheObj.CloudConfigCksum, pgCacheObj.LastModified
+				return obj, true
+			}
+		}
+	case "HTTPPolicySet":
+		if httpCache, ok := rest.cache.HTTPPolicyCache.AviCacheGet(key); ok {
+			if httpCacheObj, ok := httpCache.(*avicache.AviHTTPPolicyCache); ok {
+				obj.cksum, obj.lastModified = httpCacheObj.CloudConfigCksum, httpCacheObj.LastModified
+				return obj, true
+			}
+		}
+	case "SSLKeyAndCertificate":
+		if sslCache, ok := rest.cache.SSLKeyCache.AviCacheGet(key); ok {
+			if sslCacheObj, ok := sslCache.(*avicache.AviSSLCache); ok {
+				obj.cksum, obj.lastModified = strconv.Itoa(int(sslCacheObj.CloudConfigCksum)), sslCacheObj.LastModified
+				return obj, true
+			}
+		}
+	case "PKIProfile":
+		if pkiCache, ok := rest.cache.PKIProfileCache.AviCacheGet(key); ok {
+			if pkiCacheObj, ok := pkiCache.(*avicache.AviPkiProfileCache); ok {
+				obj.cksum, obj.lastModified = strconv.Itoa(int(pkiCacheObj.CloudConfigCksum)), pkiCacheObj.LastModified
+				return obj, true
+			}
+		}
+	case "L4PolicySet":
+		if l4Cache, ok := rest.cache.L4PolicyCache.AviCacheGet(key); ok {
+			if l4CacheObj, ok := l4Cache.(*avicache.AviL4PolicyCache); ok {
+				obj.cksum, obj.lastModified = strconv.Itoa(int(l4CacheObj.CloudConfigCksum)), l4CacheObj.LastModified
+				return obj, true
+			}
+		}
+	case "NetworkSecurityPolicy":
+		if nspCache, ok := rest.cache.NSPCache.AviCacheGet(key); ok {
+			if nspCacheObj, ok := nspCache.(*avicache.AviNetworkSecurityPolicyCache); ok {
+				obj.cksum, obj.lastModified = strconv.Itoa(int(nspCacheObj.CloudConfigCksum)), nspCacheObj.LastModified
+				return obj, true
+			}
+		}
+	case "VSDataScriptSet":
+		if dsCache, ok := rest.cache.DSCache.AviCacheGet(key); ok {
+			if dsCacheObj, ok := dsCache.(*avicache.AviDSCache); ok {
+				obj.cksum, obj.lastModified = strconv.Itoa(int(dsCacheObj.CloudConfigCksum)), dsCacheObj.LastModified
+				return obj, true
+			}
+		}
+	}
+	return obj, false
+}
+
+// invalidateCachedDriftObject clears the cached checksum of the object, so that its checksum does not match
+// the checksum of its model and the object is updated in the next sync. If the object was deleted, the update
+// fails with a 404 error, which removes the object from the cache and lets the retry create it.
+func (rest *RestOperations) invalidateCachedDriftObject(model string, key avicache.NamespaceName) {
+	switch model {
+	case "VirtualService":
+		if vsCache, ok := rest.cache.VsCacheMeta.AviCacheGet(key); ok {
+			if vsCacheObj, ok := vsCache.(*avicache.AviVsCache); ok {
+				vsCacheObj.VSCacheLock.Lock()
+				vsCacheObj.CloudConfigCksum = ""
+				vsCacheObj.VSCacheLock.Unlock()
+			}
+		}
+	case "VsVip":
+		if vsvipCache, ok := rest.cache.VSVIPCache.AviCacheGet(key); ok {
+			if vsvipCacheObj, ok := vsvipCache.(*avicache.AviVSVIPCache); ok {
+				vsvipCacheCopy := *vsvipCacheObj
+				vsvipCacheCopy.CloudConfigCksum = ""
+				rest.cache.VSVIPCache.AviCacheAdd(key, &vsvipCacheCopy)
+			}
+		}
+	case "Pool":
+		if poolCache, ok := rest.cache.PoolCache.AviCacheGet(key); ok {
+			if poolCacheObj, ok := poolCache.(*avicache.AviPoolCache); ok {
+				poolCacheCopy := *poolCacheObj
+				poolCacheCopy.CloudConfigCksum = ""
+				rest.cache.PoolCache.AviCacheAdd(key, &poolCacheCopy)
+			}
+		}
+	case "PoolGroup":
+		if pgCache, ok := rest.cache.PgCache.AviCacheGet(key); ok {
+			if pgCacheObj, ok := pgCache.(*avicache.AviPGCache); ok {
+				pgCacheCopy := *pgCacheObj
+				pgCacheCopy.CloudConfigCksum = ""
+				rest.cache.PgCache.AviCacheAdd(key, &pgCacheCopy)
+			}
+		}
+	case "HTTPPolicySet":
+		if httpCache, ok := rest.cache.HTTPPolicyCache.AviCacheGet(key); ok {
+			if httpCacheObj, ok := httpCache.(*avicache.AviHTTPPolicyCache); ok {
+				httpCacheCopy := *httpCacheObj
+				httpCacheCopy.CloudConfigCksum = ""
+				rest.cache.HTTPPolicyCache.AviCacheAdd(key, &httpCacheCopy)
+			}
+		}
+	case "SSLKeyAndCertificate":
+		if sslCache, ok := rest.cache.SSLKeyCache.AviCacheGet(key); ok {
+			if sslCacheObj, ok := sslCache.(*avicache.AviSSLCache); ok {
+				sslCacheCopy := *sslCacheObj
+				sslCacheCopy.CloudConfigCksum = 0
+				rest.cache.SSLKeyCache.AviCacheAdd(key, &sslCacheCopy)
+			}
+		}
+	case "PKIProfile":
+		if pkiCache, ok := rest.cache.PKIProfileCache.AviCacheGet(key); ok {
+			if pkiCacheObj, ok := pkiCache.(*avicache.AviPkiProfileCache); ok {
+				pkiCacheCopy := *pkiCacheObj
+				pkiCacheCopy.CloudConfigCksum = 0
+				rest.cache.PKIProfileCache.AviCacheAdd(key, &pkiCacheCopy)
+			}
+		}
+	case "L4PolicySet":
+		if l4Cache, ok := rest.cache.L4PolicyCache.AviCacheGet(key); ok {
+			if l4CacheObj, ok := l4Cache.(*avicache.AviL4PolicyCache); ok {
+				l4CacheCopy := *l4CacheObj
+				l4CacheCopy.CloudConfigCksum = 0
+				rest.cache.L4PolicyCache.AviCacheAdd(key, &l4CacheCopy)
+			}
+		}
+	case "NetworkSecurityPolicy":
+		if nspCache, ok := rest.cache.NSPCache.AviCacheGet(key); ok {
+			if nspCacheObj, ok := nspCache.(*avicache.AviNetworkSecurityPolicyCache); ok {
+				nspCacheCopy := *nspCacheObj
+				nspCacheCopy.CloudConfigCksum = 0
+				rest.cache.NSPCache.AviCacheAdd(key, &nspCacheCopy)
+			}
+		}
+	case "VSDataScriptSet":
+		if dsCache, ok := rest.cache.DSCache.AviCacheGet(key); ok {
+			if dsCacheObj, ok := dsCache.(*avicache.AviDSCache); ok {
+				dsCacheCopy := *dsCacheObj
+				dsCacheCopy.CloudConfigCksum = 0
+				rest.cache.DSCache.AviCacheAdd(key, &dsCacheCopy)
+			}
+		}
+	}
+}
+
+// getLiveDriftObjects fetches the objects of the type created by AKO in the tenant from the Avi controller.
+// The virtualservice VIPs do not carry the created_by marker, so they are selected by the AKO name prefix.
+func getLiveDriftObjects(client *clients.AviClient, tenant, model string) (map[string]driftAviObject, error) {
+	objs := make(map[string]driftAviObject)
+	uri := "/api/" + strings.ToLower(model) + "/?include_name=true&page_size=100"
+	if model == "VsVip" {
+		uri += "&name.contains=" + lib.GetNamePrefix()
+	} else {
+		uri += "&created_by=" + lib.AKOUser
+	}
+	for uri != "" {
+		result, err := client.AviSession.GetCollectionRaw(uri, session.SetOptTenant(tenant))
+		lib.IncrementRestOpCouter(lib.HTTPMethodGet, uri)
+		if err != nil {
+			return nil, err
+		}
+		var elems []map[string]interface{}
+		if err := json.Unmarshal(result.Results, &elems); err != nil {
+			return nil, err
+		}
+		for _, elem := range elems {
+			name, _ := elem["name"].(string)
+			if name == "" {
+				continue
+			}
+			lastModified, _ := elem["_last_modified"].(string)
+			objs[name] = driftAviObject{model: model, name: name, lastModified: lastModified}
+		}
+		uri = ""
+		if nextURI := strings.Split(result.Next, "/api/"); len(nextURI) > 1 {
+			uri = "/api/" + nextURI[1]
+		}
+	}
+	return objs, nil
+}
+
+// isModifiedAfter returns true if the live object was modified after AKO last applied it. The timestamps are in
+// microseconds since the epoch, and an object synced after the live object was fetched is not considered drifted.
+func isModifiedAfter(liveLastModified, cachedLastModified string) bool {
+	if liveLastModified == "" || cachedLastModified == "" {
+		return false
+	}
+	live, liveErr := strconv.ParseInt(liveLastModified, 10, 64)
+	cached, cachedErr := strconv.ParseInt(cachedLastModified, 10, 64)
+	if liveErr != nil || cachedErr != nil {
+		return liveLastModified != cachedLastModified
+	}
+	return live > cached
+}
@@ -0,0 +1,209 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package rest
+
+import (
+	"errors"
+	"sync"
+
+	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/third_party/github.com/vmware/alb-sdk/go/clients"
+)
+
+// restOpDependencyLevel is the level of an object type in the dependency graph of a virtualservice.
+// An object only refers to objects of a lower level, so all the objects of a level can be created
+// in parallel once the objects of the lower levels are created. Deletes follow the reverse order.
+var restOpDependencyLevel = map[string]int{
+	"SSLKeyAndCertificate":  0,
+	"PKIprofile":            0,
+	"VsVip":                 0,
+	"NetworkSecurityPolicy": 0,
+	"TrafficCloneProfile":   0,
+	"Pool":                  1,
+	"ApplicationProfile":    1,
+	"PoolGroup":             2,
+	"HTTPPolicySet":         3,
+	"L4PolicySet":           3,
+	"VSDataScriptSet":       3,
+	"VirtualService":        4,
+}
+
+// restOpSerialModels are the object types whose objects can refer to each other, e.g. a key certificate
+// refers to its CA certificate. The rest operations of these types are kept in their original order.
+var restOpSerialModels = map[string]bool{
+	"SSLKeyAndCertificate": true,
+	"VirtualService":       true,
+}
+
+// errPendingRestOp marks the rest operations of a task which were not sent because of an error in a previous
+// rest operation of the task.
+var errPendingRestOp = errors.New("pending due to error in the same task")
+
+// restOpTask is a set of rest operations which are sent serially on one client.
+type restOpTask []*utils.RestOp
+
+// buildRestOpLevels groups the rest operations into levels of tasks. The levels are processed one after
+// the other, and the tasks of a level are independent of each other. nil is returned if the rest
+// operations have to be processed serially, i.e. if they are of an unknown type, mix creates and deletes,
+// or if no two of them can be processed in parallel.
+func buildRestOpLevels(rest_ops []*utils.RestOp) [][]restOpTask {
+	maxLevel := 0
+	for _, level := range restOpDependencyLevel {
+		if level > maxLevel {
+			maxLevel = level
+		}
+	}
+	isDelete := rest_ops[0].Method == utils.RestDelete
+	levels := make([][]restOpTask, maxLevel+1)
+	serialTaskIndex := make(map[string]int)
+	parallel := false
+	for _, op := range rest_ops {
+		level, ok := restOpDependencyLevel[op.Model]
+		if !ok || op.Version != "" {
+			return nil
+		}
+		switch op.Method {
+		case utils.RestPost, utils.RestPut:
+			if isDelete {
+				return nil
+			}
+		case utils.RestDelete:
+			if !isDelete {
+				return nil
+			}
+			level = maxLevel - level
+		default:
+			return nil
+		}
+		if restOpSerialModels[op.Model] {
+			if i, ok := serialTaskIndex[op.Model]; ok {
+				levels[level][i] = append(levels[level][i], op)
+				continue
+			}
+			serialTaskIndex[op.Model] = len(levels[level])
+		}
+		levels[level] = append(levels[level], restOpTask{op})
+		if len(levels[level]) > 1 {
+			parallel = true
+		}
+	}
+	if !parallel {
+		return nil
+	}
+	return levels
+}
+
+// aviRestOperateConcurrent processes the levels of rest operations one after the other. The tasks of a level
+// are spread over the client of the worker and at most concurrency-1 clients of the concurrent client pool
+// which are not in use. The clients of the other rest workers are never used, since an AviSession is not
+// safe for concurrent use. The errors are handled once all the tasks of a level are done, the same way as
+// if the rest operations were processed serially.
+func (l *leader) aviRestOperateConcurrent(c *clients.AviClient, levels [][]restOpTask, rest_ops []*utils.RestOp, concurrency int, key string) error {
+	for i, tasks := range levels {
+		if len(tasks) == 0 {
+			continue
+		}
+		workers := concurrency
+		if workers > len(tasks) {
+			workers = len(tasks)
+		}
+		aviClients := []*clients.AviClient{c}
+		if workers > 1 {
+			aviClients = append(aviClients, avicache.AcquireAVIConcurrentClients(workers-1)...)
+		}
+		utils.AviLog.Debugf("key: %s, msg: processing %d rest tasks of level %d with %d clients", key, len(tasks), i, len(aviClients))
+		taskChan := make(chan restOpTask, len(tasks))
+		for _, task := range tasks {
+			taskChan <- task
+		}
+		close(taskChan)
+		var wg sync.WaitGroup
+		for _, client := range aviClients {
+			wg.Add(1)
+			go func(client *clients.AviClient) {
+				defer wg.Done()
+				for task := range taskChan {
+					for j, op := range task {
+						executeRestOp(client, op, key)
+						if op.Err != nil {
+							// Let the remaining operations of the task wait for the error to be handled.
+							for _, pending := range task[j+1:] {
+								pending.Err = errPendingRestOp
+							}
+							break
+						}
+					}
+				}
+			}(client)
+		}
+		wg.Wait()
+		avicache.ReleaseAVIConcurrentClients(aviClients[1:])
+
+		if err := l.handleRestOpLevelErrors(c, levels, i, rest_ops, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handleRestOpLevelErrors handles the errors of the rest operations of a level. An ignorable error lets the
+// operations following it in the same task be sent serially, while any other error aborts all the pending
+// rest operations.
+func (l *leader) handleRestOpLevelErrors(c *clients.AviClient, levels [][]restOpTask, level int, rest_ops []*utils.RestOp, key string) error {
+	for _, task := range levels[level] {
+		for j := 0; j < len(task); j++ {
+			op := task[j]
+			if op.Err == nil {
+				utils.AviLog.Debugf("key: %s, msg: RestOp method %v path %v tenant %v response %v objName %v",
+					key, op.Method, op.Path, op.Tenant, utils.Stringify(op.Response), op.ObjName)
+				continue
+			}
+			if op.Err == errPendingRestOp {
+				op.Err = nil
+				executeRestOp(c, op, key)
+				if op.Err == nil {
+					continue
+				}
+			}
+			if isRestOpErrorIgnorable(op, rest_ops, key) {
+				continue
+			}
+			for _, pending := range task[j+1:] {
+				pending.Err = errors.New("Aborted due to prev error")
+			}
+			for _, task := range levels[level] {
+				for _, pending := range task {
+					if pending.Err == errPendingRestOp {
+						pending.Err = errors.New("Aborted due to prev error")
+					}
+				}
+			}
+			for _, tasks := range levels[level+1:] {
+				for _, task := range tasks {
+					for _, pending := range task {
+						pending.Err = errors.New("Aborted due to prev error")
+					}
+				}
+			}
+			if lib.IsRestTransactionEnabled() {
+				l.rollbackCreates(c, rest_ops, key)
+			}
+			return &utils.WebSyncError{Err: op.Err, Operation: string(op.Method)}
+		}
+	}
+	return nil
+}
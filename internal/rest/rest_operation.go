@@ -15,12 +15,16 @@
 package rest
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"path"
 	"strings"
 	"time"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/retry"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/tracing"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/third_party/github.com/vmware/alb-sdk/go/clients"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/third_party/github.com/vmware/alb-sdk/go/session"
@@ -196,7 +200,7 @@ func (v *L4PolicySetSchema) removePoolRef(op *utils.RestOp, objRef string) bool
 	}
 
 	for i, rule := range l4PolSet.L4ConnectionPolicy.Rules {
-		if strings.EqualFold(*rule.Action.SelectPool.PoolRef, objRef) {
+		if rule.Action.SelectPool.PoolRef != nil && strings.EqualFold(*rule.Action.SelectPool.PoolRef, objRef) {
 			l4PolSet.L4ConnectionPolicy.Rules = append(l4PolSet.L4ConnectionPolicy.Rules[:i], l4PolSet.L4ConnectionPolicy.Rules[i+1:]...)
 		}
 	}
@@ -223,17 +227,7 @@ func removeObjRefFromRestOps(restOps []*utils.RestOp, objName, objType string) b
 }
 
 func isErrorRetryable(statusCode int, errMsg string) bool {
-	// List of status codes for which we support retry
-	if (statusCode >= 500 && statusCode < 599) || statusCode == 404 || statusCode == 401 || statusCode == 408 || statusCode == 409 {
-		return true
-	}
-	if statusCode == 400 && strings.Contains(errMsg, lib.NoFreeIPError) {
-		return true
-	}
-	if statusCode == 403 && strings.Contains(errMsg, lib.ConfigDisallowedDuringUpgradeError) {
-		return true
-	}
-	return false
+	return retry.GetRetryPolicy().IsRetryable(statusCode, errMsg)
 }
 
 type AviRestClientPool struct {
@@ -244,6 +238,7 @@ type RestOperator interface {
 	AviRestOperate(c *clients.AviClient, rest_ops []*utils.RestOp, key string) error
 	isRetryRequired(key string, err error) bool
 	SyncObjectStatuses()
+	DetectDrift()
 	RestRespArrToObjByType(rest_op *utils.RestOp, obj_type string, key string) []map[string]interface{}
 }
 
@@ -257,6 +252,9 @@ type (
 )
 
 func NewRestOperator(restOp *RestOperations, overrideLeaderFlag ...bool) RestOperator {
+	if lib.IsDryRunEnabled() {
+		return &recorder{restOp: restOp}
+	}
 	if lib.AKOControlConfig().IsLeader() ||
 		len(overrideLeaderFlag) > 0 && overrideLeaderFlag[0] {
 		return &leader{restOp: restOp}
@@ -269,6 +267,31 @@ func (l *leader) isRetryRequired(key string, err error) bool {
 }
 
 func (l *leader) AviRestOperate(c *clients.AviClient, rest_ops []*utils.RestOp, key string) error {
+	if lib.IsRestTransactionEnabled() && isMacroBatchable(rest_ops) && lib.IsMacroAPIBatchSupported() {
+		err := l.aviRestOperateMacro(c, rest_ops, key)
+		aviErr, ok := err.(*utils.WebSyncError)
+		if err == nil || !ok || !isMacroFallbackRequired(aviErr.Err) {
+			return err
+		}
+		// The controller has rolled back the macro, the rest operations are retried one by one
+		// so that the objects which can not be created are handled individually. The creates and
+		// updates are not sent again if only the deletes failed, as they are already applied.
+		utils.AviLog.Infof("key: %s, msg: falling back to individual rest operations", key)
+		var pendingOps []*utils.RestOp
+		for _, op := range rest_ops {
+			if op.Err != nil {
+				op.Err = nil
+				op.Response = nil
+				pendingOps = append(pendingOps, op)
+			}
+		}
+		rest_ops = pendingOps
+	}
+	if concurrency := lib.GetRestConcurrency(); concurrency > 1 && len(rest_ops) > 1 && !lib.DisableSync {
+		if levels := buildRestOpLevels(rest_ops); levels != nil {
+			return l.aviRestOperateConcurrent(c, levels, rest_ops, concurrency, key)
+		}
+	}
 	for i, op := range rest_ops {
 		// This condition check is introduced to prevent any keys which is already present in the Graph
 		// Queue from doing any POST/PUT/PATCH/GET operations at the controller when the `deleteConfig` is set.
@@ -278,57 +301,25 @@ func (l *leader) AviRestOperate(c *clients.AviClient, rest_ops []*utils.RestOp,
 			utils.AviLog.Warnf("key: %s, msg: Sync is disabled, Only DELETE operation is allowed for models other than VRF model", key)
 			continue
 		}
-		lib.IncrementRestOpCouter(utils.Stringify(op.Method), op.ObjName)
 		SetTenant := session.SetTenant(op.Tenant)
 		SetTenant(c.AviSession)
 		if op.Version != "" {
 			SetVersion := session.SetVersion(op.Version)
 			SetVersion(c.AviSession)
 		}
-		switch op.Method {
-		case utils.RestPost:
-			op.Err = c.AviSession.Post(op.Path, op.Obj, &op.Response)
-		case utils.RestPut:
-			op.Err = c.AviSession.Put(op.Path, op.Obj, &op.Response)
-		case utils.RestGet:
-			op.Err = c.AviSession.Get(op.Path, &op.Response)
-		case utils.RestPatch:
-			op.Err = c.AviSession.Patch(op.Path, op.Obj, op.PatchOp,
-				&op.Response)
-		case utils.RestDelete:
-			op.Err = c.AviSession.Delete(op.Path)
-		default:
-			utils.AviLog.Errorf("Unknown RestOp %v", op.Method)
-			op.Err = fmt.Errorf("Unknown RestOp %v", op.Method)
-		}
+		executeRestOp(c, op, key)
 		if op.Err != nil {
-			utils.AviLog.Warnf("key: %s, msg: RestOp method %v path %v tenant %v Obj %s returned err %s with response %s",
-				key, op.Method, op.Path, op.Tenant, utils.Stringify(op.Obj), utils.Stringify(op.Err), utils.Stringify(op.Response))
-			// Wrap the error into a websync error.
-			err := &utils.WebSyncError{Err: op.Err, Operation: string(op.Method)}
-			aviErr, ok := op.Err.(session.AviError)
-			if !ok {
-				utils.AviLog.Warnf("key: %s, msg: Error in rest operation is not of type AviError, err: %v, %T", key, op.Err, op.Err)
-			} else if op.Model == "VsVip" && op.Method == utils.RestPut {
-				utils.AviLog.Debugf("key: %s, msg: Error in rest operation for VsVip Put request.", key)
-			} else if aviErr.HttpStatusCode == 404 && op.Method == utils.RestDelete {
-				utils.AviLog.Warnf("key: %s, msg: Error during rest operation: %v, object of type %s not found in the controller. Ignoring err: %v", key, op.Method, op.Model, op.Err)
+			if isRestOpErrorIgnorable(op, rest_ops, key) {
 				continue
-			} else if op.Model == "VrfContext" && aviErr.HttpStatusCode == 412 {
-				utils.AviLog.Debugf("key: %s, msg: Error in rest operation for VrfContext Put request.", key)
-			} else if !isErrorRetryable(aviErr.HttpStatusCode, *aviErr.Message) {
-				if op.Method != utils.RestPost {
-					continue
-				}
-				if removeObjRefFromRestOps(rest_ops, op.ObjName, op.Model) {
-					continue
-				}
 			}
-
 			for j := i + 1; j < len(rest_ops); j++ {
 				rest_ops[j].Err = errors.New("Aborted due to prev error")
 			}
-			return err
+			if lib.IsRestTransactionEnabled() {
+				l.rollbackCreates(c, rest_ops[:i], key)
+			}
+			// Wrap the error into a websync error.
+			return &utils.WebSyncError{Err: op.Err, Operation: string(op.Method)}
 		} else {
 			utils.AviLog.Debugf("key: %s, msg: RestOp method %v path %v tenant %v response %v objName %v",
 				key, op.Method, op.Path, op.Tenant, utils.Stringify(op.Response), op.ObjName)
@@ -337,6 +328,266 @@ func (l *leader) AviRestOperate(c *clients.AviClient, rest_ops []*utils.RestOp,
 	return nil
 }
 
+// executeRestOp sends a single rest operation to the controller and stores the response and error in the
+// rest operation. The tenant is passed along with the request, wherever the session allows it, so that
+// a client can be used without depending on the tenant set in its session.
+func executeRestOp(c *clients.AviClient, op *utils.RestOp, key string) {
+	lib.IncrementRestOpCouter(utils.Stringify(op.Method), op.ObjName)
+	defer lib.ObserveRestOpLatency(utils.Stringify(op.Method), op.Model, time.Now())
+	endSpan := tracing.StartRestOp(key, string(op.Method), op.Model, op.ObjName)
+	defer func() { endSpan(op.Err) }()
+	tenant := session.SetOptTenant(op.Tenant)
+	switch op.Method {
+	case utils.RestPost:
+		op.Err = c.AviSession.Post(op.Path, op.Obj, &op.Response, tenant)
+	case utils.RestPut:
+		op.Err = c.AviSession.Put(op.Path, op.Obj, &op.Response, tenant)
+	case utils.RestGet:
+		op.Err = c.AviSession.Get(op.Path, &op.Response, tenant)
+	case utils.RestPatch:
+		op.Err = c.AviSession.Patch(op.Path, op.Obj, op.PatchOp,
+			&op.Response, tenant)
+	case utils.RestDelete:
+		op.Err = c.AviSession.DeleteObject(op.Path, tenant)
+	default:
+		utils.AviLog.Errorf("Unknown RestOp %v", op.Method)
+		op.Err = fmt.Errorf("Unknown RestOp %v", op.Method)
+	}
+}
+
+// isRestOpErrorIgnorable returns true if the error of a rest operation does not have to abort the remaining
+// rest operations. References to an object which could not be created are removed from the rest operations.
+func isRestOpErrorIgnorable(op *utils.RestOp, rest_ops []*utils.RestOp, key string) bool {
+	utils.AviLog.Warnf("key: %s, msg: RestOp method %v path %v tenant %v Obj %s returned err %s with response %s",
+		key, op.Method, op.Path, op.Tenant, utils.Stringify(op.Obj), utils.Stringify(op.Err), utils.Stringify(op.Response))
+	aviErr, ok := op.Err.(session.AviError)
+	if !ok {
+		utils.AviLog.Warnf("key: %s, msg: Error in rest operation is not of type AviError, err: %v, %T", key, op.Err, op.Err)
+	} else if op.Model == "VsVip" && op.Method == utils.RestPut {
+		utils.AviLog.Debugf("key: %s, msg: Error in rest operation for VsVip Put request.", key)
+	} else if aviErr.HttpStatusCode == 404 && op.Method == utils.RestDelete {
+		utils.AviLog.Warnf("key: %s, msg: Error during rest operation: %v, object of type %s not found in the controller. Ignoring err: %v", key, op.Method, op.Model, op.Err)
+		return true
+	} else if op.Model == "VrfContext" && aviErr.HttpStatusCode == 412 {
+		utils.AviLog.Debugf("key: %s, msg: Error in rest operation for VrfContext Put request.", key)
+	} else if !isErrorRetryable(aviErr.HttpStatusCode, *aviErr.Message) {
+		if op.Method != utils.RestPost {
+			return true
+		}
+		if removeObjRefFromRestOps(rest_ops, op.ObjName, op.Model) {
+			return true
+		}
+	}
+	return false
+}
+
+// isMacroBatchable returns true if the rest operations can be sent in macro API calls, i.e. all of them create,
+// update or delete objects in the same tenant, and the deletes follow all the creates and updates.
+func isMacroBatchable(rest_ops []*utils.RestOp) bool {
+	if len(rest_ops) < 2 || lib.DisableSync {
+		return false
+	}
+	deletes := false
+	for _, op := range rest_ops {
+		if op.Tenant != rest_ops[0].Tenant ||
+			op.Version != rest_ops[0].Version {
+			return false
+		}
+		switch op.Method {
+		case utils.RestPost, utils.RestPut:
+			if deletes {
+				return false
+			}
+		case utils.RestDelete:
+			deletes = true
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// isMacroFallbackRequired returns true if the macro API call failed for a reason which is handled per object
+// when the rest operations are sent individually.
+func isMacroFallbackRequired(err error) bool {
+	aviErr, ok := err.(session.AviError)
+	if !ok || aviErr.Message == nil {
+		return false
+	}
+	return !isErrorRetryable(aviErr.HttpStatusCode, *aviErr.Message)
+}
+
+// aviRestOperateMacro sends the objects of all the rest operations in macro API calls. The objects which are
+// created or updated are sent in a single macro, which the controller applies as one transaction, so either all
+// of them are applied or none of them. The macro API does not accept deletes along with them, so the objects
+// which are deleted are sent in a second macro, once the objects referring to them are updated.
+func (l *leader) aviRestOperateMacro(c *clients.AviClient, rest_ops []*utils.RestOp, key string) error {
+	var upsertOps, deleteOps []*utils.RestOp
+	for _, op := range rest_ops {
+		if op.Method == utils.RestDelete {
+			deleteOps = append(deleteOps, op)
+		} else {
+			upsertOps = append(upsertOps, op)
+		}
+	}
+	SetTenant := session.SetTenant(rest_ops[0].Tenant)
+	SetTenant(c.AviSession)
+	if rest_ops[0].Version != "" {
+		SetVersion := session.SetVersion(rest_ops[0].Version)
+		SetVersion(c.AviSession)
+	}
+
+	if len(upsertOps) > 0 {
+		var response interface{}
+		if err := sendMacro(c, utils.RestPost, upsertOps, &response, key); err != nil {
+			for _, op := range rest_ops[1:] {
+				op.Err = errors.New("Aborted due to prev error")
+			}
+			rest_ops[0].Err = err
+			return &utils.WebSyncError{Err: err, Operation: string(utils.RestPost)}
+		}
+		responses, ok := response.([]interface{})
+		if !ok || len(responses) != len(upsertOps) {
+			err := fmt.Errorf("unexpected response for macro with %d objects: %s", len(upsertOps), utils.Stringify(response))
+			utils.AviLog.Warnf("key: %s, msg: %v", key, err)
+			for _, op := range rest_ops {
+				op.Err = err
+			}
+			return &utils.WebSyncError{Err: err, Operation: string(utils.RestPost)}
+		}
+		for i, op := range upsertOps {
+			op.Response = responses[i]
+			utils.AviLog.Debugf("key: %s, msg: RestOp method %v path %v tenant %v response %v objName %v",
+				key, op.Method, lib.MacroAPIPath, op.Tenant, utils.Stringify(op.Response), op.ObjName)
+		}
+	}
+
+	if len(deleteOps) > 0 {
+		if err := sendMacro(c, utils.RestDelete, deleteOps, nil, key); err != nil {
+			deleteOps[0].Err = err
+			for _, op := range deleteOps[1:] {
+				op.Err = errors.New("Aborted due to prev error")
+			}
+			return &utils.WebSyncError{Err: err, Operation: string(utils.RestDelete)}
+		}
+	}
+	return nil
+}
+
+// sendMacro sends the objects of the rest operations in a single macro API call with the given method.
+// The objects which are updated or deleted are identified by the uuid in the path of their rest operation.
+func sendMacro(c *clients.AviClient, method utils.RestMethod, rest_ops []*utils.RestOp, response interface{}, key string) error {
+	macro := make([]utils.AviRestObjMacro, 0, len(rest_ops))
+	for _, op := range rest_ops {
+		lib.IncrementRestOpCouter(utils.Stringify(op.Method), op.ObjName)
+		data, err := getMacroData(op)
+		if err != nil {
+			utils.AviLog.Warnf("key: %s, msg: unable to add %s %s to the macro, err: %v", key, op.Model, op.ObjName, err)
+			return err
+		}
+		macro = append(macro, utils.AviRestObjMacro{ModelName: op.Model, Data: data})
+	}
+
+	var err error
+	endSpan := tracing.StartRestOp(key, string(method), "Macro", fmt.Sprintf("%d objects", len(rest_ops)))
+	if method == utils.RestDelete {
+		err = c.AviSession.Delete(lib.MacroAPIPath, macro)
+	} else {
+		err = c.AviSession.Post(lib.MacroAPIPath, macro, response)
+	}
+	endSpan(err)
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: %s macro with %d objects in tenant %v returned err %s with response %s",
+			key, method, len(rest_ops), rest_ops[0].Tenant, utils.Stringify(err), utils.Stringify(response))
+	}
+	return err
+}
+
+// getMacroData returns the object of a rest operation as it is sent in a macro. The uuid of the object is added
+// to the objects which are updated, and only the uuid is sent for the objects which are deleted.
+func getMacroData(op *utils.RestOp) (interface{}, error) {
+	if op.Method == utils.RestPost {
+		return op.Obj, nil
+	}
+	uuid := path.Base(strings.Split(op.Path, "?")[0])
+	if op.Method == utils.RestDelete {
+		return map[string]interface{}{"uuid": uuid}, nil
+	}
+	objBytes, err := json.Marshal(op.Obj)
+	if err != nil {
+		return nil, err
+	}
+	data := make(map[string]interface{})
+	if err := json.Unmarshal(objBytes, &data); err != nil {
+		return nil, err
+	}
+	data["uuid"] = uuid
+	return data, nil
+}
+
+// rollbackCreates deletes the objects created by the rest operations in the reverse order of their creation,
+// so that a failed sync does not leave orphan objects on the controller. The objects updated before the failure
+// are not reverted, as the cache does not hold their previous configuration, so the created objects which they
+// refer to are left in place along with them. They are updated again when the key is retried.
+func (l *leader) rollbackCreates(c *clients.AviClient, rest_ops []*utils.RestOp, key string) {
+	for i := len(rest_ops) - 1; i >= 0; i-- {
+		op := rest_ops[i]
+		if op.Method != utils.RestPost || op.Err != nil {
+			continue
+		}
+		resp, ok := op.Response.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		uuid, ok := resp["uuid"].(string)
+		if !ok {
+			utils.AviLog.Warnf("key: %s, msg: uuid not present in response for %s %s, can not roll back", key, op.Model, op.ObjName)
+			continue
+		}
+		if isReferencedByUpdates(op, rest_ops) {
+			utils.AviLog.Infof("key: %s, msg: %s %s is referred to by an updated object, not rolling it back", key, op.Model, op.ObjName)
+			continue
+		}
+		SetTenant := session.SetTenant(op.Tenant)
+		SetTenant(c.AviSession)
+		path := strings.TrimSuffix(strings.Split(op.Path, "?")[0], "/") + "/" + uuid
+		if err := c.AviSession.Delete(path); err != nil {
+			if aviErr, ok := err.(session.AviError); !ok || aviErr.HttpStatusCode != 404 {
+				utils.AviLog.Warnf("key: %s, msg: failed to roll back %s %s, err: %v", key, op.Model, op.ObjName, err)
+				continue
+			}
+		}
+		utils.AviLog.Infof("key: %s, msg: rolled back %s %s", key, op.Model, op.ObjName)
+		op.Err = errors.New(lib.RolledBackError)
+		op.Response = nil
+	}
+}
+
+// isReferencedByUpdates returns true if an object created by a rest operation is referred to by an object
+// which was updated by one of the rest operations.
+func isReferencedByUpdates(createOp *utils.RestOp, rest_ops []*utils.RestOp) bool {
+	objType := strings.ToLower(createOp.Model)
+	refs := []string{
+		"/api/" + objType + "?name=" + createOp.ObjName + `"`,
+		"/api/" + objType + "/?name=" + createOp.ObjName + `"`,
+	}
+	for _, op := range rest_ops {
+		if op.Method != utils.RestPut || op.Err != nil {
+			continue
+		}
+		objBytes, err := json.Marshal(op.Obj)
+		if err != nil {
+			continue
+		}
+		for _, ref := range refs {
+			if strings.Contains(string(objBytes), ref) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (f *follower) isRetryRequired(key string, err error) bool {
 	if err == nil {
 		return false
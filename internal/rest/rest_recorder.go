@@ -0,0 +1,86 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package rest
+
+import (
+	"encoding/json"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api/models"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/third_party/github.com/vmware/alb-sdk/go/clients"
+)
+
+// recorder is the RestOperator used in dry run mode. It records the rest operations in the dry run plan
+// of the virtualservice instead of sending them to the Avi controller.
+type recorder struct {
+	restOp *RestOperations
+}
+
+func (r *recorder) AviRestOperate(c *clients.AviClient, rest_ops []*utils.RestOp, key string) error {
+	var planned []models.DryRunRestOp
+	for _, op := range rest_ops {
+		name := op.ObjName
+		if name == "" {
+			name = restOpObjName(op)
+		}
+		utils.AviLog.Infof("key: %s, msg: dry run, planned %s of %s %s in tenant %s", key, op.Method, op.Model, name, op.Tenant)
+		planned = append(planned, models.DryRunRestOp{
+			Method: string(op.Method),
+			Model:  op.Model,
+			Name:   name,
+			Tenant: op.Tenant,
+			Path:   op.Path,
+			Data:   op.Obj,
+		})
+	}
+	models.DryRunPlans.AddToPlan(key, planned)
+	return nil
+}
+
+func (r *recorder) isRetryRequired(key string, err error) bool {
+	return false
+}
+
+func (r *recorder) SyncObjectStatuses() {
+	utils.AviLog.Debugf("Dry run mode, skipping the sync of object statuses")
+}
+
+func (r *recorder) DetectDrift() {
+	utils.AviLog.Debugf("Dry run mode, skipping drift detection")
+}
+
+func (r *recorder) RestRespArrToObjByType(rest_op *utils.RestOp, obj_type string, key string) []map[string]interface{} {
+	return nil
+}
+
+// restOpObjName returns the name of the object of a POST or PUT rest operation.
+func restOpObjName(op *utils.RestOp) string {
+	data, err := json.Marshal(op.Obj)
+	if err != nil {
+		return ""
+	}
+	var obj struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return ""
+	}
+	return obj.Name
+}
+
+func (rest *RestOperations) isDryRun() bool {
+	_, ok := rest.restOperator.(*recorder)
+	return ok
+}
@@ -139,6 +139,17 @@ func (rest *RestOperations) AviSSLKeyCertAdd(rest_op *utils.RestOp, vsKey avicac
 			utils.AviLog.Warnf("Uuid not present in response %v", resp)
 			continue
 		}
+
+		var lastModifiedStr string
+		lastModifiedIntf, ok := resp["_last_modified"]
+		if !ok {
+			utils.AviLog.Warnf("key: %s, msg: last_modified not present in response %v", key, resp)
+		} else {
+			lastModifiedStr, ok = lastModifiedIntf.(string)
+			if !ok {
+				utils.AviLog.Warnf("key: %s, msg: last_modified is not of type string", key)
+			}
+		}
 		_, ok = resp["certificate"].(map[string]interface{})
 		if !ok {
 			utils.AviLog.Warnf("Certificate not present in response %v", resp)
@@ -169,6 +180,7 @@ func (rest *RestOperations) AviSSLKeyCertAdd(rest_op *utils.RestOp, vsKey avicac
 			Tenant:           rest_op.Tenant,
 			Uuid:             uuid,
 			CloudConfigCksum: lib.SSLKeyCertChecksum(name, cert, cacert, emptyIngestionMarkers, SSLKeyAndCertificate.Markers, true),
+			LastModified:     lastModifiedStr,
 			HasCARef:         hasCA,
 		}
 
@@ -303,6 +315,17 @@ func (rest *RestOperations) AviPkiProfileAdd(rest_op *utils.RestOp, poolKey avic
 			continue
 		}
 
+		var lastModifiedStr string
+		lastModifiedIntf, ok := resp["_last_modified"]
+		if !ok {
+			utils.AviLog.Warnf("key: %s, msg: last_modified not present in response %v", key, resp)
+		} else {
+			lastModifiedStr, ok = lastModifiedIntf.(string)
+			if !ok {
+				utils.AviLog.Warnf("key: %s, msg: last_modified is not of type string", key)
+			}
+		}
+
 		var pkiCertificate string
 		var pkiMarkers []*avimodels.RoleFilterMatchLabel
 		switch rest_op.Obj.(type) {
@@ -319,6 +342,7 @@ func (rest *RestOperations) AviPkiProfileAdd(rest_op *utils.RestOp, poolKey avic
 			Tenant:           rest_op.Tenant,
 			Uuid:             uuid,
 			CloudConfigCksum: lib.SSLKeyCertChecksum(name, pkiCertificate, "", emptyIngestionMarkers, pkiMarkers, true),
+			LastModified:     lastModifiedStr,
 		}
 
 		k := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: name}
@@ -14,14 +14,40 @@
 package retry
 
 import (
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api/models"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 )
 
+// retryTracker keeps the number of retry attempts of the keys since they were last synced. A key is marked
+// as failed when its sync publishes it to a retry queue, until the sync is done.
+type retryTracker struct {
+	lock     sync.Mutex
+	attempts map[string]int
+	failed   map[string]bool
+}
+
+var tracker = &retryTracker{
+	attempts: make(map[string]int),
+	failed:   make(map[string]bool),
+}
+
 func DequeueFastRetry(vsKey string) {
 	utils.AviLog.Infof("Retrieved the key for fast retry: %s", vsKey)
+	if isKeyDeadLettered(vsKey) {
+		utils.AviLog.Infof("key: %s, msg: retry attempts exhausted, not retrying", vsKey)
+		return
+	}
 	sharedQueue := utils.SharedWorkQueue().GetQueueByName(utils.GraphLayer)
 	modelName := lib.GetTenant() + "/" + vsKey
 	nodes.PublishKeyToRestLayer(modelName, "retry", sharedQueue)
@@ -30,8 +56,109 @@ func DequeueFastRetry(vsKey string) {
 
 func DequeueSlowRetry(vsKey string) {
 	utils.AviLog.Infof("Retrieved the key for slow retry: %s", vsKey)
+	if isKeyDeadLettered(vsKey) {
+		utils.AviLog.Infof("key: %s, msg: retry attempts exhausted, not retrying", vsKey)
+		return
+	}
 	sharedQueue := utils.SharedWorkQueue().GetQueueByName(utils.GraphLayer)
 	modelName := lib.GetTenant() + "/" + vsKey
 	nodes.PublishKeyToRestLayer(modelName, "retry", sharedQueue)
 
 }
+
+// PublishKeyToRetryQueue adds the key to a retry queue after the backoff of its next attempt. The key is moved to
+// the dead-letter set instead, once it exhausts the attempts allowed by the retry policy. The slow retry queue
+// waits for at least SLOW_SYNC_TIME before retrying a key.
+func PublishKeyToRetryQueue(queueName, vsKey, key string) bool {
+	policy := GetRetryPolicy()
+	tracker.lock.Lock()
+	tracker.failed[vsKey] = true
+	tracker.attempts[vsKey]++
+	attempt := tracker.attempts[vsKey]
+	if maxAttempts := policy.MaxAttempts(); maxAttempts > 0 && attempt > maxAttempts {
+		// Start afresh if the key fails again after it is updated.
+		delete(tracker.attempts, vsKey)
+		tracker.lock.Unlock()
+		moveKeyToDeadLetter(vsKey, key, maxAttempts)
+		return false
+	}
+	tracker.lock.Unlock()
+
+	delay := policy.Backoff(attempt)
+	if queueName == lib.SLOW_RETRY_LAYER && delay < lib.SLOW_SYNC_TIME*time.Second {
+		delay = lib.SLOW_SYNC_TIME * time.Second
+	}
+	retryQueue := utils.SharedWorkQueue().GetQueueByName(queueName)
+	retryQueue.Workqueue[0].AddAfter(vsKey, delay)
+	lib.IncrementQueueCounter(queueName)
+	lib.IncrementRetryCounter(queueName)
+	utils.AviLog.Infof("key: %s, msg: Published key with vs_key to %s: %s, attempt: %d, delay: %v", key, queueName, vsKey, attempt, delay)
+	return true
+}
+
+// KeySynced is called once the sync of a key is done. It resets the retry attempts of the key, and removes it
+// from the dead-letter set, unless the sync has failed and published the key to a retry queue.
+func KeySynced(vsKey string) {
+	tracker.lock.Lock()
+	if tracker.failed[vsKey] {
+		delete(tracker.failed, vsKey)
+		tracker.lock.Unlock()
+		return
+	}
+	delete(tracker.attempts, vsKey)
+	tracker.lock.Unlock()
+
+	if removed, count := models.RetryDeadLetters.Remove(vsKey); removed {
+		lib.SetDeadLetterKeysCounter(count)
+		utils.AviLog.Infof("key: %s, msg: removed from the retry dead-letter set", vsKey)
+	}
+}
+
+// isKeyDeadLettered returns true if the key was moved to the dead-letter set, and no retry attempt was made
+// for it since, i.e. it is a retry which was queued before the key exhausted its attempts.
+func isKeyDeadLettered(vsKey string) bool {
+	if _, ok := models.RetryDeadLetters.Get(vsKey); !ok {
+		return false
+	}
+	return GetRetryAttempts(vsKey) == 0
+}
+
+// GetRetryAttempts returns the number of retry attempts of the key since it was last synced.
+func GetRetryAttempts(vsKey string) int {
+	tracker.lock.Lock()
+	defer tracker.lock.Unlock()
+	return tracker.attempts[vsKey]
+}
+
+func moveKeyToDeadLetter(vsKey, key string, attempts int) {
+	count := models.RetryDeadLetters.Add(vsKey, attempts)
+	lib.SetDeadLetterKeysCounter(count)
+	utils.AviLog.Errorf("key: %s, msg: retry attempts exhausted for vs_key %s after %d attempts, moved to dead-letter set", key, vsKey, attempts)
+
+	message := fmt.Sprintf("Sync of VirtualService %s failed after %d retries, it will be retried when the object is updated", vsKey, attempts)
+	owners := getOwnerObjects(vsKey)
+	for _, owner := range owners {
+		lib.AKOControlConfig().EventRecorder().Eventf(owner, corev1.EventTypeWarning, lib.RetryExhausted, message)
+	}
+	if len(owners) == 0 {
+		lib.AKOControlConfig().PodEventf(corev1.EventTypeWarning, lib.RetryExhausted, message)
+	}
+}
+
+// getOwnerObjects returns the Services and Ingresses from which the virtualservice of the key is built.
+func getOwnerObjects(vsKey string) []runtime.Object {
+	found, aviModel := objects.SharedAviGraphLister().Get(lib.GetTenant() + "/" + vsKey)
+	if !found || aviModel == nil {
+		return nil
+	}
+	model, ok := aviModel.(*nodes.AviObjectGraph)
+	if !ok || model == nil {
+		return nil
+	}
+	if vsNodes := model.GetAviVS(); len(vsNodes) > 0 {
+		return nodes.GetServiceMetadataOwners(vsNodes[0].ServiceMetadata)
+	} else if evhNodes := model.GetAviEvhVS(); len(evhNodes) > 0 {
+		return nodes.GetServiceMetadataOwners(evhNodes[0].ServiceMetadata)
+	}
+	return nil
+}
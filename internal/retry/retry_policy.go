@@ -0,0 +1,111 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package retry
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+)
+
+// RetryPolicy decides which failures are retried, and how many times and how often a key is retried.
+type RetryPolicy interface {
+	// IsRetryable returns true if a rest operation which failed with the status code and error message
+	// can succeed when it is retried.
+	IsRetryable(statusCode int, errMsg string) bool
+	// Backoff returns the delay before the given attempt of a key, starting with 1.
+	Backoff(attempt int) time.Duration
+	// MaxAttempts returns the number of attempts after which a key is moved to the dead-letter set.
+	// 0 means that the key is retried until it is synced.
+	MaxAttempts() int
+}
+
+var retryPolicy RetryPolicy
+var retryPolicyLock sync.RWMutex
+
+// SetRetryPolicy replaces the retry policy used by the retry layers.
+func SetRetryPolicy(policy RetryPolicy) {
+	retryPolicyLock.Lock()
+	defer retryPolicyLock.Unlock()
+	retryPolicy = policy
+}
+
+// GetRetryPolicy returns the retry policy used by the retry layers. Unless it is replaced with SetRetryPolicy,
+// it is an exponential backoff policy configured through the AKO configmap.
+func GetRetryPolicy() RetryPolicy {
+	retryPolicyLock.RLock()
+	defer retryPolicyLock.RUnlock()
+	if retryPolicy == nil {
+		return NewExponentialBackoffPolicy()
+	}
+	return retryPolicy
+}
+
+// ExponentialBackoffPolicy doubles the delay for every attempt of a key, up to MaxDelay. A random jitter of
+// up to Jitter times the delay is added, so that the keys which failed together are not retried together.
+type ExponentialBackoffPolicy struct {
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	Jitter    float64
+	Attempts  int
+}
+
+func NewExponentialBackoffPolicy() *ExponentialBackoffPolicy {
+	return &ExponentialBackoffPolicy{
+		BaseDelay: lib.GetRetryBaseDelay(),
+		MaxDelay:  lib.GetRetryMaxDelay(),
+		Jitter:    lib.RETRY_JITTER_FACTOR,
+		Attempts:  lib.GetRetryMaxAttempts(),
+	}
+}
+
+func (p *ExponentialBackoffPolicy) IsRetryable(statusCode int, errMsg string) bool {
+	// List of status codes for which we support retry
+	if (statusCode >= 500 && statusCode < 599) || statusCode == 404 || statusCode == 401 || statusCode == 408 || statusCode == 409 {
+		return true
+	}
+	if statusCode == 400 && strings.Contains(errMsg, lib.NoFreeIPError) {
+		return true
+	}
+	if statusCode == 403 && strings.Contains(errMsg, lib.ConfigDisallowedDuringUpgradeError) {
+		return true
+	}
+	return false
+}
+
+func (p *ExponentialBackoffPolicy) Backoff(attempt int) time.Duration {
+	if p.BaseDelay <= 0 {
+		return 0
+	}
+	if attempt < 1 {
+		attempt = 1
+	}
+	delay := p.MaxDelay
+	if exp := math.Pow(2, float64(attempt-1)); exp < float64(p.MaxDelay/p.BaseDelay) {
+		delay = time.Duration(exp) * p.BaseDelay
+	}
+	if p.Jitter > 0 {
+		delay += time.Duration(rand.Float64() * p.Jitter * float64(delay))
+	}
+	return delay
+}
+
+func (p *ExponentialBackoffPolicy) MaxAttempts() int {
+	return p.Attempts
+}
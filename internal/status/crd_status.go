@@ -17,7 +17,9 @@ package status
 import (
 	"context"
 	"encoding/json"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	akov1alpha2 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha2"
@@ -26,6 +28,8 @@ import (
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -34,6 +38,199 @@ import (
 type UpdateCRDStatusOptions struct {
 	Status string
 	Error  string
+	// Reason is the reason of the Accepted condition of a rejected CRD, Invalid if not set.
+	Reason string
+}
+
+// crdStatusLock serializes the status updates of the AKO CRDs. The validations and the REST layer update
+// different parts of the same status, and each update patches the whole status of the latest object.
+var crdStatusLock sync.Mutex
+
+// setValidationConditions sets the Accepted and ResolvedRefs conditions for the result of the validations of a CRD.
+// The Programmed condition is only initialized here, if the CRD can be applied to Avi objects, since it is set by
+// the REST layer.
+func setValidationConditions(conditions *[]metav1.Condition, generation int64, updateStatus UpdateCRDStatusOptions, programmable bool) {
+	if updateStatus.Status == lib.StatusAccepted {
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:               lib.CRDConditionAccepted,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: generation,
+			Reason:             lib.CRDReasonAccepted,
+			Message:            "The configuration is valid",
+		})
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:               lib.CRDConditionResolvedRefs,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: generation,
+			Reason:             lib.CRDReasonResolvedRefs,
+			Message:            "All the references are resolved",
+		})
+		if programmable && meta.FindStatusCondition(*conditions, lib.CRDConditionProgrammed) == nil {
+			meta.SetStatusCondition(conditions, metav1.Condition{
+				Type:               lib.CRDConditionProgrammed,
+				Status:             metav1.ConditionFalse,
+				ObservedGeneration: generation,
+				Reason:             lib.CRDReasonPending,
+				Message:            "The configuration is not applied to any Avi object yet",
+			})
+		}
+		return
+	}
+
+	reason := updateStatus.Reason
+	if reason == "" {
+		reason = lib.CRDReasonInvalid
+	}
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               lib.CRDConditionAccepted,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            updateStatus.Error,
+	})
+	if reason == lib.CRDReasonRefNotFound {
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:               lib.CRDConditionResolvedRefs,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: generation,
+			Reason:             lib.CRDReasonRefNotFound,
+			Message:            updateStatus.Error,
+		})
+	}
+	if programmable && meta.FindStatusCondition(*conditions, lib.CRDConditionProgrammed) == nil {
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:               lib.CRDConditionProgrammed,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: generation,
+			Reason:             lib.CRDReasonInvalid,
+			Message:            "The configuration is rejected",
+		})
+	}
+}
+
+// setAppliedTo adds the Avi object to, or removes it from, the objects a rule is applied to, and sets the Programmed
+// condition of the rule. It returns false if the status is already up to date.
+func setAppliedTo(appliedTo *[]string, conditions *[]metav1.Condition, generation int64, aviObjName string, applied bool) bool {
+	found := utils.HasElem(*appliedTo, aviObjName)
+	programmed := meta.FindStatusCondition(*conditions, lib.CRDConditionProgrammed)
+	if found == applied && programmed != nil && programmed.ObservedGeneration == generation &&
+		(programmed.Status == metav1.ConditionTrue) == (len(*appliedTo) > 0) {
+		return false
+	}
+
+	if applied && !found {
+		*appliedTo = append(*appliedTo, aviObjName)
+		sort.Strings(*appliedTo)
+	} else if !applied && found {
+		var objs []string
+		for _, obj := range *appliedTo {
+			if obj != aviObjName {
+				objs = append(objs, obj)
+			}
+		}
+		*appliedTo = objs
+	}
+
+	if len(*appliedTo) > 0 {
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:               lib.CRDConditionProgrammed,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: generation,
+			Reason:             lib.CRDReasonProgrammed,
+			Message:            "The configuration is applied to the Avi objects in appliedTo",
+		})
+	} else {
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:               lib.CRDConditionProgrammed,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: generation,
+			Reason:             lib.CRDReasonNotApplied,
+			Message:            "The configuration is not applied to any Avi object",
+		})
+	}
+	return true
+}
+
+// UpdateRuleAppliedTo updates the Avi objects a HostRule, HTTPRule, SSORule or L4Rule is applied to, when the CRD
+// metadata of an Avi object is updated in the cache.
+func UpdateRuleAppliedTo(aviObjName string, oldMetadata, newMetadata lib.CRDMetadata) {
+	if oldMetadata.Status == lib.CRDActive &&
+		(oldMetadata.Type != newMetadata.Type || oldMetadata.Value != newMetadata.Value || newMetadata.Status != lib.CRDActive) {
+		updateRuleAppliedTo(aviObjName, oldMetadata, false)
+	}
+	if newMetadata.Status == lib.CRDActive {
+		updateRuleAppliedTo(aviObjName, newMetadata, true)
+	}
+}
+
+func updateRuleAppliedTo(aviObjName string, metadata lib.CRDMetadata, applied bool) {
+	// The value of the HTTPRule metadata is namespace/name/path, and namespace/name for the other rules.
+	namespaceName := strings.SplitN(metadata.Value, "/", 3)
+	if len(namespaceName) < 2 {
+		return
+	}
+	namespace, name := namespaceName[0], namespaceName[1]
+
+	var err error
+	switch metadata.Type {
+	case lib.HostRule:
+		hostrule, getErr := lib.AKOControlConfig().CRDInformers().HostRuleInformer.Lister().HostRules(namespace).Get(name)
+		if getErr != nil {
+			return
+		}
+		// The status in the informer cache is checked first, to not get the object from the API server
+		// on every update of the Avi object.
+		cachedStatus := hostrule.Status.DeepCopy()
+		if !setAppliedTo(&cachedStatus.AppliedTo, &cachedStatus.Conditions, hostrule.Generation, aviObjName, applied) {
+			return
+		}
+		err = patchHostRuleStatus(namespace, name, func(hr *akov1beta1.HostRule) bool {
+			return setAppliedTo(&hr.Status.AppliedTo, &hr.Status.Conditions, hr.Generation, aviObjName, applied)
+		})
+	case lib.HTTPRule:
+		httprule, getErr := lib.AKOControlConfig().CRDInformers().HTTPRuleInformer.Lister().HTTPRules(namespace).Get(name)
+		if getErr != nil {
+			return
+		}
+		cachedStatus := httprule.Status.DeepCopy()
+		if !setAppliedTo(&cachedStatus.AppliedTo, &cachedStatus.Conditions, httprule.Generation, aviObjName, applied) {
+			return
+		}
+		err = patchHTTPRuleStatus(namespace, name, func(rr *akov1beta1.HTTPRule) bool {
+			return setAppliedTo(&rr.Status.AppliedTo, &rr.Status.Conditions, rr.Generation, aviObjName, applied)
+		})
+	case lib.SSORule:
+		ssoRule, getErr := lib.AKOControlConfig().CRDInformers().SSORuleInformer.Lister().SSORules(namespace).Get(name)
+		if getErr != nil {
+			return
+		}
+		cachedStatus := ssoRule.Status.DeepCopy()
+		if !setAppliedTo(&cachedStatus.AppliedTo, &cachedStatus.Conditions, ssoRule.Generation, aviObjName, applied) {
+			return
+		}
+		err = patchSSORuleStatus(namespace, name, func(sr *akov1alpha2.SSORule) bool {
+			return setAppliedTo(&sr.Status.AppliedTo, &sr.Status.Conditions, sr.Generation, aviObjName, applied)
+		})
+	case lib.L4Rule:
+		l4Rule, getErr := lib.AKOControlConfig().CRDInformers().L4RuleInformer.Lister().L4Rules(namespace).Get(name)
+		if getErr != nil {
+			return
+		}
+		cachedStatus := l4Rule.Status.DeepCopy()
+		if !setAppliedTo(&cachedStatus.AppliedTo, &cachedStatus.Conditions, l4Rule.Generation, aviObjName, applied) {
+			return
+		}
+		err = patchL4RuleStatus(namespace, name, func(lr *akov1alpha2.L4Rule) bool {
+			return setAppliedTo(&lr.Status.AppliedTo, &lr.Status.Conditions, lr.Generation, aviObjName, applied)
+		})
+	default:
+		return
+	}
+	if err != nil {
+		utils.AviLog.Warnf("msg: there was an error in updating the objects %s %s/%s is applied to: %v", metadata.Type, namespace, name, err)
+		return
+	}
+	utils.AviLog.Infof("msg: Updated the objects %s %s/%s is applied to, %s applied: %t", metadata.Type, namespace, name, aviObjName, applied)
 }
 
 // UpdateHostRuleStatus HostRule status updates
@@ -47,27 +244,46 @@ func UpdateHostRuleStatus(key string, hr *akov1beta1.HostRule, updateStatus Upda
 		}
 	}
 
-	patchPayload, _ := json.Marshal(map[string]interface{}{
-		"status": akov1beta1.HostRuleStatus(updateStatus),
+	err := patchHostRuleStatus(hr.Namespace, hr.Name, func(obj *akov1beta1.HostRule) bool {
+		obj.Status.Status = updateStatus.Status
+		obj.Status.Error = updateStatus.Error
+		obj.Status.ObservedGeneration = hr.Generation
+		setValidationConditions(&obj.Status.Conditions, hr.Generation, updateStatus, true)
+		return true
 	})
-
-	_, err := lib.AKOControlConfig().V1beta1CRDClientset().AkoV1beta1().HostRules(hr.Namespace).Patch(context.TODO(), hr.Name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
 	if err != nil {
-		utils.AviLog.Errorf("key: %s, msg: there was an error in updating the hostrule status: %+v", key, err)
-		updatedHr, err := lib.AKOControlConfig().CRDInformers().HostRuleInformer.Lister().HostRules(hr.Namespace).Get(hr.Name)
-		if err != nil {
+		if k8serrors.IsNotFound(err) {
 			utils.AviLog.Warnf("key: %s, msg: hostrule not found %v", key, err)
-			if strings.Contains(err.Error(), utils.K8S_ETIMEDOUT) {
-				UpdateHostRuleStatus(key, updatedHr, updateStatus, retry+1)
-			}
 			return
 		}
-		UpdateHostRuleStatus(key, updatedHr, updateStatus, retry+1)
+		utils.AviLog.Errorf("key: %s, msg: %d there was an error in updating the hostrule status: %+v", key, retry, err)
+		UpdateHostRuleStatus(key, hr, updateStatus, retry+1)
+		return
 	}
 
 	utils.AviLog.Infof("key: %s, msg: Successfully updated the hostrule %s/%s status %+v", key, hr.Namespace, hr.Name, utils.Stringify(updateStatus))
 }
 
+// patchHostRuleStatus gets the latest hostrule, and patches its status if it is changed by updateFn.
+func patchHostRuleStatus(namespace, name string, updateFn func(*akov1beta1.HostRule) bool) error {
+	crdStatusLock.Lock()
+	defer crdStatusLock.Unlock()
+
+	client := lib.AKOControlConfig().V1beta1CRDClientset().AkoV1beta1().HostRules(namespace)
+	obj, err := client.Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if !updateFn(obj) {
+		return nil
+	}
+	patchPayload, _ := json.Marshal(map[string]interface{}{
+		"status": obj.Status,
+	})
+	_, err = client.Patch(context.TODO(), name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
+	return err
+}
+
 // HostRuleEventBroadcast is responsible from broadcasting HostRule specific events when the VS Cache is Added/Updated/Deleted.
 func HostRuleEventBroadcast(vsName string, vsCacheMetadataOld, vsMetadataNew lib.CRDMetadata) {
 	if vsCacheMetadataOld.Value != vsMetadataNew.Value {
@@ -117,27 +333,98 @@ func UpdateHTTPRuleStatus(key string, rr *akov1beta1.HTTPRule, updateStatus Upda
 		}
 	}
 
-	patchPayload, _ := json.Marshal(map[string]interface{}{
-		"status": akov1beta1.HTTPRuleStatus(updateStatus),
+	err := patchHTTPRuleStatus(rr.Namespace, rr.Name, func(obj *akov1beta1.HTTPRule) bool {
+		obj.Status.Status = updateStatus.Status
+		obj.Status.Error = updateStatus.Error
+		obj.Status.ObservedGeneration = rr.Generation
+		setValidationConditions(&obj.Status.Conditions, rr.Generation, updateStatus, true)
+		return true
 	})
-
-	_, err := lib.AKOControlConfig().V1beta1CRDClientset().AkoV1beta1().HTTPRules(rr.Namespace).Patch(context.TODO(), rr.Name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
 	if err != nil {
-		utils.AviLog.Errorf("key: %s, msg: %d there was an error in updating the httprule status: %+v", key, retry, err)
-		updatedRr, err := lib.AKOControlConfig().CRDInformers().HTTPRuleInformer.Lister().HTTPRules(rr.Namespace).Get(rr.Name)
-		if err != nil {
+		if k8serrors.IsNotFound(err) {
 			utils.AviLog.Warnf("key: %s, msg: httprule not found %v", key, err)
-			if strings.Contains(err.Error(), utils.K8S_ETIMEDOUT) {
-				UpdateHTTPRuleStatus(key, updatedRr, updateStatus, retry+1)
-			}
 			return
 		}
-		UpdateHTTPRuleStatus(key, updatedRr, updateStatus, retry+1)
+		utils.AviLog.Errorf("key: %s, msg: %d there was an error in updating the httprule status: %+v", key, retry, err)
+		UpdateHTTPRuleStatus(key, rr, updateStatus, retry+1)
+		return
 	}
 
 	utils.AviLog.Infof("key: %s, msg: Successfully updated the httprule %s/%s status %+v", key, rr.Namespace, rr.Name, utils.Stringify(updateStatus))
 }
 
+// UpdateHTTPRuleBackendsCondition sets the BackendsApplied condition of the httprule to False with the message
+// when backends in its paths are ignored, and back to True once the message is empty. It returns true if the
+// condition is changed.
+func UpdateHTTPRuleBackendsCondition(key string, rr *akov1beta1.HTTPRule, ignoredMessage string) bool {
+	// The status in the informer cache is checked first, to not get the object from the API server
+	// on every graph build of the host.
+	cachedStatus := rr.Status.DeepCopy()
+	if !setBackendsApplied(&cachedStatus.Conditions, rr.Generation, ignoredMessage) {
+		return false
+	}
+	err := patchHTTPRuleStatus(rr.Namespace, rr.Name, func(obj *akov1beta1.HTTPRule) bool {
+		return setBackendsApplied(&obj.Status.Conditions, obj.Generation, ignoredMessage)
+	})
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: there was an error in updating the backends condition of httprule %s/%s: %v", key, rr.Namespace, rr.Name, err)
+		return false
+	}
+	utils.AviLog.Infof("key: %s, msg: Updated the backends condition of httprule %s/%s, ignored backends: %s", key, rr.Namespace, rr.Name, ignoredMessage)
+	return true
+}
+
+// setBackendsApplied sets the BackendsApplied condition, and returns true if it is changed. The condition is
+// added only once backends are ignored, so that it is not set on every httprule.
+func setBackendsApplied(conditions *[]metav1.Condition, generation int64, ignoredMessage string) bool {
+	current := meta.FindStatusCondition(*conditions, lib.CRDConditionBackendsApplied)
+	if ignoredMessage == "" {
+		if current == nil || (current.Status == metav1.ConditionTrue && current.ObservedGeneration == generation) {
+			return false
+		}
+		meta.SetStatusCondition(conditions, metav1.Condition{
+			Type:               lib.CRDConditionBackendsApplied,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: generation,
+			Reason:             lib.CRDReasonBackendsApplied,
+			Message:            "All the backends are applied",
+		})
+		return true
+	}
+	if current != nil && current.Status == metav1.ConditionFalse && current.ObservedGeneration == generation &&
+		current.Message == ignoredMessage {
+		return false
+	}
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               lib.CRDConditionBackendsApplied,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: generation,
+		Reason:             lib.CRDReasonBackendsIgnored,
+		Message:            ignoredMessage,
+	})
+	return true
+}
+
+// patchHTTPRuleStatus gets the latest httprule, and patches its status if it is changed by updateFn.
+func patchHTTPRuleStatus(namespace, name string, updateFn func(*akov1beta1.HTTPRule) bool) error {
+	crdStatusLock.Lock()
+	defer crdStatusLock.Unlock()
+
+	client := lib.AKOControlConfig().V1beta1CRDClientset().AkoV1beta1().HTTPRules(namespace)
+	obj, err := client.Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if !updateFn(obj) {
+		return nil
+	}
+	patchPayload, _ := json.Marshal(map[string]interface{}{
+		"status": obj.Status,
+	})
+	_, err = client.Patch(context.TODO(), name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
+	return err
+}
+
 // HttpRuleEventBroadcast is responsible from broadcasting HttpRule specific events when the Pool Cache is Added/Updated/Deleted.
 func HttpRuleEventBroadcast(poolName string, poolCacheMetadataOld, vsMetadataNew lib.CRDMetadata) {
 	if poolCacheMetadataOld.Value != vsMetadataNew.Value {
@@ -178,7 +465,6 @@ func HttpRuleEventBroadcast(poolName string, poolCacheMetadataOld, vsMetadataNew
 
 // UpdateAviInfraSettingStatus AviInfraSetting status updates
 func UpdateAviInfraSettingStatus(key string, infraSetting *akov1beta1.AviInfraSetting, updateStatus UpdateCRDStatusOptions, retryNum ...int) {
-
 	retry := 0
 	if len(retryNum) > 0 {
 		retry = retryNum[0]
@@ -188,27 +474,46 @@ func UpdateAviInfraSettingStatus(key string, infraSetting *akov1beta1.AviInfraSe
 		}
 	}
 
-	patchPayload, _ := json.Marshal(map[string]interface{}{
-		"status": akov1beta1.AviInfraSettingStatus(updateStatus),
+	err := patchAviInfraSettingStatus(infraSetting.Name, func(obj *akov1beta1.AviInfraSetting) bool {
+		obj.Status.Status = updateStatus.Status
+		obj.Status.Error = updateStatus.Error
+		obj.Status.ObservedGeneration = infraSetting.Generation
+		setValidationConditions(&obj.Status.Conditions, infraSetting.Generation, updateStatus, false)
+		return true
 	})
-
-	_, err := lib.AKOControlConfig().V1beta1CRDClientset().AkoV1beta1().AviInfraSettings().Patch(context.TODO(), infraSetting.Name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
 	if err != nil {
-		utils.AviLog.Errorf("key: %s, msg: %d there was an error in updating the aviinfrasetting status: %+v", key, retry, err)
-		updatedInfraSetting, err := lib.AKOControlConfig().CRDInformers().AviInfraSettingInformer.Lister().Get(infraSetting.Name)
-		if err != nil {
+		if k8serrors.IsNotFound(err) {
 			utils.AviLog.Warnf("key: %s, msg: aviinfrasetting not found %v", key, err)
-			if strings.Contains(err.Error(), utils.K8S_ETIMEDOUT) {
-				UpdateAviInfraSettingStatus(key, updatedInfraSetting, updateStatus, retry+1)
-			}
 			return
 		}
-		UpdateAviInfraSettingStatus(key, updatedInfraSetting, updateStatus, retry+1)
+		utils.AviLog.Errorf("key: %s, msg: %d there was an error in updating the aviinfrasetting status: %+v", key, retry, err)
+		UpdateAviInfraSettingStatus(key, infraSetting, updateStatus, retry+1)
+		return
 	}
 
 	utils.AviLog.Infof("key: %s, msg: Successfully updated the aviinfrasetting %s status %+v", key, infraSetting.Name, utils.Stringify(updateStatus))
 }
 
+// patchAviInfraSettingStatus gets the latest aviinfrasetting, and patches its status if it is changed by updateFn.
+func patchAviInfraSettingStatus(name string, updateFn func(*akov1beta1.AviInfraSetting) bool) error {
+	crdStatusLock.Lock()
+	defer crdStatusLock.Unlock()
+
+	client := lib.AKOControlConfig().V1beta1CRDClientset().AkoV1beta1().AviInfraSettings()
+	obj, err := client.Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if !updateFn(obj) {
+		return nil
+	}
+	patchPayload, _ := json.Marshal(map[string]interface{}{
+		"status": obj.Status,
+	})
+	_, err = client.Patch(context.TODO(), name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
+	return err
+}
+
 // UpdateL4RuleStatus updates the L4Rule status
 func UpdateL4RuleStatus(key string, l4Rule *akov1alpha2.L4Rule, updateStatus UpdateCRDStatusOptions, retryNum ...int) {
 	retry := 0
@@ -220,25 +525,44 @@ func UpdateL4RuleStatus(key string, l4Rule *akov1alpha2.L4Rule, updateStatus Upd
 		}
 	}
 
-	patchPayload, _ := json.Marshal(map[string]interface{}{
-		"status": akov1alpha2.L4RuleStatus(updateStatus),
+	err := patchL4RuleStatus(l4Rule.Namespace, l4Rule.Name, func(obj *akov1alpha2.L4Rule) bool {
+		obj.Status.Status = updateStatus.Status
+		obj.Status.Error = updateStatus.Error
+		obj.Status.ObservedGeneration = l4Rule.Generation
+		setValidationConditions(&obj.Status.Conditions, l4Rule.Generation, updateStatus, true)
+		return true
 	})
-
-	_, err := lib.AKOControlConfig().V1alpha2CRDClientset().AkoV1alpha2().L4Rules(l4Rule.Namespace).Patch(context.TODO(), l4Rule.Name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
 	if err != nil {
-		utils.AviLog.Errorf("key: %s, msg: %d there was an error in updating the L4Rule status: %+v", key, retry, err)
-		updatedL4RuleObj, err := lib.AKOControlConfig().V1alpha2CRDClientset().AkoV1alpha2().L4Rules(l4Rule.Namespace).Get(context.TODO(), l4Rule.Name, metav1.GetOptions{})
-		if err != nil {
+		if k8serrors.IsNotFound(err) {
 			utils.AviLog.Warnf("key: %s, msg: L4Rule not found %v", key, err)
-			if strings.Contains(err.Error(), utils.K8S_ETIMEDOUT) {
-				UpdateL4RuleStatus(key, updatedL4RuleObj, updateStatus, retry+1)
-			}
 			return
 		}
-		UpdateL4RuleStatus(key, updatedL4RuleObj, updateStatus, retry+1)
+		utils.AviLog.Errorf("key: %s, msg: %d there was an error in updating the L4Rule status: %+v", key, retry, err)
+		UpdateL4RuleStatus(key, l4Rule, updateStatus, retry+1)
+		return
 	}
 
-	utils.AviLog.Infof("key: %s, msg: Successfully updated the L4Rule %s status %+v", key, l4Rule.Name, utils.Stringify(updateStatus))
+	utils.AviLog.Infof("key: %s, msg: Successfully updated the L4Rule %s/%s status %+v", key, l4Rule.Namespace, l4Rule.Name, utils.Stringify(updateStatus))
+}
+
+// patchL4RuleStatus gets the latest L4Rule, and patches its status if it is changed by updateFn.
+func patchL4RuleStatus(namespace, name string, updateFn func(*akov1alpha2.L4Rule) bool) error {
+	crdStatusLock.Lock()
+	defer crdStatusLock.Unlock()
+
+	client := lib.AKOControlConfig().V1alpha2CRDClientset().AkoV1alpha2().L4Rules(namespace)
+	obj, err := client.Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if !updateFn(obj) {
+		return nil
+	}
+	patchPayload, _ := json.Marshal(map[string]interface{}{
+		"status": obj.Status,
+	})
+	_, err = client.Patch(context.TODO(), name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
+	return err
 }
 
 // L4RuleEventBroadcast is responsible from broadcasting L4Rule specific events when the VS Cache is Added/Updated/Deleted.
@@ -291,27 +615,46 @@ func UpdateSSORuleStatus(key string, sr *akov1alpha2.SSORule, updateStatus Updat
 		}
 	}
 
-	patchPayload, _ := json.Marshal(map[string]interface{}{
-		"status": akov1alpha2.SSORuleStatus(updateStatus),
+	err := patchSSORuleStatus(sr.Namespace, sr.Name, func(obj *akov1alpha2.SSORule) bool {
+		obj.Status.Status = updateStatus.Status
+		obj.Status.Error = updateStatus.Error
+		obj.Status.ObservedGeneration = sr.Generation
+		setValidationConditions(&obj.Status.Conditions, sr.Generation, updateStatus, true)
+		return true
 	})
-
-	_, err := lib.AKOControlConfig().V1alpha2CRDClientset().AkoV1alpha2().SSORules(sr.Namespace).Patch(context.TODO(), sr.Name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
 	if err != nil {
-		utils.AviLog.Errorf("key: %s, msg: there was an error in updating the SSORule status: %+v", key, err)
-		updatedSr, err := lib.AKOControlConfig().CRDInformers().SSORuleInformer.Lister().SSORules(sr.Namespace).Get(sr.Name)
-		if err != nil {
+		if k8serrors.IsNotFound(err) {
 			utils.AviLog.Warnf("key: %s, msg: SSORule not found %v", key, err)
-			if strings.Contains(err.Error(), utils.K8S_ETIMEDOUT) {
-				UpdateSSORuleStatus(key, updatedSr, updateStatus, retry+1)
-			}
 			return
 		}
-		UpdateSSORuleStatus(key, updatedSr, updateStatus, retry+1)
+		utils.AviLog.Errorf("key: %s, msg: %d there was an error in updating the SSORule status: %+v", key, retry, err)
+		UpdateSSORuleStatus(key, sr, updateStatus, retry+1)
+		return
 	}
 
 	utils.AviLog.Infof("key: %s, msg: Successfully updated the SSORule %s/%s status %+v", key, sr.Namespace, sr.Name, utils.Stringify(updateStatus))
 }
 
+// patchSSORuleStatus gets the latest SSORule, and patches its status if it is changed by updateFn.
+func patchSSORuleStatus(namespace, name string, updateFn func(*akov1alpha2.SSORule) bool) error {
+	crdStatusLock.Lock()
+	defer crdStatusLock.Unlock()
+
+	client := lib.AKOControlConfig().V1alpha2CRDClientset().AkoV1alpha2().SSORules(namespace)
+	obj, err := client.Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if !updateFn(obj) {
+		return nil
+	}
+	patchPayload, _ := json.Marshal(map[string]interface{}{
+		"status": obj.Status,
+	})
+	_, err = client.Patch(context.TODO(), name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
+	return err
+}
+
 // SSORuleEventBroadcast is responsible for broadcasting SSORule specific events when the VS Cache is Added/Updated/Deleted.
 func SSORuleEventBroadcast(vsName string, vsCacheMetadataOld, vsMetadataNew lib.CRDMetadata) {
 	if vsCacheMetadataOld.Value != vsMetadataNew.Value {
@@ -16,6 +16,7 @@ package status
 
 import (
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/tracing"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 )
 
@@ -34,6 +35,7 @@ func PublishToStatusQueue(key string, statusOption StatusOptions) {
 	bkt := utils.Bkt(key, statusQueue.NumWorkers)
 	lib.IncrementQueueCounter(utils.StatusQueue)
 	statusQueue.Workqueue[bkt].AddRateLimited(statusOption)
+	tracing.StatusQueued(statusOption.Key)
 }
 
 func (l *leader) DequeueStatus(objIntf interface{}) error {
@@ -44,6 +46,7 @@ func (l *leader) DequeueStatus(objIntf interface{}) error {
 		return nil
 	}
 	utils.AviLog.Infof("key: %s, msg: start status layer sync.", obj.Key)
+	defer tracing.StartStatus(obj.Key)()
 	switch obj.ObjType {
 	case utils.L4LBService:
 		if obj.Op == lib.UpdateStatus {
@@ -163,6 +163,14 @@ func (l *leader) DeleteL4LBStatus(svc_mdata_obj lib.ServiceMetadataObj, vsName,
 			"status": nil,
 		})
 
+		// The status of a service handled by another LB provider is owned by that provider.
+		if serviceObj := serviceMap[service]; serviceObj != nil && serviceObj.Spec.Type == corev1.ServiceTypeLoadBalancer && !lib.IsValidLoadBalancerClass(serviceObj) {
+			if err := deleteSvcAnnotation(serviceObj); err != nil {
+				utils.AviLog.Errorf("key: %s, msg: error in deleting service annotation: %v", key, err)
+			}
+			continue
+		}
+
 		if serviceObj := serviceMap[service]; serviceObj != nil && (serviceObj.Status.LoadBalancer.Ingress == nil ||
 			(serviceObj.Status.LoadBalancer.Ingress != nil && len(serviceObj.Status.LoadBalancer.Ingress) == 0)) {
 			continue
@@ -230,7 +238,7 @@ func getServices(serviceNSNames []string, bulk bool, retryNum ...int) map[string
 		for i := range serviceLBList {
 			svc := serviceLBList[i].DeepCopy()
 			if !lib.UseServicesAPI() {
-				if svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
+				if svc.Spec.Type == corev1.ServiceTypeLoadBalancer && lib.IsValidLoadBalancerClass(svc) {
 					//Do not perform status update on service if namespace is not accepted.
 					if utils.CheckIfNamespaceAccepted(svc.Namespace) {
 						serviceMap[svc.Namespace+"/"+svc.Name] = svc
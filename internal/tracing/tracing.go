@@ -0,0 +1,338 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+// Package tracing traces the sync of a kubernetes object, from its informer event to the completion of the Avi
+// rest operations and the status update of the object. The keys passed between the layers are plain strings,
+// so the spans are tracked against the ingestion layer key of the object, and the model names it is synced to.
+package tracing
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "ako"
+
+// objectSync is the sync of the changes of a kubernetes object, which is the root span of a trace.
+type objectSync struct {
+	key   string
+	ctx   context.Context
+	span  trace.Span
+	start time.Time
+	// models holds the number of models the object was published to, which are not yet synced.
+	models int
+}
+
+// modelSync tracks the objects whose changes are queued for, and being synced to, a model.
+type modelSync struct {
+	queued  []*objectSync
+	syncing []*objectSync
+	ctx     context.Context
+	span    trace.Span
+	// statuses holds the number of status updates published by the rest layer sync, which are not yet done.
+	statuses int
+	restDone bool
+}
+
+var (
+	tracer    trace.Tracer = trace.NewNoopTracerProvider().Tracer(tracerName)
+	provider  *sdktrace.TracerProvider
+	enabled   bool
+	traceLock sync.Mutex
+	// objectSyncs holds the objects queued in the ingestion layer, and ingesting the objects being processed by it.
+	objectSyncs = make(map[string]*objectSync)
+	ingesting   = make(map[string]*objectSync)
+	modelSyncs  = make(map[string]*modelSync)
+)
+
+// Init sets up the export of the spans over OTLP/HTTP to the collector at endpoint. The syncs are tracked
+// for the sync duration metric even when the endpoint is not set, if prometheus is enabled.
+func Init(endpoint string) error {
+	if endpoint == "" {
+		setTracer(nil, lib.IsPrometheusEnabled())
+		return nil
+	}
+	exporter, err := otlptracehttp.New(context.Background(),
+		otlptracehttp.WithEndpoint(endpoint),
+		otlptracehttp.WithInsecure(),
+	)
+	if err != nil {
+		return err
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(tracerName))),
+	)
+	setTracer(tp, true)
+	utils.AviLog.Infof("Exporting the sync traces to the OTLP collector at %s", endpoint)
+	return nil
+}
+
+// SetTracerProvider exports the spans with the given provider, instead of OTLP. A nil provider disables tracing.
+func SetTracerProvider(tp *sdktrace.TracerProvider) {
+	setTracer(tp, tp != nil)
+}
+
+func setTracer(tp *sdktrace.TracerProvider, enable bool) {
+	traceLock.Lock()
+	defer traceLock.Unlock()
+	provider = tp
+	if tp != nil {
+		tracer = tp.Tracer(tracerName)
+	} else {
+		tracer = trace.NewNoopTracerProvider().Tracer(tracerName)
+	}
+	enabled = enable
+	objectSyncs = make(map[string]*objectSync)
+	ingesting = make(map[string]*objectSync)
+	modelSyncs = make(map[string]*modelSync)
+}
+
+// Shutdown flushes the spans which are not yet exported.
+func Shutdown() {
+	traceLock.Lock()
+	tp := provider
+	traceLock.Unlock()
+	if tp == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := tp.Shutdown(ctx); err != nil {
+		utils.AviLog.Warnf("Error in exporting the remaining sync traces: %v", err)
+	}
+}
+
+func objectType(key string) string {
+	return strings.SplitN(key, "/", 2)[0]
+}
+
+// objectNamespace returns the namespace of a Type/namespace/name key, and an empty string for the
+// Type/name keys of cluster scoped objects.
+func objectNamespace(key string) string {
+	parts := strings.SplitN(key, "/", 3)
+	if len(parts) < 3 {
+		return ""
+	}
+	return parts[1]
+}
+
+func startObjectSync(key string) *objectSync {
+	ctx, span := tracer.Start(context.Background(), "sync "+objectType(key),
+		trace.WithAttributes(attribute.String("ako.key", key)))
+	return &objectSync{key: key, ctx: ctx, span: span, start: time.Now()}
+}
+
+func finishObjectSync(obj *objectSync) {
+	obj.span.End()
+	// The key itself is only recorded on the span, as the histogram would get a series per object otherwise.
+	lib.ObserveSyncDuration(objectType(obj.key), objectNamespace(obj.key), time.Since(obj.start))
+}
+
+// ObjectQueued starts the sync of an object, when its informer event is added to the ingestion layer queue.
+func ObjectQueued(key string) {
+	traceLock.Lock()
+	defer traceLock.Unlock()
+	if !enabled {
+		return
+	}
+	if obj, ok := objectSyncs[key]; ok {
+		obj.span.AddEvent("queued again")
+		return
+	}
+	objectSyncs[key] = startObjectSync(key)
+}
+
+// StartIngestion starts the ingestion layer span of an object. The returned function ends it.
+func StartIngestion(key string) func() {
+	traceLock.Lock()
+	defer traceLock.Unlock()
+	if !enabled {
+		return func() {}
+	}
+	obj, ok := objectSyncs[key]
+	if !ok {
+		// The object was queued by a controller which does not start its sync.
+		obj = startObjectSync(key)
+	}
+	// The events received from now on start a new sync of the object.
+	delete(objectSyncs, key)
+	ingesting[key] = obj
+	_, span := tracer.Start(obj.ctx, "ingestion")
+	return func() {
+		traceLock.Lock()
+		defer traceLock.Unlock()
+		span.End()
+		if ingesting[key] == obj {
+			delete(ingesting, key)
+		}
+		if obj.models == 0 {
+			// The object did not change any model, there is nothing more to sync.
+			finishObjectSync(obj)
+		}
+	}
+}
+
+// ModelQueued links the sync of an object to a model, when the ingestion layer publishes the model to the rest layer.
+func ModelQueued(modelName, key string) {
+	traceLock.Lock()
+	defer traceLock.Unlock()
+	obj, ok := ingesting[key]
+	if !enabled || !ok {
+		return
+	}
+	model, ok := modelSyncs[modelName]
+	if !ok {
+		model = &modelSync{}
+		modelSyncs[modelName] = model
+	}
+	for _, queued := range model.queued {
+		if queued == obj {
+			return
+		}
+	}
+	model.queued = append(model.queued, obj)
+	obj.models++
+	obj.span.AddEvent("model queued", trace.WithAttributes(attribute.String("ako.model", modelName)))
+}
+
+// StartRest starts the rest layer span of a model, under the sync of the first object queued for it, and
+// linked to the syncs of the others. The returned function ends it.
+func StartRest(modelName string) func() {
+	traceLock.Lock()
+	defer traceLock.Unlock()
+	model, ok := modelSyncs[modelName]
+	if !enabled || !ok || len(model.queued) == 0 {
+		return func() {}
+	}
+	if len(model.syncing) > 0 {
+		// The status updates of the previous sync are not waited for any longer.
+		finishModelSync(modelName, model)
+	}
+	model.syncing, model.queued = model.queued, nil
+	var links []trace.Link
+	for _, obj := range model.syncing[1:] {
+		links = append(links, trace.LinkFromContext(obj.ctx))
+	}
+	model.ctx, model.span = tracer.Start(model.syncing[0].ctx, "rest",
+		trace.WithAttributes(attribute.String("ako.model", modelName)),
+		trace.WithLinks(links...))
+	model.statuses = 0
+	model.restDone = false
+	span := model.span
+	return func() {
+		traceLock.Lock()
+		defer traceLock.Unlock()
+		span.End()
+		if model.span != span {
+			return
+		}
+		model.restDone = true
+		if model.statuses == 0 {
+			finishModelSync(modelName, model)
+		}
+	}
+}
+
+// StartRestOp starts the span of a rest operation of a model. The returned function ends it with the
+// error of the rest operation.
+func StartRestOp(modelName, method, objType, objName string) func(err error) {
+	traceLock.Lock()
+	model, ok := modelSyncs[modelName]
+	if !enabled || !ok || model.span == nil || model.restDone {
+		traceLock.Unlock()
+		return func(error) {}
+	}
+	ctx := model.ctx
+	traceLock.Unlock()
+
+	_, span := tracer.Start(ctx, method+" "+objType,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("avi.method", method),
+			attribute.String("avi.model", objType),
+			attribute.String("avi.name", objName),
+		))
+	return func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
+
+// StatusQueued holds the sync of a model open until the status update published by its rest layer sync is done.
+func StatusQueued(modelName string) {
+	traceLock.Lock()
+	defer traceLock.Unlock()
+	model, ok := modelSyncs[modelName]
+	if !enabled || !ok || model.span == nil || model.restDone {
+		return
+	}
+	model.statuses++
+}
+
+// StartStatus starts the status layer span of a model. The returned function ends it, along with the sync
+// of the objects synced to the model, once all its status updates are done.
+func StartStatus(modelName string) func() {
+	traceLock.Lock()
+	defer traceLock.Unlock()
+	model, ok := modelSyncs[modelName]
+	if !enabled || !ok || model.statuses == 0 || len(model.syncing) == 0 {
+		return func() {}
+	}
+	_, span := tracer.Start(model.syncing[0].ctx, "status", trace.WithAttributes(attribute.String("ako.model", modelName)))
+	syncing := model.syncing
+	return func() {
+		traceLock.Lock()
+		defer traceLock.Unlock()
+		span.End()
+		if len(model.syncing) == 0 || model.syncing[0] != syncing[0] || model.statuses == 0 {
+			return
+		}
+		model.statuses--
+		if model.statuses == 0 && model.restDone {
+			finishModelSync(modelName, model)
+		}
+	}
+}
+
+func finishModelSync(modelName string, model *modelSync) {
+	for _, obj := range model.syncing {
+		obj.models--
+		if obj.models == 0 && ingesting[obj.key] != obj {
+			finishObjectSync(obj)
+		}
+	}
+	model.syncing = nil
+	model.span = nil
+	model.statuses = 0
+	if len(model.queued) == 0 {
+		delete(modelSyncs, modelName)
+	}
+}
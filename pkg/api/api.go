@@ -31,6 +31,9 @@ type ApiServer struct {
 	http.Server
 	Port   string
 	Models []models.ApiModel
+	// CertFile and KeyFile are set if the server is served over TLS.
+	CertFile string
+	KeyFile  string
 }
 
 type ApiServerInterface interface {
@@ -78,6 +81,8 @@ func (a *ApiServer) initModels() {
 	// add common models in ApiServer
 	genericModels := []models.ApiModel{
 		models.RestStatus,
+		models.RetryDeadLetters,
+		models.DryRunPlans,
 	}
 	a.Models = append(a.Models, genericModels...)
 
@@ -106,10 +111,37 @@ func NewServer(port string, models []models.ApiModel, prometheusEnavbled bool, r
 	return s
 }
 
+// NewWebhookServer returns a server for the admission webhooks, served over TLS with the certificate and key
+// in the given files. Unlike NewServer, it does not serve the common models.
+func NewWebhookServer(port, certFile, keyFile string, models []models.ApiModel) *ApiServer {
+
+	s := &ApiServer{
+		Server: http.Server{
+			Addr:         ":" + port,
+			ReadTimeout:  10 * time.Second,
+			WriteTimeout: 10 * time.Second,
+		},
+		CertFile: certFile,
+		KeyFile:  keyFile,
+	}
+	s.Models = models
+	for _, model := range s.Models {
+		model.InitModel()
+	}
+	s.Handler = s.SetRouter(false, nil)
+
+	return s
+}
+
 func (a *ApiServer) InitApi() {
 	go func() {
 		utils.AviLog.Infof("Starting API server at %s", a.Server.Addr)
-		err := a.ListenAndServe()
+		var err error
+		if a.CertFile != "" {
+			err = a.ListenAndServeTLS(a.CertFile, a.KeyFile)
+		} else {
+			err = a.ListenAndServe()
+		}
 		if err != nil {
 			utils.AviLog.Infof("API server shutdown: %v", err)
 		}
@@ -32,6 +32,8 @@ func (a *FakeApiServer) initModels() {
 	// add common models in ApiServer
 	genericModels := []models.ApiModel{
 		models.RestStatus,
+		models.RetryDeadLetters,
+		models.DryRunPlans,
 	}
 	a.Models = append(a.Models, genericModels...)
 
@@ -0,0 +1,223 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DryRunRestOp is a rest operation which AKO would have sent to the Avi controller, if it was not in dry run mode.
+type DryRunRestOp struct {
+	Method string      `json:"method"`
+	Model  string      `json:"model"`
+	Name   string      `json:"name"`
+	Tenant string      `json:"tenant"`
+	Path   string      `json:"path"`
+	Data   interface{} `json:"data,omitempty"`
+}
+
+// DryRunStatus holds the dry run mode, and the rest operations planned for every virtualservice.
+type DryRunStatus struct {
+	Enabled bool                      `json:"enabled"`
+	Plans   map[string][]DryRunRestOp `json:"plans"`
+}
+
+var DryRunPlans *DryRunModel
+var dryrunonce sync.Once
+
+// DryRunModel implements ApiModel
+type DryRunModel struct {
+	// enabled is set when the dry run mode is changed through the API, and overrides the mode set at startup.
+	enabled    *bool
+	plans      map[string][]DryRunRestOp
+	syncFunc   func(keys []string)
+	dryRunLock sync.RWMutex
+}
+
+func (a *DryRunModel) InitModel() {
+	dryrunonce.Do(func() {
+		DryRunPlans = &DryRunModel{
+			plans: make(map[string][]DryRunRestOp),
+		}
+	})
+}
+
+func (a *DryRunModel) ApiOperationMap(prometheusEnavbled bool, reg *prometheus.Registry) []OperationMap {
+	var operationMapList []OperationMap
+
+	get := OperationMap{
+		Route:  "/api/dryrun",
+		Method: "GET",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("format") == "diff" {
+				w.Header().Add("Content-Type", "text/plain")
+				w.Write([]byte(DryRunPlans.Diff()))
+				return
+			}
+			utils.Respond(w, DryRunPlans.Status())
+		},
+	}
+	operationMapList = append(operationMapList, get)
+
+	put := OperationMap{
+		Route:  "/api/dryrun",
+		Method: "PUT",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			var request struct {
+				Enabled *bool `json:"enabled"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Enabled == nil {
+				http.Error(w, `expected a body of the form {"enabled": true|false}`, http.StatusBadRequest)
+				return
+			}
+			DryRunPlans.SetEnabled(*request.Enabled)
+			utils.Respond(w, DryRunPlans.Status())
+		},
+	}
+	operationMapList = append(operationMapList, put)
+	return operationMapList
+}
+
+// IsEnabled returns the dry run mode set through the API, and false if it was not set.
+func (a *DryRunModel) IsEnabled() (bool, bool) {
+	// The model is not initialized if the API server is not running.
+	if a == nil {
+		return false, false
+	}
+	a.dryRunLock.RLock()
+	defer a.dryRunLock.RUnlock()
+	if a.enabled == nil {
+		return false, false
+	}
+	return *a.enabled, true
+}
+
+// SetEnabled changes the dry run mode. The plans are cleared when the mode is enabled, and the virtualservices
+// with planned rest operations are synced when the mode is disabled.
+func (a *DryRunModel) SetEnabled(enabled bool) {
+	if a == nil {
+		return
+	}
+	a.dryRunLock.Lock()
+	a.enabled = &enabled
+	var keys []string
+	if enabled {
+		a.plans = make(map[string][]DryRunRestOp)
+	} else {
+		for key := range a.plans {
+			keys = append(keys, key)
+		}
+	}
+	syncFunc := a.syncFunc
+	a.dryRunLock.Unlock()
+
+	utils.AviLog.Infof("Dry run mode set to %t", enabled)
+	if len(keys) > 0 && syncFunc != nil {
+		sort.Strings(keys)
+		syncFunc(keys)
+	}
+}
+
+// SetSyncFunc sets the function which syncs the virtualservices with planned rest operations, once the dry run
+// mode is disabled.
+func (a *DryRunModel) SetSyncFunc(syncFunc func(keys []string)) {
+	if a == nil {
+		return
+	}
+	a.dryRunLock.Lock()
+	defer a.dryRunLock.Unlock()
+	a.syncFunc = syncFunc
+}
+
+// ResetPlan removes the planned rest operations of a virtualservice, before it is planned afresh.
+func (a *DryRunModel) ResetPlan(key string) {
+	if a == nil {
+		return
+	}
+	a.dryRunLock.Lock()
+	defer a.dryRunLock.Unlock()
+	delete(a.plans, key)
+}
+
+// AddToPlan adds rest operations to the plan of a virtualservice.
+func (a *DryRunModel) AddToPlan(key string, restOps []DryRunRestOp) {
+	if a == nil || len(restOps) == 0 {
+		return
+	}
+	a.dryRunLock.Lock()
+	defer a.dryRunLock.Unlock()
+	a.plans[key] = append(a.plans[key], restOps...)
+}
+
+func (a *DryRunModel) GetPlan(key string) []DryRunRestOp {
+	if a == nil {
+		return nil
+	}
+	a.dryRunLock.RLock()
+	defer a.dryRunLock.RUnlock()
+	return append([]DryRunRestOp{}, a.plans[key]...)
+}
+
+func (a *DryRunModel) Status() DryRunStatus {
+	status := DryRunStatus{Plans: make(map[string][]DryRunRestOp)}
+	if a == nil {
+		return status
+	}
+	a.dryRunLock.RLock()
+	defer a.dryRunLock.RUnlock()
+	if a.enabled != nil {
+		status.Enabled = *a.enabled
+	}
+	for key, restOps := range a.plans {
+		status.Plans[key] = append([]DryRunRestOp{}, restOps...)
+	}
+	return status
+}
+
+// Diff returns the plans as a diff against the objects on the Avi controller, with one line per object
+// which would be created (+), updated (~) or deleted (-), grouped by virtualservice.
+func (a *DryRunModel) Diff() string {
+	plans := a.Status().Plans
+	var keys []string
+	for key := range plans {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var diff strings.Builder
+	for _, key := range keys {
+		fmt.Fprintf(&diff, "%s\n", key)
+		for _, restOp := range plans[key] {
+			prefix := "~"
+			switch restOp.Method {
+			case "POST":
+				prefix = "+"
+			case "DELETE":
+				prefix = "-"
+			}
+			fmt.Fprintf(&diff, "%s %s %s %s\n", prefix, restOp.Method, restOp.Model, restOp.Name)
+		}
+	}
+	return diff.String()
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package models
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DeadLetterKey holds the details of a key which was not retried any further after exhausting its retry attempts.
+type DeadLetterKey struct {
+	Key       string    `json:"key"`
+	Attempts  int       `json:"attempts"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+var RetryDeadLetters *DeadLetterModel
+var deadletteronce sync.Once
+
+// DeadLetterModel implements ApiModel
+type DeadLetterModel struct {
+	keys           map[string]DeadLetterKey
+	deadLetterLock sync.RWMutex
+}
+
+func (a *DeadLetterModel) InitModel() {
+	deadletteronce.Do(func() {
+		RetryDeadLetters = &DeadLetterModel{
+			keys: make(map[string]DeadLetterKey),
+		}
+	})
+}
+
+func (a *DeadLetterModel) ApiOperationMap(prometheusEnavbled bool, reg *prometheus.Registry) []OperationMap {
+	var operationMapList []OperationMap
+
+	get := OperationMap{
+		Route:  "/api/retry/deadletter",
+		Method: "GET",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			response := RetryDeadLetters.List()
+			utils.Respond(w, response)
+		},
+	}
+	operationMapList = append(operationMapList, get)
+	return operationMapList
+}
+
+// Add adds a key to the dead-letter set and returns the number of keys in the set.
+func (a *DeadLetterModel) Add(key string, attempts int) int {
+	// The model is not initialized if the API server is not running.
+	if a == nil {
+		return 0
+	}
+	a.deadLetterLock.Lock()
+	defer a.deadLetterLock.Unlock()
+	a.keys[key] = DeadLetterKey{
+		Key:       key,
+		Attempts:  attempts,
+		Timestamp: time.Now(),
+	}
+	return len(a.keys)
+}
+
+// Remove removes a key from the dead-letter set and returns the number of keys in the set.
+func (a *DeadLetterModel) Remove(key string) (bool, int) {
+	if a == nil {
+		return false, 0
+	}
+	a.deadLetterLock.Lock()
+	defer a.deadLetterLock.Unlock()
+	_, ok := a.keys[key]
+	delete(a.keys, key)
+	return ok, len(a.keys)
+}
+
+func (a *DeadLetterModel) Get(key string) (DeadLetterKey, bool) {
+	if a == nil {
+		return DeadLetterKey{}, false
+	}
+	a.deadLetterLock.RLock()
+	defer a.deadLetterLock.RUnlock()
+	deadLetterKey, ok := a.keys[key]
+	return deadLetterKey, ok
+}
+
+// List returns the keys in the dead-letter set, sorted by key.
+func (a *DeadLetterModel) List() []DeadLetterKey {
+	deadLetterKeys := []DeadLetterKey{}
+	if a == nil {
+		return deadLetterKeys
+	}
+	a.deadLetterLock.RLock()
+	defer a.deadLetterLock.RUnlock()
+	for _, deadLetterKey := range a.keys {
+		deadLetterKeys = append(deadLetterKeys, deadLetterKey)
+	}
+	sort.Slice(deadLetterKeys, func(i, j int) bool {
+		return deadLetterKeys[i].Key < deadLetterKeys[j].Key
+	})
+	return deadLetterKeys
+}
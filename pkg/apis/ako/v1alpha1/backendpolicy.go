@@ -0,0 +1,76 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package v1alpha1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BackendPolicy is a top-level type, which attaches the pool settings to the
+// backends of a Service, an HTTPRoute (rule) or a Gateway.
+type BackendPolicy struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec BackendPolicySpec `json:"spec,omitempty"`
+
+	// +optional
+	Status BackendPolicyStatus `json:"status,omitempty"`
+}
+
+// BackendPolicySpec consists of the target and the pool settings of the BackendPolicy
+type BackendPolicySpec struct {
+	TargetRef              BackendPolicyTargetRef `json:"targetRef"`
+	LoadBalancerPolicy     BackendPolicyLBPolicy  `json:"loadBalancerPolicy,omitempty"`
+	HealthMonitors         []string               `json:"healthMonitors,omitempty"`
+	ApplicationPersistence string                 `json:"applicationPersistence,omitempty"`
+}
+
+// BackendPolicyTargetRef identifies the object, in the namespace of the
+// BackendPolicy, whose backends the policy applies to.
+type BackendPolicyTargetRef struct {
+	Group string `json:"group"`
+	Kind  string `json:"kind"`
+	Name  string `json:"name"`
+	// SectionName is the name of a port of the Service, or the index
+	// of a rule of the HTTPRoute.
+	// +optional
+	SectionName *string `json:"sectionName,omitempty"`
+}
+
+// BackendPolicyLBPolicy holds the load balancer policies of the pools
+type BackendPolicyLBPolicy struct {
+	Algorithm  string `json:"algorithm,omitempty"`
+	Hash       string `json:"hash,omitempty"`
+	HostHeader string `json:"hostHeader,omitempty"`
+}
+
+// BackendPolicyStatus holds the status of the BackendPolicy
+type BackendPolicyStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BackendPolicyList has the list of BackendPolicy objects
+type BackendPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []BackendPolicy `json:"items"`
+}
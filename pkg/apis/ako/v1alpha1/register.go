@@ -52,6 +52,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&MultiClusterIngressList{},
 		&ServiceImport{},
 		&ServiceImportList{},
+		&BackendPolicy{},
+		&BackendPolicyList{},
 	)
 
 	scheme.AddKnownTypes(
@@ -32,6 +32,17 @@ type SSORuleSpec struct {
 type SSORuleStatus struct {
 	Status string `json:"status,omitempty"`
 	Error  string `json:"error"`
+	// ObservedGeneration is the generation of the SSORule last validated by AKO.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions has the Accepted, ResolvedRefs and Programmed conditions of the SSORule.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// AppliedTo has the Avi objects the SSORule is currently applied to. It is not omitted when empty,
+	// so that it is cleared by the merge patches of the status.
+	// +optional
+	AppliedTo []string `json:"appliedTo"`
 }
 
 // +genclient
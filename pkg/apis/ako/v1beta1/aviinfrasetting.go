@@ -84,6 +84,13 @@ type AviInfraL7Settings struct {
 type AviInfraSettingStatus struct {
 	Status string `json:"status,omitempty"`
 	Error  string `json:"error"`
+	// ObservedGeneration is the generation of the AviInfraSetting last validated by AKO.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions has the Accepted and ResolvedRefs conditions of the AviInfraSetting.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -76,6 +76,7 @@ type HostRuleVirtualHost struct {
 	Aliases               []string                 `json:"aliases,omitempty"`
 	ICAPProfile           []string                 `json:"icapProfile,omitempty"`
 	NetworkSecurityPolicy string                   `json:"networkSecurityPolicy,omitempty"`
+	RateLimit             *HostRuleRateLimit       `json:"rateLimit,omitempty"`
 }
 
 // HostRuleTCPSettings allows for customizing TCP settings
@@ -91,13 +92,104 @@ type HostRuleTCPListeners struct {
 	EnableSSL bool `json:"enableSSL,omitempty"`
 }
 
+// HostRuleRateLimit holds the rate limits of the requests and the connections to a host
+type HostRuleRateLimit struct {
+	Requests    *HostRuleRequestRateLimit    `json:"requests,omitempty"`
+	Connections *HostRuleConnectionRateLimit `json:"connections,omitempty"`
+}
+
+// HostRuleRequestRateLimit limits the number of requests in a period, counted per client IP
+// or per value of a request header. Burst allows that many requests above the count.
+type HostRuleRequestRateLimit struct {
+	Count  uint32                  `json:"count,omitempty"`
+	Period uint32                  `json:"period,omitempty"`
+	Burst  uint32                  `json:"burst,omitempty"`
+	Key    HostRuleRateLimitKey    `json:"key,omitempty"`
+	Header string                  `json:"header,omitempty"`
+	Action HostRuleRateLimitAction `json:"action,omitempty"`
+}
+
+// HostRuleConnectionRateLimit limits the number of new connections in a period, for all the clients.
+// It is applied only to the parent and dedicated virtual services, which own the connections.
+type HostRuleConnectionRateLimit struct {
+	Count  uint32 `json:"count,omitempty"`
+	Period uint32 `json:"period,omitempty"`
+	Burst  uint32 `json:"burst,omitempty"`
+}
+
+type HostRuleRateLimitKey string
+
+const (
+	HostRuleRateLimitKeyClientIP HostRuleRateLimitKey = "ClientIP"
+	HostRuleRateLimitKeyHeader   HostRuleRateLimitKey = "Header"
+)
+
+// HostRuleRateLimitAction is taken on the requests over the limit
+type HostRuleRateLimitAction struct {
+	Type     HostRuleRateLimitActionType `json:"type,omitempty"`
+	Redirect *HostRuleRateLimitRedirect  `json:"redirect,omitempty"`
+}
+
+type HostRuleRateLimitActionType string
+
+const (
+	HostRuleRateLimitActionDrop            HostRuleRateLimitActionType = "Drop"
+	HostRuleRateLimitActionTooManyRequests HostRuleRateLimitActionType = "TooManyRequests"
+	HostRuleRateLimitActionRedirect        HostRuleRateLimitActionType = "Redirect"
+)
+
+// HostRuleRateLimitRedirect holds the target of the redirect action
+type HostRuleRateLimitRedirect struct {
+	Protocol   string `json:"protocol,omitempty"`
+	Host       string `json:"host,omitempty"`
+	Port       uint32 `json:"port,omitempty"`
+	Path       string `json:"path,omitempty"`
+	StatusCode int    `json:"statusCode,omitempty"`
+}
+
 // HostRuleTLS holds secure host specific properties
 type HostRuleTLS struct {
-	SSLKeyCertificate HostRuleSSLKeyCertificate `json:"sslKeyCertificate,omitempty"`
-	SSLProfile        string                    `json:"sslProfile,omitempty"`
-	Termination       string                    `json:"termination,omitempty"`
+	SSLKeyCertificate HostRuleSSLKeyCertificate  `json:"sslKeyCertificate,omitempty"`
+	SSLProfile        string                     `json:"sslProfile,omitempty"`
+	Termination       string                     `json:"termination,omitempty"`
+	ClientCertificate *HostRuleClientCertificate `json:"clientCertificate,omitempty"`
+}
+
+// HostRuleClientCertificate holds the settings to authenticate the clients with their certificates.
+// The CA is either an Avi PKI profile (ref), or a Kubernetes Secret with the CA bundle in ca.crt (secret).
+type HostRuleClientCertificate struct {
+	Mode    HostRuleClientCertificateMode     `json:"mode,omitempty"`
+	CA      HostRuleSecret                    `json:"ca,omitempty"`
+	Headers []HostRuleClientCertificateHeader `json:"headers,omitempty"`
+}
+
+type HostRuleClientCertificateMode string
+
+const (
+	HostRuleClientCertificateModeRequire HostRuleClientCertificateMode = "Require"
+	HostRuleClientCertificateModeRequest HostRuleClientCertificateMode = "Request"
+	HostRuleClientCertificateModeNone    HostRuleClientCertificateMode = "None"
+)
+
+// HostRuleClientCertificateHeader forwards a detail of the client certificate to the backends in a request header
+type HostRuleClientCertificateHeader struct {
+	Name  string                               `json:"name,omitempty"`
+	Value HostRuleClientCertificateHeaderValue `json:"value,omitempty"`
 }
 
+type HostRuleClientCertificateHeaderValue string
+
+const (
+	HostRuleClientCertificateHeaderCertificate        HostRuleClientCertificateHeaderValue = "Certificate"
+	HostRuleClientCertificateHeaderEscapedCertificate HostRuleClientCertificateHeaderValue = "EscapedCertificate"
+	HostRuleClientCertificateHeaderSubject            HostRuleClientCertificateHeaderValue = "Subject"
+	HostRuleClientCertificateHeaderIssuer             HostRuleClientCertificateHeaderValue = "Issuer"
+	HostRuleClientCertificateHeaderSerial             HostRuleClientCertificateHeaderValue = "Serial"
+	HostRuleClientCertificateHeaderFingerprint        HostRuleClientCertificateHeaderValue = "Fingerprint"
+	HostRuleClientCertificateHeaderNotValidBefore     HostRuleClientCertificateHeaderValue = "NotValidBefore"
+	HostRuleClientCertificateHeaderNotValidAfter      HostRuleClientCertificateHeaderValue = "NotValidAfter"
+)
+
 // HostRuleSecret is required to provide distinction between Avi SSLKeyCertificate
 // or K8s Secret Objects
 type HostRuleSecret struct {
@@ -133,6 +225,17 @@ type HostRuleGSLB struct {
 type HostRuleStatus struct {
 	Status string `json:"status,omitempty"`
 	Error  string `json:"error"`
+	// ObservedGeneration is the generation of the HostRule last validated by AKO.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions has the Accepted, ResolvedRefs and Programmed conditions of the HostRule.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// AppliedTo has the Avi objects the HostRule is currently applied to. It is not omitted when empty,
+	// so that it is cleared by the merge patches of the status.
+	// +optional
+	AppliedTo []string `json:"appliedTo"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -39,11 +39,34 @@ type HTTPRuleSpec struct {
 
 // HTTPRulePaths has settings for a specific target path
 type HTTPRulePaths struct {
-	Target                 string           `json:"target,omitempty"`
-	LoadBalancerPolicy     HTTPRuleLBPolicy `json:"loadBalancerPolicy,omitempty"`
-	TLS                    HTTPRuleTLS      `json:"tls,omitempty"`
-	HealthMonitors         []string         `json:"healthMonitors,omitempty"`
-	ApplicationPersistence string           `json:"applicationPersistence,omitempty"`
+	Target                 string            `json:"target,omitempty"`
+	LoadBalancerPolicy     HTTPRuleLBPolicy  `json:"loadBalancerPolicy,omitempty"`
+	TLS                    HTTPRuleTLS       `json:"tls,omitempty"`
+	HealthMonitors         []string          `json:"healthMonitors,omitempty"`
+	ApplicationPersistence string            `json:"applicationPersistence,omitempty"`
+	Backends               []HTTPRuleBackend `json:"backends,omitempty"`
+}
+
+// HTTPRuleBackend is an alternate service for the target path, which receives either
+// a share of the traffic by weight, or the requests matching a header or a cookie.
+type HTTPRuleBackend struct {
+	ServiceName string `json:"serviceName,omitempty"`
+	Port        int32  `json:"port,omitempty"`
+	// Weight is the percentage of the traffic to the target path sent to the service.
+	Weight int32                 `json:"weight,omitempty"`
+	Match  *HTTPRuleBackendMatch `json:"match,omitempty"`
+}
+
+// HTTPRuleBackendMatch holds the header or the cookie of the requests sent to the service.
+type HTTPRuleBackendMatch struct {
+	Header *HTTPRuleMatchValue `json:"header,omitempty"`
+	Cookie *HTTPRuleMatchValue `json:"cookie,omitempty"`
+}
+
+// HTTPRuleMatchValue holds the name of a header or a cookie, and its value to match.
+type HTTPRuleMatchValue struct {
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value,omitempty"`
 }
 
 // HTTPRuleLBPolicy holds a path/pool's load balancer policies
@@ -65,6 +88,17 @@ type HTTPRuleTLS struct {
 type HTTPRuleStatus struct {
 	Status string `json:"status,omitempty"`
 	Error  string `json:"error"`
+	// ObservedGeneration is the generation of the HTTPRule last validated by AKO.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions has the Accepted, ResolvedRefs and Programmed conditions of the HTTPRule.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// AppliedTo has the Avi objects the HTTPRule is currently applied to. It is not omitted when empty,
+	// so that it is cleared by the merge patches of the status.
+	// +optional
+	AppliedTo []string `json:"appliedTo"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -22,6 +22,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -103,7 +104,7 @@ func (in *AviInfraSetting) DeepCopyInto(out *AviInfraSetting) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	in.Spec.DeepCopyInto(&out.Spec)
 	return
 }
@@ -269,6 +270,13 @@ func (in *AviInfraSettingSpec) DeepCopy() *AviInfraSettingSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AviInfraSettingStatus) DeepCopyInto(out *AviInfraSettingStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -324,7 +332,7 @@ func (in *HTTPRule) DeepCopyInto(out *HTTPRule) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	in.Spec.DeepCopyInto(&out.Spec)
 	return
 }
@@ -347,6 +355,53 @@ func (in *HTTPRule) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRuleBackend) DeepCopyInto(out *HTTPRuleBackend) {
+	*out = *in
+	if in.Match != nil {
+		in, out := &in.Match, &out.Match
+		*out = new(HTTPRuleBackendMatch)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRuleBackend.
+func (in *HTTPRuleBackend) DeepCopy() *HTTPRuleBackend {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRuleBackend)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRuleBackendMatch) DeepCopyInto(out *HTTPRuleBackendMatch) {
+	*out = *in
+	if in.Header != nil {
+		in, out := &in.Header, &out.Header
+		*out = new(HTTPRuleMatchValue)
+		**out = **in
+	}
+	if in.Cookie != nil {
+		in, out := &in.Cookie, &out.Cookie
+		*out = new(HTTPRuleMatchValue)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRuleBackendMatch.
+func (in *HTTPRuleBackendMatch) DeepCopy() *HTTPRuleBackendMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRuleBackendMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPRuleLBPolicy) DeepCopyInto(out *HTTPRuleLBPolicy) {
 	*out = *in
@@ -396,6 +451,22 @@ func (in *HTTPRuleList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRuleMatchValue) DeepCopyInto(out *HTTPRuleMatchValue) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRuleMatchValue.
+func (in *HTTPRuleMatchValue) DeepCopy() *HTTPRuleMatchValue {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRuleMatchValue)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPRulePaths) DeepCopyInto(out *HTTPRulePaths) {
 	*out = *in
@@ -406,6 +477,13 @@ func (in *HTTPRulePaths) DeepCopyInto(out *HTTPRulePaths) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = make([]HTTPRuleBackend, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -445,6 +523,18 @@ func (in *HTTPRuleSpec) DeepCopy() *HTTPRuleSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPRuleStatus) DeepCopyInto(out *HTTPRuleStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AppliedTo != nil {
+		in, out := &in.AppliedTo, &out.AppliedTo
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -479,7 +569,7 @@ func (in *HostRule) DeepCopyInto(out *HostRule) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	in.Spec.DeepCopyInto(&out.Spec)
 	return
 }
@@ -528,6 +618,60 @@ func (in *HostRuleAnalyticsPolicy) DeepCopy() *HostRuleAnalyticsPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostRuleClientCertificate) DeepCopyInto(out *HostRuleClientCertificate) {
+	*out = *in
+	out.CA = in.CA
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]HostRuleClientCertificateHeader, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostRuleClientCertificate.
+func (in *HostRuleClientCertificate) DeepCopy() *HostRuleClientCertificate {
+	if in == nil {
+		return nil
+	}
+	out := new(HostRuleClientCertificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostRuleClientCertificateHeader) DeepCopyInto(out *HostRuleClientCertificateHeader) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostRuleClientCertificateHeader.
+func (in *HostRuleClientCertificateHeader) DeepCopy() *HostRuleClientCertificateHeader {
+	if in == nil {
+		return nil
+	}
+	out := new(HostRuleClientCertificateHeader)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostRuleConnectionRateLimit) DeepCopyInto(out *HostRuleConnectionRateLimit) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostRuleConnectionRateLimit.
+func (in *HostRuleConnectionRateLimit) DeepCopy() *HostRuleConnectionRateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(HostRuleConnectionRateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostRuleGSLB) DeepCopyInto(out *HostRuleGSLB) {
 	*out = *in
@@ -598,6 +742,86 @@ func (in *HostRuleList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostRuleRateLimit) DeepCopyInto(out *HostRuleRateLimit) {
+	*out = *in
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		*out = new(HostRuleRequestRateLimit)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Connections != nil {
+		in, out := &in.Connections, &out.Connections
+		*out = new(HostRuleConnectionRateLimit)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostRuleRateLimit.
+func (in *HostRuleRateLimit) DeepCopy() *HostRuleRateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(HostRuleRateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostRuleRateLimitAction) DeepCopyInto(out *HostRuleRateLimitAction) {
+	*out = *in
+	if in.Redirect != nil {
+		in, out := &in.Redirect, &out.Redirect
+		*out = new(HostRuleRateLimitRedirect)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostRuleRateLimitAction.
+func (in *HostRuleRateLimitAction) DeepCopy() *HostRuleRateLimitAction {
+	if in == nil {
+		return nil
+	}
+	out := new(HostRuleRateLimitAction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostRuleRateLimitRedirect) DeepCopyInto(out *HostRuleRateLimitRedirect) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostRuleRateLimitRedirect.
+func (in *HostRuleRateLimitRedirect) DeepCopy() *HostRuleRateLimitRedirect {
+	if in == nil {
+		return nil
+	}
+	out := new(HostRuleRateLimitRedirect)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostRuleRequestRateLimit) DeepCopyInto(out *HostRuleRequestRateLimit) {
+	*out = *in
+	in.Action.DeepCopyInto(&out.Action)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostRuleRequestRateLimit.
+func (in *HostRuleRequestRateLimit) DeepCopy() *HostRuleRequestRateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(HostRuleRequestRateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostRuleSSLKeyCertificate) DeepCopyInto(out *HostRuleSSLKeyCertificate) {
 	*out = *in
@@ -651,6 +875,18 @@ func (in *HostRuleSpec) DeepCopy() *HostRuleSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostRuleStatus) DeepCopyInto(out *HostRuleStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AppliedTo != nil {
+		in, out := &in.AppliedTo, &out.AppliedTo
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -705,6 +941,11 @@ func (in *HostRuleTCPSettings) DeepCopy() *HostRuleTCPSettings {
 func (in *HostRuleTLS) DeepCopyInto(out *HostRuleTLS) {
 	*out = *in
 	out.SSLKeyCertificate = in.SSLKeyCertificate
+	if in.ClientCertificate != nil {
+		in, out := &in.ClientCertificate, &out.ClientCertificate
+		*out = new(HostRuleClientCertificate)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -733,7 +974,7 @@ func (in *HostRuleVirtualHost) DeepCopyInto(out *HostRuleVirtualHost) {
 	}
 	in.HTTPPolicy.DeepCopyInto(&out.HTTPPolicy)
 	out.Gslb = in.Gslb
-	out.TLS = in.TLS
+	in.TLS.DeepCopyInto(&out.TLS)
 	if in.AnalyticsPolicy != nil {
 		in, out := &in.AnalyticsPolicy, &out.AnalyticsPolicy
 		*out = new(HostRuleAnalyticsPolicy)
@@ -754,6 +995,11 @@ func (in *HostRuleVirtualHost) DeepCopyInto(out *HostRuleVirtualHost) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(HostRuleRateLimit)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
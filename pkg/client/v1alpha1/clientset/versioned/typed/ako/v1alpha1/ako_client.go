@@ -26,6 +26,7 @@ import (
 
 type AkoV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	BackendPoliciesGetter
 	ClusterSetsGetter
 	MultiClusterIngressesGetter
 	ServiceImportsGetter
@@ -36,6 +37,10 @@ type AkoV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *AkoV1alpha1Client) BackendPolicies(namespace string) BackendPolicyInterface {
+	return newBackendPolicies(c, namespace)
+}
+
 func (c *AkoV1alpha1Client) ClusterSets(namespace string) ClusterSetInterface {
 	return newClusterSets(c, namespace)
 }
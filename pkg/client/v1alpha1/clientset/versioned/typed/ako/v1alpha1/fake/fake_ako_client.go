@@ -28,6 +28,10 @@ type FakeAkoV1alpha1 struct {
 	*testing.Fake
 }
 
+func (c *FakeAkoV1alpha1) BackendPolicies(namespace string) v1alpha1.BackendPolicyInterface {
+	return &FakeBackendPolicies{c, namespace}
+}
+
 func (c *FakeAkoV1alpha1) ClusterSets(namespace string) v1alpha1.ClusterSetInterface {
 	return &FakeClusterSets{c, namespace}
 }
@@ -18,6 +18,8 @@ limitations under the License.
 
 package v1alpha1
 
+type BackendPolicyExpansion interface{}
+
 type ClusterSetExpansion interface{}
 
 type MultiClusterIngressExpansion interface{}
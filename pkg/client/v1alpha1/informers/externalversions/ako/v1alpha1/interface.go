@@ -24,6 +24,8 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// BackendPolicies returns a BackendPolicyInformer.
+	BackendPolicies() BackendPolicyInformer
 	// ClusterSets returns a ClusterSetInformer.
 	ClusterSets() ClusterSetInformer
 	// MultiClusterIngresses returns a MultiClusterIngressInformer.
@@ -43,6 +45,11 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// BackendPolicies returns a BackendPolicyInformer.
+func (v *version) BackendPolicies() BackendPolicyInformer {
+	return &backendPolicyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // ClusterSets returns a ClusterSetInformer.
 func (v *version) ClusterSets() ClusterSetInformer {
 	return &clusterSetInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
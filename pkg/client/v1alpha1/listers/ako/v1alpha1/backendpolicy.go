@@ -0,0 +1,99 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// BackendPolicyLister helps list BackendPolicies.
+// All objects returned here must be treated as read-only.
+type BackendPolicyLister interface {
+	// List lists all BackendPolicies in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.BackendPolicy, err error)
+	// BackendPolicies returns an object that can list and get BackendPolicies.
+	BackendPolicies(namespace string) BackendPolicyNamespaceLister
+	BackendPolicyListerExpansion
+}
+
+// backendPolicyLister implements the BackendPolicyLister interface.
+type backendPolicyLister struct {
+	indexer cache.Indexer
+}
+
+// NewBackendPolicyLister returns a new BackendPolicyLister.
+func NewBackendPolicyLister(indexer cache.Indexer) BackendPolicyLister {
+	return &backendPolicyLister{indexer: indexer}
+}
+
+// List lists all BackendPolicies in the indexer.
+func (s *backendPolicyLister) List(selector labels.Selector) (ret []*v1alpha1.BackendPolicy, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.BackendPolicy))
+	})
+	return ret, err
+}
+
+// BackendPolicies returns an object that can list and get BackendPolicies.
+func (s *backendPolicyLister) BackendPolicies(namespace string) BackendPolicyNamespaceLister {
+	return backendPolicyNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// BackendPolicyNamespaceLister helps list and get BackendPolicies.
+// All objects returned here must be treated as read-only.
+type BackendPolicyNamespaceLister interface {
+	// List lists all BackendPolicies in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.BackendPolicy, err error)
+	// Get retrieves the BackendPolicy from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.BackendPolicy, error)
+	BackendPolicyNamespaceListerExpansion
+}
+
+// backendPolicyNamespaceLister implements the BackendPolicyNamespaceLister
+// interface.
+type backendPolicyNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all BackendPolicies in the indexer for a given namespace.
+func (s backendPolicyNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.BackendPolicy, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.BackendPolicy))
+	})
+	return ret, err
+}
+
+// Get retrieves the BackendPolicy from the indexer for a given namespace and name.
+func (s backendPolicyNamespaceLister) Get(name string) (*v1alpha1.BackendPolicy, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("backendpolicy"), name)
+	}
+	return obj.(*v1alpha1.BackendPolicy), nil
+}
@@ -18,6 +18,14 @@ limitations under the License.
 
 package v1alpha1
 
+// BackendPolicyListerExpansion allows custom methods to be added to
+// BackendPolicyLister.
+type BackendPolicyListerExpansion interface{}
+
+// BackendPolicyNamespaceListerExpansion allows custom methods to be added to
+// BackendPolicyNamespaceLister.
+type BackendPolicyNamespaceListerExpansion interface{}
+
 // ClusterSetListerExpansion allows custom methods to be added to
 // ClusterSetLister.
 type ClusterSetListerExpansion interface{}
@@ -29,6 +29,7 @@ const (
 	SecretInformer                = "SecretInformer"
 	NodeInformer                  = "NodeInformer"
 	EndpointInformer              = "EndpointInformer"
+	EndpointSlicesInformer        = "EndpointSlicesInformer"
 	ConfigMapInformer             = "ConfigMapInformer"
 	MultiClusterIngressInformer   = "MultiClusterIngressInformer"
 	ServiceImportInformer         = "ServiceImportInformer"
@@ -36,6 +37,7 @@ const (
 	K8S_TLS_SECRET_KEY            = "tls.key"
 	K8S_TLS_SECRET_ALT_CERT       = "alt.crt"
 	K8S_TLS_SECRET_ALT_KEY        = "alt.key"
+	K8S_TLS_SECRET_CA_CERT        = "ca.crt"
 	IngressInformer               = "IngressInformer"
 	RouteInformer                 = "RouteInformer"
 	IngressClassInformer          = "IngressClassInformer"
@@ -67,6 +69,7 @@ const (
 	DEFAULT_L4_SSL_APP_PROFILE    = "System-SSL-Application"
 	DEFAULT_L7_APP_PROFILE        = "System-HTTP"
 	DEFAULT_L7_SECURE_APP_PROFILE = "System-Secure-HTTP"
+	CLIENT_IP_PERSISTENCE_PROFILE = "System-Persistence-Client-IP"
 	DEFAULT_SHARD_VS_PREFIX       = "Shard-VS-"
 	L7_PG_PREFIX                  = "-PG-l7"
 	VS_DATASCRIPT_EVT_HTTP_REQ    = "VS_DATASCRIPT_EVT_HTTP_REQ"
@@ -0,0 +1,275 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package utils
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+// FairQueueOptions configures the fair queuing mode of a WorkerQueue. In this mode the keys of every flow
+// (a namespace or a tenant) are queued separately, and the flows are served in a weighted round-robin order,
+// so that a flow with a lot of updates does not starve the others.
+type FairQueueOptions struct {
+	// Weights holds the number of keys served from a flow in its turn. Flows which are not listed have a weight of 1.
+	Weights map[string]int
+	// FlowFunc returns the flow of a key.
+	FlowFunc func(item interface{}) string
+	// ObserveDepth, if set, is called with the number of keys queued for a flow whenever it changes.
+	ObserveDepth func(queueName, flow string, depth int)
+	// ObserveLatency, if set, is called with the time a key of a flow spent in the queue.
+	ObserveLatency func(queueName, flow string, wait time.Duration)
+}
+
+// FairQueueFlowByNamespace returns the namespace of an ingestion layer key of the form objtype/namespace/name.
+// Keys of cluster scoped objects belong to the same flow.
+func FairQueueFlowByNamespace(item interface{}) string {
+	key, ok := item.(string)
+	if !ok {
+		return ""
+	}
+	segments := strings.Split(key, "/")
+	if len(segments) < 3 {
+		return ""
+	}
+	return segments[1]
+}
+
+// FairQueueFlowByTenant returns the tenant of a graph layer key of the form tenant/modelname.
+func FairQueueFlowByTenant(item interface{}) string {
+	key, ok := item.(string)
+	if !ok {
+		return ""
+	}
+	tenant, _ := ExtractNamespaceObjectName(key)
+	return tenant
+}
+
+type fairQueueFlow struct {
+	items []interface{}
+	// Every flow is rate limited separately, so that the keys of a busy flow do not delay the keys of the others.
+	rateLimiter workqueue.RateLimiter
+}
+
+// fairQueue implements workqueue.RateLimitingInterface. Like the workqueue of client-go, a key is queued only once,
+// and a key added while it is being processed is queued again once it is done.
+type fairQueue struct {
+	name    string
+	options FairQueueOptions
+	cond    *sync.Cond
+
+	flows map[string]*fairQueueFlow
+	// active holds the flows with queued keys in their round-robin order, next is the flow being served,
+	// and credit is the number of keys the flow can still be served in its turn.
+	active []string
+	next   int
+	credit int
+	length int
+
+	// dirty holds the flow of the keys which have to be processed, and addTimes the time they were added at.
+	dirty      map[interface{}]string
+	addTimes   map[interface{}]time.Time
+	processing map[interface{}]struct{}
+
+	shuttingDown bool
+	drain        bool
+}
+
+func newFairQueue(name string, options FairQueueOptions) *fairQueue {
+	if options.FlowFunc == nil {
+		options.FlowFunc = func(item interface{}) string { return "" }
+	}
+	return &fairQueue{
+		name:       name,
+		options:    options,
+		cond:       sync.NewCond(&sync.Mutex{}),
+		flows:      make(map[string]*fairQueueFlow),
+		dirty:      make(map[interface{}]string),
+		addTimes:   make(map[interface{}]time.Time),
+		processing: make(map[interface{}]struct{}),
+	}
+}
+
+func (q *fairQueue) getFlow(flow string) *fairQueueFlow {
+	f, ok := q.flows[flow]
+	if !ok {
+		f = &fairQueueFlow{rateLimiter: workqueue.DefaultControllerRateLimiter()}
+		q.flows[flow] = f
+	}
+	return f
+}
+
+func (q *fairQueue) weight(flow string) int {
+	if weight := q.options.Weights[flow]; weight > 0 {
+		return weight
+	}
+	return 1
+}
+
+func (q *fairQueue) observeDepth(flow string, f *fairQueueFlow) {
+	if q.options.ObserveDepth != nil {
+		q.options.ObserveDepth(q.name, flow, len(f.items))
+	}
+}
+
+func (q *fairQueue) enqueue(flow string, item interface{}) {
+	f := q.getFlow(flow)
+	if len(f.items) == 0 {
+		q.active = append(q.active, flow)
+	}
+	f.items = append(f.items, item)
+	q.length++
+	q.observeDepth(flow, f)
+}
+
+// dequeue returns the next key in the weighted round-robin order of the flows.
+func (q *fairQueue) dequeue() (interface{}, string) {
+	flow := q.active[q.next]
+	f := q.flows[flow]
+	if q.credit <= 0 {
+		q.credit = q.weight(flow)
+	}
+	item := f.items[0]
+	f.items[0] = nil
+	f.items = f.items[1:]
+	q.length--
+	q.credit--
+	q.observeDepth(flow, f)
+
+	if len(f.items) == 0 {
+		// The flow leaves the round-robin order until a key is added to it again.
+		q.active = append(q.active[:q.next], q.active[q.next+1:]...)
+		q.credit = 0
+	} else if q.credit == 0 {
+		q.next++
+	}
+	if q.next >= len(q.active) {
+		q.next = 0
+	}
+	return item, flow
+}
+
+func (q *fairQueue) Add(item interface{}) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	if q.shuttingDown {
+		return
+	}
+	if _, ok := q.dirty[item]; ok {
+		return
+	}
+	flow := q.options.FlowFunc(item)
+	q.dirty[item] = flow
+	q.addTimes[item] = time.Now()
+	if _, ok := q.processing[item]; ok {
+		return
+	}
+	q.enqueue(flow, item)
+	q.cond.Signal()
+}
+
+func (q *fairQueue) Len() int {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return q.length
+}
+
+func (q *fairQueue) Get() (interface{}, bool) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	for q.length == 0 && !q.shuttingDown {
+		q.cond.Wait()
+	}
+	if q.length == 0 {
+		return nil, true
+	}
+	item, flow := q.dequeue()
+	q.processing[item] = struct{}{}
+	delete(q.dirty, item)
+	if q.options.ObserveLatency != nil {
+		q.options.ObserveLatency(q.name, flow, time.Since(q.addTimes[item]))
+	}
+	delete(q.addTimes, item)
+	return item, false
+}
+
+func (q *fairQueue) Done(item interface{}) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	delete(q.processing, item)
+	if flow, ok := q.dirty[item]; ok {
+		q.enqueue(flow, item)
+		q.cond.Signal()
+	} else if len(q.processing) == 0 {
+		q.cond.Broadcast()
+	}
+}
+
+func (q *fairQueue) ShutDown() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	q.drain = false
+	q.shuttingDown = true
+	q.cond.Broadcast()
+}
+
+func (q *fairQueue) ShutDownWithDrain() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	q.drain = true
+	q.shuttingDown = true
+	q.cond.Broadcast()
+	for len(q.processing) != 0 && q.drain {
+		q.cond.Wait()
+	}
+}
+
+func (q *fairQueue) ShuttingDown() bool {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return q.shuttingDown
+}
+
+func (q *fairQueue) AddAfter(item interface{}, duration time.Duration) {
+	if q.ShuttingDown() {
+		return
+	}
+	if duration <= 0 {
+		q.Add(item)
+		return
+	}
+	time.AfterFunc(duration, func() { q.Add(item) })
+}
+
+func (q *fairQueue) rateLimiter(item interface{}) workqueue.RateLimiter {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return q.getFlow(q.options.FlowFunc(item)).rateLimiter
+}
+
+func (q *fairQueue) AddRateLimited(item interface{}) {
+	q.AddAfter(item, q.rateLimiter(item).When(item))
+}
+
+func (q *fairQueue) Forget(item interface{}) {
+	q.rateLimiter(item).Forget(item)
+}
+
+func (q *fairQueue) NumRequeues(item interface{}) int {
+	return q.rateLimiter(item).NumRequeues(item)
+}
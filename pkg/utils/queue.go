@@ -43,7 +43,12 @@ func SharedWorkQueue(queueParams ...*WorkerQueue) *WorkQueueWrapper {
 		queueInstance.queueCollection = make(map[string]*WorkerQueue)
 		if len(queueParams) != 0 {
 			for _, queue := range queueParams {
-				workqueue := NewWorkQueue(queue.NumWorkers, queue.WorkqueueName, queue.SlowSyncTime)
+				var workqueue *WorkerQueue
+				if queue.FairQueuing != nil {
+					workqueue = NewFairWorkQueue(queue.NumWorkers, queue.WorkqueueName, *queue.FairQueuing, queue.SlowSyncTime)
+				} else {
+					workqueue = NewWorkQueue(queue.NumWorkers, queue.WorkqueueName, queue.SlowSyncTime)
+				}
 				queueInstance.queueCollection[queue.WorkqueueName] = workqueue
 			}
 		} else {
@@ -65,9 +70,11 @@ type WorkerQueue struct {
 	workerId      uint32
 	SyncFunc      func(interface{}, *sync.WaitGroup) error
 	SlowSyncTime  int
+	// FairQueuing, if set, serves the keys of the queues in a weighted round-robin order of their flows.
+	FairQueuing *FairQueueOptions
 }
 
-func NewWorkQueue(num_workers uint32, workerQueueName string, slowSyncTime ...int) *WorkerQueue {
+func newWorkerQueue(num_workers uint32, workerQueueName string, slowSyncTime ...int) *WorkerQueue {
 	queue := &WorkerQueue{}
 	queue.Workqueue = make([]workqueue.RateLimitingInterface, num_workers)
 	queue.workerId = (uint32(1) << num_workers) - 1
@@ -76,12 +83,27 @@ func NewWorkQueue(num_workers uint32, workerQueueName string, slowSyncTime ...in
 	if len(slowSyncTime) > 0 {
 		queue.SlowSyncTime = slowSyncTime[0]
 	}
+	return queue
+}
+
+func NewWorkQueue(num_workers uint32, workerQueueName string, slowSyncTime ...int) *WorkerQueue {
+	queue := newWorkerQueue(num_workers, workerQueueName, slowSyncTime...)
 	for i := uint32(0); i < num_workers; i++ {
 		queue.Workqueue[i] = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), fmt.Sprintf("avi-%s", workerQueueName))
 	}
 	return queue
 }
 
+// NewFairWorkQueue returns a WorkerQueue whose queues serve their keys fairly across flows, as per the options.
+func NewFairWorkQueue(num_workers uint32, workerQueueName string, options FairQueueOptions, slowSyncTime ...int) *WorkerQueue {
+	queue := newWorkerQueue(num_workers, workerQueueName, slowSyncTime...)
+	queue.FairQueuing = &options
+	for i := uint32(0); i < num_workers; i++ {
+		queue.Workqueue[i] = newFairQueue(workerQueueName, options)
+	}
+	return queue
+}
+
 func (c *WorkerQueue) Run(stopCh <-chan struct{}, wg *sync.WaitGroup) error {
 	AviLog.Infof("Starting workers to drain the %s layer queues", c.WorkqueueName)
 	if c.SyncFunc == nil {
@@ -31,9 +31,11 @@ import (
 	oshiftclientset "github.com/openshift/client-go/route/clientset/versioned"
 	oshiftinformers "github.com/openshift/client-go/route/informers/externalversions"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 
 	akov1beta1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1beta1"
@@ -195,6 +197,8 @@ func instantiateInformers(kubeClient KubeClientIntf, registeredInformers []strin
 			informers.PodInformer = kubeInformerFactory.Core().V1().Pods()
 		case EndpointInformer:
 			informers.EpInformer = kubeInformerFactory.Core().V1().Endpoints()
+		case EndpointSlicesInformer:
+			informers.EpSlicesInformer = kubeInformerFactory.Discovery().V1().EndpointSlices()
 		case SecretInformer:
 			if akoNSBoundInformer {
 				informers.SecretInformer = akoNSInformerFactory.Core().V1().Secrets()
@@ -337,6 +341,33 @@ func ObjKey(obj interface{}) string {
 	return key
 }
 
+// EndpointSliceObjKey returns the namespace/name key of the Service which owns the EndpointSlice,
+// so that the EndpointSlices of a Service are processed in the same way as its Endpoints.
+func EndpointSliceObjKey(eps *discoveryv1.EndpointSlice) (string, bool) {
+	svcName, ok := eps.Labels[discoveryv1.LabelServiceName]
+	if !ok || svcName == "" {
+		return "", false
+	}
+	return eps.Namespace + "/" + svcName, true
+}
+
+// GetEndpointInformer returns the EndpointSlices informer if the cluster serves discovery.k8s.io/v1 EndpointSlices,
+// and falls back to the Endpoints informer for older clusters.
+func GetEndpointInformer(cs kubernetes.Interface) string {
+	resources, err := cs.Discovery().ServerResourcesForGroupVersion(discoveryv1.SchemeGroupVersion.String())
+	if err != nil {
+		AviLog.Infof("EndpointSlices are not served by the cluster, using Endpoints, err: %v", err)
+		return EndpointInformer
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Name == "endpointslices" {
+			return EndpointSlicesInformer
+		}
+	}
+	AviLog.Infof("EndpointSlices are not served by the cluster, using Endpoints")
+	return EndpointInformer
+}
+
 func Remove(arr []string, item string) []string {
 	for i, v := range arr {
 		if v == item {
@@ -0,0 +1,296 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package endpointslicetests
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	crdfake "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha1/clientset/versioned/fake"
+	v1alpha2crdfake "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha2/clientset/versioned/fake"
+	v1beta1crdfake "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1beta1/clientset/versioned/fake"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+)
+
+var KubeClient *k8sfake.Clientset
+var ctrl *k8s.AviController
+
+func TestMain(m *testing.M) {
+	os.Setenv("VIP_NETWORK_LIST", `[{"networkName":"net123"}]`)
+	os.Setenv("CLUSTER_NAME", "cluster")
+	os.Setenv("CLOUD_NAME", "CLOUD_VCENTER")
+	os.Setenv("SEG_NAME", "Default-Group")
+	os.Setenv("NODE_NETWORK_LIST", `[{"networkName":"net123","cidrs":["10.79.168.0/22"]}]`)
+	os.Setenv("SERVICE_TYPE", "ClusterIP")
+	os.Setenv("AUTO_L4_FQDN", "disable")
+	os.Setenv("POD_NAMESPACE", utils.AKO_DEFAULT_NS)
+	os.Setenv("SHARD_VS_SIZE", "LARGE")
+	os.Setenv("POD_NAME", "ako-0")
+
+	akoControlConfig := lib.AKOControlConfig()
+	KubeClient = k8sfake.NewSimpleClientset()
+	akoControlConfig.SetCRDClientset(crdfake.NewSimpleClientset())
+	akoControlConfig.Setv1alpha2CRDClientset(v1alpha2crdfake.NewSimpleClientset())
+	akoControlConfig.Setv1beta1CRDClientset(v1beta1crdfake.NewSimpleClientset())
+	akoControlConfig.SetAKOInstanceFlag(true)
+	akoControlConfig.SetEventRecorder(lib.AKOEventComponent, KubeClient, true)
+	data := map[string][]byte{
+		"username": []byte("admin"),
+		"password": []byte("admin"),
+	}
+	object := metav1.ObjectMeta{Name: "avi-secret", Namespace: utils.GetAKONamespace()}
+	secret := &corev1.Secret{Data: data, ObjectMeta: object}
+	KubeClient.CoreV1().Secrets(utils.GetAKONamespace()).Create(context.TODO(), secret, metav1.CreateOptions{})
+
+	registeredInformers := []string{
+		utils.ServiceInformer,
+		utils.EndpointSlicesInformer,
+		utils.IngressInformer,
+		utils.IngressClassInformer,
+		utils.SecretInformer,
+		utils.NSInformer,
+		utils.NodeInformer,
+		utils.ConfigMapInformer,
+	}
+	utils.NewInformers(utils.KubeClientIntf{ClientSet: KubeClient}, registeredInformers)
+	informers := k8s.K8sinformers{Cs: KubeClient}
+	k8s.NewCRDInformers()
+
+	integrationtest.InitializeFakeAKOAPIServer()
+	integrationtest.NewAviFakeClientInstance(KubeClient)
+	defer integrationtest.AviFakeClientInstance.Close()
+
+	ctrl = k8s.SharedAviController()
+	stopCh := utils.SetupSignalHandler()
+	ctrlCh := make(chan struct{})
+	quickSyncCh := make(chan struct{})
+	waitGroupMap := make(map[string]*sync.WaitGroup)
+	wgIngestion := &sync.WaitGroup{}
+	waitGroupMap["ingestion"] = wgIngestion
+	wgFastRetry := &sync.WaitGroup{}
+	waitGroupMap["fastretry"] = wgFastRetry
+	wgSlowRetry := &sync.WaitGroup{}
+	waitGroupMap["slowretry"] = wgSlowRetry
+	wgGraph := &sync.WaitGroup{}
+	waitGroupMap["graph"] = wgGraph
+	wgStatus := &sync.WaitGroup{}
+	waitGroupMap["status"] = wgStatus
+	wgLeaderElection := &sync.WaitGroup{}
+	waitGroupMap["leaderElection"] = wgLeaderElection
+
+	integrationtest.AddConfigMap(KubeClient)
+	ctrl.SetSEGroupCloudNameFromNSAnnotations()
+	integrationtest.PollForSyncStart(ctrl, 10)
+
+	ctrl.HandleConfigMap(informers, ctrlCh, stopCh, quickSyncCh)
+	integrationtest.KubeClient = KubeClient
+	integrationtest.AddDefaultIngressClass()
+	integrationtest.AddDefaultNamespace()
+	integrationtest.AddDefaultNamespace(integrationtest.NAMESPACE)
+
+	go ctrl.InitController(informers, registeredInformers, ctrlCh, stopCh, quickSyncCh, waitGroupMap)
+	os.Exit(m.Run())
+}
+
+type fakeEndpoint struct {
+	address     string
+	ready       bool
+	serving     bool
+	terminating bool
+}
+
+func constructEndpointSlice(name, svcName string, addressType discoveryv1.AddressType, endpoints []fakeEndpoint) *discoveryv1.EndpointSlice {
+	portName, port, protocol := "foo0", int32(8080), corev1.ProtocolTCP
+	eps := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: integrationtest.NAMESPACE,
+			Name:      name,
+			Labels:    map[string]string{discoveryv1.LabelServiceName: svcName},
+		},
+		AddressType: addressType,
+		Ports:       []discoveryv1.EndpointPort{{Name: &portName, Port: &port, Protocol: &protocol}},
+	}
+	for i := range endpoints {
+		endpoint := endpoints[i]
+		eps.Endpoints = append(eps.Endpoints, discoveryv1.Endpoint{
+			Addresses: []string{endpoint.address},
+			Conditions: discoveryv1.EndpointConditions{
+				Ready:       &endpoint.ready,
+				Serving:     &endpoint.serving,
+				Terminating: &endpoint.terminating,
+			},
+		})
+	}
+	return eps
+}
+
+func createEndpointSlice(t *testing.T, name, svcName string, addressType discoveryv1.AddressType, endpoints []fakeEndpoint) {
+	eps := constructEndpointSlice(name, svcName, addressType, endpoints)
+	if _, err := KubeClient.DiscoveryV1().EndpointSlices(integrationtest.NAMESPACE).Create(context.TODO(), eps, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in creating EndpointSlice: %v", err)
+	}
+}
+
+func updateEndpointSlice(t *testing.T, name, svcName string, addressType discoveryv1.AddressType, endpoints []fakeEndpoint) {
+	eps := constructEndpointSlice(name, svcName, addressType, endpoints)
+	eps.ResourceVersion = time.Now().Local().String()
+	if _, err := KubeClient.DiscoveryV1().EndpointSlices(integrationtest.NAMESPACE).Update(context.TODO(), eps, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating EndpointSlice: %v", err)
+	}
+}
+
+func deleteEndpointSlice(t *testing.T, name string) {
+	if err := KubeClient.DiscoveryV1().EndpointSlices(integrationtest.NAMESPACE).Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("error in deleting EndpointSlice: %v", err)
+	}
+}
+
+func getPoolServers(modelName string) []string {
+	found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	if !found || aviModel == nil {
+		return nil
+	}
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	if len(nodes) == 0 || len(nodes[0].PoolRefs) == 0 {
+		return nil
+	}
+	var servers []string
+	for _, server := range nodes[0].PoolRefs[0].Servers {
+		servers = append(servers, *server.Ip.Addr)
+	}
+	sort.Strings(servers)
+	return servers
+}
+
+func tearDownSvcLB(t *testing.T, g *gomega.GomegaWithT, epsNames ...string) {
+	objects.SharedAviGraphLister().Delete(integrationtest.SINGLEPORTMODEL)
+	integrationtest.DelSVC(t, integrationtest.NAMESPACE, integrationtest.SINGLEPORTSVC)
+	for _, name := range epsNames {
+		deleteEndpointSlice(t, name)
+	}
+	mcache := cache.SharedAviObjCache()
+	vsKey := cache.NamespaceName{Namespace: integrationtest.AVINAMESPACE, Name: fmt.Sprintf("cluster--%s-%s", integrationtest.NAMESPACE, integrationtest.SINGLEPORTSVC)}
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 10*time.Second).Should(gomega.Equal(false))
+}
+
+// TestSvcLBWithMultipleEndpointSlices verifies that the servers of all the EndpointSlices of a Service are
+// aggregated in the pool, and that the endpoints which are not ready are skipped.
+func TestSvcLBWithMultipleEndpointSlices(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	modelName := integrationtest.SINGLEPORTMODEL
+	svcName := integrationtest.SINGLEPORTSVC
+
+	objects.SharedAviGraphLister().Delete(modelName)
+	integrationtest.CreateSVC(t, integrationtest.NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, false)
+	createEndpointSlice(t, svcName+"-a", svcName, discoveryv1.AddressTypeIPv4, []fakeEndpoint{
+		{address: "1.1.1.1", ready: true, serving: true},
+		{address: "1.1.1.2", ready: true, serving: true},
+	})
+	createEndpointSlice(t, svcName+"-b", svcName, discoveryv1.AddressTypeIPv4, []fakeEndpoint{
+		{address: "1.1.1.3", ready: true, serving: true},
+		{address: "1.1.1.4", ready: false, serving: false},
+	})
+
+	g.Eventually(func() []string {
+		return getPoolServers(modelName)
+	}, 10*time.Second).Should(gomega.Equal([]string{"1.1.1.1", "1.1.1.2", "1.1.1.3"}))
+
+	updateEndpointSlice(t, svcName+"-b", svcName, discoveryv1.AddressTypeIPv4, []fakeEndpoint{
+		{address: "1.1.1.3", ready: true, serving: true},
+		{address: "1.1.1.4", ready: true, serving: true},
+	})
+	g.Eventually(func() []string {
+		return getPoolServers(modelName)
+	}, 10*time.Second).Should(gomega.Equal([]string{"1.1.1.1", "1.1.1.2", "1.1.1.3", "1.1.1.4"}))
+
+	deleteEndpointSlice(t, svcName+"-a")
+	g.Eventually(func() []string {
+		return getPoolServers(modelName)
+	}, 10*time.Second).Should(gomega.Equal([]string{"1.1.1.3", "1.1.1.4"}))
+
+	tearDownSvcLB(t, g, svcName+"-b")
+}
+
+// TestSvcLBWithTerminatingEndpoints verifies that the serving endpoints which are terminating are used
+// only when none of the endpoints of the Service is ready.
+func TestSvcLBWithTerminatingEndpoints(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	modelName := integrationtest.SINGLEPORTMODEL
+	svcName := integrationtest.SINGLEPORTSVC
+
+	objects.SharedAviGraphLister().Delete(modelName)
+	integrationtest.CreateSVC(t, integrationtest.NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, false)
+	createEndpointSlice(t, svcName+"-a", svcName, discoveryv1.AddressTypeIPv4, []fakeEndpoint{
+		{address: "1.1.1.1", ready: false, serving: true, terminating: true},
+		{address: "1.1.1.2", ready: false, serving: false, terminating: true},
+	})
+
+	g.Eventually(func() []string {
+		return getPoolServers(modelName)
+	}, 10*time.Second).Should(gomega.Equal([]string{"1.1.1.1"}))
+
+	updateEndpointSlice(t, svcName+"-a", svcName, discoveryv1.AddressTypeIPv4, []fakeEndpoint{
+		{address: "1.1.1.1", ready: false, serving: true, terminating: true},
+		{address: "1.1.1.3", ready: true, serving: true},
+	})
+	g.Eventually(func() []string {
+		return getPoolServers(modelName)
+	}, 10*time.Second).Should(gomega.Equal([]string{"1.1.1.3"}))
+
+	tearDownSvcLB(t, g, svcName+"-a")
+}
+
+// TestSvcLBWithEndpointSlicesOfOtherAddressType verifies that the EndpointSlices of an address type
+// which does not match the IP family of the Service are skipped.
+func TestSvcLBWithEndpointSlicesOfOtherAddressType(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	modelName := integrationtest.SINGLEPORTMODEL
+	svcName := integrationtest.SINGLEPORTSVC
+
+	objects.SharedAviGraphLister().Delete(modelName)
+	integrationtest.CreateSVC(t, integrationtest.NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, false)
+	createEndpointSlice(t, svcName+"-v4", svcName, discoveryv1.AddressTypeIPv4, []fakeEndpoint{
+		{address: "1.1.1.1", ready: true, serving: true},
+	})
+	createEndpointSlice(t, svcName+"-v6", svcName, discoveryv1.AddressTypeIPv6, []fakeEndpoint{
+		{address: "2001::1", ready: true, serving: true},
+	})
+
+	g.Eventually(func() []string {
+		return getPoolServers(modelName)
+	}, 10*time.Second).Should(gomega.Equal([]string{"1.1.1.1"}))
+
+	tearDownSvcLB(t, g, svcName+"-v4", svcName+"-v6")
+}
@@ -678,6 +678,149 @@ func TestHostruleAnalyticsPolicyUpdateForEvh(t *testing.T) {
 	TearDownIngressForCacheSyncCheck(t, modelName)
 }
 
+func TestHostruleRateLimitForEvh(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName, _ := GetModelName("foo.com", "default")
+	hrname := "rl-hr-foo"
+	SetUpIngressForCacheSyncCheck(t, false, false, modelName)
+	integrationtest.SetupHostRule(t, hrname, "foo.com", false)
+	g.Eventually(func() int {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		return len(nodes[0].EvhNodes)
+	}, 10*time.Second).Should(gomega.Equal(1))
+	evhVSKey := cache.NamespaceName{Namespace: "admin", Name: lib.Encode("cluster--foo.com", lib.EVHVS)}
+	integrationtest.VerifyMetadataHostRule(t, g, evhVSKey, "default/rl-hr-foo", true)
+
+	hrUpdate := integrationtest.FakeHostRule{
+		Name:      hrname,
+		Namespace: "default",
+		Fqdn:      "foo.com",
+	}.HostRule()
+	hrUpdate.Spec.VirtualHost.RateLimit = &v1beta1.HostRuleRateLimit{
+		Requests: &v1beta1.HostRuleRequestRateLimit{
+			Count:  100,
+			Period: 1,
+		},
+	}
+	hrUpdate.ResourceVersion = "2"
+	if _, err := v1beta1CRDClient.AkoV1beta1().HostRules("default").Update(context.TODO(), hrUpdate, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating HostRule: %v", err)
+	}
+
+	// The requests are limited per client IP with the drop action by default.
+	var policy *avinodes.AviHttpPolicySetNode
+	g.Eventually(func() bool {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		if len(nodes[0].EvhNodes) != 1 {
+			return false
+		}
+		for _, httpPolicy := range nodes[0].EvhNodes[0].HttpPolicyRefs {
+			if httpPolicy.Name == lib.GetRateLimitPolicyName(nodes[0].EvhNodes[0].Name) {
+				policy = httpPolicy
+				return true
+			}
+		}
+		return false
+	}, 10*time.Second).Should(gomega.BeTrue())
+	g.Expect(policy.SecurityRules).To(gomega.HaveLen(1))
+	g.Expect(*policy.SecurityRules[0].RateProfile.PerClientIP).To(gomega.BeTrue())
+	g.Expect(*policy.SecurityRules[0].RateProfile.Action.Type).To(gomega.Equal(lib.RL_ACTION_DROP_CONN))
+
+	integrationtest.TeardownHostRule(t, g, evhVSKey, hrname)
+	g.Eventually(func() bool {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		if len(nodes[0].EvhNodes) != 1 {
+			return false
+		}
+		for _, httpPolicy := range nodes[0].EvhNodes[0].HttpPolicyRefs {
+			if httpPolicy.Name == policy.Name {
+				return false
+			}
+		}
+		return true
+	}, 10*time.Second).Should(gomega.BeTrue())
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
+
+func TestHostruleClientCertificateForEvh(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName, _ := GetModelName("foo.com", "default")
+	hrname := "mtls-hr-foo"
+	SetUpIngressForCacheSyncCheck(t, true, true, modelName)
+	integrationtest.SetupHostRule(t, hrname, "foo.com", false)
+	g.Eventually(func() int {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		return len(nodes[0].EvhNodes)
+	}, 10*time.Second).Should(gomega.Equal(1))
+	evhVSName := lib.Encode("cluster--foo.com", lib.EVHVS)
+	evhVSKey := cache.NamespaceName{Namespace: "admin", Name: evhVSName}
+	integrationtest.VerifyMetadataHostRule(t, g, evhVSKey, "default/mtls-hr-foo", true)
+
+	hrUpdate := integrationtest.FakeHostRule{
+		Name:      hrname,
+		Namespace: "default",
+		Fqdn:      "foo.com",
+	}.HostRule()
+	hrUpdate.Spec.VirtualHost.TLS.ClientCertificate = &v1beta1.HostRuleClientCertificate{
+		Mode: v1beta1.HostRuleClientCertificateModeRequire,
+		CA:   v1beta1.HostRuleSecret{Name: "thisisaviref-pki", Type: v1beta1.HostRuleSecretTypeAviReference},
+		Headers: []v1beta1.HostRuleClientCertificateHeader{
+			{Name: "X-Client-Cert", Value: v1beta1.HostRuleClientCertificateHeaderEscapedCertificate},
+		},
+	}
+	hrUpdate.ResourceVersion = "2"
+	if _, err := v1beta1CRDClient.AkoV1beta1().HostRules("default").Update(context.TODO(), hrUpdate, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating HostRule: %v", err)
+	}
+
+	appProfileName := lib.GetClientCertAppProfileName(evhVSName)
+	g.Eventually(func() int {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		if len(nodes[0].EvhNodes) != 1 {
+			return 0
+		}
+		return len(nodes[0].EvhNodes[0].AppProfileRefs)
+	}, 10*time.Second).Should(gomega.Equal(1))
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	evhNode := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()[0].EvhNodes[0]
+	g.Expect(*evhNode.ApplicationProfileRef).To(gomega.Equal("/api/applicationprofile?name=" + appProfileName))
+	appProfile := evhNode.AppProfileRefs[0]
+	g.Expect(appProfile.ClientCertificateMode).To(gomega.Equal(lib.SSL_CLIENT_CERTIFICATE_REQUIRE))
+	g.Expect(appProfile.PkiProfileName).To(gomega.Equal("thisisaviref-pki"))
+	g.Expect(appProfile.PkiProfile).To(gomega.BeNil())
+	g.Expect(appProfile.ClientCertHeaders).To(gomega.HaveLen(1))
+	g.Expect(*appProfile.ClientCertHeaders[0].RequestHeaderValue).To(gomega.Equal("HTTP_POLICY_VAR_SSL_CLIENT_ESCAPED"))
+
+	appProfileKey := cache.NamespaceName{Namespace: "admin", Name: appProfileName}
+	mcache := cache.SharedAviObjCache()
+	g.Eventually(func() bool {
+		_, found := mcache.AppProfileCache.AviCacheGet(appProfileKey)
+		return found
+	}, 10*time.Second).Should(gomega.BeTrue())
+
+	integrationtest.TeardownHostRule(t, g, evhVSKey, hrname)
+	g.Eventually(func() int {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		if len(nodes[0].EvhNodes) != 1 {
+			return -1
+		}
+		return len(nodes[0].EvhNodes[0].AppProfileRefs)
+	}, 10*time.Second).Should(gomega.Equal(0))
+	g.Eventually(func() bool {
+		_, found := mcache.AppProfileCache.AviCacheGet(appProfileKey)
+		return found
+	}, 10*time.Second).Should(gomega.BeFalse())
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
+
 func TestHostruleFQDNAliasesForEvh(t *testing.T) {
 	g := gomega.NewGomegaWithT(t)
 
@@ -1213,6 +1356,87 @@ func TestHTTPRuleCreateDeleteWithPkiRefForEvh(t *testing.T) {
 	TearDownIngressForCacheSyncCheck(t, modelName)
 }
 
+func TestHTTPRuleBackendsForEvh(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName, _ := GetModelName("foo.com", "default")
+	rrname := "samplerr-foo"
+	integrationtest.CreateSVC(t, "default", "avisvc-canary", corev1.ProtocolTCP, corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, "default", "avisvc-canary", false, false, "2.2.2")
+	integrationtest.CreateSVC(t, "default", "avisvc-beta", corev1.ProtocolTCP, corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, "default", "avisvc-beta", false, false, "3.3.3")
+	SetUpIngressForCacheSyncCheck(t, true, true, modelName)
+
+	httprule := integrationtest.FakeHTTPRule{
+		Name:           rrname,
+		Namespace:      "default",
+		Fqdn:           "foo.com",
+		PathProperties: []integrationtest.FakeHTTPRulePath{{Path: "/foo"}},
+	}.HTTPRule()
+	httprule.Spec.Paths[0].TLS = v1beta1.HTTPRuleTLS{}
+	httprule.Spec.Paths[0].Backends = []v1beta1.HTTPRuleBackend{
+		{ServiceName: "avisvc-canary", Port: 8080, Weight: 20},
+		{ServiceName: "avisvc-beta", Port: 8080, Match: &v1beta1.HTTPRuleBackendMatch{
+			Cookie: &v1beta1.HTTPRuleMatchValue{Name: "beta", Value: "yes"},
+		}},
+	}
+	if _, err := v1beta1CRDClient.AkoV1beta1().HTTPRules("default").Create(context.TODO(), httprule, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding HTTPRule: %v", err)
+	}
+
+	poolName := lib.GetEvhPoolName("foo-with-targets", "default", "foo.com", "/foo", "", "avisvc", false)
+	canaryPoolName := lib.GetEvhPoolName("foo-with-targets", "default", "foo.com", "/foo", "", "avisvc-canary", false)
+	betaPoolName := lib.GetEvhPoolName("foo-with-targets", "default", "foo.com", "/foo", "", "avisvc-beta", false)
+	g.Eventually(func() int {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		if len(nodes) != 1 || len(nodes[0].EvhNodes) != 1 {
+			return 0
+		}
+		return len(nodes[0].EvhNodes[0].PoolRefs)
+	}, 10*time.Second).Should(gomega.Equal(3))
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	evhNode := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()[0].EvhNodes[0]
+	ratios := make(map[string]uint32)
+	for _, member := range evhNode.PoolGroupRefs[0].Members {
+		ratios[*member.PoolRef] = *member.Ratio
+	}
+	g.Expect(ratios).To(gomega.Equal(map[string]uint32{
+		"/api/pool?name=" + poolName:       80,
+		"/api/pool?name=" + canaryPoolName: 20,
+	}))
+	var betaHppMap *avinodes.AviHostPathPortPoolPG
+	for i, hppMap := range evhNode.HttpPolicyRefs[0].HppMap {
+		if hppMap.Pool == betaPoolName {
+			betaHppMap = &evhNode.HttpPolicyRefs[0].HppMap[i]
+		}
+	}
+	g.Expect(betaHppMap).NotTo(gomega.BeNil())
+	g.Expect(*betaHppMap.Cookie.Name).To(gomega.Equal("beta"))
+	g.Expect(*betaHppMap.Cookie.Value).To(gomega.Equal("yes"))
+
+	integrationtest.TeardownHTTPRule(t, rrname)
+	g.Eventually(func() int {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		if len(nodes) != 1 || len(nodes[0].EvhNodes) != 1 {
+			return 0
+		}
+		return len(nodes[0].EvhNodes[0].PoolRefs)
+	}, 10*time.Second).Should(gomega.Equal(1))
+	_, aviModel = objects.SharedAviGraphLister().Get(modelName)
+	evhNode = aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()[0].EvhNodes[0]
+	g.Expect(evhNode.PoolGroupRefs[0].Members).To(gomega.HaveLen(1))
+	g.Expect(*evhNode.PoolGroupRefs[0].Members[0].Ratio).To(gomega.Equal(uint32(100)))
+	g.Expect(evhNode.HttpPolicyRefs[0].HppMap).To(gomega.HaveLen(1))
+
+	TearDownIngressForCacheSyncCheck(t, modelName)
+	for _, svc := range []string{"avisvc-canary", "avisvc-beta"} {
+		integrationtest.DelSVC(t, "default", svc)
+		integrationtest.DelEP(t, "default", svc)
+	}
+}
+
 func TestHTTPRuleWithInvalidPath(t *testing.T) {
 	g := gomega.NewGomegaWithT(t)
 
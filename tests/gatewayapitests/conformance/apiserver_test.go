@@ -0,0 +1,430 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package conformance
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/watch"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	crfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// storedGatewayResources are the Gateway API resources which are served in more than one version. They are
+// stored in v1, the version watched by AKO, and converted on the fly for the clients of the other versions.
+var storedGatewayResources = map[string]bool{
+	"gatewayclasses": true,
+	"gateways":       true,
+	"httproutes":     true,
+}
+
+// apiServer emulates the parts of the kubernetes API server which the conformance suite relies on, on top of
+// an object tracker. The tracker is shared by the fake clientsets used by AKO and the client used by the
+// suite, so that the objects created by the suite are seen by the informers of AKO and the status updated
+// by AKO is seen by the suite. Over the tracker, the apiServer
+//   - converts the Gateway API objects of the served versions to the stored version,
+//   - sets the uid, the creation timestamp and the generation of the created objects,
+//   - sets the defaults of the created Services, with a cluster IP,
+//   - sets the defaults of the CRDs in the spec of the Gateways and the HTTPRoutes,
+//   - bumps the generation of the objects whose spec is updated,
+//   - sets a new resource version on each write, as the informers of AKO skip the updates without one.
+type apiServer struct {
+	k8stesting.ObjectTracker
+	scheme          *runtime.Scheme
+	resourceVersion uint64
+	clusterIPs      uint64
+}
+
+func newAPIServer() *apiServer {
+	scheme := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{
+		clientgoscheme.AddToScheme,
+		gatewayv1.AddToScheme,
+		gatewayv1beta1.AddToScheme,
+		gatewayv1alpha2.AddToScheme,
+	} {
+		if err := addToScheme(scheme); err != nil {
+			panic(err)
+		}
+	}
+	codecs := serializer.NewCodecFactory(scheme)
+	return &apiServer{
+		ObjectTracker: k8stesting.NewObjectTracker(scheme, codecs.UniversalDecoder()),
+		scheme:        scheme,
+	}
+}
+
+// serve makes the fake clientset send all its requests to the apiServer.
+func (s *apiServer) serve(fake *k8stesting.Fake) {
+	fake.ReactionChain = nil
+	fake.WatchReactionChain = nil
+	fake.AddReactor("*", "*", k8stesting.ObjectReaction(s))
+	fake.AddWatchReactor("*", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		w, err := s.Watch(action.GetResource(), action.GetNamespace())
+		if err != nil {
+			return false, nil, err
+		}
+		return true, w, nil
+	})
+}
+
+// newClient returns a controller-runtime client served by the apiServer, with the status of the Gateway API
+// objects as a subresource.
+func (s *apiServer) newClient() client.Client {
+	return crfake.NewClientBuilder().
+		WithScheme(s.scheme).
+		WithObjectTracker(s).
+		WithStatusSubresource(
+			&gatewayv1.GatewayClass{}, &gatewayv1.Gateway{}, &gatewayv1.HTTPRoute{},
+			&gatewayv1beta1.GatewayClass{}, &gatewayv1beta1.Gateway{}, &gatewayv1beta1.HTTPRoute{},
+		).
+		Build()
+}
+
+// guessedResources are the resources whose plural is guessed wrongly from the kind by the controller-runtime
+// client, keyed by the guessed plural.
+var guessedResources = map[string]string{
+	"gatewaies": "gateways",
+}
+
+func storageGVR(gvr schema.GroupVersionResource) schema.GroupVersionResource {
+	if resource, ok := guessedResources[gvr.Resource]; ok {
+		gvr.Resource = resource
+	}
+	if gvr.Group == gatewayv1.GroupName && storedGatewayResources[gvr.Resource] {
+		gvr.Version = gatewayv1.GroupVersion.Version
+	}
+	return gvr
+}
+
+// convert converts the object to the given version of its group.
+func (s *apiServer) convert(obj runtime.Object, version string) (runtime.Object, error) {
+	gvks, _, err := s.scheme.ObjectKinds(obj)
+	if err != nil {
+		return nil, err
+	}
+	gvk := gvks[0]
+	if gvk.Version == version {
+		return obj, nil
+	}
+	gvk.Version = version
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	converted, err := s.scheme.New(gvk)
+	if err != nil {
+		return nil, err
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, converted); err != nil {
+		return nil, err
+	}
+	converted.GetObjectKind().SetGroupVersionKind(gvk)
+	return converted, nil
+}
+
+func (s *apiServer) Add(obj runtime.Object) error {
+	if obj.GetObjectKind().GroupVersionKind().Group == gatewayv1.GroupName {
+		var err error
+		if obj, err = s.convert(obj, gatewayv1.GroupVersion.Version); err != nil {
+			return err
+		}
+	}
+	return s.ObjectTracker.Add(obj)
+}
+
+func (s *apiServer) Get(gvr schema.GroupVersionResource, ns, name string) (runtime.Object, error) {
+	obj, err := s.ObjectTracker.Get(storageGVR(gvr), ns, name)
+	if err != nil {
+		return nil, err
+	}
+	return s.convert(obj, gvr.Version)
+}
+
+func (s *apiServer) List(gvr schema.GroupVersionResource, gvk schema.GroupVersionKind, ns string) (runtime.Object, error) {
+	storedGVR := storageGVR(gvr)
+	if storedGVR == gvr {
+		return s.ObjectTracker.List(gvr, gvk, ns)
+	}
+	storedGVK := gvk
+	storedGVK.Version = storedGVR.Version
+	list, err := s.ObjectTracker.List(storedGVR, storedGVK, ns)
+	if err != nil {
+		return nil, err
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		if items[i], err = s.convert(items[i], gvr.Version); err != nil {
+			return nil, err
+		}
+	}
+	convertedList, err := s.scheme.New(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err != nil {
+		return nil, err
+	}
+	if err := meta.SetList(convertedList, items); err != nil {
+		return nil, err
+	}
+	return convertedList, nil
+}
+
+func (s *apiServer) Create(gvr schema.GroupVersionResource, obj runtime.Object, ns string) error {
+	obj, err := s.convert(obj, storageGVR(gvr).Version)
+	if err != nil {
+		return err
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	if accessor.GetUID() == "" {
+		accessor.SetUID(uuid.NewUUID())
+	}
+	if creationTimestamp := accessor.GetCreationTimestamp(); creationTimestamp.IsZero() {
+		accessor.SetCreationTimestamp(metav1.Now())
+	}
+	if _, hasSpec := getSpec(obj); hasSpec {
+		accessor.SetGeneration(1)
+	}
+	if svc, ok := obj.(*corev1.Service); ok {
+		s.setServiceDefaults(svc)
+	}
+	setGatewayAPIDefaults(obj)
+	accessor.SetResourceVersion(s.nextResourceVersion())
+	return s.ObjectTracker.Create(storageGVR(gvr), obj, ns)
+}
+
+func (s *apiServer) Update(gvr schema.GroupVersionResource, obj runtime.Object, ns string) error {
+	obj, err := s.convert(obj, storageGVR(gvr).Version)
+	if err != nil {
+		return err
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	oldObj, err := s.ObjectTracker.Get(storageGVR(gvr), ns, accessor.GetName())
+	if err != nil {
+		return err
+	}
+	oldAccessor, err := meta.Accessor(oldObj)
+	if err != nil {
+		return err
+	}
+	setGatewayAPIDefaults(obj)
+	generation := oldAccessor.GetGeneration()
+	newSpec, hasSpec := getSpec(obj)
+	if oldSpec, _ := getSpec(oldObj); hasSpec && !reflect.DeepEqual(oldSpec, newSpec) {
+		generation++
+	}
+	accessor.SetGeneration(generation)
+	accessor.SetResourceVersion(s.nextResourceVersion())
+	accessor.SetUID(oldAccessor.GetUID())
+	accessor.SetCreationTimestamp(oldAccessor.GetCreationTimestamp())
+	return s.ObjectTracker.Update(storageGVR(gvr), obj, ns)
+}
+
+func (s *apiServer) Delete(gvr schema.GroupVersionResource, ns, name string) error {
+	return s.ObjectTracker.Delete(storageGVR(gvr), ns, name)
+}
+
+func (s *apiServer) Watch(gvr schema.GroupVersionResource, ns string) (watch.Interface, error) {
+	return s.ObjectTracker.Watch(storageGVR(gvr), ns)
+}
+
+func (s *apiServer) setServiceDefaults(svc *corev1.Service) {
+	if svc.Spec.Type == "" {
+		svc.Spec.Type = corev1.ServiceTypeClusterIP
+	}
+	if svc.Spec.ClusterIP == "" {
+		n := atomic.AddUint64(&s.clusterIPs, 1)
+		svc.Spec.ClusterIP = fmt.Sprintf("10.96.%d.%d", n/250, n%250+1)
+		svc.Spec.ClusterIPs = []string{svc.Spec.ClusterIP}
+	}
+	if len(svc.Spec.IPFamilies) == 0 {
+		svc.Spec.IPFamilies = []corev1.IPFamily{corev1.IPv4Protocol}
+	}
+	if svc.Spec.IPFamilyPolicy == nil {
+		singleStack := corev1.IPFamilyPolicySingleStack
+		svc.Spec.IPFamilyPolicy = &singleStack
+	}
+	for i := range svc.Spec.Ports {
+		if svc.Spec.Ports[i].Protocol == "" {
+			svc.Spec.Ports[i].Protocol = corev1.ProtocolTCP
+		}
+		if svc.Spec.Ports[i].TargetPort.IntVal == 0 && svc.Spec.Ports[i].TargetPort.StrVal == "" {
+			svc.Spec.Ports[i].TargetPort = intstr.FromInt(int(svc.Spec.Ports[i].Port))
+		}
+	}
+}
+
+// setGatewayAPIDefaults sets the defaults of the Gateway API CRDs in the spec of the stored Gateways and
+// HTTPRoutes, which AKO copies to their status, e.g. the group and the kind of the parent references.
+func setGatewayAPIDefaults(obj runtime.Object) {
+	switch obj := obj.(type) {
+	case *gatewayv1.Gateway:
+		for i := range obj.Spec.Listeners {
+			listener := &obj.Spec.Listeners[i]
+			if listener.AllowedRoutes == nil {
+				listener.AllowedRoutes = &gatewayv1.AllowedRoutes{}
+			}
+			if listener.AllowedRoutes.Namespaces == nil {
+				listener.AllowedRoutes.Namespaces = &gatewayv1.RouteNamespaces{}
+			}
+			if listener.AllowedRoutes.Namespaces.From == nil {
+				listener.AllowedRoutes.Namespaces.From = ptr(gatewayv1.NamespacesFromSame)
+			}
+			for j := range listener.AllowedRoutes.Kinds {
+				if listener.AllowedRoutes.Kinds[j].Group == nil {
+					listener.AllowedRoutes.Kinds[j].Group = ptr(gatewayv1.Group(gatewayv1.GroupName))
+				}
+			}
+			if listener.TLS == nil {
+				continue
+			}
+			if listener.TLS.Mode == nil {
+				listener.TLS.Mode = ptr(gatewayv1.TLSModeTerminate)
+			}
+			for j := range listener.TLS.CertificateRefs {
+				certRef := &listener.TLS.CertificateRefs[j]
+				if certRef.Group == nil {
+					certRef.Group = ptr(gatewayv1.Group(""))
+				}
+				if certRef.Kind == nil {
+					certRef.Kind = ptr(gatewayv1.Kind("Secret"))
+				}
+			}
+		}
+		for i := range obj.Spec.Addresses {
+			if obj.Spec.Addresses[i].Type == nil {
+				obj.Spec.Addresses[i].Type = ptr(gatewayv1.IPAddressType)
+			}
+		}
+	case *gatewayv1.HTTPRoute:
+		for i := range obj.Spec.ParentRefs {
+			setParentRefDefaults(&obj.Spec.ParentRefs[i])
+		}
+		if len(obj.Spec.Rules) == 0 {
+			obj.Spec.Rules = []gatewayv1.HTTPRouteRule{{}}
+		}
+		for i := range obj.Spec.Rules {
+			rule := &obj.Spec.Rules[i]
+			if len(rule.Matches) == 0 {
+				rule.Matches = []gatewayv1.HTTPRouteMatch{{}}
+			}
+			for j := range rule.Matches {
+				setHTTPRouteMatchDefaults(&rule.Matches[j])
+			}
+			for j := range rule.Filters {
+				setHTTPRouteFilterDefaults(&rule.Filters[j])
+			}
+			for j := range rule.BackendRefs {
+				backendRef := &rule.BackendRefs[j]
+				setBackendRefDefaults(&backendRef.BackendObjectReference)
+				if backendRef.Weight == nil {
+					backendRef.Weight = ptr(int32(1))
+				}
+				for k := range backendRef.Filters {
+					setHTTPRouteFilterDefaults(&backendRef.Filters[k])
+				}
+			}
+		}
+	}
+}
+
+func setParentRefDefaults(parentRef *gatewayv1.ParentReference) {
+	if parentRef.Group == nil {
+		parentRef.Group = ptr(gatewayv1.Group(gatewayv1.GroupName))
+	}
+	if parentRef.Kind == nil {
+		parentRef.Kind = ptr(gatewayv1.Kind("Gateway"))
+	}
+}
+
+func setBackendRefDefaults(backendRef *gatewayv1.BackendObjectReference) {
+	if backendRef.Group == nil {
+		backendRef.Group = ptr(gatewayv1.Group(""))
+	}
+	if backendRef.Kind == nil {
+		backendRef.Kind = ptr(gatewayv1.Kind("Service"))
+	}
+}
+
+func setHTTPRouteMatchDefaults(match *gatewayv1.HTTPRouteMatch) {
+	if match.Path == nil {
+		match.Path = &gatewayv1.HTTPPathMatch{}
+	}
+	if match.Path.Type == nil {
+		match.Path.Type = ptr(gatewayv1.PathMatchPathPrefix)
+	}
+	if match.Path.Value == nil {
+		match.Path.Value = ptr("/")
+	}
+	for i := range match.Headers {
+		if match.Headers[i].Type == nil {
+			match.Headers[i].Type = ptr(gatewayv1.HeaderMatchExact)
+		}
+	}
+	for i := range match.QueryParams {
+		if match.QueryParams[i].Type == nil {
+			match.QueryParams[i].Type = ptr(gatewayv1.QueryParamMatchExact)
+		}
+	}
+}
+
+func setHTTPRouteFilterDefaults(filter *gatewayv1.HTTPRouteFilter) {
+	if filter.RequestRedirect != nil && filter.RequestRedirect.StatusCode == nil {
+		filter.RequestRedirect.StatusCode = ptr(302)
+	}
+	if filter.RequestMirror != nil {
+		setBackendRefDefaults(&filter.RequestMirror.BackendRef)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func (s *apiServer) nextResourceVersion() string {
+	return strconv.FormatUint(atomic.AddUint64(&s.resourceVersion, 1), 10)
+}
+
+func getSpec(obj runtime.Object) (interface{}, bool) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, false
+	}
+	spec, ok := content["spec"]
+	return spec, ok
+}
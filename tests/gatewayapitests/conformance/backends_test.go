@@ -0,0 +1,244 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package conformance
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+)
+
+const backendLabel = "conformance.ako.vmware.com/deployment"
+
+// backendController emulates the deployment and the endpoints controllers of kubernetes for the echo
+// backends of the suite. It runs the replicas of the Deployments as ready Pods with an IP address each, and
+// adds the Pods selected by the Services to their Endpoints. The Pods are looked up by their address to
+// answer the requests sent to the pool servers.
+type backendController struct {
+	kubeClient kubernetes.Interface
+	lock       sync.RWMutex
+	podsByIP   map[string]types.NamespacedName
+	podIPs     map[types.NamespacedName]string
+	lastIP     int
+}
+
+func newBackendController(kubeClient kubernetes.Interface) *backendController {
+	return &backendController{
+		kubeClient: kubeClient,
+		podsByIP:   make(map[string]types.NamespacedName),
+		podIPs:     make(map[types.NamespacedName]string),
+	}
+}
+
+func (b *backendController) run(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			b.syncPods()
+			b.syncEndpoints()
+		}
+	}
+}
+
+// getPod returns the Pod with the address.
+func (b *backendController) getPod(ip string) (types.NamespacedName, bool) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	pod, ok := b.podsByIP[ip]
+	return pod, ok
+}
+
+func (b *backendController) allocateIP(pod types.NamespacedName) string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if ip, ok := b.podIPs[pod]; ok {
+		return ip
+	}
+	b.lastIP++
+	ip := fmt.Sprintf("10.200.%d.%d", b.lastIP/250, b.lastIP%250+1)
+	b.podIPs[pod] = ip
+	b.podsByIP[ip] = pod
+	return ip
+}
+
+func (b *backendController) releaseIP(pod types.NamespacedName) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	delete(b.podsByIP, b.podIPs[pod])
+	delete(b.podIPs, pod)
+}
+
+func (b *backendController) syncPods() {
+	deployments, err := b.kubeClient.AppsV1().Deployments(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		utils.AviLog.Warnf("conformance: unable to list the Deployments, err: %v", err)
+		return
+	}
+	desiredPods := make(map[types.NamespacedName]*corev1.Pod)
+	for _, deployment := range deployments.Items {
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+		for i := int32(0); i < replicas; i++ {
+			podLabels := make(map[string]string)
+			for k, v := range deployment.Spec.Template.Labels {
+				podLabels[k] = v
+			}
+			podLabels[backendLabel] = deployment.Name
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("%s-%d", deployment.Name, i),
+					Namespace: deployment.Namespace,
+					Labels:    podLabels,
+				},
+				Spec: deployment.Spec.Template.Spec,
+			}
+			desiredPods[types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}] = pod
+		}
+	}
+
+	pods, err := b.kubeClient.CoreV1().Pods(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{LabelSelector: backendLabel})
+	if err != nil {
+		utils.AviLog.Warnf("conformance: unable to list the Pods, err: %v", err)
+		return
+	}
+	for _, pod := range pods.Items {
+		podName := types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}
+		if _, ok := desiredPods[podName]; ok {
+			delete(desiredPods, podName)
+			continue
+		}
+		if err := b.kubeClient.CoreV1().Pods(pod.Namespace).Delete(context.TODO(), pod.Name, metav1.DeleteOptions{}); err != nil {
+			utils.AviLog.Warnf("conformance: unable to delete the Pod %s, err: %v", podName, err)
+			continue
+		}
+		b.releaseIP(podName)
+	}
+	for podName, pod := range desiredPods {
+		pod.Status = corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			PodIP: b.allocateIP(podName),
+			Conditions: []corev1.PodCondition{{
+				Type:   corev1.PodReady,
+				Status: corev1.ConditionTrue,
+			}},
+		}
+		if _, err := b.kubeClient.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{}); err != nil {
+			utils.AviLog.Warnf("conformance: unable to create the Pod %s, err: %v", podName, err)
+		}
+	}
+}
+
+func (b *backendController) syncEndpoints() {
+	services, err := b.kubeClient.CoreV1().Services(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		utils.AviLog.Warnf("conformance: unable to list the Services, err: %v", err)
+		return
+	}
+	for _, svc := range services.Items {
+		if len(svc.Spec.Selector) == 0 {
+			continue
+		}
+		pods, err := b.kubeClient.CoreV1().Pods(svc.Namespace).List(context.TODO(), metav1.ListOptions{
+			LabelSelector: labels.SelectorFromSet(svc.Spec.Selector).String(),
+		})
+		if err != nil {
+			utils.AviLog.Warnf("conformance: unable to list the Pods of the Service %s/%s, err: %v", svc.Namespace, svc.Name, err)
+			continue
+		}
+		subset := corev1.EndpointSubset{}
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			subset.Addresses = append(subset.Addresses, corev1.EndpointAddress{
+				IP: pod.Status.PodIP,
+				TargetRef: &corev1.ObjectReference{
+					Kind:      "Pod",
+					Namespace: pod.Namespace,
+					Name:      pod.Name,
+				},
+			})
+		}
+		sort.Slice(subset.Addresses, func(i, j int) bool {
+			return subset.Addresses[i].IP < subset.Addresses[j].IP
+		})
+		for _, svcPort := range svc.Spec.Ports {
+			subset.Ports = append(subset.Ports, corev1.EndpointPort{
+				Name:        svcPort.Name,
+				Port:        getTargetPort(svcPort, pods.Items),
+				Protocol:    svcPort.Protocol,
+				AppProtocol: svcPort.AppProtocol,
+			})
+		}
+		var subsets []corev1.EndpointSubset
+		if len(subset.Addresses) != 0 {
+			subsets = []corev1.EndpointSubset{subset}
+		}
+
+		ep, err := b.kubeClient.CoreV1().Endpoints(svc.Namespace).Get(context.TODO(), svc.Name, metav1.GetOptions{})
+		if err != nil {
+			ep = &corev1.Endpoints{
+				ObjectMeta: metav1.ObjectMeta{Name: svc.Name, Namespace: svc.Namespace},
+				Subsets:    subsets,
+			}
+			if _, err := b.kubeClient.CoreV1().Endpoints(svc.Namespace).Create(context.TODO(), ep, metav1.CreateOptions{}); err != nil {
+				utils.AviLog.Warnf("conformance: unable to create the Endpoints %s/%s, err: %v", svc.Namespace, svc.Name, err)
+			}
+			continue
+		}
+		if reflect.DeepEqual(ep.Subsets, subsets) {
+			continue
+		}
+		ep.Subsets = subsets
+		if _, err := b.kubeClient.CoreV1().Endpoints(svc.Namespace).Update(context.TODO(), ep, metav1.UpdateOptions{}); err != nil {
+			utils.AviLog.Warnf("conformance: unable to update the Endpoints %s/%s, err: %v", svc.Namespace, svc.Name, err)
+		}
+	}
+}
+
+// getTargetPort returns the container port the port of the Service is sent to.
+func getTargetPort(svcPort corev1.ServicePort, pods []corev1.Pod) int32 {
+	switch {
+	case svcPort.TargetPort.Type == intstr.Int && svcPort.TargetPort.IntVal != 0:
+		return svcPort.TargetPort.IntVal
+	case svcPort.TargetPort.Type == intstr.String && svcPort.TargetPort.StrVal != "":
+		for _, pod := range pods {
+			for _, container := range pod.Spec.Containers {
+				for _, containerPort := range container.Ports {
+					if containerPort.Name == svcPort.TargetPort.StrVal {
+						return containerPort.ContainerPort
+					}
+				}
+			}
+		}
+	}
+	return svcPort.Port
+}
@@ -0,0 +1,305 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+// Package conformance runs the conformance tests of the Gateway API against AKO, with the Avi controller
+// mocked by the fake Avi API server of the integration tests. See the Conformance section of
+// docs/gateway-api/gateway-api-v1beta1.md for the parts of the environment which are emulated.
+package conformance
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	confv1a1 "sigs.k8s.io/gateway-api/conformance/apis/v1alpha1"
+	conformancetests "sigs.k8s.io/gateway-api/conformance/tests"
+	"sigs.k8s.io/gateway-api/conformance/utils/suite"
+	gatewayfake "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned/fake"
+	"sigs.k8s.io/yaml"
+
+	akogatewayapik8s "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/k8s"
+	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	akocrdfake "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha1/clientset/versioned/fake"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	tests "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/gatewayapitests"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+)
+
+const (
+	gatewayClassName = "avi-lb"
+	mockFilePath     = "../../avimockobjects"
+)
+
+var (
+	reportOutput = flag.String("report-output", "", "The file the conformance report is written to")
+	runTest      = flag.String("run-test", "", "The short name of the single conformance test to run")
+)
+
+// supportedFeatures are the features of the HTTP profile which are implemented by AKO.
+var supportedFeatures = sets.New(
+	suite.SupportGateway,
+	suite.SupportHTTPRoute,
+	suite.SupportReferenceGrant,
+	suite.SupportHTTPRouteQueryParamMatching,
+	suite.SupportHTTPRouteMethodMatching,
+	suite.SupportHTTPRouteResponseHeaderModification,
+	suite.SupportHTTPRoutePortRedirect,
+	suite.SupportHTTPRouteSchemeRedirect,
+	suite.SupportHTTPRoutePathRedirect,
+	suite.SupportHTTPRouteHostRewrite,
+	suite.SupportHTTPRoutePathRewrite,
+)
+
+var (
+	suiteClient client.Client
+	backends    *backendController
+)
+
+func TestMain(m *testing.M) {
+	// The suite takes about an hour, hence it is only run to generate the report.
+	flag.Parse()
+	if *reportOutput == "" {
+		os.Exit(m.Run())
+	}
+
+	server := newAPIServer()
+	kubeClient := k8sfake.NewSimpleClientset()
+	server.serve(&kubeClient.Fake)
+	gatewayClient := gatewayfake.NewSimpleClientset()
+	server.serve(&gatewayClient.Fake)
+	suiteClient = server.newClient()
+
+	tests.KubeClient = kubeClient
+	tests.GatewayClient = gatewayClient
+	tests.AKOClient = akocrdfake.NewSimpleClientset()
+	integrationtest.KubeClient = tests.KubeClient
+
+	// Sets the environment variables
+	os.Setenv("CLUSTER_NAME", "cluster")
+	os.Setenv("CLOUD_NAME", "CLOUD_VCENTER")
+	os.Setenv("SEG_NAME", "Default-Group")
+	os.Setenv("POD_NAMESPACE", utils.AKO_DEFAULT_NS)
+	os.Setenv("ENABLE_EVH", "true")
+	os.Setenv("TENANT", "admin")
+	os.Setenv("POD_NAME", "ako-0")
+
+	utils.AviLog.SetLevel("WARN")
+	// Set the user with prefix
+	_ = lib.AKOControlConfig()
+	lib.SetAKOUser(akogatewayapilib.Prefix)
+	lib.SetNamePrefix(akogatewayapilib.Prefix)
+	lib.AKOControlConfig().SetIsLeaderFlag(true)
+	lib.AKOControlConfig().SetAKOInstanceFlag(true)
+	akoControlConfig := akogatewayapilib.AKOControlConfig()
+	akoControlConfig.SetEventRecorder(lib.AKOGatewayEventComponent, tests.KubeClient, true)
+	registeredInformers := []string{
+		utils.ServiceInformer,
+		utils.EndpointInformer,
+		utils.SecretInformer,
+		utils.NSInformer,
+		utils.ConfigMapInformer,
+	}
+	utils.NewInformers(utils.KubeClientIntf{ClientSet: tests.KubeClient}, registeredInformers, make(map[string]interface{}))
+	data := map[string][]byte{
+		"username": []byte("admin"),
+		"password": []byte("admin"),
+	}
+	object := metav1.ObjectMeta{Name: "avi-secret", Namespace: utils.GetAKONamespace()}
+	secret := &corev1.Secret{Data: data, ObjectMeta: object}
+	tests.KubeClient.CoreV1().Secrets(utils.GetAKONamespace()).Create(context.TODO(), secret, metav1.CreateOptions{})
+
+	akoApi := integrationtest.InitializeFakeAKOAPIServer()
+	defer akoApi.ShutDown()
+
+	tests.NewAviFakeClientInstance(tests.KubeClient)
+	defer integrationtest.AviFakeClientInstance.Close()
+	integrationtest.AddMiddleware(newVIPAllocator().serve)
+
+	ctrl := akogatewayapik8s.SharedGatewayController()
+	ctrl.DisableSync = false
+	ctrl.InitGatewayAPIInformers(tests.GatewayClient, tests.KubeClient)
+	akoControlConfig.SetGatewayAPIClientset(tests.GatewayClient)
+	ctrl.InitAKOCRDInformers(tests.AKOClient)
+	akoControlConfig.SetAKOCRDClientset(tests.AKOClient)
+
+	stopCh := utils.SetupSignalHandler()
+	ctrlCh := make(chan struct{})
+	quickSyncCh := make(chan struct{})
+
+	waitGroupMap := make(map[string]*sync.WaitGroup)
+	wgIngestion := &sync.WaitGroup{}
+	waitGroupMap["ingestion"] = wgIngestion
+	wgFastRetry := &sync.WaitGroup{}
+	waitGroupMap["fastretry"] = wgFastRetry
+	wgSlowRetry := &sync.WaitGroup{}
+	waitGroupMap["slowretry"] = wgSlowRetry
+	wgGraph := &sync.WaitGroup{}
+	waitGroupMap["graph"] = wgGraph
+	wgStatus := &sync.WaitGroup{}
+	waitGroupMap["status"] = wgStatus
+
+	integrationtest.AddConfigMap(tests.KubeClient)
+	go ctrl.InitController(k8s.K8sinformers{Cs: tests.KubeClient}, registeredInformers, ctrlCh, stopCh, quickSyncCh, waitGroupMap)
+
+	backends = newBackendController(tests.KubeClient)
+	go backends.run(stopCh)
+
+	gatewayClass := &gatewayv1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{Name: gatewayClassName},
+		Spec:       gatewayv1.GatewayClassSpec{ControllerName: akogatewayapilib.GatewayController},
+	}
+	if _, err := tests.GatewayClient.GatewayV1().GatewayClasses().Create(context.TODO(), gatewayClass, metav1.CreateOptions{}); err != nil {
+		fmt.Fprintf(os.Stderr, "unable to create the GatewayClass %s, err: %v\n", gatewayClassName, err)
+		os.Exit(1)
+	}
+	os.Exit(m.Run())
+}
+
+func TestGatewayAPIConformance(t *testing.T) {
+	if *reportOutput == "" {
+		t.Skip("the conformance report output is not set")
+	}
+	// RunTest is not supported by the experimental suite, hence the other tests are skipped.
+	var skipTests []string
+	for _, test := range conformancetests.ConformanceTests {
+		if *runTest != "" && test.ShortName != *runTest {
+			skipTests = append(skipTests, test.ShortName)
+		}
+	}
+	cSuite, err := suite.NewExperimentalConformanceTestSuite(suite.ExperimentalConformanceOptions{
+		Options: suite.Options{
+			Client:               suiteClient,
+			Clientset:            tests.KubeClient,
+			GatewayClassName:     gatewayClassName,
+			RoundTripper:         newAviDataPath(backends),
+			SupportedFeatures:    supportedFeatures,
+			CleanupBaseResources: false,
+			SkipTests:            skipTests,
+		},
+		Implementation: confv1a1.Implementation{
+			Organization: "vmware",
+			Project:      "load-balancer-and-ingress-services-for-kubernetes",
+			URL:          "https://github.com/vmware/load-balancer-and-ingress-services-for-kubernetes",
+			Version:      "mock-avi-controller",
+			Contact:      []string{"@vmware/load-balancer-and-ingress-services-for-kubernetes"},
+		},
+		ConformanceProfiles: sets.New(suite.HTTPConformanceProfileName),
+	})
+	if err != nil {
+		t.Fatalf("unable to create the conformance test suite, err: %v", err)
+	}
+	// The base resources of the suite are set up in a subtest, so that the tests are run and reported even
+	// if some of the base Gateways are not accepted. They are not cleaned up, as the cleanup of the subtest
+	// would delete them before the tests are run.
+	t.Run("Setup", cSuite.Setup)
+	if err := cSuite.Run(t, conformancetests.ConformanceTests); err != nil {
+		t.Fatalf("unable to run the conformance tests, err: %v", err)
+	}
+
+	report, err := cSuite.Report()
+	if err != nil {
+		t.Fatalf("unable to generate the conformance report, err: %v", err)
+	}
+	content, err := yaml.Marshal(report)
+	if err != nil {
+		t.Fatalf("unable to marshal the conformance report, err: %v", err)
+	}
+	if err := os.WriteFile(*reportOutput, content, 0644); err != nil {
+		t.Fatalf("unable to write the conformance report, err: %v", err)
+	}
+}
+
+// vipAllocator allocates a different address to each vsvip created in the fake Avi API server, which
+// otherwise allocates the same address to all of them, so that the requests sent to a Gateway reach it.
+type vipAllocator struct {
+	lock  sync.Mutex
+	addrs map[string]string
+}
+
+func newVIPAllocator() *vipAllocator {
+	return &vipAllocator{addrs: make(map[string]string)}
+}
+
+func (v *vipAllocator) allocate(name string) string {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	if addr, ok := v.addrs[name]; ok {
+		return addr
+	}
+	n := len(v.addrs) + 1
+	addr := fmt.Sprintf("10.100.%d.%d", n/250, n%250+1)
+	v.addrs[name] = addr
+	return addr
+}
+
+// setVIP sets the allocated address of the vsvip in the response of the fake Avi API server.
+func (v *vipAllocator) setVIP(obj map[string]interface{}) {
+	vips, ok := obj["vip"].([]interface{})
+	if !ok || len(vips) == 0 {
+		return
+	}
+	if vip, ok := vips[0].(map[string]interface{}); ok {
+		name, _ := obj["name"].(string)
+		vip["ip_address"] = map[string]string{"addr": v.allocate(name), "type": "V4"}
+	}
+}
+
+func (v *vipAllocator) serve(w http.ResponseWriter, r *http.Request) {
+	// url = //api/<object>/:objectId, the vsvips are also created in the macro calls.
+	var object string
+	if urlSlice := strings.Split(strings.Trim(r.URL.EscapedPath(), "/"), "/"); len(urlSlice) > 1 {
+		object = urlSlice[1]
+	}
+	if (object != "vsvip" && object != "macro") || (r.Method != http.MethodPost && r.Method != http.MethodPut) {
+		integrationtest.NormalControllerServer(w, r, mockFilePath)
+		return
+	}
+	recorder := httptest.NewRecorder()
+	integrationtest.NormalControllerServer(recorder, r, mockFilePath)
+	body := recorder.Body.Bytes()
+	if object == "vsvip" {
+		var resp map[string]interface{}
+		if err := json.Unmarshal(body, &resp); err == nil {
+			v.setVIP(resp)
+			body, _ = json.Marshal(resp)
+		}
+	} else {
+		var resp []map[string]interface{}
+		if err := json.Unmarshal(body, &resp); err == nil {
+			for _, obj := range resp {
+				if url, _ := obj["url"].(string); strings.Contains(url, "/api/vsvip/") {
+					v.setVIP(obj)
+				}
+			}
+			body, _ = json.Marshal(resp)
+		}
+	}
+	w.WriteHeader(recorder.Code)
+	w.Write(body)
+}
@@ -0,0 +1,730 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package conformance
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/vmware/alb-sdk/go/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/gateway-api/conformance/utils/roundtripper"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	tests "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/gatewayapitests"
+)
+
+const maxRedirects = 10
+
+// aviDataPath is the RoundTripper of the suite. The mock Avi controller doesn't forward any traffic, hence
+// the requests are answered by evaluating them, the way a Service Engine does, against the Avi objects AKO
+// built for the Gateway which has the address the request is sent to:
+//   - the parent virtualservice has to listen on the port, with SSL enabled for HTTPS,
+//   - the child virtualservice is selected by its vhmatch. When several children match, the most specific
+//     match wins, following the precedence of the Gateway API: exact hostnames, then exact paths, then the
+//     longest path prefixes, then the method, the number of header and query matches,
+//   - the first matching HTTP request rule of the child redirects, or modifies the headers and the URL,
+//   - the member of the poolgroup and the server of the pool are selected round robin, by their ratio,
+//   - the response is the one of the echo backend running at the address of the server, which is modified
+//     by the HTTP response rules of the child.
+//
+// Only the virtualservices which are created in the Avi controller, i.e. which are in the Avi object cache,
+// serve the requests.
+type aviDataPath struct {
+	backends *backendController
+	lock     sync.Mutex
+	counters map[string]int
+}
+
+func newAviDataPath(backends *backendController) *aviDataPath {
+	return &aviDataPath{
+		backends: backends,
+		counters: make(map[string]int),
+	}
+}
+
+// httpRequest is the request as seen by the Service Engine.
+type httpRequest struct {
+	scheme  string
+	host    string
+	port    int32
+	method  string
+	path    string
+	query   string
+	headers http.Header
+}
+
+func (d *aviDataPath) CaptureRoundTrip(request roundtripper.Request) (*roundtripper.CapturedRequest, *roundtripper.CapturedResponse, error) {
+	for i := 0; i < maxRedirects; i++ {
+		cReq, cRes, err := d.roundTrip(request)
+		if err != nil || request.UnfollowRedirect || !roundtripper.IsRedirect(cRes.StatusCode) {
+			return cReq, cRes, err
+		}
+		redirect := cRes.RedirectRequest
+		request.URL = url.URL{Scheme: redirect.Scheme, Host: redirect.Host, Path: redirect.Path}
+		if redirect.Port != "" {
+			request.URL.Host = net.JoinHostPort(redirect.Host, redirect.Port)
+		}
+		request.Host = ""
+	}
+	return nil, nil, fmt.Errorf("stopped after %d redirects", maxRedirects)
+}
+
+func (d *aviDataPath) roundTrip(request roundtripper.Request) (*roundtripper.CapturedRequest, *roundtripper.CapturedResponse, error) {
+	req, err := newHTTPRequest(request)
+	if err != nil {
+		return nil, nil, err
+	}
+	addr, _, _ := net.SplitHostPort(request.URL.Host)
+	if addr == "" {
+		addr = request.URL.Hostname()
+	}
+	parentVS, err := getParentVS(addr, req.port, req.scheme)
+	if err != nil {
+		return nil, nil, err
+	}
+	if req.scheme == "https" {
+		if err := verifyServerCertificate(parentVS, request); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	childVS := getChildVS(parentVS, req)
+	if childVS == nil {
+		return newCapturedResponse(req, http.StatusNotFound)
+	}
+	for _, policy := range childVS.HttpPolicyRefs {
+		rule := getRequestRule(policy.RequestRules, req)
+		if rule == nil {
+			continue
+		}
+		if rule.RedirectAction != nil {
+			return newRedirectResponse(req, rule.RedirectAction)
+		}
+		applyHdrActions(req.headers, rule.HdrAction)
+		if rewrite := rule.RewriteURLAction; rewrite != nil {
+			if rewrite.Path != nil {
+				req.path = "/" + buildURIParam(rewrite.Path, req)
+			}
+			if rewrite.HostHdr != nil {
+				req.host = buildURIParam(rewrite.HostHdr, req)
+			}
+		}
+	}
+
+	server, err := d.getPoolServer(childVS)
+	if err != nil {
+		return newCapturedResponse(req, http.StatusServiceUnavailable)
+	}
+	pod, ok := d.backends.getPod(server)
+	if !ok {
+		return newCapturedResponse(req, http.StatusServiceUnavailable)
+	}
+
+	path := req.path
+	if req.query != "" {
+		path += "?" + req.query
+	}
+	cReq := &roundtripper.CapturedRequest{
+		Path:      path,
+		Host:      req.host,
+		Method:    req.method,
+		Protocol:  "HTTP/1.1",
+		Headers:   req.headers,
+		Namespace: pod.Namespace,
+		Pod:       pod.Name,
+	}
+	responseHeaders := http.Header{}
+	responseHeaders.Set("Content-Type", "application/json")
+	for _, header := range strings.Split(req.headers.Get("X-Echo-Set-Header"), ",") {
+		if name, value, ok := strings.Cut(header, ":"); ok {
+			responseHeaders.Add(name, value)
+		}
+	}
+	for _, policy := range childVS.HttpPolicyRefs {
+		for _, rule := range policy.ResponseRules {
+			if rule.Enable != nil && !*rule.Enable {
+				continue
+			}
+			applyHdrActions(responseHeaders, rule.HdrAction)
+			break
+		}
+	}
+	cRes := &roundtripper.CapturedResponse{
+		StatusCode: http.StatusOK,
+		Protocol:   "HTTP/1.1",
+		Headers:    responseHeaders,
+	}
+	return cReq, cRes, nil
+}
+
+func newHTTPRequest(request roundtripper.Request) (*httpRequest, error) {
+	req := &httpRequest{
+		scheme:  strings.ToLower(request.URL.Scheme),
+		method:  request.Method,
+		path:    request.URL.Path,
+		query:   request.URL.RawQuery,
+		headers: http.Header{},
+	}
+	if req.method == "" {
+		req.method = http.MethodGet
+	}
+	if req.path == "" {
+		req.path = "/"
+	}
+	port := request.URL.Port()
+	if port == "" {
+		port = "80"
+		if req.scheme == "https" {
+			port = "443"
+		}
+	}
+	portNum, err := strconv.Atoi(port)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port %s", port)
+	}
+	req.port = int32(portNum)
+	req.host = request.Host
+	if req.host == "" {
+		req.host = request.URL.Host
+	}
+	for name, values := range request.Headers {
+		req.headers.Set(name, values[0])
+	}
+	return req, nil
+}
+
+// hostname returns the host header without the port.
+func (req *httpRequest) hostname() string {
+	if host, _, err := net.SplitHostPort(req.host); err == nil {
+		return host
+	}
+	return req.host
+}
+
+// getParentVS returns the parent virtualservice of the Gateway which has the address, if it listens on the port.
+func getParentVS(addr string, port int32, scheme string) (*avinodes.AviEvhVsNode, error) {
+	gateways, err := tests.GatewayClient.GatewayV1().Gateways(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var modelName string
+	for _, gw := range gateways.Items {
+		for _, gwAddr := range gw.Status.Addresses {
+			if gwAddr.Value == addr {
+				modelName, _ = tests.GetModelName(gw.Namespace, gw.Name)
+			}
+		}
+	}
+	connRefused := fmt.Errorf("dial tcp %s: connect: connection refused", net.JoinHostPort(addr, strconv.Itoa(int(port))))
+	if modelName == "" {
+		return nil, connRefused
+	}
+	found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	if !found || aviModel == nil {
+		return nil, connRefused
+	}
+	parentVSes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+	if len(parentVSes) == 0 || !isInAviCache(parentVSes[0]) {
+		return nil, connRefused
+	}
+	for _, portProto := range parentVSes[0].PortProto {
+		if portProto.Port == port && portProto.EnableSSL == (scheme == "https") {
+			return parentVSes[0], nil
+		}
+	}
+	return nil, connRefused
+}
+
+func isInAviCache(vs *avinodes.AviEvhVsNode) bool {
+	_, ok := cache.SharedAviObjCache().VsCacheMeta.AviCacheGet(cache.NamespaceName{Namespace: vs.Tenant, Name: vs.Name})
+	return ok
+}
+
+// verifyServerCertificate verifies the certificate the parent virtualservice presents for the server name
+// of the request, with the certificate of the request as the trusted CA.
+func verifyServerCertificate(parentVS *avinodes.AviEvhVsNode, request roundtripper.Request) error {
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(request.CertPem) {
+		return errors.New("tls: failed to verify certificate: x509: certificate signed by unknown authority")
+	}
+	serverName := request.Server
+	if serverName == "" {
+		serverName = request.URL.Hostname()
+	}
+	var verifyErr error
+	for _, keyCert := range parentVS.SSLKeyCertRefs {
+		block, _ := pem.Decode(keyCert.Cert)
+		if block == nil {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+		if _, verifyErr = cert.Verify(x509.VerifyOptions{DNSName: serverName, Roots: roots}); verifyErr == nil {
+			return nil
+		}
+	}
+	if verifyErr == nil {
+		verifyErr = errors.New("remote error: tls: unrecognized name")
+	}
+	return fmt.Errorf("tls: failed to verify certificate: %w", verifyErr)
+}
+
+// vhMatchCandidate is a rule of the vhmatch of a child virtualservice, which matches the request.
+type vhMatchCandidate struct {
+	childVS *avinodes.AviEvhVsNode
+	host    string
+	match   *models.MatchTarget
+}
+
+// precedes returns true if the match of the candidate is more specific than the match of the other candidate.
+func (c vhMatchCandidate) precedes(o vhMatchCandidate) bool {
+	cWildcard, oWildcard := strings.HasPrefix(c.host, "*"), strings.HasPrefix(o.host, "*")
+	if cWildcard != oWildcard {
+		return oWildcard
+	}
+	if len(c.host) != len(o.host) {
+		return len(c.host) > len(o.host)
+	}
+	cPathRank, cPathLen := getPathRank(c.match)
+	oPathRank, oPathLen := getPathRank(o.match)
+	if cPathRank != oPathRank {
+		return cPathRank > oPathRank
+	}
+	if cPathLen != oPathLen {
+		return cPathLen > oPathLen
+	}
+	if (c.match.Method != nil) != (o.match.Method != nil) {
+		return c.match.Method != nil
+	}
+	if len(c.match.Hdrs) != len(o.match.Hdrs) {
+		return len(c.match.Hdrs) > len(o.match.Hdrs)
+	}
+	return getQueryMatchCount(c.match) > getQueryMatchCount(o.match)
+}
+
+func getPathRank(match *models.MatchTarget) (int, int) {
+	if match.Path == nil || match.Path.MatchCriteria == nil || len(match.Path.MatchStr) == 0 {
+		return 1, 0
+	}
+	switch *match.Path.MatchCriteria {
+	case "EQUALS":
+		return 3, len(match.Path.MatchStr[0])
+	case "BEGINS_WITH":
+		return 2, len(match.Path.MatchStr[0])
+	}
+	return 0, 0
+}
+
+// getQueryMatchCount returns the number of the query params the query of the match checks.
+func getQueryMatchCount(match *models.MatchTarget) int {
+	if match.Query == nil {
+		return 0
+	}
+	count := 0
+	for _, matchStr := range match.Query.MatchStr {
+		count += strings.Count(matchStr, "(?=")
+	}
+	if count == 0 {
+		count = 1
+	}
+	return count
+}
+
+func getChildVS(parentVS *avinodes.AviEvhVsNode, req *httpRequest) *avinodes.AviEvhVsNode {
+	var candidates []vhMatchCandidate
+	for _, childVS := range parentVS.EvhNodes {
+		if !isInAviCache(childVS) {
+			continue
+		}
+		for _, vhMatch := range childVS.VHMatches {
+			if vhMatch.Host == nil || !isHostMatched(*vhMatch.Host, req.hostname()) {
+				continue
+			}
+			for _, rule := range vhMatch.Rules {
+				match := rule.Matches
+				if match == nil {
+					match = &models.MatchTarget{}
+				}
+				if isMatched(match, req) {
+					candidates = append(candidates, vhMatchCandidate{childVS: childVS, host: *vhMatch.Host, match: match})
+				}
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].precedes(candidates[j])
+	})
+	return candidates[0].childVS
+}
+
+func isHostMatched(vhHost, host string) bool {
+	vhHost, host = strings.ToLower(vhHost), strings.ToLower(host)
+	if strings.HasPrefix(vhHost, "*") {
+		return strings.HasSuffix(host, vhHost[1:]) && len(host) > len(vhHost)-1
+	}
+	return vhHost == host
+}
+
+func getRequestRule(rules []*models.HTTPRequestRule, req *httpRequest) *models.HTTPRequestRule {
+	sorted := make([]*models.HTTPRequestRule, len(rules))
+	copy(sorted, rules)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Index != nil && sorted[j].Index != nil && *sorted[i].Index < *sorted[j].Index
+	})
+	for _, rule := range sorted {
+		if rule.Enable != nil && !*rule.Enable {
+			continue
+		}
+		if rule.Match == nil || isMatched(rule.Match, req) {
+			return rule
+		}
+	}
+	return nil
+}
+
+func isMatched(match *models.MatchTarget, req *httpRequest) bool {
+	if match.Path != nil && !isPathMatched(match.Path, req.path) {
+		return false
+	}
+	for _, hdr := range match.Hdrs {
+		if !isHdrMatched(hdr, req.headers) {
+			return false
+		}
+	}
+	if match.Query != nil && !isQueryMatched(match.Query, req.query) {
+		return false
+	}
+	if match.Method != nil {
+		matched := false
+		for _, method := range match.Method.Methods {
+			if method == "HTTP_METHOD_"+req.method {
+				matched = true
+			}
+		}
+		if matched == (match.Method.MatchCriteria != nil && *match.Method.MatchCriteria == "IS_NOT_IN") {
+			return false
+		}
+	}
+	return true
+}
+
+func isSensitive(matchCase *string) bool {
+	return matchCase == nil || *matchCase == "SENSITIVE"
+}
+
+func isPathMatched(pathMatch *models.PathMatch, path string) bool {
+	if !isSensitive(pathMatch.MatchCase) {
+		path = strings.ToLower(path)
+	}
+	criteria := ""
+	if pathMatch.MatchCriteria != nil {
+		criteria = *pathMatch.MatchCriteria
+	}
+	for _, matchStr := range pathMatch.MatchStr {
+		if !isSensitive(pathMatch.MatchCase) {
+			matchStr = strings.ToLower(matchStr)
+		}
+		switch criteria {
+		case "BEGINS_WITH":
+			if strings.HasPrefix(path, matchStr) {
+				return true
+			}
+		case "EQUALS":
+			if path == matchStr {
+				return true
+			}
+		case "REGEX_MATCH":
+			if matched, _ := isRegexMatched(matchStr, path); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isHdrMatched(hdrMatch *models.HdrMatch, headers http.Header) bool {
+	values, found := headers[http.CanonicalHeaderKey(*hdrMatch.Hdr)]
+	criteria := ""
+	if hdrMatch.MatchCriteria != nil {
+		criteria = *hdrMatch.MatchCriteria
+	}
+	switch criteria {
+	case "HDR_EXISTS":
+		return found
+	case "HDR_DOES_NOT_EXIST":
+		return !found
+	}
+	for _, value := range values {
+		for _, matchValue := range hdrMatch.Value {
+			if !isSensitive(hdrMatch.MatchCase) {
+				value, matchValue = strings.ToLower(value), strings.ToLower(matchValue)
+			}
+			switch criteria {
+			case "HDR_EQUALS":
+				if value == matchValue {
+					return true
+				}
+			case "HDR_BEGINS_WITH":
+				if strings.HasPrefix(value, matchValue) {
+					return true
+				}
+			case "HDR_CONTAINS":
+				if strings.Contains(value, matchValue) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func isQueryMatched(queryMatch *models.QueryMatch, query string) bool {
+	criteria := ""
+	if queryMatch.MatchCriteria != nil {
+		criteria = *queryMatch.MatchCriteria
+	}
+	for _, matchStr := range queryMatch.MatchStr {
+		switch criteria {
+		case "QUERY_MATCH_CONTAINS":
+			if strings.Contains(query, matchStr) {
+				return true
+			}
+		case "QUERY_MATCH_EQUALS":
+			if query == matchStr {
+				return true
+			}
+		case "QUERY_MATCH_REGEX_MATCH":
+			if matched, _ := isRegexMatched(matchStr, query); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isRegexMatched matches the string with the PCRE regex of a match. The lookaheads at the start of an
+// anchored regex, which RE2 doesn't support, are matched as regexes anchored at the start of the string.
+func isRegexMatched(pattern, s string) (bool, error) {
+	if !strings.HasPrefix(pattern, "^(?=") {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return false, err
+		}
+		return re.MatchString(s), nil
+	}
+	rest := pattern[1:]
+	for strings.HasPrefix(rest, "(?=") {
+		end := findGroupEnd(rest)
+		if end < 0 {
+			return false, fmt.Errorf("unbalanced lookahead in %s", pattern)
+		}
+		re, err := regexp.Compile("^(?:" + rest[len("(?="):end] + ")")
+		if err != nil {
+			return false, err
+		}
+		if !re.MatchString(s) {
+			return false, nil
+		}
+		rest = rest[end+1:]
+	}
+	re, err := regexp.Compile("^(?:" + rest + ")")
+	if err != nil {
+		return false, err
+	}
+	return re.MatchString(s), nil
+}
+
+// findGroupEnd returns the index of the parenthesis closing the group the pattern starts with.
+func findGroupEnd(pattern string) int {
+	depth, inClass := 0, false
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '\\':
+			i++
+		case inClass:
+			inClass = c != ']'
+		case c == '[':
+			inClass = true
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func applyHdrActions(headers http.Header, hdrActions []*models.HTTPHdrAction) {
+	for _, hdrAction := range hdrActions {
+		if hdrAction.Action == nil || hdrAction.Hdr == nil || hdrAction.Hdr.Name == nil {
+			continue
+		}
+		value := ""
+		if hdrAction.Hdr.Value != nil && hdrAction.Hdr.Value.Val != nil {
+			value = *hdrAction.Hdr.Value.Val
+		}
+		switch *hdrAction.Action {
+		case "HTTP_ADD_HDR":
+			headers.Add(*hdrAction.Hdr.Name, value)
+		case "HTTP_REPLACE_HDR":
+			headers.Set(*hdrAction.Hdr.Name, value)
+		case "HTTP_REMOVE_HDR":
+			headers.Del(*hdrAction.Hdr.Name)
+		}
+	}
+}
+
+// buildURIParam builds the path or the host of the tokenized URI param. The tokens are joined with "/".
+func buildURIParam(uriParam *models.URIParam, req *httpRequest) string {
+	var segments []string
+	for _, segment := range strings.Split(req.path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	var tokens []string
+	for _, token := range uriParam.Tokens {
+		if token.Type == nil {
+			continue
+		}
+		switch *token.Type {
+		case "URI_TOKEN_TYPE_STRING":
+			if token.StrValue != nil && *token.StrValue != "" {
+				tokens = append(tokens, strings.Trim(*token.StrValue, "/"))
+			}
+		case "URI_TOKEN_TYPE_PATH":
+			for i := int(token.StartIndex); i <= int(token.EndIndex) && i < len(segments); i++ {
+				tokens = append(tokens, segments[i])
+			}
+		case "URI_TOKEN_TYPE_HOST":
+			tokens = append(tokens, req.hostname())
+		}
+	}
+	return strings.Join(tokens, "/")
+}
+
+func newRedirectResponse(req *httpRequest, redirect *models.HTTPRedirectAction) (*roundtripper.CapturedRequest, *roundtripper.CapturedResponse, error) {
+	scheme := req.scheme
+	if redirect.Protocol != nil {
+		scheme = strings.ToLower(*redirect.Protocol)
+	}
+	host := req.hostname()
+	if redirect.Host != nil {
+		host = buildURIParam(redirect.Host, req)
+	}
+	path := req.path
+	if redirect.Path != nil {
+		path = "/" + buildURIParam(redirect.Path, req)
+	}
+	location := url.URL{Scheme: scheme, Host: host, Path: path}
+	if redirect.Port != 0 && !isDefaultPort(scheme, redirect.Port) {
+		location.Host = net.JoinHostPort(host, strconv.Itoa(int(redirect.Port)))
+	}
+	if redirect.KeepQuery == nil || *redirect.KeepQuery {
+		location.RawQuery = req.query
+	}
+	statusCode := http.StatusFound
+	if redirect.StatusCode != nil {
+		statusCode, _ = strconv.Atoi(strings.TrimPrefix(*redirect.StatusCode, "HTTP_REDIRECT_STATUS_CODE_"))
+	}
+	cReq, cRes, err := newCapturedResponse(req, statusCode)
+	http.Header(cRes.Headers).Set("Location", location.String())
+	cRes.RedirectRequest = &roundtripper.RedirectRequest{
+		Scheme: location.Scheme,
+		Host:   location.Hostname(),
+		Port:   location.Port(),
+		Path:   location.Path,
+	}
+	return cReq, cRes, err
+}
+
+func isDefaultPort(scheme string, port uint32) bool {
+	return (scheme == "http" && port == 80) || (scheme == "https" && port == 443)
+}
+
+// newCapturedResponse returns the response generated by the Service Engine, which isn't echoed.
+func newCapturedResponse(req *httpRequest, statusCode int) (*roundtripper.CapturedRequest, *roundtripper.CapturedResponse, error) {
+	return &roundtripper.CapturedRequest{Method: req.method}, &roundtripper.CapturedResponse{
+		StatusCode: statusCode,
+		Protocol:   "HTTP/1.1",
+		Headers:    http.Header{},
+	}, nil
+}
+
+// getPoolServer returns the address of the server the request is load balanced to.
+func (d *aviDataPath) getPoolServer(childVS *avinodes.AviEvhVsNode) (string, error) {
+	if len(childVS.PoolGroupRefs) == 0 {
+		return "", errors.New("no poolgroup")
+	}
+	pg := childVS.PoolGroupRefs[0]
+	var poolNames []string
+	for _, member := range pg.Members {
+		ratio := uint32(1)
+		if member.Ratio != nil {
+			ratio = *member.Ratio
+		}
+		if member.PoolRef == nil {
+			continue
+		}
+		_, poolName, _ := strings.Cut(*member.PoolRef, "name=")
+		for i := uint32(0); i < ratio; i++ {
+			poolNames = append(poolNames, poolName)
+		}
+	}
+	if len(poolNames) == 0 {
+		return "", errors.New("no pool")
+	}
+	poolName := poolNames[d.next(pg.Name)%len(poolNames)]
+	for _, pool := range childVS.PoolRefs {
+		if pool.Name != poolName || len(pool.Servers) == 0 {
+			continue
+		}
+		server := pool.Servers[d.next(pool.Name)%len(pool.Servers)]
+		if server.Ip.Addr == nil {
+			break
+		}
+		return *server.Ip.Addr, nil
+	}
+	return "", errors.New("no server")
+}
+
+func (d *aviDataPath) next(name string) int {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.counters[name]++
+	return d.counters[name]
+}
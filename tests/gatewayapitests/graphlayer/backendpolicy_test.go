@@ -0,0 +1,196 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package graphlayer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	akov1alpha1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha1"
+	akogatewayapitests "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/gatewayapitests"
+)
+
+/* Test cases
+ * - BackendPolicy CRUD on the backend Service of an HTTPRoute
+ * - BackendPolicy on an HTTPRoute rule overrides the BackendPolicy on the Gateway
+ */
+func TestBackendPolicyOnService(t *testing.T) {
+
+	gatewayName := "gateway-bp-01"
+	gatewayClassName := "gateway-class-bp-01"
+	httpRouteName := "http-route-bp-01"
+	policyName := "backend-policy-01"
+	svcName := "avisvc-bp-01"
+	ports := []int32{8080}
+	modelName, _ := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	setupL4RouteBackend(t, svcName, 8080, corev1.ProtocolTCP)
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	rule := akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo"}, []string{}, nil,
+		[][]string{{svcName, DEFAULT_NAMESPACE, "8080", "1"}})
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	akogatewayapitests.SetupHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, []gatewayv1.HTTPRouteRule{rule})
+
+	g.Eventually(func() bool {
+		return getHTTPRoutePool(modelName) != nil
+	}, 25*time.Second).Should(gomega.Equal(true))
+	pool := getHTTPRoutePool(modelName)
+	g.Expect(pool.LbAlgorithm).To(gomega.BeNil())
+	g.Expect(pool.ApplicationPersistenceProfileRef).To(gomega.BeNil())
+
+	spec := akov1alpha1.BackendPolicySpec{
+		TargetRef: akov1alpha1.BackendPolicyTargetRef{
+			Kind: "Service",
+			Name: svcName,
+		},
+		LoadBalancerPolicy: akov1alpha1.BackendPolicyLBPolicy{
+			Algorithm:  lib.LB_ALGORITHM_CONSISTENT_HASH,
+			Hash:       lib.LB_ALGORITHM_CONSISTENT_HASH_CUSTOM_HEADER,
+			HostHeader: "x-user-id",
+		},
+		HealthMonitors:         []string{"thisisaviref-hm1"},
+		ApplicationPersistence: "thisisaviref-persistence",
+	}
+	akogatewayapitests.SetupBackendPolicy(t, policyName, DEFAULT_NAMESPACE, spec)
+
+	g.Eventually(func() bool {
+		pool := getHTTPRoutePool(modelName)
+		return pool != nil && pool.LbAlgorithm != nil
+	}, 25*time.Second).Should(gomega.Equal(true))
+	pool = getHTTPRoutePool(modelName)
+	g.Expect(*pool.LbAlgorithm).To(gomega.Equal(lib.LB_ALGORITHM_CONSISTENT_HASH))
+	g.Expect(*pool.LbAlgorithmHash).To(gomega.Equal(lib.LB_ALGORITHM_CONSISTENT_HASH_CUSTOM_HEADER))
+	g.Expect(*pool.LbAlgorithmConsistentHashHdr).To(gomega.Equal("x-user-id"))
+	g.Expect(pool.HealthMonitorRefs).To(gomega.ContainElement("/api/healthmonitor?name=thisisaviref-hm1"))
+	g.Expect(*pool.ApplicationPersistenceProfileRef).To(gomega.Equal("/api/applicationpersistenceprofile?name=thisisaviref-persistence"))
+
+	spec.LoadBalancerPolicy = akov1alpha1.BackendPolicyLBPolicy{Algorithm: "LB_ALGORITHM_LEAST_CONNECTIONS"}
+	akogatewayapitests.UpdateBackendPolicy(t, policyName, DEFAULT_NAMESPACE, spec)
+	g.Eventually(func() bool {
+		pool := getHTTPRoutePool(modelName)
+		return pool != nil && pool.LbAlgorithm != nil && *pool.LbAlgorithm == "LB_ALGORITHM_LEAST_CONNECTIONS"
+	}, 25*time.Second).Should(gomega.Equal(true))
+	pool = getHTTPRoutePool(modelName)
+	g.Expect(pool.LbAlgorithmHash).To(gomega.BeNil())
+
+	akogatewayapitests.TeardownBackendPolicy(t, policyName, DEFAULT_NAMESPACE)
+	g.Eventually(func() bool {
+		pool := getHTTPRoutePool(modelName)
+		return pool != nil && pool.LbAlgorithm == nil
+	}, 25*time.Second).Should(gomega.Equal(true))
+	pool = getHTTPRoutePool(modelName)
+	g.Expect(pool.HealthMonitorRefs).NotTo(gomega.ContainElement("/api/healthmonitor?name=thisisaviref-hm1"))
+	g.Expect(pool.ApplicationPersistenceProfileRef).To(gomega.BeNil())
+
+	akogatewayapitests.TeardownHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE)
+	teardownL4RouteBackend(t, svcName)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
+
+func TestBackendPolicyPrecedence(t *testing.T) {
+
+	gatewayName := "gateway-bp-02"
+	gatewayClassName := "gateway-class-bp-02"
+	httpRouteName := "http-route-bp-02"
+	gatewayPolicyName := "backend-policy-02-gw"
+	rulePolicyName := "backend-policy-02-rule"
+	svcName := "avisvc-bp-02"
+	ports := []int32{8080}
+	modelName, _ := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	setupL4RouteBackend(t, svcName, 8080, corev1.ProtocolTCP)
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	rule := akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo"}, []string{}, nil,
+		[][]string{{svcName, DEFAULT_NAMESPACE, "8080", "1"}})
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	akogatewayapitests.SetupHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, []gatewayv1.HTTPRouteRule{rule})
+
+	g.Eventually(func() bool {
+		return getHTTPRoutePool(modelName) != nil
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	akogatewayapitests.SetupBackendPolicy(t, gatewayPolicyName, DEFAULT_NAMESPACE, akov1alpha1.BackendPolicySpec{
+		TargetRef: akov1alpha1.BackendPolicyTargetRef{
+			Group: gatewayv1.GroupName,
+			Kind:  "Gateway",
+			Name:  gatewayName,
+		},
+		LoadBalancerPolicy: akov1alpha1.BackendPolicyLBPolicy{Algorithm: "LB_ALGORITHM_ROUND_ROBIN"},
+	})
+	g.Eventually(func() bool {
+		pool := getHTTPRoutePool(modelName)
+		return pool != nil && pool.LbAlgorithm != nil && *pool.LbAlgorithm == "LB_ALGORITHM_ROUND_ROBIN"
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	ruleIndex := "0"
+	akogatewayapitests.SetupBackendPolicy(t, rulePolicyName, DEFAULT_NAMESPACE, akov1alpha1.BackendPolicySpec{
+		TargetRef: akov1alpha1.BackendPolicyTargetRef{
+			Group:       gatewayv1.GroupName,
+			Kind:        "HTTPRoute",
+			Name:        httpRouteName,
+			SectionName: &ruleIndex,
+		},
+		LoadBalancerPolicy: akov1alpha1.BackendPolicyLBPolicy{Algorithm: "LB_ALGORITHM_FEWEST_SERVERS"},
+	})
+	g.Eventually(func() bool {
+		pool := getHTTPRoutePool(modelName)
+		return pool != nil && pool.LbAlgorithm != nil && *pool.LbAlgorithm == "LB_ALGORITHM_FEWEST_SERVERS"
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	akogatewayapitests.TeardownBackendPolicy(t, rulePolicyName, DEFAULT_NAMESPACE)
+	g.Eventually(func() bool {
+		pool := getHTTPRoutePool(modelName)
+		return pool != nil && pool.LbAlgorithm != nil && *pool.LbAlgorithm == "LB_ALGORITHM_ROUND_ROBIN"
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	akogatewayapitests.TeardownBackendPolicy(t, gatewayPolicyName, DEFAULT_NAMESPACE)
+	g.Eventually(func() bool {
+		pool := getHTTPRoutePool(modelName)
+		return pool != nil && pool.LbAlgorithm == nil
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	akogatewayapitests.TeardownHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE)
+	teardownL4RouteBackend(t, svcName)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
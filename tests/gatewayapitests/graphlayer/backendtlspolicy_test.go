@@ -0,0 +1,174 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package graphlayer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	akogatewayapitests "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/gatewayapitests"
+)
+
+const backendTLSPolicyCACert = "-----BEGIN CERTIFICATE-----\nMIIBkTCB+wIJAKHHIG...\n-----END CERTIFICATE-----"
+
+func getHTTPRoutePool(modelName string) *avinodes.AviPoolNode {
+	found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	if !found || aviModel == nil {
+		return nil
+	}
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+	if len(nodes) == 0 || len(nodes[0].EvhNodes) == 0 || len(nodes[0].EvhNodes[0].PoolRefs) == 0 {
+		return nil
+	}
+	return nodes[0].EvhNodes[0].PoolRefs[0]
+}
+
+/* Test cases
+ * - BackendTLSPolicy CRUD on the backend Service of an HTTPRoute
+ * - BackendTLSPolicy referring to a ConfigMap which is created and updated later
+ */
+func TestBackendTLSPolicyCRUD(t *testing.T) {
+
+	gatewayName := "gateway-btls-01"
+	gatewayClassName := "gateway-class-btls-01"
+	httpRouteName := "http-route-btls-01"
+	policyName := "backend-tls-policy-01"
+	configMapName := "ca-cert-btls-01"
+	svcName := "avisvc-btls-01"
+	ports := []int32{8080}
+	modelName, _ := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	setupL4RouteBackend(t, svcName, 8080, corev1.ProtocolTCP)
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	rule := akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo"}, []string{}, nil,
+		[][]string{{svcName, DEFAULT_NAMESPACE, "8080", "1"}})
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	akogatewayapitests.SetupHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, []gatewayv1.HTTPRouteRule{rule})
+
+	g.Eventually(func() bool {
+		return getHTTPRoutePool(modelName) != nil
+	}, 25*time.Second).Should(gomega.Equal(true))
+	pool := getHTTPRoutePool(modelName)
+	g.Expect(pool.SniEnabled).To(gomega.BeFalse())
+	g.Expect(pool.PkiProfile).To(gomega.BeNil())
+
+	akogatewayapitests.SetupCACertConfigMap(t, configMapName, DEFAULT_NAMESPACE, backendTLSPolicyCACert)
+	akogatewayapitests.SetupBackendTLSPolicy(t, policyName, DEFAULT_NAMESPACE, svcName, "backend.example.com", []string{configMapName})
+
+	g.Eventually(func() bool {
+		pool := getHTTPRoutePool(modelName)
+		return pool != nil && pool.PkiProfile != nil
+	}, 25*time.Second).Should(gomega.Equal(true))
+	pool = getHTTPRoutePool(modelName)
+	g.Expect(pool.SniEnabled).To(gomega.BeTrue())
+	g.Expect(pool.ServerName).To(gomega.Equal("backend.example.com"))
+	g.Expect(*pool.SslProfileRef).To(gomega.Equal("/api/sslprofile?name=System-Standard"))
+	g.Expect(pool.PkiProfile.CACert).To(gomega.Equal(backendTLSPolicyCACert))
+
+	akogatewayapitests.TeardownBackendTLSPolicy(t, policyName, DEFAULT_NAMESPACE)
+	g.Eventually(func() bool {
+		pool := getHTTPRoutePool(modelName)
+		return pool != nil && pool.PkiProfile == nil
+	}, 25*time.Second).Should(gomega.Equal(true))
+	pool = getHTTPRoutePool(modelName)
+	g.Expect(pool.SniEnabled).To(gomega.BeFalse())
+	g.Expect(pool.ServerName).To(gomega.BeEmpty())
+
+	akogatewayapitests.TeardownCACertConfigMap(t, configMapName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE)
+	teardownL4RouteBackend(t, svcName)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
+
+func TestBackendTLSPolicyWithoutConfigMap(t *testing.T) {
+
+	gatewayName := "gateway-btls-02"
+	gatewayClassName := "gateway-class-btls-02"
+	httpRouteName := "http-route-btls-02"
+	policyName := "backend-tls-policy-02"
+	configMapName := "ca-cert-btls-02"
+	svcName := "avisvc-btls-02"
+	ports := []int32{8080}
+	modelName, _ := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	setupL4RouteBackend(t, svcName, 8080, corev1.ProtocolTCP)
+	akogatewayapitests.SetupBackendTLSPolicy(t, policyName, DEFAULT_NAMESPACE, svcName, "backend.example.com", []string{configMapName})
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	rule := akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo"}, []string{}, nil,
+		[][]string{{svcName, DEFAULT_NAMESPACE, "8080", "1"}})
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	akogatewayapitests.SetupHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, []gatewayv1.HTTPRouteRule{rule})
+
+	g.Eventually(func() bool {
+		return getHTTPRoutePool(modelName) != nil
+	}, 25*time.Second).Should(gomega.Equal(true))
+	pool := getHTTPRoutePool(modelName)
+	g.Expect(pool.SniEnabled).To(gomega.BeFalse())
+	g.Expect(pool.PkiProfile).To(gomega.BeNil())
+
+	// the pool re-encrypts once the ConfigMap is created
+	akogatewayapitests.SetupCACertConfigMap(t, configMapName, DEFAULT_NAMESPACE, backendTLSPolicyCACert)
+	g.Eventually(func() bool {
+		pool := getHTTPRoutePool(modelName)
+		return pool != nil && pool.PkiProfile != nil
+	}, 25*time.Second).Should(gomega.Equal(true))
+	g.Expect(getHTTPRoutePool(modelName).SniEnabled).To(gomega.BeTrue())
+
+	// the PKI profile follows the CA certificate in the ConfigMap
+	updatedCACert := "-----BEGIN CERTIFICATE-----\nMIIBkTCB+wIJAKHHIH...\n-----END CERTIFICATE-----"
+	akogatewayapitests.UpdateCACertConfigMap(t, configMapName, DEFAULT_NAMESPACE, updatedCACert)
+	g.Eventually(func() string {
+		pool := getHTTPRoutePool(modelName)
+		if pool == nil || pool.PkiProfile == nil {
+			return ""
+		}
+		return pool.PkiProfile.CACert
+	}, 25*time.Second).Should(gomega.Equal(updatedCACert))
+
+	akogatewayapitests.TeardownCACertConfigMap(t, configMapName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownBackendTLSPolicy(t, policyName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE)
+	teardownL4RouteBackend(t, svcName)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
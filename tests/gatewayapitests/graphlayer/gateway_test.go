@@ -35,6 +35,7 @@ import (
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	akocrdfake "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha1/clientset/versioned/fake"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 	tests "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/gatewayapitests"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
@@ -49,6 +50,7 @@ const (
 func TestMain(m *testing.M) {
 	tests.KubeClient = k8sfake.NewSimpleClientset()
 	tests.GatewayClient = gatewayfake.NewSimpleClientset()
+	tests.AKOClient = akocrdfake.NewSimpleClientset()
 	integrationtest.KubeClient = tests.KubeClient
 
 	// Sets the environment variables
@@ -92,8 +94,10 @@ func TestMain(m *testing.M) {
 
 	ctrl = akogatewayapik8s.SharedGatewayController()
 	ctrl.DisableSync = false
-	ctrl.InitGatewayAPIInformers(tests.GatewayClient)
+	ctrl.InitGatewayAPIInformers(tests.GatewayClient, tests.KubeClient)
 	akoControlConfig.SetGatewayAPIClientset(tests.GatewayClient)
+	ctrl.InitAKOCRDInformers(tests.AKOClient)
+	akoControlConfig.SetAKOCRDClientset(tests.AKOClient)
 
 	stopCh := utils.SetupSignalHandler()
 	ctrlCh := make(chan struct{})
@@ -206,6 +210,62 @@ func TestGatewayWithTLS(t *testing.T) {
 	integrationtest.DeleteSecret(secrets[0], DEFAULT_NAMESPACE)
 }
 
+func TestGatewayWithCrossNamespaceCertificateRef(t *testing.T) {
+
+	gatewayName := "gateway-02a"
+	invalidGatewayName := "gateway-02b"
+	gatewayClassName := "gateway-class-02a"
+	referenceGrantName := "referencegrant-02a"
+	secretNamespace := "red-ns"
+	ports := []int32{8080}
+
+	secrets := []string{"secret-02a"}
+	for _, secret := range secrets {
+		integrationtest.AddSecret(secret, secretNamespace, "cert", "key")
+	}
+
+	tests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := tests.GetListenersV1(ports, secrets...)
+	tests.SetListenerTLS(&listeners[0], gatewayv1.TLSModeTerminate, secrets[0], secretNamespace)
+	tests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+	// the Gateway with a listener without hostname stays invalid once the reference is permitted
+	invalidListeners := tests.GetListenersV1(ports, secrets...)
+	tests.SetListenerTLS(&invalidListeners[0], gatewayv1.TLSModeTerminate, secrets[0], secretNamespace)
+	invalidListeners[0].Hostname = nil
+	tests.SetupGateway(t, invalidGatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, invalidListeners)
+
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := lib.GetModelName(lib.GetTenant(), akogatewayapilib.GetGatewayParentName(DEFAULT_NAMESPACE, gatewayName))
+	invalidModelName := lib.GetModelName(lib.GetTenant(), akogatewayapilib.GetGatewayParentName(DEFAULT_NAMESPACE, invalidGatewayName))
+
+	g.Consistently(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 10*time.Second).Should(gomega.Equal(false))
+
+	tests.SetupReferenceGrant(t, referenceGrantName, secretNamespace, lib.Gateway, DEFAULT_NAMESPACE, utils.Secret)
+
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+	g.Expect(nodes).To(gomega.HaveLen(1))
+	g.Expect(nodes[0].SSLKeyCertRefs).To(gomega.HaveLen(1))
+
+	found, _ := objects.SharedAviGraphLister().Get(invalidModelName)
+	g.Expect(found).To(gomega.Equal(false))
+
+	tests.TeardownReferenceGrant(t, referenceGrantName, secretNamespace)
+	tests.TeardownGateway(t, invalidGatewayName, DEFAULT_NAMESPACE)
+	tests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	tests.TeardownGatewayClass(t, gatewayClassName)
+	integrationtest.DeleteSecret(secrets[0], secretNamespace)
+}
+
 /*
 Positive Case
 Transition Gateway 1 listener (noTLS -> TLS)
@@ -356,6 +416,79 @@ func TestGatewayTLSToNoTLS(t *testing.T) {
 	integrationtest.DeleteSecret(secrets[0], DEFAULT_NAMESPACE)
 }
 
+/*
+Positive Case
+Create Gateway with one IPv4 and one IPv6 address
+Create Gateway with a NamedAddress
+*/
+func TestGatewayWithDualStackAddresses(t *testing.T) {
+
+	gatewayName := "gateway-07"
+	gatewayClassName := "gateway-class-07"
+	ports := []int32{8080}
+
+	tests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := tests.GetListenersV1(ports)
+	ipAddressType := gatewayv1.IPAddressType
+	addresses := []gatewayv1.GatewayAddress{
+		{Type: &ipAddressType, Value: "10.10.10.1"},
+		{Type: &ipAddressType, Value: "2001:db8::1"},
+	}
+	tests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, addresses, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	modelName := lib.GetModelName(lib.GetTenant(), akogatewayapilib.GetGatewayParentName(DEFAULT_NAMESPACE, gatewayName))
+
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+	g.Expect(nodes).To(gomega.HaveLen(1))
+	g.Expect(nodes[0].VSVIPRefs).To(gomega.HaveLen(1))
+	g.Expect(nodes[0].VSVIPRefs[0].IPAddress).To(gomega.Equal("10.10.10.1"))
+	g.Expect(nodes[0].VSVIPRefs[0].V6IPAddress).To(gomega.Equal("2001:db8::1"))
+
+	tests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	tests.TeardownGatewayClass(t, gatewayClassName)
+}
+
+func TestGatewayWithNamedAddress(t *testing.T) {
+
+	gatewayName := "gateway-08"
+	gatewayClassName := "gateway-class-08"
+	ports := []int32{8080}
+
+	tests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := tests.GetListenersV1(ports)
+	namedAddressType := gatewayv1.NamedAddressType
+	addresses := []gatewayv1.GatewayAddress{
+		{Type: &namedAddressType, Value: "vip-network-01"},
+	}
+	tests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, addresses, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	modelName := lib.GetModelName(lib.GetTenant(), akogatewayapilib.GetGatewayParentName(DEFAULT_NAMESPACE, gatewayName))
+
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+	g.Expect(nodes).To(gomega.HaveLen(1))
+	g.Expect(nodes[0].VSVIPRefs).To(gomega.HaveLen(1))
+	g.Expect(nodes[0].VSVIPRefs[0].IPAddress).To(gomega.BeEmpty())
+	g.Expect(nodes[0].VSVIPRefs[0].VipNetworks).To(gomega.HaveLen(1))
+	g.Expect(nodes[0].VSVIPRefs[0].VipNetworks[0].NetworkName).To(gomega.Equal("vip-network-01"))
+
+	tests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	tests.TeardownGatewayClass(t, gatewayClassName)
+}
+
 /*
 Negative Case
 Delete Gateway 1 listener
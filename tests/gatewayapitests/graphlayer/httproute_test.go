@@ -530,6 +530,367 @@ func TestHTTPRouteFilterWithRequestRedirect(t *testing.T) {
 	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
 }
 
+func TestHTTPRouteFilterWithRequestRedirectSchemePortPath(t *testing.T) {
+
+	gatewayName := "gateway-hrf-05"
+	gatewayClassName := "gateway-class-hrf-05"
+	httpRouteName := "http-route-hrf-05"
+	ports := []int32{8080}
+	modelName, _ := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	rule := akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo"}, []string{},
+		map[string][]string{"RequestRedirect": {"scheme", "port", "fullpath"}},
+		[][]string{{"avisvc", "default", "8080", "1"}})
+	rules := []gatewayv1.HTTPRouteRule{rule}
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	akogatewayapitests.SetupHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found {
+			return 0
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		if len(nodes[0].EvhNodes) != 1 || len(nodes[0].EvhNodes[0].HttpPolicyRefs) != 1 {
+			return -1
+		}
+		return len(nodes[0].EvhNodes[0].HttpPolicyRefs[0].RequestRules)
+	}, 25*time.Second).Should(gomega.Equal(1))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+	redirectAction := nodes[0].EvhNodes[0].HttpPolicyRefs[0].RequestRules[0].RedirectAction
+	g.Expect(redirectAction).ShouldNot(gomega.BeNil())
+	g.Expect(*redirectAction.Protocol).To(gomega.Equal("HTTPS"))
+	g.Expect(redirectAction.Port).To(gomega.Equal(uint32(8443)))
+	g.Expect(*redirectAction.Host.Tokens[0].StrValue).To(gomega.Equal("redirect.com"))
+	g.Expect(redirectAction.Path.Tokens).To(gomega.HaveLen(1))
+	g.Expect(*redirectAction.Path.Tokens[0].StrValue).To(gomega.Equal("bar"))
+
+	// update the redirect to replace the prefix, the listener scheme and port are retained
+	rule = akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo", "/foo/baz"}, []string{},
+		map[string][]string{"RequestRedirect": {"prefix"}},
+		[][]string{{"avisvc", "default", "8080", "1"}})
+	rules = []gatewayv1.HTTPRouteRule{rule}
+	akogatewayapitests.UpdateHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+
+	g.Eventually(func() int {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		if len(nodes[0].EvhNodes) != 1 || len(nodes[0].EvhNodes[0].HttpPolicyRefs) != 1 {
+			return -1
+		}
+		return len(nodes[0].EvhNodes[0].HttpPolicyRefs[0].RequestRules)
+	}, 25*time.Second).Should(gomega.Equal(2))
+
+	_, aviModel = objects.SharedAviGraphLister().Get(modelName)
+	nodes = aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+	requestRules := nodes[0].EvhNodes[0].HttpPolicyRefs[0].RequestRules
+	// longest prefix is evaluated first
+	g.Expect(requestRules[0].Match.Path.MatchStr).To(gomega.Equal([]string{"/foo/baz"}))
+	g.Expect(requestRules[0].RedirectAction.Path.Tokens[1].StartIndex).To(gomega.Equal(uint32(2)))
+	g.Expect(requestRules[1].Match.Path.MatchStr).To(gomega.Equal([]string{"/foo"}))
+	g.Expect(*requestRules[1].RedirectAction.Path.Tokens[0].StrValue).To(gomega.Equal("bar"))
+	g.Expect(*requestRules[1].RedirectAction.Path.Tokens[1].Type).To(gomega.Equal("URI_TOKEN_TYPE_PATH"))
+	g.Expect(requestRules[1].RedirectAction.Path.Tokens[1].StartIndex).To(gomega.Equal(uint32(1)))
+	g.Expect(*requestRules[1].RedirectAction.Protocol).To(gomega.Equal("HTTPS"))
+	g.Expect(requestRules[1].RedirectAction.Port).To(gomega.Equal(uint32(8080)))
+
+	// delete httproute
+	akogatewayapitests.TeardownHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
+
+func TestHTTPRouteFilterWithURLRewrite(t *testing.T) {
+
+	gatewayName := "gateway-hrf-06"
+	gatewayClassName := "gateway-class-hrf-06"
+	httpRouteName := "http-route-hrf-06"
+	ports := []int32{8080}
+	modelName, _ := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	rule := akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo"}, []string{},
+		map[string][]string{"URLRewrite": {"prefix"}, "RequestHeaderModifier": {"add"}},
+		[][]string{{"avisvc", "default", "8080", "1"}})
+	rules := []gatewayv1.HTTPRouteRule{rule}
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	akogatewayapitests.SetupHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found {
+			return 0
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		if len(nodes[0].EvhNodes) != 1 || len(nodes[0].EvhNodes[0].HttpPolicyRefs) != 1 {
+			return -1
+		}
+		return len(nodes[0].EvhNodes[0].HttpPolicyRefs[0].RequestRules)
+	}, 25*time.Second).Should(gomega.Equal(1))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+	requestRule := nodes[0].EvhNodes[0].HttpPolicyRefs[0].RequestRules[0]
+	g.Expect(requestRule.Match.Path.MatchStr).To(gomega.Equal([]string{"/foo"}))
+	g.Expect(requestRule.HdrAction).To(gomega.HaveLen(1))
+	g.Expect(requestRule.RewriteURLAction).ShouldNot(gomega.BeNil())
+	g.Expect(*requestRule.RewriteURLAction.HostHdr.Tokens[0].StrValue).To(gomega.Equal("rewrite.com"))
+	g.Expect(*requestRule.RewriteURLAction.Path.Tokens[0].StrValue).To(gomega.Equal("bar"))
+	g.Expect(requestRule.RewriteURLAction.Path.Tokens[1].StartIndex).To(gomega.Equal(uint32(1)))
+
+	// update the rewrite to replace the full path
+	rule = akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo"}, []string{},
+		map[string][]string{"URLRewrite": {"fullpath"}},
+		[][]string{{"avisvc", "default", "8080", "1"}})
+	rules = []gatewayv1.HTTPRouteRule{rule}
+	akogatewayapitests.UpdateHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+
+	g.Eventually(func() bool {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		if len(nodes[0].EvhNodes) != 1 || len(nodes[0].EvhNodes[0].HttpPolicyRefs) != 1 ||
+			len(nodes[0].EvhNodes[0].HttpPolicyRefs[0].RequestRules) != 1 {
+			return false
+		}
+		return nodes[0].EvhNodes[0].HttpPolicyRefs[0].RequestRules[0].Match == nil
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	_, aviModel = objects.SharedAviGraphLister().Get(modelName)
+	nodes = aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+	requestRule = nodes[0].EvhNodes[0].HttpPolicyRefs[0].RequestRules[0]
+	g.Expect(requestRule.HdrAction).To(gomega.BeEmpty())
+	g.Expect(requestRule.RewriteURLAction.Path.Tokens).To(gomega.HaveLen(1))
+	g.Expect(*requestRule.RewriteURLAction.Path.Tokens[0].StrValue).To(gomega.Equal("bar"))
+
+	// delete httproute
+	akogatewayapitests.TeardownHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
+
+func TestHTTPRouteFilterWithRequestMirror(t *testing.T) {
+
+	gatewayName := "gateway-hrf-07"
+	gatewayClassName := "gateway-class-hrf-07"
+	httpRouteName := "http-route-hrf-07"
+	mirrorSvcName := "avisvc-mirror"
+	ports := []int32{8080}
+	modelName, _ := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	integrationtest.CreateSVC(t, DEFAULT_NAMESPACE, mirrorSvcName, "TCP", corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, DEFAULT_NAMESPACE, mirrorSvcName, false, false, "1.2.4")
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	rule := akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo"}, []string{},
+		map[string][]string{"RequestMirror": {}},
+		[][]string{{"avisvc", "default", "8080", "1"}})
+	rules := []gatewayv1.HTTPRouteRule{rule}
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	akogatewayapitests.SetupHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found {
+			return 0
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		if len(nodes[0].EvhNodes) != 1 {
+			return -1
+		}
+		return len(nodes[0].EvhNodes[0].CloneProfileRefs)
+	}, 25*time.Second).Should(gomega.Equal(1))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+	childNode := nodes[0].EvhNodes[0]
+	g.Expect(childNode.CloneProfileRefs[0].Name).To(gomega.Equal(lib.GetTrafficCloneProfileName(childNode.Name)))
+	g.Expect(childNode.CloneProfileRefs[0].CloneServers).To(gomega.Equal([]string{"1.2.4.1"}))
+	// the mirror pool is not attached to the child VS, and no HTTP policy is needed for the mirror
+	for _, pool := range childNode.PoolRefs {
+		g.Expect(pool.Name).NotTo(gomega.Equal(childNode.CloneProfileRefs[0].Name))
+	}
+	g.Expect(childNode.HttpPolicyRefs).To(gomega.BeEmpty())
+
+	// remove the mirror filter
+	rule = akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo"}, []string{},
+		map[string][]string{"RequestHeaderModifier": {"add"}},
+		[][]string{{"avisvc", "default", "8080", "1"}})
+	rules = []gatewayv1.HTTPRouteRule{rule}
+	akogatewayapitests.UpdateHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+
+	g.Eventually(func() int {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		if len(nodes[0].EvhNodes) != 1 {
+			return -1
+		}
+		return len(nodes[0].EvhNodes[0].CloneProfileRefs)
+	}, 25*time.Second).Should(gomega.Equal(0))
+
+	integrationtest.DelSVC(t, DEFAULT_NAMESPACE, mirrorSvcName)
+	integrationtest.DelEP(t, DEFAULT_NAMESPACE, mirrorSvcName)
+	akogatewayapitests.TeardownHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
+
+func TestHTTPRouteWithQueryParamAndMethodMatch(t *testing.T) {
+
+	gatewayName := "gateway-hrm-01"
+	gatewayClassName := "gateway-class-hrm-01"
+	httpRouteName := "http-route-hrm-01"
+	ports := []int32{8080}
+	modelName, _ := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	rule := akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo", "/foo"}, []string{},
+		map[string][]string{},
+		[][]string{{"avisvc", "default", "8080", "1"}})
+	method := gatewayv1.HTTPMethodGet
+	regexType := gatewayv1.QueryParamMatchRegularExpression
+	rule.Matches[1].Method = &method
+	rule.Matches[1].QueryParams = []gatewayv1.HTTPQueryParamMatch{
+		{Name: "version", Value: "v1.0"},
+		{Type: &regexType, Name: "id", Value: "[0-9]+"},
+	}
+	rules := []gatewayv1.HTTPRouteRule{rule}
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	akogatewayapitests.SetupHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found {
+			return 0
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		if len(nodes[0].EvhNodes) != 1 || len(nodes[0].EvhNodes[0].VHMatches) != 1 {
+			return -1
+		}
+		return len(nodes[0].EvhNodes[0].VHMatches[0].Rules)
+	}, 25*time.Second).Should(gomega.Equal(2))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+	vhMatchRules := nodes[0].EvhNodes[0].VHMatches[0].Rules
+
+	// the match with the method takes precedence
+	g.Expect(vhMatchRules[0].Matches.Method).ShouldNot(gomega.BeNil())
+	g.Expect(*vhMatchRules[0].Matches.Method.MatchCriteria).To(gomega.Equal("IS_IN"))
+	g.Expect(vhMatchRules[0].Matches.Method.Methods).To(gomega.Equal([]string{"HTTP_METHOD_GET"}))
+	g.Expect(vhMatchRules[0].Matches.Query).ShouldNot(gomega.BeNil())
+	g.Expect(*vhMatchRules[0].Matches.Query.MatchCriteria).To(gomega.Equal("QUERY_MATCH_REGEX_MATCH"))
+	g.Expect(vhMatchRules[0].Matches.Query.MatchStr).To(gomega.Equal([]string{`^(?=(.*&)?version=v1\.0(&|$))(?=(.*&)?id=([0-9]+)(&|$))`}))
+	g.Expect(vhMatchRules[1].Matches.Method).Should(gomega.BeNil())
+	g.Expect(vhMatchRules[1].Matches.Query).Should(gomega.BeNil())
+
+	// delete httproute
+	akogatewayapitests.TeardownHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
+
+func TestHTTPRouteWithWildcardHostnames(t *testing.T) {
+
+	gatewayName := "gateway-hrw-01"
+	gatewayClassName := "gateway-class-hrw-01"
+	httpRouteName := "http-route-hrw-01"
+	ports := []int32{8080}
+	modelName, _ := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	listenerHostname := gatewayv1.Hostname("*.example.com")
+	listeners[0].Hostname = &listenerHostname
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	rule := akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo"}, []string{},
+		map[string][]string{},
+		[][]string{{"avisvc", "default", "8080", "1"}})
+	rules := []gatewayv1.HTTPRouteRule{rule}
+	// *.com intersects with the listener hostname as *.example.com
+	hostnames := []gatewayv1.Hostname{"*.team.example.com", "foo.example.com", "*.com", "foo.example.org"}
+	akogatewayapitests.SetupHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found {
+			return 0
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		if len(nodes[0].EvhNodes) != 1 {
+			return -1
+		}
+		return len(nodes[0].EvhNodes[0].VHMatches)
+	}, 25*time.Second).Should(gomega.Equal(3))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+	var vhMatchHosts []string
+	for _, vhMatch := range nodes[0].EvhNodes[0].VHMatches {
+		vhMatchHosts = append(vhMatchHosts, *vhMatch.Host)
+	}
+	g.Expect(vhMatchHosts).To(gomega.ConsistOf("*.team.example.com", "foo.example.com", "*.example.com"))
+	// only the non wildcard hostnames are added as the vsvip fqdns
+	g.Expect(nodes[0].VSVIPRefs[0].FQDNs).To(gomega.ContainElement("foo.example.com"))
+	g.Expect(nodes[0].VSVIPRefs[0].FQDNs).NotTo(gomega.ContainElement("*.team.example.com"))
+	g.Expect(nodes[0].VSVIPRefs[0].FQDNs).NotTo(gomega.ContainElement("*.example.com"))
+
+	// delete httproute
+	akogatewayapitests.TeardownHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
+
 func TestHTTPRouteWithValidConfig(t *testing.T) {
 	gatewayClassName := "gateway-class-hr-01"
 	gatewayName := "gateway-hr-01"
@@ -878,3 +1239,68 @@ func TestHTTPRouteBackendServiceUpdate(t *testing.T) {
 	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
 	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
 }
+
+func TestHTTPRouteWithCrossNamespaceBackendRef(t *testing.T) {
+
+	gatewayName := "gateway-hr-rg-01"
+	gatewayClassName := "gateway-class-hr-rg-01"
+	httpRouteName := "http-route-hr-rg-01"
+	referenceGrantName := "referencegrant-hr-rg-01"
+	svcName := "avisvc-hr-rg-01"
+	svcNamespace := "red-ns"
+	ports := []int32{8080}
+	modelName, _ := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	integrationtest.CreateSVC(t, svcNamespace, svcName, "TCP", corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, svcNamespace, svcName, false, false, "1.2.3")
+
+	// the backend is skipped without a ReferenceGrant in the service namespace
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, ports)
+	rule := akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo"}, []string{}, nil,
+		[][]string{{svcName, svcNamespace, "8080", "1"}})
+	rules := []gatewayv1.HTTPRouteRule{rule}
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	akogatewayapitests.SetupHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE, parentRefs, hostnames, rules)
+
+	getChildNodeCount := func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		if !found {
+			return -1
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+		return len(nodes[0].EvhNodes)
+	}
+	g.Consistently(getChildNodeCount, 5*time.Second).Should(gomega.Equal(0))
+
+	akogatewayapitests.SetupReferenceGrant(t, referenceGrantName, svcNamespace, lib.HTTPRoute, DEFAULT_NAMESPACE, "Service")
+	g.Eventually(getChildNodeCount, 25*time.Second).Should(gomega.Equal(1))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+	childNode := nodes[0].EvhNodes[0]
+	g.Expect(childNode.PoolGroupRefs).To(gomega.HaveLen(1))
+	g.Expect(childNode.PoolGroupRefs[0].Members).To(gomega.HaveLen(1))
+	g.Expect(childNode.PoolRefs).To(gomega.HaveLen(1))
+	g.Expect(childNode.PoolRefs[0].Servers).To(gomega.HaveLen(1))
+
+	// deleting the ReferenceGrant removes the backend
+	akogatewayapitests.TeardownReferenceGrant(t, referenceGrantName, svcNamespace)
+	g.Eventually(getChildNodeCount, 25*time.Second).Should(gomega.Equal(0))
+
+	integrationtest.DelSVC(t, svcNamespace, svcName)
+	integrationtest.DelEP(t, svcNamespace, svcName)
+	akogatewayapitests.TeardownHTTPRoute(t, httpRouteName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
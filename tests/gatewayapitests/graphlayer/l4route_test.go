@@ -0,0 +1,223 @@
+/*
+ * Copyright 2023-2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package graphlayer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	akogatewayapitests "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/gatewayapitests"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+)
+
+/* Test cases
+ * - TCPRoute CRUD
+ * - UDPRoute CRUD
+ * - TLSRoute CRUD with Passthrough listener
+ */
+
+func setupL4RouteBackend(t *testing.T, name string, port int32, protocol corev1.Protocol) {
+	svcExample := (integrationtest.FakeService{
+		Name:         name,
+		Namespace:    DEFAULT_NAMESPACE,
+		Type:         corev1.ServiceTypeClusterIP,
+		ServicePorts: []integrationtest.Serviceport{{PortName: "foo", Protocol: protocol, PortNumber: port, TargetPort: intstr.FromInt(int(port))}},
+	}).Service()
+	if _, err := akogatewayapitests.KubeClient.CoreV1().Services(DEFAULT_NAMESPACE).Create(context.TODO(), svcExample, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Service: %v", err)
+	}
+	epExample := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Namespace: DEFAULT_NAMESPACE, Name: name},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{{IP: "1.2.3.4"}},
+			Ports:     []corev1.EndpointPort{{Name: "foo", Port: port, Protocol: protocol}},
+		}},
+	}
+	if _, err := akogatewayapitests.KubeClient.CoreV1().Endpoints(DEFAULT_NAMESPACE).Create(context.TODO(), epExample, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in creating Endpoint: %v", err)
+	}
+}
+
+func teardownL4RouteBackend(t *testing.T, name string) {
+	akogatewayapitests.KubeClient.CoreV1().Endpoints(DEFAULT_NAMESPACE).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	akogatewayapitests.KubeClient.CoreV1().Services(DEFAULT_NAMESPACE).Delete(context.TODO(), name, metav1.DeleteOptions{})
+}
+
+func getL4PolicyCount(modelName string) int {
+	found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	if !found || aviModel == nil {
+		return -1
+	}
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+	return len(nodes[0].L4PolicyRefs)
+}
+
+func TestTCPRouteCRUD(t *testing.T) {
+
+	gatewayName := "gateway-tcp-01"
+	gatewayClassName := "gateway-class-tcp-01"
+	tcpRouteName := "tcp-route-01"
+	svcName := "avisvc-tcp-01"
+	modelName, _ := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1([]int32{8080})
+	listeners = append(listeners, gatewayv1.Listener{Name: "listener-9000", Port: 9000, Protocol: gatewayv1.TCPProtocolType})
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	setupL4RouteBackend(t, svcName, 9000, corev1.ProtocolTCP)
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, []int32{9000})
+	akogatewayapitests.SetupTCPRoute(t, tcpRouteName, DEFAULT_NAMESPACE, parentRefs, [][]string{{svcName, DEFAULT_NAMESPACE, "9000", "1"}})
+
+	g.Eventually(func() int {
+		return getL4PolicyCount(modelName)
+	}, 25*time.Second).Should(gomega.Equal(1))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	parentNode := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()[0]
+	g.Expect(parentNode.PortProto).To(gomega.HaveLen(2))
+	g.Expect(parentNode.L4PolicyRefs[0].PortPool).To(gomega.HaveLen(1))
+	g.Expect(parentNode.L4PolicyRefs[0].PortPool[0].Port).To(gomega.Equal(uint32(9000)))
+	g.Expect(parentNode.L4PolicyRefs[0].PortPool[0].Protocol).To(gomega.Equal(utils.TCP))
+	g.Expect(parentNode.PoolGroupRefs).To(gomega.HaveLen(1))
+	g.Expect(parentNode.PoolGroupRefs[0].Members).To(gomega.HaveLen(1))
+	g.Expect(parentNode.PoolRefs).To(gomega.HaveLen(1))
+	g.Expect(parentNode.PoolRefs[0].Servers).To(gomega.HaveLen(1))
+	g.Expect(parentNode.PoolRefs[0].Port).To(gomega.Equal(int32(9000)))
+
+	akogatewayapitests.TeardownTCPRoute(t, tcpRouteName, DEFAULT_NAMESPACE)
+	g.Eventually(func() int {
+		return getL4PolicyCount(modelName)
+	}, 25*time.Second).Should(gomega.Equal(0))
+	_, aviModel = objects.SharedAviGraphLister().Get(modelName)
+	parentNode = aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()[0]
+	g.Expect(parentNode.PoolGroupRefs).To(gomega.HaveLen(0))
+	g.Expect(parentNode.PoolRefs).To(gomega.HaveLen(0))
+
+	teardownL4RouteBackend(t, svcName)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
+
+func TestUDPRouteCRUD(t *testing.T) {
+
+	gatewayName := "gateway-udp-01"
+	gatewayClassName := "gateway-class-udp-01"
+	udpRouteName := "udp-route-01"
+	svcName := "avisvc-udp-01"
+	modelName, _ := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := []gatewayv1.Listener{{Name: "listener-5353", Port: 5353, Protocol: gatewayv1.UDPProtocolType}}
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	setupL4RouteBackend(t, svcName, 5353, corev1.ProtocolUDP)
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, []int32{5353})
+	akogatewayapitests.SetupUDPRoute(t, udpRouteName, DEFAULT_NAMESPACE, parentRefs, [][]string{{svcName, DEFAULT_NAMESPACE, "5353", "1"}})
+
+	g.Eventually(func() int {
+		return getL4PolicyCount(modelName)
+	}, 25*time.Second).Should(gomega.Equal(1))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	parentNode := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()[0]
+	g.Expect(parentNode.L4PolicyRefs[0].PortPool[0].Port).To(gomega.Equal(uint32(5353)))
+	g.Expect(parentNode.L4PolicyRefs[0].PortPool[0].Protocol).To(gomega.Equal(utils.UDP))
+	g.Expect(parentNode.PoolRefs).To(gomega.HaveLen(1))
+	g.Expect(parentNode.PoolRefs[0].Protocol).To(gomega.Equal(utils.UDP))
+
+	akogatewayapitests.TeardownUDPRoute(t, udpRouteName, DEFAULT_NAMESPACE)
+	g.Eventually(func() int {
+		return getL4PolicyCount(modelName)
+	}, 25*time.Second).Should(gomega.Equal(0))
+
+	teardownL4RouteBackend(t, svcName)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
+
+func TestTLSRouteCRUD(t *testing.T) {
+
+	gatewayName := "gateway-tls-01"
+	gatewayClassName := "gateway-class-tls-01"
+	tlsRouteName := "tls-route-01"
+	svcName := "avisvc-tls-01"
+	modelName, _ := akogatewayapitests.GetModelName(DEFAULT_NAMESPACE, gatewayName)
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	passthrough := gatewayv1.TLSModePassthrough
+	hostname := gatewayv1.Hostname("*.tls.com")
+	listeners := []gatewayv1.Listener{{
+		Name:     "listener-8443",
+		Port:     8443,
+		Protocol: gatewayv1.TLSProtocolType,
+		Hostname: &hostname,
+		TLS:      &gatewayv1.GatewayTLSConfig{Mode: &passthrough},
+	}}
+	akogatewayapitests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		found, _ := objects.SharedAviGraphLister().Get(modelName)
+		return found
+	}, 25*time.Second).Should(gomega.Equal(true))
+
+	setupL4RouteBackend(t, svcName, 8443, corev1.ProtocolTCP)
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, DEFAULT_NAMESPACE, []int32{8443})
+	akogatewayapitests.SetupTLSRoute(t, tlsRouteName, DEFAULT_NAMESPACE, parentRefs, []gatewayv1.Hostname{"foo.tls.com"}, [][]string{{svcName, DEFAULT_NAMESPACE, "8443", "1"}})
+
+	g.Eventually(func() int {
+		return getL4PolicyCount(modelName)
+	}, 25*time.Second).Should(gomega.Equal(1))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	parentNode := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()[0]
+	g.Expect(parentNode.L4PolicyRefs[0].PortPool[0].Port).To(gomega.Equal(uint32(8443)))
+	g.Expect(parentNode.L4PolicyRefs[0].PortPool[0].Protocol).To(gomega.Equal(utils.TCP))
+	g.Expect(parentNode.VSVIPRefs[0].FQDNs).To(gomega.ContainElement("foo.tls.com"))
+	g.Expect(parentNode.SSLKeyCertRefs).To(gomega.HaveLen(0))
+
+	akogatewayapitests.TeardownTLSRoute(t, tlsRouteName, DEFAULT_NAMESPACE)
+	g.Eventually(func() int {
+		return getL4PolicyCount(modelName)
+	}, 25*time.Second).Should(gomega.Equal(0))
+
+	teardownL4RouteBackend(t, svcName)
+	akogatewayapitests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
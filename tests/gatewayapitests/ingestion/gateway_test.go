@@ -30,6 +30,7 @@ import (
 	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	akocrdfake "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha1/clientset/versioned/fake"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 	akogatewayapitests "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/gatewayapitests"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
@@ -83,6 +84,7 @@ func setupQueue(stopCh <-chan struct{}) {
 func TestMain(m *testing.M) {
 	akogatewayapitests.KubeClient = k8sfake.NewSimpleClientset()
 	akogatewayapitests.GatewayClient = gatewayfake.NewSimpleClientset()
+	akogatewayapitests.AKOClient = akocrdfake.NewSimpleClientset()
 	integrationtest.KubeClient = akogatewayapitests.KubeClient
 
 	os.Setenv("CLUSTER_NAME", "cluster")
@@ -110,8 +112,10 @@ func TestMain(m *testing.M) {
 
 	defer integrationtest.AviFakeClientInstance.Close()
 	ctrl := akogatewayapik8s.SharedGatewayController()
-	ctrl.InitGatewayAPIInformers(akogatewayapitests.GatewayClient)
+	ctrl.InitGatewayAPIInformers(akogatewayapitests.GatewayClient, akogatewayapitests.KubeClient)
 	akoControlConfig.SetGatewayAPIClientset(akogatewayapitests.GatewayClient)
+	ctrl.InitAKOCRDInformers(akogatewayapitests.AKOClient)
+	akoControlConfig.SetAKOCRDClientset(akogatewayapitests.AKOClient)
 	stopCh := utils.SetupSignalHandler()
 	ctrl.Start(stopCh)
 	keyChan = make(chan string)
@@ -247,13 +251,16 @@ func TestGatewayInvalidAddress(t *testing.T) {
 	t.Logf("Created %+v", gw.Name)
 	waitAndverify(t, "")
 
-	//update with IPv6
+	//update with two IPv4 addresses
 	ipAddressType := gatewayv1.AddressType("IPAddress")
 	gateway.Spec.Addresses = []gatewayv1.GatewayAddress{
 		{
-			Type: &ipAddressType,
-			//TODO replace with constant from utils
-			Value: "2001:db8:3333:4444:5555:6666:7777:8888",
+			Type:  &ipAddressType,
+			Value: "1.2.3.4",
+		},
+		{
+			Type:  &ipAddressType,
+			Value: "1.2.3.5",
 		},
 	}
 	gw, err = akogatewayapitests.GatewayClient.GatewayV1().Gateways("default").Update(context.TODO(), &gateway, metav1.UpdateOptions{})
@@ -263,13 +270,17 @@ func TestGatewayInvalidAddress(t *testing.T) {
 	t.Logf("Updated %+v", gw.Name)
 	waitAndverify(t, "")
 
-	//update with IPv4
+	//update with one IPv4 and one IPv6 address
 	gateway.Spec.Addresses = []gatewayv1.GatewayAddress{
 		{
 			Type: &ipAddressType,
 			//TODO replace with constant from utils
 			Value: "1.2.3.4",
 		},
+		{
+			Type:  &ipAddressType,
+			Value: "2001:db8:3333:4444:5555:6666:7777:8888",
+		},
 	}
 	gw, err = akogatewayapitests.GatewayClient.GatewayV1().Gateways("default").Update(context.TODO(), &gateway, metav1.UpdateOptions{})
 	if err != nil {
@@ -342,7 +353,7 @@ func TestGatewayInvalidListenerProtocol(t *testing.T) {
 		Status: gatewayv1.GatewayStatus{},
 	}
 	akogatewayapitests.SetGatewayGatewayClass(&gateway, gwClassName)
-	akogatewayapitests.AddGatewayListener(&gateway, "listener-example", 80, gatewayv1.TCPProtocolType, false)
+	akogatewayapitests.AddGatewayListener(&gateway, "listener-example", 80, gatewayv1.ProtocolType("SCTP"), false)
 	akogatewayapitests.SetListenerHostname(&gateway.Spec.Listeners[0], "*.example.com")
 
 	//create
@@ -0,0 +1,149 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	akov1alpha1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha1"
+	akogatewayapitests "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/gatewayapitests"
+)
+
+func getBackendPolicyAcceptedCondition(t *testing.T, name, namespace string) *metav1.Condition {
+	backendPolicy, err := akogatewayapitests.AKOClient.AkoV1alpha1().BackendPolicies(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil || backendPolicy == nil {
+		t.Logf("Couldn't get the BackendPolicy, err: %+v", err)
+		return nil
+	}
+	return apimeta.FindStatusCondition(backendPolicy.Status.Conditions, string(gatewayv1alpha2.PolicyConditionAccepted))
+}
+
+/* Test cases
+ * - BackendPolicy with a valid and an invalid Avi reference
+ * - BackendPolicy with an unsupported target
+ * - BackendPolicies with the same target
+ */
+func TestBackendPolicyWithAviRefs(t *testing.T) {
+	policyName := "backend-policy-status-01"
+	namespace := "default"
+
+	spec := akov1alpha1.BackendPolicySpec{
+		TargetRef: akov1alpha1.BackendPolicyTargetRef{
+			Kind: "Service",
+			Name: "avisvc-bp-status-01",
+		},
+		HealthMonitors: []string{"thisisaviref-hm1"},
+	}
+	akogatewayapitests.SetupBackendPolicy(t, policyName, namespace, spec)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		return getBackendPolicyAcceptedCondition(t, policyName, namespace) != nil
+	}, 30*time.Second).Should(gomega.Equal(true))
+	expectedCondition := metav1.Condition{
+		Type:    string(gatewayv1alpha2.PolicyConditionAccepted),
+		Reason:  string(gatewayv1alpha2.PolicyReasonAccepted),
+		Status:  metav1.ConditionTrue,
+		Message: "BackendPolicy is accepted",
+	}
+	akogatewayapitests.ValidateConditions(t, []metav1.Condition{*getBackendPolicyAcceptedCondition(t, policyName, namespace)}, []metav1.Condition{expectedCondition})
+
+	spec.HealthMonitors = []string{"thisisBADaviref-hm1"}
+	akogatewayapitests.UpdateBackendPolicy(t, policyName, namespace, spec)
+	g.Eventually(func() string {
+		condition := getBackendPolicyAcceptedCondition(t, policyName, namespace)
+		if condition == nil {
+			return ""
+		}
+		return condition.Reason
+	}, 30*time.Second).Should(gomega.Equal(string(gatewayv1alpha2.PolicyReasonInvalid)))
+	g.Expect(getBackendPolicyAcceptedCondition(t, policyName, namespace).Status).To(gomega.Equal(metav1.ConditionFalse))
+
+	akogatewayapitests.TeardownBackendPolicy(t, policyName, namespace)
+}
+
+func TestBackendPolicyWithUnsupportedTarget(t *testing.T) {
+	policyName := "backend-policy-status-02"
+	namespace := "default"
+
+	akogatewayapitests.SetupBackendPolicy(t, policyName, namespace, akov1alpha1.BackendPolicySpec{
+		TargetRef: akov1alpha1.BackendPolicyTargetRef{
+			Kind: "ConfigMap",
+			Name: "configmap-bp-status-02",
+		},
+	})
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		return getBackendPolicyAcceptedCondition(t, policyName, namespace) != nil
+	}, 30*time.Second).Should(gomega.Equal(true))
+	expectedCondition := metav1.Condition{
+		Type:    string(gatewayv1alpha2.PolicyConditionAccepted),
+		Reason:  string(gatewayv1alpha2.PolicyReasonInvalid),
+		Status:  metav1.ConditionFalse,
+		Message: "target ConfigMap of group \"\" is not supported",
+	}
+	akogatewayapitests.ValidateConditions(t, []metav1.Condition{*getBackendPolicyAcceptedCondition(t, policyName, namespace)}, []metav1.Condition{expectedCondition})
+
+	akogatewayapitests.TeardownBackendPolicy(t, policyName, namespace)
+}
+
+func TestBackendPolicyWithSameTarget(t *testing.T) {
+	policyName := "backend-policy-status-03a"
+	conflictedPolicyName := "backend-policy-status-03b"
+	namespace := "default"
+
+	spec := akov1alpha1.BackendPolicySpec{
+		TargetRef: akov1alpha1.BackendPolicyTargetRef{
+			Kind: "Service",
+			Name: "avisvc-bp-status-03",
+		},
+	}
+	akogatewayapitests.SetupBackendPolicy(t, policyName, namespace, spec)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		condition := getBackendPolicyAcceptedCondition(t, policyName, namespace)
+		return condition != nil && condition.Status == metav1.ConditionTrue
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	akogatewayapitests.SetupBackendPolicy(t, conflictedPolicyName, namespace, spec)
+	g.Eventually(func() bool {
+		return getBackendPolicyAcceptedCondition(t, conflictedPolicyName, namespace) != nil
+	}, 30*time.Second).Should(gomega.Equal(true))
+	expectedCondition := metav1.Condition{
+		Type:    string(gatewayv1alpha2.PolicyConditionAccepted),
+		Reason:  string(gatewayv1alpha2.PolicyReasonConflicted),
+		Status:  metav1.ConditionFalse,
+		Message: "BackendPolicy " + policyName + " has the same target",
+	}
+	akogatewayapitests.ValidateConditions(t, []metav1.Condition{*getBackendPolicyAcceptedCondition(t, conflictedPolicyName, namespace)}, []metav1.Condition{expectedCondition})
+
+	// the conflicted BackendPolicy is accepted once the older one is deleted
+	akogatewayapitests.TeardownBackendPolicy(t, policyName, namespace)
+	g.Eventually(func() bool {
+		condition := getBackendPolicyAcceptedCondition(t, conflictedPolicyName, namespace)
+		return condition != nil && condition.Status == metav1.ConditionTrue
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	akogatewayapitests.TeardownBackendPolicy(t, conflictedPolicyName, namespace)
+}
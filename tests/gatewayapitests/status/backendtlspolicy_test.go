@@ -0,0 +1,125 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	akogatewayapitests "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/gatewayapitests"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+)
+
+func getBackendTLSPolicyAncestors(t *testing.T, name, namespace string) []gatewayv1alpha2.PolicyAncestorStatus {
+	backendTLSPolicy, err := akogatewayapitests.GatewayClient.GatewayV1alpha2().BackendTLSPolicies(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil || backendTLSPolicy == nil {
+		t.Logf("Couldn't get the BackendTLSPolicy, err: %+v", err)
+		return nil
+	}
+	return backendTLSPolicy.Status.Ancestors
+}
+
+/* Test cases
+ * - BackendTLSPolicy on the backend Service of an HTTPRoute, with the CA ConfigMap created and deleted
+ */
+func TestBackendTLSPolicyWithHTTPRoute(t *testing.T) {
+	gatewayClassName := "gateway-class-btls-status-01"
+	gatewayName := "gateway-btls-status-01"
+	httpRouteName := "httproute-btls-status-01"
+	policyName := "backend-tls-policy-status-01"
+	configMapName := "ca-cert-btls-status-01"
+	svcName := "avisvc-btls-status-01"
+	namespace := "default"
+	ports := []int32{8080}
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, namespace, gatewayClassName, nil, listeners)
+	integrationtest.CreateSVC(t, namespace, svcName, corev1.ProtocolTCP, corev1.ServiceTypeClusterIP, false)
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, namespace, ports)
+	rule := akogatewayapitests.GetHTTPRouteRuleV1([]string{"/foo"}, []string{}, nil,
+		[][]string{{svcName, namespace, "8080", "1"}})
+	hostnames := []gatewayv1.Hostname{"foo-8080.com"}
+	akogatewayapitests.SetupHTTPRoute(t, httpRouteName, namespace, parentRefs, hostnames, []gatewayv1.HTTPRouteRule{rule})
+
+	// the CA ConfigMap does not exist yet
+	akogatewayapitests.SetupBackendTLSPolicy(t, policyName, namespace, svcName, "backend.example.com", []string{configMapName})
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() int {
+		return len(getBackendTLSPolicyAncestors(t, policyName, namespace))
+	}, 30*time.Second).Should(gomega.Equal(1))
+	ancestor := getBackendTLSPolicyAncestors(t, policyName, namespace)[0]
+	g.Expect(string(ancestor.ControllerName)).To(gomega.Equal(akogatewayapilib.GatewayController))
+	g.Expect(string(ancestor.AncestorRef.Name)).To(gomega.Equal(gatewayName))
+	g.Expect(string(*ancestor.AncestorRef.Namespace)).To(gomega.Equal(namespace))
+	g.Expect(string(*ancestor.AncestorRef.Kind)).To(gomega.Equal("Gateway"))
+	expectedConditions := []metav1.Condition{
+		{
+			Type:    string(gatewayv1alpha2.PolicyConditionAccepted),
+			Reason:  string(gatewayv1alpha2.PolicyReasonAccepted),
+			Status:  metav1.ConditionTrue,
+			Message: "BackendTLSPolicy is accepted",
+		},
+		{
+			Type:    string(gatewayv1.RouteConditionResolvedRefs),
+			Reason:  akogatewayapilib.BackendTLSPolicyReasonInvalidCACertificateRef,
+			Status:  metav1.ConditionFalse,
+			Message: "unable to get the ConfigMap " + configMapName + ", err: configmap \"" + configMapName + "\" not found",
+		},
+	}
+	akogatewayapitests.ValidateConditions(t, ancestor.Conditions, expectedConditions)
+
+	getResolvedRefsStatus := func() metav1.ConditionStatus {
+		ancestors := getBackendTLSPolicyAncestors(t, policyName, namespace)
+		if len(ancestors) != 1 {
+			return ""
+		}
+		condition := apimeta.FindStatusCondition(ancestors[0].Conditions, string(gatewayv1.RouteConditionResolvedRefs))
+		if condition == nil {
+			return ""
+		}
+		return condition.Status
+	}
+
+	// creating the CA ConfigMap resolves the reference
+	akogatewayapitests.SetupCACertConfigMap(t, configMapName, namespace, "-----BEGIN CERTIFICATE-----\n-----END CERTIFICATE-----")
+	g.Eventually(getResolvedRefsStatus, 30*time.Second).Should(gomega.Equal(metav1.ConditionTrue))
+
+	// deleting the CA ConfigMap leaves the reference unresolved
+	akogatewayapitests.TeardownCACertConfigMap(t, configMapName, namespace)
+	g.Eventually(getResolvedRefsStatus, 30*time.Second).Should(gomega.Equal(metav1.ConditionFalse))
+
+	// the Gateway is no longer an ancestor once the route is deleted
+	akogatewayapitests.TeardownHTTPRoute(t, httpRouteName, namespace)
+	g.Eventually(func() int {
+		return len(getBackendTLSPolicyAncestors(t, policyName, namespace))
+	}, 30*time.Second).Should(gomega.Equal(0))
+
+	akogatewayapitests.TeardownBackendTLSPolicy(t, policyName, namespace)
+	integrationtest.DelSVC(t, namespace, svcName)
+	akogatewayapitests.TeardownGateway(t, gatewayName, namespace)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
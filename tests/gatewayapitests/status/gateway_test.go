@@ -33,6 +33,7 @@ import (
 	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	akocrdfake "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha1/clientset/versioned/fake"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 	tests "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/gatewayapitests"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
@@ -47,6 +48,7 @@ const (
 func TestMain(m *testing.M) {
 	tests.KubeClient = k8sfake.NewSimpleClientset()
 	tests.GatewayClient = gatewayfake.NewSimpleClientset()
+	tests.AKOClient = akocrdfake.NewSimpleClientset()
 	integrationtest.KubeClient = tests.KubeClient
 
 	// Sets the environment variables
@@ -87,8 +89,10 @@ func TestMain(m *testing.M) {
 
 	ctrl = akogatewayapik8s.SharedGatewayController()
 	ctrl.DisableSync = false
-	ctrl.InitGatewayAPIInformers(tests.GatewayClient)
+	ctrl.InitGatewayAPIInformers(tests.GatewayClient, tests.KubeClient)
 	akoControlConfig.SetGatewayAPIClientset(tests.GatewayClient)
+	ctrl.InitAKOCRDInformers(tests.AKOClient)
+	akoControlConfig.SetAKOCRDClientset(tests.AKOClient)
 
 	stopCh := utils.SetupSignalHandler()
 	ctrlCh := make(chan struct{})
@@ -533,7 +537,7 @@ func TestGatewayTransitionFromAKOControllerToNonAKOController(t *testing.T) {
 
 /* Negative test cases
  * - Gateway with no listeners
- * - Gateway with more than one static address
+ * - Gateway with more than one static IPv4 address
  * - Gateway with invalid listeners
  *    - Listeners with unsupported protocol
  *    - Listeners with invalid hostname
@@ -607,9 +611,9 @@ func TestGatewayWithMoreThanOneAddress(t *testing.T) {
 			{
 				Type:               string(gatewayv1.GatewayConditionAccepted),
 				Status:             metav1.ConditionFalse,
-				Message:            "More than one address is not supported",
+				Message:            "More than one IPv4 address is not supported",
 				ObservedGeneration: 1,
-				Reason:             string(gatewayv1.GatewayReasonInvalid),
+				Reason:             string(gatewayv1.GatewayReasonUnsupportedAddress),
 			},
 		},
 	}
@@ -719,6 +723,59 @@ func TestGatewayWithInvalidHostnameInListeners(t *testing.T) {
 	tests.TeardownGatewayClass(t, gatewayClassName)
 }
 
+func TestGatewayWithNamespaceSelectorInListeners(t *testing.T) {
+
+	gatewayName := "gateway-neg-08"
+	gatewayClassName := "gateway-class-neg-08"
+	ports := []int32{8080, 8081}
+
+	tests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := tests.GetListenersV1(ports)
+	fromSelector := gatewayv1.NamespacesFromSelector
+	listeners[0].AllowedRoutes = &gatewayv1.AllowedRoutes{
+		Namespaces: &gatewayv1.RouteNamespaces{
+			From:     &fromSelector,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "gateway"}},
+		},
+	}
+	tests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		gateway, err := tests.GatewayClient.GatewayV1().Gateways(DEFAULT_NAMESPACE).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+		if err != nil || gateway == nil {
+			t.Logf("Couldn't get the gateway, err: %+v", err)
+			return false
+		}
+		return apimeta.FindStatusCondition(gateway.Status.Conditions, string(gatewayv1.GatewayConditionAccepted)) != nil
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	expectedStatus := &gatewayv1.GatewayStatus{
+		Conditions: []metav1.Condition{
+			{
+				Type:               string(gatewayv1.GatewayConditionAccepted),
+				Status:             metav1.ConditionFalse,
+				Message:            "Gateway contains 1 invalid listener(s)",
+				ObservedGeneration: 1,
+				Reason:             string(gatewayv1.GatewayReasonListenersNotValid),
+			},
+		},
+		Listeners: tests.GetListenerStatusV1(ports, []int32{0, 0}),
+	}
+	expectedStatus.Listeners[0].Conditions[0].Reason = string(gatewayv1.ListenerReasonInvalid)
+	expectedStatus.Listeners[0].Conditions[0].Status = metav1.ConditionFalse
+	expectedStatus.Listeners[0].Conditions[0].Message = "AllowedRoutes namespaces from Selector is not supported"
+
+	gateway, err := tests.GatewayClient.GatewayV1().Gateways(DEFAULT_NAMESPACE).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+	if err != nil || gateway == nil {
+		t.Fatalf("Couldn't get the gateway, err: %+v", err)
+	}
+
+	tests.ValidateGatewayStatus(t, &gateway.Status, expectedStatus)
+	tests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	tests.TeardownGatewayClass(t, gatewayClassName)
+}
+
 func TestGatewayWithInvalidTLSConfigInListeners(t *testing.T) {
 
 	gatewayName := "gateway-neg-05"
@@ -772,3 +829,80 @@ func TestGatewayWithInvalidTLSConfigInListeners(t *testing.T) {
 		integrationtest.DeleteSecret(secret, DEFAULT_NAMESPACE)
 	}
 }
+
+func TestGatewayWithCrossNamespaceCertificateRef(t *testing.T) {
+
+	gatewayName := "gateway-neg-06"
+	gatewayClassName := "gateway-class-neg-06"
+	referenceGrantName := "referencegrant-neg-06"
+	secretNamespace := "red-ns"
+	ports := []int32{8080, 8081}
+	secrets := []string{"secret-03"}
+	for _, secret := range secrets {
+		integrationtest.AddSecret(secret, DEFAULT_NAMESPACE, "cert", "key")
+		integrationtest.AddSecret(secret, secretNamespace, "cert", "key")
+	}
+	tests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+	listeners := tests.GetListenersV1(ports, secrets...)
+	tests.SetListenerTLS(&listeners[0], gatewayv1.TLSModeTerminate, secrets[0], secretNamespace)
+	tests.SetupGateway(t, gatewayName, DEFAULT_NAMESPACE, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		gateway, err := tests.GatewayClient.GatewayV1().Gateways(DEFAULT_NAMESPACE).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+		if err != nil || gateway == nil {
+			t.Logf("Couldn't get the gateway, err: %+v", err)
+			return false
+		}
+		return apimeta.FindStatusCondition(gateway.Status.Conditions, string(gatewayv1.GatewayConditionAccepted)) != nil
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	expectedStatus := &gatewayv1.GatewayStatus{
+		Conditions: []metav1.Condition{
+			{
+				Type:               string(gatewayv1.GatewayConditionAccepted),
+				Status:             metav1.ConditionFalse,
+				Message:            "Gateway contains 1 invalid listener(s)",
+				ObservedGeneration: 1,
+				Reason:             string(gatewayv1.GatewayReasonListenersNotValid),
+			},
+		},
+		Listeners: tests.GetListenerStatusV1(ports, []int32{0, 0}),
+	}
+	expectedStatus.Listeners[0].Conditions = []metav1.Condition{
+		{
+			Type:               string(gatewayv1.ListenerConditionResolvedRefs),
+			Status:             metav1.ConditionFalse,
+			Message:            "Reference to the Secret red-ns/secret-03 is not permitted",
+			ObservedGeneration: 1,
+			Reason:             string(gatewayv1.ListenerReasonRefNotPermitted),
+		},
+	}
+
+	gateway, err := tests.GatewayClient.GatewayV1().Gateways(DEFAULT_NAMESPACE).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+	if err != nil || gateway == nil {
+		t.Fatalf("Couldn't get the gateway, err: %+v", err)
+	}
+	tests.ValidateGatewayStatus(t, &gateway.Status, expectedStatus)
+
+	// ReferenceGrant in the secret namespace permits the reference
+	tests.SetupReferenceGrant(t, referenceGrantName, secretNamespace, lib.Gateway, DEFAULT_NAMESPACE, utils.Secret)
+	g.Eventually(func() bool {
+		gateway, err := tests.GatewayClient.GatewayV1().Gateways(DEFAULT_NAMESPACE).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+		if err != nil || gateway == nil {
+			t.Logf("Couldn't get the gateway, err: %+v", err)
+			return false
+		}
+		return apimeta.IsStatusConditionTrue(gateway.Status.Conditions, string(gatewayv1.GatewayConditionAccepted)) &&
+			len(gateway.Status.Listeners) == len(ports) &&
+			apimeta.IsStatusConditionTrue(gateway.Status.Listeners[0].Conditions, string(gatewayv1.ListenerConditionResolvedRefs))
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	tests.TeardownReferenceGrant(t, referenceGrantName, secretNamespace)
+	tests.TeardownGateway(t, gatewayName, DEFAULT_NAMESPACE)
+	tests.TeardownGatewayClass(t, gatewayClassName)
+	for _, secret := range secrets {
+		integrationtest.DeleteSecret(secret, DEFAULT_NAMESPACE)
+		integrationtest.DeleteSecret(secret, secretNamespace)
+	}
+}
@@ -0,0 +1,158 @@
+/*
+ * Copyright 2024 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+
+	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
+	akogatewayapitests "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/gatewayapitests"
+)
+
+/* Test cases
+ * - GRPCRoute with valid configurations
+ * - GRPCRoute with the unsupported RequestMirror filter
+ */
+func TestGRPCRouteWithValidConfig(t *testing.T) {
+	gatewayClassName := "gateway-class-grpc-01"
+	gatewayName := "gateway-grpc-01"
+	grpcRouteName := "grpcroute-01"
+	namespace := "default"
+	ports := []int32{8080}
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, namespace, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		gateway, err := akogatewayapitests.GatewayClient.GatewayV1().Gateways(namespace).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+		if err != nil || gateway == nil {
+			t.Logf("Couldn't get the gateway, err: %+v", err)
+			return false
+		}
+		return apimeta.FindStatusCondition(gateway.Status.Conditions, string(gatewayv1.GatewayConditionAccepted)) != nil
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, namespace, ports)
+	rule := akogatewayapitests.GetGRPCRouteRuleV1alpha2("helloworld.Greeter", "SayHello",
+		[]string{"RequestHeaderModifier"}, [][]string{{"avisvc", namespace, "8080", "1"}})
+	akogatewayapitests.SetupGRPCRoute(t, grpcRouteName, namespace, parentRefs, nil, []gatewayv1alpha2.GRPCRouteRule{rule})
+
+	g.Eventually(func() bool {
+		grpcRoute, err := akogatewayapitests.GatewayClient.GatewayV1alpha2().GRPCRoutes(namespace).Get(context.TODO(), grpcRouteName, metav1.GetOptions{})
+		if err != nil || grpcRoute == nil {
+			t.Logf("Couldn't get the GRPCRoute, err: %+v", err)
+			return false
+		}
+		if len(grpcRoute.Status.Parents) != len(ports) {
+			return false
+		}
+		return apimeta.FindStatusCondition(grpcRoute.Status.Parents[0].Conditions, string(gatewayv1.RouteConditionAccepted)) != nil
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	grpcRoute, err := akogatewayapitests.GatewayClient.GatewayV1alpha2().GRPCRoutes(namespace).Get(context.TODO(), grpcRouteName, metav1.GetOptions{})
+	if err != nil || grpcRoute == nil {
+		t.Fatalf("Couldn't get the GRPCRoute, err: %+v", err)
+	}
+	expectedConditions := []metav1.Condition{
+		{
+			Type:    string(gatewayv1.RouteConditionAccepted),
+			Reason:  string(gatewayv1.RouteReasonAccepted),
+			Status:  metav1.ConditionTrue,
+			Message: "Parent reference is valid",
+		},
+	}
+	akogatewayapitests.ValidateConditions(t, grpcRoute.Status.Parents[0].Conditions, expectedConditions)
+
+	g.Eventually(func() int32 {
+		gateway, err := akogatewayapitests.GatewayClient.GatewayV1().Gateways(namespace).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+		if err != nil || gateway == nil || len(gateway.Status.Listeners) != len(ports) {
+			return -1
+		}
+		return gateway.Status.Listeners[0].AttachedRoutes
+	}, 30*time.Second).Should(gomega.Equal(int32(1)))
+
+	akogatewayapitests.TeardownGRPCRoute(t, grpcRouteName, namespace)
+	akogatewayapitests.TeardownGateway(t, gatewayName, namespace)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
+
+func TestGRPCRouteWithUnsupportedFilter(t *testing.T) {
+	gatewayClassName := "gateway-class-grpc-02"
+	gatewayName := "gateway-grpc-02"
+	grpcRouteName := "grpcroute-02"
+	namespace := "default"
+	ports := []int32{8080}
+
+	akogatewayapitests.SetupGatewayClass(t, gatewayClassName, akogatewayapilib.GatewayController)
+
+	listeners := akogatewayapitests.GetListenersV1(ports)
+	akogatewayapitests.SetupGateway(t, gatewayName, namespace, gatewayClassName, nil, listeners)
+
+	g := gomega.NewGomegaWithT(t)
+	g.Eventually(func() bool {
+		gateway, err := akogatewayapitests.GatewayClient.GatewayV1().Gateways(namespace).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+		if err != nil || gateway == nil {
+			t.Logf("Couldn't get the gateway, err: %+v", err)
+			return false
+		}
+		return apimeta.FindStatusCondition(gateway.Status.Conditions, string(gatewayv1.GatewayConditionAccepted)) != nil
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	parentRefs := akogatewayapitests.GetParentReferencesV1([]string{gatewayName}, namespace, ports)
+	rule := akogatewayapitests.GetGRPCRouteRuleV1alpha2("helloworld.Greeter", "",
+		[]string{"RequestMirror"}, [][]string{{"avisvc", namespace, "8080", "1"}})
+	akogatewayapitests.SetupGRPCRoute(t, grpcRouteName, namespace, parentRefs, nil, []gatewayv1alpha2.GRPCRouteRule{rule})
+
+	g.Eventually(func() bool {
+		grpcRoute, err := akogatewayapitests.GatewayClient.GatewayV1alpha2().GRPCRoutes(namespace).Get(context.TODO(), grpcRouteName, metav1.GetOptions{})
+		if err != nil || grpcRoute == nil {
+			t.Logf("Couldn't get the GRPCRoute, err: %+v", err)
+			return false
+		}
+		if len(grpcRoute.Status.Parents) != len(ports) {
+			return false
+		}
+		return apimeta.IsStatusConditionFalse(grpcRoute.Status.Parents[0].Conditions, string(gatewayv1.RouteConditionAccepted))
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	grpcRoute, err := akogatewayapitests.GatewayClient.GatewayV1alpha2().GRPCRoutes(namespace).Get(context.TODO(), grpcRouteName, metav1.GetOptions{})
+	if err != nil || grpcRoute == nil {
+		t.Fatalf("Couldn't get the GRPCRoute, err: %+v", err)
+	}
+	expectedConditions := []metav1.Condition{
+		{
+			Type:    string(gatewayv1.RouteConditionAccepted),
+			Reason:  string(gatewayv1.RouteReasonUnsupportedValue),
+			Status:  metav1.ConditionFalse,
+			Message: "RequestMirror filter is not supported",
+		},
+	}
+	akogatewayapitests.ValidateConditions(t, grpcRoute.Status.Parents[0].Conditions, expectedConditions)
+
+	akogatewayapitests.TeardownGRPCRoute(t, grpcRouteName, namespace)
+	akogatewayapitests.TeardownGateway(t, gatewayName, namespace)
+	akogatewayapitests.TeardownGatewayClass(t, gatewayClassName)
+}
@@ -27,20 +27,26 @@ import (
 
 	"github.com/onsi/gomega"
 	"google.golang.org/protobuf/proto"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gatewayv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 	gatewayfake "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned/fake"
 
 	akogatewayapilib "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-gateway-api/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
+	akov1alpha1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha1"
+	akocrdfake "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha1/clientset/versioned/fake"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
 )
 
 var KubeClient *k8sfake.Clientset
 var GatewayClient *gatewayfake.Clientset
+var AKOClient *akocrdfake.Clientset
 
 func NewAviFakeClientInstance(kubeclient *k8sfake.Clientset, skipCachePopulation ...bool) {
 	if integrationtest.AviFakeClientInstance == nil {
@@ -48,8 +54,8 @@ func NewAviFakeClientInstance(kubeclient *k8sfake.Clientset, skipCachePopulation
 			w.Header().Set("Content-Type", "application/json")
 			utils.AviLog.Infof("[fakeAPI]: %s %s", r.Method, r.URL)
 
-			if integrationtest.FakeServerMiddleware != nil {
-				integrationtest.FakeServerMiddleware(w, r)
+			if middleware := integrationtest.GetMiddleware(); middleware != nil {
+				middleware(w, r)
 				return
 			}
 
@@ -61,6 +67,8 @@ func NewAviFakeClientInstance(kubeclient *k8sfake.Clientset, skipCachePopulation
 		os.Setenv("FULL_SYNC_INTERVAL", "600")
 		// resets avi client pool instance, allows to connect with the new `ts` server
 		cache.AviClientInstance = nil
+		cache.AviConcurrentClientInstance = nil
+		cache.AviDriftClientInstance = nil
 		k8s.PopulateControllerProperties(kubeclient)
 		if len(skipCachePopulation) == 0 || !skipCachePopulation[0] {
 			k8s.PopulateCache()
@@ -411,10 +419,61 @@ func GetHTTPRouteFilterV1(filterType string, actions []string) gatewayv1.HTTPRou
 			Hostname:   (*gatewayv1.PreciseHostname)(&host),
 			StatusCode: &statusCode302,
 		}
+		for _, action := range actions {
+			switch action {
+			case "scheme":
+				scheme := "https"
+				routeFilter.RequestRedirect.Scheme = &scheme
+			case "port":
+				port := gatewayv1.PortNumber(8443)
+				routeFilter.RequestRedirect.Port = &port
+			default:
+				routeFilter.RequestRedirect.Path = GetHTTPPathModifierV1(action)
+			}
+		}
+	case "URLRewrite":
+		host := "rewrite.com"
+		routeFilter.URLRewrite = &gatewayv1.HTTPURLRewriteFilter{
+			Hostname: (*gatewayv1.PreciseHostname)(&host),
+		}
+		for _, action := range actions {
+			routeFilter.URLRewrite.Path = GetHTTPPathModifierV1(action)
+		}
+	case "RequestMirror":
+		port := gatewayv1.PortNumber(8080)
+		routeFilter.RequestMirror = &gatewayv1.HTTPRequestMirrorFilter{
+			BackendRef: gatewayv1.BackendObjectReference{
+				Name: gatewayv1.ObjectName("avisvc-mirror"),
+				Port: &port,
+			},
+		}
+	case "ExtensionRef":
+		routeFilter.ExtensionRef = &gatewayv1.LocalObjectReference{
+			Group: gatewayv1.Group("example.com"),
+			Kind:  gatewayv1.Kind("Filter"),
+			Name:  gatewayv1.ObjectName("filter"),
+		}
 	}
 	return routeFilter
 }
 
+func GetHTTPPathModifierV1(action string) *gatewayv1.HTTPPathModifier {
+	path := "/bar"
+	switch action {
+	case "fullpath":
+		return &gatewayv1.HTTPPathModifier{
+			Type:            gatewayv1.FullPathHTTPPathModifier,
+			ReplaceFullPath: &path,
+		}
+	case "prefix":
+		return &gatewayv1.HTTPPathModifier{
+			Type:               gatewayv1.PrefixMatchHTTPPathModifier,
+			ReplacePrefixMatch: &path,
+		}
+	}
+	return nil
+}
+
 func GetHTTPRouteBackendV1(backendRefs []string) gatewayv1.HTTPBackendRef {
 	serviceKind := gatewayv1.Kind("Service")
 	port, _ := strconv.Atoi(backendRefs[2])
@@ -533,14 +592,317 @@ func TeardownHTTPRoute(t *testing.T, name, namespace string) {
 	hr.Delete(t)
 }
 
+func GetL4RouteBackendRefs(backendRefs [][]string) []gatewayv1.BackendRef {
+	backends := make([]gatewayv1.BackendRef, 0, len(backendRefs))
+	for _, backendRef := range backendRefs {
+		backends = append(backends, GetHTTPRouteBackendV1(backendRef).BackendRef)
+	}
+	return backends
+}
+
+func SetupTCPRoute(t *testing.T, name, namespace string, parentRefs []gatewayv1.ParentReference, backendRefs [][]string) {
+	tcpRoute := &gatewayv1alpha2.TCPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			ResourceVersion: time.Now().Local().String(),
+		},
+		Spec: gatewayv1alpha2.TCPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: parentRefs},
+			Rules:           []gatewayv1alpha2.TCPRouteRule{{BackendRefs: GetL4RouteBackendRefs(backendRefs)}},
+		},
+	}
+	_, err := GatewayClient.GatewayV1alpha2().TCPRoutes(namespace).Create(context.TODO(), tcpRoute, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't create the TCPRoute, err: %+v", err)
+	}
+	t.Logf("Created TCPRoute %s", name)
+}
+
+func TeardownTCPRoute(t *testing.T, name, namespace string) {
+	err := GatewayClient.GatewayV1alpha2().TCPRoutes(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't delete the TCPRoute, err: %+v", err)
+	}
+	t.Logf("Deleted TCPRoute %s", name)
+}
+
+func SetupUDPRoute(t *testing.T, name, namespace string, parentRefs []gatewayv1.ParentReference, backendRefs [][]string) {
+	udpRoute := &gatewayv1alpha2.UDPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			ResourceVersion: time.Now().Local().String(),
+		},
+		Spec: gatewayv1alpha2.UDPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: parentRefs},
+			Rules:           []gatewayv1alpha2.UDPRouteRule{{BackendRefs: GetL4RouteBackendRefs(backendRefs)}},
+		},
+	}
+	_, err := GatewayClient.GatewayV1alpha2().UDPRoutes(namespace).Create(context.TODO(), udpRoute, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't create the UDPRoute, err: %+v", err)
+	}
+	t.Logf("Created UDPRoute %s", name)
+}
+
+func TeardownUDPRoute(t *testing.T, name, namespace string) {
+	err := GatewayClient.GatewayV1alpha2().UDPRoutes(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't delete the UDPRoute, err: %+v", err)
+	}
+	t.Logf("Deleted UDPRoute %s", name)
+}
+
+func SetupTLSRoute(t *testing.T, name, namespace string, parentRefs []gatewayv1.ParentReference, hostnames []gatewayv1.Hostname, backendRefs [][]string) {
+	tlsRoute := &gatewayv1alpha2.TLSRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			ResourceVersion: time.Now().Local().String(),
+		},
+		Spec: gatewayv1alpha2.TLSRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: parentRefs},
+			Hostnames:       hostnames,
+			Rules:           []gatewayv1alpha2.TLSRouteRule{{BackendRefs: GetL4RouteBackendRefs(backendRefs)}},
+		},
+	}
+	_, err := GatewayClient.GatewayV1alpha2().TLSRoutes(namespace).Create(context.TODO(), tlsRoute, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't create the TLSRoute, err: %+v", err)
+	}
+	t.Logf("Created TLSRoute %s", name)
+}
+
+func TeardownTLSRoute(t *testing.T, name, namespace string) {
+	err := GatewayClient.GatewayV1alpha2().TLSRoutes(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't delete the TLSRoute, err: %+v", err)
+	}
+	t.Logf("Deleted TLSRoute %s", name)
+}
+
+func GetGRPCRouteRuleV1alpha2(service, method string, filterTypes []string, backendRefs [][]string) gatewayv1alpha2.GRPCRouteRule {
+	rule := gatewayv1alpha2.GRPCRouteRule{}
+	if service != "" || method != "" {
+		methodMatch := &gatewayv1alpha2.GRPCMethodMatch{}
+		if service != "" {
+			methodMatch.Service = &service
+		}
+		if method != "" {
+			methodMatch.Method = &method
+		}
+		rule.Matches = []gatewayv1alpha2.GRPCRouteMatch{{Method: methodMatch}}
+	}
+	for _, filterType := range filterTypes {
+		filter := gatewayv1alpha2.GRPCRouteFilter{Type: gatewayv1alpha2.GRPCRouteFilterType(filterType)}
+		switch filter.Type {
+		case gatewayv1alpha2.GRPCRouteFilterRequestHeaderModifier:
+			filter.RequestHeaderModifier = GetHTTPHeaderFilterV1([]string{"add"})
+		case gatewayv1alpha2.GRPCRouteFilterResponseHeaderModifier:
+			filter.ResponseHeaderModifier = GetHTTPHeaderFilterV1([]string{"add"})
+		case gatewayv1alpha2.GRPCRouteFilterRequestMirror:
+			filter.RequestMirror = &gatewayv1.HTTPRequestMirrorFilter{BackendRef: gatewayv1.BackendObjectReference{Name: "avisvc"}}
+		}
+		rule.Filters = append(rule.Filters, filter)
+	}
+	for _, backendRef := range GetL4RouteBackendRefs(backendRefs) {
+		rule.BackendRefs = append(rule.BackendRefs, gatewayv1alpha2.GRPCBackendRef{BackendRef: backendRef})
+	}
+	return rule
+}
+
+func SetupGRPCRoute(t *testing.T, name, namespace string, parentRefs []gatewayv1.ParentReference, hostnames []gatewayv1.Hostname, rules []gatewayv1alpha2.GRPCRouteRule) {
+	grpcRoute := &gatewayv1alpha2.GRPCRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			ResourceVersion: time.Now().Local().String(),
+		},
+		Spec: gatewayv1alpha2.GRPCRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{ParentRefs: parentRefs},
+			Hostnames:       hostnames,
+			Rules:           rules,
+		},
+	}
+	_, err := GatewayClient.GatewayV1alpha2().GRPCRoutes(namespace).Create(context.TODO(), grpcRoute, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't create the GRPCRoute, err: %+v", err)
+	}
+	t.Logf("Created GRPCRoute %s", name)
+}
+
+func TeardownGRPCRoute(t *testing.T, name, namespace string) {
+	err := GatewayClient.GatewayV1alpha2().GRPCRoutes(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't delete the GRPCRoute, err: %+v", err)
+	}
+	t.Logf("Deleted GRPCRoute %s", name)
+}
+
+func SetupReferenceGrant(t *testing.T, name, namespace, fromKind, fromNamespace, toKind string) {
+	referenceGrant := &gatewayv1beta1.ReferenceGrant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			ResourceVersion: time.Now().Local().String(),
+		},
+		Spec: gatewayv1beta1.ReferenceGrantSpec{
+			From: []gatewayv1beta1.ReferenceGrantFrom{
+				{
+					Group:     gatewayv1.GroupName,
+					Kind:      gatewayv1.Kind(fromKind),
+					Namespace: gatewayv1.Namespace(fromNamespace),
+				},
+			},
+			To: []gatewayv1beta1.ReferenceGrantTo{
+				{
+					Kind: gatewayv1.Kind(toKind),
+				},
+			},
+		},
+	}
+	_, err := GatewayClient.GatewayV1beta1().ReferenceGrants(namespace).Create(context.TODO(), referenceGrant, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't create the ReferenceGrant, err: %+v", err)
+	}
+	t.Logf("Created ReferenceGrant %s", name)
+}
+
+func TeardownReferenceGrant(t *testing.T, name, namespace string) {
+	err := GatewayClient.GatewayV1beta1().ReferenceGrants(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't delete the ReferenceGrant, err: %+v", err)
+	}
+	t.Logf("Deleted ReferenceGrant %s", name)
+}
+
+func SetupBackendTLSPolicy(t *testing.T, name, namespace, svcName, hostname string, caCertRefs []string) {
+	backendTLSPolicy := &gatewayv1alpha2.BackendTLSPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			ResourceVersion: time.Now().Local().String(),
+		},
+		Spec: gatewayv1alpha2.BackendTLSPolicySpec{
+			TargetRef: gatewayv1alpha2.PolicyTargetReferenceWithSectionName{
+				PolicyTargetReference: gatewayv1alpha2.PolicyTargetReference{
+					Kind: "Service",
+					Name: gatewayv1.ObjectName(svcName),
+				},
+			},
+			TLS: gatewayv1alpha2.BackendTLSPolicyConfig{
+				Hostname: gatewayv1.PreciseHostname(hostname),
+			},
+		},
+	}
+	for _, caCertRef := range caCertRefs {
+		backendTLSPolicy.Spec.TLS.CACertRefs = append(backendTLSPolicy.Spec.TLS.CACertRefs, gatewayv1.LocalObjectReference{
+			Kind: "ConfigMap",
+			Name: gatewayv1.ObjectName(caCertRef),
+		})
+	}
+	_, err := GatewayClient.GatewayV1alpha2().BackendTLSPolicies(namespace).Create(context.TODO(), backendTLSPolicy, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't create the BackendTLSPolicy, err: %+v", err)
+	}
+	t.Logf("Created BackendTLSPolicy %s", name)
+}
+
+func TeardownBackendTLSPolicy(t *testing.T, name, namespace string) {
+	err := GatewayClient.GatewayV1alpha2().BackendTLSPolicies(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't delete the BackendTLSPolicy, err: %+v", err)
+	}
+	t.Logf("Deleted BackendTLSPolicy %s", name)
+}
+
+func SetupBackendPolicy(t *testing.T, name, namespace string, spec akov1alpha1.BackendPolicySpec) {
+	backendPolicy := &akov1alpha1.BackendPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         namespace,
+			ResourceVersion:   time.Now().Local().String(),
+			CreationTimestamp: metav1.Now(),
+		},
+		Spec: spec,
+	}
+	_, err := AKOClient.AkoV1alpha1().BackendPolicies(namespace).Create(context.TODO(), backendPolicy, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't create the BackendPolicy, err: %+v", err)
+	}
+	t.Logf("Created BackendPolicy %s", name)
+}
+
+func UpdateBackendPolicy(t *testing.T, name, namespace string, spec akov1alpha1.BackendPolicySpec) {
+	backendPolicy, err := AKOClient.AkoV1alpha1().BackendPolicies(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't get the BackendPolicy, err: %+v", err)
+	}
+	backendPolicy.Spec = spec
+	backendPolicy.ResourceVersion = time.Now().Local().String()
+	_, err = AKOClient.AkoV1alpha1().BackendPolicies(namespace).Update(context.TODO(), backendPolicy, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't update the BackendPolicy, err: %+v", err)
+	}
+	t.Logf("Updated BackendPolicy %s", name)
+}
+
+func TeardownBackendPolicy(t *testing.T, name, namespace string) {
+	err := AKOClient.AkoV1alpha1().BackendPolicies(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't delete the BackendPolicy, err: %+v", err)
+	}
+	t.Logf("Deleted BackendPolicy %s", name)
+}
+
+func SetupCACertConfigMap(t *testing.T, name, namespace, caCert string) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Data: map[string]string{"ca.crt": caCert},
+	}
+	_, err := KubeClient.CoreV1().ConfigMaps(namespace).Create(context.TODO(), configMap, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't create the ConfigMap, err: %+v", err)
+	}
+	t.Logf("Created ConfigMap %s", name)
+}
+
+func UpdateCACertConfigMap(t *testing.T, name, namespace, caCert string) {
+	configMap, err := KubeClient.CoreV1().ConfigMaps(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't get the ConfigMap, err: %+v", err)
+	}
+	configMap.Data = map[string]string{"ca.crt": caCert}
+	configMap.ResourceVersion = time.Now().Local().String()
+	_, err = KubeClient.CoreV1().ConfigMaps(namespace).Update(context.TODO(), configMap, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't update the ConfigMap, err: %+v", err)
+	}
+	t.Logf("Updated ConfigMap %s", name)
+}
+
+func TeardownCACertConfigMap(t *testing.T, name, namespace string) {
+	err := KubeClient.CoreV1().ConfigMaps(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("Couldn't delete the ConfigMap, err: %+v", err)
+	}
+	t.Logf("Deleted ConfigMap %s", name)
+}
+
 func ValidateGatewayStatus(t *testing.T, actualStatus, expectedStatus *gatewayv1.GatewayStatus) {
 
 	g := gomega.NewGomegaWithT(t)
 
 	// validate the ip address
 	if len(expectedStatus.Addresses) > 0 {
-		g.Expect(actualStatus.Addresses).To(gomega.HaveLen(1))
-		g.Expect(actualStatus.Addresses[0]).Should(gomega.Equal(expectedStatus.Addresses[0]))
+		g.Expect(actualStatus.Addresses).To(gomega.HaveLen(len(expectedStatus.Addresses)))
+		for i := range expectedStatus.Addresses {
+			g.Expect(actualStatus.Addresses[i]).Should(gomega.Equal(expectedStatus.Addresses[i]))
+		}
 	}
 
 	ValidateConditions(t, actualStatus.Conditions, expectedStatus.Conditions)
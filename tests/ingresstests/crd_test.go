@@ -15,20 +15,28 @@
 package ingresstests
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"net/http/httptest"
 	"sort"
 	"testing"
 	"time"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1beta1"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
 
 	"github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -702,6 +710,287 @@ func TestHostruleAnalyticsPolicyUpdate(t *testing.T) {
 	TearDownIngressForCacheSyncCheck(t, modelName)
 }
 
+func TestHostruleRateLimit(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	hrname := "rl-hr-foo"
+	SetUpIngressForCacheSyncCheck(t, true, true, modelName)
+
+	integrationtest.SetupHostRule(t, hrname, "foo.com", true)
+
+	g.Eventually(func() string {
+		hostrule, _ := v1beta1CRDClient.AkoV1beta1().HostRules("default").Get(context.TODO(), hrname, metav1.GetOptions{})
+		return hostrule.Status.Status
+	}, 10*time.Second).Should(gomega.Equal("Accepted"))
+
+	sniVSKey := cache.NamespaceName{Namespace: "admin", Name: "cluster--foo.com"}
+	integrationtest.VerifyMetadataHostRule(t, g, sniVSKey, "default/rl-hr-foo", true)
+
+	getRateLimitPolicy := func() *avinodes.AviHttpPolicySetNode {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(nodes) != 1 || len(nodes[0].SniNodes) != 1 {
+			return nil
+		}
+		for _, policy := range nodes[0].SniNodes[0].HttpPolicyRefs {
+			if policy.Name == lib.GetRateLimitPolicyName(nodes[0].SniNodes[0].Name) {
+				return policy
+			}
+		}
+		return nil
+	}
+	g.Expect(getRateLimitPolicy()).To(gomega.BeNil())
+
+	// Limit the requests per client IP, the connections limit is ignored for the child VS.
+	hrUpdate := integrationtest.FakeHostRule{
+		Name:      hrname,
+		Namespace: "default",
+		Fqdn:      "foo.com",
+	}.HostRule()
+	hrUpdate.Spec.VirtualHost.RateLimit = &v1beta1.HostRuleRateLimit{
+		Requests: &v1beta1.HostRuleRequestRateLimit{
+			Count:  100,
+			Period: 1,
+			Burst:  20,
+			Key:    v1beta1.HostRuleRateLimitKeyClientIP,
+			Action: v1beta1.HostRuleRateLimitAction{Type: v1beta1.HostRuleRateLimitActionTooManyRequests},
+		},
+		Connections: &v1beta1.HostRuleConnectionRateLimit{
+			Count:  1000,
+			Period: 1,
+		},
+	}
+	hrUpdate.ResourceVersion = "2"
+	if _, err := v1beta1CRDClient.AkoV1beta1().HostRules("default").Update(context.TODO(), hrUpdate, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating HostRule: %v", err)
+	}
+
+	g.Eventually(func() bool {
+		return getRateLimitPolicy() != nil
+	}, 10*time.Second).Should(gomega.BeTrue())
+	policy := getRateLimitPolicy()
+	g.Expect(policy.SecurityRules).To(gomega.HaveLen(1))
+	g.Expect(policy.SecurityRules[0].Action).To(gomega.Equal(lib.RATE_LIMIT))
+	rateProfile := policy.SecurityRules[0].RateProfile
+	g.Expect(*rateProfile.PerClientIP).To(gomega.BeTrue())
+	g.Expect(*rateProfile.RateLimiter.Count).To(gomega.Equal(uint32(100)))
+	g.Expect(*rateProfile.RateLimiter.Period).To(gomega.Equal(uint32(1)))
+	g.Expect(rateProfile.RateLimiter.BurstSz).To(gomega.Equal(uint32(20)))
+	g.Expect(*rateProfile.Action.Type).To(gomega.Equal(lib.RL_ACTION_LOCAL_RSP))
+	g.Expect(*rateProfile.Action.StatusCode).To(gomega.Equal(lib.STATUS_TOO_MANY_REQUESTS))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	g.Expect(nodes[0].SniNodes[0].RequestsRateLimit).To(gomega.BeNil())
+	g.Expect(nodes[0].SniNodes[0].ConnectionsRateLimit).To(gomega.BeNil())
+
+	mcache := cache.SharedAviObjCache()
+	g.Eventually(func() bool {
+		sniCache, found := mcache.VsCacheMeta.AviCacheGet(sniVSKey)
+		if !found {
+			return false
+		}
+		for _, httpKey := range sniCache.(*cache.AviVsCache).HTTPKeyCollection {
+			if httpKey.Name == policy.Name {
+				return true
+			}
+		}
+		return false
+	}, 10*time.Second).Should(gomega.BeTrue())
+
+	// Limit the requests per header, which is set on the VS instead of the HTTP policy set.
+	hrUpdate.Spec.VirtualHost.RateLimit = &v1beta1.HostRuleRateLimit{
+		Requests: &v1beta1.HostRuleRequestRateLimit{
+			Count:  10,
+			Period: 60,
+			Key:    v1beta1.HostRuleRateLimitKeyHeader,
+			Header: "X-Api-Key",
+			Action: v1beta1.HostRuleRateLimitAction{
+				Type:     v1beta1.HostRuleRateLimitActionRedirect,
+				Redirect: &v1beta1.HostRuleRateLimitRedirect{Host: "busy.foo.com", Path: "/retry"},
+			},
+		},
+	}
+	hrUpdate.ResourceVersion = "3"
+	if _, err := v1beta1CRDClient.AkoV1beta1().HostRules("default").Update(context.TODO(), hrUpdate, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating HostRule: %v", err)
+	}
+
+	g.Eventually(func() bool {
+		return getRateLimitPolicy() == nil
+	}, 10*time.Second).Should(gomega.BeTrue())
+	g.Eventually(func() bool {
+		_, aviModel = objects.SharedAviGraphLister().Get(modelName)
+		nodes = aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		return len(nodes[0].SniNodes) == 1 && nodes[0].SniNodes[0].RequestsRateLimit != nil
+	}, 10*time.Second).Should(gomega.BeTrue())
+	requestsRateLimit := nodes[0].SniNodes[0].RequestsRateLimit
+	g.Expect(*requestsRateLimit.HTTPHeader).To(gomega.Equal("X-Api-Key"))
+	g.Expect(*requestsRateLimit.RateLimiter.Count).To(gomega.Equal(uint32(10)))
+	g.Expect(*requestsRateLimit.Action.Type).To(gomega.Equal(lib.RL_ACTION_REDIRECT))
+	g.Expect(*requestsRateLimit.Action.Redirect.Host.Tokens[0].StrValue).To(gomega.Equal("busy.foo.com"))
+	g.Expect(*requestsRateLimit.Action.Redirect.Path.Tokens[0].StrValue).To(gomega.Equal("retry"))
+	g.Expect(*requestsRateLimit.Action.Redirect.StatusCode).To(gomega.Equal(lib.STATUS_REDIRECT))
+
+	// A header key without the header name is rejected.
+	hrUpdate.Spec.VirtualHost.RateLimit.Requests.Header = ""
+	hrUpdate.ResourceVersion = "4"
+	if _, err := v1beta1CRDClient.AkoV1beta1().HostRules("default").Update(context.TODO(), hrUpdate, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating HostRule: %v", err)
+	}
+	g.Eventually(func() string {
+		hostrule, _ := v1beta1CRDClient.AkoV1beta1().HostRules("default").Get(context.TODO(), hrname, metav1.GetOptions{})
+		return hostrule.Status.Status
+	}, 10*time.Second).Should(gomega.Equal("Rejected"))
+
+	integrationtest.TeardownHostRule(t, g, sniVSKey, hrname)
+	g.Eventually(func() bool {
+		_, aviModel = objects.SharedAviGraphLister().Get(modelName)
+		nodes = aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		return len(nodes[0].SniNodes) == 1 && nodes[0].SniNodes[0].RequestsRateLimit == nil
+	}, 10*time.Second).Should(gomega.BeTrue())
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
+
+func TestHostruleClientCertificate(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	hrname := "mtls-hr-foo"
+	SetUpIngressForCacheSyncCheck(t, true, true, modelName)
+
+	caSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "client-ca", Namespace: "default"},
+		Data:       map[string][]byte{"ca.crt": []byte("clientCACert")},
+	}
+	if _, err := KubeClient.CoreV1().Secrets("default").Create(context.TODO(), caSecret, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Secret: %v", err)
+	}
+	g.Eventually(func() error {
+		_, err := utils.GetInformers().SecretInformer.Lister().Secrets("default").Get("client-ca")
+		return err
+	}, 10*time.Second).Should(gomega.BeNil())
+
+	hostrule := integrationtest.FakeHostRule{
+		Name:      hrname,
+		Namespace: "default",
+		Fqdn:      "foo.com",
+	}.HostRule()
+	hostrule.Spec.VirtualHost.TLS.ClientCertificate = &v1beta1.HostRuleClientCertificate{
+		Mode: v1beta1.HostRuleClientCertificateModeRequire,
+		CA:   v1beta1.HostRuleSecret{Name: "client-ca", Type: v1beta1.HostRuleSecretTypeSecretReference},
+		Headers: []v1beta1.HostRuleClientCertificateHeader{
+			{Name: "X-Client-Subject", Value: v1beta1.HostRuleClientCertificateHeaderSubject},
+		},
+	}
+	if _, err := v1beta1CRDClient.AkoV1beta1().HostRules("default").Create(context.TODO(), hostrule, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding HostRule: %v", err)
+	}
+	g.Eventually(func() string {
+		hostrule, _ := v1beta1CRDClient.AkoV1beta1().HostRules("default").Get(context.TODO(), hrname, metav1.GetOptions{})
+		return hostrule.Status.Status
+	}, 10*time.Second).Should(gomega.Equal("Accepted"))
+
+	sniVSKey := cache.NamespaceName{Namespace: "admin", Name: "cluster--foo.com"}
+	integrationtest.VerifyMetadataHostRule(t, g, sniVSKey, "default/mtls-hr-foo", true)
+
+	appProfileName := lib.GetClientCertAppProfileName("cluster--foo.com")
+	pkiProfileName := lib.GetClientCertPKIProfileName("cluster--foo.com")
+	appProfileKey := cache.NamespaceName{Namespace: "admin", Name: appProfileName}
+	pkiProfileKey := cache.NamespaceName{Namespace: "admin", Name: pkiProfileName}
+	getSniNode := func() *avinodes.AviVsNode {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(nodes) != 1 || len(nodes[0].SniNodes) != 1 {
+			return nil
+		}
+		return nodes[0].SniNodes[0]
+	}
+
+	g.Eventually(func() int {
+		if sniNode := getSniNode(); sniNode != nil {
+			return len(sniNode.AppProfileRefs)
+		}
+		return 0
+	}, 10*time.Second).Should(gomega.Equal(1))
+	sniNode := getSniNode()
+	g.Expect(*sniNode.ApplicationProfileRef).To(gomega.Equal("/api/applicationprofile?name=" + appProfileName))
+	appProfile := sniNode.AppProfileRefs[0]
+	g.Expect(appProfile.Name).To(gomega.Equal(appProfileName))
+	g.Expect(appProfile.ClientCertificateMode).To(gomega.Equal(lib.SSL_CLIENT_CERTIFICATE_REQUIRE))
+	g.Expect(appProfile.PkiProfileName).To(gomega.Equal(pkiProfileName))
+	g.Expect(appProfile.PkiProfile).NotTo(gomega.BeNil())
+	g.Expect(appProfile.PkiProfile.CACert).To(gomega.Equal("clientCACert"))
+	g.Expect(appProfile.ClientCertHeaders).To(gomega.HaveLen(1))
+	g.Expect(*appProfile.ClientCertHeaders[0].RequestHeader).To(gomega.Equal("X-Client-Subject"))
+	g.Expect(*appProfile.ClientCertHeaders[0].RequestHeaderValue).To(gomega.Equal("HTTP_POLICY_VAR_SSL_CLIENT_SUBJECT"))
+
+	mcache := cache.SharedAviObjCache()
+	g.Eventually(func() bool {
+		sniCache, found := mcache.VsCacheMeta.AviCacheGet(sniVSKey)
+		if !found {
+			return false
+		}
+		for _, key := range sniCache.(*cache.AviVsCache).AppProfileCollection {
+			if key == appProfileKey {
+				return true
+			}
+		}
+		return false
+	}, 10*time.Second).Should(gomega.BeTrue())
+	appProfileCache, found := mcache.AppProfileCache.AviCacheGet(appProfileKey)
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(appProfileCache.(*cache.AviAppProfileCache).PkiProfileCollection).To(gomega.Equal(pkiProfileKey))
+	_, found = mcache.PKIProfileCache.AviCacheGet(pkiProfileKey)
+	g.Expect(found).To(gomega.BeTrue())
+
+	// Use an Avi PKI profile, the PKI profile created from the Secret is deleted.
+	hostrule.Spec.VirtualHost.TLS.ClientCertificate = &v1beta1.HostRuleClientCertificate{
+		Mode: v1beta1.HostRuleClientCertificateModeRequest,
+		CA:   v1beta1.HostRuleSecret{Name: "thisisaviref-pki", Type: v1beta1.HostRuleSecretTypeAviReference},
+	}
+	hostrule.ResourceVersion = "2"
+	if _, err := v1beta1CRDClient.AkoV1beta1().HostRules("default").Update(context.TODO(), hostrule, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating HostRule: %v", err)
+	}
+	g.Eventually(func() bool {
+		sniNode := getSniNode()
+		return sniNode != nil && len(sniNode.AppProfileRefs) == 1 && sniNode.AppProfileRefs[0].PkiProfile == nil
+	}, 10*time.Second).Should(gomega.BeTrue())
+	appProfile = getSniNode().AppProfileRefs[0]
+	g.Expect(appProfile.ClientCertificateMode).To(gomega.Equal(lib.SSL_CLIENT_CERTIFICATE_REQUEST))
+	g.Expect(appProfile.PkiProfileName).To(gomega.Equal("thisisaviref-pki"))
+	g.Expect(appProfile.ClientCertHeaders).To(gomega.BeEmpty())
+	g.Eventually(func() bool {
+		_, found := mcache.PKIProfileCache.AviCacheGet(pkiProfileKey)
+		return found
+	}, 10*time.Second).Should(gomega.BeFalse())
+
+	// The client certificate can not be configured along with an application profile.
+	hostrule.Spec.VirtualHost.ApplicationProfile = "thisisaviref-appprof"
+	hostrule.ResourceVersion = "3"
+	if _, err := v1beta1CRDClient.AkoV1beta1().HostRules("default").Update(context.TODO(), hostrule, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating HostRule: %v", err)
+	}
+	g.Eventually(func() string {
+		hostrule, _ := v1beta1CRDClient.AkoV1beta1().HostRules("default").Get(context.TODO(), hrname, metav1.GetOptions{})
+		return hostrule.Status.Status
+	}, 10*time.Second).Should(gomega.Equal("Rejected"))
+
+	integrationtest.TeardownHostRule(t, g, sniVSKey, hrname)
+	g.Eventually(func() bool {
+		sniNode := getSniNode()
+		return sniNode != nil && len(sniNode.AppProfileRefs) == 0 && sniNode.ApplicationProfileRef == nil
+	}, 10*time.Second).Should(gomega.BeTrue())
+	g.Eventually(func() bool {
+		_, found := mcache.AppProfileCache.AviCacheGet(appProfileKey)
+		return found
+	}, 10*time.Second).Should(gomega.BeFalse())
+	KubeClient.CoreV1().Secrets("default").Delete(context.TODO(), "client-ca", metav1.DeleteOptions{})
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
+
 func TestHostruleFQDNAliases(t *testing.T) {
 	g := gomega.NewGomegaWithT(t)
 
@@ -1501,3 +1790,381 @@ func TestHTTPRuleWithInvalidPath(t *testing.T) {
 
 	TearDownIngressForCacheSyncCheck(t, modelName)
 }
+
+func setupHTTPRuleBackendServices(t *testing.T) {
+	integrationtest.CreateSVC(t, "default", "avisvc-canary", corev1.ProtocolTCP, corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, "default", "avisvc-canary", false, false, "2.2.2")
+	integrationtest.CreateSVC(t, "default", "avisvc-beta", corev1.ProtocolTCP, corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, "default", "avisvc-beta", false, false, "3.3.3")
+}
+
+func teardownHTTPRuleBackendServices(t *testing.T) {
+	for _, svc := range []string{"avisvc-canary", "avisvc-beta"} {
+		integrationtest.DelSVC(t, "default", svc)
+		integrationtest.DelEP(t, "default", svc)
+	}
+}
+
+func httpRuleWithBackends(rrname, path string, backends []v1beta1.HTTPRuleBackend) *v1beta1.HTTPRule {
+	httprule := integrationtest.FakeHTTPRule{
+		Name:           rrname,
+		Namespace:      "default",
+		Fqdn:           "foo.com",
+		PathProperties: []integrationtest.FakeHTTPRulePath{{Path: path}},
+	}.HTTPRule()
+	httprule.Spec.Paths[0].TLS = v1beta1.HTTPRuleTLS{}
+	httprule.Spec.Paths[0].Backends = backends
+	return httprule
+}
+
+func TestHTTPRuleBackends(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	rrname := "samplerr-foo"
+	setupHTTPRuleBackendServices(t)
+	SetUpIngressForCacheSyncCheck(t, true, true, modelName)
+
+	httprule := httpRuleWithBackends(rrname, "/foo", []v1beta1.HTTPRuleBackend{
+		{ServiceName: "avisvc-canary", Port: 8080, Weight: 10},
+		{ServiceName: "avisvc-beta", Port: 8080, Match: &v1beta1.HTTPRuleBackendMatch{
+			Header: &v1beta1.HTTPRuleMatchValue{Name: "x-canary", Value: "true"},
+		}},
+	})
+	if _, err := v1beta1CRDClient.AkoV1beta1().HTTPRules("default").Create(context.TODO(), httprule, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding HTTPRule: %v", err)
+	}
+	g.Eventually(func() string {
+		httprule, _ := v1beta1CRDClient.AkoV1beta1().HTTPRules("default").Get(context.TODO(), rrname, metav1.GetOptions{})
+		return httprule.Status.Status
+	}, 10*time.Second).Should(gomega.Equal("Accepted"))
+
+	poolName := "cluster--default-foo.com_foo-foo-with-targets"
+	canaryPoolName := lib.GetSniPoolName("foo-with-targets", "default", "foo.com", "/foo", "", false, "avisvc-canary")
+	betaPoolName := lib.GetSniPoolName("foo-with-targets", "default", "foo.com", "/foo", "", false, "avisvc-beta")
+	getSniNode := func() *avinodes.AviVsNode {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(nodes) != 1 || len(nodes[0].SniNodes) != 1 {
+			return nil
+		}
+		return nodes[0].SniNodes[0]
+	}
+	g.Eventually(func() int {
+		if sniNode := getSniNode(); sniNode != nil {
+			return len(sniNode.PoolRefs)
+		}
+		return 0
+	}, 10*time.Second).Should(gomega.Equal(3))
+
+	sniNode := getSniNode()
+	pools := make(map[string]*avinodes.AviPoolNode)
+	for _, pool := range sniNode.PoolRefs {
+		pools[pool.Name] = pool
+	}
+	g.Expect(pools).To(gomega.HaveKey(canaryPoolName))
+	g.Expect(pools[canaryPoolName].HTTPRuleBackendOf).To(gomega.Equal(poolName))
+	g.Expect(pools[canaryPoolName].Servers).To(gomega.HaveLen(1))
+	g.Expect(*pools[canaryPoolName].Servers[0].Ip.Addr).To(gomega.Equal("2.2.2.1"))
+	g.Expect(pools).To(gomega.HaveKey(betaPoolName))
+	g.Expect(*pools[betaPoolName].Servers[0].Ip.Addr).To(gomega.Equal("3.3.3.1"))
+
+	// the canary receives 10% of the traffic to the path in the pool group
+	g.Expect(sniNode.PoolGroupRefs).To(gomega.HaveLen(1))
+	ratios := make(map[string]uint32)
+	for _, member := range sniNode.PoolGroupRefs[0].Members {
+		ratios[*member.PoolRef] = *member.Ratio
+	}
+	g.Expect(ratios).To(gomega.Equal(map[string]uint32{
+		"/api/pool?name=" + poolName:       90,
+		"/api/pool?name=" + canaryPoolName: 10,
+	}))
+
+	// the requests with the header are switched to the beta pool
+	g.Expect(sniNode.HttpPolicyRefs).To(gomega.HaveLen(1))
+	g.Expect(sniNode.HttpPolicyRefs[0].HppMap).To(gomega.HaveLen(2))
+	var betaHppMap *avinodes.AviHostPathPortPoolPG
+	for i, hppMap := range sniNode.HttpPolicyRefs[0].HppMap {
+		if hppMap.Pool == betaPoolName {
+			betaHppMap = &sniNode.HttpPolicyRefs[0].HppMap[i]
+		}
+	}
+	g.Expect(betaHppMap).NotTo(gomega.BeNil())
+	g.Expect(betaHppMap.Path).To(gomega.Equal([]string{"/foo"}))
+	g.Expect(betaHppMap.Hdrs).To(gomega.HaveLen(1))
+	g.Expect(*betaHppMap.Hdrs[0].Hdr).To(gomega.Equal("x-canary"))
+	g.Expect(betaHppMap.Hdrs[0].Value).To(gomega.Equal([]string{"true"}))
+
+	mcache := cache.SharedAviObjCache()
+	canaryPoolKey := cache.NamespaceName{Namespace: "admin", Name: canaryPoolName}
+	g.Eventually(func() bool {
+		_, found := mcache.PoolCache.AviCacheGet(canaryPoolKey)
+		return found
+	}, 10*time.Second).Should(gomega.BeTrue())
+
+	// the weights of the backends must leave a share of the traffic for the service of the path
+	httprule.Spec.Paths[0].Backends[0].Weight = 100
+	httprule.ResourceVersion = "2"
+	if _, err := v1beta1CRDClient.AkoV1beta1().HTTPRules("default").Update(context.TODO(), httprule, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating HTTPRule: %v", err)
+	}
+	g.Eventually(func() string {
+		httprule, _ := v1beta1CRDClient.AkoV1beta1().HTTPRules("default").Get(context.TODO(), rrname, metav1.GetOptions{})
+		return httprule.Status.Status
+	}, 10*time.Second).Should(gomega.Equal("Rejected"))
+
+	// removing the backends removes their pools, pool group members and HTTP policy rules
+	httprule.Spec.Paths[0].Backends = nil
+	httprule.ResourceVersion = "3"
+	if _, err := v1beta1CRDClient.AkoV1beta1().HTTPRules("default").Update(context.TODO(), httprule, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating HTTPRule: %v", err)
+	}
+	g.Eventually(func() int {
+		if sniNode := getSniNode(); sniNode != nil {
+			return len(sniNode.PoolRefs)
+		}
+		return 0
+	}, 10*time.Second).Should(gomega.Equal(1))
+	sniNode = getSniNode()
+	g.Expect(sniNode.PoolGroupRefs[0].Members).To(gomega.HaveLen(1))
+	g.Expect(*sniNode.PoolGroupRefs[0].Members[0].Ratio).To(gomega.Equal(uint32(100)))
+	g.Expect(sniNode.HttpPolicyRefs[0].HppMap).To(gomega.HaveLen(1))
+	g.Eventually(func() bool {
+		_, found := mcache.PoolCache.AviCacheGet(canaryPoolKey)
+		return found
+	}, 10*time.Second).Should(gomega.BeFalse())
+
+	integrationtest.TeardownHTTPRule(t, rrname)
+	TearDownIngressForCacheSyncCheck(t, modelName)
+	teardownHTTPRuleBackendServices(t)
+}
+
+func TestHTTPRuleBackendsInSharedVS(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	rrname := "samplerr-foo"
+	setupHTTPRuleBackendServices(t)
+	SetUpIngressForCacheSyncCheck(t, false, false, modelName)
+
+	httprule := httpRuleWithBackends(rrname, "/foo", []v1beta1.HTTPRuleBackend{
+		{ServiceName: "avisvc-canary", Port: 8080, Weight: 25},
+		{ServiceName: "avisvc-beta", Port: 8080, Match: &v1beta1.HTTPRuleBackendMatch{
+			Cookie: &v1beta1.HTTPRuleMatchValue{Name: "beta", Value: "yes"},
+		}},
+	})
+	if _, err := v1beta1CRDClient.AkoV1beta1().HTTPRules("default").Create(context.TODO(), httprule, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding HTTPRule: %v", err)
+	}
+
+	// the canary shares the priority label of the path in the pool group of the shared VS,
+	// and the backend with the match is ignored.
+	poolName := "cluster--foo.com_foo-default-foo-with-targets"
+	canaryPoolName := lib.GetL7PoolName("foo.com/foo", "default", "foo-with-targets", "", "avisvc-canary")
+	g.Eventually(func() int {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		return len(aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].PoolRefs)
+	}, 10*time.Second).Should(gomega.Equal(2))
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	vsNode := aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0]
+	g.Expect(vsNode.PoolRefs[1].Name).To(gomega.Equal(canaryPoolName))
+	g.Expect(vsNode.PoolRefs[1].PriorityLabel).To(gomega.Equal("foo.com/foo"))
+	g.Expect(vsNode.PoolGroupRefs[0].Members).To(gomega.HaveLen(2))
+	for _, member := range vsNode.PoolGroupRefs[0].Members {
+		g.Expect(*member.PriorityLabel).To(gomega.Equal("foo.com/foo"))
+		if *member.PoolRef == "/api/pool?name="+poolName {
+			g.Expect(*member.Ratio).To(gomega.Equal(uint32(75)))
+		} else {
+			g.Expect(*member.Ratio).To(gomega.Equal(uint32(25)))
+		}
+	}
+
+	// the ignored backend is reported in the status of the httprule
+	g.Eventually(func() string {
+		httprule, _ := v1beta1CRDClient.AkoV1beta1().HTTPRules("default").Get(context.TODO(), rrname, metav1.GetOptions{})
+		for _, condition := range httprule.Status.Conditions {
+			if condition.Type == lib.CRDConditionBackendsApplied && condition.Status == metav1.ConditionFalse {
+				return condition.Message
+			}
+		}
+		return ""
+	}, 10*time.Second).Should(gomega.ContainSubstring("avisvc-beta (path /foo)"))
+
+	integrationtest.TeardownHTTPRule(t, rrname)
+	g.Eventually(func() int {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		return len(aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].PoolRefs)
+	}, 10*time.Second).Should(gomega.Equal(1))
+	_, aviModel = objects.SharedAviGraphLister().Get(modelName)
+	vsNode = aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0]
+	g.Expect(vsNode.PoolGroupRefs[0].Members).To(gomega.HaveLen(1))
+	g.Expect(*vsNode.PoolGroupRefs[0].Members[0].Ratio).To(gomega.Equal(uint32(100)))
+
+	TearDownIngressForCacheSyncCheck(t, modelName)
+	teardownHTTPRuleBackendServices(t)
+}
+
+func reviewHostRule(t *testing.T, operation admissionv1.Operation, hostrule, oldHostrule *v1beta1.HostRule) *admissionv1.AdmissionResponse {
+	request := &admissionv1.AdmissionRequest{
+		UID:       "hostrule-review",
+		Kind:      metav1.GroupVersionKind{Group: "ako.vmware.com", Version: "v1beta1", Kind: lib.HostRule},
+		Name:      hostrule.Name,
+		Namespace: hostrule.Namespace,
+		Operation: operation,
+	}
+	request.Object.Raw, _ = json.Marshal(hostrule)
+	if oldHostrule != nil {
+		request.OldObject.Raw, _ = json.Marshal(oldHostrule)
+	}
+	body, _ := json.Marshal(&admissionv1.AdmissionReview{Request: request})
+
+	recorder := httptest.NewRecorder()
+	k8s.ServeValidatingWebhook(recorder, httptest.NewRequest("POST", k8s.ValidatingWebhookPath, bytes.NewReader(body)))
+	review := &admissionv1.AdmissionReview{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), review); err != nil || review.Response == nil {
+		t.Fatalf("invalid AdmissionReview response %s: %v", recorder.Body.String(), err)
+	}
+	if review.Response.UID != request.UID {
+		t.Fatalf("expected AdmissionReview response for %s, got %s", request.UID, review.Response.UID)
+	}
+	return review.Response
+}
+
+func TestValidatingWebhookHostRule(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	hrname := "samplehr-foo"
+	SetUpIngressForCacheSyncCheck(t, true, true, modelName)
+
+	hostrule := integrationtest.FakeHostRule{
+		Name:               "samplehr-webhook",
+		Namespace:          "default",
+		Fqdn:               "voo.com",
+		WafPolicy:          "thisisaviref-waf",
+		ApplicationProfile: "thisisaviref-appprof",
+	}.HostRule()
+	response := reviewHostRule(t, admissionv1.Create, hostrule, nil)
+	g.Expect(response.Allowed).To(gomega.BeTrue())
+
+	// a hostrule with a ref which does not exist on the controller is rejected.
+	badHostrule := hostrule.DeepCopy()
+	badHostrule.Spec.VirtualHost.WAFPolicy = "thisisBADaviref"
+	response = reviewHostRule(t, admissionv1.Create, badHostrule, nil)
+	g.Expect(response.Allowed).To(gomega.BeFalse())
+	g.Expect(response.Result.Message).To(gomega.ContainSubstring("thisisBADaviref"))
+	response = reviewHostRule(t, admissionv1.Update, badHostrule, hostrule)
+	g.Expect(response.Allowed).To(gomega.BeFalse())
+
+	// an update which does not change the spec is allowed.
+	labelledHostrule := badHostrule.DeepCopy()
+	labelledHostrule.Labels = map[string]string{"app": "webhook"}
+	response = reviewHostRule(t, admissionv1.Update, labelledHostrule, badHostrule)
+	g.Expect(response.Allowed).To(gomega.BeTrue())
+
+	badHostrule = hostrule.DeepCopy()
+	badHostrule.Spec.VirtualHost.Gslb.Fqdn = "voo.com"
+	response = reviewHostRule(t, admissionv1.Create, badHostrule, nil)
+	g.Expect(response.Allowed).To(gomega.BeFalse())
+	g.Expect(response.Result.Message).To(gomega.Equal("GSLB FQDN and local FQDN are same"))
+
+	// a hostrule with the fqdn of an accepted hostrule is rejected.
+	integrationtest.SetupHostRule(t, hrname, "foo.com", true)
+	g.Eventually(func() string {
+		hostrule, _ := v1beta1CRDClient.AkoV1beta1().HostRules("default").Get(context.TODO(), hrname, metav1.GetOptions{})
+		return hostrule.Status.Status
+	}, 20*time.Second).Should(gomega.Equal("Accepted"))
+	sniVSKey := cache.NamespaceName{Namespace: "admin", Name: "cluster--foo.com"}
+	integrationtest.VerifyMetadataHostRule(t, g, sniVSKey, "default/samplehr-foo", true)
+
+	hostrule.Spec.VirtualHost.Fqdn = "foo.com"
+	g.Eventually(func() bool {
+		return reviewHostRule(t, admissionv1.Create, hostrule, nil).Allowed
+	}, 10*time.Second).Should(gomega.BeFalse())
+	response = reviewHostRule(t, admissionv1.Create, hostrule, nil)
+	g.Expect(response.Result.Message).To(gomega.Equal("duplicate fqdn foo.com found in default/samplehr-foo"))
+
+	acceptedHostrule, _ := v1beta1CRDClient.AkoV1beta1().HostRules("default").Get(context.TODO(), hrname, metav1.GetOptions{})
+	updatedHostrule := acceptedHostrule.DeepCopy()
+	updatedHostrule.Spec.VirtualHost.Gslb.Fqdn = "baz.com"
+	response = reviewHostRule(t, admissionv1.Update, updatedHostrule, acceptedHostrule)
+	g.Expect(response.Allowed).To(gomega.BeTrue())
+
+	integrationtest.TeardownHostRule(t, g, sniVSKey, hrname)
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
+
+func TestHostRuleStatusConditions(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	hrname := "samplehr-foo"
+	SetUpIngressForCacheSyncCheck(t, true, true, modelName)
+
+	integrationtest.SetupHostRule(t, hrname, "foo.com", true)
+	sniVSKey := cache.NamespaceName{Namespace: "admin", Name: "cluster--foo.com"}
+	integrationtest.VerifyMetadataHostRule(t, g, sniVSKey, "default/samplehr-foo", true)
+
+	conditionStatus := func(conditionType string) metav1.ConditionStatus {
+		hostrule, _ := v1beta1CRDClient.AkoV1beta1().HostRules("default").Get(context.TODO(), hrname, metav1.GetOptions{})
+		if condition := meta.FindStatusCondition(hostrule.Status.Conditions, conditionType); condition != nil {
+			return condition.Status
+		}
+		return metav1.ConditionUnknown
+	}
+	g.Eventually(func() metav1.ConditionStatus {
+		return conditionStatus(lib.CRDConditionProgrammed)
+	}, 20*time.Second).Should(gomega.Equal(metav1.ConditionTrue))
+	hostrule, _ := v1beta1CRDClient.AkoV1beta1().HostRules("default").Get(context.TODO(), hrname, metav1.GetOptions{})
+	g.Expect(hostrule.Status.AppliedTo).To(gomega.Equal([]string{"cluster--foo.com"}))
+	g.Expect(meta.IsStatusConditionTrue(hostrule.Status.Conditions, lib.CRDConditionAccepted)).To(gomega.BeTrue())
+	g.Expect(meta.IsStatusConditionTrue(hostrule.Status.Conditions, lib.CRDConditionResolvedRefs)).To(gomega.BeTrue())
+
+	// a ref which does not exist on the controller rejects the hostrule, with unresolved refs.
+	hrUpdate := integrationtest.FakeHostRule{
+		Name:               hrname,
+		Namespace:          "default",
+		Fqdn:               "foo.com",
+		WafPolicy:          "thisisBADaviref",
+		ApplicationProfile: "thisisaviref-appprof",
+	}.HostRule()
+	hrUpdate.Status = hostrule.Status
+	hrUpdate.ResourceVersion = "2"
+	if _, err := v1beta1CRDClient.AkoV1beta1().HostRules("default").Update(context.TODO(), hrUpdate, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating HostRule: %v", err)
+	}
+	g.Eventually(func() metav1.ConditionStatus {
+		return conditionStatus(lib.CRDConditionResolvedRefs)
+	}, 20*time.Second).Should(gomega.Equal(metav1.ConditionFalse))
+	hostrule, _ = v1beta1CRDClient.AkoV1beta1().HostRules("default").Get(context.TODO(), hrname, metav1.GetOptions{})
+	g.Expect(hostrule.Status.Status).To(gomega.Equal(lib.StatusRejected))
+	accepted := meta.FindStatusCondition(hostrule.Status.Conditions, lib.CRDConditionAccepted)
+	g.Expect(accepted).NotTo(gomega.BeNil())
+	g.Expect(accepted.Status).To(gomega.Equal(metav1.ConditionFalse))
+	g.Expect(accepted.Reason).To(gomega.Equal(lib.CRDReasonRefNotFound))
+	g.Expect(accepted.Message).To(gomega.ContainSubstring("thisisBADaviref"))
+
+	// moving the hostrule to another fqdn removes it from the virtualservice.
+	hrUpdate = integrationtest.FakeHostRule{
+		Name:               hrname,
+		Namespace:          "default",
+		Fqdn:               "voo.com",
+		WafPolicy:          "thisisaviref-waf",
+		ApplicationProfile: "thisisaviref-appprof",
+	}.HostRule()
+	hrUpdate.Status = hostrule.Status
+	hrUpdate.ResourceVersion = "3"
+	if _, err := v1beta1CRDClient.AkoV1beta1().HostRules("default").Update(context.TODO(), hrUpdate, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating HostRule: %v", err)
+	}
+	g.Eventually(func() metav1.ConditionStatus {
+		return conditionStatus(lib.CRDConditionProgrammed)
+	}, 20*time.Second).Should(gomega.Equal(metav1.ConditionFalse))
+	hostrule, _ = v1beta1CRDClient.AkoV1beta1().HostRules("default").Get(context.TODO(), hrname, metav1.GetOptions{})
+	g.Expect(hostrule.Status.AppliedTo).To(gomega.BeEmpty())
+	g.Expect(meta.IsStatusConditionTrue(hostrule.Status.Conditions, lib.CRDConditionAccepted)).To(gomega.BeTrue())
+	g.Expect(meta.FindStatusCondition(hostrule.Status.Conditions, lib.CRDConditionProgrammed).Reason).To(gomega.Equal(lib.CRDReasonNotApplied))
+
+	integrationtest.TeardownHostRule(t, g, sniVSKey, hrname)
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
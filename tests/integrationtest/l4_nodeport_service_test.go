@@ -272,3 +272,62 @@ func TestMultiPortL4SvcNodePort(t *testing.T) {
 
 	TearDownTestForSvcLBMultiport(t, g)
 }
+
+// TestSinglePortL4SvcNodePortWithExternalTrafficPolicyLocal tests that only the nodes hosting
+// ready endpoints are added as pool servers for a service with externalTrafficPolicy Local.
+func TestSinglePortL4SvcNodePortWithExternalTrafficPolicyLocal(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	SetNodePortMode()
+	defer SetClusterIPMode()
+	nodeIP1, nodeIP2 := "10.1.1.2", "10.1.1.3"
+	nodeName1, nodeName2 := "testNode1", "testNode2"
+	CreateNode(t, nodeName1, nodeIP1)
+	defer DeleteNode(t, nodeName1)
+	CreateNode(t, nodeName2, nodeIP2)
+	defer DeleteNode(t, nodeName2)
+
+	objects.SharedAviGraphLister().Delete(SINGLEPORTMODEL)
+	svcExample := ConstructService(NAMESPACE, SINGLEPORTSVC, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, false, make(map[string]string))
+	svcExample.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyLocal
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcExample, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Service: %v", err)
+	}
+	epExample := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Namespace: NAMESPACE, Name: SINGLEPORTSVC},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{{IP: "1.1.1.1", NodeName: &nodeName2}},
+			Ports:     []corev1.EndpointPort{{Name: "foo0", Port: 8080, Protocol: corev1.ProtocolTCP}},
+		}},
+	}
+	if _, err := KubeClient.CoreV1().Endpoints(NAMESPACE).Create(context.TODO(), epExample, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in creating Endpoint: %v", err)
+	}
+
+	getServerIPs := func() []string {
+		found, aviModel := objects.SharedAviGraphLister().Get(SINGLEPORTMODEL)
+		if !found || aviModel == nil {
+			return nil
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(nodes) != 1 || len(nodes[0].PoolRefs) != 1 {
+			return nil
+		}
+		var ips []string
+		for _, server := range nodes[0].PoolRefs[0].Servers {
+			ips = append(ips, *server.Ip.Addr)
+		}
+		return ips
+	}
+	g.Eventually(getServerIPs, 10*time.Second).Should(gomega.ConsistOf(nodeIP2))
+
+	// Moving the endpoint to the other node should update the pool servers.
+	epExample.Subsets[0].Addresses[0].NodeName = &nodeName1
+	epExample.ResourceVersion = "2"
+	if _, err := KubeClient.CoreV1().Endpoints(NAMESPACE).Update(context.TODO(), epExample, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Endpoint: %v", err)
+	}
+	g.Eventually(getServerIPs, 10*time.Second).Should(gomega.ConsistOf(nodeIP1))
+
+	TearDownTestForSvcLB(t, g)
+}
@@ -15,6 +15,7 @@
 package integrationtest
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -31,6 +32,10 @@ import (
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/rest"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/retry"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/tracing"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api/models"
 	crdfake "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha1/clientset/versioned/fake"
 	v1beta1crdfake "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1beta1/clientset/versioned/fake"
 
@@ -38,6 +43,8 @@ import (
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 
 	"github.com/onsi/gomega"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -1633,3 +1640,899 @@ func TestLBSvcWithExtDNSAndAutoFQDNAsFlat(t *testing.T) {
 	TearDownTestForSvcLBWithExtDNS(t, g)
 	os.Setenv("AUTO_L4_FQDN", "disable")
 }
+
+// TestL4SvcWithLoadBalancerSourceRanges tests that the loadBalancerSourceRanges of a LB service
+// are translated to a network security policy attached to the L4 VS.
+func TestL4SvcWithLoadBalancerSourceRanges(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	objects.SharedAviGraphLister().Delete(SINGLEPORTMODEL)
+	svcExample := ConstructService(NAMESPACE, SINGLEPORTSVC, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, false, make(map[string]string))
+	svcExample.Spec.LoadBalancerSourceRanges = []string{"10.10.0.0/16", "192.168.1.5/24", "invalid-cidr"}
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcExample, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Service: %v", err)
+	}
+	CreateEP(t, NAMESPACE, SINGLEPORTSVC, false, false, "1.1.1")
+
+	vsName := fmt.Sprintf("cluster--%s-%s", NAMESPACE, SINGLEPORTSVC)
+	g.Eventually(func() int {
+		found, aviModel := objects.SharedAviGraphLister().Get(SINGLEPORTMODEL)
+		if !found || aviModel == nil {
+			return 0
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(nodes) != 1 {
+			return 0
+		}
+		return len(nodes[0].NSPRefs)
+	}, 10*time.Second).Should(gomega.Equal(1))
+	_, aviModel := objects.SharedAviGraphLister().Get(SINGLEPORTMODEL)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	g.Expect(nodes[0].NetworkSecurityPolicyRef).NotTo(gomega.BeNil())
+	g.Expect(*nodes[0].NetworkSecurityPolicyRef).To(gomega.Equal("/api/networksecuritypolicy?name=" + vsName))
+	g.Expect(nodes[0].NSPRefs[0].Name).To(gomega.Equal(vsName))
+	g.Expect(nodes[0].NSPRefs[0].AllowedClientIPs).To(gomega.ConsistOf("10.10.0.0/16", "192.168.1.0/24"))
+
+	mcache := cache.SharedAviObjCache()
+	vsKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: vsName}
+	nspKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: vsName}
+	g.Eventually(func() bool {
+		_, found := mcache.NSPCache.AviCacheGet(nspKey)
+		return found
+	}, 10*time.Second).Should(gomega.Equal(true))
+	vsCache, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+	g.Expect(found).To(gomega.Equal(true))
+	g.Expect(vsCache.(*cache.AviVsCache).NSPCollection).To(gomega.ContainElement(nspKey))
+
+	// Removing the source ranges should remove the network security policy.
+	svcExample = ConstructService(NAMESPACE, SINGLEPORTSVC, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, false, make(map[string]string))
+	svcExample.ResourceVersion = "2"
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Update(context.TODO(), svcExample, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Service: %v", err)
+	}
+	g.Eventually(func() bool {
+		_, found := mcache.NSPCache.AviCacheGet(nspKey)
+		return found
+	}, 10*time.Second).Should(gomega.Equal(false))
+	_, aviModel = objects.SharedAviGraphLister().Get(SINGLEPORTMODEL)
+	nodes = aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	g.Expect(nodes[0].NSPRefs).To(gomega.HaveLen(0))
+	g.Expect(nodes[0].NetworkSecurityPolicyRef).To(gomega.BeNil())
+
+	TearDownTestForSvcLB(t, g)
+}
+
+// TestL4SvcWithClientIPSessionAffinity tests that ClientIP session affinity of a LB service
+// sets the client IP persistence profile on the L4 pools.
+func TestL4SvcWithClientIPSessionAffinity(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	objects.SharedAviGraphLister().Delete(SINGLEPORTMODEL)
+	svcExample := ConstructService(NAMESPACE, SINGLEPORTSVC, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, false, make(map[string]string))
+	svcExample.Spec.SessionAffinity = corev1.ServiceAffinityClientIP
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcExample, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Service: %v", err)
+	}
+	CreateEP(t, NAMESPACE, SINGLEPORTSVC, false, false, "1.1.1")
+
+	persistenceProfile := "/api/applicationpersistenceprofile?name=" + utils.CLIENT_IP_PERSISTENCE_PROFILE
+	g.Eventually(func() *string {
+		found, aviModel := objects.SharedAviGraphLister().Get(SINGLEPORTMODEL)
+		if !found || aviModel == nil {
+			return nil
+		}
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(nodes) != 1 || len(nodes[0].PoolRefs) != 1 {
+			return nil
+		}
+		return nodes[0].PoolRefs[0].ApplicationPersistenceProfileRef
+	}, 10*time.Second).Should(gomega.Equal(&persistenceProfile))
+
+	// Reverting to no session affinity should remove the persistence profile.
+	svcExample = ConstructService(NAMESPACE, SINGLEPORTSVC, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, false, make(map[string]string))
+	svcExample.ResourceVersion = "2"
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Update(context.TODO(), svcExample, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Service: %v", err)
+	}
+	g.Eventually(func() *string {
+		_, aviModel := objects.SharedAviGraphLister().Get(SINGLEPORTMODEL)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		return nodes[0].PoolRefs[0].ApplicationPersistenceProfileRef
+	}, 10*time.Second).Should(gomega.BeNil())
+
+	TearDownTestForSvcLB(t, g)
+}
+
+// TestL4SvcWithLoadBalancerClass tests that only the LB services with the loadBalancerClass
+// handled by AKO are synced, and the VS is removed when the class changes away from AKO.
+func TestL4SvcWithLoadBalancerClass(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	otherLBClass := "example.com/other-lb"
+	akoLBClass := lib.AviLoadBalancerClass
+	mcache := cache.SharedAviObjCache()
+	vsKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: fmt.Sprintf("cluster--%s-%s", NAMESPACE, SINGLEPORTSVC)}
+
+	objects.SharedAviGraphLister().Delete(SINGLEPORTMODEL)
+	svcExample := ConstructService(NAMESPACE, SINGLEPORTSVC, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, false, make(map[string]string))
+	svcExample.Spec.LoadBalancerClass = &otherLBClass
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcExample, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Service: %v", err)
+	}
+	CreateEP(t, NAMESPACE, SINGLEPORTSVC, false, false, "1.1.1")
+	g.Consistently(func() bool {
+		found, aviModel := objects.SharedAviGraphLister().Get(SINGLEPORTMODEL)
+		return found && aviModel != nil
+	}, 5*time.Second).Should(gomega.Equal(false))
+
+	// Moving the service to the class handled by AKO should create the VS.
+	svcExample.Spec.LoadBalancerClass = &akoLBClass
+	svcExample.ResourceVersion = "2"
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Update(context.TODO(), svcExample, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Service: %v", err)
+	}
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 10*time.Second).Should(gomega.Equal(true))
+
+	// Moving the service away from the class handled by AKO should delete the VS.
+	svcExample.Spec.LoadBalancerClass = &otherLBClass
+	svcExample.ResourceVersion = "3"
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Update(context.TODO(), svcExample, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Service: %v", err)
+	}
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 10*time.Second).Should(gomega.Equal(false))
+
+	TearDownTestForSvcLB(t, g)
+}
+
+// TestL4SvcWithoutLoadBalancerClassNotDefaultController tests that the LB services without a
+// loadBalancerClass are not synced when AKO is not the default LB controller.
+func TestL4SvcWithoutLoadBalancerClassNotDefaultController(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	os.Setenv("DEFAULT_LB_CONTROLLER", "false")
+	defer os.Setenv("DEFAULT_LB_CONTROLLER", "true")
+
+	SetUpTestForSvcLB(t)
+	g.Consistently(func() bool {
+		found, aviModel := objects.SharedAviGraphLister().Get(SINGLEPORTMODEL)
+		return found && aviModel != nil
+	}, 5*time.Second).Should(gomega.Equal(false))
+
+	TearDownTestForSvcLB(t, g)
+}
+
+// setUpTestForSvcLBWithRestTransaction creates an LB service whose VS is not present on the controller,
+// and returns the VS and pool cache keys for it.
+func setUpTestForSvcLBWithRestTransaction(t *testing.T, svcName string) (cache.NamespaceName, cache.NamespaceName) {
+	objects.SharedAviGraphLister().Delete(fmt.Sprintf("%s/cluster--%s-%s", AVINAMESPACE, NAMESPACE, svcName))
+	CreateSVC(t, NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, false)
+	CreateEP(t, NAMESPACE, svcName, false, false, "1.1.1")
+	vsKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: fmt.Sprintf("cluster--%s-%s", NAMESPACE, svcName)}
+	poolKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: fmt.Sprintf("cluster--%s-%s-TCP-8080", NAMESPACE, svcName)}
+	return vsKey, poolKey
+}
+
+func tearDownTestForSvcLBWithRestTransaction(t *testing.T, g *gomega.GomegaWithT, svcName string) {
+	objects.SharedAviGraphLister().Delete(fmt.Sprintf("%s/cluster--%s-%s", AVINAMESPACE, NAMESPACE, svcName))
+	DelSVC(t, NAMESPACE, svcName)
+	DelEP(t, NAMESPACE, svcName)
+	vsKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: fmt.Sprintf("cluster--%s-%s", NAMESPACE, svcName)}
+	g.Eventually(func() bool {
+		_, found := cache.SharedAviObjCache().VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 10*time.Second).Should(gomega.Equal(false))
+}
+
+// TestL4SvcWithRestTransactionMacro tests that the objects of a new L4 VS are created with a single
+// macro API call when rest transactions are enabled.
+func TestL4SvcWithRestTransactionMacro(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	os.Setenv("ENABLE_REST_TRANSACTION", "true")
+	defer os.Setenv("ENABLE_REST_TRANSACTION", "false")
+	ctrlVersion := lib.AKOControlConfig().ControllerVersion()
+	lib.AKOControlConfig().SetControllerVersion(lib.MacroAPIBatchMinVersion)
+	defer lib.AKOControlConfig().SetControllerVersion(ctrlVersion)
+
+	var mutex sync.Mutex
+	var macroCalls, poolCalls int
+	AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			mutex.Lock()
+			if strings.Contains(r.URL.EscapedPath(), "macro") {
+				// Only the macros which create objects are counted, the later updates are sent in macros as well.
+				data, _ := io.ReadAll(r.Body)
+				r.Body = io.NopCloser(bytes.NewReader(data))
+				if !strings.Contains(string(data), `"uuid"`) {
+					macroCalls++
+				}
+			} else if strings.Contains(r.URL.EscapedPath(), "/pool") {
+				poolCalls++
+			}
+			mutex.Unlock()
+		}
+		NormalControllerServer(w, r)
+	})
+	defer ResetMiddleware()
+
+	svcName := "testsvc-macro"
+	vsKey, poolKey := setUpTestForSvcLBWithRestTransaction(t, svcName)
+
+	mcache := cache.SharedAviObjCache()
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 10*time.Second).Should(gomega.Equal(true))
+	_, found := mcache.PoolCache.AviCacheGet(poolKey)
+	g.Expect(found).To(gomega.Equal(true))
+	vsCache, _ := mcache.VsCacheMeta.AviCacheGet(vsKey)
+	g.Expect(vsCache.(*cache.AviVsCache).PoolKeyCollection).To(gomega.ContainElement(poolKey))
+	g.Expect(vsCache.(*cache.AviVsCache).L4PolicyCollection).To(gomega.HaveLen(1))
+
+	mutex.Lock()
+	g.Expect(macroCalls).To(gomega.Equal(1))
+	g.Expect(poolCalls).To(gomega.Equal(0))
+	mutex.Unlock()
+
+	ResetMiddleware()
+	tearDownTestForSvcLBWithRestTransaction(t, g, svcName)
+}
+
+// TestL4SvcWithRestTransactionMacroUpdate tests that the objects created, updated and deleted by an update of an
+// L4 VS are sent in macro API calls when rest transactions are enabled.
+func TestL4SvcWithRestTransactionMacroUpdate(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	os.Setenv("ENABLE_REST_TRANSACTION", "true")
+	defer os.Setenv("ENABLE_REST_TRANSACTION", "false")
+	ctrlVersion := lib.AKOControlConfig().ControllerVersion()
+	lib.AKOControlConfig().SetControllerVersion(lib.MacroAPIBatchMinVersion)
+	defer lib.AKOControlConfig().SetControllerVersion(ctrlVersion)
+
+	svcName := "testsvc-macro-update"
+	vsKey, _ := setUpTestForSvcLBWithRestTransaction(t, svcName)
+	mcache := cache.SharedAviObjCache()
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 10*time.Second).Should(gomega.Equal(true))
+
+	var mutex sync.Mutex
+	var macroPosts, macroDeletes, objectCalls int
+	AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			mutex.Lock()
+			if !strings.Contains(r.URL.EscapedPath(), "macro") {
+				objectCalls++
+			} else if r.Method == "POST" {
+				macroPosts++
+			} else if r.Method == "DELETE" {
+				macroDeletes++
+			}
+			mutex.Unlock()
+		}
+		NormalControllerServer(w, r)
+	})
+	defer ResetMiddleware()
+
+	// The new pools are created along with the updates of the L4 policy set and the VS.
+	UpdateSVC(t, NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, true)
+	g.Eventually(func() int {
+		vsCache, _ := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return len(vsCache.(*cache.AviVsCache).PoolKeyCollection)
+	}, 10*time.Second).Should(gomega.Equal(3))
+	mutex.Lock()
+	g.Expect(macroPosts).To(gomega.Equal(1))
+	g.Expect(objectCalls).To(gomega.Equal(0))
+	mutex.Unlock()
+
+	// The stale pools are deleted once the L4 policy set and the VS no longer refer to them.
+	svcExample := ConstructService(NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, false, make(map[string]string))
+	svcExample.ResourceVersion = "3"
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Update(context.TODO(), svcExample, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Service: %v", err)
+	}
+	g.Eventually(func() int {
+		vsCache, _ := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return len(vsCache.(*cache.AviVsCache).PoolKeyCollection)
+	}, 10*time.Second).Should(gomega.Equal(1))
+	mutex.Lock()
+	g.Expect(macroPosts).To(gomega.Equal(2))
+	g.Expect(macroDeletes).To(gomega.Equal(1))
+	g.Expect(objectCalls).To(gomega.Equal(0))
+	mutex.Unlock()
+
+	ResetMiddleware()
+	tearDownTestForSvcLBWithRestTransaction(t, g, svcName)
+}
+
+// TestL4SvcWithRestTransactionRollback tests that the objects created for a new L4 VS are deleted
+// when the VS creation fails, if the controller does not support batching in a macro API call.
+func TestL4SvcWithRestTransactionRollback(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	os.Setenv("ENABLE_REST_TRANSACTION", "true")
+	defer os.Setenv("ENABLE_REST_TRANSACTION", "false")
+	ctrlVersion := lib.AKOControlConfig().ControllerVersion()
+	lib.AKOControlConfig().SetControllerVersion("21.1.1")
+	defer lib.AKOControlConfig().SetControllerVersion(ctrlVersion)
+
+	var mutex sync.Mutex
+	var deletedObjs []string
+	AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		url := r.URL.EscapedPath()
+		if r.Method == "POST" && strings.Contains(url, "virtualservice") {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintln(w, `{"error": "bad request"}`)
+			return
+		}
+		if r.Method == "DELETE" {
+			mutex.Lock()
+			deletedObjs = append(deletedObjs, strings.Split(strings.Trim(url, "/"), "/")[1])
+			mutex.Unlock()
+		}
+		NormalControllerServer(w, r)
+	})
+	defer ResetMiddleware()
+
+	svcName := "testsvc-rollback"
+	vsKey, poolKey := setUpTestForSvcLBWithRestTransaction(t, svcName)
+
+	g.Eventually(func() []string {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return append([]string{}, deletedObjs...)
+	}, 10*time.Second).Should(gomega.ContainElements("vsvip", "pool", "l4policyset"))
+
+	mcache := cache.SharedAviObjCache()
+	_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+	g.Expect(found).To(gomega.Equal(false))
+	_, found = mcache.PoolCache.AviCacheGet(poolKey)
+	g.Expect(found).To(gomega.Equal(false))
+	// The key is retried to create the objects which were rolled back.
+	g.Eventually(func() int {
+		return retry.GetRetryAttempts(vsKey.Name)
+	}, 10*time.Second).Should(gomega.BeNumerically(">", 0))
+
+	ResetMiddleware()
+	tearDownTestForSvcLBWithRestTransaction(t, g, svcName)
+}
+
+// TestL4SvcWithRestTransactionRollbackUpdate tests that the pools created for an update of an L4 VS are not
+// deleted when the VS update fails, as the L4 policy set updated before the failure refers to them.
+func TestL4SvcWithRestTransactionRollbackUpdate(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	os.Setenv("ENABLE_REST_TRANSACTION", "true")
+	defer os.Setenv("ENABLE_REST_TRANSACTION", "false")
+	ctrlVersion := lib.AKOControlConfig().ControllerVersion()
+	lib.AKOControlConfig().SetControllerVersion("21.1.1")
+	defer lib.AKOControlConfig().SetControllerVersion(ctrlVersion)
+
+	svcName := "testsvc-rollback-update"
+	vsKey, _ := setUpTestForSvcLBWithRestTransaction(t, svcName)
+	mcache := cache.SharedAviObjCache()
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 10*time.Second).Should(gomega.Equal(true))
+
+	var mutex sync.Mutex
+	var vsPuts int
+	var deletedObjs []string
+	AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		url := r.URL.EscapedPath()
+		if r.Method == "PUT" && strings.Contains(url, "virtualservice") {
+			mutex.Lock()
+			vsPuts++
+			mutex.Unlock()
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprintln(w, `{"error": "concurrent update"}`)
+			return
+		}
+		if r.Method == "DELETE" {
+			mutex.Lock()
+			deletedObjs = append(deletedObjs, strings.Split(strings.Trim(url, "/"), "/")[1])
+			mutex.Unlock()
+		}
+		NormalControllerServer(w, r)
+	})
+	defer ResetMiddleware()
+
+	UpdateSVC(t, NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, true)
+	g.Eventually(func() int {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return vsPuts
+	}, 10*time.Second).Should(gomega.BeNumerically(">", 0))
+	g.Consistently(func() []string {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return append([]string{}, deletedObjs...)
+	}, 2*time.Second).Should(gomega.BeEmpty())
+	for _, port := range []string{"8081", "8082"} {
+		poolKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: fmt.Sprintf("cluster--%s-%s-TCP-%s", NAMESPACE, svcName, port)}
+		_, found := mcache.PoolCache.AviCacheGet(poolKey)
+		g.Expect(found).To(gomega.Equal(true))
+	}
+
+	ResetMiddleware()
+	tearDownTestForSvcLBWithRestTransaction(t, g, svcName)
+}
+
+// TestL4SvcWithRestConcurrency tests that the pools of a new multiport L4 VS are created in parallel
+// and that the VS is created only after all of its pools.
+func TestL4SvcWithRestConcurrency(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	os.Setenv("REST_CONCURRENCY", "4")
+	defer os.Setenv("REST_CONCURRENCY", "1")
+
+	var mutex sync.Mutex
+	var inFlightPools, maxInFlightPools, poolsCreated, poolsBeforeVS int
+	AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		url := r.URL.EscapedPath()
+		if r.Method == "POST" && strings.Contains(url, "/pool") && !strings.Contains(url, "poolgroup") {
+			mutex.Lock()
+			inFlightPools++
+			if inFlightPools > maxInFlightPools {
+				maxInFlightPools = inFlightPools
+			}
+			mutex.Unlock()
+			time.Sleep(200 * time.Millisecond)
+			NormalControllerServer(w, r)
+			mutex.Lock()
+			inFlightPools--
+			poolsCreated++
+			mutex.Unlock()
+			return
+		}
+		if r.Method == "POST" && strings.Contains(url, "virtualservice") {
+			mutex.Lock()
+			poolsBeforeVS = poolsCreated
+			mutex.Unlock()
+		}
+		NormalControllerServer(w, r)
+	})
+	defer ResetMiddleware()
+
+	svcName := "testsvc-concurrent"
+	objects.SharedAviGraphLister().Delete(fmt.Sprintf("%s/cluster--%s-%s", AVINAMESPACE, NAMESPACE, svcName))
+	CreateSVC(t, NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, true)
+	CreateEP(t, NAMESPACE, svcName, true, true, "1.1.1")
+	vsKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: fmt.Sprintf("cluster--%s-%s", NAMESPACE, svcName)}
+
+	mcache := cache.SharedAviObjCache()
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 10*time.Second).Should(gomega.Equal(true))
+	vsCache, _ := mcache.VsCacheMeta.AviCacheGet(vsKey)
+	g.Expect(vsCache.(*cache.AviVsCache).PoolKeyCollection).To(gomega.HaveLen(3))
+	g.Expect(vsCache.(*cache.AviVsCache).L4PolicyCollection).To(gomega.HaveLen(1))
+
+	mutex.Lock()
+	g.Expect(maxInFlightPools).To(gomega.BeNumerically(">", 1))
+	g.Expect(poolsBeforeVS).To(gomega.Equal(3))
+	mutex.Unlock()
+
+	ResetMiddleware()
+	tearDownTestForSvcLBWithRestTransaction(t, g, svcName)
+}
+
+// TestL4SvcWithRestConcurrencyInTwoWorkers tests that two rest workers can send the pools of their VSes in
+// parallel at the same time. The parallel requests must not share an Avi session with the other worker, which
+// is caught when the test is run with -race.
+func TestL4SvcWithRestConcurrencyInTwoWorkers(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	os.Setenv("REST_CONCURRENCY", "4")
+	defer os.Setenv("REST_CONCURRENCY", "1")
+
+	// Every session gets an id in a cookie, to find the requests sent on the same session at the same time.
+	var mutex sync.Mutex
+	var sessions, inFlightPools, maxInFlightPools int
+	inFlightSessions := make(map[string]int)
+	var sharedSessions []string
+	AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie("sessionid"); err != nil {
+			mutex.Lock()
+			sessions++
+			http.SetCookie(w, &http.Cookie{Name: "sessionid", Value: fmt.Sprintf("session-%d", sessions)})
+			mutex.Unlock()
+		} else {
+			mutex.Lock()
+			inFlightSessions[cookie.Value]++
+			if inFlightSessions[cookie.Value] > 1 {
+				sharedSessions = append(sharedSessions, cookie.Value)
+			}
+			mutex.Unlock()
+			defer func() {
+				mutex.Lock()
+				inFlightSessions[cookie.Value]--
+				mutex.Unlock()
+			}()
+		}
+		url := r.URL.EscapedPath()
+		if r.Method == "POST" && strings.Contains(url, "/pool") && !strings.Contains(url, "poolgroup") {
+			mutex.Lock()
+			inFlightPools++
+			if inFlightPools > maxInFlightPools {
+				maxInFlightPools = inFlightPools
+			}
+			mutex.Unlock()
+			// The pools are held until the pools of the other VS are sent as well.
+			for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(100 * time.Millisecond) {
+				mutex.Lock()
+				bothWorkers := maxInFlightPools > 3
+				mutex.Unlock()
+				if bothWorkers {
+					break
+				}
+			}
+			NormalControllerServer(w, r)
+			mutex.Lock()
+			inFlightPools--
+			mutex.Unlock()
+			return
+		}
+		NormalControllerServer(w, r)
+	})
+	defer ResetMiddleware()
+	for _, client := range cache.SharedAVIClients().AviClient {
+		var resp interface{}
+		client.AviSession.Get("api/cloud", &resp)
+	}
+
+	// The models of the services are processed by adjacent rest workers, so that the parallel requests
+	// of the first one would go to the client of the second one if the clients of the workers were shared.
+	svcNames := []string{"testsvc-worker-a", "testsvc-worker-b"}
+	modelNames := make([]string, len(svcNames))
+	for i, svcName := range svcNames {
+		modelNames[i] = fmt.Sprintf("%s/cluster--%s-%s", AVINAMESPACE, NAMESPACE, svcName)
+		objects.SharedAviGraphLister().Delete(modelNames[i])
+	}
+	g.Expect(utils.Bkt(modelNames[1], lib.GetshardSize())).To(gomega.Equal(utils.Bkt(modelNames[0], lib.GetshardSize()) + 1))
+
+	for _, svcName := range svcNames {
+		CreateSVC(t, NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, true)
+		CreateEP(t, NAMESPACE, svcName, true, true, "1.1.1")
+	}
+
+	mcache := cache.SharedAviObjCache()
+	for _, svcName := range svcNames {
+		vsKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: fmt.Sprintf("cluster--%s-%s", NAMESPACE, svcName)}
+		g.Eventually(func() int {
+			vsCache, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+			if !found {
+				return 0
+			}
+			return len(vsCache.(*cache.AviVsCache).PoolKeyCollection)
+		}, 20*time.Second).Should(gomega.Equal(3))
+	}
+
+	// A VS has 3 pools, so more requests in flight are from both workers.
+	mutex.Lock()
+	g.Expect(maxInFlightPools).To(gomega.BeNumerically(">", 3))
+	g.Expect(sharedSessions).To(gomega.BeEmpty())
+	mutex.Unlock()
+
+	ResetMiddleware()
+	for _, svcName := range svcNames {
+		tearDownTestForSvcLBWithRestTransaction(t, g, svcName)
+	}
+}
+
+// TestL4SvcRetryDeadLetter tests that a VS which keeps failing with a retryable error is moved to the
+// dead-letter set after the maximum retry attempts, and is removed from it once it is synced.
+func TestL4SvcRetryDeadLetter(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	os.Setenv("RETRY_MAX_ATTEMPTS", "2")
+	defer os.Setenv("RETRY_MAX_ATTEMPTS", "0")
+
+	var mutex sync.Mutex
+	var poolCalls int
+	AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		url := r.URL.EscapedPath()
+		if r.Method == "POST" && strings.Contains(url, "/pool") && !strings.Contains(url, "poolgroup") {
+			mutex.Lock()
+			poolCalls++
+			mutex.Unlock()
+			w.WriteHeader(http.StatusRequestTimeout)
+			fmt.Fprintln(w, `{"error": "request timed out"}`)
+			return
+		}
+		NormalControllerServer(w, r)
+	})
+	defer ResetMiddleware()
+
+	svcName := "testsvc-deadletter"
+	vsName := fmt.Sprintf("cluster--%s-%s", NAMESPACE, svcName)
+	vsKey, _ := setUpTestForSvcLBWithRestTransaction(t, svcName)
+
+	g.Eventually(func() bool {
+		_, found := models.RetryDeadLetters.Get(vsName)
+		return found
+	}, 30*time.Second).Should(gomega.Equal(true))
+	deadLetterKey, _ := models.RetryDeadLetters.Get(vsName)
+	g.Expect(deadLetterKey.Attempts).To(gomega.Equal(2))
+	g.Expect(retry.GetRetryAttempts(vsName)).To(gomega.Equal(0))
+
+	// The key is not retried any further.
+	mutex.Lock()
+	calls := poolCalls
+	mutex.Unlock()
+	time.Sleep(5 * time.Second)
+	mutex.Lock()
+	g.Expect(poolCalls).To(gomega.Equal(calls))
+	mutex.Unlock()
+
+	// The key is synced again once the endpoints of the service are updated.
+	ResetMiddleware()
+	ScaleCreateEP(t, NAMESPACE, svcName)
+	g.Eventually(func() bool {
+		_, found := cache.SharedAviObjCache().VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 30*time.Second).Should(gomega.Equal(true))
+	g.Eventually(func() bool {
+		_, found := models.RetryDeadLetters.Get(vsName)
+		return found
+	}, 10*time.Second).Should(gomega.Equal(false))
+
+	tearDownTestForSvcLBWithRestTransaction(t, g, svcName)
+}
+
+// TestL4SvcDriftReapply tests that a pool changed on the Avi controller outside of AKO is re-applied when
+// the drift action for pools is reapply, while the drift of the virtualservice is only reported.
+func TestL4SvcDriftReapply(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	os.Setenv("DRIFT_ACTIONS", `{"Pool": "reapply"}`)
+	defer os.Setenv("DRIFT_ACTIONS", "")
+
+	svcName := "testsvc-drift"
+	vsKey, poolKey := setUpTestForSvcLBWithRestTransaction(t, svcName)
+	mcache := cache.SharedAviObjCache()
+	g.Eventually(func() bool {
+		_, found := mcache.PoolCache.AviCacheGet(poolKey)
+		return found
+	}, 10*time.Second).Should(gomega.Equal(true))
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 10*time.Second).Should(gomega.Equal(true))
+	poolCache, _ := mcache.PoolCache.AviCacheGet(poolKey)
+	poolCacheCopy := *poolCache.(*cache.AviPoolCache)
+	poolCacheCopy.LastModified = "1000"
+	mcache.PoolCache.AviCacheAdd(poolKey, &poolCacheCopy)
+	poolUuid, poolCksum := poolCacheCopy.Uuid, poolCacheCopy.CloudConfigCksum
+	vsCache, _ := mcache.VsCacheMeta.AviCacheGet(vsKey)
+	vsCksum := vsCache.(*cache.AviVsCache).CloudConfigCksum
+
+	var mutex sync.Mutex
+	var poolPuts, vsPuts int
+	AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		url := r.URL.EscapedPath()
+		if r.Method == "GET" && strings.Trim(url, "/") == "api/pool" {
+			// The pool was updated on the Avi controller after AKO last applied it.
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, `{"count": 1, "results": [{"name": "%s", "uuid": "%s", "_last_modified": "2000"}]}`, poolKey.Name, poolUuid)
+			return
+		}
+		if r.Method == "PUT" && strings.Contains(url, poolUuid) {
+			mutex.Lock()
+			poolPuts++
+			mutex.Unlock()
+		} else if r.Method == "PUT" && strings.Contains(url, "/virtualservice/") {
+			mutex.Lock()
+			vsPuts++
+			mutex.Unlock()
+		}
+		NormalControllerServer(w, r)
+	})
+	defer ResetMiddleware()
+
+	restlayer := rest.NewRestOperations(mcache, cache.SharedAVIClients(), true)
+	restlayer.DetectDrift()
+
+	g.Eventually(func() int {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return poolPuts
+	}, 10*time.Second).Should(gomega.Equal(1))
+	g.Eventually(func() string {
+		poolCache, _ := mcache.PoolCache.AviCacheGet(poolKey)
+		return poolCache.(*cache.AviPoolCache).CloudConfigCksum
+	}, 10*time.Second).Should(gomega.Equal(poolCksum))
+
+	// The virtualservice is missing from the live objects, which is only reported.
+	vsCache, _ = mcache.VsCacheMeta.AviCacheGet(vsKey)
+	g.Expect(vsCache.(*cache.AviVsCache).CloudConfigCksum).To(gomega.Equal(vsCksum))
+	mutex.Lock()
+	g.Expect(vsPuts).To(gomega.Equal(0))
+	mutex.Unlock()
+
+	ResetMiddleware()
+	tearDownTestForSvcLBWithRestTransaction(t, g, svcName)
+}
+
+// TestL4SvcDriftReapplyL4Policy tests that the L4 policy set of a VS, which was deleted on the Avi controller
+// outside of AKO, is re-applied when the drift action for L4 policy sets is reapply.
+func TestL4SvcDriftReapplyL4Policy(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	os.Setenv("DRIFT_ACTIONS", `{"L4PolicySet": "reapply"}`)
+	defer os.Setenv("DRIFT_ACTIONS", "")
+
+	svcName := "testsvc-drift-l4policy"
+	vsKey, _ := setUpTestForSvcLBWithRestTransaction(t, svcName)
+	mcache := cache.SharedAviObjCache()
+	var l4PolicyKey cache.NamespaceName
+	g.Eventually(func() bool {
+		vsCache, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		if !found || len(vsCache.(*cache.AviVsCache).L4PolicyCollection) == 0 {
+			return false
+		}
+		l4PolicyKey = vsCache.(*cache.AviVsCache).L4PolicyCollection[0]
+		_, found = mcache.L4PolicyCache.AviCacheGet(l4PolicyKey)
+		return found
+	}, 10*time.Second).Should(gomega.Equal(true))
+	// The L4 policy set is in sync with the model, as after it was last applied.
+	modelName := fmt.Sprintf("%s/cluster--%s-%s", AVINAMESPACE, NAMESPACE, svcName)
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	l4PolicyNode := aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].L4PolicyRefs[0]
+	l4PolicyCache, _ := mcache.L4PolicyCache.AviCacheGet(l4PolicyKey)
+	l4PolicyCacheCopy := *l4PolicyCache.(*cache.AviL4PolicyCache)
+	l4PolicyCacheCopy.CloudConfigCksum = l4PolicyNode.GetCheckSum()
+	mcache.L4PolicyCache.AviCacheAdd(l4PolicyKey, &l4PolicyCacheCopy)
+
+	var mutex sync.Mutex
+	var l4PolicyWrites int
+	AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		url := r.URL.EscapedPath()
+		if r.Method == "GET" && strings.Trim(url, "/") == "api/l4policyset" {
+			// The L4 policy set was deleted on the Avi controller.
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `{"count": 0, "results": []}`)
+			return
+		}
+		if r.Method != "GET" && strings.Contains(url, "l4policyset") {
+			mutex.Lock()
+			l4PolicyWrites++
+			mutex.Unlock()
+		}
+		NormalControllerServer(w, r)
+	})
+	defer ResetMiddleware()
+
+	restlayer := rest.NewRestOperations(mcache, cache.SharedAVIClients(), true)
+	restlayer.DetectDrift()
+
+	g.Eventually(func() int {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return l4PolicyWrites
+	}, 10*time.Second).Should(gomega.Equal(1))
+	g.Eventually(func() uint32 {
+		l4PolicyCache, _ := mcache.L4PolicyCache.AviCacheGet(l4PolicyKey)
+		return l4PolicyCache.(*cache.AviL4PolicyCache).CloudConfigCksum
+	}, 10*time.Second).ShouldNot(gomega.BeZero())
+
+	ResetMiddleware()
+	tearDownTestForSvcLBWithRestTransaction(t, g, svcName)
+}
+
+// TestL4SvcDryRun tests that the rest operations of a new L4 VS are only recorded in dry run mode,
+// and that the VS is created on the controller once dry run is disabled.
+func TestL4SvcDryRun(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	svcName := "testsvc-dryrun"
+	modelName := fmt.Sprintf("%s/cluster--%s-%s", AVINAMESPACE, NAMESPACE, svcName)
+	var mutex sync.Mutex
+	var writes int
+	AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" && strings.Contains(r.URL.EscapedPath(), "/api/") {
+			mutex.Lock()
+			writes++
+			mutex.Unlock()
+		}
+		NormalControllerServer(w, r)
+	})
+	defer ResetMiddleware()
+
+	models.DryRunPlans.SetEnabled(true)
+	vsKey, poolKey := setUpTestForSvcLBWithRestTransaction(t, svcName)
+	mcache := cache.SharedAviObjCache()
+
+	g.Eventually(func() int {
+		return len(models.DryRunPlans.GetPlan(modelName))
+	}, 10*time.Second).Should(gomega.BeNumerically(">=", 3))
+	planned := make(map[string]string)
+	for _, restOp := range models.DryRunPlans.GetPlan(modelName) {
+		planned[restOp.Model] = restOp.Method
+	}
+	g.Expect(planned).To(gomega.HaveKeyWithValue("VsVip", "POST"))
+	g.Expect(planned).To(gomega.HaveKeyWithValue("Pool", "POST"))
+	g.Expect(planned).To(gomega.HaveKeyWithValue("VirtualService", "POST"))
+	g.Expect(models.DryRunPlans.Diff()).To(gomega.ContainSubstring("+ POST VirtualService " + vsKey.Name))
+
+	// Neither the controller nor the cache is updated in dry run mode.
+	_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+	g.Expect(found).To(gomega.Equal(false))
+	_, found = mcache.PoolCache.AviCacheGet(poolKey)
+	g.Expect(found).To(gomega.Equal(false))
+	mutex.Lock()
+	g.Expect(writes).To(gomega.Equal(0))
+	mutex.Unlock()
+
+	models.DryRunPlans.SetEnabled(false)
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 10*time.Second).Should(gomega.Equal(true))
+	mutex.Lock()
+	g.Expect(writes).To(gomega.BeNumerically(">", 0))
+	mutex.Unlock()
+
+	ResetMiddleware()
+	tearDownTestForSvcLBWithRestTransaction(t, g, svcName)
+}
+
+// TestL4SvcSyncTracing tests that the sync of an update of an L4 service is traced from its kubernetes event
+// to the completion of its rest operations and status update.
+func TestL4SvcSyncTracing(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	recorder := tracetest.NewSpanRecorder()
+	tracing.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer tracing.SetTracerProvider(nil)
+
+	svcName := "testsvc-trace"
+	vsKey, _ := setUpTestForSvcLBWithRestTransaction(t, svcName)
+	mcache := cache.SharedAviObjCache()
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 10*time.Second).Should(gomega.Equal(true))
+
+	// The new ports of the service add pools to the virtualservice.
+	UpdateSVC(t, NAMESPACE, svcName, corev1.ProtocolTCP, corev1.ServiceTypeLoadBalancer, true)
+	svcKey := utils.L4LBService + "/" + NAMESPACE + "/" + svcName
+
+	// tracedSync returns the names of the spans of the ended syncs of the service, which updated the virtualservice.
+	tracedSync := func() map[string]bool {
+		spans := recorder.Ended()
+		for _, root := range spans {
+			if root.Name() != "sync "+utils.L4LBService || len(root.Attributes()) == 0 ||
+				root.Attributes()[0].Value.AsString() != svcKey {
+				continue
+			}
+			names := make(map[string]bool)
+			for _, span := range spans {
+				if span.SpanContext().TraceID() == root.SpanContext().TraceID() {
+					names[span.Name()] = true
+				}
+			}
+			if names["PUT VirtualService"] {
+				return names
+			}
+		}
+		return nil
+	}
+	g.Eventually(tracedSync, 10*time.Second).ShouldNot(gomega.BeNil())
+	names := tracedSync()
+	g.Expect(names).To(gomega.HaveKey("ingestion"))
+	g.Expect(names).To(gomega.HaveKey("rest"))
+	g.Expect(names).To(gomega.HaveKey("POST Pool"))
+	g.Expect(names).To(gomega.HaveKey("status"))
+
+	tearDownTestForSvcLBWithRestTransaction(t, g, svcName)
+}
@@ -25,6 +25,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -1083,25 +1084,42 @@ var FakeAviObjects = []string{
 	"tenant",
 	"vsvip",
 	"l4policyset",
+	"networksecuritypolicy",
+	"applicationprofile",
+	"trafficcloneprofile",
 }
 
 type InjectFault func(w http.ResponseWriter, r *http.Request)
 
+// middlewareLock guards FakeServerMiddleware, which is changed by the tests while requests are served.
+var middlewareLock sync.RWMutex
+
 func AddMiddleware(exec InjectFault) {
+	middlewareLock.Lock()
+	defer middlewareLock.Unlock()
 	FakeServerMiddleware = exec
 }
 
 func ResetMiddleware() {
+	middlewareLock.Lock()
+	defer middlewareLock.Unlock()
 	FakeServerMiddleware = nil
 }
+
+// GetMiddleware returns the middleware set with AddMiddleware, or nil.
+func GetMiddleware() InjectFault {
+	middlewareLock.RLock()
+	defer middlewareLock.RUnlock()
+	return FakeServerMiddleware
+}
 func NewAviFakeClientInstance(kubeclient *k8sfake.Clientset, skipCachePopulation ...bool) {
 	if AviFakeClientInstance == nil {
 		AviFakeClientInstance = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Content-Type", "application/json")
 			utils.AviLog.Infof("[fakeAPI]: %s %s", r.Method, r.URL)
 
-			if FakeServerMiddleware != nil {
-				FakeServerMiddleware(w, r)
+			if middleware := GetMiddleware(); middleware != nil {
+				middleware(w, r)
 				return
 			}
 
@@ -1113,6 +1131,8 @@ func NewAviFakeClientInstance(kubeclient *k8sfake.Clientset, skipCachePopulation
 		os.Setenv("FULL_SYNC_INTERVAL", "600")
 		// resets avi client pool instance, allows to connect with the new `ts` server
 		cache.AviClientInstance = nil
+		cache.AviConcurrentClientInstance = nil
+		cache.AviDriftClientInstance = nil
 		k8s.PopulateControllerProperties(kubeclient)
 		if len(skipCachePopulation) == 0 || skipCachePopulation[0] == false {
 			k8s.PopulateCache()
@@ -1139,7 +1159,10 @@ func NormalControllerServer(w http.ResponseWriter, r *http.Request, args ...stri
 
 	reg, _ := regexp.Compile("[^.0-9]+")
 
-	if r.Method == "POST" && !strings.Contains(url, "login") {
+	if r.Method == "POST" && object == "macro" {
+		FeedMacroData(w, r, mockFilePath)
+
+	} else if r.Method == "POST" && !strings.Contains(url, "login") {
 		data, _ := io.ReadAll(r.Body)
 		json.Unmarshal(data, &resp)
 		rName := resp["name"].(string)
@@ -1376,6 +1399,32 @@ func NormalControllerServer(w http.ResponseWriter, r *http.Request, args ...stri
 	}
 }
 
+// FeedMacroData responds to a macro API call with the objects created or updated by it, in the order of the request.
+// The objects with a uuid are updated.
+func FeedMacroData(w http.ResponseWriter, r *http.Request, mockFilePath string) {
+	var macro []utils.AviRestObjMacro
+	data, _ := io.ReadAll(r.Body)
+	json.Unmarshal(data, &macro)
+
+	var response []interface{}
+	for _, obj := range macro {
+		objData, _ := json.Marshal(obj.Data)
+		method, objURL := http.MethodPost, "/api/"+strings.ToLower(obj.ModelName)+"/"
+		if objMap, ok := obj.Data.(map[string]interface{}); ok && objMap["uuid"] != nil {
+			method, objURL = http.MethodPut, objURL+objMap["uuid"].(string)
+		}
+		objReq := httptest.NewRequest(method, objURL, strings.NewReader(string(objData)))
+		objResp := httptest.NewRecorder()
+		NormalControllerServer(objResp, objReq, mockFilePath)
+		var objRespData map[string]interface{}
+		json.Unmarshal(objResp.Body.Bytes(), &objRespData)
+		response = append(response, objRespData)
+	}
+	finalResponse, _ := json.Marshal(response)
+	w.WriteHeader(http.StatusOK)
+	w.Write(finalResponse)
+}
+
 func inArray(a []string, b string) bool {
 	for _, k := range a {
 		if k == b {
@@ -427,3 +427,76 @@ func TestServiceImport(t *testing.T) {
 	}
 	waitAndverify(t, "ServiceImport/avi-system/SI-01")
 }
+
+// TestFairQueueWeightedRoundRobin tests that the keys of a busy namespace do not starve the keys of the others,
+// when fair queuing is enabled.
+func TestFairQueueWeightedRoundRobin(t *testing.T) {
+	var depthLock sync.Mutex
+	depths := make(map[string]int)
+	options := utils.FairQueueOptions{
+		Weights:  map[string]int{"busy-ns": 2},
+		FlowFunc: utils.FairQueueFlowByNamespace,
+		ObserveDepth: func(queueName, flow string, depth int) {
+			depthLock.Lock()
+			defer depthLock.Unlock()
+			depths[flow] = depth
+		},
+	}
+	queue := utils.NewFairWorkQueue(1, "fairqueue-test", options).Workqueue[0]
+	defer queue.ShutDown()
+
+	for i := 0; i < 5; i++ {
+		queue.Add(fmt.Sprintf("Endpoints/busy-ns/ep-%d", i))
+	}
+	queue.Add("Service/quiet-ns/svc-0")
+	queue.Add("Service/quiet-ns/svc-1")
+	queue.Add("Node/node-1")
+	// A key which is already queued is not queued again.
+	queue.Add("Endpoints/busy-ns/ep-4")
+	if queue.Len() != 8 {
+		t.Fatalf("expected 8 keys in the queue, got: %d", queue.Len())
+	}
+	depthLock.Lock()
+	if depths["busy-ns"] != 5 || depths["quiet-ns"] != 2 {
+		t.Fatalf("unexpected queue depths: %v", depths)
+	}
+	depthLock.Unlock()
+
+	expected := []string{
+		"Endpoints/busy-ns/ep-0",
+		"Endpoints/busy-ns/ep-1",
+		"Service/quiet-ns/svc-0",
+		"Node/node-1",
+		"Endpoints/busy-ns/ep-2",
+		"Endpoints/busy-ns/ep-3",
+		"Service/quiet-ns/svc-1",
+		"Endpoints/busy-ns/ep-4",
+	}
+	for _, key := range expected {
+		item, shutdown := queue.Get()
+		if shutdown {
+			t.Fatalf("queue shut down while waiting for %s", key)
+		}
+		if item != key {
+			t.Fatalf("error in match expected: %v, got: %v", key, item)
+		}
+		queue.Done(item)
+	}
+
+	// A key added while it is being processed is queued again once it is done.
+	queue.Add("Service/quiet-ns/svc-0")
+	item, _ := queue.Get()
+	queue.Add("Service/quiet-ns/svc-0")
+	if queue.Len() != 0 {
+		t.Fatalf("expected no keys in the queue while the key is processed, got: %d", queue.Len())
+	}
+	queue.Done(item)
+	if queue.Len() != 1 {
+		t.Fatalf("expected the key to be queued again, got: %d keys in the queue", queue.Len())
+	}
+	depthLock.Lock()
+	if depths["busy-ns"] != 0 || depths["quiet-ns"] != 1 {
+		t.Fatalf("unexpected queue depths: %v", depths)
+	}
+	depthLock.Unlock()
+}
@@ -18,6 +18,7 @@ import (
 	"os"
 	"reflect"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang/glog"
@@ -277,11 +278,16 @@ const DEFAULT_API_TENANT = "admin"
 const DEFAULT_MAX_API_RETRIES = 3
 const DEFAULT_API_RETRY_INTERVAL = 500
 
+// parseFlags parses the command line flags once, as the sessions of a client pool are created concurrently.
+var parseFlags sync.Once
+
 // NewAviSession initiates a session to AviController and returns it
 func NewAviSession(host string, username string, options ...func(*AviSession) error) (*AviSession, error) {
-	if flag.Parsed() == false {
-		flag.Parse()
-	}
+	parseFlags.Do(func() {
+		if flag.Parsed() == false {
+			flag.Parse()
+		}
+	})
 	avisess := &AviSession{
 		host:     host,
 		username: username,